@@ -425,5 +425,5 @@ func TestGetExistedUserDBs(t *testing.T) {
 //
 // The above variables are in the file br/pkg/restore/systable_restore.go
 func TestMonitorTheSystemTableIncremental(t *testing.T) {
-	require.Equal(t, int64(195), session.CurrentBootstrapVersion)
+	require.Equal(t, int64(198), session.CurrentBootstrapVersion)
 }
@@ -47,12 +47,17 @@ var unRecoverableTable = map[string]map[string]struct{}{
 
 		"column_stats_usage":               {},
 		"capture_plan_baselines_blacklist": {},
+		// cardinality corrections are keyed by the table IDs of the backup cluster.
+		"stats_cardinality_feedback": {},
 		// gc info don't need to recover.
 		"gc_delete_range":      {},
 		"gc_delete_range_done": {},
 
 		// replace into view is not supported now
 		"tidb_mdl_view": {},
+
+		// prepared XA branches depend on the timestamps of the backup cluster.
+		"tidb_xa_branches": {},
 	},
 	"sys": {
 		// replace into view is not supported now
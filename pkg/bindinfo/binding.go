@@ -48,6 +48,8 @@ const (
 	Builtin = "builtin"
 	// History indicate the binding is created from statement summary by plan digest
 	History = "history"
+	// Evolve indicates the binding is created by TiDB automatically to fall back to an accepted plan baseline.
+	Evolve = "evolve"
 )
 
 // Binding stores the basic bind hint info.
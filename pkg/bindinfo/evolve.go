@@ -0,0 +1,616 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/bindinfo/internal/logutil"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/hint"
+	utilparser "github.com/pingcap/tidb/pkg/util/parser"
+	stmtsummaryv2 "github.com/pingcap/tidb/pkg/util/stmtsummary/v2"
+	"github.com/pingcap/tidb/pkg/util/sqlkiller"
+	"github.com/pingcap/tidb/pkg/util/timeutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// BaselineAccepted indicates the plan baseline is verified to be good, so the statement can fall back to it.
+	BaselineAccepted = "accepted"
+	// BaselineRejected indicates the plan baseline is a regression or is slower than the accepted plans.
+	BaselineRejected = "rejected"
+)
+
+// The actions recorded in mysql.plan_baseline_history.
+const (
+	baselineActionAccept   = "accept"
+	baselineActionReject   = "reject"
+	baselineActionFallback = "fallback"
+)
+
+// evolveVerifyRuns is the number of times that the accepted plan and the candidate plan are executed
+// alternately when verifying the candidate plan.
+const evolveVerifyRuns = 3
+
+const (
+	// evolveTimeoutFactor limits the execution time of the candidate plan to this multiple of the accepted plan's
+	// latency, so a regressed candidate plan doesn't run for too long. The candidate is rejected if it times out.
+	evolveTimeoutFactor = 2
+	// evolveMinTimeout is the min execution time of the candidate plan, so that a fast plan isn't rejected because
+	// of the jitter of the latency.
+	evolveMinTimeout = 100 * time.Millisecond
+)
+
+// sideEffectFunctions are the functions which change the state of the sessions or the sequences, so the queries
+// calling them can't be executed again to verify the plans.
+var sideEffectFunctions = map[string]struct{}{
+	ast.NextVal:         {},
+	ast.LastVal:         {},
+	ast.SetVal:          {},
+	ast.GetLock:         {},
+	ast.ReleaseLock:     {},
+	ast.ReleaseAllLocks: {},
+	ast.Sleep:           {},
+	ast.Benchmark:       {},
+}
+
+// GetLastPlanDigest returns the plan digest of the statement executed last in the session.
+// It's assigned by the executor package, because the bindinfo package cannot import it.
+var GetLastPlanDigest func(sctx sessionctx.Context) string
+
+// planBaseline is a plan of a statement recorded in mysql.plan_baselines.
+type planBaseline struct {
+	sqlDigest   string
+	planDigest  string
+	originalSQL string
+	bindSQL     string
+	db          string
+	charset     string
+	collation   string
+	status      string
+	avgLatency  time.Duration
+	execCount   int64
+	updateTime  types.Time
+}
+
+// toBinding builds the binding which makes the statement fall back to this plan.
+func (b *planBaseline) toBinding() Binding {
+	return Binding{
+		OriginalSQL: b.originalSQL,
+		Db:          b.db,
+		BindSQL:     b.bindSQL,
+		Status:      Enabled,
+		Charset:     b.charset,
+		Collation:   b.collation,
+		Source:      Evolve,
+		SQLDigest:   b.sqlDigest,
+		PlanDigest:  b.planDigest,
+	}
+}
+
+// generateBindSQLForPlan generates the bind SQL which fixes the plan described by the plan hints.
+// It's the same as the bind SQL of the binding created from the plan digest.
+func generateBindSQLForPlan(p *parser.Parser, stmt ast.StmtNode, planHint, db, charset, collation string) string {
+	bindSQL := GenerateBindingSQL(stmt, planHint, true, db)
+	if bindSQL == "" {
+		return ""
+	}
+	hintNode, err := p.ParseOneStmt(bindSQL, charset, collation)
+	if err != nil {
+		return ""
+	}
+	return utilparser.RestoreWithDefaultDB(hintNode, db, hintNode.Text())
+}
+
+// RecordPlanBaselines records the plans in the statement summary as the plan baselines of the statements.
+// A new plan of a statement is compared with the last accepted plan, it is accepted if it performs well,
+// otherwise a binding is created to make the statement fall back to the last accepted plan.
+func (h *globalBindingHandle) RecordPlanBaselines() {
+	ratio, minExecCount := variable.DefTiDBPlanRegressionRatio, int64(variable.DefTiDBPlanRegressionMinExecCount)
+	_ = h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		vars := sctx.GetSessionVars().GlobalVarsAccessor
+		if val, err := vars.GetGlobalSysVar(variable.TiDBPlanRegressionRatio); err == nil {
+			if v, err := strconv.ParseFloat(val, 64); err == nil {
+				ratio = v
+			}
+		}
+		if val, err := vars.GetGlobalSysVar(variable.TiDBPlanRegressionMinExecCount); err == nil {
+			minExecCount = variable.TidbOptInt64(val, minExecCount)
+		}
+		return nil
+	})
+
+	parser4Baseline := parser.New()
+	observed := make(map[string]map[string]*planBaseline)
+	for _, bindableStmt := range stmtsummaryv2.GetMoreThanCntBindableStmt(minExecCount - 1) {
+		if bindableStmt.ExecCount < minExecCount || bindableStmt.PlanDigest == "" {
+			continue
+		}
+		stmt, err := parser4Baseline.ParseOneStmt(bindableStmt.Query, bindableStmt.Charset, bindableStmt.Collation)
+		if err != nil {
+			logutil.BindLogger().Debug("parse SQL failed in recording plan baselines", zap.String("SQL", bindableStmt.Query), zap.Error(err))
+			continue
+		}
+		if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
+			continue
+		}
+		db := utilparser.GetDefaultDB(stmt, bindableStmt.Schema)
+		normdOrigSQL, sqlDigest := parser.NormalizeDigestForBinding(utilparser.RestoreWithDefaultDB(stmt, bindableStmt.Schema, bindableStmt.Query))
+		bindSQL := generateBindSQLForPlan(parser4Baseline, stmt, bindableStmt.PlanHint, bindableStmt.Schema, bindableStmt.Charset, bindableStmt.Collation)
+		if bindSQL == "" {
+			continue
+		}
+		plans, ok := observed[sqlDigest.String()]
+		if !ok {
+			plans = make(map[string]*planBaseline)
+			observed[sqlDigest.String()] = plans
+		}
+		// The same plan may be summarized in several records, e.g, with different previous statements.
+		if plan, ok := plans[bindableStmt.PlanDigest]; ok {
+			plan.avgLatency = (plan.avgLatency*time.Duration(plan.execCount) + bindableStmt.SumLatency) /
+				time.Duration(plan.execCount+bindableStmt.ExecCount)
+			plan.execCount += bindableStmt.ExecCount
+			continue
+		}
+		plans[bindableStmt.PlanDigest] = &planBaseline{
+			sqlDigest:   sqlDigest.String(),
+			planDigest:  bindableStmt.PlanDigest,
+			originalSQL: normdOrigSQL,
+			bindSQL:     bindSQL,
+			db:          db,
+			charset:     bindableStmt.Charset,
+			collation:   bindableStmt.Collation,
+			avgLatency:  bindableStmt.SumLatency / time.Duration(bindableStmt.ExecCount),
+			execCount:   bindableStmt.ExecCount,
+		}
+	}
+
+	for sqlDigest, plans := range observed {
+		if err := h.recordPlanBaselines(sqlDigest, plans, ratio); err != nil {
+			logutil.BindLogger().Warn("record plan baselines failed", zap.String("sqlDigest", sqlDigest), zap.Error(err))
+		}
+	}
+}
+
+func (h *globalBindingHandle) recordPlanBaselines(sqlDigest string, plans map[string]*planBaseline, ratio float64) error {
+	fallingBack := false
+	for _, binding := range h.getCache().GetBinding(sqlDigest) {
+		if !binding.IsBindingAvailable() {
+			continue
+		}
+		// The plans of the statements bound by users are left alone.
+		if binding.Source != Evolve {
+			return nil
+		}
+		fallingBack = true
+	}
+	baselines, err := h.loadPlanBaselines(sqlDigest)
+	if err != nil {
+		return err
+	}
+
+	observed := make([]*planBaseline, 0, len(plans))
+	for _, plan := range plans {
+		observed = append(observed, plan)
+	}
+	// Update the accepted plans first to find the last accepted one, then check the new plans from the fastest.
+	slices.SortFunc(observed, func(a, b *planBaseline) int {
+		aAccepted := baselines[a.planDigest] != nil && baselines[a.planDigest].status == BaselineAccepted
+		bAccepted := baselines[b.planDigest] != nil && baselines[b.planDigest].status == BaselineAccepted
+		if aAccepted != bAccepted {
+			if aAccepted {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.avgLatency, b.avgLatency)
+	})
+	for _, plan := range observed {
+		if old := baselines[plan.planDigest]; old != nil && old.status == BaselineAccepted {
+			if old.execCount == plan.execCount && old.avgLatency == plan.avgLatency {
+				continue
+			}
+			plan.status = BaselineAccepted
+			if err := h.savePlanBaseline(plan); err != nil {
+				return err
+			}
+			baselines[plan.planDigest] = plan
+			continue
+		}
+		// The plans executed when falling back have been verified by the background evolution.
+		if fallingBack {
+			continue
+		}
+		last := lastAcceptedPlanBaseline(baselines)
+		if last == nil || last.avgLatency <= 0 {
+			plan.status = BaselineAccepted
+			reason := "the first plan of the statement"
+			if last != nil {
+				reason = "the accepted plan has no latency to compare with"
+			}
+			if err := h.acceptPlanBaseline(plan, nil, reason); err != nil {
+				return err
+			}
+			baselines[plan.planDigest] = plan
+			continue
+		}
+		if float64(plan.avgLatency) <= float64(last.avgLatency)*ratio {
+			plan.status = BaselineAccepted
+			reason := fmt.Sprintf("no regression in %d executions", plan.execCount)
+			if err := h.acceptPlanBaseline(plan, last, reason); err != nil {
+				return err
+			}
+			baselines[plan.planDigest] = plan
+			continue
+		}
+
+		plan.status = BaselineRejected
+		if err := h.savePlanBaseline(plan); err != nil {
+			return err
+		}
+		baselines[plan.planDigest] = plan
+		if err := h.CreateGlobalBinding(nil, last.toBinding()); err != nil {
+			return err
+		}
+		fallingBack = true
+		reason := fmt.Sprintf("the average latency is %.2f times of the accepted plan in %d executions",
+			float64(plan.avgLatency)/float64(last.avgLatency), plan.execCount)
+		if err := h.addPlanBaselineHistory(plan, baselineActionFallback, reason, last); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastAcceptedPlanBaseline returns the accepted plan which is updated last, that is, the last good plan.
+func lastAcceptedPlanBaseline(baselines map[string]*planBaseline) (last *planBaseline) {
+	for _, baseline := range baselines {
+		if baseline.status != BaselineAccepted {
+			continue
+		}
+		if last == nil || baseline.updateTime.Compare(last.updateTime) > 0 ||
+			(baseline.updateTime.Compare(last.updateTime) == 0 && baseline.planDigest < last.planDigest) {
+			last = baseline
+		}
+	}
+	return last
+}
+
+// EvolvePlanBaselines verifies the plans chosen by the optimizer for the statements which fall back to the accepted
+// plans by bindings. If the new plan performs better, it's accepted and the binding is dropped.
+// The evolution only works in the time window of tidb_evolve_plan_task_start_time and tidb_evolve_plan_task_end_time
+// unless it's triggered by `admin evolve bindings`.
+func (h *globalBindingHandle) EvolvePlanBaselines(adminEvolve bool) error {
+	var (
+		maxTime    time.Duration
+		start, end time.Time
+	)
+	err := h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		rows, _, err := execRows(sctx, "SELECT variable_name, variable_value FROM mysql.global_variables WHERE variable_name IN (%?, %?, %?)",
+			variable.TiDBEvolvePlanTaskMaxTime, variable.TiDBEvolvePlanTaskStartTime, variable.TiDBEvolvePlanTaskEndTime)
+		if err != nil {
+			return err
+		}
+		params := map[string]string{
+			variable.TiDBEvolvePlanTaskMaxTime:   strconv.Itoa(variable.DefTiDBEvolvePlanTaskMaxTime),
+			variable.TiDBEvolvePlanTaskStartTime: variable.DefTiDBEvolvePlanTaskStartTime,
+			variable.TiDBEvolvePlanTaskEndTime:   variable.DefTiDBEvolvePlanTaskEndTime,
+		}
+		for _, row := range rows {
+			params[row.GetString(0)] = row.GetString(1)
+		}
+		maxTime = time.Duration(variable.TidbOptInt64(params[variable.TiDBEvolvePlanTaskMaxTime], variable.DefTiDBEvolvePlanTaskMaxTime)) * time.Second
+		if start, err = time.ParseInLocation(variable.FullDayTimeFormat, params[variable.TiDBEvolvePlanTaskStartTime], time.UTC); err != nil {
+			return err
+		}
+		end, err = time.ParseInLocation(variable.FullDayTimeFormat, params[variable.TiDBEvolvePlanTaskEndTime], time.UTC)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !adminEvolve && !timeutil.WithinDayTimePeriod(start, end, time.Now()) {
+		return nil
+	}
+
+	startTime := time.Now()
+	for _, binding := range h.GetAllGlobalBindings() {
+		if binding.Source != Evolve || !binding.IsBindingEnabled() {
+			continue
+		}
+		if maxTime > 0 && time.Since(startTime) > maxTime {
+			break
+		}
+		if err := h.evolvePlanBaseline(binding); err != nil {
+			logutil.BindLogger().Warn("evolve plan baseline failed", zap.String("sqlDigest", binding.SQLDigest), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (h *globalBindingHandle) evolvePlanBaseline(binding Binding) error {
+	baselines, err := h.loadPlanBaselines(binding.SQLDigest)
+	if err != nil {
+		return err
+	}
+	accepted := baselines[binding.PlanDigest]
+	if accepted == nil || accepted.status != BaselineAccepted {
+		return nil
+	}
+	p := parser.New()
+	stmt, err := p.ParseOneStmt(binding.BindSQL, binding.Charset, binding.Collation)
+	if err != nil {
+		return err
+	}
+	// Only the queries can be executed to verify the plans.
+	if _, ok := stmt.(*ast.SelectStmt); !ok {
+		return nil
+	}
+	paramChecker := &paramMarkerChecker{}
+	stmt.Accept(paramChecker)
+	if paramChecker.hasParamMarker {
+		return nil
+	}
+	// The queries with side effects are not executed again, for example, `nextval()` consumes the sequence and
+	// `FOR UPDATE` locks the rows.
+	sideEffectChecker := &sideEffectChecker{}
+	stmt.Accept(sideEffectChecker)
+	if sideEffectChecker.hasSideEffect {
+		return nil
+	}
+	hint.BindHint(stmt, &hint.HintsSet{})
+	sql := utilparser.RestoreWithDefaultDB(stmt, binding.Db, "")
+
+	return h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		planHint, err := getHintsForSQL(sctx, sql)
+		if err != nil {
+			return err
+		}
+		candidate := &planBaseline{
+			sqlDigest:   accepted.sqlDigest,
+			originalSQL: accepted.originalSQL,
+			bindSQL:     generateBindSQLForPlan(p, stmt, planHint, binding.Db, binding.Charset, binding.Collation),
+			db:          accepted.db,
+			charset:     accepted.charset,
+			collation:   accepted.collation,
+			execCount:   evolveVerifyRuns,
+		}
+		for _, baseline := range baselines {
+			if baseline.bindSQL != candidate.bindSQL {
+				continue
+			}
+			switch baseline.status {
+			case BaselineAccepted:
+				// The optimizer chooses an accepted plan by itself again.
+				return h.releaseFallbackBinding(baseline, accepted, "the optimizer chooses an accepted plan again")
+			case BaselineRejected:
+				return nil
+			}
+		}
+
+		origin := sctx.GetSessionVars().UsePlanBaselines
+		sctx.GetSessionVars().UsePlanBaselines = false
+		defer func() {
+			sctx.GetSessionVars().UsePlanBaselines = origin
+		}()
+		var acceptedLatency, candidateLatency time.Duration
+		var reason string
+		timedOut := false
+		runs := 0
+		for runs < evolveVerifyRuns {
+			latency, err := runPlanForEvolution(sctx, binding.BindSQL, 0)
+			if err != nil {
+				return err
+			}
+			acceptedLatency += latency
+			runs++
+			timeout := max(latency*evolveTimeoutFactor, evolveMinTimeout)
+			latency, err = runPlanForEvolution(sctx, sql, timeout)
+			if candidate.planDigest == "" && GetLastPlanDigest != nil {
+				candidate.planDigest = GetLastPlanDigest(sctx)
+			}
+			if exeerrors.ErrMaxExecTimeExceeded.Equal(err) {
+				candidateLatency += timeout
+				timedOut = true
+				reason = fmt.Sprintf("the plan doesn't finish in %s, which is %d times of the accepted plan's latency",
+					timeout, evolveTimeoutFactor)
+				break
+			}
+			if err != nil {
+				return err
+			}
+			candidateLatency += latency
+		}
+		if candidate.planDigest == "" {
+			return errors.New("failed to get the plan digest of the candidate plan")
+		}
+		candidate.avgLatency = candidateLatency / time.Duration(runs)
+		verified := &planBaseline{planDigest: accepted.planDigest, avgLatency: acceptedLatency / time.Duration(runs)}
+		if old := baselines[candidate.planDigest]; old != nil && old.status == BaselineAccepted {
+			return h.releaseFallbackBinding(old, verified, "the optimizer chooses an accepted plan again")
+		}
+		if reason == "" {
+			reason = fmt.Sprintf("the average latency is %s in %d runs, and the accepted plan's is %s",
+				candidate.avgLatency, evolveVerifyRuns, verified.avgLatency)
+		}
+		if timedOut || candidateLatency > acceptedLatency {
+			candidate.status = BaselineRejected
+			if err := h.savePlanBaseline(candidate); err != nil {
+				return err
+			}
+			return h.addPlanBaselineHistory(candidate, baselineActionReject, reason, verified)
+		}
+		candidate.status = BaselineAccepted
+		return h.releaseFallbackBinding(candidate, verified, reason)
+	})
+}
+
+// releaseFallbackBinding accepts the plan and drops the binding which makes the statement fall back to an accepted plan,
+// so the optimizer can choose the plan by itself.
+func (h *globalBindingHandle) releaseFallbackBinding(plan, baseline *planBaseline, reason string) error {
+	if err := h.acceptPlanBaseline(plan, baseline, reason); err != nil {
+		return err
+	}
+	_, err := h.DropGlobalBinding(plan.sqlDigest)
+	return err
+}
+
+// runPlanForEvolution executes the query and returns the latency. If the timeout is set, the query is killed with
+// ErrMaxExecTimeExceeded when it runs longer, the same as max_execution_time, which doesn't work for internal sessions.
+func runPlanForEvolution(sctx sessionctx.Context, sql string, timeout time.Duration) (time.Duration, error) {
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo)
+	killer := &sctx.GetSessionVars().SQLKiller
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			killer.SendKillSignal(sqlkiller.MaxExecTimeExceeded)
+		})
+		defer func() {
+			timer.Stop()
+			killer.Reset()
+		}()
+	}
+	start := time.Now()
+	rs, err := exec(sctx, sql)
+	if err != nil {
+		return 0, err
+	}
+	if rs == nil {
+		return time.Since(start), nil
+	}
+	defer terror.Call(rs.Close)
+	chk := rs.NewChunk(nil)
+	for {
+		failpoint.Inject("mockSlowCandidatePlan", func() {
+			if timeout > 0 {
+				time.Sleep(2 * timeout)
+			}
+		})
+		if err := rs.Next(ctx, chk); err != nil {
+			return 0, err
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+	}
+	return time.Since(start), nil
+}
+
+// sideEffectChecker checks whether the query has side effects, which calls the functions in sideEffectFunctions,
+// locks the rows, assigns the user variables or writes the results into files.
+type sideEffectChecker struct {
+	hasSideEffect bool
+}
+
+func (e *sideEffectChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.FuncCallExpr:
+		if _, ok := sideEffectFunctions[x.FnName.L]; ok {
+			e.hasSideEffect = true
+		}
+	case *ast.VariableExpr:
+		if x.Value != nil {
+			e.hasSideEffect = true
+		}
+	case *ast.SelectStmt:
+		if (x.LockInfo != nil && x.LockInfo.LockType != ast.SelectLockNone) || x.SelectIntoOpt != nil {
+			e.hasSideEffect = true
+		}
+	}
+	return in, e.hasSideEffect
+}
+
+func (*sideEffectChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (h *globalBindingHandle) loadPlanBaselines(sqlDigest string) (baselines map[string]*planBaseline, err error) {
+	err = h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		rows, _, err := execRows(sctx, `SELECT plan_digest, original_sql, bind_sql, default_db, charset, collation, status,
+       avg_latency, exec_count, update_time FROM mysql.plan_baselines WHERE sql_digest = %?`, sqlDigest)
+		if err != nil {
+			return err
+		}
+		baselines = make(map[string]*planBaseline, len(rows))
+		for _, row := range rows {
+			baselines[row.GetString(0)] = &planBaseline{
+				sqlDigest:   sqlDigest,
+				planDigest:  row.GetString(0),
+				originalSQL: row.GetString(1),
+				bindSQL:     row.GetString(2),
+				db:          row.GetString(3),
+				charset:     row.GetString(4),
+				collation:   row.GetString(5),
+				status:      row.GetString(6),
+				avgLatency:  time.Duration(row.GetInt64(7)),
+				execCount:   row.GetInt64(8),
+				updateTime:  row.GetTime(9),
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func (h *globalBindingHandle) savePlanBaseline(b *planBaseline) error {
+	b.updateTime = types.NewTime(types.FromGoTime(time.Now()), mysql.TypeTimestamp, 3)
+	return h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		now := b.updateTime.String()
+		_, err := exec(sctx, `INSERT INTO mysql.plan_baselines VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)
+       ON DUPLICATE KEY UPDATE bind_sql = VALUES(bind_sql), status = VALUES(status), avg_latency = VALUES(avg_latency),
+       exec_count = VALUES(exec_count), update_time = VALUES(update_time)`,
+			b.sqlDigest, b.planDigest, b.originalSQL, b.bindSQL, b.db, b.charset, b.collation, b.status,
+			b.avgLatency.Nanoseconds(), b.execCount, now, now)
+		return err
+	})
+}
+
+func (h *globalBindingHandle) acceptPlanBaseline(plan, baseline *planBaseline, reason string) error {
+	plan.status = BaselineAccepted
+	if err := h.savePlanBaseline(plan); err != nil {
+		return err
+	}
+	return h.addPlanBaselineHistory(plan, baselineActionAccept, reason, baseline)
+}
+
+func (h *globalBindingHandle) addPlanBaselineHistory(plan *planBaseline, action, reason string, baseline *planBaseline) error {
+	var baselinePlanDigest string
+	var baselineAvgLatency int64
+	if baseline != nil {
+		baselinePlanDigest, baselineAvgLatency = baseline.planDigest, baseline.avgLatency.Nanoseconds()
+	}
+	return h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		_, err := exec(sctx, `INSERT INTO mysql.plan_baseline_history (sql_digest, plan_digest, action, reason, avg_latency,
+       baseline_plan_digest, baseline_avg_latency, create_time) VALUES (%?, %?, %?, %?, %?, %?, %?, %?)`,
+			plan.sqlDigest, plan.planDigest, action, reason, plan.avgLatency.Nanoseconds(),
+			baselinePlanDigest, baselineAvgLatency, types.NewTime(types.FromGoTime(time.Now()), mysql.TypeTimestamp, 3).String())
+		return err
+	})
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/util/execdetails"
+	"github.com/pingcap/tidb/pkg/util/stmtsummary"
+	"github.com/stretchr/testify/require"
+)
+
+// addPlanToStmtSummary adds the executions of a plan to the statement summary with the given latency.
+func addPlanToStmtSummary(sql, planDigest, planHint string, latency time.Duration, execCount int) {
+	normalized, digest := parser.NormalizeDigest(sql)
+	for i := 0; i < execCount; i++ {
+		sc := stmtctx.NewStmtCtx()
+		sc.StmtType = "Select"
+		stmtsummary.StmtSummaryByDigestMap.AddStatement(&stmtsummary.StmtExecInfo{
+			SchemaName:    "test",
+			OriginalSQL:   sql,
+			Charset:       "utf8mb4",
+			Collation:     "utf8mb4_bin",
+			NormalizedSQL: normalized,
+			Digest:        digest.String(),
+			PlanDigest:    planDigest,
+			PlanGenerator: func() (string, string) {
+				return "", planHint
+			},
+			User:         "root",
+			TotalLatency: latency,
+			StmtCtx:      sc,
+			CopTasks:     &execdetails.CopTasksDetails{},
+			ExecDetail:   &execdetails.ExecDetails{},
+			StartTime:    time.Now(),
+			Succeed:      true,
+		})
+	}
+}
+
+func TestPlanRegressionFallbackAndEvolve(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	defer stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, key ia(a), key ib(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4)")
+	for i := 0; i < 12; i++ {
+		tk.MustExec("insert into t select a + 4, b + 4, c from t")
+	}
+
+	sql := "select * from t where a = 1 and b = 1"
+	fullScanHint := "use_index(@`sel_1` `test`.`t` )"
+	indexHint := tk.MustQuery("explain format='hint' " + sql).Rows()[0][0].(string)
+	require.NotEqual(t, fullScanHint, indexHint)
+
+	// The first plan of the statement is accepted.
+	addPlanToStmtSummary(sql, "plan_scan", fullScanHint, time.Millisecond, 10)
+	// The plans executed less than tidb_plan_regression_min_exec_count times are ignored.
+	addPlanToStmtSummary("select * from t where c = 1", "plan_c", fullScanHint, time.Millisecond, 9)
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("select plan_digest, status, avg_latency, exec_count from mysql.plan_baselines").Check(testkit.Rows(
+		"plan_scan accepted 1000000 10"))
+	tk.MustQuery("select plan_digest, action, reason from mysql.plan_baseline_history order by id").Check(testkit.Rows(
+		"plan_scan accept the first plan of the statement"))
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+
+	// The plan whose latency is more than tidb_plan_regression_ratio times of the accepted one is a regression,
+	// and the statement falls back to the accepted plan by a binding.
+	addPlanToStmtSummary(sql, "plan_index", indexHint, 3*time.Millisecond, 10)
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("select plan_digest, status from mysql.plan_baselines order by plan_digest").Check(testkit.Rows(
+		"plan_index rejected", "plan_scan accepted"))
+	tk.MustQuery("select plan_digest, action, reason, avg_latency, baseline_plan_digest, baseline_avg_latency from mysql.plan_baseline_history order by id").Check(testkit.Rows(
+		"plan_scan accept the first plan of the statement 1000000  0",
+		"plan_index fallback the average latency is 3.00 times of the accepted plan in 10 executions 3000000 plan_scan 1000000"))
+	rows := tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "SELECT /*+ use_index(@`sel_1` `test`.`t` )*/ * FROM `test`.`t` WHERE `a` = 1 AND `b` = 1", rows[0][1])
+	require.Equal(t, "evolve", rows[0][8])
+	require.Equal(t, "plan_scan", rows[0][10])
+	tk.MustQuery(sql)
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("1"))
+
+	// The optimizer still chooses the rejected plan, so there is nothing to evolve.
+	tk.MustExec("set global tidb_evolve_plan_baselines = on")
+	defer tk.MustExec("set global tidb_evolve_plan_baselines = default")
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("select count(*) from mysql.plan_baseline_history").Check(testkit.Rows("2"))
+	require.Len(t, tk.MustQuery("show global bindings").Rows(), 1)
+
+	// The new plan chosen by the optimizer is verified and accepted, and the fallback binding is dropped.
+	tk.MustExec("alter table t add index iab(a, b)")
+	tk.MustExec("admin evolve bindings")
+	rows = tk.MustQuery("select action, baseline_plan_digest from mysql.plan_baseline_history order by id").Rows()
+	require.Len(t, rows, 3)
+	require.Equal(t, "accept", rows[2][0])
+	require.Equal(t, "plan_scan", rows[2][1])
+	tk.MustQuery("select count(*) from mysql.plan_baselines where status = 'accepted'").Check(testkit.Rows("2"))
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	tk.MustQuery(sql)
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("0"))
+}
+
+func TestPlanRegressionGuardSkipUserBindings(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	defer stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, key ia(a), key ib(b))")
+
+	sql := "select * from t where a = 1 and b = 1"
+	hintA := tk.MustQuery("explain format='hint' select /*+ use_index(t, ia) */ * from t where a = 1 and b = 1").Rows()[0][0].(string)
+	hintB := tk.MustQuery("explain format='hint' select /*+ use_index(t, ib) */ * from t where a = 1 and b = 1").Rows()[0][0].(string)
+	tk.MustExec("set global tidb_plan_regression_ratio = 5")
+	defer tk.MustExec("set global tidb_plan_regression_ratio = default")
+	tk.MustExec("set global tidb_plan_regression_min_exec_count = 2")
+	defer tk.MustExec("set global tidb_plan_regression_min_exec_count = default")
+
+	// The new plan is accepted if it's not slow enough to be a regression.
+	addPlanToStmtSummary(sql, "plan_a", hintA, time.Millisecond, 2)
+	addPlanToStmtSummary(sql, "plan_b", hintB, 4*time.Millisecond, 2)
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("select plan_digest, status from mysql.plan_baselines order by plan_digest").Check(testkit.Rows(
+		"plan_a accepted", "plan_b accepted"))
+	tk.MustQuery("select plan_digest, action, reason from mysql.plan_baseline_history order by id").Check(testkit.Rows(
+		"plan_a accept the first plan of the statement", "plan_b accept no regression in 2 executions"))
+
+	// The statements bound by users are left alone.
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("create global binding for select * from t where a = 1 and b = 1 using select /*+ use_index(t, ia) */ * from t where a = 1 and b = 1")
+	tk.MustExec("alter table t add index iab(a, b)")
+	hintAB := tk.MustQuery("explain format='hint' select /*+ use_index(t, iab) */ * from t where a = 1 and b = 1").Rows()[0][0].(string)
+	addPlanToStmtSummary(sql, "plan_ab", hintAB, 100*time.Millisecond, 2)
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("select count(*) from mysql.plan_baselines").Check(testkit.Rows("2"))
+	rows := tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "manual", rows[0][8])
+}
+
+func TestEvolveRejectSlowPlanAndSkipSideEffects(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	defer stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, key ia(a), key ib(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4)")
+
+	sql := "select * from t where a = 1 and b = 1"
+	lockSQL := "select * from t where a = 1 and b = 1 for update"
+	fullScanHint := "use_index(@`sel_1` `test`.`t` )"
+	indexHint := tk.MustQuery("explain format='hint' " + sql).Rows()[0][0].(string)
+	for _, s := range []string{sql, lockSQL} {
+		addPlanToStmtSummary(s, "plan_scan", fullScanHint, time.Millisecond, 10)
+		addPlanToStmtSummary(s, "plan_index", indexHint, 3*time.Millisecond, 10)
+	}
+	tk.MustExec("admin evolve bindings")
+	require.Len(t, tk.MustQuery("show global bindings").Rows(), 2)
+	tk.MustQuery("select count(*) from mysql.plan_baseline_history").Check(testkit.Rows("4"))
+
+	// The candidate plan is rejected if it runs longer than the limit, and the query with side effects isn't executed.
+	tk.MustExec("set global tidb_evolve_plan_baselines = on")
+	defer tk.MustExec("set global tidb_evolve_plan_baselines = default")
+	tk.MustExec("alter table t add index iab(a, b)")
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/pkg/bindinfo/mockSlowCandidatePlan", "return"))
+	tk.MustExec("admin evolve bindings")
+	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/pkg/bindinfo/mockSlowCandidatePlan"))
+	rows := tk.MustQuery("select action, reason, baseline_plan_digest from mysql.plan_baseline_history order by id").Rows()
+	require.Len(t, rows, 5)
+	require.Equal(t, "reject", rows[4][0])
+	require.Contains(t, rows[4][1], "the plan doesn't finish in 100ms")
+	require.Equal(t, "plan_scan", rows[4][2])
+	require.Len(t, tk.MustQuery("show global bindings").Rows(), 2)
+}
@@ -104,6 +104,16 @@ type GlobalBindingHandle interface {
 	// CaptureBaselines is used to automatically capture plan baselines.
 	CaptureBaselines()
 
+	// Methods for Plan Baseline Evolution.
+
+	// RecordPlanBaselines records the plans in the statement summary as plan baselines, and falls back to
+	// the last accepted plan by a binding if a plan regression is detected.
+	RecordPlanBaselines()
+
+	// EvolvePlanBaselines verifies the plans chosen by the optimizer for the statements which fall back to
+	// the accepted plans, and accepts them if they perform better.
+	EvolvePlanBaselines(adminEvolve bool) error
+
 	variable.Statistics
 }
 
@@ -53,11 +53,13 @@ import (
 type backfillerType byte
 
 const (
-	typeAddIndexWorker         backfillerType = 0
-	typeUpdateColumnWorker     backfillerType = 1
-	typeCleanUpIndexWorker     backfillerType = 2
-	typeAddIndexMergeTmpWorker backfillerType = 3
-	typeReorgPartitionWorker   backfillerType = 4
+	typeAddIndexWorker            backfillerType = 0
+	typeUpdateColumnWorker        backfillerType = 1
+	typeCleanUpIndexWorker        backfillerType = 2
+	typeAddIndexMergeTmpWorker    backfillerType = 3
+	typeReorgPartitionWorker      backfillerType = 4
+	typeReorgPrimaryKeyWorker     backfillerType = 5
+	typeReassignVectorIndexWorker backfillerType = 6
 )
 
 func (bT backfillerType) String() string {
@@ -72,6 +74,10 @@ func (bT backfillerType) String() string {
 		return "merge temporary index"
 	case typeReorgPartitionWorker:
 		return "reorganize partition"
+	case typeReorgPrimaryKeyWorker:
+		return "alter primary key"
+	case typeReassignVectorIndexWorker:
+		return "reassign vector index"
 	default:
 		return "unknown"
 	}
@@ -82,6 +88,7 @@ func (bT backfillerType) String() string {
 // 2: modify-column-type
 // 3: clean-up global index
 // 4: reorganize partition
+// 5: alter primary key
 //
 // They all have a write reorganization state to back fill data into the rows existed.
 // Backfilling is time consuming, to accelerate this process, TiDB has built some sub
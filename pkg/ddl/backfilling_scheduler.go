@@ -261,7 +261,10 @@ func (b *txnBackfillScheduler) adjustWorkerSize() error {
 			sessCtx.GetSessionVars().StmtCtx.SetTypeFlags(
 				sessCtx.GetSessionVars().StmtCtx.TypeFlags().
 					WithIgnoreZeroDateErr(!reorgInfo.ReorgMeta.SQLMode.HasStrictMode()))
-			updateWorker := newUpdateColumnWorker(sessCtx, i, b.tbl, b.decodeColMap, reorgInfo, jc)
+			updateWorker, err := newUpdateColumnWorker(sessCtx, i, b.tbl, b.decodeColMap, reorgInfo, jc)
+			if err != nil {
+				return err
+			}
 			runner = newBackfillWorker(jc.ddlJobCtx, updateWorker)
 			worker = updateWorker
 		case typeCleanUpIndexWorker:
@@ -275,6 +278,17 @@ func (b *txnBackfillScheduler) adjustWorkerSize() error {
 			}
 			runner = newBackfillWorker(jc.ddlJobCtx, partWorker)
 			worker = partWorker
+		case typeReorgPrimaryKeyWorker:
+			pkWorker, err := newReorgPrimaryKeyWorker(sessCtx, i, b.tbl, b.decodeColMap, reorgInfo, jc)
+			if err != nil {
+				return err
+			}
+			runner = newBackfillWorker(jc.ddlJobCtx, pkWorker)
+			worker = pkWorker
+		case typeReassignVectorIndexWorker:
+			idxWorker := newReassignVectorIndexWorker(sessCtx, i, b.tbl, b.decodeColMap, reorgInfo, jc)
+			runner = newBackfillWorker(jc.ddlJobCtx, idxWorker)
+			worker = idxWorker
 		default:
 			return errors.New("unknown backfill type")
 		}
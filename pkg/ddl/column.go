@@ -69,21 +69,35 @@ func InitAndAddColumnToTable(tblInfo *model.TableInfo, colInfo *model.ColumnInfo
 	return colInfo
 }
 
+// InitAndAddColumnIndexesToTable initializes the indexes defined on the adding column in-place and adds them to the
+// table. See BuildAddColumnIndexInfos.
+func InitAndAddColumnIndexesToTable(tblInfo *model.TableInfo, colInfo *model.ColumnInfo, idxInfos []*model.IndexInfo) {
+	for _, idxInfo := range idxInfos {
+		idxInfo.ID = AllocateIndexID(tblInfo)
+		idxInfo.State = colInfo.State
+		for _, idxCol := range idxInfo.Columns {
+			idxCol.Offset = colInfo.Offset
+		}
+		tblInfo.Indices = append(tblInfo.Indices, idxInfo)
+	}
+}
+
 func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo,
-	*ast.ColumnPosition, bool /* ifNotExists */, error) {
+	[]*model.IndexInfo, *ast.ColumnPosition, bool /* ifNotExists */, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, schemaID)
 	if err != nil {
-		return nil, nil, nil, nil, false, errors.Trace(err)
+		return nil, nil, nil, nil, nil, false, errors.Trace(err)
 	}
 	col := &model.ColumnInfo{}
 	pos := &ast.ColumnPosition{}
 	offset := 0
 	ifNotExists := false
-	err = job.DecodeArgs(col, pos, &offset, &ifNotExists)
+	var idxInfos []*model.IndexInfo
+	err = job.DecodeArgs(col, pos, &offset, &ifNotExists, &idxInfos)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return nil, nil, nil, nil, false, errors.Trace(err)
+		return nil, nil, nil, nil, nil, false, errors.Trace(err)
 	}
 
 	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
@@ -91,20 +105,34 @@ func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.Colu
 		if columnInfo.State == model.StatePublic {
 			// We already have a column with the same column name.
 			job.State = model.JobStateCancelled
-			return nil, nil, nil, nil, ifNotExists, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+			return nil, nil, nil, nil, nil, ifNotExists, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
 		}
+		// The indexes have been added to the table along with the column.
+		idxInfos = listIndicesWithColumn(columnInfo.Name.L, tblInfo.Indices)
 	}
 
 	err = CheckAfterPositionExists(tblInfo, pos)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return nil, nil, nil, nil, false, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+		return nil, nil, nil, nil, nil, false, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
 	}
 
-	return tblInfo, columnInfo, col, pos, false, nil
+	return tblInfo, columnInfo, col, idxInfos, pos, false, nil
+}
+
+// needReorgForAddColumn checks whether adding the column needs an online reorganization, which fills the column
+// values and builds the indexes defined on the column before the column becomes public.
+func needReorgForAddColumn(col *model.ColumnInfo, idxInfos []*model.IndexInfo) bool {
+	return needBackfillAddedColumn(col) || len(idxInfos) > 0
+}
+
+// needBackfillAddedColumn checks whether the rows need to be filled with the values of the added column, since the
+// values of auto-increment and stored generated columns can't be the origin default value.
+func needBackfillAddedColumn(col *model.ColumnInfo) bool {
+	return mysql.HasAutoIncrementFlag(col.GetFlag()) || (col.IsGenerated() && col.GeneratedStored)
 }
 
-func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+func (w *worker) onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	// Handle the rolling back job.
 	if job.IsRollingback() {
 		ver, err = onDropColumn(d, t, job)
@@ -121,7 +149,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		}
 	})
 
-	tblInfo, columnInfo, colFromArgs, pos, ifNotExists, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, colFromArgs, idxInfos, pos, ifNotExists, err := checkAddColumn(t, job)
 	if err != nil {
 		if ifNotExists && infoschema.ErrColumnExists.Equal(err) {
 			job.Warning = toTError(err)
@@ -137,6 +165,10 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
+		InitAndAddColumnIndexesToTable(tblInfo, columnInfo, idxInfos)
+	}
+	if needReorgForAddColumn(columnInfo, idxInfos) {
+		return w.doAddColumnWithReorg(d, t, job, tblInfo, columnInfo, idxInfos, pos)
 	}
 
 	originalState := columnInfo.State
@@ -197,6 +229,129 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 	return ver, errors.Trace(err)
 }
 
+// doAddColumnWithReorg adds the column and its indexes together. In the write-reorganization state, the column values
+// are filled and the indexes are built, which also validates the uniqueness, before the column becomes public.
+func (w *worker) doAddColumnWithReorg(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo,
+	columnInfo *model.ColumnInfo, idxInfos []*model.IndexInfo, pos *ast.ColumnPosition) (ver int64, _ error) {
+	var err error
+	originalState := columnInfo.State
+	switch columnInfo.State {
+	case model.StateNone:
+		// none -> delete only
+		updateChangingObjState(columnInfo, idxInfos, model.StateDeleteOnly)
+		ver, err = updateVersionAndTableInfoWithCheck(d, t, job, tblInfo, originalState != columnInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		updateChangingObjState(columnInfo, idxInfos, model.StateWriteOnly)
+		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != columnInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		updateChangingObjState(columnInfo, idxInfos, model.StateWriteReorganization)
+		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != columnInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		tbl, err := getTable((*asAutoIDRequirement)(d), job.SchemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		var done bool
+		done, ver, err = doReorgWorkForAddColumn(w, d, t, job, tbl, columnInfo, idxInfos)
+		if !done {
+			return ver, err
+		}
+
+		// reorganization -> public
+		offset, err := LocateOffsetToMove(columnInfo.Offset, pos, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		tblInfo.MoveColumnInfo(columnInfo.Offset, offset)
+		updateChangingObjState(columnInfo, idxInfos, model.StatePublic)
+		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != columnInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		addColumnEvent := statsutil.NewAddColumnEvent(
+			job.SchemaID,
+			tblInfo,
+			[]*model.ColumnInfo{columnInfo},
+		)
+		asyncNotifyEvent(d, addColumnEvent)
+	default:
+		err = dbterror.ErrInvalidDDLState.GenWithStackByArgs("column", columnInfo.State)
+	}
+
+	return ver, errors.Trace(err)
+}
+
+func doReorgWorkForAddColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job, tbl table.Table,
+	columnInfo *model.ColumnInfo, idxInfos []*model.IndexInfo) (done bool, ver int64, err error) {
+	job.ReorgMeta.ReorgTp = model.ReorgTypeTxn
+	sctx, err1 := w.sessPool.Get()
+	if err1 != nil {
+		err = errors.Trace(err1)
+		return
+	}
+	defer w.sessPool.Put(sctx)
+	rh := newReorgHandler(sess.NewSession(sctx))
+	dbInfo, err := t.GetDatabase(job.SchemaID)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+	reorgInfo, err := getReorgInfo(d.jobContext(job.ID, job.ReorgMeta),
+		d, rh, job, dbInfo, tbl, BuildElements(columnInfo, idxInfos), false)
+	if err != nil || reorgInfo == nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(reorgInfo, tbl.Meta(), d.lease, func() (addColumnErr error) {
+		defer util.Recover(metrics.LabelDDL, "onAddColumn",
+			func() {
+				addColumnErr = dbterror.ErrCancelledDDLJob.GenWithStack("add table `%v` column `%v` panic", tbl.Meta().Name, columnInfo.Name)
+			}, false)
+		return w.updateCurrentElement(tbl, reorgInfo)
+	})
+	if err != nil {
+		if dbterror.ErrPausedDDLJob.Equal(err) {
+			return false, ver, nil
+		}
+
+		if dbterror.ErrWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.IsTxnRetryableError(err) || dbterror.ErrNotOwner.Equal(err) {
+			return false, ver, errors.Trace(err)
+		}
+		if err1 := rh.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("run add column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback", zap.String("category", "ddl"),
+				zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("run add column job failed, convert job to rollback", zap.String("category", "ddl"), zap.String("job", job.String()), zap.Error(err))
+		ver, err = convertAddColumnJob2RollbackJob(d, t, job, tbl.Meta(), columnInfo, idxInfos, err)
+		return false, ver, err
+	}
+	return true, ver, nil
+}
+
 // CheckAfterPositionExists makes sure the column specified in AFTER clause is exists.
 // For example, ALTER TABLE t ADD COLUMN c3 INT AFTER c1.
 func CheckAfterPositionExists(tblInfo *model.TableInfo, pos *ast.ColumnPosition) error {
@@ -367,6 +522,12 @@ func onSetDefaultValue(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ er
 	return updateColumnDefaultValue(d, t, job, newCol, &newCol.Name)
 }
 
+// needReorgColumnData checks whether the rows of the table need reorg when the column is modified, that is the column
+// data has to be changed, or the rows have to be moved to new partitions.
+func needReorgColumnData(tblInfo *model.TableInfo, oldCol, newCol *model.ColumnInfo) bool {
+	return needChangeColumnData(oldCol, newCol) || needReorgPartitionForModifyColumn(tblInfo, oldCol, newCol)
+}
+
 func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
 	toUnsigned := mysql.HasUnsignedFlag(newCol.GetFlag())
 	originUnsigned := mysql.HasUnsignedFlag(oldCol.GetFlag())
@@ -523,7 +684,7 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 
 	if job.IsRollingback() {
 		// For those column-type-change jobs which don't reorg the data.
-		if !needChangeColumnData(oldCol, modifyInfo.newCol) {
+		if !needReorgColumnData(tblInfo, oldCol, modifyInfo.newCol) {
 			return rollbackModifyColumnJob(d, t, tblInfo, job, modifyInfo.newCol, oldCol, modifyInfo.modifyColumnTp)
 		}
 		// For those column-type-change jobs which reorg the data.
@@ -554,7 +715,7 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 		return ver, errors.Trace(err)
 	}
 
-	if !needChangeColumnData(oldCol, modifyInfo.newCol) {
+	if !needReorgColumnData(tblInfo, oldCol, modifyInfo.newCol) {
 		return w.doModifyColumn(d, t, job, dbInfo, tblInfo, modifyInfo.newCol, oldCol, modifyInfo.pos)
 	}
 
@@ -562,10 +723,6 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
-	if tblInfo.Partition != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("table is partition table"))
-	}
 
 	changingCol := modifyInfo.changingCol
 	if changingCol == nil {
@@ -575,6 +732,14 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 			msg := "this column has primary key flag"
 			return ver, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs(msg)
 		}
+		var reorgPartDefs []model.PartitionDefinition
+		if tblInfo.Partition != nil {
+			reorgPartDefs, err = w.checkModifyPartitionedTableColumn(d, job, dbInfo, tblInfo, oldCol, modifyInfo.newCol)
+			if err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(err)
+			}
+		}
 
 		changingCol = modifyInfo.newCol.Clone()
 		changingCol.Name = newColName
@@ -609,6 +774,12 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 				modifyInfo.removedIdxs = append(modifyInfo.removedIdxs, oldTempIdxID)
 			}
 		}
+		if len(reorgPartDefs) > 0 {
+			err = w.initReorgPartitionForModifyColumn(d, t, tblInfo, oldCol, changingCol, reorgPartDefs)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
 	} else {
 		changingCol = model.FindColumnInfoByID(tblInfo.Columns, modifyInfo.changingCol.ID)
 		if changingCol == nil {
@@ -621,6 +792,95 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 	return w.doModifyColumnTypeWithData(d, t, job, dbInfo, tblInfo, changingCol, oldCol, modifyInfo.newCol.Name, modifyInfo.pos, modifyInfo.removedIdxs)
 }
 
+// checkModifyPartitionedTableColumn checks whether the column of the partitioned table can be modified with data
+// reorg. It returns the new partition definitions if the rows have to be moved to new partitions.
+func (w *worker) checkModifyPartitionedTableColumn(d *ddlCtx, job *model.Job, dbInfo *model.DBInfo, tblInfo *model.TableInfo,
+	oldCol, newCol *model.ColumnInfo) ([]model.PartitionDefinition, error) {
+	if err := checkModifyColumnWithGlobalIndex(tblInfo, oldCol); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tbl, err := getTable((*asAutoIDRequirement)(d), dbInfo.ID, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The partitioning column may be changed by another job after this job is submitted.
+	defs, err := checkModifyPartitioningColumn(w.sess.Context, tbl, oldCol, newCol)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(defs) > 0 && job.MultiSchemaInfo != nil {
+		return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't change the partitioning column in a multi-schema change, since it would require reorganize all partitions")
+	}
+	return defs, nil
+}
+
+// initReorgPartitionForModifyColumn adds the new partitions the rows are moved to when the partitioning column is
+// modified. Like REORGANIZE PARTITION, the rows are written to both the old and the new partitions during the job,
+// the new partitions are located by the changing column.
+func (w *worker) initReorgPartitionForModifyColumn(d *ddlCtx, t *meta.Meta, tblInfo *model.TableInfo, oldCol, changingCol *model.ColumnInfo,
+	defs []model.PartitionDefinition) error {
+	pi := tblInfo.Partition
+	ids, err := t.GenGlobalIDs(len(defs))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i := range defs {
+		defs[i].ID = ids[i]
+	}
+	pi.AddingDefinitions = defs
+	pi.DroppingDefinitions = append([]model.PartitionDefinition{}, pi.Definitions...)
+	// Use the new partitioning in the table layer, which refers to the changing column.
+	pi.NewTableID = tblInfo.ID
+	pi.DDLType = pi.Type
+	pi.DDLExpr = pi.Expr
+	if pi.Expr != "" {
+		if pi.DDLExpr, err = renameExprColumn(pi.Expr, oldCol.Name, changingCol.Name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	pi.DDLColumns = make([]model.CIStr, 0, len(pi.Columns))
+	for _, name := range pi.Columns {
+		if name.L == oldCol.Name.L {
+			name = changingCol.Name
+		}
+		pi.DDLColumns = append(pi.DDLColumns, name)
+	}
+	preSplitAndScatter(w.sess.Context, d.store, tblInfo, pi.AddingDefinitions)
+	return nil
+}
+
+// updateReorgPartitionState updates the state of the new partitions along with the changing column, when the
+// partitioning column is modified.
+func updateReorgPartitionState(tblInfo *model.TableInfo, schemaState model.SchemaState) {
+	if isModifyingPartitioningColumn(tblInfo) {
+		tblInfo.Partition.DDLState = schemaState
+	}
+}
+
+// finishReorgPartitionForModifyColumn replaces the old partitions with the new ones the rows are moved to, when the
+// partitioning column is modified. It returns the old partitions.
+func finishReorgPartitionForModifyColumn(job *model.Job, tblInfo *model.TableInfo) (*model.PartitionInfo, error) {
+	pi := tblInfo.Partition
+	droppedPartInfo := &model.PartitionInfo{Definitions: pi.DroppingDefinitions}
+	pi.Definitions = pi.AddingDefinitions
+	pi.Num = uint64(len(pi.Definitions))
+	pi.AddingDefinitions = nil
+	pi.DroppingDefinitions = nil
+	pi.DDLState = model.StateNone
+	pi.ClearReorgIntermediateInfo()
+	if _, err := alterTableLabelRule(job.SchemaName, tblInfo, getIDs([]*model.TableInfo{tblInfo})); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return droppedPartInfo, nil
+}
+
+// isModifyingPartitioningColumn checks whether the rows are being moved to new partitions by modifying the
+// partitioning column.
+func isModifyingPartitioningColumn(tblInfo *model.TableInfo) bool {
+	pi := tblInfo.GetPartitionInfo()
+	return pi != nil && len(pi.AddingDefinitions) > 0
+}
+
 func setIdxIDName(idxInfo *model.IndexInfo, newID int64, newName model.CIStr) {
 	idxInfo.ID = newID
 	idxInfo.Name = newName
@@ -652,6 +912,16 @@ func rollbackModifyColumnJobWithData(d *ddlCtx, t *meta.Meta, tblInfo *model.Tab
 		// be removed from the tableInfo as well.
 		removeChangingColAndIdxs(tblInfo, modifyInfo.changingCol.ID)
 	}
+	var addingPartIDs []int64
+	if isModifyingPartitioningColumn(tblInfo) {
+		// The new partitions are dropped, the rows stay in the old ones.
+		pi := tblInfo.Partition
+		addingPartIDs = getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+		pi.AddingDefinitions = nil
+		pi.DroppingDefinitions = nil
+		pi.DDLState = model.StateNone
+		pi.ClearReorgIntermediateInfo()
+	}
 	ver, err = updateVersionAndTableInfoWithCheck(d, t, job, tblInfo, true)
 	if err != nil {
 		return ver, errors.Trace(err)
@@ -659,6 +929,9 @@ func rollbackModifyColumnJobWithData(d *ddlCtx, t *meta.Meta, tblInfo *model.Tab
 	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
 	// Reconstruct the job args to add the temporary index ids into delete range table.
 	job.Args = []any{changingIdxIDs, getPartitionIDs(tblInfo)}
+	if len(addingPartIDs) > 0 {
+		job.Args = append(job.Args, addingPartIDs)
+	}
 	return ver, nil
 }
 
@@ -704,6 +977,7 @@ func (w *worker) doModifyColumnTypeWithData(
 		}
 		// none -> delete only
 		updateChangingObjState(changingCol, changingIdxs, model.StateDeleteOnly)
+		updateReorgPartitionState(tblInfo, model.StateDeleteOnly)
 		failpoint.Inject("mockInsertValueAfterCheckNull", func(val failpoint.Value) {
 			if valStr, ok := val.(string); ok {
 				var sctx sessionctx.Context
@@ -745,6 +1019,7 @@ func (w *worker) doModifyColumnTypeWithData(
 		}
 		// delete only -> write only
 		updateChangingObjState(changingCol, changingIdxs, model.StateWriteOnly)
+		updateReorgPartitionState(tblInfo, model.StateWriteOnly)
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != changingCol.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -753,6 +1028,7 @@ func (w *worker) doModifyColumnTypeWithData(
 	case model.StateWriteOnly:
 		// write only -> reorganization
 		updateChangingObjState(changingCol, changingIdxs, model.StateWriteReorganization)
+		updateReorgPartitionState(tblInfo, model.StateWriteReorganization)
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != changingCol.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -783,6 +1059,13 @@ func (w *worker) doModifyColumnTypeWithData(
 			job.State = model.JobStateRollingback
 			return ver, errors.Trace(err)
 		}
+		var droppedPartInfo *model.PartitionInfo
+		if isModifyingPartitioningColumn(tblInfo) {
+			droppedPartInfo, err = finishReorgPartitionForModifyColumn(job, tblInfo)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
 
 		updateChangingObjState(changingCol, changingIdxs, model.StatePublic)
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != changingCol.State)
@@ -800,6 +1083,16 @@ func (w *worker) doModifyColumnTypeWithData(
 			[]*model.ColumnInfo{changingCol},
 		)
 		asyncNotifyEvent(d, modifyColumnEvent)
+		if droppedPartInfo != nil {
+			// The old partitions are dropped as well.
+			job.Args = append(job.Args, getPartitionIDsFromDefinitions(droppedPartInfo.Definitions))
+			asyncNotifyEvent(d, statsutil.NewReorganizePartitionEvent(
+				job.SchemaID,
+				tblInfo,
+				&model.PartitionInfo{Definitions: tblInfo.Partition.Definitions},
+				droppedPartInfo,
+			))
+		}
 	default:
 		err = dbterror.ErrInvalidDDLState.GenWithStackByArgs("column", changingCol.State)
 	}
@@ -836,8 +1129,12 @@ func doReorgWorkForModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.J
 	if err != nil {
 		return false, ver, errors.Trace(err)
 	}
+	elements := BuildElements(changingCol, changingIdxs)
+	if isModifyingPartitioningColumn(tbl.Meta()) {
+		elements = buildModifyPartitioningColumnElements(tbl.Meta(), oldCol, changingCol)
+	}
 	reorgInfo, err := getReorgInfo(d.jobContext(job.ID, job.ReorgMeta),
-		d, rh, job, dbInfo, tbl, BuildElements(changingCol, changingIdxs), false)
+		d, rh, job, dbInfo, tbl, elements, false)
 	if err != nil || reorgInfo == nil || reorgInfo.first {
 		// If we run reorg firstly, we should update the job snapshot version
 		// and then run the reorg next time.
@@ -882,6 +1179,20 @@ func doReorgWorkForModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.J
 	return true, ver, nil
 }
 
+// buildModifyPartitioningColumnElements builds the elements when the partitioning column is modified. The changing
+// column is updated first, then the rows are copied to the new partitions, where all the indexes are built except the
+// ones replaced by the changing indexes.
+func buildModifyPartitioningColumnElements(tblInfo *model.TableInfo, oldCol, changingCol *model.ColumnInfo) []*meta.Element {
+	indices := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+	for _, idx := range tblInfo.Indices {
+		if !idx.HasColumnInIndexColumns(tblInfo, oldCol.ID) {
+			indices = append(indices, idx)
+		}
+	}
+	elements := []*meta.Element{{ID: changingCol.ID, TypeKey: meta.ColumnElementKey}}
+	return append(elements, BuildElements(tblInfo.Columns[0], indices)...)
+}
+
 func adjustTableInfoAfterModifyColumnWithData(tblInfo *model.TableInfo, pos *ast.ColumnPosition,
 	oldCol, changingCol *model.ColumnInfo, newName model.CIStr, changingIdxs []*model.IndexInfo) (err error) {
 	if pos != nil && pos.RelativeColumn != nil && oldCol.Name.L == pos.RelativeColumn.Name.L {
@@ -977,7 +1288,12 @@ func updateTTLInfoWhenModifyColumn(tblInfo *model.TableInfo, oldCol, newCol mode
 		return
 	}
 	if tblInfo.TTLInfo != nil {
-		if tblInfo.TTLInfo.ColumnName.L == oldCol.L {
+		if tblInfo.TTLInfo.Expr != "" {
+			// the expression has been checked to be valid, so it's safe to ignore the error here.
+			if expr, err := renameExprColumn(tblInfo.TTLInfo.Expr, oldCol, newCol); err == nil {
+				tblInfo.TTLInfo.Expr = expr
+			}
+		} else if tblInfo.TTLInfo.ColumnName.L == oldCol.L {
 			tblInfo.TTLInfo.ColumnName = newCol
 		}
 	}
@@ -1088,10 +1404,12 @@ func (w *worker) updatePhysicalTableRow(t table.Table, reorgInfo *reorgInfo) err
 				model.ActionAlterTablePartitioning:
 				// Expected
 			default:
-				// workType = typeUpdateColumnWorker
-				// TODO: Support Modify Column on partitioned table
-				// https://github.com/pingcap/tidb/issues/38297
-				return dbterror.ErrCancelledDDLJob.GenWithStack("Modify Column on partitioned table / typeUpdateColumnWorker not yet supported.")
+				// The changing column is updated in every partition, then the rows are copied
+				// to the new partitions if the partitioning column is modified.
+				col := model.FindColumnInfoByID(t.Meta().Columns, reorgInfo.currElement.ID)
+				if col == nil || col.State != model.StatePublic {
+					workType = typeUpdateColumnWorker
+				}
 			}
 			err := w.writePhysicalTableRecord(w.sessPool, p, workType, reorgInfo)
 			if err != nil {
@@ -1110,6 +1428,13 @@ func (w *worker) updatePhysicalTableRow(t table.Table, reorgInfo *reorgInfo) err
 	return dbterror.ErrCancelledDDLJob.GenWithStack("internal error for phys tbl id: %d tbl id: %d", reorgInfo.PhysicalTableID, t.Meta().ID)
 }
 
+// needUpdateColumnRow checks whether the rows need to be updated for the column element. The rows don't need to be
+// updated for an added column whose values are the origin default, only its indexes are built.
+func needUpdateColumnRow(tblInfo *model.TableInfo, colID int64) bool {
+	col := model.FindColumnInfoByID(tblInfo.Columns, colID)
+	return col == nil || col.ChangeStateInfo != nil || needBackfillAddedColumn(col)
+}
+
 // TestReorgGoroutineRunning is only used in test to indicate the reorg goroutine has been started.
 var TestReorgGoroutineRunning = make(chan any)
 
@@ -1129,27 +1454,32 @@ func (w *worker) updateCurrentElement(t table.Table, reorgInfo *reorgInfo) error
 			}
 		}
 	})
-	// TODO: Support partition tables.
-	if bytes.Equal(reorgInfo.currElement.TypeKey, meta.ColumnElementKey) {
-		//nolint:forcetypeassert
-		err := w.updatePhysicalTableRow(t.(table.PhysicalTable), reorgInfo)
+	if isModifyingPartitioningColumn(t.Meta()) {
+		return w.updatePartitioningColumnElement(t, reorgInfo)
+	}
+	if bytes.Equal(reorgInfo.currElement.TypeKey, meta.ColumnElementKey) && needUpdateColumnRow(t.Meta(), reorgInfo.currElement.ID) {
+		err := w.updatePhysicalTableRow(t, reorgInfo)
 		if err != nil {
 			return errors.Trace(err)
 		}
 	}
 
-	if _, ok := t.(table.PartitionedTable); ok {
-		// TODO: remove when modify column of partitioned table is supported
-		// https://github.com/pingcap/tidb/issues/38297
-		return dbterror.ErrCancelledDDLJob.GenWithStack("Modify Column on partitioned table / typeUpdateColumnWorker not yet supported.")
-	}
 	// Get the original start handle and end handle.
 	currentVer, err := getValidCurrentVersion(reorgInfo.d.store)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	//nolint:forcetypeassert
-	originalStartHandle, originalEndHandle, err := getTableRange(reorgInfo.NewJobContext(), reorgInfo.d, t.(table.PhysicalTable), currentVer.Ver, reorgInfo.Job.Priority)
+	// The indexes of the partitioned table are built from the first partition.
+	firstPhysicalTableID := reorgInfo.PhysicalTableID
+	var physTbl table.PhysicalTable
+	if tbl, ok := t.(table.PartitionedTable); ok {
+		firstPhysicalTableID = t.Meta().Partition.Definitions[0].ID
+		physTbl = tbl.GetPartition(firstPhysicalTableID)
+	} else {
+		//nolint:forcetypeassert
+		physTbl = t.(table.PhysicalTable)
+	}
+	originalStartHandle, originalEndHandle, err := getTableRange(reorgInfo.NewJobContext(), reorgInfo.d, physTbl, currentVer.Ver, reorgInfo.Job.Priority)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1170,7 +1500,9 @@ func (w *worker) updateCurrentElement(t table.Table, reorgInfo *reorgInfo) error
 	for i := startElementOffset; i < len(reorgInfo.elements[1:]); i++ {
 		// This backfill job has been exited during processing. At that time, the element is reorgInfo.elements[i+1] and handle range is [reorgInfo.StartHandle, reorgInfo.EndHandle].
 		// Then the handle range of the rest elements' is [originalStartHandle, originalEndHandle].
-		if i == startElementOffsetToResetHandle+1 {
+		// The partitions of the partitioned table are processed one by one for every element.
+		if i > startElementOffsetToResetHandle {
+			reorgInfo.PhysicalTableID = firstPhysicalTableID
 			reorgInfo.StartKey, reorgInfo.EndKey = originalStartHandle, originalEndHandle
 		}
 
@@ -1194,10 +1526,59 @@ func (w *worker) updateCurrentElement(t table.Table, reorgInfo *reorgInfo) error
 	return nil
 }
 
+// updatePartitioningColumnElement updates the changing column in the old partitions, then copies the rows to the
+// new partitions and builds the indexes there like REORGANIZE PARTITION, when the partitioning column is modified.
+func (w *worker) updatePartitioningColumnElement(t table.Table, reorgInfo *reorgInfo) error {
+	tbl, ok := t.(table.PartitionedTable)
+	if !ok {
+		return dbterror.ErrCancelledDDLJob.GenWithStack("internal error for modifying partitioning column of table %d", t.Meta().ID)
+	}
+	changingColElement := reorgInfo.elements[0]
+	if bytes.Equal(reorgInfo.currElement.TypeKey, changingColElement.TypeKey) && reorgInfo.currElement.ID == changingColElement.ID {
+		err := w.updatePhysicalTableRow(t, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Start copying the rows from the first old partition.
+		pid := t.Meta().Partition.DroppingDefinitions[0].ID
+		currentVer, err := getValidCurrentVersion(reorgInfo.d.store)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		startKey, endKey, err := getTableRange(reorgInfo.NewJobContext(), reorgInfo.d, tbl.GetPartition(pid), currentVer.Ver, reorgInfo.Job.Priority)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reorgInfo.currElement = reorgInfo.elements[1]
+		reorgInfo.PhysicalTableID, reorgInfo.StartKey, reorgInfo.EndKey = pid, startKey, endKey
+		// Write the reorg info to store so the whole reorganize process can recover from panic.
+		err = reorgInfo.UpdateReorgMeta(reorgInfo.StartKey, w.sessPool)
+		logutil.BgLogger().Info("update partitioning column and copy rows", zap.String("category", "ddl"),
+			zap.Int64("job ID", reorgInfo.Job.ID),
+			zap.Stringer("element", reorgInfo.currElement),
+			zap.Int64("partitionTableID", pid),
+			zap.String("start key", hex.EncodeToString(reorgInfo.StartKey)),
+			zap.String("end key", hex.EncodeToString(reorgInfo.EndKey)))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// The rest elements are the same as REORGANIZE PARTITION.
+	elements := reorgInfo.elements
+	reorgInfo.elements = elements[1:]
+	defer func() {
+		reorgInfo.elements = elements
+	}()
+	return w.reorgPartitionDataAndIndex(t, reorgInfo)
+}
+
 type updateColumnWorker struct {
 	*backfillCtx
+	// oldColInfo is nil if the new column is being added.
 	oldColInfo *model.ColumnInfo
 	newColInfo *model.ColumnInfo
+	// genExpr is the expression of the stored generated column being added.
+	genExpr expression.Expression
 
 	// The following attributes are used to reduce memory allocation.
 	rowRecords []*rowRecord
@@ -1209,20 +1590,30 @@ type updateColumnWorker struct {
 	checksumNeeded bool
 }
 
-func newUpdateColumnWorker(sessCtx sessionctx.Context, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo, jc *JobContext) *updateColumnWorker {
+func newUpdateColumnWorker(sessCtx sessionctx.Context, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo, jc *JobContext) (*updateColumnWorker, error) {
 	if !bytes.Equal(reorgInfo.currElement.TypeKey, meta.ColumnElementKey) {
 		logutil.BgLogger().Error("Element type for updateColumnWorker incorrect", zap.String("jobQuery", reorgInfo.Query),
 			zap.String("reorgInfo", reorgInfo.String()))
-		return nil
+		return nil, dbterror.ErrCancelledDDLJob.GenWithStack("element type for updateColumnWorker incorrect")
 	}
 	var oldCol, newCol *model.ColumnInfo
 	for _, col := range t.WritableCols() {
 		if col.ID == reorgInfo.currElement.ID {
 			newCol = col.ColumnInfo
-			oldCol = table.FindCol(t.Cols(), getChangingColumnOriginName(newCol)).ColumnInfo
+			if newCol.ChangeStateInfo != nil {
+				oldCol = table.FindCol(t.Cols(), getChangingColumnOriginName(newCol)).ColumnInfo
+			}
 			break
 		}
 	}
+	var genExpr expression.Expression
+	if oldCol == nil && newCol.IsGenerated() {
+		var err error
+		genExpr, err = expression.ParseSimpleExpr(sessCtx.GetExprCtx(), newCol.GeneratedExprString, expression.WithTableInfo("", t.Meta()))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	rowDecoder := decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap)
 	checksumNeeded := false
 	failpoint.Inject("forceRowLevelChecksumOnUpdateColumnBackfill", func() {
@@ -1232,7 +1623,7 @@ func newUpdateColumnWorker(sessCtx sessionctx.Context, id int, t table.PhysicalT
 	})
 	// We use global `EnableRowLevelChecksum` to detect whether checksum is enabled in ddl backfill worker because
 	// `SessionVars.IsRowLevelChecksumEnabled` will filter out internal sessions.
-	if variable.EnableRowLevelChecksum.Load() {
+	if variable.EnableRowLevelChecksum.Load() && oldCol != nil {
 		if numNonPubCols := len(t.DeletableCols()) - len(t.Cols()); numNonPubCols > 1 {
 			cols := make([]*model.ColumnInfo, len(t.DeletableCols()))
 			for i, col := range t.DeletableCols() {
@@ -1248,10 +1639,11 @@ func newUpdateColumnWorker(sessCtx sessionctx.Context, id int, t table.PhysicalT
 		backfillCtx:    newBackfillCtx(reorgInfo.d, id, sessCtx, reorgInfo.SchemaName, t, jc, "update_col_rate", false),
 		oldColInfo:     oldCol,
 		newColInfo:     newCol,
+		genExpr:        genExpr,
 		rowDecoder:     rowDecoder,
 		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
 		checksumNeeded: checksumNeeded,
-	}
+	}, nil
 }
 
 func (w *updateColumnWorker) AddMetricInfo(cnt float64) {
@@ -1324,57 +1716,74 @@ func (w *updateColumnWorker) fetchRowColVals(txn kv.Transaction, taskRange reorg
 
 func (w *updateColumnWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
 	sysTZ := w.sessCtx.GetSessionVars().StmtCtx.TimeZone()
+	if w.oldColInfo == nil {
+		// The added column is filled with the origin default value when decoding the row, so check it on the raw row.
+		existed, err := rowContainsColumn(rawRow, w.newColInfo, sysTZ)
+		if err != nil {
+			return errors.Trace(dbterror.ErrCantDecodeRecord.GenWithStackByArgs("column", err))
+		}
+		if existed {
+			// The column is already added by update or insert statement, skip it.
+			return nil
+		}
+	}
 	_, err := w.rowDecoder.DecodeTheExistedColumnMap(w.sessCtx, handle, rawRow, sysTZ, w.rowMap)
 	if err != nil {
 		return errors.Trace(dbterror.ErrCantDecodeRecord.GenWithStackByArgs("column", err))
 	}
 
-	if _, ok := w.rowMap[w.newColInfo.ID]; ok {
-		// The column is already added by update or insert statement, skip it.
-		w.cleanRowMap()
-		return nil
-	}
-
 	var recordWarning *terror.Error
-	// Since every updateColumnWorker handle their own work individually, we can cache warning in statement context when casting datum.
-	oldWarn := w.sessCtx.GetSessionVars().StmtCtx.GetWarnings()
-	if oldWarn == nil {
-		oldWarn = []stmtctx.SQLWarn{}
+	if w.oldColInfo == nil {
+		if err = w.fillAddedColumnValue(); err != nil {
+			return errors.Trace(err)
+		}
 	} else {
-		oldWarn = oldWarn[:0]
-	}
-	w.sessCtx.GetSessionVars().StmtCtx.SetWarnings(oldWarn)
-	val := w.rowMap[w.oldColInfo.ID]
-	col := w.newColInfo
-	if val.Kind() == types.KindNull && col.FieldType.GetType() == mysql.TypeTimestamp && mysql.HasNotNullFlag(col.GetFlag()) {
-		if v, err := expression.GetTimeCurrentTimestamp(w.sessCtx.GetExprCtx(), col.GetType(), col.GetDecimal()); err == nil {
-			// convert null value to timestamp should be substituted with current timestamp if NOT_NULL flag is set.
-			w.rowMap[w.oldColInfo.ID] = v
+		if _, ok := w.rowMap[w.newColInfo.ID]; ok {
+			// The column is already added by update or insert statement, skip it.
+			w.cleanRowMap()
+			return nil
 		}
-	}
-	newColVal, err := table.CastValue(w.sessCtx, w.rowMap[w.oldColInfo.ID], w.newColInfo, false, false)
-	if err != nil {
-		return w.reformatErrors(err)
-	}
-	warn := w.sessCtx.GetSessionVars().StmtCtx.GetWarnings()
-	if len(warn) != 0 {
-		//nolint:forcetypeassert
-		recordWarning = errors.Cause(w.reformatErrors(warn[0].Err)).(*terror.Error)
-	}
 
-	failpoint.Inject("MockReorgTimeoutInOneRegion", func(val failpoint.Value) {
-		//nolint:forcetypeassert
-		if val.(bool) {
-			if handle.IntValue() == 3000 && atomic.CompareAndSwapInt32(&TestCheckReorgTimeout, 0, 1) {
-				failpoint.Return(errors.Trace(dbterror.ErrWaitReorgTimeout))
+		// Since every updateColumnWorker handle their own work individually, we can cache warning in statement context when casting datum.
+		oldWarn := w.sessCtx.GetSessionVars().StmtCtx.GetWarnings()
+		if oldWarn == nil {
+			oldWarn = []stmtctx.SQLWarn{}
+		} else {
+			oldWarn = oldWarn[:0]
+		}
+		w.sessCtx.GetSessionVars().StmtCtx.SetWarnings(oldWarn)
+		val := w.rowMap[w.oldColInfo.ID]
+		col := w.newColInfo
+		if val.Kind() == types.KindNull && col.FieldType.GetType() == mysql.TypeTimestamp && mysql.HasNotNullFlag(col.GetFlag()) {
+			if v, err := expression.GetTimeCurrentTimestamp(w.sessCtx.GetExprCtx(), col.GetType(), col.GetDecimal()); err == nil {
+				// convert null value to timestamp should be substituted with current timestamp if NOT_NULL flag is set.
+				w.rowMap[w.oldColInfo.ID] = v
 			}
 		}
-	})
+		newColVal, err := table.CastValue(w.sessCtx, w.rowMap[w.oldColInfo.ID], w.newColInfo, false, false)
+		if err != nil {
+			return w.reformatErrors(err)
+		}
+		warn := w.sessCtx.GetSessionVars().StmtCtx.GetWarnings()
+		if len(warn) != 0 {
+			//nolint:forcetypeassert
+			recordWarning = errors.Cause(w.reformatErrors(warn[0].Err)).(*terror.Error)
+		}
 
-	w.rowMap[w.newColInfo.ID] = newColVal
-	_, err = w.rowDecoder.EvalRemainedExprColumnMap(w.sessCtx, w.rowMap)
-	if err != nil {
-		return errors.Trace(err)
+		failpoint.Inject("MockReorgTimeoutInOneRegion", func(val failpoint.Value) {
+			//nolint:forcetypeassert
+			if val.(bool) {
+				if handle.IntValue() == 3000 && atomic.CompareAndSwapInt32(&TestCheckReorgTimeout, 0, 1) {
+					failpoint.Return(errors.Trace(dbterror.ErrWaitReorgTimeout))
+				}
+			}
+		})
+
+		w.rowMap[w.newColInfo.ID] = newColVal
+		_, err = w.rowDecoder.EvalRemainedExprColumnMap(w.sessCtx, w.rowMap)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	newColumnIDs := make([]int64, 0, len(w.rowMap))
 	newRow := make([]types.Datum, 0, len(w.rowMap))
@@ -1395,6 +1804,56 @@ func (w *updateColumnWorker) getRowRecord(handle kv.Handle, recordKey []byte, ra
 	return nil
 }
 
+// fillAddedColumnValue fills the value of the column being added into the row, which is a newly allocated
+// auto-increment ID or the value of the stored generated column.
+func (w *updateColumnWorker) fillAddedColumnValue() error {
+	_, err := w.rowDecoder.EvalRemainedExprColumnMap(w.sessCtx, w.rowMap)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var val types.Datum
+	if w.genExpr != nil {
+		val, err = w.genExpr.Eval(w.sessCtx.GetExprCtx().GetEvalCtx(), w.rowDecoder.CurrentRowWithDefaultVal())
+		if err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		alloc := w.table.Allocators(nil).Get(autoid.AutoIncrementType)
+		if alloc == nil {
+			return errors.Trace(autoid.ErrAutoincReadFailed)
+		}
+		ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnDDL)
+		_, id, err := alloc.Alloc(ctx, 1, 1, 1)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		val.SetAutoID(id, w.newColInfo.GetFlag())
+	}
+	val, err = table.CastValue(w.sessCtx, val, w.newColInfo, false, false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.rowMap[w.newColInfo.ID] = val
+	return nil
+}
+
+// rowContainsColumn checks whether the column is stored in the raw row.
+func rowContainsColumn(rawRow []byte, col *model.ColumnInfo, loc *time.Location) (bool, error) {
+	cols := map[int64]*types.FieldType{col.ID: &col.FieldType}
+	var row map[int64]types.Datum
+	var err error
+	if rowcodec.IsNewFormat(rawRow) {
+		row, err = tablecodec.DecodeRowWithMapNew(rawRow, cols, loc, nil)
+	} else {
+		row, err = tablecodec.DecodeRowWithMap(rawRow, cols, loc, nil)
+	}
+	if err != nil {
+		return false, err
+	}
+	_, ok := row[col.ID]
+	return ok, nil
+}
+
 func (w *updateColumnWorker) calcChecksums() []uint32 {
 	if !w.checksumNeeded {
 		return nil
@@ -1845,6 +2304,20 @@ func isColumnWithIndex(colName string, indices []*model.IndexInfo) bool {
 	return false
 }
 
+// checkModifyColumnWithGlobalIndex checks whether the column of the partitioned table can be modified with data reorg.
+// The indexes are rebuilt on every partition, which is not supported for global indexes yet.
+func checkModifyColumnWithGlobalIndex(tblInfo *model.TableInfo, col *model.ColumnInfo) error {
+	if tblInfo.GetPartitionInfo() == nil {
+		return nil
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.Global && idx.HasColumnInIndexColumns(tblInfo, col.ID) {
+			return dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs(fmt.Sprintf("global index %s is defined on it", idx.Name.O))
+		}
+	}
+	return nil
+}
+
 func isColumnCanDropWithIndex(colName string, indices []*model.IndexInfo) error {
 	for _, indexInfo := range indices {
 		if indexInfo.Primary || len(indexInfo.Columns) > 1 {
@@ -184,14 +184,14 @@ AddLoop:
 	tk.MustExec("alter table test_on_update_e add column c2 year not null;")
 	tk.MustQuery("select c2 from test_on_update_e").Check(testkit.Rows("0"))
 
-	// test add unsupported constraint
-	tk.MustExec("create table t_add_unsupported_constraint (a int);")
-	err = tk.ExecToErr("ALTER TABLE t_add_unsupported_constraint ADD id int AUTO_INCREMENT;")
-	require.EqualError(t, err, "[ddl:8200]unsupported add column 'id' constraint AUTO_INCREMENT when altering 'test.t_add_unsupported_constraint'")
-	err = tk.ExecToErr("ALTER TABLE t_add_unsupported_constraint ADD id int KEY;")
-	require.EqualError(t, err, "[ddl:8200]unsupported add column 'id' constraint PRIMARY KEY when altering 'test.t_add_unsupported_constraint'")
-	err = tk.ExecToErr("ALTER TABLE t_add_unsupported_constraint ADD id int UNIQUE;")
-	require.EqualError(t, err, "[ddl:8200]unsupported add column 'id' constraint UNIQUE KEY when altering 'test.t_add_unsupported_constraint'")
+	// test add column with constraints which needs reorganization
+	tk.MustExec("create table t_add_constraint (a int) partition by hash(a) partitions 2;")
+	err = tk.ExecToErr("ALTER TABLE t_add_constraint ADD id int AUTO_INCREMENT;")
+	require.EqualError(t, err, "[ddl:8200]unsupported add column 'id' which needs reorganization on partitioned table when altering 'test.t_add_constraint'")
+	err = tk.ExecToErr("ALTER TABLE t_add_constraint ADD id int KEY;")
+	require.EqualError(t, err, "[ddl:8200]unsupported add column 'id' which needs reorganization on partitioned table when altering 'test.t_add_constraint'")
+	err = tk.ExecToErr("ALTER TABLE t_add_constraint ADD id int UNIQUE;")
+	require.EqualError(t, err, "[ddl:8200]unsupported add column 'id' which needs reorganization on partitioned table when altering 'test.t_add_constraint'")
 
 	// ===========
 	// DROP COLUMN
@@ -250,6 +250,49 @@ DropLoop:
 	require.Greater(t, count, int64(0))
 }
 
+func TestAddColumnWithReorg(t *testing.T) {
+	store := testkit.CreateMockStoreWithSchemaLease(t, columnModifyLease)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	// auto-increment column
+	tk.MustExec("create table t1 (a int);")
+	tk.MustExec("insert into t1 values (1), (2), (3);")
+	tk.MustExec("alter table t1 add column id int auto_increment unique;")
+	tk.MustQuery("select count(distinct id) from t1 where id is not null").Check(testkit.Rows("3"))
+	tk.MustExec("insert into t1 (a) values (4);")
+	tk.MustQuery("select count(distinct id) from t1").Check(testkit.Rows("4"))
+	tk.MustQuery("select count(*) from t1 use index(id)").Check(testkit.Rows("4"))
+	tk.MustExec("admin check table t1;")
+	tk.MustGetErrCode("alter table t1 add column id2 int auto_increment;", errno.ErrWrongAutoKey)
+
+	// stored generated column
+	tk.MustExec("create table t2 (a int, b int);")
+	tk.MustExec("insert into t2 values (1, 2), (3, 4);")
+	tk.MustExec("alter table t2 add column c int as (a + b) stored;")
+	tk.MustQuery("select a, b, c from t2 order by a").Check(testkit.Rows("1 2 3", "3 4 7"))
+	tk.MustExec("update t2 set a = 10 where a = 1;")
+	tk.MustQuery("select a, b, c from t2 order by a").Check(testkit.Rows("3 4 7", "10 2 12"))
+	tk.MustExec("admin check table t2;")
+
+	// primary key and unique column
+	tk.MustExec("create table t3 (a int);")
+	tk.MustExec("insert into t3 values (1);")
+	tk.MustExec("alter table t3 add column b int default 5 primary key;")
+	tk.MustQuery("select a, b from t3 use index(primary)").Check(testkit.Rows("1 5"))
+	tk.MustGetErrCode("insert into t3 values (2, 5);", errno.ErrDupEntry)
+	tk.MustGetErrCode("alter table t3 add column c int primary key;", errno.ErrMultiplePriKey)
+	tk.MustExec("admin check table t3;")
+
+	// duplicate values roll back the job
+	tk.MustExec("create table t4 (a int);")
+	tk.MustExec("insert into t4 values (1), (2);")
+	tk.MustGetErrCode("alter table t4 add column b int default 1 unique;", errno.ErrDupEntry)
+	tk.MustQuery("select column_name from information_schema.columns where table_schema = 'test' and table_name = 't4'").Check(testkit.Rows("a"))
+	tk.MustQuery("select count(*) from information_schema.tidb_indexes where table_schema = 'test' and table_name = 't4'").Check(testkit.Rows("0"))
+	tk.MustExec("admin check table t4;")
+}
+
 // TestDropColumn is for inserting value with a to-be-dropped column when do drop column.
 // Column info from schema in build-insert-plan should be public only,
 // otherwise they will not be consisted with Table.Col(), then the server will panic.
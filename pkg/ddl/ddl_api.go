@@ -42,7 +42,6 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta"
 	"github.com/pingcap/tidb/pkg/meta/autoid"
-	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/charset"
 	"github.com/pingcap/tidb/pkg/parser/format"
@@ -628,6 +627,9 @@ func (d *ddl) DropSchema(ctx sessionctx.Context, stmt *ast.DropDatabaseStmt) (er
 	if err != nil {
 		return err
 	}
+	if err = checkDatabaseWithMViews(is, old); err != nil {
+		return err
+	}
 	job := &model.Job{
 		SchemaID:       old.ID,
 		SchemaName:     old.Name.L,
@@ -1013,11 +1015,12 @@ func buildColumnAndConstraint(
 
 // checkColumnDefaultValue checks the default value of the column.
 // In non-strict SQL mode, if the default value of the column is an empty string, the default value can be ignored.
-// In strict SQL mode, TEXT/BLOB/JSON can't have not null default values.
+// In strict SQL mode, TEXT/BLOB/JSON/GEOMETRY/VECTOR can't have not null default values.
 // In NO_ZERO_DATE SQL mode, TIMESTAMP/DATE/DATETIME type can't have zero date like '0000-00-00' or '0000-00-00 00:00:00'.
 func checkColumnDefaultValue(ctx sessionctx.Context, col *table.Column, value any) (bool, any, error) {
 	hasDefaultValue := true
-	if value != nil && (col.GetType() == mysql.TypeJSON ||
+	if value != nil && (col.GetType() == mysql.TypeJSON || col.GetType() == mysql.TypeGeometry ||
+		col.GetType() == mysql.TypeTiDBVectorFloat32 ||
 		col.GetType() == mysql.TypeTinyBlob || col.GetType() == mysql.TypeMediumBlob ||
 		col.GetType() == mysql.TypeLongBlob || col.GetType() == mysql.TypeBlob) {
 		// In non-strict SQL mode.
@@ -1467,8 +1470,8 @@ func getDefaultValue(ctx sessionctx.Context, col *table.Column, option *ast.Colu
 	}
 
 	if v.Kind() == types.KindBinaryLiteral || v.Kind() == types.KindMysqlBit {
-		if types.IsTypeBlob(tp) || tp == mysql.TypeJSON {
-			// BLOB/TEXT/JSON column cannot have a default value.
+		if types.IsTypeBlob(tp) || tp == mysql.TypeJSON || tp == mysql.TypeGeometry || tp == mysql.TypeTiDBVectorFloat32 {
+			// BLOB/TEXT/JSON/GEOMETRY/VECTOR column cannot have a default value.
 			// Skip the unnecessary decode procedure.
 			return v.GetString(), false, err
 		}
@@ -1889,6 +1892,13 @@ func checkColumnAttributes(colName string, tp *types.FieldType) error {
 		if tp.GetDecimal() != types.UnspecifiedFsp && (tp.GetDecimal() < types.MinFsp || tp.GetDecimal() > types.MaxFsp) {
 			return types.ErrTooBigPrecision.GenWithStackByArgs(tp.GetDecimal(), colName, types.MaxFsp)
 		}
+	case mysql.TypeTiDBVectorFloat32:
+		if tp.GetFlen() > types.MaxVectorDimension {
+			return types.ErrTooBigFieldLength.GenWithStackByArgs(colName, types.MaxVectorDimension)
+		}
+		if tp.GetFlen() != types.UnspecifiedLength && tp.GetFlen() < 1 {
+			return types.ErrWrongFieldSpec.GenWithStackByArgs(colName)
+		}
 	}
 	return nil
 }
@@ -2103,6 +2113,20 @@ func BuildTableInfo(
 	}
 	foreignKeyID := tbInfo.MaxForeignKeyID
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintFulltext {
+			supported, err := CheckFullTextIndex(ctx, constr.Keys, constr.Option)
+			if err != nil {
+				return nil, err
+			}
+			if !supported {
+				continue
+			}
+		}
+		if constr.Tp == ast.ConstraintVector {
+			if constr.Keys, constr.Option, err = ResolveVectorIndexParts(constr.Keys, constr.Option); err != nil {
+				return nil, err
+			}
+		}
 		// Build hidden columns if necessary.
 		hiddenCols, err := buildHiddenColumnInfoWithCheck(ctx, constr.Keys, model.NewCIStr(constr.Name), tbInfo, tblColumns)
 		if err != nil {
@@ -2166,8 +2190,7 @@ func BuildTableInfo(
 		}
 
 		if constr.Tp == ast.ConstraintFulltext {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt.FastGenByArgs())
-			continue
+			constr.Option = FullTextIndexOption(constr.Option)
 		}
 
 		var (
@@ -2426,6 +2449,11 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 			if err := checkPartitionFuncType(ctx, s.Partition.Expr, s.Table.Schema.O, tbInfo); err != nil {
 				return errors.Trace(err)
 			}
+			if s.Partition.Sub != nil {
+				if err := checkPartitionFuncType(ctx, s.Partition.Sub.Expr, s.Table.Schema.O, tbInfo); err != nil {
+					return errors.Trace(err)
+				}
+			}
 			if err := checkPartitioningKeysConstraints(ctx, s, tbInfo); err != nil {
 				return errors.Trace(err)
 			}
@@ -2455,15 +2483,23 @@ func checkPartitionDefinitionConstraints(ctx sessionctx.Context, tbInfo *model.T
 		return err
 	}
 
+	// The partition bounds are checked on the partitions, not the subpartitions.
+	topTbInfo := getTopLevelPartitionTableInfo(tbInfo)
 	switch tbInfo.Partition.Type {
 	case model.PartitionTypeRange:
-		err = checkPartitionByRange(ctx, tbInfo)
+		err = checkPartitionByRange(ctx, topTbInfo)
 	case model.PartitionTypeHash, model.PartitionTypeKey:
-		err = checkPartitionByHash(ctx, tbInfo)
+		err = checkPartitionByHash(ctx, topTbInfo)
 	case model.PartitionTypeList:
-		err = checkPartitionByList(ctx, tbInfo)
+		err = checkPartitionByList(ctx, topTbInfo)
 	}
-	return errors.Trace(err)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tbInfo.Partition.Sub != nil {
+		setSubPartitionBounds(tbInfo.Partition, topTbInfo.Partition.Definitions)
+	}
+	return nil
 }
 
 // checkTableInfoValid uses to check table info valid. This is used to validate table info.
@@ -2498,6 +2534,7 @@ func BuildTableInfoWithLike(ctx sessionctx.Context, ident ast.Ident, referTblInf
 	tblInfo.Name = ident.Name
 	tblInfo.AutoIncID = 0
 	tblInfo.ForeignKeys = nil
+	tblInfo.Triggers = nil
 	// Ignore TiFlash replicas for temporary tables.
 	if s.TemporaryKeyword != ast.TemporaryNone {
 		tblInfo.TiFlashReplica = nil
@@ -2635,6 +2672,9 @@ func BuildTableInfoWithStmt(ctx sessionctx.Context, s *ast.CreateTableStmt, dbCh
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err = setIndexScopesForCreateTable(tbInfo, newConstraints); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	return tbInfo, nil
 }
@@ -2707,13 +2747,12 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 }
 
 func setTemporaryType(_ sessionctx.Context, tbInfo *model.TableInfo, s *ast.CreateTableStmt) error {
+	tbInfo.TempTablePreserveRows = false
 	switch s.TemporaryKeyword {
 	case ast.TemporaryGlobal:
 		tbInfo.TempTableType = model.TempTableGlobal
 		// "create global temporary table ... on commit preserve rows"
-		if !s.OnCommitDelete {
-			return errors.Trace(dbterror.ErrUnsupportedOnCommitPreserve)
-		}
+		tbInfo.TempTablePreserveRows = !s.OnCommitDelete
 	case ast.TemporaryLocal:
 		tbInfo.TempTableType = model.TempTableLocal
 	default:
@@ -3277,7 +3316,8 @@ func checkPartitionByList(ctx sessionctx.Context, tbInfo *model.TableInfo) error
 
 func isValidKeyPartitionColType(fieldType types.FieldType) bool {
 	switch fieldType.GetType() {
-	case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeJSON, mysql.TypeGeometry:
+	case mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeJSON, mysql.TypeGeometry,
+		mysql.TypeTiDBVectorFloat32:
 		return false
 	default:
 		return true
@@ -3618,12 +3658,12 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) err
 			tbInfo.PlacementPolicyRef = &model.PolicyRefInfo{
 				Name: model.NewCIStr(op.StrValue),
 			}
-		case ast.TableOptionTTL, ast.TableOptionTTLEnable, ast.TableOptionTTLJobInterval:
+		case ast.TableOptionTTL, ast.TableOptionTTLEnable, ast.TableOptionTTLJobInterval, ast.TableOptionTTLEpochUnit, ast.TableOptionTTLArchive, ast.TableOptionTTLArchiveFormat:
 			if ttlOptionsHandled {
 				continue
 			}
 
-			ttlInfo, ttlEnable, ttlJobInterval, err := getTTLInfoInOptions(options)
+			ttlInfo, ttlEnable, ttlJobInterval, ttlArchive, ttlArchiveFormat, err := getTTLInfoInOptions(options)
 			if err != nil {
 				return err
 			}
@@ -3636,6 +3676,12 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) err
 				if ttlJobInterval != nil {
 					return errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_JOB_INTERVAL"))
 				}
+				if ttlArchive != nil {
+					return errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_ARCHIVE"))
+				}
+				if ttlArchiveFormat != nil {
+					return errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_ARCHIVE_FORMAT"))
+				}
 			}
 
 			tbInfo.TTLInfo = ttlInfo
@@ -3856,6 +3902,11 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 		}
 	}
 
+	if isChangePrimaryKey(tb.Meta(), validSpecs) {
+		pkSpec := validSpecs[1].Constraint
+		return d.changePrimaryKey(sctx, ident, true, pkSpec.Keys, pkSpec.Option)
+	}
+
 	if len(validSpecs) > 1 {
 		sctx.GetSessionVars().StmtCtx.MultiSchemaInfo = model.NewMultiSchemaInfo()
 	}
@@ -3933,7 +3984,11 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 			case ast.ConstraintPrimaryKey:
 				err = d.CreatePrimaryKey(sctx, ident, model.NewCIStr(constr.Name), spec.Constraint.Keys, constr.Option)
 			case ast.ConstraintFulltext:
-				sctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt)
+				err = d.createIndex(sctx, ident, ast.IndexKeyTypeFullText, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
+			case ast.ConstraintVector:
+				err = d.createIndex(sctx, ident, ast.IndexKeyTypeVector, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
 			case ast.ConstraintCheck:
 				if !variable.EnableCheckConstraint.Load() {
 					sctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackError("the switch of check constraint is off"))
@@ -4002,19 +4057,21 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 					}
 				case ast.TableOptionEngine:
 				case ast.TableOptionRowFormat:
-				case ast.TableOptionTTL, ast.TableOptionTTLEnable, ast.TableOptionTTLJobInterval:
+				case ast.TableOptionTTL, ast.TableOptionTTLEnable, ast.TableOptionTTLJobInterval, ast.TableOptionTTLEpochUnit, ast.TableOptionTTLArchive, ast.TableOptionTTLArchiveFormat:
 					var ttlInfo *model.TTLInfo
 					var ttlEnable *bool
 					var ttlJobInterval *string
+					var ttlArchive *string
+					var ttlArchiveFormat *string
 
 					if ttlOptionsHandled {
 						continue
 					}
-					ttlInfo, ttlEnable, ttlJobInterval, err = getTTLInfoInOptions(spec.Options)
+					ttlInfo, ttlEnable, ttlJobInterval, ttlArchive, ttlArchiveFormat, err = getTTLInfoInOptions(spec.Options)
 					if err != nil {
 						return err
 					}
-					err = d.AlterTableTTLInfoOrEnable(sctx, ident, ttlInfo, ttlEnable, ttlJobInterval)
+					err = d.AlterTableTTLInfoOrEnable(sctx, ident, ttlInfo, ttlEnable, ttlJobInterval, ttlArchive, ttlArchiveFormat)
 
 					ttlOptionsHandled = true
 				default:
@@ -4216,12 +4273,6 @@ func (d *ddl) getSchemaAndTableByIdent(ctx sessionctx.Context, tableIdent ast.Id
 func checkUnsupportedColumnConstraint(col *ast.ColumnDef, ti ast.Ident) error {
 	for _, constraint := range col.Options {
 		switch constraint.Tp {
-		case ast.ColumnOptionAutoIncrement:
-			return dbterror.ErrUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint AUTO_INCREMENT when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
-		case ast.ColumnOptionPrimaryKey:
-			return dbterror.ErrUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint PRIMARY KEY when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
-		case ast.ColumnOptionUniqKey:
-			return dbterror.ErrUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint UNIQUE KEY when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
 		case ast.ColumnOptionAutoRandom:
 			errMsg := fmt.Sprintf(autoid.AutoRandomAlterAddColumn, col.Name, ti.Schema, ti.Name)
 			return dbterror.ErrInvalidAutoRandom.GenWithStackByArgs(errMsg)
@@ -4269,10 +4320,6 @@ func CreateNewColumn(ctx sessionctx.Context, schema *model.DBInfo, spec *ast.Alt
 				return nil, errors.Trace(err)
 			}
 
-			if option.Stored {
-				return nil, dbterror.ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE")
-			}
-
 			_, dependColNames, err := findDependedColumnNames(schema.Name, t.Meta().Name, specNewColumn)
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -4345,6 +4392,84 @@ func CreateNewColumn(ctx sessionctx.Context, schema *model.DBInfo, spec *ast.Alt
 	return col, err
 }
 
+// BuildAddColumnIndexInfos builds the indexes defined by the PRIMARY KEY and UNIQUE options of the adding column.
+// The indexes are added in the same job as the column. Their IDs and column offsets are assigned when the job runs.
+func BuildAddColumnIndexInfos(ctx sessionctx.Context, t table.Table, col *table.Column, specNewColumn *ast.ColumnDef) ([]*model.IndexInfo, error) {
+	tblInfo := t.Meta()
+	allCols := append(slices.Clone(tblInfo.Columns), col.ColumnInfo)
+	idxParts := []*ast.IndexPartSpecification{{Column: &ast.ColumnName{Name: col.Name}, Length: types.UnspecifiedLength}}
+	var idxInfos []*model.IndexInfo
+	var hasPrimary, hasUnique bool
+	for _, option := range specNewColumn.Options {
+		var (
+			idxInfo *model.IndexInfo
+			err     error
+		)
+		switch option.Tp {
+		case ast.ColumnOptionPrimaryKey:
+			if hasPrimary {
+				continue
+			}
+			hasPrimary = true
+			if option.PrimaryKeyTp == model.PrimaryKeyTypeClustered {
+				return nil, dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Adding clustered primary key is not supported. " +
+					"Please consider adding NONCLUSTERED primary key instead")
+			}
+			if tblInfo.PKIsHandle || tblInfo.IsCommonHandle || tables.FindPrimaryIndex(tblInfo) != nil {
+				return nil, infoschema.ErrMultiplePriKey
+			}
+			if col.IsGenerated() && !col.GeneratedStored {
+				return nil, dbterror.ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Defining a virtual generated column as primary key")
+			}
+			idxInfo, err = BuildIndexInfo(ctx, allCols, model.NewCIStr(mysql.PrimaryKeyName), true, true, false, idxParts, nil, model.StateNone)
+		case ast.ColumnOptionUniqKey:
+			if hasUnique {
+				continue
+			}
+			hasUnique = true
+			idxInfo, err = BuildIndexInfo(ctx, allCols, GetName4AnonymousIndex(t, col.Name, model.CIStr{}), false, true, false, idxParts, nil, model.StateNone)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		idxInfos = append(idxInfos, idxInfo)
+	}
+	if len(idxInfos) > 0 {
+		if err := checkTooManyIndexes(append(slices.Clone(tblInfo.Indices), idxInfos...)); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return idxInfos, nil
+}
+
+// checkAddColumnWithReorg checks whether the column can be added with an online reorganization, which fills the
+// auto-increment or stored generated values and builds the indexes of the column.
+func checkAddColumnWithReorg(ctx sessionctx.Context, ti ast.Ident, tblInfo *model.TableInfo, col *table.Column) error {
+	unsupported := func(reason string) error {
+		return dbterror.ErrUnsupportedAddColumn.GenWithStack("unsupported add column '%s' %s when altering '%s.%s'", col.Name, reason, ti.Schema, ti.Name)
+	}
+	if ctx.GetSessionVars().StmtCtx.MultiSchemaInfo != nil {
+		return unsupported("which needs reorganization in multi-schema change")
+	}
+	if tblInfo.GetPartitionInfo() != nil {
+		return unsupported("which needs reorganization on partitioned table")
+	}
+	if tblInfo.TempTableType != model.TempTableNone {
+		return unsupported("which needs reorganization on temporary table")
+	}
+	if mysql.HasAutoIncrementFlag(col.GetFlag()) {
+		if tblInfo.GetAutoIncrementColInfo() != nil || tblInfo.ContainsAutoRandomBits() {
+			return autoid.ErrWrongAutoKey.GenWithStackByArgs()
+		}
+		if tblInfo.SepAutoInc() {
+			return unsupported("constraint AUTO_INCREMENT with AUTO_ID_CACHE 1")
+		}
+	}
+	return nil
+}
+
 // AddColumn will add a new column to the table.
 func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
 	specNewColumn := spec.NewColumns[0]
@@ -4368,6 +4493,17 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 	if err != nil {
 		return errors.Trace(err)
 	}
+	idxInfos, err := BuildAddColumnIndexInfos(ctx, t, col, specNewColumn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var reorgMeta *model.DDLReorgMeta
+	if needReorgForAddColumn(col.ColumnInfo, idxInfos) {
+		if err = checkAddColumnWithReorg(ctx, ti, tbInfo, col); err != nil {
+			return errors.Trace(err)
+		}
+		reorgMeta = NewDDLReorgMeta(ctx)
+	}
 
 	txn, err := ctx.Txn(true)
 	if err != nil {
@@ -4388,7 +4524,9 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 		TableName:      tbInfo.Name.L,
 		Type:           model.ActionAddColumn,
 		BinlogInfo:     &model.HistoryInfo{},
-		Args:           []any{col, spec.Position, 0, spec.IfNotExists},
+		ReorgMeta:      reorgMeta,
+		Args:           []any{col, spec.Position, 0, spec.IfNotExists, idxInfos},
+		Priority:       ctx.GetSessionVars().DDLReorgPriority,
 		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
 		SQLMode:        ctx.GetSessionVars().SQLMode,
 	}
@@ -4587,7 +4725,13 @@ func (d *ddl) AlterTablePartitioning(ctx sessionctx.Context, ident ast.Ident, sp
 	}
 
 	meta := t.Meta().Clone()
+	if hasGlobalIndex(meta) {
+		return dbterror.ErrCancelledDDLJob.GenWithStack("global index is not supported yet for alter table partitioning")
+	}
 	piOld := meta.GetPartitionInfo()
+	if (piOld != nil && piOld.Sub != nil) || (spec.Partition != nil && spec.Partition.Sub != nil) {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("ALTER TABLE PARTITION BY of subpartitioned table")
+	}
 	var partNames []string
 	if piOld != nil {
 		partNames = make([]string, 0, len(piOld.Definitions))
@@ -4701,6 +4845,9 @@ func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec
 	default:
 		return errors.Trace(dbterror.ErrUnsupportedReorganizePartition)
 	}
+	if pi.Sub != nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("REORGANIZE PARTITION of subpartitioned table")
+	}
 	partNames := make([]string, 0, len(spec.PartitionNames))
 	for _, name := range spec.PartitionNames {
 		partNames = append(partNames, name.L)
@@ -4757,6 +4904,12 @@ func (d *ddl) RemovePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *
 	if pi == nil {
 		return dbterror.ErrPartitionMgmtOnNonpartitioned
 	}
+	if hasGlobalIndex(meta) {
+		return dbterror.ErrCancelledDDLJob.GenWithStack("global index is not supported yet for remove partitioning")
+	}
+	if pi.Sub != nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("REMOVE PARTITIONING of subpartitioned table")
+	}
 	// TODO: Optimize for remove partitioning with a single partition
 	// TODO: Add the support for this in onReorganizePartition
 	// skip if only one partition
@@ -4970,7 +5123,7 @@ func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 		// MySQL allows duplicate partition names in truncate partition
 		// so we filter them out through a hash
 		posMap := make(map[int]bool)
-		for _, name := range spec.PartitionNames {
+		for _, name := range pi.ExpandPartitionNames(spec.PartitionNames) {
 			pos := pi.FindPartitionDefinitionByName(name.L)
 			if pos < 0 {
 				return nil, errors.Trace(table.ErrUnknownPartition.GenWithStackByArgs(name.L, ident.Name.O))
@@ -5082,11 +5235,10 @@ func (d *ddl) DropTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *
 			ctx.SetValue(sessionctx.QueryString, newQuery)
 		}
 	}
-	partNames := make([]string, len(spec.PartitionNames))
-	for i, partCIName := range spec.PartitionNames {
-		partNames[i] = partCIName.L
+	partNames, err := getDroppedPartitionNames(meta.Partition, spec.PartitionNames)
+	if err == nil {
+		err = CheckDropTablePartition(meta, partNames)
 	}
-	err = CheckDropTablePartition(meta, partNames)
 	if err != nil {
 		if dbterror.ErrDropPartitionNonExistent.Equal(err) && spec.IfExists {
 			ctx.GetSessionVars().StmtCtx.AppendNote(err)
@@ -5203,12 +5355,22 @@ func checkTableDefCompatible(source *model.TableInfo, target *model.TableInfo) e
 			return dbterror.ErrPartitionExchangeDifferentOption.GenWithStackByArgs(fmt.Sprintf("column: %s", sourceCol.Name))
 		}
 	}
-	if len(source.Indices) != len(target.Indices) {
+	// Global indexes of the partitioned table have no counterpart in the
+	// non-partitioned table, they are rebuilt during the exchange.
+	localIndexCnt := 0
+	for _, sourceIdx := range source.Indices {
+		if !sourceIdx.Global {
+			localIndexCnt++
+		} else if target.FindIndexByName(sourceIdx.Name.L) != nil {
+			return dbterror.ErrPartitionExchangeDifferentOption.GenWithStackByArgs(fmt.Sprintf("global index: %s", sourceIdx.Name))
+		}
+	}
+	if localIndexCnt != len(target.Indices) {
 		return errors.Trace(dbterror.ErrTablesDifferentMetadata)
 	}
 	for _, sourceIdx := range source.Indices {
 		if sourceIdx.Global {
-			return dbterror.ErrPartitionExchangeDifferentOption.GenWithStackByArgs(fmt.Sprintf("global index: %s", sourceIdx.Name))
+			continue
 		}
 		var compatIdx *model.IndexInfo
 		for _, targetIdx := range target.Indices {
@@ -5293,7 +5455,14 @@ func (d *ddl) ExchangeTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 
 	partName := spec.PartitionNames[0].L
 
-	// NOTE: if pt is subPartitioned, it should be checked
+	if pi := ptMeta.Partition; pi.Sub != nil {
+		// A partition can only be exchanged if it has a single subpartition.
+		subNames := pi.ExpandPartitionNames(spec.PartitionNames[:1])
+		if len(subNames) > 1 {
+			return errors.Trace(dbterror.ErrPartitionInsteadOfSubpartition)
+		}
+		partName = subNames[0].L
+	}
 
 	defID, err := tables.FindPartitionByName(ptMeta, partName)
 	if err != nil {
@@ -5315,6 +5484,7 @@ func (d *ddl) ExchangeTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 		Args:           []any{defID, ptSchema.ID, ptMeta.ID, partName, spec.WithValidation},
 		CtxVars:        []any{[]int64{ntSchema.ID, ptSchema.ID}, []int64{ntMeta.ID, ptMeta.ID}},
 		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
+		ReorgMeta:      NewDDLReorgMeta(ctx),
 		InvolvingSchemaInfo: []model.InvolvingSchemaInfo{
 			{Database: ptSchema.Name.L, Table: ptMeta.Name.L},
 			{Database: ntSchema.Name.L, Table: ntMeta.Name.L},
@@ -5399,6 +5569,9 @@ func checkIsDroppableColumn(ctx sessionctx.Context, is infoschema.InfoSchema, sc
 	if err != nil {
 		return false, errors.Trace(err)
 	}
+	if err = checkColumnWithMViewLogs(is, tblInfo, colName); err != nil {
+		return false, errors.Trace(err)
+	}
 	// We don't support dropping column with PK handle covered now.
 	if col.IsPKHandleColumn(tblInfo) {
 		return false, dbterror.ErrUnsupportedPKHandle
@@ -5754,6 +5927,9 @@ func GetModifiableColumnJob(
 	if newColName.L == model.ExtraHandleName.L {
 		return nil, dbterror.ErrWrongColumnName.GenWithStackByArgs(newColName.L)
 	}
+	if err = checkColumnWithMViewLogs(is, t.Meta(), originalColName); err != nil {
+		return nil, errors.Trace(err)
+	}
 	errG := checkModifyColumnWithGeneratedColumnsConstraint(t.Cols(), originalColName)
 
 	// If we want to rename the column name, we need to check whether it already exists.
@@ -5826,102 +6002,20 @@ func GetModifiableColumnJob(
 		}
 		return nil, errors.Trace(err)
 	}
-	needChangeColData := needChangeColumnData(col.ColumnInfo, newCol.ColumnInfo)
+	needChangeColData := needReorgColumnData(t.Meta(), col.ColumnInfo, newCol.ColumnInfo)
 	if needChangeColData {
 		if err = isGeneratedRelatedColumn(t.Meta(), newCol.ColumnInfo, col.ColumnInfo); err != nil {
 			return nil, errors.Trace(err)
 		}
-		if t.Meta().Partition != nil {
-			return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("table is partition table")
+		if err = checkModifyColumnWithGlobalIndex(t.Meta(), col.ColumnInfo); err != nil {
+			return nil, errors.Trace(err)
 		}
 	}
 
 	// Check that the column change does not affect the partitioning column
-	// It must keep the same type, int [unsigned], [var]char, date[time]
 	if t.Meta().Partition != nil {
-		pt, ok := t.(table.PartitionedTable)
-		if !ok {
-			// Should never happen!
-			return nil, dbterror.ErrNotAllowedTypeInPartition.GenWithStackByArgs(newCol.Name.O)
-		}
-		isPartitioningColumn := false
-		for _, name := range pt.GetPartitionColumnNames() {
-			if strings.EqualFold(name.L, col.Name.L) {
-				isPartitioningColumn = true
-				break
-			}
-		}
-		if isPartitioningColumn {
-			// TODO: update the partitioning columns with new names if column is renamed
-			// Would be an extension from MySQL which does not support it.
-			if col.Name.L != newCol.Name.L {
-				return nil, dbterror.ErrDependentByPartitionFunctional.GenWithStackByArgs(col.Name.L)
-			}
-			if !isColTypeAllowedAsPartitioningCol(t.Meta().Partition.Type, newCol.FieldType) {
-				return nil, dbterror.ErrNotAllowedTypeInPartition.GenWithStackByArgs(newCol.Name.O)
-			}
-			pi := pt.Meta().GetPartitionInfo()
-			if len(pi.Columns) == 0 {
-				// non COLUMNS partitioning, only checks INTs, not their actual range
-				// There are many edge cases, like when truncating SQL Mode is allowed
-				// which will change the partitioning expression value resulting in a
-				// different partition. Better be safe and not allow decreasing of length.
-				// TODO: Should we allow it in strict mode? Wait for a use case / request.
-				if newCol.FieldType.GetFlen() < col.FieldType.GetFlen() {
-					return nil, dbterror.ErrUnsupportedModifyCollation.GenWithStack("Unsupported modify column, decreasing length of int may result in truncation and change of partition")
-				}
-			}
-			// Basically only allow changes of the length/decimals for the column
-			// Note that enum is not allowed, so elems are not checked
-			// TODO: support partition by ENUM
-			if newCol.FieldType.EvalType() != col.FieldType.EvalType() ||
-				newCol.FieldType.GetFlag() != col.FieldType.GetFlag() ||
-				newCol.FieldType.GetCollate() != col.FieldType.GetCollate() ||
-				newCol.FieldType.GetCharset() != col.FieldType.GetCharset() {
-				return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't change the partitioning column, since it would require reorganize all partitions")
-			}
-			// Generate a new PartitionInfo and validate it together with the new column definition
-			// Checks if all partition definition values are compatible.
-			// Similar to what buildRangePartitionDefinitions would do in terms of checks.
-
-			tblInfo := pt.Meta()
-			newTblInfo := *tblInfo
-			// Replace col with newCol and see if we can generate a new SHOW CREATE TABLE
-			// and reparse it and build new partition definitions (which will do additional
-			// checks columns vs partition definition values
-			newCols := make([]*model.ColumnInfo, 0, len(newTblInfo.Columns))
-			for _, c := range newTblInfo.Columns {
-				if c.ID == col.ID {
-					newCols = append(newCols, newCol.ColumnInfo)
-					continue
-				}
-				newCols = append(newCols, c)
-			}
-			newTblInfo.Columns = newCols
-
-			var buf bytes.Buffer
-			AppendPartitionInfo(tblInfo.GetPartitionInfo(), &buf, mysql.ModeNone)
-			// The parser supports ALTER TABLE ... PARTITION BY ... even if the ddl code does not yet :)
-			// Ignoring warnings
-			stmt, _, err := parser.New().ParseSQL("ALTER TABLE t " + buf.String())
-			if err != nil {
-				// Should never happen!
-				return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStack("cannot parse generated PartitionInfo")
-			}
-			at, ok := stmt[0].(*ast.AlterTableStmt)
-			if !ok || len(at.Specs) != 1 || at.Specs[0].Partition == nil {
-				return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStack("cannot parse generated PartitionInfo")
-			}
-			pAst := at.Specs[0].Partition
-			sv := sctx.GetSessionVars().StmtCtx
-			oldTypeFlags := sv.TypeFlags()
-			newTypeFlags := oldTypeFlags.WithTruncateAsWarning(false).WithIgnoreTruncateErr(false)
-			sv.SetTypeFlags(newTypeFlags)
-			_, err = buildPartitionDefinitionsInfo(sctx.GetExprCtx(), pAst.Definitions, &newTblInfo, uint64(len(newTblInfo.Partition.Definitions)))
-			sv.SetTypeFlags(oldTypeFlags)
-			if err != nil {
-				return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStack("New column does not match partition definitions: %s", err.Error())
-			}
+		if _, err = checkModifyPartitioningColumn(sctx, t, col.ColumnInfo, newCol.ColumnInfo); err != nil {
+			return nil, errors.Trace(err)
 		}
 	}
 
@@ -5962,11 +6056,8 @@ func GetModifiableColumnJob(
 		return nil, dbterror.ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs(errG.Error())
 	}
 
-	if t.Meta().TTLInfo != nil {
-		// the column referenced by TTL should be a time type
-		if t.Meta().TTLInfo.ColumnName.L == originalColName.L && !types.IsTypeTime(newCol.ColumnInfo.FieldType.GetType()) {
-			return nil, errors.Trace(dbterror.ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(newCol.ColumnInfo.Name.O))
-		}
+	if err = checkModifyColumnWithTTLConfig(sctx, t.Meta(), originalColName, newCol.ColumnInfo); err != nil {
+		return nil, errors.Trace(err)
 	}
 
 	var newAutoRandBits uint64
@@ -6248,6 +6339,10 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 	if err != nil {
 		return errors.Trace(err)
 	}
+	err = checkColumnWithMViewLogs(d.GetInfoSchemaWithInterceptor(ctx), tbl.Meta(), oldColName)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	newCol := oldCol.Clone()
 	newCol.Name = newColName
@@ -6552,8 +6647,12 @@ func (d *ddl) AlterTableSetTiFlashReplica(ctx sessionctx.Context, ident ast.Iden
 // `.Enable`. If the `.TTLInfo` in the table info is empty, this function will return an error.
 // When `ttlInfo` is nil, and `ttlCronJobSchedule` is not, it will use the original `.TTLInfo` in the table info and modify the
 // `.JobInterval`. If the `.TTLInfo` in the table info is empty, this function will return an error.
+// When `ttlInfo` is nil, and `ttlArchive` is not, it will use the original `.TTLInfo` in the table info and modify the
+// `.Archive`. If the `.TTLInfo` in the table info is empty, this function will return an error.
+// When `ttlInfo` is nil, and `ttlArchiveFormat` is not, it will use the original `.TTLInfo` in the table info and modify the
+// `.ArchiveFormat`. If the `.TTLInfo` in the table info is empty, this function will return an error.
 // When `ttlInfo` is not nil, it simply submits the job with the `ttlInfo` and ignore the `ttlEnable`.
-func (d *ddl) AlterTableTTLInfoOrEnable(ctx sessionctx.Context, ident ast.Ident, ttlInfo *model.TTLInfo, ttlEnable *bool, ttlCronJobSchedule *string) error {
+func (d *ddl) AlterTableTTLInfoOrEnable(ctx sessionctx.Context, ident ast.Ident, ttlInfo *model.TTLInfo, ttlEnable *bool, ttlCronJobSchedule *string, ttlArchive *string, ttlArchiveFormat *string) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ident.Schema)
 	if !ok {
@@ -6584,6 +6683,12 @@ func (d *ddl) AlterTableTTLInfoOrEnable(ctx sessionctx.Context, ident ast.Ident,
 			if ttlCronJobSchedule != nil {
 				return errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_JOB_INTERVAL"))
 			}
+			if ttlArchive != nil {
+				return errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_ARCHIVE"))
+			}
+			if ttlArchiveFormat != nil {
+				return errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_ARCHIVE_FORMAT"))
+			}
 		}
 	}
 
@@ -6594,7 +6699,7 @@ func (d *ddl) AlterTableTTLInfoOrEnable(ctx sessionctx.Context, ident ast.Ident,
 		TableName:      tableName,
 		Type:           model.ActionAlterTTLInfo,
 		BinlogInfo:     &model.HistoryInfo{},
-		Args:           []any{ttlInfo, ttlEnable, ttlCronJobSchedule},
+		Args:           []any{ttlInfo, ttlEnable, ttlCronJobSchedule, ttlArchive, ttlArchiveFormat},
 		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
 		SQLMode:        ctx.GetSessionVars().SQLMode,
 	}
@@ -6676,18 +6781,11 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
 		return err
 	}
 	tblInfo := tbl.Meta()
-	if tblInfo.GetPartitionInfo() != nil {
-		return errors.New("Extended statistics on partitioned tables are not supported now")
-	}
 	colIDs := make([]int64, 0, 2)
 	colIDSet := make(map[int64]struct{}, 2)
 	// Check whether columns exist.
@@ -6709,10 +6807,9 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
-	// TODO: check whether covering index exists for cardinality / dependency types.
 
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
@@ -6731,7 +6828,18 @@ func (d *ddl) AlterTableDropStatistics(ctx sessionctx.Context, ident ast.Ident,
 	tblInfo := tbl.Meta()
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
-	return d.ddlCtx.statsHandle.MarkExtendedStatsDeleted(stats.StatsName, tblInfo.ID, ifExists)
+	if err := d.ddlCtx.statsHandle.MarkExtendedStatsDeleted(stats.StatsName, tblInfo.ID, ifExists); err != nil {
+		return err
+	}
+	// The partition-level stats are collected under the same name, drop them as well.
+	if pi := tblInfo.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			if err := d.ddlCtx.statsHandle.MarkExtendedStatsDeleted(stats.StatsName, def.ID, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // UpdateTableReplicaInfo updates the table flash replica infos.
@@ -6991,6 +7099,9 @@ func (d *ddl) dropTableObject(
 			if tableInfo.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 				return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Drop Table")
 			}
+			if err = checkTableWithMViews(is, schema.Name, tableInfo.Meta()); err != nil {
+				return err
+			}
 		case viewObject:
 			if !tableInfo.Meta().IsView() {
 				return dbterror.ErrWrongObject.GenWithStackByArgs(fullti.Schema, fullti.Name, "VIEW")
@@ -7072,6 +7183,9 @@ func (d *ddl) TruncateTable(ctx sessionctx.Context, ti ast.Ident) error {
 	if tb.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 		return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Truncate Table")
 	}
+	if err = checkTableWithMViews(d.GetInfoSchemaWithInterceptor(ctx), schema.Name, tb.Meta()); err != nil {
+		return err
+	}
 	fkCheck := ctx.GetSessionVars().ForeignKeyChecks
 	referredFK := checkTableHasForeignKeyReferred(d.GetInfoSchemaWithInterceptor(ctx), ti.Schema.L, ti.Name.L, []ast.Ident{{Name: ti.Name, Schema: ti.Schema}}, fkCheck)
 	if referredFK != nil {
@@ -7354,8 +7468,7 @@ func GetName4AnonymousIndex(t table.Table, colName model.CIStr, idxName model.CI
 func (d *ddl) CreatePrimaryKey(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr,
 	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) error {
 	if indexOption != nil && indexOption.PrimaryKeyTp == model.PrimaryKeyTypeClustered {
-		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Adding clustered primary key is not supported. " +
-			"Please consider adding NONCLUSTERED primary key instead")
+		return d.changePrimaryKey(ctx, ti, false, indexPartSpecifications, indexOption)
 	}
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
 	if err != nil {
@@ -7402,13 +7515,12 @@ func (d *ddl) CreatePrimaryKey(ctx sessionctx.Context, ti ast.Ident, indexName m
 		if err != nil {
 			return err
 		}
-		if !ck {
-			if !ctx.GetSessionVars().EnableGlobalIndex {
-				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY")
-			}
-			// index columns does not contain all partition columns, must set global
-			global = true
+		global, err = decideIndexGlobal(ctx, getIndexScope(indexOption), true, ck, "PRIMARY")
+		if err != nil {
+			return err
 		}
+	} else if getIndexScope(indexOption) == ast.IndexScopeGlobal {
+		return dbterror.ErrGlobalIndexOnNonPartitionedTable
 	}
 
 	// May be truncate comment here, when index comment too long and sql_mode is't strict.
@@ -7444,6 +7556,109 @@ func (d *ddl) CreatePrimaryKey(ctx sessionctx.Context, ti ast.Ident, indexName m
 	return errors.Trace(err)
 }
 
+// isChangePrimaryKey checks whether the specs drop the primary key and add a new one, which needs to
+// rewrite the rows of the table if the old or the new primary key is clustered.
+func isChangePrimaryKey(tblInfo *model.TableInfo, specs []*ast.AlterTableSpec) bool {
+	if len(specs) != 2 || specs[0].Tp != ast.AlterTableDropPrimaryKey || specs[1].Tp != ast.AlterTableAddConstraint ||
+		specs[1].Constraint.Tp != ast.ConstraintPrimaryKey {
+		return false
+	}
+	if tblInfo.PKIsHandle || tblInfo.IsCommonHandle {
+		return true
+	}
+	opt := specs[1].Constraint.Option
+	return opt != nil && opt.PrimaryKeyTp == model.PrimaryKeyTypeClustered
+}
+
+// changePrimaryKey changes the primary key of the table, or adds one if dropOld is false, by copying
+// the rows into a new table. It's used when the old or the new primary key is clustered.
+func (d *ddl) changePrimaryKey(ctx sessionctx.Context, ti ast.Ident, dropOld bool,
+	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) error {
+	is := d.infoCache.GetLatest()
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+	oldPKInfo := tables.FindPrimaryIndex(tblInfo)
+	hasPK := tblInfo.PKIsHandle || oldPKInfo != nil
+	if !dropOld && hasPK {
+		return infoschema.ErrMultiplePriKey
+	}
+	if dropOld && !hasPK {
+		return dbterror.ErrCantDropFieldOrKey.GenWithStack("index %s doesn't exist", mysql.PrimaryKeyName)
+	}
+	clustered := indexOption != nil && indexOption.PrimaryKeyTp == model.PrimaryKeyTypeClustered
+	switch {
+	case tblInfo.GetPartitionInfo() != nil:
+		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Changing the clustered primary key of a partitioned table is not supported")
+	case tblInfo.TempTableType != model.TempTableNone:
+		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Changing the clustered primary key of a temporary table is not supported")
+	case tblInfo.TableCacheStatusType != model.TableCacheStatusDisable:
+		return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Alter Primary Key")
+	case tblInfo.TiFlashReplica != nil:
+		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Changing the clustered primary key of a table with TiFlash replicas is not supported")
+	case tblInfo.ContainsAutoRandomBits():
+		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Changing the clustered primary key of a table with AUTO_RANDOM column is not supported")
+	case tblInfo.PlacementPolicyRef != nil:
+		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Changing the clustered primary key of a table with placement policy is not supported")
+	case tblInfo.MaterializedView != nil || len(tblInfo.MViewLogs) > 0:
+		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Changing the clustered primary key of a table related to materialized views is not supported")
+	case clustered && tblInfo.ShardRowIDBits > 0:
+		return dbterror.ErrUnsupportedModifyPrimaryKey.GenWithStack("Adding clustered primary key to a table with SHARD_ROW_ID_BITS is not supported")
+	}
+
+	// Primary keys cannot include expression index parts. A primary key requires the generated column to be stored,
+	// but expression index parts are implemented as virtual generated columns, not stored generated columns.
+	for _, idxPart := range indexPartSpecifications {
+		if idxPart.Expr != nil {
+			return dbterror.ErrFunctionalIndexPrimaryKey
+		}
+	}
+	if _, _, err = buildIndexColumns(ctx, tblInfo.Columns, indexPartSpecifications); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err = CheckPKOnGeneratedColumn(tblInfo, indexPartSpecifications); err != nil {
+		return err
+	}
+	if dropOld && oldPKInfo != nil {
+		if err = checkIndexNeededInForeignKey(is, schema.Name.L, tblInfo, oldPKInfo); err != nil {
+			return err
+		}
+	}
+	if indexOption != nil {
+		if _, err = validateCommentLength(ctx.GetSessionVars(), mysql.PrimaryKeyName, &indexOption.Comment, dbterror.ErrTooLongIndexComment); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	genIDs, err := d.genGlobalIDs(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	job := &model.Job{
+		SchemaID:       schema.ID,
+		TableID:        tblInfo.ID,
+		SchemaName:     schema.Name.L,
+		TableName:      tblInfo.Name.L,
+		Type:           model.ActionAlterPrimaryKey,
+		BinlogInfo:     &model.HistoryInfo{},
+		Args:           []any{genIDs[0], indexPartSpecifications, indexOption, ctx.GetSessionVars().SQLMode, dropOld},
+		Priority:       ctx.GetSessionVars().DDLReorgPriority,
+		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:        ctx.GetSessionVars().SQLMode,
+	}
+	reorgMeta, err := newReorgMetaFromVariables(job, ctx)
+	if err != nil {
+		return err
+	}
+	job.ReorgMeta = reorgMeta
+
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
 func precheckBuildHiddenColumnInfo(
 	indexPartSpecifications []*ast.IndexPartSpecification,
 	indexName model.CIStr,
@@ -7582,9 +7797,22 @@ func (*ddl) addHypoIndexIntoCtx(ctx sessionctx.Context, schemaName, tableName mo
 
 func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
-	// not support Spatial and FullText index
-	if keyType == ast.IndexKeyTypeFullText || keyType == ast.IndexKeyTypeSpatial {
-		return dbterror.ErrUnsupportedIndexType.GenWithStack("FULLTEXT and SPATIAL index is not supported")
+	// not support Spatial index
+	if keyType == ast.IndexKeyTypeSpatial {
+		return dbterror.ErrUnsupportedIndexType.GenWithStack("SPATIAL index is not supported")
+	}
+	if keyType == ast.IndexKeyTypeFullText {
+		supported, err := CheckFullTextIndex(ctx, indexPartSpecifications, indexOption)
+		if err != nil || !supported {
+			return err
+		}
+		indexOption = FullTextIndexOption(indexOption)
+	}
+	if keyType == ast.IndexKeyTypeVector {
+		var err error
+		if indexPartSpecifications, indexOption, err = ResolveVectorIndexParts(indexPartSpecifications, indexOption); err != nil {
+			return err
+		}
 	}
 	unique := keyType == ast.IndexKeyTypeUnique
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
@@ -7644,24 +7872,49 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	// After DDL job is put to the queue, and if the check fail, TiDB will run the DDL cancel logic.
 	// The recover step causes DDL wait a few seconds, makes the unit test painfully slow.
 	// For same reason, decide whether index is global here.
-	indexColumns, _, err := buildIndexColumns(ctx, finalColumns, indexPartSpecifications)
+	var (
+		indexColumns []*model.IndexColumn
+		mvIndex      bool
+	)
+	if keyType == ast.IndexKeyTypeFullText {
+		indexColumns, err = buildFullTextIndexColumns(finalColumns, indexPartSpecifications)
+		if err == nil {
+			_, err = buildFullTextIndexInfo(indexOption)
+		}
+	} else if keyType == ast.IndexKeyTypeVector {
+		indexColumns, _, err = buildVectorIndexColumns(finalColumns, indexPartSpecifications, indexOption)
+	} else {
+		indexColumns, mvIndex, err = buildIndexColumns(ctx, finalColumns, indexPartSpecifications)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
 
 	global := false
-	if unique && tblInfo.GetPartitionInfo() != nil {
-		ck, err := checkPartitionKeysConstraint(tblInfo.GetPartitionInfo(), indexColumns, tblInfo)
+	if tblInfo.GetPartitionInfo() != nil {
+		ck := true
+		if unique {
+			ck, err = checkPartitionKeysConstraint(tblInfo.GetPartitionInfo(), indexColumns, tblInfo)
+			if err != nil {
+				return err
+			}
+		}
+		global, err = decideIndexGlobal(ctx, getIndexScope(indexOption), unique, ck, "UNIQUE INDEX")
 		if err != nil {
 			return err
 		}
-		if !ck {
-			if !ctx.GetSessionVars().EnableGlobalIndex {
-				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+		if global {
+			switch {
+			case keyType == ast.IndexKeyTypeFullText:
+				return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("FULLTEXT index")
+			case keyType == ast.IndexKeyTypeVector:
+				return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("VECTOR index")
+			case mvIndex:
+				return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("multi-valued index")
 			}
-			// index columns does not contain all partition columns, must set global
-			global = true
 		}
+	} else if getIndexScope(indexOption) == ast.IndexScopeGlobal {
+		return dbterror.ErrGlobalIndexOnNonPartitionedTable
 	}
 	// May be truncate comment here, when index comment too long and sql_mode is't strict.
 	if indexOption != nil {
@@ -7708,6 +7961,12 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 		return nil
 	}
 	err = d.callHookOnChanged(job, err)
+	if err == nil && keyType == ast.IndexKeyTypeVector && ctx.GetSessionVars().StmtCtx.MultiSchemaInfo == nil {
+		// The index is added untrained, train it now. If it fails, the index is trained by ANALYZE TABLE later.
+		if err := d.TrainVectorIndex(ctx, schema.Name, t.Meta().Name, indexName); err != nil {
+			ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		}
+	}
 	return errors.Trace(err)
 }
 
@@ -8151,6 +8410,7 @@ func BuildAddedPartitionInfo(ctx expression.BuildContext, meta *model.TableInfo,
 		Expr:    meta.Partition.Expr,
 		Columns: meta.Partition.Columns,
 		Enable:  meta.Partition.Enable,
+		Sub:     meta.Partition.Sub,
 	}
 
 	defs, err := buildPartitionDefinitionsInfo(ctx, spec.PartDefinitions, meta, numParts)
@@ -671,8 +671,12 @@ func JobNeedGC(job *model.Job) bool {
 			model.ActionDropColumn, model.ActionModifyColumn,
 			model.ActionAddIndex, model.ActionAddPrimaryKey,
 			model.ActionReorganizePartition, model.ActionRemovePartitioning,
-			model.ActionAlterTablePartitioning:
+			model.ActionAlterTablePartitioning, model.ActionDropMaterializedView,
+			model.ActionExchangeTablePartition, model.ActionAlterPrimaryKey:
 			return true
+		case model.ActionAddColumn:
+			// The indexes defined on the adding column need to be cleaned up when the job is rolled back.
+			return job.IsRollbackDone()
 		case model.ActionMultiSchemaChange:
 			for i, sub := range job.MultiSchemaInfo.SubJobs {
 				proxyJob := sub.ToProxyJob(job, i)
@@ -1229,7 +1233,7 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 	case model.ActionExchangeTablePartition:
 		ver, err = w.onExchangeTablePartition(d, t, job)
 	case model.ActionAddColumn:
-		ver, err = onAddColumn(d, t, job)
+		ver, err = w.onAddColumn(d, t, job)
 	case model.ActionDropColumn:
 		ver, err = onDropColumn(d, t, job)
 	case model.ActionModifyColumn:
@@ -1250,6 +1254,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onDropForeignKey(d, t, job)
 	case model.ActionTruncateTable:
 		ver, err = w.onTruncateTable(d, t, job)
+	case model.ActionAlterPrimaryKey:
+		ver, err = w.onAlterPrimaryKey(d, t, job)
 	case model.ActionRebaseAutoID:
 		ver, err = onRebaseAutoIncrementIDType(d, t, job)
 	case model.ActionRebaseAutoRandomBase:
@@ -1260,6 +1266,14 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = w.onShardRowID(d, t, job)
 	case model.ActionModifyTableComment:
 		ver, err = onModifyTableComment(d, t, job)
+	case model.ActionCreateTrigger:
+		ver, err = onCreateTrigger(d, t, job)
+	case model.ActionDropTrigger:
+		ver, err = onDropTrigger(d, t, job)
+	case model.ActionCreateMaterializedView:
+		ver, err = onCreateMaterializedView(d, t, job)
+	case model.ActionDropMaterializedView:
+		ver, err = onDropMaterializedView(d, t, job)
 	case model.ActionModifyTableAutoIdCache:
 		ver, err = onModifyTableAutoIDCache(d, t, job)
 	case model.ActionAddTablePartition:
@@ -1280,6 +1294,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onCreateSequence(d, t, job)
 	case model.ActionAlterIndexVisibility:
 		ver, err = onAlterIndexVisibility(d, t, job)
+	case model.ActionTrainVectorIndex:
+		ver, err = w.onTrainVectorIndex(d, t, job)
 	case model.ActionAlterSequence:
 		ver, err = onAlterSequence(d, t, job)
 	case model.ActionRenameTables:
@@ -1583,7 +1599,15 @@ func updateSchemaVersion(d *ddlCtx, t *meta.Meta, job *model.Job, multiInfos ...
 		diff.AffectedOpts = []*model.AffectedOption{{
 			TableID: ptTableID,
 		}}
-		if job.SchemaState != model.StatePublic {
+		if job.SchemaState == model.StateDeleteReorganization {
+			// After the exchange, only the partitioned table is refreshed
+			// for removing the replaced global indexes.
+			diff.TableID = ptTableID
+			diff.SchemaID = ptSchemaID
+			diff.OldTableID = ptTableID
+			diff.OldSchemaID = ptSchemaID
+			diff.AffectedOpts[0].SchemaID = ptSchemaID
+		} else if job.SchemaState != model.StatePublic {
 			// No change, just to refresh the non-partitioned table
 			// with its new ExchangePartitionInfo.
 			diff.TableID = job.TableID
@@ -1693,6 +1717,33 @@ func updateSchemaVersion(d *ddlCtx, t *meta.Meta, job *model.Job, multiInfos ...
 				OldTableID:  recoverTabsInfo[i].TableInfo.ID,
 			}
 		}
+	case model.ActionCreateMaterializedView:
+		// The change logs are created along with the materialized view.
+		diff.TableID = job.TableID
+		if len(job.CtxVars) > 0 {
+			sysSchemaID := job.CtxVars[0].(int64)
+			for _, logID := range job.CtxVars[1].([]int64) {
+				diff.AffectedOpts = append(diff.AffectedOpts, &model.AffectedOption{SchemaID: sysSchemaID, TableID: logID})
+			}
+		}
+	case model.ActionAlterPrimaryKey:
+		// The table ID is changed when the table is switched to the new primary key.
+		diff.TableID = job.TableID
+		diff.OldTableID = job.TableID
+		if len(job.CtxVars) > 0 {
+			diff.OldTableID = job.CtxVars[0].(int64)
+		}
+	case model.ActionDropMaterializedView:
+		// The change logs are dropped along with the materialized view in the last step.
+		diff.TableID = job.TableID
+		diff.OldTableID = job.TableID
+		if len(job.CtxVars) > 0 {
+			diff.TableID = 0
+			sysSchemaID := job.CtxVars[0].(int64)
+			for _, logID := range job.CtxVars[1].([]int64) {
+				diff.AffectedOpts = append(diff.AffectedOpts, &model.AffectedOption{OldSchemaID: sysSchemaID, OldTableID: logID})
+			}
+		}
 	case model.ActionFlashbackCluster:
 		diff.TableID = -1
 		if job.SchemaState == model.StatePublic {
@@ -267,6 +267,8 @@ func TestJobNeedGC(t *testing.T) {
 
 	job = &model.Job{Type: model.ActionAddColumn, State: model.JobStateDone}
 	require.False(t, ddl.JobNeedGC(job))
+	job = &model.Job{Type: model.ActionAddColumn, State: model.JobStateRollbackDone}
+	require.True(t, ddl.JobNeedGC(job))
 	job = &model.Job{Type: model.ActionAddIndex, State: model.JobStateDone}
 	require.True(t, ddl.JobNeedGC(job))
 	job = &model.Job{Type: model.ActionAddPrimaryKey, State: model.JobStateDone}
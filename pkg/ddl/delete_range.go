@@ -312,6 +312,30 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, wrapper DelRangeExecWrap
 			return errors.Trace(doBatchDeleteTablesRange(ctx, wrapper, job.ID, []int64{tableID}, ea, "drop table: table ID"))
 		}
 		return errors.Trace(doBatchDeleteTablesRange(ctx, wrapper, job.ID, []int64{tableID}, ea, "drop table: table ID"))
+	case model.ActionDropMaterializedView:
+		var (
+			baseSchemaIDs []int64
+			sysSchemaID   int64
+			tableIDs      []int64
+		)
+		if err := job.DecodeArgs(&baseSchemaIDs, &sysSchemaID, &tableIDs); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(doBatchDeleteTablesRange(ctx, wrapper, job.ID, tableIDs, ea, "drop materialized view: table IDs"))
+	case model.ActionAlterPrimaryKey:
+		// The table with the old primary key is dropped when the job is done, the table with the new primary key
+		// and the temporary unique index are dropped when the job is rolled back.
+		var tableIDs []int64
+		var tempIndexID int64
+		if err := job.DecodeArgs(&tableIDs, &tempIndexID); err != nil {
+			return errors.Trace(err)
+		}
+		if tempIndexID != 0 {
+			if err := doBatchDeleteIndiceRange(ctx, wrapper, job.ID, job.TableID, []int64{tempIndexID}, ea, "alter primary key: temporary index ID"); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return errors.Trace(doBatchDeleteTablesRange(ctx, wrapper, job.ID, tableIDs, ea, "alter primary key: table ID"))
 	case model.ActionDropTablePartition, model.ActionTruncateTablePartition,
 		model.ActionReorganizePartition, model.ActionRemovePartitioning,
 		model.ActionAlterTablePartitioning:
@@ -320,10 +344,33 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, wrapper DelRangeExecWrap
 		// Better to have an additional argument in job.DecodeArgs since it is ignored,
 		// instead of having one to few, which will remove the data from the job arguments...
 		var partInfo model.PartitionInfo
-		if err := job.DecodeArgs(&physicalTableIDs, &partInfo); err != nil {
+		// globalIndexIDs are the global indexes replaced or added by reorganize partition.
+		var globalIndexIDs []int64
+		if err := job.DecodeArgs(&physicalTableIDs, &partInfo, &globalIndexIDs); err != nil {
 			return errors.Trace(err)
 		}
+		if len(globalIndexIDs) > 0 {
+			if err := doBatchDeleteIndiceRange(ctx, wrapper, job.ID, job.TableID, globalIndexIDs, ea, "reorganize partition: global index ID(s)"); err != nil {
+				return errors.Trace(err)
+			}
+		}
 		return errors.Trace(doBatchDeleteTablesRange(ctx, wrapper, job.ID, physicalTableIDs, ea, "drop partition: physical table ID(s)"))
+	case model.ActionExchangeTablePartition:
+		var (
+			defID          int64
+			ptSchemaID     int64
+			ptID           int64
+			partName       string
+			withValidation bool
+			globalIndexIDs []int64
+		)
+		if err := job.DecodeArgs(&defID, &ptSchemaID, &ptID, &partName, &withValidation, &globalIndexIDs); err != nil {
+			return errors.Trace(err)
+		}
+		if len(globalIndexIDs) == 0 {
+			return nil
+		}
+		return errors.Trace(doBatchDeleteIndiceRange(ctx, wrapper, job.ID, ptID, globalIndexIDs, ea, "exchange partition: global index ID(s)"))
 	// ActionAddIndex, ActionAddPrimaryKey needs do it, because it needs to be rolled back when it's canceled.
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
 		allIndexIDs := make([]int64, 1)
@@ -379,6 +426,20 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, wrapper DelRangeExecWrap
 				return errors.Trace(err)
 			}
 		}
+	case model.ActionAddColumn:
+		// Only the rolled back add column job has indexes to clean up, see convertAddColumnJob2RollbackJob.
+		if !job.IsRollbackDone() {
+			return nil
+		}
+		var colName model.CIStr
+		var ifExists bool
+		var indexIDs []int64
+		if err := job.DecodeArgs(&colName, &ifExists, &indexIDs); err != nil {
+			return errors.Trace(err)
+		}
+		if len(indexIDs) > 0 {
+			return errors.Trace(doBatchDeleteIndiceRange(ctx, wrapper, job.ID, job.TableID, indexIDs, ea, "add column: rollback index ID(s)"))
+		}
 	case model.ActionDropColumn:
 		var colName model.CIStr
 		var ifExists bool
@@ -400,9 +461,16 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, wrapper DelRangeExecWrap
 	case model.ActionModifyColumn:
 		var indexIDs []int64
 		var partitionIDs []int64
-		if err := job.DecodeArgs(&indexIDs, &partitionIDs); err != nil {
+		var droppedPartIDs []int64
+		if err := job.DecodeArgs(&indexIDs, &partitionIDs, &droppedPartIDs); err != nil {
 			return errors.Trace(err)
 		}
+		if len(droppedPartIDs) > 0 {
+			// The partitioning column is modified, the rows are moved to the new partitions.
+			if err := doBatchDeleteTablesRange(ctx, wrapper, job.ID, droppedPartIDs, ea, "modify column: reorganized partition table IDs"); err != nil {
+				return errors.Trace(err)
+			}
+		}
 		if len(indexIDs) == 0 {
 			return nil
 		}
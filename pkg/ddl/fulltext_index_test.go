@@ -0,0 +1,118 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullTextIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (id int primary key, title varchar(100), body text, fulltext key ft_body (body))")
+	tk.MustQuery("show create table t").Check(testkit.RowsWithSep("|", "t|CREATE TABLE `t` (\n"+
+		"  `id` int(11) NOT NULL,\n"+
+		"  `title` varchar(100) DEFAULT NULL,\n"+
+		"  `body` text DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  FULLTEXT KEY `ft_body` (`body`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into t values " +
+		"(1, 'first', 'TiDB is a distributed SQL database'), " +
+		"(2, 'second', 'MySQL is a popular database, and a database server'), " +
+		"(3, 'third', 'The distributed key-value storage'), " +
+		"(4, 'fourth', null)")
+
+	tk.MustQuery("select id from t where match(body) against('database') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id, (match(body) against('database')) > 1 from t order by id").Check(testkit.Rows("1 0", "2 1", "3 0", "4 0"))
+	tk.MustQuery("select id from t where match(body) against('distributed database') order by match(body) against('distributed database') desc, id").
+		Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t where match(body) against('+distributed +database' in boolean mode)").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where match(body) against('+distributed -sql' in boolean mode)").Check(testkit.Rows("3"))
+	tk.MustQuery("select id from t where match(body) against('data*' in boolean mode) order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where match(body) against('\"sql database\"' in boolean mode)").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where match(body) against('\"database sql\"' in boolean mode)").Check(testkit.Rows())
+
+	// The FULLTEXT index is accessed by IndexMerge.
+	tk.MustHavePlan("select id from t where match(body) against('+distributed +database' in boolean mode)", "IndexMerge")
+	require.True(t, tk.HasKeywordInOperatorInfo("select id from t where match(body) against('+distributed +database' in boolean mode)", "intersection"))
+	tk.MustHavePlan("select id from t where match(body) against('distributed database')", "IndexMerge")
+	require.True(t, tk.HasKeywordInOperatorInfo("select id from t where match(body) against('distributed database')", "union"))
+	tk.MustHavePlan("select /*+ use_index_merge(t, ft_body) */ id from t where match(body) against('data*' in boolean mode)", "IndexMerge")
+
+	// The index is maintained by the writes.
+	tk.MustExec("update t set body = 'A document without the keyword' where id = 1")
+	tk.MustExec("update t set body = 'Another database' where id = 4")
+	tk.MustExec("delete from t where id = 2")
+	tk.MustExec("insert into t values (5, 'fifth', 'DATABASE')")
+	tk.MustQuery("select id from t where match(body) against('database') order by id").Check(testkit.Rows("4", "5"))
+	tk.MustQuery("select id from t where match(body) against('keyword')").Check(testkit.Rows("1"))
+	tk.MustExec("admin check table t")
+
+	// The index is backfilled when it's added.
+	tk.MustExec("alter table t add fulltext index ft_title (title)")
+	tk.MustQuery("select id from t where match(title) against('third fifth') order by id").Check(testkit.Rows("3", "5"))
+	tk.MustExec("alter table t drop index ft_title")
+
+	tk.MustGetErrCode("select id from t where match(title) against('database')", errno.ErrFtMatchingKeyNotFound)
+	tk.MustGetErrCode("select id from t where match(body, title) against('database')", errno.ErrFtMatchingKeyNotFound)
+	tk.MustGetErrCode("select id from t where match(body) against(title)", errno.ErrWrongArguments)
+	tk.MustGetErrCode("alter table t add fulltext index (id)", errno.ErrBadFtColumn)
+	tk.MustGetErrCode("alter table t add fulltext index ((lower(title)))", errno.ErrFulltextFunctionalIndex)
+	tk.MustGetErrCode("create table t1 (a blob, fulltext (a))", errno.ErrBadFtColumn)
+
+	// The unsupported FULLTEXT indexes are ignored with a warning.
+	tk.MustExec("alter table t add fulltext index ft_multi (title, body)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1214 The used table type doesn't support FULLTEXT indexes"))
+	tk.MustExec("create table t1 (a text, b text, fulltext (a, b), fulltext ft_a (a))")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1214 The used table type doesn't support FULLTEXT indexes"))
+	tk.MustExec("alter table t1 add fulltext index ft_mecab (a) with parser mecab")
+	tk.MustQuery("show warnings").CheckContain("The used table type doesn't support FULLTEXT indexes")
+	tk.MustQuery("select index_name from information_schema.statistics where table_name = 't1'").Check(testkit.Rows("ft_a"))
+	tk.MustQuery("select count(*) from information_schema.statistics where table_name = 't' and index_name = 'ft_multi'").Check(testkit.Rows("0"))
+	tk.MustGetErrCode("alter table t1 add fulltext index ((lower(a)), b)", errno.ErrFulltextFunctionalIndex)
+}
+
+func TestFullTextIndexWithNgramParser(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (id int primary key, body text)")
+	tk.MustExec("insert into t values (1, '分布式数据库'), (2, '关系型数据库'), (3, '分布式存储')")
+	tk.MustExec("alter table t add fulltext index ft_body (body) with parser ngram")
+	tk.MustQuery("show create table t").Check(testkit.RowsWithSep("|", "t|CREATE TABLE `t` (\n"+
+		"  `id` int(11) NOT NULL,\n"+
+		"  `body` text DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  FULLTEXT KEY `ft_body` (`body`) /*!50100 WITH PARSER `ngram` */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	tk.MustQuery("select id from t where match(body) against('数据库') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where match(body) against('分布' in boolean mode) order by id").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select id from t where match(body) against('\"式数据\"' in boolean mode)").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where match(body) against('+分布式 -存储' in boolean mode)").Check(testkit.Rows("1"))
+	tk.MustHavePlan("select id from t where match(body) against('数据库')", "IndexMerge")
+
+	tk.MustExec("insert into t values (4, '图数据库')")
+	tk.MustQuery("select id from t where match(body) against('图数据' in boolean mode)").Check(testkit.Rows("4"))
+	tk.MustExec("admin check table t")
+}
@@ -157,6 +157,147 @@ func buildIndexColumns(ctx sessionctx.Context, columns []*model.ColumnInfo, inde
 	return idxParts, mvIndex, nil
 }
 
+// FullTextIndexOption returns a copy of the index option with the FULLTEXT index type.
+func FullTextIndexOption(indexOption *ast.IndexOption) *ast.IndexOption {
+	opt := &ast.IndexOption{}
+	if indexOption != nil {
+		*opt = *indexOption
+	}
+	opt.Tp = model.IndexTypeFullText
+	return opt
+}
+
+// checkFullTextIndexParts checks the key parts of a FULLTEXT index before the hidden columns of the expression key
+// parts are built.
+func checkFullTextIndexParts(indexPartSpecifications []*ast.IndexPartSpecification) error {
+	for _, ip := range indexPartSpecifications {
+		if ip.Expr != nil {
+			return dbterror.ErrFulltextFunctionalIndex
+		}
+	}
+	if len(indexPartSpecifications) != 1 {
+		return dbterror.ErrUnsupportedIndexType.GenWithStack("FULLTEXT index on multiple columns is not supported")
+	}
+	return nil
+}
+
+// CheckFullTextIndex checks whether a FULLTEXT index can be built. A functional key part is an error, but like MySQL
+// on an engine without FULLTEXT support, an index on multiple columns or with an unknown parser is ignored with a
+// warning, and false is returned for it.
+func CheckFullTextIndex(sctx sessionctx.Context, indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) (bool, error) {
+	for _, ip := range indexPartSpecifications {
+		if ip.Expr != nil {
+			return false, dbterror.ErrFulltextFunctionalIndex
+		}
+	}
+	supported := len(indexPartSpecifications) == 1
+	if indexOption != nil {
+		switch indexOption.ParserName.L {
+		case "", string(model.FullTextParserTypeNgram):
+		default:
+			supported = false
+		}
+	}
+	if !supported {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrTableCantHandleFt.FastGenByArgs())
+	}
+	return supported, nil
+}
+
+// buildFullTextIndexColumns builds the column of a FULLTEXT index, which must be a non-binary string column. The
+// whole text is tokenized, so the prefix length is ignored.
+func buildFullTextIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*ast.IndexPartSpecification) ([]*model.IndexColumn, error) {
+	if err := checkFullTextIndexParts(indexPartSpecifications); err != nil {
+		return nil, err
+	}
+	ip := indexPartSpecifications[0]
+	col := model.FindColumnInfo(columns, ip.Column.Name.L)
+	if col == nil {
+		return nil, dbterror.ErrKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
+	}
+	switch col.GetType() {
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+	default:
+		return nil, dbterror.ErrBadFtColumn.GenWithStackByArgs(col.Name)
+	}
+	if col.GetCharset() == charset.CharsetBin || col.FieldType.IsArray() {
+		return nil, dbterror.ErrBadFtColumn.GenWithStackByArgs(col.Name)
+	}
+	return []*model.IndexColumn{{
+		Name:   col.Name,
+		Offset: col.Offset,
+		Length: types.UnspecifiedLength,
+	}}, nil
+}
+
+// buildFullTextIndexInfo builds the parser information of a FULLTEXT index from the `WITH PARSER` option.
+func buildFullTextIndexInfo(indexOption *ast.IndexOption) (*model.FullTextIndexInfo, error) {
+	switch indexOption.ParserName.L {
+	case "":
+		return &model.FullTextIndexInfo{ParserType: model.FullTextParserTypeStandard}, nil
+	case string(model.FullTextParserTypeNgram):
+		return &model.FullTextIndexInfo{ParserType: model.FullTextParserTypeNgram, NgramSize: model.DefaultNgramSize}, nil
+	default:
+		return nil, dbterror.ErrUnsupportedIndexType.GenWithStack("FULLTEXT parser '%s' is not supported", indexOption.ParserName.O)
+	}
+}
+
+// ResolveVectorIndexParts resolves the key part of a VECTOR index, which must be a distance function on a column,
+// e.g. `(VEC_COSINE_DISTANCE(v))`. The distance function decides the distance metric of the index, which is kept in
+// the returned index option with the IVF index type, and the key part is replaced by the column, so no hidden column
+// is built for it.
+func ResolveVectorIndexParts(indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) ([]*ast.IndexPartSpecification, *ast.IndexOption, error) {
+	if len(indexPartSpecifications) != 1 {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index on multiple key parts is not supported")
+	}
+	fn, ok := indexPartSpecifications[0].Expr.(*ast.FuncCallExpr)
+	if !ok {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index must be built on a distance function, e.g. VEC_COSINE_DISTANCE(col)")
+	}
+	metric, ok := model.DistanceMetric4VectorIndex[fn.FnName.L]
+	if !ok {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("distance function '%s' is not supported by VECTOR index", fn.FnName.O)
+	}
+	var colName *ast.ColumnNameExpr
+	if len(fn.Args) == 1 {
+		colName, _ = fn.Args[0].(*ast.ColumnNameExpr)
+	}
+	if colName == nil {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index must be built on a distance function of a column, e.g. %s(col)", fn.FnName.O)
+	}
+	opt := &ast.IndexOption{}
+	if indexOption != nil {
+		*opt = *indexOption
+	}
+	opt.Tp = model.IndexTypeIVF
+	opt.DistanceMetric = metric
+	parts := []*ast.IndexPartSpecification{{Column: colName.Name, Length: types.UnspecifiedLength}}
+	return parts, opt, nil
+}
+
+// buildVectorIndexColumns builds the column of a vector index, which must be a vector column with a fixed dimension.
+// The key part must have been resolved by ResolveVectorIndexParts.
+func buildVectorIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) ([]*model.IndexColumn, *model.VectorIndexInfo, error) {
+	if len(indexPartSpecifications) != 1 || indexPartSpecifications[0].Column == nil || indexOption.DistanceMetric == "" {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index must be built on a distance function, e.g. VEC_COSINE_DISTANCE(col)")
+	}
+	ip := indexPartSpecifications[0]
+	col := model.FindColumnInfo(columns, ip.Column.Name.L)
+	if col == nil {
+		return nil, nil, dbterror.ErrKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
+	}
+	if col.GetType() != mysql.TypeTiDBVectorFloat32 || col.GetFlen() == types.UnspecifiedLength {
+		return nil, nil, dbterror.ErrUnsupportedIndexType.GenWithStack("VECTOR index can only be built on a vector column with a fixed dimension")
+	}
+	idxColumns := []*model.IndexColumn{{
+		Name:   col.Name,
+		Offset: col.Offset,
+		Length: types.UnspecifiedLength,
+	}}
+	return idxColumns, &model.VectorIndexInfo{Dimension: col.GetFlen(), DistanceMetric: indexOption.DistanceMetric}, nil
+}
+
 // CheckPKOnGeneratedColumn checks the specification of PK is valid.
 func CheckPKOnGeneratedColumn(tblInfo *model.TableInfo, indexPartSpecifications []*ast.IndexPartSpecification) (*model.ColumnInfo, error) {
 	var lastCol *model.ColumnInfo
@@ -204,6 +345,19 @@ func checkIndexColumn(ctx sessionctx.Context, col *model.ColumnInfo, indexColumn
 		return errors.Trace(dbterror.ErrJSONUsedAsKey.GenWithStackByArgs(col.Name.O))
 	}
 
+	// Geometry column can only be indexed by SPATIAL index, which is not supported yet.
+	if col.FieldType.GetType() == mysql.TypeGeometry {
+		if col.Hidden {
+			return dbterror.ErrFunctionalIndexOnJSONOrGeometryFunction
+		}
+		return errors.Trace(dbterror.ErrBlobKeyWithoutLength.GenWithStackByArgs(col.Name.O))
+	}
+
+	// Vector column can only be indexed by vector index.
+	if col.FieldType.GetType() == mysql.TypeTiDBVectorFloat32 {
+		return dbterror.ErrUnsupportedIndexType.GenWithStack("vector column '%s' can only be indexed by VECTOR index", col.Name.O)
+	}
+
 	// Length must be specified and non-zero for BLOB and TEXT column indexes.
 	if types.IsTypeBlob(col.FieldType.GetType()) {
 		if indexColumnLen == types.UnspecifiedLength {
@@ -309,20 +463,45 @@ func BuildIndexInfo(
 		return nil, errors.Trace(err)
 	}
 
-	idxColumns, mvIndex, err := buildIndexColumns(ctx, allTableColumns, indexPartSpecifications)
-	if err != nil {
-		return nil, errors.Trace(err)
+	var (
+		idxColumns   []*model.IndexColumn
+		mvIndex      bool
+		fullTextInfo *model.FullTextIndexInfo
+		vectorInfo   *model.VectorIndexInfo
+		err          error
+	)
+	if indexOption != nil && indexOption.Tp == model.IndexTypeFullText {
+		if idxColumns, err = buildFullTextIndexColumns(allTableColumns, indexPartSpecifications); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if fullTextInfo, err = buildFullTextIndexInfo(indexOption); err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if indexOption != nil && indexOption.Tp == model.IndexTypeIVF {
+		if idxColumns, vectorInfo, err = buildVectorIndexColumns(allTableColumns, indexPartSpecifications, indexOption); err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else {
+		idxColumns, mvIndex, err = buildIndexColumns(ctx, allTableColumns, indexPartSpecifications)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Create index info.
 	idxInfo := &model.IndexInfo{
-		Name:    indexName,
-		Columns: idxColumns,
-		State:   state,
-		Primary: isPrimary,
-		Unique:  isUnique,
-		Global:  isGlobal,
-		MVIndex: mvIndex,
+		Name:         indexName,
+		Columns:      idxColumns,
+		State:        state,
+		Primary:      isPrimary,
+		Unique:       isUnique,
+		Global:       isGlobal,
+		MVIndex:      mvIndex,
+		FullTextInfo: fullTextInfo,
+		VectorInfo:   vectorInfo,
+	}
+	if isGlobal {
+		idxInfo.GlobalIndexVersion = model.GlobalIndexVersionV1
 	}
 
 	if indexOption != nil {
@@ -1600,6 +1779,11 @@ func (w *addIndexTxnWorker) checkHandleExists(idxInfo *model.IndexInfo, key kv.K
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if idxInfo.Global {
+		// The same handle may exist in different partitions.
+		//nolint:forcetypeassert
+		handle = kv.NewPartitionHandle(w.table.(table.PhysicalTable).GetPhysicalID(), handle)
+	}
 	hasBeenBackFilled := h.Equal(handle)
 	if hasBeenBackFilled {
 		return nil
@@ -2558,6 +2742,55 @@ func (w *worker) cleanupGlobalIndexes(tbl table.PartitionedTable, partitionIDs [
 	return errors.Trace(err)
 }
 
+// addGlobalIndexesForPartitions builds the new global indexes of a reorganize or exchange partition job,
+// from the rows of the partitions in partitionIDs.
+func (w *worker) addGlobalIndexesForPartitions(t table.Table, partitionIDs []int64, reorgInfo *reorgInfo) error {
+	tbl, ok := t.(table.PartitionedTable)
+	if !ok {
+		return dbterror.ErrCancelledDDLJob.GenWithStack("table %d is not partitioned", t.Meta().ID)
+	}
+	origElements := reorgInfo.elements
+	defer func() {
+		reorgInfo.elements = origElements
+	}()
+	reorgInfo.elements = getChangedGlobalIndexElements(t.Meta())
+	if len(reorgInfo.elements) == 0 {
+		return nil
+	}
+	if !slices.Contains(partitionIDs, reorgInfo.PhysicalTableID) {
+		// First run, restart with the full range of the first partition.
+		currentVer, err := getValidCurrentVersion(reorgInfo.d.store)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		start, end, err := getTableRange(reorgInfo.NewJobContext(), reorgInfo.d, tbl.GetPartition(partitionIDs[0]), currentVer.Ver, reorgInfo.Job.Priority)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reorgInfo.StartKey, reorgInfo.EndKey, reorgInfo.PhysicalTableID = start, end, partitionIDs[0]
+		reorgInfo.currElement = reorgInfo.elements[0]
+		if err = reorgInfo.UpdateReorgMeta(reorgInfo.StartKey, w.sessPool); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return dbterror.ErrCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
+		}
+		err := w.addPhysicalTableIndex(p, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		finish, err = w.updateReorgInfoForPartitions(tbl, reorgInfo, partitionIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // updateReorgInfoForPartitions will find the next partition in partitionIDs according to current reorgInfo.
 // If no more partitions, or table t is not a partitioned table, returns true to
 // indicate that the reorganize work is finished.
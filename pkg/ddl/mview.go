@@ -0,0 +1,440 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+)
+
+// mviewLogHandleLen is the length of the handle column of a change log for a table with a clustered index. The log
+// rows are written by the table write path directly, so longer handles are still stored completely.
+const mviewLogHandleLen = 2048
+
+// MViewLogTableName returns the name of the change log of a base table of a materialized view.
+func MViewLogTableName(mviewID, baseTableID int64) string {
+	return fmt.Sprintf("tidb_mlog_%d_%d", mviewID, baseTableID)
+}
+
+// CreateMaterializedView creates a materialized view and the change logs of its base tables. The columns are the
+// output columns of the definition, and logCols are the columns to record in the change log of each base table.
+func (d *ddl) CreateMaterializedView(ctx sessionctx.Context, s *ast.CreateMaterializedViewStmt, cols []*table.Column,
+	mview *model.MaterializedViewInfo, logCols [][]int64) error {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(s.ViewName.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.ViewName.Schema)
+	}
+	if is.TableExists(s.ViewName.Schema, s.ViewName.Name) {
+		err := infoschema.ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name})
+		if s.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	sysSchema, ok := is.SchemaByName(model.NewCIStr(mysql.SystemDB))
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(mysql.SystemDB)
+	}
+
+	tblCharset, _ := ctx.GetSessionVars().GetSystemVar(variable.CharacterSetConnection)
+	tblCollate, _ := ctx.GetSessionVars().GetSystemVar(variable.CollationConnection)
+	tbInfo, err := BuildTableInfo(ctx, s.ViewName.Name, cols, nil, tblCharset, tblCollate)
+	if err != nil {
+		return err
+	}
+	tbInfo.MaterializedView = mview
+	if err = checkTableInfoValidWithStmt(ctx, tbInfo, &ast.CreateTableStmt{Table: s.ViewName}); err != nil {
+		return err
+	}
+
+	// The change logs are only created for fast refresh, logCols is nil otherwise.
+	ids, err := d.genGlobalIDs(1 + len(logCols))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo.ID = ids[0]
+	mview.LogTableIDs = ids[1:]
+
+	involvingSchemas := []model.InvolvingSchemaInfo{{Database: schema.Name.L, Table: tbInfo.Name.L}}
+	logInfos := make([]*model.TableInfo, 0, len(logCols))
+	logs := make([]*model.MViewLogInfo, 0, len(logCols))
+	baseSchemaIDs := make([]int64, 0, len(logCols))
+	for i, baseID := range mview.BaseTableIDs[:len(logCols)] {
+		base, ok := is.TableByID(baseID)
+		if !ok {
+			return infoschema.ErrTableNotExists.GenWithStackByArgs(schema.Name, fmt.Sprintf("(Table ID %d)", baseID))
+		}
+		baseSchema, ok := infoschema.SchemaByTable(is, base.Meta())
+		if !ok {
+			return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(fmt.Sprintf("(Schema ID %d)", base.Meta().DBID))
+		}
+		logInfo, err := buildMViewLogTableInfo(ctx, tbInfo.ID, base.Meta(), logCols[i])
+		if err != nil {
+			return err
+		}
+		logInfo.ID = mview.LogTableIDs[i]
+		logInfos = append(logInfos, logInfo)
+		logs = append(logs, &model.MViewLogInfo{MViewID: tbInfo.ID, LogTableID: logInfo.ID, Columns: logCols[i]})
+		baseSchemaIDs = append(baseSchemaIDs, baseSchema.ID)
+		involvingSchemas = append(involvingSchemas,
+			model.InvolvingSchemaInfo{Database: baseSchema.Name.L, Table: base.Meta().Name.L},
+			model.InvolvingSchemaInfo{Database: sysSchema.Name.L, Table: logInfo.Name.L})
+	}
+
+	job := &model.Job{
+		SchemaID:            schema.ID,
+		TableID:             tbInfo.ID,
+		SchemaName:          schema.Name.L,
+		TableName:           tbInfo.Name.L,
+		Type:                model.ActionCreateMaterializedView,
+		BinlogInfo:          &model.HistoryInfo{},
+		Args:                []any{tbInfo, logInfos, sysSchema.ID, baseSchemaIDs, logs},
+		InvolvingSchemaInfo: involvingSchemas,
+		CDCWriteSource:      ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:             ctx.GetSessionVars().SQLMode,
+	}
+	err = d.DoDDLJob(ctx, job)
+	if infoschema.ErrTableExists.Equal(err) && s.IfNotExists {
+		ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		err = nil
+	}
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// DropMaterializedView drops a materialized view and the change logs of its base tables.
+func (d *ddl) DropMaterializedView(ctx sessionctx.Context, s *ast.DropMaterializedViewStmt) error {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	ident := ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name}
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err) {
+		err = infoschema.ErrTableDropExists.FastGenByArgs(ident.String())
+		if s.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tb.Meta()
+	if tbInfo.MaterializedView == nil {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(schema.Name.O, tbInfo.Name.O, "MATERIALIZED VIEW")
+	}
+	sysSchema, ok := is.SchemaByName(model.NewCIStr(mysql.SystemDB))
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(mysql.SystemDB)
+	}
+
+	involvingSchemas := []model.InvolvingSchemaInfo{{Database: schema.Name.L, Table: tbInfo.Name.L}}
+	logTableIDs := tbInfo.MaterializedView.LogTableIDs
+	baseSchemaIDs := make([]int64, 0, len(logTableIDs))
+	for i, baseID := range tbInfo.MaterializedView.BaseTableIDs[:len(logTableIDs)] {
+		// The base table may have been dropped along with its schema.
+		baseSchemaID := int64(0)
+		if base, ok := is.TableByID(baseID); ok {
+			if baseSchema, ok := infoschema.SchemaByTable(is, base.Meta()); ok {
+				baseSchemaID = baseSchema.ID
+				involvingSchemas = append(involvingSchemas,
+					model.InvolvingSchemaInfo{Database: baseSchema.Name.L, Table: base.Meta().Name.L})
+			}
+		}
+		baseSchemaIDs = append(baseSchemaIDs, baseSchemaID)
+		involvingSchemas = append(involvingSchemas, model.InvolvingSchemaInfo{
+			Database: sysSchema.Name.L,
+			Table:    MViewLogTableName(tbInfo.ID, tbInfo.MaterializedView.BaseTableIDs[i]),
+		})
+	}
+
+	job := &model.Job{
+		SchemaID:            schema.ID,
+		TableID:             tbInfo.ID,
+		SchemaName:          schema.Name.L,
+		TableName:           tbInfo.Name.L,
+		Type:                model.ActionDropMaterializedView,
+		BinlogInfo:          &model.HistoryInfo{},
+		Args:                []any{baseSchemaIDs, sysSchema.ID},
+		InvolvingSchemaInfo: involvingSchemas,
+		CDCWriteSource:      ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:             ctx.GetSessionVars().SQLMode,
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// buildMViewLogTableInfo builds the change log of a base table. The log is clustered by the start ts of the
+// transaction, the handle of the changed row and the sign of the row image, followed by the recorded columns.
+func buildMViewLogTableInfo(ctx sessionctx.Context, mviewID int64, base *model.TableInfo, logCols []int64) (*model.TableInfo, error) {
+	handleType := types.NewFieldType(mysql.TypeLonglong)
+	if base.IsCommonHandle {
+		handleType = types.NewFieldType(mysql.TypeVarchar)
+		handleType.SetFlen(mviewLogHandleLen)
+		handleType.SetCharset("binary")
+		handleType.SetCollate("binary")
+		handleType.AddFlag(mysql.BinaryFlag)
+	}
+	tsType := types.NewFieldType(mysql.TypeLonglong)
+	tsType.AddFlag(mysql.UnsignedFlag)
+	colInfos := []*model.ColumnInfo{
+		{Name: model.NewCIStr(model.MViewLogTSColName), FieldType: *tsType},
+		{Name: model.NewCIStr(model.MViewLogHandleColName), FieldType: *handleType},
+		{Name: model.NewCIStr(model.MViewLogSignColName), FieldType: *types.NewFieldType(mysql.TypeTiny)},
+	}
+	for _, id := range logCols {
+		col := model.FindColumnInfoByID(base.Columns, id)
+		if col == nil {
+			return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(fmt.Sprintf("(Column ID %d)", id), base.Name)
+		}
+		ft := col.FieldType.Clone()
+		ft.DelFlag(mysql.PriKeyFlag | mysql.UniqueKeyFlag | mysql.MultipleKeyFlag | mysql.AutoIncrementFlag |
+			mysql.OnUpdateNowFlag | mysql.NotNullFlag)
+		colInfos = append(colInfos, &model.ColumnInfo{Name: col.Name, FieldType: *ft})
+	}
+
+	cols := make([]*table.Column, 0, len(colInfos))
+	keys := make([]*ast.IndexPartSpecification, 0, model.MViewLogReservedCols)
+	for i, colInfo := range colInfos {
+		colInfo.Offset = i
+		colInfo.State = model.StatePublic
+		if i < model.MViewLogReservedCols {
+			colInfo.AddFlag(mysql.NotNullFlag)
+			keys = append(keys, &ast.IndexPartSpecification{Column: &ast.ColumnName{Name: colInfo.Name}, Length: types.UnspecifiedLength})
+		}
+		cols = append(cols, table.ToColumn(colInfo))
+	}
+	pk := &ast.Constraint{
+		Tp:     ast.ConstraintPrimaryKey,
+		Keys:   keys,
+		Option: &ast.IndexOption{PrimaryKeyTp: model.PrimaryKeyTypeClustered},
+	}
+	return BuildTableInfo(ctx, model.NewCIStr(MViewLogTableName(mviewID, base.ID)), cols, []*ast.Constraint{pk},
+		mysql.DefaultCharset, mysql.DefaultCollationName)
+}
+
+func onCreateMaterializedView(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tbInfo := &model.TableInfo{}
+	var (
+		logInfos      []*model.TableInfo
+		sysSchemaID   int64
+		baseSchemaIDs []int64
+		logs          []*model.MViewLogInfo
+	)
+	if err := job.DecodeArgs(tbInfo, &logInfos, &sysSchemaID, &baseSchemaIDs, &logs); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	if err := checkTableNotExists(d, t, job.SchemaID, tbInfo.Name.L); err != nil {
+		if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableExists.Equal(err) {
+			job.State = model.JobStateCancelled
+		}
+		return ver, errors.Trace(err)
+	}
+	bases := make([]schemaIDAndTableInfo, 0, len(baseSchemaIDs))
+	for i, baseID := range tbInfo.MaterializedView.BaseTableIDs[:len(logs)] {
+		base, err := getTableInfo(t, baseID, baseSchemaIDs[i])
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		for _, id := range logs[i].Columns {
+			if col := model.FindColumnInfoByID(base.Columns, id); col == nil || col.State != model.StatePublic {
+				job.State = model.JobStateCancelled
+				return ver, infoschema.ErrColumnNotExists.GenWithStackByArgs(fmt.Sprintf("(Column ID %d)", id), base.Name)
+			}
+		}
+		base.MViewLogs = append(base.MViewLogs, logs[i])
+		base.UpdateTS = t.StartTS
+		bases = append(bases, schemaIDAndTableInfo{schemaID: baseSchemaIDs[i], tblInfo: base})
+	}
+
+	for _, logInfo := range logInfos {
+		logInfo.State = model.StatePublic
+		logInfo.UpdateTS = t.StartTS
+		if err := checkTableInfoValid(logInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if err := t.CreateTableOrView(sysSchemaID, mysql.SystemDB, logInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	tbInfo.State = model.StatePublic
+	tbInfo.UpdateTS = t.StartTS
+	if err := createTableOrViewWithCheck(t, job, job.SchemaID, tbInfo); err != nil {
+		return ver, errors.Trace(err)
+	}
+	for _, base := range bases {
+		if err := t.UpdateTable(base.schemaID, base.tblInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+
+	job.CtxVars = []any{sysSchemaID, tbInfo.MaterializedView.LogTableIDs}
+	ver, err := updateSchemaVersion(d, t, job, bases...)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tbInfo)
+	return ver, nil
+}
+
+// onDropMaterializedView drops a materialized view in two steps. The change logs are detached from the base tables
+// first, so no transaction writes the logs any more when they're dropped.
+func onDropMaterializedView(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var (
+		baseSchemaIDs []int64
+		sysSchemaID   int64
+	)
+	if err := job.DecodeArgs(&baseSchemaIDs, &sysSchemaID); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tbInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if tbInfo.MaterializedView == nil {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrWrongObject.GenWithStackByArgs(job.SchemaName, tbInfo.Name.O, "MATERIALIZED VIEW")
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		// none -> delete only, the base tables stop writing the change logs.
+		bases := make([]schemaIDAndTableInfo, 0, len(baseSchemaIDs))
+		for i, baseID := range tbInfo.MaterializedView.BaseTableIDs[:len(baseSchemaIDs)] {
+			base, err := t.GetTable(baseSchemaIDs[i], baseID)
+			if err != nil && !meta.ErrDBNotExists.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			if base == nil {
+				continue
+			}
+			logs := make([]*model.MViewLogInfo, 0, len(base.MViewLogs))
+			for _, log := range base.MViewLogs {
+				if log.MViewID != tbInfo.ID {
+					logs = append(logs, log)
+				}
+			}
+			base.MViewLogs = logs
+			if err = t.UpdateTable(baseSchemaIDs[i], base); err != nil {
+				return ver, errors.Trace(err)
+			}
+			bases = append(bases, schemaIDAndTableInfo{schemaID: baseSchemaIDs[i], tblInfo: base})
+		}
+		ver, err = updateSchemaVersion(d, t, job, bases...)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> none
+		tableIDs := make([]int64, 0, 1+len(tbInfo.MaterializedView.LogTableIDs))
+		tableIDs = append(tableIDs, tbInfo.ID)
+		for i, logID := range tbInfo.MaterializedView.LogTableIDs {
+			logName := MViewLogTableName(tbInfo.ID, tbInfo.MaterializedView.BaseTableIDs[i])
+			if err = t.DropTableOrView(sysSchemaID, mysql.SystemDB, logID, logName); err != nil {
+				return ver, errors.Trace(err)
+			}
+			tableIDs = append(tableIDs, logID)
+		}
+		if err = t.DropTableOrView(job.SchemaID, job.SchemaName, tbInfo.ID, tbInfo.Name.L); err != nil {
+			return ver, errors.Trace(err)
+		}
+		if err = t.GetAutoIDAccessors(job.SchemaID, tbInfo.ID).Del(); err != nil {
+			return ver, errors.Trace(err)
+		}
+
+		job.CtxVars = []any{sysSchemaID, tbInfo.MaterializedView.LogTableIDs}
+		ver, err = updateSchemaVersion(d, t, job)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		tbInfo.State = model.StateNone
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tbInfo)
+		job.Args = append(job.Args, tableIDs)
+	default:
+		return ver, dbterror.ErrInvalidDDLState.GenWithStackByArgs("materialized view", job.SchemaState)
+	}
+	return ver, nil
+}
+
+// checkTableWithMViews checks the table isn't a materialized view or a base table of a materialized view refreshed
+// incrementally, whose table ID is tracked by the materialized view.
+func checkTableWithMViews(is infoschema.InfoSchema, schemaName model.CIStr, tblInfo *model.TableInfo) error {
+	if tblInfo.MaterializedView != nil {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(schemaName.O, tblInfo.Name.O, "BASE TABLE")
+	}
+	if len(tblInfo.MViewLogs) > 0 {
+		return dbterror.ErrMViewDependency.GenWithStackByArgs(tblInfo.Name.O, tableNameByID(is, tblInfo.MViewLogs[0].MViewID))
+	}
+	return nil
+}
+
+// checkColumnWithMViewLogs checks the column isn't recorded by the change logs of the table.
+func checkColumnWithMViewLogs(is infoschema.InfoSchema, tblInfo *model.TableInfo, colName model.CIStr) error {
+	col := model.FindColumnInfo(tblInfo.Columns, colName.L)
+	if col == nil {
+		return nil
+	}
+	for _, log := range tblInfo.MViewLogs {
+		for _, id := range log.Columns {
+			if id == col.ID {
+				return dbterror.ErrMViewDependency.GenWithStackByArgs(col.Name.O, tableNameByID(is, log.MViewID))
+			}
+		}
+	}
+	return nil
+}
+
+// checkDatabaseWithMViews checks the tables of the database aren't tracked by the materialized views outside the
+// database, or the change logs would be left behind.
+func checkDatabaseWithMViews(is infoschema.InfoSchema, dbInfo *model.DBInfo) error {
+	for _, tbl := range is.SchemaTables(dbInfo.Name) {
+		tblInfo := tbl.Meta()
+		if tblInfo.MaterializedView != nil && len(tblInfo.MaterializedView.LogTableIDs) > 0 {
+			baseName := tableNameByID(is, tblInfo.MaterializedView.BaseTableIDs[0])
+			return dbterror.ErrMViewDependency.GenWithStackByArgs(baseName, tblInfo.Name.O)
+		}
+		if len(tblInfo.MViewLogs) > 0 {
+			return dbterror.ErrMViewDependency.GenWithStackByArgs(tblInfo.Name.O, tableNameByID(is, tblInfo.MViewLogs[0].MViewID))
+		}
+	}
+	return nil
+}
+
+func tableNameByID(is infoschema.InfoSchema, id int64) string {
+	if is != nil {
+		if tbl, ok := is.TableByID(id); ok {
+			return tbl.Meta().Name.O
+		}
+	}
+	return fmt.Sprintf("(Table ID %d)", id)
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -369,7 +370,8 @@ func checkAddPartitionValue(meta *model.TableInfo, part *model.PartitionInfo) er
 	switch meta.Partition.Type {
 	case model.PartitionTypeRange:
 		if len(meta.Partition.Columns) == 0 {
-			newDefs, oldDefs := part.Definitions, meta.Partition.Definitions
+			newDefs := part.TopLevelDefinitions(part.Definitions)
+			oldDefs := meta.Partition.TopLevelDefinitions(meta.Partition.Definitions)
 			rangeValue := oldDefs[len(oldDefs)-1].LessThan[0]
 			if strings.EqualFold(rangeValue, "MAXVALUE") {
 				return errors.Trace(dbterror.ErrPartitionMaxvalue)
@@ -549,9 +551,6 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 		ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrUnsupportedCreatePartition.FastGen(fmt.Sprintf("Unsupported partition type %v, treat as normal table", s.Tp)))
 		return nil
 	}
-	if s.Sub != nil {
-		ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrUnsupportedCreatePartition.FastGen(fmt.Sprintf("Unsupported subpartitioning, only using %v partitioning", s.Tp)))
-	}
 
 	pi := &model.PartitionInfo{
 		Type:   s.Tp,
@@ -591,6 +590,14 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 			return err
 		}
 	}
+	if s.Sub != nil {
+		if s.Interval != nil {
+			return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("INTERVAL partitioning with subpartitions")
+		}
+		if err := buildSubPartitionInfo(ctx, s.Sub, tbInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	exprCtx := ctx.GetExprCtx()
 	err := generatePartitionDefinitionsFromInterval(exprCtx, s, tbInfo)
@@ -629,12 +636,103 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 	for _, index := range tbInfo.Indices {
 		if index.Unique && !checkUniqueKeyIncludePartKey(partCols, index.Columns) {
 			index.Global = ctx.GetSessionVars().EnableGlobalIndex
+			if index.Global {
+				index.GlobalIndexVersion = model.GlobalIndexVersionV1
+			}
 		}
 	}
 	return nil
 }
 
+// buildSubPartitionInfo builds the HASH or KEY subpartitioning of a RANGE or
+// LIST partitioned table.
+func buildSubPartitionInfo(ctx sessionctx.Context, s *ast.PartitionMethod, tbInfo *model.TableInfo) error {
+	if s.Tp != model.PartitionTypeHash && s.Tp != model.PartitionTypeKey {
+		return ast.ErrSubpartition
+	}
+	if s.Linear {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrUnsupportedCreatePartition.FastGen(fmt.Sprintf("LINEAR %s is not supported, using non-linear %s instead", s.Tp.String(), s.Tp.String())))
+	}
+	sub := &model.SubPartitionInfo{
+		Type: s.Tp,
+		Num:  s.Num,
+	}
+	if sub.Num == 0 {
+		sub.Num = 1
+	}
+	if s.Expr != nil {
+		if err := checkPartitionFuncValid(ctx.GetExprCtx(), tbInfo, s.Expr); err != nil {
+			return errors.Trace(err)
+		}
+		buf := new(bytes.Buffer)
+		restoreFlags := format.DefaultRestoreFlags | format.RestoreBracketAroundBinaryOperation |
+			format.RestoreWithoutSchemaName | format.RestoreWithoutTableName
+		restoreCtx := format.NewRestoreCtx(restoreFlags, buf)
+		if err := s.Expr.Restore(restoreCtx); err != nil {
+			return err
+		}
+		sub.Expr = buf.String()
+	} else {
+		if len(s.ColumnNames) == 0 {
+			return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("SUBPARTITION BY KEY without columns")
+		}
+		sub.Columns = make([]model.CIStr, 0, len(s.ColumnNames))
+		for _, cn := range s.ColumnNames {
+			colInfo := tbInfo.FindPublicColumnByName(cn.Name.L)
+			if colInfo == nil {
+				return errors.Trace(dbterror.ErrFieldNotFoundPart)
+			}
+			if !isColTypeAllowedAsPartitioningCol(model.PartitionTypeKey, colInfo.FieldType) {
+				return dbterror.ErrNotAllowedTypeInPartition.GenWithStackByArgs(cn.Name.O)
+			}
+			sub.Columns = append(sub.Columns, cn.Name)
+		}
+	}
+	tbInfo.Partition.Sub = sub
+	return nil
+}
+
+// getTopLevelPartitionTableInfo returns a copy of tblInfo partitioned by its
+// partitions instead of its subpartitions, used for checking the partition
+// bounds of a subpartitioned table.
+func getTopLevelPartitionTableInfo(tblInfo *model.TableInfo) *model.TableInfo {
+	if tblInfo.Partition == nil || tblInfo.Partition.Sub == nil {
+		return tblInfo
+	}
+	newTblInfo := *tblInfo
+	pi := *tblInfo.Partition
+	pi.Definitions = pi.TopLevelDefinitions(pi.Definitions)
+	pi.AddingDefinitions = pi.TopLevelDefinitions(pi.AddingDefinitions)
+	pi.DroppingDefinitions = pi.TopLevelDefinitions(pi.DroppingDefinitions)
+	pi.Num = uint64(len(pi.Definitions))
+	pi.Sub = nil
+	newTblInfo.Partition = &pi
+	return &newTblInfo
+}
+
+// setSubPartitionBounds copies the partition bounds, which may have been
+// simplified by the checks, back to the subpartitions.
+func setSubPartitionBounds(pi *model.PartitionInfo, partitions []model.PartitionDefinition) {
+	num := int(pi.Sub.Num)
+	for i := range pi.Definitions {
+		pi.Definitions[i].LessThan = partitions[i/num].LessThan
+		pi.Definitions[i].InValues = partitions[i/num].InValues
+	}
+}
+
 func getPartitionColSlices(sctx expression.BuildContext, tblInfo *model.TableInfo, s *ast.PartitionOptions) (partCols stringSlice, err error) {
+	partCols, err = getPartitionMethodColSlices(sctx, tblInfo, &s.PartitionMethod)
+	if err != nil || s.Sub == nil {
+		return partCols, err
+	}
+	subCols, err := getPartitionMethodColSlices(sctx, tblInfo, s.Sub)
+	if err != nil {
+		return nil, err
+	}
+	return multiStringSlice{partCols, subCols}, nil
+}
+
+func getPartitionMethodColSlices(sctx expression.BuildContext, tblInfo *model.TableInfo, s *ast.PartitionMethod) (partCols stringSlice, err error) {
 	if s.Expr != nil {
 		extractCols := newPartitionExprChecker(sctx, tblInfo)
 		s.Expr.Accept(extractCols)
@@ -663,7 +761,8 @@ func getPartitionColSlices(sctx expression.BuildContext, tblInfo *model.TableInf
 // will return nil if error occurs, i.e. not an INTERVAL partitioned table
 func getPartitionIntervalFromTable(ctx expression.BuildContext, tbInfo *model.TableInfo) *ast.PartitionInterval {
 	if tbInfo.Partition == nil ||
-		tbInfo.Partition.Type != model.PartitionTypeRange {
+		tbInfo.Partition.Type != model.PartitionTypeRange ||
+		tbInfo.Partition.Sub != nil {
 		return nil
 	}
 	if len(tbInfo.Partition.Columns) > 1 {
@@ -1231,9 +1330,48 @@ func buildPartitionDefinitionsInfo(ctx expression.BuildContext, defs []*ast.Part
 		return nil, err
 	}
 
+	if tbInfo.Partition.Sub != nil {
+		return buildSubPartitionDefinitions(defs, partitions, tbInfo.Partition.Sub)
+	}
 	return partitions, nil
 }
 
+// buildSubPartitionDefinitions expands each partition definition into
+// sub.Num subpartition definitions, which share its bounds and inherit its
+// comment and placement unless given explicitly for the subpartition.
+func buildSubPartitionDefinitions(defs []*ast.PartitionDefinition, partitions []model.PartitionDefinition, sub *model.SubPartitionInfo) ([]model.PartitionDefinition, error) {
+	num := int(sub.Num)
+	subPartitions := make([]model.PartitionDefinition, 0, len(partitions)*num)
+	for i := range partitions {
+		var subDefs []*ast.SubPartitionDefinition
+		if i < len(defs) {
+			subDefs = defs[i].Sub
+		}
+		if len(subDefs) > 0 && len(subDefs) != num {
+			return nil, errors.Trace(ast.ErrPartitionWrongNoSubpart)
+		}
+		for j := 0; j < num; j++ {
+			def := partitions[i]
+			def.ParentName = partitions[i].Name
+			if len(subDefs) == 0 {
+				def.Name = model.NewCIStr(fmt.Sprintf("%ssp%d", partitions[i].Name.O, j))
+			} else {
+				def.Name = subDefs[j].Name
+				for _, opt := range subDefs[j].Options {
+					if opt.Tp == ast.TableOptionComment {
+						def.Comment = opt.StrValue
+					}
+				}
+				if err := setPartitionPlacementFromOptions(&def, subDefs[j].Options); err != nil {
+					return nil, err
+				}
+			}
+			subPartitions = append(subPartitions, def)
+		}
+	}
+	return subPartitions, nil
+}
+
 func setPartitionPlacementFromOptions(partition *model.PartitionDefinition, options []*ast.TableOption) error {
 	// the partition inheritance of placement rules don't have to copy the placement elements to themselves.
 	// For example:
@@ -1509,14 +1647,29 @@ func checkPartitionValuesIsInt(ctx expression.BuildContext, defName any, exprs [
 	return nil
 }
 
+// getPartitionDefinitionNames returns the names of the partition definitions,
+// followed by the names of the partitions if they are subpartitions.
+func getPartitionDefinitionNames(pi *model.PartitionInfo) []model.CIStr {
+	names := make([]model.CIStr, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		names = append(names, def.Name)
+	}
+	if pi.Sub != nil {
+		for _, def := range pi.TopLevelDefinitions(pi.Definitions) {
+			names = append(names, def.Name)
+		}
+	}
+	return names
+}
+
 func checkPartitionNameUnique(pi *model.PartitionInfo) error {
-	newPars := pi.Definitions
+	newPars := getPartitionDefinitionNames(pi)
 	partNames := make(map[string]struct{}, len(newPars))
 	for _, newPar := range newPars {
-		if _, ok := partNames[newPar.Name.L]; ok {
-			return dbterror.ErrSameNamePartition.GenWithStackByArgs(newPar.Name)
+		if _, ok := partNames[newPar.L]; ok {
+			return dbterror.ErrSameNamePartition.GenWithStackByArgs(newPar)
 		}
-		partNames[newPar.Name.L] = struct{}{}
+		partNames[newPar.L] = struct{}{}
 	}
 	return nil
 }
@@ -1524,17 +1677,17 @@ func checkPartitionNameUnique(pi *model.PartitionInfo) error {
 func checkAddPartitionNameUnique(tbInfo *model.TableInfo, pi *model.PartitionInfo) error {
 	partNames := make(map[string]struct{})
 	if tbInfo.Partition != nil {
-		oldPars := tbInfo.Partition.Definitions
+		oldPars := getPartitionDefinitionNames(tbInfo.Partition)
 		for _, oldPar := range oldPars {
-			partNames[oldPar.Name.L] = struct{}{}
+			partNames[oldPar.L] = struct{}{}
 		}
 	}
-	newPars := pi.Definitions
+	newPars := getPartitionDefinitionNames(pi)
 	for _, newPar := range newPars {
-		if _, ok := partNames[newPar.Name.L]; ok {
-			return dbterror.ErrSameNamePartition.GenWithStackByArgs(newPar.Name)
+		if _, ok := partNames[newPar.L]; ok {
+			return dbterror.ErrSameNamePartition.GenWithStackByArgs(newPar)
 		}
-		partNames[newPar.Name.L] = struct{}{}
+		partNames[newPar.L] = struct{}{}
 	}
 	return nil
 }
@@ -1828,6 +1981,32 @@ func getRangeValue(ctx expression.BuildContext, str string, unsigned bool) (any,
 	return 0, false, dbterror.ErrNotAllowedTypeInPartition.GenWithStackByArgs(str)
 }
 
+// getDroppedPartitionNames returns the lower case names of the partitions to
+// drop. For a subpartitioned table these are the names of their subpartitions,
+// since a subpartition cannot be dropped on its own.
+func getDroppedPartitionNames(pi *model.PartitionInfo, names []model.CIStr) ([]string, error) {
+	partNames := make([]string, 0, len(names))
+	if pi.Sub == nil {
+		for _, name := range names {
+			partNames = append(partNames, name.L)
+		}
+		return partNames, nil
+	}
+	for _, name := range names {
+		found := false
+		for i := range pi.Definitions {
+			if pi.Definitions[i].ParentName.L == name.L {
+				partNames = append(partNames, pi.Definitions[i].Name.L)
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.Trace(dbterror.ErrDropPartitionNonExistent.GenWithStackByArgs("DROP"))
+		}
+	}
+	return partNames, nil
+}
+
 // CheckDropTablePartition checks if the partition exists and does not allow deleting the last existing partition in the table.
 func CheckDropTablePartition(meta *model.TableInfo, partLowerNames []string) error {
 	pi := meta.Partition
@@ -1918,6 +2097,94 @@ func hasGlobalIndex(tblInfo *model.TableInfo) bool {
 	return false
 }
 
+// addChangedGlobalIndexes adds a new index in StateDeleteOnly for each public global index.
+// The new index is built from the new set of partitions and replaces the old one,
+// PartitionInfo.DDLChangedIndex maps the new index IDs to true and the old ones to false.
+func addChangedGlobalIndexes(tblInfo *model.TableInfo) {
+	pi := tblInfo.Partition
+	var newIndices []*model.IndexInfo
+	for _, idxInfo := range tblInfo.Indices {
+		if !idxInfo.Global || idxInfo.State != model.StatePublic {
+			continue
+		}
+		if pi.DDLChangedIndex == nil {
+			pi.DDLChangedIndex = make(map[int64]bool)
+		}
+		newIdxInfo := idxInfo.Clone()
+		newIdxInfo.ID = AllocateIndexID(tblInfo)
+		newIdxInfo.Name = model.NewCIStr(genChangingIndexUniqueName(tblInfo, idxInfo))
+		newIdxInfo.State = model.StateDeleteOnly
+		// The new index is built from scratch, so it can use the latest encoding.
+		newIdxInfo.GlobalIndexVersion = model.GlobalIndexVersionV1
+		pi.DDLChangedIndex[idxInfo.ID] = false
+		pi.DDLChangedIndex[newIdxInfo.ID] = true
+		newIndices = append(newIndices, newIdxInfo)
+	}
+	tblInfo.Indices = append(tblInfo.Indices, newIndices...)
+}
+
+// setChangedGlobalIndexesState sets the state of the new global indexes added by addChangedGlobalIndexes.
+func setChangedGlobalIndexesState(tblInfo *model.TableInfo, state model.SchemaState) {
+	for _, idxInfo := range tblInfo.Indices {
+		if tblInfo.Partition.DDLChangedIndex[idxInfo.ID] {
+			idxInfo.State = state
+		}
+	}
+}
+
+// switchChangedGlobalIndexes makes the new global indexes public under the names of
+// the indexes they replace, the old indexes are kept in StateWriteOnly until removed.
+func switchChangedGlobalIndexes(tblInfo *model.TableInfo) {
+	pi := tblInfo.Partition
+	for _, newIdxInfo := range tblInfo.Indices {
+		if !pi.DDLChangedIndex[newIdxInfo.ID] || newIdxInfo.State == model.StatePublic {
+			continue
+		}
+		originName := strings.ToLower(getChangingIndexOriginName(newIdxInfo))
+		for _, oldIdxInfo := range tblInfo.Indices {
+			if isNew, ok := pi.DDLChangedIndex[oldIdxInfo.ID]; !ok || isNew || oldIdxInfo.Name.L != originName {
+				continue
+			}
+			oldIdxInfo.Name, newIdxInfo.Name = newIdxInfo.Name, oldIdxInfo.Name
+			oldIdxInfo.State = model.StateWriteOnly
+			newIdxInfo.State = model.StatePublic
+			break
+		}
+	}
+}
+
+// removeChangedGlobalIndexes removes either the new or the replaced global indexes,
+// clears PartitionInfo.DDLChangedIndex and returns the IDs of the removed indexes.
+func removeChangedGlobalIndexes(tblInfo *model.TableInfo, removeNew bool) []int64 {
+	pi := tblInfo.Partition
+	if pi == nil || len(pi.DDLChangedIndex) == 0 {
+		return nil
+	}
+	var removedIDs []int64
+	indices := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+	for _, idxInfo := range tblInfo.Indices {
+		if isNew, ok := pi.DDLChangedIndex[idxInfo.ID]; ok && isNew == removeNew {
+			removedIDs = append(removedIDs, idxInfo.ID)
+			continue
+		}
+		indices = append(indices, idxInfo)
+	}
+	tblInfo.Indices = indices
+	pi.DDLChangedIndex = nil
+	return removedIDs
+}
+
+// getChangedGlobalIndexElements returns the elements of the new global indexes added by addChangedGlobalIndexes.
+func getChangedGlobalIndexElements(tblInfo *model.TableInfo) []*meta.Element {
+	var elements []*meta.Element
+	for _, idxInfo := range tblInfo.Indices {
+		if tblInfo.Partition.DDLChangedIndex[idxInfo.ID] {
+			elements = append(elements, &meta.Element{ID: idxInfo.ID, TypeKey: meta.IndexElementKey})
+		}
+	}
+	return elements
+}
+
 // getTableInfoWithDroppingPartitions builds oldTableInfo including dropping partitions, only used by onDropTablePartition.
 func getTableInfoWithDroppingPartitions(t *model.TableInfo) *model.TableInfo {
 	p := t.Partition
@@ -2004,6 +2271,8 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 			job.State = model.JobStateCancelled
 			return ver, err
 		}
+		// Remove the global indexes that were built for the new partitions.
+		globalIndexIDs := removeChangedGlobalIndexes(tblInfo, true)
 		// ALTER TABLE ... PARTITION BY
 		if partInfo.Type != model.PartitionTypeNone {
 			// Also remove anything with the new table id
@@ -2025,7 +2294,7 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 			return ver, errors.Trace(err)
 		}
 		job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
-		job.Args = []any{physicalTableIDs}
+		job.Args = []any{physicalTableIDs, &model.PartitionInfo{}, globalIndexIDs}
 		return ver, nil
 	}
 
@@ -2496,6 +2765,11 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 		return ver, errors.Trace(err)
 	}
 
+	if job.SchemaState == model.StateDeleteReorganization {
+		// The partition is already exchanged, job.TableID is now a partition ID.
+		return onExchangeTablePartitionRemoveGlobalIndexes(d, t, job, ptSchemaID, ptID)
+	}
+
 	ntDbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
 	if err != nil {
 		job.State = model.JobStateCancelled
@@ -2562,6 +2836,28 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 			}
 		}
 		var ptInfo []schemaIDAndTableInfo
+		if hasGlobalIndex(pt) {
+			// The global indexes are replaced by new ones, which have the entries
+			// of the non-partitioned table instead of the exchanged partition.
+			// Writes to both are rejected until the exchange is done.
+			pt.ExchangePartitionInfo = &model.ExchangePartitionInfo{
+				ExchangePartitionTableID: nt.ID,
+				ExchangePartitionDefID:   defID,
+				RejectWrites:             true,
+			}
+			addChangedGlobalIndexes(pt)
+			nt.ExchangePartitionInfo = &model.ExchangePartitionInfo{
+				ExchangePartitionTableID: ptID,
+				ExchangePartitionDefID:   defID,
+				RejectWrites:             true,
+			}
+			ptInfo = append(ptInfo, schemaIDAndTableInfo{
+				schemaID: ptSchemaID,
+				tblInfo:  pt,
+			})
+			job.SchemaState = model.StateDeleteOnly
+			return updateVersionAndTableInfoWithCheck(d, t, job, nt, true, ptInfo...)
+		}
 		if len(nt.Constraints) > 0 {
 			pt.ExchangePartitionInfo = &model.ExchangePartitionInfo{
 				ExchangePartitionTableID: nt.ID,
@@ -2589,6 +2885,22 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 	// partition to be exchange with.
 	// So we need to rollback that change, instead of just cancelling.
 
+	changedGlobalIndex := len(pt.Partition.DDLChangedIndex) > 0
+	if changedGlobalIndex {
+		ptInfo := []schemaIDAndTableInfo{{schemaID: ptSchemaID, tblInfo: pt}}
+		switch job.SchemaState {
+		case model.StateDeleteOnly:
+			setChangedGlobalIndexesState(pt, model.StateWriteOnly)
+			job.SchemaState = model.StateWriteOnly
+			return updateVersionAndTableInfo(d, t, job, nt, true, ptInfo...)
+		case model.StateWriteOnly:
+			setChangedGlobalIndexesState(pt, model.StateWriteReorganization)
+			job.SnapshotVer = 0
+			job.SchemaState = model.StateWriteReorganization
+			return updateVersionAndTableInfo(d, t, job, nt, true, ptInfo...)
+		}
+	}
+
 	if d.lease > 0 {
 		delayForAsyncCommit()
 	}
@@ -2621,6 +2933,14 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 		}
 	}
 
+	if changedGlobalIndex {
+		done, err := w.addExchangeGlobalIndexes(d, t, job, ptSchemaID, pt, nt.ID, defID)
+		if err != nil || !done {
+			return ver, errors.Trace(err)
+		}
+		switchChangedGlobalIndexes(pt)
+	}
+
 	// partition table auto IDs.
 	ptAutoIDs, err := t.GetAutoIDAccessors(ptSchemaID, ptID).Get()
 	if err != nil {
@@ -2755,7 +3075,12 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 		return ver, errors.Trace(err)
 	}
 
-	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, pt)
+	if changedGlobalIndex {
+		// The replaced global indexes are removed in the next step.
+		job.SchemaState = model.StateDeleteReorganization
+	} else {
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, pt)
+	}
 	exchangePartitionEvent := statsutil.NewExchangePartitionEvent(
 		job.SchemaID,
 		pt,
@@ -2766,6 +3091,90 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 	return ver, nil
 }
 
+// addExchangeGlobalIndexes builds the new global indexes of the partitioned table,
+// from all partitions except the exchanged one, and from the non-partitioned table.
+func (w *worker) addExchangeGlobalIndexes(d *ddlCtx, t *meta.Meta, job *model.Job, ptSchemaID int64, pt *model.TableInfo, ntID, defID int64) (done bool, err error) {
+	job.ReorgMeta.ReorgTp = model.ReorgTypeTxn
+	tbl, err := getTable((*asAutoIDRequirement)(d), ptSchemaID, pt)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	ptbl, ok := tbl.(table.PartitionedTable)
+	if !ok {
+		return false, errors.Trace(dbterror.ErrPartitionMgmtOnNonpartitioned)
+	}
+	dbInfo, err := t.GetDatabase(ptSchemaID)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	physicalTableIDs := make([]int64, 0, len(pt.Partition.Definitions))
+	for _, def := range pt.Partition.Definitions {
+		if def.ID != defID {
+			physicalTableIDs = append(physicalTableIDs, def.ID)
+		}
+	}
+	physicalTableIDs = append(physicalTableIDs, ntID)
+
+	sctx, err := w.sessPool.Get()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer w.sessPool.Put(sctx)
+	rh := newReorgHandler(sess.NewSession(sctx))
+	elements := getChangedGlobalIndexElements(pt)
+	reorgInfo, err := getReorgInfoFromPartitions(d.jobContext(job.ID, job.ReorgMeta), d, rh, job, dbInfo, ptbl, physicalTableIDs, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, errors.Trace(err)
+	}
+	err = w.runReorgJob(reorgInfo, pt, d.lease, func() (addIndexErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onExchangeTablePartition",
+			func() {
+				addIndexErr = dbterror.ErrCancelledDDLJob.GenWithStack("exchange partition panic")
+			}, false)
+		return w.addGlobalIndexesForPartitions(ptbl, physicalTableIDs, reorgInfo)
+	})
+	if err != nil {
+		if dbterror.ErrWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, nil
+		}
+		if kv.IsTxnRetryableError(err) || dbterror.ErrPausedDDLJob.Equal(err) {
+			return false, errors.Trace(err)
+		}
+		if err1 := rh.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("exchange partition job failed, RemoveDDLReorgHandle failed, can't convert job to rollback", zap.String("category", "ddl"),
+				zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("exchange partition job failed, convert job to rollback", zap.String("category", "ddl"), zap.String("job", job.String()), zap.Error(err))
+		job.State = model.JobStateRollingback
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// onExchangeTablePartitionRemoveGlobalIndexes removes the global indexes replaced during
+// the exchange partition, and finishes the job.
+func onExchangeTablePartitionRemoveGlobalIndexes(d *ddlCtx, t *meta.Meta, job *model.Job, ptSchemaID, ptID int64) (ver int64, err error) {
+	pt, err := getTableInfo(t, ptID, ptSchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	globalIndexIDs := removeChangedGlobalIndexes(pt, false)
+	ver, err = updateSchemaVersion(d, t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = updateTable(t, ptSchemaID, pt); err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, pt)
+	// A background job will be created to delete the replaced global indexes.
+	job.Args = append(job.Args, globalIndexIDs)
+	return ver, nil
+}
+
 func getReorgPartitionInfo(t *meta.Meta, job *model.Job) (*model.TableInfo, []string, *model.PartitionInfo, []model.PartitionDefinition, []model.PartitionDefinition, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, schemaID)
@@ -2859,6 +3268,11 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		_ = updateDroppingPartitionInfo(tblInfo, partNames)
 		// Reset original partitions, and keep DroppedDefinitions
 		tblInfo.Partition.Definitions = orgDefs
+		if job.Type == model.ActionReorganizePartition {
+			// The global indexes have entries of the dropped partitions,
+			// so they are replaced by new ones built from the new set of partitions.
+			addChangedGlobalIndexes(tblInfo)
+		}
 
 		// modify placement settings
 		for _, def := range tblInfo.Partition.AddingDefinitions {
@@ -2978,6 +3392,7 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		}
 
 		tblInfo.Partition.DDLState = model.StateWriteOnly
+		setChangedGlobalIndexesState(tblInfo, model.StateWriteOnly)
 		metrics.GetBackfillProgressByLabel(metrics.LblReorgPartition, job.SchemaName, tblInfo.Name.String()).Set(0.2 / float64(math.MaxUint64))
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
 		job.SchemaState = model.StateWriteOnly
@@ -2986,6 +3401,7 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		// so that new data will be updated in both old and new partitions when reorganizing.
 		job.SnapshotVer = 0
 		tblInfo.Partition.DDLState = model.StateWriteReorganization
+		setChangedGlobalIndexesState(tblInfo, model.StateWriteReorganization)
 		metrics.GetBackfillProgressByLabel(metrics.LblReorgPartition, job.SchemaName, tblInfo.Name.String()).Set(0.3 / float64(math.MaxUint64))
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
 		job.SchemaState = model.StateWriteReorganization
@@ -2995,10 +3411,8 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		if err2 != nil {
 			return ver, errors.Trace(err2)
 		}
-		// TODO: If table has global indexes, we need reorg to clean up them.
-		// and then add the new partition ids back...
-		if _, ok := tbl.(table.PartitionedTable); ok && hasGlobalIndex(tblInfo) {
-			err = errors.Trace(dbterror.ErrCancelledDDLJob.GenWithStack("global indexes is not supported yet for reorganize partition"))
+		if job.Type != model.ActionReorganizePartition && hasGlobalIndex(tblInfo) {
+			err = errors.Trace(dbterror.ErrCancelledDDLJob.GenWithStack("global indexes is not supported yet for alter table partitioning"))
 			return convertAddTablePartitionJob2RollbackJob(d, t, job, err, tblInfo)
 		}
 		var done bool
@@ -3033,6 +3447,7 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		// since they are a part of the normal Definitions that other nodes with
 		// the current schema version. So we need to double write for one more schema version
 		tblInfo.Partition.DDLState = model.StateDeleteReorganization
+		switchChangedGlobalIndexes(tblInfo)
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
 		job.SchemaState = model.StateDeleteReorganization
 
@@ -3056,6 +3471,7 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		tblInfo.Partition.DroppingDefinitions = nil
 		tblInfo.Partition.AddingDefinitions = nil
 		tblInfo.Partition.DDLState = model.StateNone
+		globalIndexIDs := removeChangedGlobalIndexes(tblInfo, false)
 
 		var oldTblID int64
 		if job.Type != model.ActionReorganizePartition {
@@ -3126,8 +3542,9 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 			return ver, errors.Trace(err)
 		}
 		asyncNotifyEvent(d, event)
-		// A background job will be created to delete old partition data.
-		job.Args = []any{physicalTableIDs}
+		// A background job will be created to delete old partition data,
+		// and the replaced global indexes.
+		job.Args = []any{physicalTableIDs, &model.PartitionInfo{}, globalIndexIDs}
 
 	default:
 		err = dbterror.ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
@@ -3183,7 +3600,15 @@ func doPartitionReorgWork(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job, tb
 	}
 	defer w.sessPool.Put(sctx)
 	rh := newReorgHandler(sess.NewSession(sctx))
-	elements := BuildElements(tbl.Meta().Columns[0], tbl.Meta().Indices)
+	// The replaced global indexes are not built for the new partitions.
+	indices := make([]*model.IndexInfo, 0, len(tbl.Meta().Indices))
+	for _, idxInfo := range tbl.Meta().Indices {
+		if isNew, ok := tbl.Meta().Partition.DDLChangedIndex[idxInfo.ID]; ok && !isNew {
+			continue
+		}
+		indices = append(indices, idxInfo)
+	}
+	elements := BuildElements(tbl.Meta().Columns[0], indices)
 	partTbl, ok := tbl.(table.PartitionedTable)
 	if !ok {
 		return false, ver, dbterror.ErrUnsupportedReorganizePartition.GenWithStackByArgs()
@@ -3248,7 +3673,7 @@ func newReorgPartitionWorker(sessCtx sessionctx.Context, i int, t table.Physical
 	maxOffset := 0
 	for _, id := range partColIDs {
 		var offset int
-		for _, col := range pt.Cols() {
+		for _, col := range pt.WritableCols() {
 			if col.ID == id {
 				offset = col.Offset
 				break
@@ -3432,11 +3857,29 @@ func (w *worker) reorgPartitionDataAndIndex(t table.Table, reorgInfo *reorgInfo)
 	// Rewrite this to do all indexes at once in addTableIndex
 	// instead of calling it once per index (meaning reading the table multiple times)
 	// But for now, try to understand how it works...
-	firstNewPartitionID := t.Meta().Partition.AddingDefinitions[0].ID
+	pi := t.Meta().Partition
+	firstNewPartitionID := pi.AddingDefinitions[0].ID
+	// The new global indexes also need the entries of the partitions that are not reorganized.
+	unchangedPartIDs := make([]int64, 0, len(pi.Definitions))
+	if len(pi.DDLChangedIndex) > 0 {
+		droppingIDs := make(map[int64]struct{}, len(pi.DroppingDefinitions))
+		for _, def := range pi.DroppingDefinitions {
+			droppingIDs[def.ID] = struct{}{}
+		}
+		for _, def := range pi.Definitions {
+			if _, ok := droppingIDs[def.ID]; !ok {
+				unchangedPartIDs = append(unchangedPartIDs, def.ID)
+			}
+		}
+	}
 	startElementOffset := 0
 	//startElementOffsetToResetHandle := -1
 	// This backfill job starts with backfilling index data, whose index ID is currElement.ID.
-	if !bytes.Equal(reorgInfo.currElement.TypeKey, meta.IndexElementKey) {
+	if slices.Contains(unchangedPartIDs, reorgInfo.PhysicalTableID) {
+		// The job was interrupted and has been restarted during
+		// building the global indexes for the unchanged partitions.
+		startElementOffset = len(reorgInfo.elements[1:])
+	} else if !bytes.Equal(reorgInfo.currElement.TypeKey, meta.IndexElementKey) {
 		// First run, have not yet started backfilling index data
 		// Restart with the first new partition.
 		// TODO: handle remove partitioning
@@ -3499,6 +3942,11 @@ func (w *worker) reorgPartitionDataAndIndex(t table.Table, reorgInfo *reorgInfo)
 		}
 		reorgInfo.PhysicalTableID = firstNewPartitionID
 	}
+	if len(unchangedPartIDs) > 0 {
+		if err := w.addGlobalIndexesForPartitions(t, unchangedPartIDs, reorgInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	failpoint.Inject("reorgPartitionAfterIndex", func(val failpoint.Value) {
 		//nolint:forcetypeassert
 		if val.(bool) {
@@ -3599,6 +4047,13 @@ func checkExchangePartitionRecordValidation(w *worker, ptbl, ntbl table.Table, p
 	checkNt := true
 
 	pi := pt.Partition
+	subIndex := 0
+	if pi.Sub != nil {
+		// Check the partition bounds on the partition level first,
+		// and then the subpartition.
+		index, subIndex = index/int(pi.Sub.Num), index%int(pi.Sub.Num)
+		pi = getTopLevelPartitionTableInfo(pt).Partition
+	}
 	switch pi.Type {
 	case model.PartitionTypeHash:
 		if pi.Num == 1 {
@@ -3644,6 +4099,26 @@ func checkExchangePartitionRecordValidation(w *worker, ptbl, ntbl table.Table, p
 	default:
 		return dbterror.ErrUnsupportedPartitionType.GenWithStackByArgs(pt.Name.O)
 	}
+	if sub := pt.Partition.Sub; sub != nil && sub.Num > 1 {
+		if sub.Type != model.PartitionTypeHash {
+			return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("EXCHANGE PARTITION WITH VALIDATION of KEY subpartitions")
+		}
+		if !checkNt {
+			checkNt = true
+		} else {
+			buf.WriteString(" or ")
+		}
+		buf.WriteString("mod(")
+		buf.WriteString(sub.Expr)
+		buf.WriteString(", %?) != %?")
+		paramList = append(paramList, sub.Num, subIndex)
+		if subIndex != 0 {
+			buf.WriteString(" or mod(")
+			buf.WriteString(sub.Expr)
+			buf.WriteString(", %?) is null")
+			paramList = append(paramList, sub.Num)
+		}
+	}
 
 	if variable.EnableCheckConstraint.Load() {
 		pcc, ok := ptbl.(CheckConstraintTable)
@@ -3845,6 +4320,166 @@ func checkNoHashPartitions(_ sessionctx.Context, partitionNum uint64) error {
 	return nil
 }
 
+// checkModifyPartitioningColumn checks whether the column of the partitioned table can be modified from oldCol to
+// newCol if it's a partitioning column, the partition definitions are re-validated with the new column definition.
+// Since the rows may be located in other partitions by the new column definition, they have to be moved to new
+// partitions like REORGANIZE PARTITION does, the new partition definitions are returned in this case.
+func checkModifyPartitioningColumn(sctx sessionctx.Context, t table.Table, oldCol, newCol *model.ColumnInfo) ([]model.PartitionDefinition, error) {
+	tblInfo := t.Meta()
+	pi := tblInfo.GetPartitionInfo()
+	pt, ok := t.(table.PartitionedTable)
+	if !ok {
+		// Should never happen!
+		return nil, dbterror.ErrNotAllowedTypeInPartition.GenWithStackByArgs(newCol.Name.O)
+	}
+	isPartitioningColumn := false
+	for _, name := range pt.GetPartitionColumnNames() {
+		if strings.EqualFold(name.L, oldCol.Name.L) {
+			isPartitioningColumn = true
+			break
+		}
+	}
+	if !isPartitioningColumn {
+		return nil, nil
+	}
+	// TODO: update the partitioning columns with new names if column is renamed
+	// Would be an extension from MySQL which does not support it.
+	if oldCol.Name.L != newCol.Name.L {
+		return nil, dbterror.ErrDependentByPartitionFunctional.GenWithStackByArgs(oldCol.Name.L)
+	}
+	if !isColTypeAllowedAsPartitioningCol(pi.Type, newCol.FieldType) {
+		return nil, dbterror.ErrNotAllowedTypeInPartition.GenWithStackByArgs(newCol.Name.O)
+	}
+	if len(pi.Columns) == 0 {
+		// non COLUMNS partitioning, only checks INTs, not their actual range
+		// There are many edge cases, like when truncating SQL Mode is allowed
+		// which will change the partitioning expression value resulting in a
+		// different partition. Better be safe and not allow decreasing of length.
+		// TODO: Should we allow it in strict mode? Wait for a use case / request.
+		if newCol.FieldType.GetFlen() < oldCol.FieldType.GetFlen() {
+			return nil, dbterror.ErrUnsupportedModifyCollation.GenWithStack("Unsupported modify column, decreasing length of int may result in truncation and change of partition")
+		}
+	}
+	needReorg := isPartitioningValueChanged(oldCol, newCol)
+	if needReorg {
+		if err := checkReorgPartitionForModifyColumn(tblInfo, oldCol, newCol); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	// Generate a new PartitionInfo and validate it together with the new column definition
+	// Checks if all partition definition values are compatible.
+	// Similar to what buildRangePartitionDefinitions would do in terms of checks.
+	newTblInfo := *tblInfo
+	// Replace col with newCol and see if we can generate a new SHOW CREATE TABLE
+	// and reparse it and build new partition definitions (which will do additional
+	// checks columns vs partition definition values
+	newCols := make([]*model.ColumnInfo, 0, len(newTblInfo.Columns))
+	for _, c := range newTblInfo.Columns {
+		if c.ID == oldCol.ID {
+			newCols = append(newCols, newCol)
+			continue
+		}
+		newCols = append(newCols, c)
+	}
+	newTblInfo.Columns = newCols
+
+	var buf bytes.Buffer
+	AppendPartitionInfo(pi, &buf, mysql.ModeNone)
+	// The parser supports ALTER TABLE ... PARTITION BY ... even if the ddl code does not yet :)
+	// Ignoring warnings
+	stmt, _, err := parser.New().ParseSQL("ALTER TABLE t " + buf.String())
+	if err != nil {
+		// Should never happen!
+		return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStack("cannot parse generated PartitionInfo")
+	}
+	at, ok := stmt[0].(*ast.AlterTableStmt)
+	if !ok || len(at.Specs) != 1 || at.Specs[0].Partition == nil {
+		return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStack("cannot parse generated PartitionInfo")
+	}
+	pAst := at.Specs[0].Partition
+	sv := sctx.GetSessionVars().StmtCtx
+	oldTypeFlags := sv.TypeFlags()
+	newTypeFlags := oldTypeFlags.WithTruncateAsWarning(false).WithIgnoreTruncateErr(false)
+	sv.SetTypeFlags(newTypeFlags)
+	defs, err := buildPartitionDefinitionsInfo(sctx.GetExprCtx(), pAst.Definitions, &newTblInfo, uint64(len(pi.Definitions)))
+	if err == nil && needReorg {
+		// The values may be ordered differently with the new column definition.
+		newTblInfo.Partition = pi.Clone()
+		newTblInfo.Partition.Definitions = defs
+		err = checkPartitionDefinitionConstraints(sctx, &newTblInfo)
+	}
+	sv.SetTypeFlags(oldTypeFlags)
+	if err != nil {
+		return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStack("New column does not match partition definitions: %s", err.Error())
+	}
+	if !needReorg {
+		return nil, nil
+	}
+	return defs, nil
+}
+
+// isPartitioningValueChanged checks whether the values of the partitioning column may be placed in different
+// partitions after the column is modified. Changes of the length/decimals keep the rows in their partitions, while
+// the values are compared or hashed differently if the type, sign or collation is changed.
+// Note that enum is not allowed, so elems are not checked
+// TODO: support partition by ENUM
+func isPartitioningValueChanged(oldCol, newCol *model.ColumnInfo) bool {
+	return newCol.FieldType.EvalType() != oldCol.FieldType.EvalType() ||
+		mysql.HasUnsignedFlag(newCol.GetFlag()) != mysql.HasUnsignedFlag(oldCol.GetFlag()) ||
+		(newCol.GetType() == mysql.TypeTimestamp) != (oldCol.GetType() == mysql.TypeTimestamp) ||
+		newCol.FieldType.GetCollate() != oldCol.FieldType.GetCollate() ||
+		newCol.FieldType.GetCharset() != oldCol.FieldType.GetCharset()
+}
+
+// needReorgPartitionForModifyColumn checks whether the rows have to be moved to new partitions when the column is
+// modified, that is the column is a partitioning column and its values may be placed in different partitions.
+func needReorgPartitionForModifyColumn(tblInfo *model.TableInfo, oldCol, newCol *model.ColumnInfo) bool {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil || !isPartitioningValueChanged(oldCol, newCol) {
+		return false
+	}
+	if len(pi.Columns) > 0 {
+		return slices.ContainsFunc(pi.Columns, func(name model.CIStr) bool {
+			return name.L == oldCol.Name.L
+		})
+	}
+	cols, err := extractPartitionColumns(pi.Expr, tblInfo)
+	if err != nil {
+		// Be safe to move the rows if the expression can't be parsed.
+		return true
+	}
+	return slices.ContainsFunc(cols, func(col *model.ColumnInfo) bool {
+		return col.ID == oldCol.ID
+	})
+}
+
+// checkReorgPartitionForModifyColumn checks whether the rows can be moved to new partitions when the partitioning
+// column is modified.
+func checkReorgPartitionForModifyColumn(tblInfo *model.TableInfo, oldCol, newCol *model.ColumnInfo) error {
+	pi := tblInfo.Partition
+	unsupported := func(reason string) error {
+		return dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs(
+			fmt.Sprintf("can't change the partitioning column of a table %s, since it would require reorganize all partitions", reason))
+	}
+	switch {
+	case pi.Sub != nil:
+		return unsupported("with subpartitions")
+	case hasGlobalIndex(tblInfo):
+		return unsupported("with global indexes")
+	case tblInfo.TiFlashReplica != nil:
+		return unsupported("with TiFlash replicas")
+	case tblInfo.PlacementPolicyRef != nil:
+		return unsupported("with placement policies")
+	}
+	for _, def := range pi.Definitions {
+		if def.PlacementPolicyRef != nil {
+			return unsupported("with placement policies")
+		}
+	}
+	return nil
+}
+
 func getPartitionIDs(table *model.TableInfo) []int64 {
 	if table.GetPartitionInfo() == nil {
 		return []int64{}
@@ -3882,6 +4517,8 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 	// Checks that the partitioning key is included in the constraint.
 	// Every unique key on the table must use every column in the table's partitioning expression.
 	// See https://dev.mysql.com/doc/refman/5.7/en/partitioning-limitations-partitioning-keys-unique-keys.html
+	// A unique index that does not satisfy the constraint must be a global index,
+	// which is chosen either explicitly by GLOBAL or implicitly by tidb_enable_global_index.
 	for _, index := range tblInfo.Indices {
 		if index.Unique && !checkUniqueKeyIncludePartKey(partCols, index.Columns) {
 			if index.Primary {
@@ -3889,11 +4526,11 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 				if tblInfo.IsCommonHandle {
 					return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("CLUSTERED INDEX")
 				}
-				if !sctx.GetSessionVars().EnableGlobalIndex {
+				if !index.Global {
 					return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
 				}
 			}
-			if !sctx.GetSessionVars().EnableGlobalIndex {
+			if !index.Global {
 				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
 			}
 		}
@@ -3911,6 +4548,94 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 	return nil
 }
 
+// getIndexScope returns the GLOBAL / LOCAL option of an index, if any.
+func getIndexScope(indexOption *ast.IndexOption) ast.IndexScope {
+	if indexOption == nil {
+		return ast.IndexScopeDefault
+	}
+	return indexOption.Scope
+}
+
+// decideIndexGlobal decides whether an index on a partitioned table is a global index.
+// An explicit GLOBAL always makes a global index, even for non-unique indexes or unique
+// indexes covering all the partitioning columns, and an explicit LOCAL never does.
+// Otherwise a unique index not covering all the partitioning columns is made global
+// only if tidb_enable_global_index is set.
+func decideIndexGlobal(sctx sessionctx.Context, scope ast.IndexScope, unique, inclPartCols bool, indexTp string) (bool, error) {
+	switch scope {
+	case ast.IndexScopeGlobal:
+		return true, nil
+	case ast.IndexScopeLocal:
+		if unique && !inclPartCols {
+			return false, dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs(indexTp)
+		}
+		return false, nil
+	}
+	if unique && !inclPartCols {
+		if !sctx.GetSessionVars().EnableGlobalIndex {
+			return false, dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs(indexTp)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// checkGlobalIndexType checks whether the kind of index can be a global index.
+func checkGlobalIndexType(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) error {
+	switch {
+	case idxInfo.Primary && tblInfo.HasClusteredIndex():
+		return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("CLUSTERED INDEX")
+	case idxInfo.FullTextInfo != nil:
+		return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("FULLTEXT index")
+	case idxInfo.VectorInfo != nil:
+		return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("VECTOR index")
+	case idxInfo.MVIndex:
+		return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("multi-valued index")
+	}
+	return nil
+}
+
+// setIndexScopesForCreateTable applies the explicit GLOBAL / LOCAL options of the
+// index constraints of CREATE TABLE, overriding the implicit choice made when
+// building the partition info.
+func setIndexScopesForCreateTable(tbInfo *model.TableInfo, constraints []*ast.Constraint) error {
+	for _, constr := range constraints {
+		scope := getIndexScope(constr.Option)
+		if scope == ast.IndexScopeDefault {
+			continue
+		}
+		global := scope == ast.IndexScopeGlobal
+		if global && tbInfo.Partition == nil {
+			return dbterror.ErrGlobalIndexOnNonPartitionedTable
+		}
+		var idxInfo *model.IndexInfo
+		if constr.Tp == ast.ConstraintPrimaryKey {
+			if tbInfo.PKIsHandle {
+				if global {
+					return dbterror.ErrUnsupportedGlobalIndex.GenWithStackByArgs("CLUSTERED INDEX")
+				}
+				continue
+			}
+			idxInfo = tbInfo.GetPrimaryKey()
+		} else {
+			idxInfo = tbInfo.FindIndexByName(strings.ToLower(constr.Name))
+		}
+		if idxInfo == nil {
+			continue
+		}
+		if global {
+			if err := checkGlobalIndexType(tbInfo, idxInfo); err != nil {
+				return err
+			}
+		}
+		idxInfo.Global = global
+		if global {
+			idxInfo.GlobalIndexVersion = model.GlobalIndexVersionV1
+		}
+	}
+	return nil
+}
+
 func checkPartitionKeysConstraint(pi *model.PartitionInfo, indexColumns []*model.IndexColumn, tblInfo *model.TableInfo) (bool, error) {
 	var (
 		partCols []*model.ColumnInfo
@@ -3925,14 +4650,22 @@ func checkPartitionKeysConstraint(pi *model.PartitionInfo, indexColumns []*model
 			return false, err
 		}
 	} else {
-		partCols = make([]*model.ColumnInfo, 0, len(pi.Columns))
-		for _, col := range pi.Columns {
-			colInfo := tblInfo.FindPublicColumnByName(col.L)
-			if colInfo == nil {
-				return false, infoschema.ErrColumnNotExists.GenWithStackByArgs(col, tblInfo.Name)
-			}
-			partCols = append(partCols, colInfo)
+		partCols, err = findPartitionColumns(pi.Columns, tblInfo)
+		if err != nil {
+			return false, err
+		}
+	}
+	if pi.Sub != nil {
+		var subCols []*model.ColumnInfo
+		if pi.Sub.Expr != "" {
+			subCols, err = extractPartitionColumns(pi.Sub.Expr, tblInfo)
+		} else {
+			subCols, err = findPartitionColumns(pi.Sub.Columns, tblInfo)
+		}
+		if err != nil {
+			return false, err
 		}
+		partCols = append(partCols, subCols...)
 	}
 
 	// In MySQL, every unique key on the table must use every column in the table's partitioning expression.(This
@@ -3942,6 +4675,18 @@ func checkPartitionKeysConstraint(pi *model.PartitionInfo, indexColumns []*model
 	return checkUniqueKeyIncludePartKey(columnInfoSlice(partCols), indexColumns), nil
 }
 
+func findPartitionColumns(cols []model.CIStr, tblInfo *model.TableInfo) ([]*model.ColumnInfo, error) {
+	partCols := make([]*model.ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		colInfo := tblInfo.FindPublicColumnByName(col.L)
+		if colInfo == nil {
+			return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(col, tblInfo.Name)
+		}
+		partCols = append(partCols, colInfo)
+	}
+	return partCols, nil
+}
+
 type columnNameExtractor struct {
 	extractedColumns []*model.ColumnInfo
 	tblInfo          *model.TableInfo
@@ -4029,6 +4774,28 @@ func (cis columnInfoSlice) At(i int) string {
 	return cis[i].Name.L
 }
 
+// multiStringSlice implements the stringSlice interface by concatenating
+// several stringSlices, like the partitioning and subpartitioning columns.
+type multiStringSlice []stringSlice
+
+func (mss multiStringSlice) Len() int {
+	l := 0
+	for _, ss := range mss {
+		l += ss.Len()
+	}
+	return l
+}
+
+func (mss multiStringSlice) At(i int) string {
+	for _, ss := range mss {
+		if i < ss.Len() {
+			return ss.At(i)
+		}
+		i -= ss.Len()
+	}
+	return ""
+}
+
 // columnNameSlice implements the stringSlice interface.
 type columnNameSlice []*ast.ColumnName
 
@@ -4352,20 +5119,60 @@ func AppendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer,
 			fmt.Fprintf(buf, "\nPARTITION BY %s COLUMNS(", partitionInfo.Type.String())
 		}
 		writeColumnListToBuffer(partitionInfo, sqlMode, buf)
-		buf.WriteString(")\n(")
+		buf.WriteString(")")
 	} else {
-		fmt.Fprintf(buf, "\nPARTITION BY %s (%s)\n(", partitionInfo.Type.String(), partitionInfo.Expr)
+		fmt.Fprintf(buf, "\nPARTITION BY %s (%s)", partitionInfo.Type.String(), partitionInfo.Expr)
+	}
+	if sub := partitionInfo.Sub; sub != nil {
+		if sub.Type == model.PartitionTypeHash {
+			fmt.Fprintf(buf, "\nSUBPARTITION BY HASH (%s)", sub.Expr)
+		} else {
+			buf.WriteString("\nSUBPARTITION BY KEY (")
+			for i, col := range sub.Columns {
+				if i > 0 {
+					buf.WriteString(",")
+				}
+				buf.WriteString(stringutil.Escape(col.O, sqlMode))
+			}
+			buf.WriteString(")")
+		}
+		if !isNonDefaultSubPartitionOptionsUsed(partitionInfo) {
+			fmt.Fprintf(buf, "\nSUBPARTITIONS %d", sub.Num)
+		}
 	}
+	buf.WriteString("\n(")
 
 	AppendPartitionDefs(partitionInfo, buf, sqlMode)
 	buf.WriteString(")")
 }
 
+// isNonDefaultSubPartitionOptionsUsed checks if any subpartition has a non
+// default name, or options different from the other subpartitions of its
+// partition, so the subpartitions must be listed explicitly.
+func isNonDefaultSubPartitionOptionsUsed(pi *model.PartitionInfo) bool {
+	num := int(pi.Sub.Num)
+	for i := range pi.Definitions {
+		def, first := &pi.Definitions[i], &pi.Definitions[i-i%num]
+		if def.Name.O != fmt.Sprintf("%ssp%d", def.ParentName.O, i%num) {
+			return true
+		}
+		if def.Comment != first.Comment {
+			return true
+		}
+		if (def.PlacementPolicyRef == nil) != (first.PlacementPolicyRef == nil) ||
+			(def.PlacementPolicyRef != nil && def.PlacementPolicyRef.Name.L != first.PlacementPolicyRef.Name.L) {
+			return true
+		}
+	}
+	return false
+}
+
 // AppendPartitionDefs generates a list of partition definitions needed for SHOW CREATE TABLE (in executor/show.go)
 // as well as needed for generating the ADD PARTITION query for INTERVAL partitioning of ALTER TABLE t LAST PARTITION
 // and generating the CREATE TABLE query from CREATE TABLE ... INTERVAL
 func AppendPartitionDefs(partitionInfo *model.PartitionInfo, buf *bytes.Buffer, sqlMode mysql.SQLMode) {
-	for i, def := range partitionInfo.Definitions {
+	explicitSubPartitions := partitionInfo.Sub != nil && isNonDefaultSubPartitionOptionsUsed(partitionInfo)
+	for i, def := range partitionInfo.TopLevelDefinitions(partitionInfo.Definitions) {
 		if i > 0 {
 			fmt.Fprintf(buf, ",\n ")
 		}
@@ -4405,13 +5212,30 @@ func AppendPartitionDefs(partitionInfo *model.PartitionInfo, buf *bytes.Buffer,
 				fmt.Fprintf(buf, " VALUES IN (%s)", values.String())
 			}
 		}
-		if len(def.Comment) > 0 {
-			fmt.Fprintf(buf, " COMMENT '%s'", format.OutputFormat(def.Comment))
-		}
-		if def.PlacementPolicyRef != nil {
-			// add placement ref info here
-			fmt.Fprintf(buf, " /*T![placement] PLACEMENT POLICY=%s */", stringutil.Escape(def.PlacementPolicyRef.Name.O, sqlMode))
+		if explicitSubPartitions {
+			num := int(partitionInfo.Sub.Num)
+			buf.WriteString("\n (")
+			for j, subDef := range partitionInfo.Definitions[i*num : (i+1)*num] {
+				if j > 0 {
+					buf.WriteString(",\n  ")
+				}
+				fmt.Fprintf(buf, "SUBPARTITION %s", stringutil.Escape(subDef.Name.O, sqlMode))
+				appendPartitionDefOptions(&subDef, buf, sqlMode)
+			}
+			buf.WriteString(")")
+			continue
 		}
+		appendPartitionDefOptions(&def, buf, sqlMode)
+	}
+}
+
+func appendPartitionDefOptions(def *model.PartitionDefinition, buf *bytes.Buffer, sqlMode mysql.SQLMode) {
+	if len(def.Comment) > 0 {
+		fmt.Fprintf(buf, " COMMENT '%s'", format.OutputFormat(def.Comment))
+	}
+	if def.PlacementPolicyRef != nil {
+		// add placement ref info here
+		fmt.Fprintf(buf, " /*T![placement] PLACEMENT POLICY=%s */", stringutil.Escape(def.PlacementPolicyRef.Name.O, sqlMode))
 	}
 }
 
@@ -0,0 +1,648 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/pingcap/errors"
+	sess "github.com/pingcap/tidb/pkg/ddl/internal/session"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta"
+	"github.com/pingcap/tidb/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/table/tables"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	decoder "github.com/pingcap/tidb/pkg/util/rowDecoder"
+	kvutil "github.com/tikv/client-go/v2/util"
+	"go.uber.org/zap"
+)
+
+// onAlterPrimaryKey changes the primary key of a table, including changing it to or from a clustered one.
+// The rows are copied into a table with a new ID and the new primary key, which is switched to when all the
+// rows are copied. The writes are applied to both tables during the reorganization, and a temporary unique
+// index on the columns of the new primary key is added to the old table to check the uniqueness and to
+// locate the rows in the old table after switching.
+func (w *worker) onAlterPrimaryKey(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	if job.IsRollingback() {
+		return onAlterPrimaryKeyRollback(d, t, job)
+	}
+
+	var (
+		newTableID              int64
+		indexPartSpecifications []*ast.IndexPartSpecification
+		indexOption             *ast.IndexOption
+		sqlMode                 mysql.SQLMode
+		dropOld                 bool
+	)
+	err := job.DecodeArgs(&newTableID, &indexPartSpecifications, &indexOption, &sqlMode, &dropOld)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		// none -> delete only
+		pkReorgInfo, tempIdxInfo, err := buildPrimaryKeyReorgInfo(tblInfo, newTableID, indexPartSpecifications, indexOption, dropOld)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		// The IDs of the rows in the new table are allocated with the new table ID, start from the ones of the old table.
+		autoIDSchemaID := tblInfo.GetAutoIDSchemaID(job.SchemaID)
+		autoIDs, err := t.GetAutoIDAccessors(autoIDSchemaID, tblInfo.ID).Get()
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		err = t.GetAutoIDAccessors(autoIDSchemaID, newTableID).Put(autoIDs)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		tblInfo.Indices = append(tblInfo.Indices, tempIdxInfo)
+		tblInfo.PrimaryKeyReorgInfo = pkReorgInfo
+		ver, err = updateVersionAndTableInfoWithCheck(d, t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly, model.StateWriteOnly:
+		// Prevent inserting null values into the columns of the new primary key.
+		tempIdxInfo := model.FindIndexInfoByID(tblInfo.Indices, tblInfo.PrimaryKeyReorgInfo.TempIndexID)
+		nullCols, err := getNullColInfos(tblInfo, tempIdxInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		if len(nullCols) > 0 {
+			err = modifyColsFromNull2NotNull(w, dbInfo, tblInfo, nullCols, &model.ColumnInfo{Name: model.NewCIStr("")}, false)
+			if err != nil {
+				return convertAlterPrimaryKeyJob2RollbackJob(d, t, job, tblInfo, err)
+			}
+		}
+		// delete only -> write only
+		// write only -> write reorganization
+		nextState := model.StateWriteOnly
+		if job.SchemaState == model.StateWriteOnly {
+			nextState = model.StateWriteReorganization
+		}
+		setPrimaryKeyReorgState(tblInfo, nextState)
+		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		if nextState == model.StateWriteReorganization {
+			// Initialize SnapshotVer to 0 for later reorganization check.
+			job.SnapshotVer = 0
+		}
+		job.SchemaState = nextState
+	case model.StateWriteReorganization:
+		var done bool
+		done, ver, err = doPrimaryKeyReorgWork(w, d, t, job, dbInfo, tblInfo)
+		if !done {
+			return ver, err
+		}
+		return switchToPrimaryKeyReorgTable(d, t, job, tblInfo)
+	case model.StateDeleteReorganization:
+		// The writes are not applied to the old table any more, finish the job.
+		oldTblInfo := tblInfo.PrimaryKeyReorgInfo.Table
+		tblInfo.PrimaryKeyReorgInfo = nil
+		err = t.GetAutoIDAccessors(tblInfo.GetAutoIDSchemaID(job.SchemaID), oldTblInfo.ID).Del()
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		// The old table is cleaned up by the delete range.
+		job.Args = []any{[]int64{oldTblInfo.ID}, int64(0)}
+		asyncNotifyEvent(d, statsutil.NewTruncateTableEvent(job.SchemaID, tblInfo, oldTblInfo))
+	default:
+		err = dbterror.ErrInvalidDDLState.GenWithStackByArgs("table", tblInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// buildPrimaryKeyReorgInfo builds the table with the new primary key, and the temporary unique index
+// on the columns of the new primary key which is added to the old table.
+func buildPrimaryKeyReorgInfo(tblInfo *model.TableInfo, newTableID int64, indexPartSpecifications []*ast.IndexPartSpecification,
+	indexOption *ast.IndexOption, dropOld bool) (*model.PrimaryKeyReorgInfo, *model.IndexInfo, error) {
+	oldPKInfo := tables.FindPrimaryIndex(tblInfo)
+	if !dropOld && (tblInfo.PKIsHandle || oldPKInfo != nil) {
+		return nil, nil, infoschema.ErrMultiplePriKey
+	}
+	pkInfo, err := BuildIndexInfo(nil, tblInfo.Columns, model.NewCIStr(mysql.PrimaryKeyName), true, true, false,
+		indexPartSpecifications, indexOption, model.StateDeleteOnly)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	pkInfo.ID = AllocateIndexID(tblInfo)
+	tempIdxInfo := pkInfo.Clone()
+	tempIdxInfo.ID = AllocateIndexID(tblInfo)
+	tempIdxInfo.Name = model.NewCIStr(genChangingIndexUniqueName(tblInfo, pkInfo))
+	tempIdxInfo.Primary = false
+
+	newTblInfo := tblInfo.Clone()
+	newTblInfo.ID = newTableID
+	newTblInfo.PrimaryKeyReorgInfo = nil
+	if newTblInfo.PKIsHandle {
+		newTblInfo.GetPkColInfo().DelFlag(mysql.PriKeyFlag)
+		newTblInfo.PKIsHandle = false
+	} else if oldPKInfo != nil {
+		DropIndexColumnFlag(newTblInfo, oldPKInfo)
+		removeIndexInfo(newTblInfo, oldPKInfo)
+		newTblInfo.IsCommonHandle = false
+		newTblInfo.CommonHandleVersion = 0
+	}
+	for _, idxCol := range pkInfo.Columns {
+		newTblInfo.Columns[idxCol.Offset].AddFlag(mysql.NotNullFlag)
+	}
+	clustered := indexOption != nil && indexOption.PrimaryKeyTp == model.PrimaryKeyTypeClustered
+	if clustered && isSingleIntPKColumn(newTblInfo, pkInfo) {
+		newTblInfo.Columns[pkInfo.Columns[0].Offset].AddFlag(mysql.PriKeyFlag)
+		newTblInfo.PKIsHandle = true
+	} else {
+		if clustered {
+			newTblInfo.IsCommonHandle = true
+			newTblInfo.CommonHandleVersion = 1
+		}
+		newTblInfo.Indices = append(newTblInfo.Indices, pkInfo)
+		AddIndexColumnFlag(newTblInfo, pkInfo)
+	}
+	newTblInfo.State = model.StateDeleteOnly
+	for _, idx := range newTblInfo.Indices {
+		idx.State = model.StateDeleteOnly
+	}
+	pkReorgInfo := &model.PrimaryKeyReorgInfo{
+		Table:       newTblInfo,
+		TempIndexID: tempIdxInfo.ID,
+	}
+	return pkReorgInfo, tempIdxInfo, nil
+}
+
+// isSingleIntPKColumn checks whether the primary key is built on a single integer column without prefix,
+// which is used as the handle if the primary key is clustered.
+func isSingleIntPKColumn(tblInfo *model.TableInfo, pkInfo *model.IndexInfo) bool {
+	if len(pkInfo.Columns) != 1 || pkInfo.Columns[0].Length != types.UnspecifiedLength {
+		return false
+	}
+	switch tblInfo.Columns[pkInfo.Columns[0].Offset].GetType() {
+	case mysql.TypeLong, mysql.TypeLonglong,
+		mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24:
+		return true
+	}
+	return false
+}
+
+// setPrimaryKeyReorgState sets the state of the table with the new primary key, its indexes and
+// the temporary unique index in the old table.
+func setPrimaryKeyReorgState(tblInfo *model.TableInfo, state model.SchemaState) {
+	pi := tblInfo.PrimaryKeyReorgInfo
+	pi.Table.State = state
+	for _, idx := range pi.Table.Indices {
+		idx.State = state
+	}
+	if tempIdxInfo := model.FindIndexInfoByID(tblInfo.Indices, pi.TempIndexID); tempIdxInfo != nil {
+		tempIdxInfo.State = state
+	}
+}
+
+// switchToPrimaryKeyReorgTable replaces the old table with the one with the new primary key. The writes are
+// still applied to the old table, until all the TiDB instances know the new table.
+func switchToPrimaryKeyReorgTable(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, _ error) {
+	pi := tblInfo.PrimaryKeyReorgInfo
+	newTblInfo := pi.Table.Clone()
+	newTblInfo.State = model.StatePublic
+	for _, idx := range newTblInfo.Indices {
+		idx.State = model.StatePublic
+	}
+	oldTblInfo := tblInfo.Clone()
+	oldTblInfo.PrimaryKeyReorgInfo = nil
+	oldTblInfo.State = model.StateWriteReorganization
+	newTblInfo.PrimaryKeyReorgInfo = &model.PrimaryKeyReorgInfo{
+		Table:       oldTblInfo,
+		TempIndexID: pi.TempIndexID,
+	}
+
+	// The auto IDs may be allocated from the old table after the new table is created, use the larger ones.
+	autoIDSchemaID := tblInfo.GetAutoIDSchemaID(job.SchemaID)
+	oldAutoIDs, err := t.GetAutoIDAccessors(autoIDSchemaID, tblInfo.ID).Get()
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	newAutoIDs, err := t.GetAutoIDAccessors(autoIDSchemaID, newTblInfo.ID).Get()
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	newAutoIDs.RowID = max(newAutoIDs.RowID, oldAutoIDs.RowID)
+	newAutoIDs.IncrementID = max(newAutoIDs.IncrementID, oldAutoIDs.IncrementID)
+	newAutoIDs.RandomID = max(newAutoIDs.RandomID, oldAutoIDs.RandomID)
+
+	tableRuleID, partRuleIDs, _, oldRules, err := getOldLabelRules(tblInfo, job.SchemaName, tblInfo.Name.L)
+	if err != nil {
+		return ver, errors.Wrapf(err, "failed to get old label rules from PD")
+	}
+	err = updateLabelRules(job, tblInfo, oldRules, tableRuleID, partRuleIDs, []string{}, newTblInfo.ID)
+	if err != nil {
+		return ver, errors.Wrapf(err, "failed to update the label rule to PD")
+	}
+
+	err = t.DropTableOrView(job.SchemaID, job.SchemaName, tblInfo.ID, tblInfo.Name.L)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	err = t.CreateTableOrView(job.SchemaID, job.SchemaName, newTblInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	err = t.GetAutoIDAccessors(autoIDSchemaID, newTblInfo.ID).Put(newAutoIDs)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	job.CtxVars = []any{tblInfo.ID}
+	job.TableID = newTblInfo.ID
+	ver, err = updateSchemaVersion(d, t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.SchemaState = model.StateDeleteReorganization
+	return ver, nil
+}
+
+func onAlterPrimaryKeyRollback(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.PrimaryKeyReorgInfo
+	if pi == nil {
+		job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+		job.Args = []any{[]int64{}, int64(0)}
+		return ver, nil
+	}
+	if tempIdxInfo := model.FindIndexInfoByID(tblInfo.Indices, pi.TempIndexID); tempIdxInfo != nil {
+		removeIndexInfo(tblInfo, tempIdxInfo)
+	}
+	tblInfo.PrimaryKeyReorgInfo = nil
+	err = t.GetAutoIDAccessors(tblInfo.GetAutoIDSchemaID(job.SchemaID), pi.Table.ID).Del()
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// The new table and the temporary unique index are cleaned up by the delete range.
+	job.Args = []any{[]int64{pi.Table.ID}, pi.TempIndexID}
+	return ver, nil
+}
+
+// convertAlterPrimaryKeyJob2RollbackJob stops applying the writes to the table with the new primary key,
+// and converts the job to a rollback job.
+func convertAlterPrimaryKeyJob2RollbackJob(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, err error) (ver int64, _ error) {
+	pi := tblInfo.PrimaryKeyReorgInfo
+	if tempIdxInfo := model.FindIndexInfoByID(tblInfo.Indices, pi.TempIndexID); tempIdxInfo != nil {
+		for _, idxCol := range tempIdxInfo.Columns {
+			tblInfo.Columns[idxCol.Offset].DelFlag(mysql.PreventNullInsertFlag)
+		}
+	}
+	setPrimaryKeyReorgState(tblInfo, model.StateDeleteOnly)
+	job.SchemaState = model.StateDeleteOnly
+	ver, err1 := updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
+	}
+	job.State = model.JobStateRollingback
+	return ver, errors.Trace(err)
+}
+
+func doPrimaryKeyReorgWork(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job, dbInfo *model.DBInfo,
+	tblInfo *model.TableInfo) (done bool, ver int64, err error) {
+	job.ReorgMeta.ReorgTp = model.ReorgTypeTxn
+	sctx, err1 := w.sessPool.Get()
+	if err1 != nil {
+		return false, ver, errors.Trace(err1)
+	}
+	defer w.sessPool.Put(sctx)
+	rh := newReorgHandler(sess.NewSession(sctx))
+	tbl, err := getTable((*asAutoIDRequirement)(d), job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+	elements := []*meta.Element{
+		{ID: tblInfo.PrimaryKeyReorgInfo.TempIndexID, TypeKey: meta.IndexElementKey},
+		{ID: tblInfo.Columns[0].ID, TypeKey: meta.ColumnElementKey},
+	}
+	reorgInfo, err := getReorgInfo(d.jobContext(job.ID, job.ReorgMeta), d, rh, job, dbInfo, tbl, elements, false)
+	if err != nil || reorgInfo == nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(reorgInfo, tblInfo, d.lease, func() (reorgErr error) {
+		defer util.Recover(metrics.LabelDDL, "onAlterPrimaryKey",
+			func() {
+				reorgErr = dbterror.ErrCancelledDDLJob.GenWithStack("alter primary key of table `%v` panic", tblInfo.Name)
+			}, false)
+		//nolint:forcetypeassert
+		return w.reorgPrimaryKeyDataAndIndex(tbl.(table.PhysicalTable), reorgInfo)
+	})
+	if err != nil {
+		if dbterror.ErrPausedDDLJob.Equal(err) {
+			return false, ver, nil
+		}
+		if dbterror.ErrWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.IsTxnRetryableError(err) || dbterror.ErrNotOwner.Equal(err) {
+			return false, ver, errors.Trace(err)
+		}
+		if err1 := rh.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("run alter primary key job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+				zap.String("category", "ddl"), zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("run alter primary key job failed, convert job to rollback", zap.String("category", "ddl"),
+			zap.String("job", job.String()), zap.Error(err))
+		ver, err = convertAlterPrimaryKeyJob2RollbackJob(d, t, job, tblInfo, err)
+		return false, ver, err
+	}
+	return true, ver, nil
+}
+
+// reorgPrimaryKeyDataAndIndex checks the uniqueness of the new primary key by adding the temporary unique
+// index to the old table first, then copies the rows into the table with the new primary key.
+func (w *worker) reorgPrimaryKeyDataAndIndex(t table.PhysicalTable, reorgInfo *reorgInfo) error {
+	if bytes.Equal(reorgInfo.currElement.TypeKey, meta.IndexElementKey) {
+		err := w.addPhysicalTableIndex(t, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		currentVer, err := getValidCurrentVersion(reorgInfo.d.store)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reorgInfo.StartKey, reorgInfo.EndKey, err = getTableRange(reorgInfo.NewJobContext(), reorgInfo.d, t, currentVer.Ver, reorgInfo.Job.Priority)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reorgInfo.currElement = reorgInfo.elements[1]
+		// Write the reorg info to store so the whole reorganize process can recover from panic.
+		err = reorgInfo.UpdateReorgMeta(reorgInfo.StartKey, w.sessPool)
+		logutil.BgLogger().Info("copy rows to the table with the new primary key", zap.String("category", "ddl"),
+			zap.Int64("job ID", reorgInfo.Job.ID),
+			zap.String("start key", hex.EncodeToString(reorgInfo.StartKey)),
+			zap.String("end key", hex.EncodeToString(reorgInfo.EndKey)))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return w.writePhysicalTableRecord(w.sessPool, t, typeReorgPrimaryKeyWorker, reorgInfo)
+}
+
+type reorgPrimaryKeyWorker struct {
+	*backfillCtx
+	// reorgTbl is the table with the new primary key.
+	reorgTbl table.PhysicalTable
+
+	// The following attributes are used to reduce memory allocation.
+	rows       [][]types.Datum
+	handles    []kv.Handle
+	rowDecoder *decoder.RowDecoder
+	rowMap     map[int64]types.Datum
+}
+
+func newReorgPrimaryKeyWorker(sessCtx sessionctx.Context, i int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo, jc *JobContext) (*reorgPrimaryKeyWorker, error) {
+	reorgTbl := tables.GetPrimaryKeyReorgTable(t)
+	if reorgTbl == nil {
+		return nil, dbterror.ErrCancelledDDLJob.GenWithStack("can not find the table with the new primary key of table %s", t.Meta().Name)
+	}
+	return &reorgPrimaryKeyWorker{
+		backfillCtx: newBackfillCtx(reorgInfo.d, i, sessCtx, reorgInfo.SchemaName, t, jc, "alter_pk_rate", false),
+		reorgTbl:    reorgTbl,
+		rowDecoder:  decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:      make(map[int64]types.Datum, len(decodeColMap)),
+	}, nil
+}
+
+func (w *reorgPrimaryKeyWorker) BackfillData(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	ctx := kv.WithInternalSourceAndTaskType(context.Background(), w.jobContext.ddlJobSourceType(), kvutil.ExplicitTypeDDL)
+	errInTxn = kv.RunInNewTxn(ctx, w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		updateTxnEntrySizeLimitIfNeeded(txn)
+		txn.SetOption(kv.Priority, handleRange.priority)
+		if tagger := w.GetCtx().getResourceGroupTaggerForTopSQL(handleRange.getJobID()); tagger != nil {
+			txn.SetOption(kv.ResourceGroupTagger, tagger)
+		}
+		txn.SetOption(kv.ResourceGroupName, w.jobContext.resourceGroupName)
+
+		rows, handles, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		for i, r := range rows {
+			taskCtx.scanCount++
+			added, err := w.copyRow(ctx, txn, handles[i], r)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if added {
+				taskCtx.addedCount++
+			}
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "BackfillData", 3000)
+
+	return
+}
+
+func (w *reorgPrimaryKeyWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([][]types.Datum, []kv.Handle, kv.Key, bool, error) {
+	w.rows = w.rows[:0]
+	w.handles = w.handles[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	sysTZ := w.sessCtx.GetSessionVars().StmtCtx.TimeZone()
+	cols := w.reorgTbl.Cols()
+
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotKeys(w.jobContext, w.sessCtx.GetStore(), taskRange.priority, w.table.RecordPrefix(), txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotKeys in reorgPrimaryKeyWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) >= 0
+
+			if taskDone || len(w.rows) >= w.batchCnt {
+				return false, nil
+			}
+
+			_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, sysTZ, w.rowMap)
+			if err != nil {
+				return false, errors.Trace(dbterror.ErrCantDecodeRecord.GenWithStackByArgs("table", err))
+			}
+			row := make([]types.Datum, len(cols))
+			for _, col := range cols {
+				row[col.Offset] = w.rowMap[col.ID]
+			}
+			w.rows = append(w.rows, row)
+			w.handles = append(w.handles, handle)
+
+			w.cleanRowMap()
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rows) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("txn fetches handle info", zap.String("category", "ddl"), zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	return w.rows, w.handles, getNextHandleKey(taskRange, taskDone, lastAccessedHandle), taskDone, errors.Trace(err)
+}
+
+// copyRow writes the row and its index entries into the table with the new primary key. It's skipped if the
+// row has been written by the DML statements since the reorganization started.
+func (w *reorgPrimaryKeyWorker) copyRow(ctx context.Context, txn kv.Transaction, oldHandle kv.Handle, row []types.Datum) (bool, error) {
+	sc := w.sessCtx.GetSessionVars().StmtCtx
+	tblInfo := w.reorgTbl.Meta()
+	handle, err := tables.BuildClusteredHandle(sc.TimeZone(), tblInfo, row)
+	if err != nil {
+		return false, errors.Trace(sc.HandleError(err))
+	}
+	if handle == nil && !w.table.Meta().PKIsHandle && !w.table.Meta().IsCommonHandle {
+		// Both tables use _tidb_rowid as the handle, keep it like the DML statements do, see
+		// primaryKeyReorgHandle in the tables package.
+		handle = oldHandle
+	}
+	var key kv.Key
+	if handle != nil {
+		key = tablecodec.EncodeRecordKey(w.reorgTbl.RecordPrefix(), handle)
+	} else {
+		// The row is located by the non-clustered primary key.
+		pk := tables.FindPrimaryKeyReorgIndex(w.reorgTbl, 0)
+		vals, err := pk.FetchValues(row, nil)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		key, _, err = pk.GenIndexKey(sc.ErrCtx(), sc.TimeZone(), vals, nil, nil)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	_, err = txn.Get(ctx, key)
+	if err == nil {
+		return false, nil
+	}
+	if !kv.IsErrNotFound(err) {
+		return false, errors.Trace(err)
+	}
+	if handle == nil {
+		handle, err = tables.AllocHandle(ctx, nil, w.reorgTbl)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+
+	colIDs := make([]int64, 0, len(row))
+	vals := make([]types.Datum, 0, len(row))
+	for _, col := range w.reorgTbl.Cols() {
+		if tables.CanSkip(tblInfo, col, &row[col.Offset]) {
+			continue
+		}
+		colIDs = append(colIDs, col.ID)
+		vals = append(vals, row[col.Offset])
+	}
+	rd := &w.sessCtx.GetSessionVars().RowEncoder
+	rowVal, err := tablecodec.EncodeRow(sc.TimeZone(), vals, colIDs, nil, nil, rd)
+	if err = sc.HandleError(err); err != nil {
+		return false, errors.Trace(err)
+	}
+	err = txn.Set(tablecodec.EncodeRecordKey(w.reorgTbl.RecordPrefix(), handle), rowVal)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, idx := range w.reorgTbl.Indices() {
+		if tblInfo.IsCommonHandle && idx.Meta().Primary {
+			continue
+		}
+		idxVals, err := idx.FetchValues(row, nil)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		rsData := tables.TryGetHandleRestoredDataWrapper(tblInfo, row, nil, idx.Meta())
+		_, err = idx.Create(w.sessCtx.GetTableCtx(), txn, idxVals, handle, rsData, table.WithIgnoreAssertion, table.FromBackfill)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	return true, nil
+}
+
+func (w *reorgPrimaryKeyWorker) cleanRowMap() {
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+}
+
+func (w *reorgPrimaryKeyWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+func (*reorgPrimaryKeyWorker) String() string {
+	return typeReorgPrimaryKeyWorker.String()
+}
+
+func (w *reorgPrimaryKeyWorker) GetCtx() *backfillCtx {
+	return w.backfillCtx
+}
@@ -21,7 +21,9 @@ import (
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/ddl"
+	"github.com/pingcap/tidb/pkg/ddl/util/callback"
 	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/errno"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
@@ -29,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/tablecodec"
 	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/testkit/external"
 	"github.com/pingcap/tidb/pkg/testkit/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/testutils"
@@ -309,3 +312,85 @@ func TestCreateClusteredIndex(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, tbl.Meta().IsCommonHandle)
 }
+
+func TestChangePrimaryKeyOnline(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+
+	cases := []struct {
+		createSQL      string
+		alterSQL       string
+		pkIsHandle     bool
+		isCommonHandle bool
+	}{
+		{"create table t (a int, b varchar(10), c int, primary key(a) clustered, key idx_c(c))",
+			"alter table t drop primary key, add primary key(b) clustered", false, true},
+		{"create table t (a int, b varchar(10), c int, primary key(b) clustered, key idx_c(c))",
+			"alter table t drop primary key, add primary key(a) nonclustered", false, false},
+		{"create table t (a int, b varchar(10), c int, primary key(a) nonclustered, key idx_c(c))",
+			"alter table t drop primary key, add primary key(a) clustered", true, false},
+		{"create table t (a int, b varchar(10), c int, key idx_c(c))",
+			"alter table t add primary key(b, c) clustered", false, true},
+	}
+	for _, ca := range cases {
+		tk.MustExec("drop table if exists t")
+		tk.MustExec(ca.createSQL)
+		tk.MustExec("insert into t values (1, 'v1', 1), (2, 'v2', 2), (3, 'v3', 3)")
+
+		// Apply the writes in every state of the job, including when the rows are being copied.
+		id := 10
+		var checkErr error
+		hook := &callback.TestDDLCallback{Do: dom}
+		hook.OnJobRunBeforeExported = func(job *model.Job) {
+			if checkErr != nil || job.Type != model.ActionAlterPrimaryKey {
+				return
+			}
+			id++
+			sqls := []string{
+				fmt.Sprintf("insert into t values (%d, 'v%d', %d)", id, id, id),
+				fmt.Sprintf("update t set b = concat(b, 'x'), c = c + 100 where a = %d", id-1),
+				"delete from t where a = 3",
+			}
+			for _, sql := range sqls {
+				if _, checkErr = tk1.Exec(sql); checkErr != nil {
+					return
+				}
+			}
+		}
+		dom.DDL().SetHook(hook.Clone())
+		tk.MustExec(ca.alterSQL)
+		dom.DDL().SetHook(&callback.TestDDLCallback{Do: dom})
+		require.NoError(t, checkErr)
+
+		tk.MustExec("admin check table t")
+		tbl := external.GetTableByName(t, tk, "test", "t")
+		require.Equal(t, ca.pkIsHandle, tbl.Meta().PKIsHandle)
+		require.Equal(t, ca.isCommonHandle, tbl.Meta().IsCommonHandle)
+		require.Nil(t, tbl.Meta().PrimaryKeyReorgInfo)
+		tk.MustQuery("select a, b, c from t where a < 10 order by a").Check(testkit.Rows("1 v1 1", "2 v2 2"))
+		tk.MustQuery(fmt.Sprintf("select count(*) from t where a > 10 and a < %d and b = concat('v', a, 'x') and c = a + 100", id)).
+			Check(testkit.Rows(fmt.Sprintf("%d", id-11)))
+		tk.MustQuery(fmt.Sprintf("select a, b, c from t where a >= %d", id)).Check(testkit.Rows(fmt.Sprintf("%d v%d %d", id, id, id)))
+	}
+
+	// The job is rolled back if the new primary key is not unique.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10), c int, primary key(a) clustered, key idx_c(c))")
+	tk.MustExec("insert into t values (1, 'v1', 1), (2, 'v1', 2)")
+	tk.MustGetErrCode("alter table t drop primary key, add primary key(b) clustered", errno.ErrDupEntry)
+	tk.MustExec("admin check table t")
+	tbl := external.GetTableByName(t, tk, "test", "t")
+	require.True(t, tbl.Meta().PKIsHandle)
+	require.Nil(t, tbl.Meta().PrimaryKeyReorgInfo)
+	require.Len(t, tbl.Meta().Indices, 1)
+	tk.MustExec("insert into t values (3, 'v1', 3)")
+	tk.MustQuery("select a, b, c from t order by a").Check(testkit.Rows("1 v1 1", "2 v1 2", "3 v1 3"))
+
+	// The new primary key columns can't contain null values.
+	tk.MustExec("insert into t values (4, null, 4)")
+	tk.MustGetErrCode("alter table t drop primary key, add primary key(b) clustered", errno.ErrInvalidUseOfNull)
+	tk.MustExec("admin check table t")
+}
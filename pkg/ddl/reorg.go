@@ -71,6 +71,9 @@ type reorgCtx struct {
 	}
 
 	references atomicutil.Int32
+
+	// vectorCentroids are the centroids trained by the reorganization of a vector index, see trainVectorIndex.
+	vectorCentroids [][]float32
 }
 
 // newContext gets a context. It is only used for adding column in reorganization state.
@@ -235,6 +238,7 @@ func (w *worker) runReorgJob(reorgInfo *reorgInfo, tblInfo *model.TableInfo,
 
 		// Update a job's warnings.
 		w.mergeWarningsIntoJob(job)
+		reorgInfo.vectorCentroids = rc.vectorCentroids
 
 		d.removeReorgCtx(job.ID)
 
@@ -454,6 +458,8 @@ type reorgInfo struct {
 	dbInfo          *model.DBInfo
 	elements        []*meta.Element
 	currElement     *meta.Element
+	// vectorCentroids are the centroids trained by the reorganization of a vector index, which are set when it's done.
+	vectorCentroids [][]float32
 }
 
 func (r *reorgInfo) NewJobContext() *JobContext {
@@ -155,7 +155,7 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	if err != nil {
 		return ver, err
 	}
-	if !needChangeColumnData(oldCol, jp.newCol) {
+	if !needReorgColumnData(tblInfo, oldCol, jp.newCol) {
 		// Normal-type rolling back
 		if job.SchemaState == model.StateNone {
 			// When change null to not null, although state is unchanged with none, the oldCol flag's has been changed to preNullInsertFlag.
@@ -183,8 +183,15 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	return ver, dbterror.ErrCancelledDDLJob
 }
 
-func rollingbackAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
-	tblInfo, columnInfo, col, _, _, err := checkAddColumn(t, job)
+func rollingbackAddColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	if needNotifyAndStopReorgWorker(job) {
+		// add column reorg workers are started. need to ask them to exit.
+		w.jobLogger(job).Info("run the cancelling DDL job", zap.String("job", job.String()))
+		d.notifyReorgWorkerJobStateChange(job)
+		// Give the this kind of ddl one more round to run, the dbterror.ErrCancelledDDLJob should be fetched from the bottom up.
+		return w.onAddColumn(d, t, job)
+	}
+	tblInfo, columnInfo, _, idxInfos, _, _, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -192,19 +199,41 @@ func rollingbackAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, e
 		job.State = model.JobStateCancelled
 		return ver, dbterror.ErrCancelledDDLJob
 	}
+	return convertAddColumnJob2RollbackJob(d, t, job, tblInfo, columnInfo, idxInfos, dbterror.ErrCancelledDDLJob)
+}
 
+// convertAddColumnJob2RollbackJob converts the add column job to a drop column job, the indexes defined on
+// the adding column are removed from the table and their data is cleaned up by the delete-range.
+func convertAddColumnJob2RollbackJob(
+	d *ddlCtx,
+	t *meta.Meta,
+	job *model.Job,
+	tblInfo *model.TableInfo,
+	columnInfo *model.ColumnInfo,
+	idxInfos []*model.IndexInfo,
+	err error,
+) (int64, error) {
 	originalState := columnInfo.State
 	columnInfo.State = model.StateDeleteOnly
 	job.SchemaState = model.StateDeleteOnly
-
-	job.Args = []any{col.Name}
-	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != columnInfo.State)
-	if err != nil {
-		return ver, errors.Trace(err)
+	if len(idxInfos) > 0 {
+		newIndices := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+		for _, idx := range tblInfo.Indices {
+			if !indexInfoContains(idx.ID, idxInfos) {
+				newIndices = append(newIndices, idx)
+			}
+		}
+		tblInfo.Indices = newIndices
 	}
 
+	// the args will be used in onDropColumn.
+	job.Args = []any{columnInfo.Name, false, indexInfosToIDList(idxInfos)}
+	ver, err1 := updateVersionAndTableInfo(d, t, job, tblInfo, originalState != columnInfo.State)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
+	}
 	job.State = model.JobStateRollingback
-	return ver, dbterror.ErrCancelledDDLJob
+	return ver, errors.Trace(err)
 }
 
 func rollingbackDropColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
@@ -291,9 +320,6 @@ func rollbackExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job, tbl
 	tblInfo.ExchangePartitionInfo = nil
 	job.State = model.JobStateRollbackDone
 	job.SchemaState = model.StatePublic
-	if len(tblInfo.Constraints) == 0 {
-		return updateVersionAndTableInfo(d, t, job, tblInfo, true)
-	}
 	var (
 		defID          int64
 		ptSchemaID     int64
@@ -308,7 +334,13 @@ func rollbackExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job, tbl
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
+	if pt.ExchangePartitionInfo == nil && len(pt.Partition.DDLChangedIndex) == 0 {
+		return updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	}
 	pt.ExchangePartitionInfo = nil
+	// Remove the new global indexes, and delete their entries.
+	globalIndexIDs := removeChangedGlobalIndexes(pt, true)
+	job.Args = []any{defID, ptSchemaID, ptID, partName, withValidation, globalIndexIDs}
 	var ptInfo []schemaIDAndTableInfo
 	ptInfo = append(ptInfo, schemaIDAndTableInfo{
 		schemaID: ptSchemaID,
@@ -324,6 +356,11 @@ func rollingbackExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		job.State = model.JobStateCancelled
 		return ver, dbterror.ErrCancelledDDLJob
 	}
+	if job.SchemaState == model.StateDeleteReorganization {
+		// The partition is already exchanged, the job can not be cancelled.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
 	var nt *model.TableInfo
 	nt, err = GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
 	if err != nil {
@@ -463,6 +500,39 @@ func rollingbackReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ve
 	return convertAddTablePartitionJob2RollbackJob(d, t, job, dbterror.ErrCancelledDDLJob, tblInfo)
 }
 
+func rollingbackAlterPrimaryKey(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	if job.SchemaState == model.StateNone {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrCancelledDDLJob
+	}
+	if needNotifyAndStopReorgWorker(job) {
+		// The reorg workers are started, need to ask them to exit.
+		w.jobLogger(job).Info("run the cancelling DDL job", zap.String("job", job.String()))
+		d.notifyReorgWorkerJobStateChange(job)
+		return w.onAlterPrimaryKey(d, t, job)
+	}
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	return convertAlterPrimaryKeyJob2RollbackJob(d, t, job, tblInfo, dbterror.ErrCancelledDDLJob)
+}
+
+// rollingbackTrainVectorIndex cancels the job before the centroids are set, see onTrainVectorIndex.
+func rollingbackTrainVectorIndex(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	if needNotifyAndStopReorgWorker(job) {
+		// The training is started, need to ask it to exit.
+		w.jobLogger(job).Info("run the cancelling DDL job", zap.String("job", job.String()))
+		d.notifyReorgWorkerJobStateChange(job)
+		return w.onTrainVectorIndex(d, t, job)
+	}
+	if job.SchemaState == model.StateWriteReorganization {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrCancelledDDLJob
+	}
+	return cancelOnlyNotHandledJob(job, model.StateNone)
+}
+
 func pauseReorgWorkers(w *worker, d *ddlCtx, job *model.Job) (err error) {
 	if needNotifyAndStopReorgWorker(job) {
 		w.jobLogger(job).Info("pausing the DDL job", zap.String("job", job.String()))
@@ -475,7 +545,7 @@ func pauseReorgWorkers(w *worker, d *ddlCtx, job *model.Job) (err error) {
 func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	switch job.Type {
 	case model.ActionAddColumn:
-		ver, err = rollingbackAddColumn(d, t, job)
+		ver, err = rollingbackAddColumn(w, d, t, job)
 	case model.ActionAddIndex:
 		ver, err = rollingbackAddIndex(w, d, t, job, false)
 	case model.ActionAddPrimaryKey:
@@ -485,6 +555,10 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	case model.ActionReorganizePartition, model.ActionRemovePartitioning,
 		model.ActionAlterTablePartitioning:
 		ver, err = rollingbackReorganizePartition(d, t, job)
+	case model.ActionAlterPrimaryKey:
+		ver, err = rollingbackAlterPrimaryKey(w, d, t, job)
+	case model.ActionTrainVectorIndex:
+		ver, err = rollingbackTrainVectorIndex(w, d, t, job)
 	case model.ActionDropColumn:
 		ver, err = rollingbackDropColumn(d, t, job)
 	case model.ActionDropIndex, model.ActionDropPrimaryKey:
@@ -98,14 +98,49 @@ func expectedDeleteRangeCnt(ctx delRangeCntCtx, job *model.Job) (int, error) {
 			return 0, errors.Trace(err)
 		}
 		return len(physicalTableIDs) + 1, nil
+	case model.ActionDropMaterializedView:
+		var (
+			baseSchemaIDs []int64
+			sysSchemaID   int64
+			tableIDs      []int64
+		)
+		if err := job.DecodeArgs(&baseSchemaIDs, &sysSchemaID, &tableIDs); err != nil {
+			return 0, errors.Trace(err)
+		}
+		return len(tableIDs), nil
+	case model.ActionAlterPrimaryKey:
+		var tableIDs []int64
+		var tempIndexID int64
+		if err := job.DecodeArgs(&tableIDs, &tempIndexID); err != nil {
+			return 0, errors.Trace(err)
+		}
+		if tempIndexID != 0 {
+			return len(tableIDs) + 1, nil
+		}
+		return len(tableIDs), nil
 	case model.ActionDropTablePartition, model.ActionTruncateTablePartition,
 		model.ActionReorganizePartition, model.ActionRemovePartitioning,
 		model.ActionAlterTablePartitioning:
 		var physicalTableIDs []int64
-		if err := job.DecodeArgs(&physicalTableIDs); err != nil {
+		var partInfo model.PartitionInfo
+		var globalIndexIDs []int64
+		if err := job.DecodeArgs(&physicalTableIDs, &partInfo, &globalIndexIDs); err != nil {
 			return 0, errors.Trace(err)
 		}
-		return len(physicalTableIDs), nil
+		return len(physicalTableIDs) + len(globalIndexIDs), nil
+	case model.ActionExchangeTablePartition:
+		var (
+			defID          int64
+			ptSchemaID     int64
+			ptID           int64
+			partName       string
+			withValidation bool
+			globalIndexIDs []int64
+		)
+		if err := job.DecodeArgs(&defID, &ptSchemaID, &ptID, &partName, &withValidation, &globalIndexIDs); err != nil {
+			return 0, errors.Trace(err)
+		}
+		return len(globalIndexIDs), nil
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
 		indexID := make([]int64, 1)
 		ifExists := make([]bool, 1)
@@ -136,6 +171,17 @@ func expectedDeleteRangeCnt(ctx delRangeCntCtx, job *model.Job) (int, error) {
 			}
 		}
 		return mathutil.Max(len(partitionIDs), 1), nil
+	case model.ActionAddColumn:
+		if job.State != model.JobStateRollbackDone {
+			return 0, nil
+		}
+		var colName model.CIStr
+		var ifExists bool
+		var indexIDs []int64
+		if err := job.DecodeArgs(&colName, &ifExists, &indexIDs); err != nil {
+			return 0, errors.Trace(err)
+		}
+		return len(indexIDs), nil
 	case model.ActionDropColumn:
 		var colName model.CIStr
 		var ifExists bool
@@ -149,11 +195,12 @@ func expectedDeleteRangeCnt(ctx delRangeCntCtx, job *model.Job) (int, error) {
 	case model.ActionModifyColumn:
 		var indexIDs []int64
 		var partitionIDs []int64
-		if err := job.DecodeArgs(&indexIDs, &partitionIDs); err != nil {
+		var droppedPartIDs []int64
+		if err := job.DecodeArgs(&indexIDs, &partitionIDs, &droppedPartIDs); err != nil {
 			return 0, errors.Trace(err)
 		}
 		physicalCnt := mathutil.Max(len(partitionIDs), 1)
-		return physicalCnt * ctx.deduplicateIdxCnt(indexIDs), nil
+		return physicalCnt*ctx.deduplicateIdxCnt(indexIDs) + len(droppedPartIDs), nil
 	case model.ActionMultiSchemaChange:
 		totalExpectedCnt := 0
 		for i, sub := range job.MultiSchemaInfo.SubJobs {
@@ -204,7 +251,7 @@ func (d *ddl) checkHistoryJobInTest(ctx sessionctx.Context, historyJob *model.Jo
 
 	// Check DDL query.
 	switch historyJob.Type {
-	case model.ActionUpdateTiFlashReplicaStatus, model.ActionUnlockTable:
+	case model.ActionUpdateTiFlashReplicaStatus, model.ActionUnlockTable, model.ActionTrainVectorIndex:
 		if historyJob.Query != "" {
 			panic(fmt.Sprintf("job ID %d, type %s, query %s", historyJob.ID, historyJob.Type.String(), historyJob.Query))
 		}
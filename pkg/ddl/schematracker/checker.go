@@ -295,6 +295,27 @@ func (d *Checker) DropView(ctx sessionctx.Context, stmt *ast.DropTableStmt) (err
 	return nil
 }
 
+// CreateTrigger implements the DDL interface.
+func (d *Checker) CreateTrigger(ctx sessionctx.Context, stmt *ast.CreateTriggerStmt) error {
+	return d.realDDL.CreateTrigger(ctx, stmt)
+}
+
+// DropTrigger implements the DDL interface.
+func (d *Checker) DropTrigger(ctx sessionctx.Context, stmt *ast.DropTriggerStmt) error {
+	return d.realDDL.DropTrigger(ctx, stmt)
+}
+
+// CreateMaterializedView implements the DDL interface.
+func (d *Checker) CreateMaterializedView(ctx sessionctx.Context, stmt *ast.CreateMaterializedViewStmt, cols []*table.Column,
+	mview *model.MaterializedViewInfo, logCols [][]int64) error {
+	return d.realDDL.CreateMaterializedView(ctx, stmt, cols, mview, logCols)
+}
+
+// DropMaterializedView implements the DDL interface.
+func (d *Checker) DropMaterializedView(ctx sessionctx.Context, stmt *ast.DropMaterializedViewStmt) error {
+	return d.realDDL.DropMaterializedView(ctx, stmt)
+}
+
 // CreateIndex implements the DDL interface.
 func (d *Checker) CreateIndex(ctx sessionctx.Context, stmt *ast.CreateIndexStmt) error {
 	err := d.realDDL.CreateIndex(ctx, stmt)
@@ -389,6 +410,11 @@ func (*Checker) UpdateTableReplicaInfo(_ sessionctx.Context, _ int64, _ bool) er
 	panic("implement me")
 }
 
+// TrainVectorIndex implements the DDL interface.
+func (d *Checker) TrainVectorIndex(ctx sessionctx.Context, schema, table, index model.CIStr) error {
+	return d.realDDL.TrainVectorIndex(ctx, schema, table, index)
+}
+
 // RepairTable implements the DDL interface.
 func (*Checker) RepairTable(_ sessionctx.Context, _ *ast.CreateTableStmt) error {
 	//TODO implement me
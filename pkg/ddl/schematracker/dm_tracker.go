@@ -390,6 +390,18 @@ func (d SchemaTracker) createIndex(
 	ifNotExists bool,
 ) (err error) {
 	unique := keyType == ast.IndexKeyTypeUnique
+	if keyType == ast.IndexKeyTypeFullText {
+		supported, err := ddl.CheckFullTextIndex(ctx, indexPartSpecifications, indexOption)
+		if err != nil || !supported {
+			return err
+		}
+		indexOption = ddl.FullTextIndexOption(indexOption)
+	}
+	if keyType == ast.IndexKeyTypeVector {
+		if indexPartSpecifications, indexOption, err = ddl.ResolveVectorIndexParts(indexPartSpecifications, indexOption); err != nil {
+			return err
+		}
+	}
 	tblInfo, err := d.TableClonedByName(ti.Schema, ti.Name)
 	if err != nil {
 		return err
@@ -521,13 +533,22 @@ func (d SchemaTracker) addColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast
 		return errors.Trace(err)
 	}
 
+	idxInfos, err := ddl.BuildAddColumnIndexInfos(ctx, t, col, specNewColumn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	columnInfo := ddl.InitAndAddColumnToTable(tblInfo, col.ColumnInfo)
+	ddl.InitAndAddColumnIndexesToTable(tblInfo, columnInfo, idxInfos)
 	offset, err := ddl.LocateOffsetToMove(columnInfo.Offset, spec.Position, tblInfo)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	tblInfo.MoveColumnInfo(columnInfo.Offset, offset)
 	columnInfo.State = model.StatePublic
+	for _, idxInfo := range idxInfos {
+		idxInfo.State = model.StatePublic
+	}
 	return nil
 }
 
@@ -809,8 +830,9 @@ func (d SchemaTracker) dropTablePartitions(_ sessionctx.Context, ident ast.Ident
 		return errors.Trace(dbterror.ErrPartitionMgmtOnNonpartitioned)
 	}
 
-	partNames := make([]string, len(spec.PartitionNames))
-	for i, partCIName := range spec.PartitionNames {
+	partCINames := pi.ExpandPartitionNames(spec.PartitionNames)
+	partNames := make([]string, len(partCINames))
+	for i, partCIName := range partCINames {
 		partNames[i] = partCIName.L
 	}
 	err = ddl.CheckDropTablePartition(tblInfo, partNames)
@@ -948,8 +970,13 @@ func (d SchemaTracker) AlterTable(ctx context.Context, sctx sessionctx.Context,
 					spec.Constraint.Keys, constr.Option, false) // IfNotExists should be not applied
 			case ast.ConstraintPrimaryKey:
 				err = d.createPrimaryKey(sctx, ident, model.NewCIStr(constr.Name), spec.Constraint.Keys, constr.Option)
+			case ast.ConstraintFulltext:
+				err = d.createIndex(sctx, ident, ast.IndexKeyTypeFullText, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
+			case ast.ConstraintVector:
+				err = d.createIndex(sctx, ident, ast.IndexKeyTypeVector, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
 			case ast.ConstraintForeignKey,
-				ast.ConstraintFulltext,
 				ast.ConstraintCheck:
 			default:
 				// Nothing to do now.
@@ -1136,11 +1163,37 @@ func (SchemaTracker) UpdateTableReplicaInfo(_ sessionctx.Context, _ int64, _ boo
 	return nil
 }
 
+// TrainVectorIndex implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) TrainVectorIndex(_ sessionctx.Context, _, _, _ model.CIStr) error {
+	return nil
+}
+
 // RepairTable implements the DDL interface, it's no-op in DM's case.
 func (SchemaTracker) RepairTable(_ sessionctx.Context, _ *ast.CreateTableStmt) error {
 	return nil
 }
 
+// CreateTrigger implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) CreateTrigger(_ sessionctx.Context, _ *ast.CreateTriggerStmt) error {
+	return nil
+}
+
+// DropTrigger implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) DropTrigger(_ sessionctx.Context, _ *ast.DropTriggerStmt) error {
+	return nil
+}
+
+// CreateMaterializedView implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) CreateMaterializedView(_ sessionctx.Context, _ *ast.CreateMaterializedViewStmt, _ []*table.Column,
+	_ *model.MaterializedViewInfo, _ [][]int64) error {
+	return nil
+}
+
+// DropMaterializedView implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) DropMaterializedView(_ sessionctx.Context, _ *ast.DropMaterializedViewStmt) error {
+	return nil
+}
+
 // CreateSequence implements the DDL interface, it's no-op in DM's case.
 func (SchemaTracker) CreateSequence(_ sessionctx.Context, _ *ast.CreateSequenceStmt) error {
 	return nil
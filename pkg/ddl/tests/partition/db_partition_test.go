@@ -400,7 +400,7 @@ func TestCreateTableWithHashPartition(t *testing.T) {
                                        PARTITION p0 VALUES LESS THAN (100),
                                        PARTITION p1 VALUES LESS THAN (200),
                                        PARTITION p2 VALUES LESS THAN MAXVALUE)`)
-	tk.MustQuery(`show warnings`).Check(testkit.Rows("Warning 8200 Unsupported subpartitioning, only using RANGE partitioning"))
+	tk.MustQuery(`show warnings`).Check(testkit.Rows())
 	tk.MustQuery("select * from t_sub partition (p0)").Check(testkit.Rows())
 	tk.MustQuery("show create table t_sub").Check(testkit.Rows("" +
 		"t_sub CREATE TABLE `t_sub` (\n" +
@@ -408,6 +408,8 @@ func TestCreateTableWithHashPartition(t *testing.T) {
 		"  `b` varchar(128) DEFAULT NULL\n" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
 		"PARTITION BY RANGE (`a`)\n" +
+		"SUBPARTITION BY HASH (`a`)\n" +
+		"SUBPARTITIONS 2\n" +
 		"(PARTITION `p0` VALUES LESS THAN (100),\n" +
 		" PARTITION `p1` VALUES LESS THAN (200),\n" +
 		" PARTITION `p2` VALUES LESS THAN (MAXVALUE))"))
@@ -424,25 +426,97 @@ func TestSubPartitioning(t *testing.T) {
 	tk := testkit.NewTestKit(t, store)
 	tk.MustExec("use test")
 	tk.MustExec(`create table t (a int) partition by range (a) subpartition by hash (a) subpartitions 2 (partition pMax values less than (maxvalue))`)
-	tk.MustQuery(`show warnings`).Check(testkit.Rows("Warning 8200 Unsupported subpartitioning, only using RANGE partitioning"))
+	tk.MustQuery(`show warnings`).Check(testkit.Rows())
 	tk.MustQuery(`show create table t`).Check(testkit.Rows("" +
 		"t CREATE TABLE `t` (\n" +
 		"  `a` int(11) DEFAULT NULL\n" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
 		"PARTITION BY RANGE (`a`)\n" +
+		"SUBPARTITION BY HASH (`a`)\n" +
+		"SUBPARTITIONS 2\n" +
 		"(PARTITION `pMax` VALUES LESS THAN (MAXVALUE))"))
+	tk.MustExec(`insert into t values (1),(2),(3),(NULL)`)
+	tk.MustQuery(`select * from t partition (pMaxsp0)`).Sort().Check(testkit.Rows("2", "<nil>"))
+	tk.MustQuery(`select * from t partition (pMaxsp1)`).Sort().Check(testkit.Rows("1", "3"))
+	tk.MustQuery(`select * from t partition (pMax)`).Sort().Check(testkit.Rows("1", "2", "3", "<nil>"))
 	tk.MustExec(`drop table t`)
 
 	tk.MustExec(`create table t (a int) partition by list (a) subpartition by key (a) subpartitions 2 (partition pMax values in (1,3,4))`)
-	tk.MustQuery(`show warnings`).Check(testkit.Rows("Warning 8200 Unsupported subpartitioning, only using LIST partitioning"))
+	tk.MustQuery(`show warnings`).Check(testkit.Rows())
 	tk.MustQuery(`show create table t`).Check(testkit.Rows("" +
 		"t CREATE TABLE `t` (\n" +
 		"  `a` int(11) DEFAULT NULL\n" +
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
 		"PARTITION BY LIST (`a`)\n" +
+		"SUBPARTITION BY KEY (`a`)\n" +
+		"SUBPARTITIONS 2\n" +
 		"(PARTITION `pMax` VALUES IN (1,3,4))"))
+	tk.MustExec(`insert into t values (1),(3),(4)`)
+	tk.MustQuery(`select * from t`).Sort().Check(testkit.Rows("1", "3", "4"))
+	tk.MustQuery(`select * from t where a = 3`).Check(testkit.Rows("3"))
+	tk.MustGetErrCode(`insert into t values (2)`, errno.ErrNoPartitionForGivenValue)
 	tk.MustExec(`drop table t`)
 
+	tk.MustExec(`create table t (a int, b varchar(20)) partition by range (a) subpartition by hash (a) (
+		partition p0 values less than (10) (subpartition s0, subpartition s1 comment 'second'),
+		partition p1 values less than (20) (subpartition s2, subpartition s3))`)
+	tk.MustQuery(`show create table t`).Check(testkit.Rows("" +
+		"t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(20) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`)\n" +
+		"SUBPARTITION BY HASH (`a`)\n" +
+		"(PARTITION `p0` VALUES LESS THAN (10)\n" +
+		" (SUBPARTITION `s0`,\n" +
+		"  SUBPARTITION `s1` COMMENT 'second'),\n" +
+		" PARTITION `p1` VALUES LESS THAN (20)\n" +
+		" (SUBPARTITION `s2`,\n" +
+		"  SUBPARTITION `s3`))"))
+	tk.MustQuery(`select partition_name, partition_ordinal_position, subpartition_name, subpartition_ordinal_position, partition_method, subpartition_method, subpartition_expression, partition_description from information_schema.partitions where table_schema = 'test' and table_name = 't'`).Check(testkit.Rows(
+		"p0 1 s0 1 RANGE HASH `a` 10",
+		"p0 1 s1 2 RANGE HASH `a` 10",
+		"p1 2 s2 1 RANGE HASH `a` 20",
+		"p1 2 s3 2 RANGE HASH `a` 20"))
+	tk.MustExec(`insert into t values (1, "1"), (2, "2"), (11, "11"), (12, "12")`)
+	tk.MustQuery(`select a from t partition (s0)`).Check(testkit.Rows("2"))
+	tk.MustQuery(`select a from t partition (s1)`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select a from t partition (s2)`).Check(testkit.Rows("12"))
+	tk.MustQuery(`select a from t partition (s3)`).Check(testkit.Rows("11"))
+	tk.MustQuery(`select a from t partition (p1)`).Sort().Check(testkit.Rows("11", "12"))
+	tk.MustGetErrCode(`insert into t partition (s0) values (1, "1")`, errno.ErrRowDoesNotMatchGivenPartitionSet)
+	tk.MustExec(`insert into t partition (p0) values (3, "3")`)
+	tk.MustQuery(`select a from t partition (s1)`).Sort().Check(testkit.Rows("1", "3"))
+	tk.MustExec(`update t set a = a + 10 where a = 1`)
+	tk.MustQuery(`select a from t partition (s3)`).Sort().Check(testkit.Rows("11", "11"))
+
+	tk.MustExec(`alter table t add partition (partition p2 values less than (30) (subpartition s4, subpartition s5))`)
+	tk.MustGetErrCode(`alter table t add partition (partition p3 values less than (40) (subpartition s6))`, errno.ErrPartitionWrongNoSubpart)
+	tk.MustGetErrCode(`alter table t add partition (partition p3 values less than (40) (subpartition s0, subpartition s7))`, errno.ErrSameNamePartition)
+	tk.MustExec(`insert into t values (21, "21"), (22, "22")`)
+	tk.MustQuery(`select a from t partition (s4)`).Check(testkit.Rows("22"))
+	tk.MustQuery(`select a from t partition (s5)`).Check(testkit.Rows("21"))
+	tk.MustExec(`alter table t truncate partition p1`)
+	tk.MustQuery(`select a from t`).Sort().Check(testkit.Rows("2", "21", "22", "3"))
+	tk.MustGetErrCode(`alter table t drop partition s4`, errno.ErrDropPartitionNonExistent)
+	tk.MustExec(`alter table t drop partition p2`)
+	tk.MustQuery(`select a from t`).Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery(`select subpartition_name from information_schema.partitions where table_schema = 'test' and table_name = 't'`).Check(testkit.Rows("s0", "s1", "s2", "s3"))
+
+	tk.MustExec(`create table tx (a int, b varchar(20))`)
+	tk.MustGetErrCode(`alter table t exchange partition p0 with table tx`, errno.ErrPartitionInsteadOfSubpartition)
+	tk.MustExec(`insert into tx values (5, "5")`)
+	tk.MustContainErrMsg(`alter table t exchange partition s0 with table tx`, "[ddl:1737]Found a row that does not match the partition")
+	tk.MustExec(`alter table t exchange partition s1 with table tx`)
+	tk.MustQuery(`select a from t partition (s1)`).Check(testkit.Rows("5"))
+	tk.MustQuery(`select a from tx`).Sort().Check(testkit.Rows("3"))
+	tk.MustExec(`drop table t, tx`)
+
+	tk.MustGetErrCode(`create table t (a int) partition by range (a) subpartition by hash (a) (partition p0 values less than (10) (subpartition s0), partition p1 values less than (20))`, errno.ErrPartitionWrongNoSubpart)
+	tk.MustGetErrCode(`create table t (a int, b varchar(10)) partition by range (a) subpartition by hash (b) subpartitions 2 (partition p0 values less than (10))`, errno.ErrFieldTypeNotAllowedAsPartitionField)
+	tk.MustGetErrCode(`create table t (a int) partition by range (a) subpartition by hash (a) subpartitions 2 (partition p0 values less than (10) (subpartition s0, subpartition p0))`, errno.ErrSameNamePartition)
+	tk.MustGetErrCode(`create table t (a int, b int, primary key (a)) partition by range (a) subpartition by hash (b) subpartitions 2 (partition p0 values less than (10))`, errno.ErrUniqueKeyNeedAllFieldsInPf)
+
 	tk.MustGetErrMsg(`create table t (a int) partition by hash (a) partitions 2 subpartition by key (a) subpartitions 2`, "[ddl:1500]It is only possible to mix RANGE/LIST partitioning with HASH/KEY partitioning for subpartitioning")
 	tk.MustGetErrMsg(`create table t (a int) partition by key (a) partitions 2 subpartition by hash (a) subpartitions 2`, "[ddl:1500]It is only possible to mix RANGE/LIST partitioning with HASH/KEY partitioning for subpartitioning")
 
@@ -1410,6 +1484,162 @@ func TestGlobalIndexUpdateInDropPartition(t *testing.T) {
 	tk.MustQuery("select * from test_global use index(idx_b) order by a").Check(testkit.Rows("2 11 11", "12 12 12"))
 }
 
+func TestExplicitGlobalIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists test_global, test_local, test_normal")
+	tk.MustExec(`create table test_global (a int, b int, c int, unique key idx_b (b) global, key idx_c (c) global)
+	partition by range( a ) (
+		partition p1 values less than (10),
+		partition p2 values less than (20)
+	)`)
+	tt := external.GetTableByName(t, tk, "test", "test_global")
+	require.True(t, tt.Meta().FindIndexByName("idx_b").Global)
+	require.True(t, tt.Meta().FindIndexByName("idx_c").Global)
+	tk.MustQuery("show create table test_global").Check(testkit.Rows("test_global CREATE TABLE `test_global` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  UNIQUE KEY `idx_b` (`b`) /*T![global_index] GLOBAL */,\n" +
+		"  KEY `idx_c` (`c`) /*T![global_index] GLOBAL */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`)\n" +
+		"(PARTITION `p1` VALUES LESS THAN (10),\n" +
+		" PARTITION `p2` VALUES LESS THAN (20))"))
+	tk.MustExec("insert into test_global values (1, 1, 1), (2, 2, 1), (11, 11, 1), (12, 12, 2)")
+	tk.MustQuery("select * from test_global use index(idx_c) where c = 1 order by a").Check(testkit.Rows("1 1 1", "2 2 1", "11 11 1"))
+	tk.MustExec("update test_global set a = 13 where a = 1")
+	tk.MustQuery("select * from test_global use index(idx_c) where c = 1 order by a").Check(testkit.Rows("2 2 1", "11 11 1", "13 1 1"))
+	tk.MustGetErrCode("insert into test_global values (3, 11, 3)", errno.ErrDupEntry)
+	tk.MustExec("create index idx_bc on test_global (b, c) global")
+	require.True(t, external.GetTableByName(t, tk, "test", "test_global").Meta().FindIndexByName("idx_bc").Global)
+	tk.MustQuery("select a from test_global use index(idx_bc) where b = 12").Check(testkit.Rows("12"))
+	tk.MustExec("admin check table test_global")
+	tk.MustExec("alter table test_global truncate partition p1")
+	tk.MustQuery("select a from test_global use index(idx_c) where c = 1 order by a").Check(testkit.Rows("11", "13"))
+	tk.MustExec("alter table test_global drop partition p2")
+	tk.MustQuery("select a from test_global use index(idx_c) where c = 1 order by a").Check(testkit.Rows())
+	tk.MustExec("admin check table test_global")
+
+	tk.MustGetErrCode(`create table test_local (a int, b int, unique key (b) local)
+	partition by hash(a) partitions 3`, errno.ErrUniqueKeyNeedAllFieldsInPf)
+	tk.MustExec(`create table test_local (a int, b int, unique key (a, b) local, key (b) local)
+	partition by hash(a) partitions 3`)
+	for _, idxInfo := range external.GetTableByName(t, tk, "test", "test_local").Meta().Indices {
+		require.False(t, idxInfo.Global)
+	}
+
+	tk.MustGetErrCode("create table test_normal (a int, b int, key (b) global)", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("create table test_normal (a int, b int)")
+	tk.MustGetErrCode("create index idx_b on test_normal (b) global", errno.ErrUnsupportedDDLOperation)
+}
+
+func TestReorganizePartitionWithGlobalIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists test_global")
+	tk.MustExec(`create table test_global (a int, b int, c int, unique key idx_b (b) global, key idx_c (c) global)
+	partition by range( a ) (
+		partition p1 values less than (10),
+		partition p2 values less than (20),
+		partition p3 values less than (30)
+	)`)
+	tk.MustExec("insert into test_global values (1, 1, 1), (5, 5, 2), (11, 11, 1), (15, 15, 2), (21, 21, 1)")
+	tt := external.GetTableByName(t, tk, "test", "test_global")
+	oldIdxB := tt.Meta().FindIndexByName("idx_b")
+	oldIdxC := tt.Meta().FindIndexByName("idx_c")
+
+	tk.MustExec("alter table test_global reorganize partition p1, p2 into (partition p0 values less than (5), partition p12 values less than (20))")
+	tt = external.GetTableByName(t, tk, "test", "test_global")
+	require.Len(t, tt.Meta().Indices, 2)
+	require.Len(t, tt.Meta().Partition.DDLChangedIndex, 0)
+	newIdxB := tt.Meta().FindIndexByName("idx_b")
+	newIdxC := tt.Meta().FindIndexByName("idx_c")
+	require.True(t, newIdxB.Global)
+	require.True(t, newIdxC.Global)
+	require.NotEqual(t, oldIdxB.ID, newIdxB.ID)
+	require.NotEqual(t, oldIdxC.ID, newIdxC.ID)
+	require.Equal(t, 5, checkGlobalIndexCleanUpDone(t, tk.Session(), tt.Meta(), newIdxB, 0))
+	require.Equal(t, 5, checkGlobalIndexCleanUpDone(t, tk.Session(), tt.Meta(), newIdxC, 0))
+
+	tk.MustQuery("select a from test_global use index(idx_c) where c = 1 order by a").Check(testkit.Rows("1", "11", "21"))
+	tk.MustQuery("select a from test_global use index(idx_b) where b = 15").Check(testkit.Rows("15"))
+	tk.MustGetErrCode("insert into test_global values (25, 5, 3)", errno.ErrDupEntry)
+	tk.MustExec("insert into test_global values (3, 3, 3)")
+	tk.MustExec("admin check table test_global")
+}
+
+func TestExchangePartitionWithGlobalIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists test_global, test_nt")
+	tk.MustExec(`create table test_global (a int, b int, c int, unique key idx_b (b) global, key idx_c (c) global)
+	partition by range( a ) (
+		partition p1 values less than (10),
+		partition p2 values less than (20)
+	)`)
+	tk.MustExec("insert into test_global values (1, 1, 1), (5, 5, 2), (11, 11, 1), (15, 15, 2)")
+	tk.MustExec("create table test_nt (a int, b int, c int)")
+	tk.MustExec("insert into test_nt values (2, 2, 1), (3, 11, 2)")
+
+	// The unique global index would get duplicate entries.
+	tk.MustGetErrCode("alter table test_global exchange partition p1 with table test_nt", errno.ErrDupEntry)
+	tt := external.GetTableByName(t, tk, "test", "test_global")
+	require.Len(t, tt.Meta().Indices, 2)
+	require.Nil(t, tt.Meta().ExchangePartitionInfo)
+	require.Nil(t, external.GetTableByName(t, tk, "test", "test_nt").Meta().ExchangePartitionInfo)
+	tk.MustExec("insert into test_nt values (4, 4, 4)")
+	tk.MustExec("delete from test_nt where a = 3 or a = 4")
+
+	tk.MustExec("alter table test_global exchange partition p1 with table test_nt")
+	tt = external.GetTableByName(t, tk, "test", "test_global")
+	require.Len(t, tt.Meta().Indices, 2)
+	require.Len(t, tt.Meta().Partition.DDLChangedIndex, 0)
+	require.Nil(t, tt.Meta().ExchangePartitionInfo)
+	idxB := tt.Meta().FindIndexByName("idx_b")
+	require.Equal(t, 3, checkGlobalIndexCleanUpDone(t, tk.Session(), tt.Meta(), idxB, 0))
+	tk.MustQuery("select * from test_nt order by a").Check(testkit.Rows("1 1 1", "5 5 2"))
+	tk.MustQuery("select a from test_global use index(idx_c) where c = 1 order by a").Check(testkit.Rows("2", "11"))
+	tk.MustQuery("select a from test_global use index(idx_b) where b = 5").Check(testkit.Rows())
+	tk.MustExec("insert into test_global values (5, 5, 2)")
+	tk.MustGetErrCode("insert into test_global values (6, 2, 2)", errno.ErrDupEntry)
+	tk.MustExec("admin check table test_global")
+	tk.MustExec("insert into test_nt values (6, 6, 6)")
+}
+
+func TestExchangePartitionWithGlobalIndexCollidingHandles(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table t (a int, b int, key idx_b (b) global)
+	partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20)
+	)`)
+	tk.MustExec("insert into t values (1, 100), (2, 200)")
+	// The rows of the exchanged table have the same handles and index values as the ones in p0.
+	tk.MustExec("create table nt (a int, b int)")
+	tk.MustExec("insert into nt values (11, 100), (12, 200)")
+	tk.MustExec("alter table t exchange partition p1 with table nt")
+	tk.MustQuery("select _tidb_rowid, a from t order by a").Check(testkit.Rows("1 1", "2 2", "1 11", "2 12"))
+	tk.MustQuery("select a from t use index(idx_b) where b = 100 order by a").Check(testkit.Rows("1", "11"))
+	tk.MustQuery("select a from t use index(idx_b) where b = 200 order by a").Check(testkit.Rows("2", "12"))
+	tk.MustExec("admin check table t")
+
+	// Deleting a row only removes its own index entry.
+	tk.MustExec("delete from t where a = 11")
+	tk.MustQuery("select a from t use index(idx_b) where b = 100").Check(testkit.Rows("1"))
+	tk.MustExec("update t set b = 300 where a = 2")
+	tk.MustQuery("select a from t use index(idx_b) where b = 200").Check(testkit.Rows("12"))
+	tk.MustQuery("select a from t use index(idx_b) where b = 300").Check(testkit.Rows("2"))
+	tk.MustExec("admin check table t")
+	tk.MustExec("set tidb_enable_fast_table_check = off")
+	tk.MustExec("admin check table t")
+}
+
 func TestTruncatePartitionWithGlobalIndex(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -2964,12 +3194,11 @@ func TestIssue40135Ver2(t *testing.T) {
 	dom.DDL().SetHook(hook)
 	tk.MustExec("alter table t40135 modify column a bigint NULL DEFAULT '6243108' FIRST")
 	wg.Wait()
-	require.ErrorContains(t, checkErr, "[ddl:8200]Unsupported modify column: table is partition table")
+	require.ErrorContains(t, checkErr, "[ddl:8200]Unsupported modify column, decreasing length of int may result in truncation and change of partition")
 	tk.MustExec("admin check table t40135")
 }
 
 func TestAlterModifyPartitionColTruncateWarning(t *testing.T) {
-	t.Skip("waiting for supporting Modify Partition Column again")
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
 	schemaName := "truncWarn"
@@ -2981,11 +3210,75 @@ func TestAlterModifyPartitionColTruncateWarning(t *testing.T) {
 	tk.MustContainErrMsg(`alter table t modify a varchar(5)`, "[types:1265]Data truncated for column 'a', value is '")
 	tk.MustExec(`set sql_mode = ''`)
 	tk.MustExec(`alter table t modify a varchar(5)`)
-	// Fix the duplicate warning, see https://github.com/pingcap/tidb/issues/38699
-	tk.MustQuery(`show warnings`).Check(testkit.Rows(""+
-		"Warning 1265 Data truncated for column 'a', value is ' 654321'",
-		"Warning 1265 Data truncated for column 'a', value is ' 654321'"))
+	// Both rows are truncated while they are copied to the new partitions.
+	tk.MustQuery(`show warnings`).Check(testkit.Rows(
+		"Warning 1265 2 warnings with this error code, first warning: Data truncated for column 'a', value is ' 654321'"))
+	tk.MustExec(`admin check table t`)
+	tk.MustQuery(`select a from t partition (p1)`).Check(testkit.Rows(" 6543"))
+	tk.MustQuery(`select a from t partition (p2)`).Check(testkit.Rows("12345"))
+	tk.MustQuery(`select a from t where a = '12345'`).Check(testkit.Rows("12345"))
+}
+
+func TestModifyColumnWithReorgOnPartitionedTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database ModifyColumnReorg")
+	tk.MustExec("use ModifyColumnReorg")
+	tk.MustExec(`create table t (id int, a int, b varchar(32), key (a), key (b, a)) partition by range (id) (partition p0 values less than (10), partition p1 values less than (20), partition p2 values less than (maxvalue))`)
+	tk.MustExec(`insert into t values (1, 2147483647, "a"), (11, -2147483648, "b"), (21, 0, "c")`)
+	tk.MustExec(`alter table t modify a bigint`)
+	tk.MustExec(`admin check table t`)
+	tk.MustExec(`insert into t values (2, 2147483648, "d")`)
+	tk.MustQuery(`select * from t partition (p0) order by id`).Check(testkit.Rows("1 2147483647 a", "2 2147483648 d"))
+	tk.MustQuery(`select id from t where a = -2147483648`).Check(testkit.Rows("11"))
+	// The values don't fit into the new type.
+	tk.MustContainErrMsg(`alter table t modify a int`, "[types:1690]constant 2147483648 overflows int")
 	tk.MustExec(`admin check table t`)
+
+	// The rows are hashed differently after changing the sign, so they are moved to new partitions.
+	tk.MustExec(`create table t1 (a int, b int, key (b)) partition by key (a) partitions 3`)
+	tk.MustExec(`create table t2 (a int unsigned, b int, key (b)) partition by key (a) partitions 3`)
+	for i := 1; i <= 20; i++ {
+		tk.MustExec(fmt.Sprintf(`insert into t1 values (%d, %d)`, i, i))
+		tk.MustExec(fmt.Sprintf(`insert into t2 values (%d, %d)`, i, i))
+	}
+	tk.MustExec(`alter table t1 modify a int unsigned`)
+	tk.MustExec(`admin check table t1`)
+	for _, part := range []string{"p0", "p1", "p2"} {
+		expected := tk.MustQuery(fmt.Sprintf(`select a, b from t2 partition (%s) order by a`, part)).Rows()
+		tk.MustQuery(fmt.Sprintf(`select a, b from t1 partition (%s) order by a`, part)).Check(expected)
+	}
+	tk.MustQuery(`select b from t1 where a = 5`).Check(testkit.Rows("5"))
+	tk.MustExec(`insert into t1 values (4294967295, 21)`)
+	tk.MustQuery(`select b from t1 where a = 4294967295`).Check(testkit.Rows("21"))
+
+	// The partitioning expression refers to the changing column while the rows are moved.
+	tk.MustExec(`create table t3 (a int, b int) partition by hash (a div 2) partitions 3`)
+	for i := 1; i <= 20; i++ {
+		tk.MustExec(fmt.Sprintf(`insert into t3 values (%d, %d)`, i, i))
+	}
+	tk.MustExec(`alter table t3 modify a bigint unsigned`)
+	tk.MustExec(`admin check table t3`)
+	tk.MustQuery(`show create table t3`).CheckContain("PARTITION BY HASH ((`a` DIV 2)) PARTITIONS 3")
+	tk.MustQuery(`select count(*) from t3 partition (p0)`).Check(testkit.Rows("7"))
+	tk.MustQuery(`select b from t3 where a = 5`).Check(testkit.Rows("5"))
+
+	// The values are compared differently after changing the collation, so the rows are moved to new partitions
+	// even if the column data isn't changed.
+	tk.MustExec(`create table t4 (a varchar(32) collate utf8mb4_bin, b int, key (b)) partition by range columns (a) (partition p0 values less than ('M'), partition p1 values less than (maxvalue))`)
+	tk.MustExec(`create table t5 (a varchar(32) collate utf8mb4_general_ci, b int, key (b)) partition by range columns (a) (partition p0 values less than ('M'), partition p1 values less than (maxvalue))`)
+	for i, v := range []string{"a", "b", "z", "A", "B", "Z", "m", "N"} {
+		tk.MustExec(fmt.Sprintf(`insert into t4 values ('%s', %d)`, v, i))
+		tk.MustExec(fmt.Sprintf(`insert into t5 values ('%s', %d)`, v, i))
+	}
+	tk.MustQuery(`select a from t4 partition (p0) order by a`).Check(testkit.Rows("A", "B"))
+	tk.MustExec(`alter table t4 modify a varchar(32) collate utf8mb4_general_ci`)
+	tk.MustExec(`admin check table t4`)
+	for _, part := range []string{"p0", "p1"} {
+		expected := tk.MustQuery(fmt.Sprintf(`select a, b from t5 partition (%s) order by b`, part)).Rows()
+		tk.MustQuery(fmt.Sprintf(`select a, b from t4 partition (%s) order by b`, part)).Check(expected)
+	}
+	tk.MustQuery(`select b from t4 where a = 'z'`).Sort().Check(testkit.Rows("2", "5"))
 }
 
 func TestRemoveKeyPartitioning(t *testing.T) {
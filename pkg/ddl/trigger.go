@@ -0,0 +1,238 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+)
+
+// CreateTrigger creates a trigger on a table.
+func (d *ddl) CreateTrigger(ctx sessionctx.Context, s *ast.CreateTriggerStmt) error {
+	if s.TriggerName.Schema.L != s.Table.Schema.L {
+		return dbterror.ErrTrgInWrongSchema
+	}
+	if util.IsMemOrSysDB(s.Table.Schema.L) {
+		return dbterror.ErrNoTriggersOnSystemSchema
+	}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := tb.Meta()
+	if tblInfo.IsView() || tblInfo.IsSequence() || tblInfo.TempTableType != model.TempTableNone {
+		return dbterror.ErrTrgOnViewOrTempTable.GenWithStackByArgs(s.Table.Name.O)
+	}
+	// The trigger names are unique within a schema.
+	if findTriggerInSchema(is, schema.Name, s.TriggerName.Name) != nil {
+		if s.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(dbterror.ErrTrgAlreadyExists)
+			return nil
+		}
+		return dbterror.ErrTrgAlreadyExists
+	}
+	if s.Order != ast.TriggerOrderNone {
+		other := tblInfo.FindTriggerByName(s.OtherTrigger.L)
+		if other == nil || other.Timing != s.Timing || other.Event != s.Event {
+			return dbterror.ErrReferencedTrgDoesNotExist.GenWithStackByArgs(s.OtherTrigger.O)
+		}
+	}
+
+	sessVars := ctx.GetSessionVars()
+	trigger := &model.TriggerInfo{
+		Name:       s.TriggerName.Name,
+		Timing:     s.Timing,
+		Event:      s.Event,
+		Definer:    s.Definer,
+		Definition: buildTriggerDefinition(s),
+		Body:       s.Body.Text(),
+		Created:    time.Now(),
+	}
+	trigger.SQLMode, _ = sessVars.GetSystemVar(variable.SQLModeVar)
+	trigger.Charset, _ = sessVars.GetSystemVar(variable.CharacterSetClient)
+	trigger.Collation, _ = sessVars.GetSystemVar(variable.CollationConnection)
+
+	job := &model.Job{
+		SchemaID:       schema.ID,
+		TableID:        tblInfo.ID,
+		SchemaName:     schema.Name.L,
+		TableName:      tblInfo.Name.L,
+		Type:           model.ActionCreateTrigger,
+		BinlogInfo:     &model.HistoryInfo{},
+		Args:           []any{trigger, s.Order, s.OtherTrigger},
+		CDCWriteSource: sessVars.CDCWriteSource,
+		SQLMode:        sessVars.SQLMode,
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// DropTrigger drops a trigger.
+func (d *ddl) DropTrigger(ctx sessionctx.Context, s *ast.DropTriggerStmt) error {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(s.TriggerName.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.TriggerName.Schema)
+	}
+	tblInfo := findTriggerInSchema(is, schema.Name, s.TriggerName.Name)
+	if tblInfo == nil {
+		if s.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(dbterror.ErrTrgDoesNotExist)
+			return nil
+		}
+		return dbterror.ErrTrgDoesNotExist
+	}
+
+	job := &model.Job{
+		SchemaID:       schema.ID,
+		TableID:        tblInfo.ID,
+		SchemaName:     schema.Name.L,
+		TableName:      tblInfo.Name.L,
+		Type:           model.ActionDropTrigger,
+		BinlogInfo:     &model.HistoryInfo{},
+		Args:           []any{s.TriggerName.Name},
+		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:        ctx.GetSessionVars().SQLMode,
+	}
+	err := d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// findTriggerInSchema returns the table on which the trigger is defined, or nil if the trigger doesn't exist.
+func findTriggerInSchema(is infoschema.InfoSchema, schema, trigger model.CIStr) *model.TableInfo {
+	for _, tblInfo := range is.SchemaTableInfos(schema) {
+		if tblInfo.FindTriggerByName(trigger.L) != nil {
+			return tblInfo
+		}
+	}
+	return nil
+}
+
+// buildTriggerDefinition builds the CREATE TRIGGER statement stored in the trigger info. The body is kept as it's
+// written by the user, and the FOLLOWS or PRECEDES clause is dropped because it's reflected by the order of the
+// triggers in the table info.
+func buildTriggerDefinition(s *ast.CreateTriggerStmt) string {
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.RestoreStringSingleQuotes|format.RestoreKeyWordUppercase|format.RestoreNameBackQuotes, &sb)
+	restoreCtx.WriteKeyWord("CREATE DEFINER")
+	restoreCtx.WritePlain("=")
+	// The definer has been resolved by the plan builder, so the restoration never fails.
+	_ = s.Definer.Restore(restoreCtx)
+	restoreCtx.WriteKeyWord(" TRIGGER ")
+	restoreCtx.WriteName(s.TriggerName.Name.O)
+	restoreCtx.WritePlain(" ")
+	restoreCtx.WriteKeyWord(s.Timing.String())
+	restoreCtx.WritePlain(" ")
+	restoreCtx.WriteKeyWord(s.Event.String())
+	restoreCtx.WriteKeyWord(" ON ")
+	restoreCtx.WriteName(s.Table.Name.O)
+	restoreCtx.WriteKeyWord(" FOR EACH ROW ")
+	sb.WriteString(s.Body.Text())
+	return sb.String()
+}
+
+func onCreateTrigger(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	trigger := &model.TriggerInfo{}
+	var order ast.TriggerOrderType
+	var otherTrigger model.CIStr
+	if err := job.DecodeArgs(trigger, &order, &otherTrigger); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if tblInfo.FindTriggerByName(trigger.Name.L) != nil {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrTrgAlreadyExists
+	}
+
+	// The new trigger is activated after the existing triggers with the same timing and event by default.
+	pos := len(tblInfo.Triggers)
+	if order != ast.TriggerOrderNone {
+		pos = -1
+		for i, other := range tblInfo.Triggers {
+			if other.Name.L == otherTrigger.L && other.Timing == trigger.Timing && other.Event == trigger.Event {
+				pos = i
+				if order == ast.TriggerOrderFollows {
+					pos++
+				}
+				break
+			}
+		}
+		if pos < 0 {
+			job.State = model.JobStateCancelled
+			return ver, dbterror.ErrReferencedTrgDoesNotExist.GenWithStackByArgs(otherTrigger.O)
+		}
+	}
+	triggers := make([]*model.TriggerInfo, 0, len(tblInfo.Triggers)+1)
+	triggers = append(triggers, tblInfo.Triggers[:pos]...)
+	triggers = append(triggers, trigger)
+	tblInfo.Triggers = append(triggers, tblInfo.Triggers[pos:]...)
+
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropTrigger(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var name model.CIStr
+	if err := job.DecodeArgs(&name); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	triggers := make([]*model.TriggerInfo, 0, len(tblInfo.Triggers))
+	for _, trigger := range tblInfo.Triggers {
+		if trigger.Name.L != name.L {
+			triggers = append(triggers, trigger)
+		}
+	}
+	if len(triggers) == len(tblInfo.Triggers) {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrTrgDoesNotExist
+	}
+	tblInfo.Triggers = triggers
+
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
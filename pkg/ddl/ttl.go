@@ -16,9 +16,12 @@ package ddl
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/meta"
 	"github.com/pingcap/tidb/pkg/parser"
@@ -52,8 +55,10 @@ func onTTLInfoChange(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err er
 	var ttlInfo *model.TTLInfo
 	var ttlInfoEnable *bool
 	var ttlInfoJobInterval *string
+	var ttlInfoArchive *string
+	var ttlInfoArchiveFormat *string
 
-	if err := job.DecodeArgs(&ttlInfo, &ttlInfoEnable, &ttlInfoJobInterval); err != nil {
+	if err := job.DecodeArgs(&ttlInfo, &ttlInfoEnable, &ttlInfoJobInterval, &ttlInfoArchive, &ttlInfoArchiveFormat); err != nil {
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
@@ -71,6 +76,12 @@ func onTTLInfoChange(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err er
 		if ttlInfoJobInterval == nil && tblInfo.TTLInfo != nil {
 			ttlInfo.JobInterval = tblInfo.TTLInfo.JobInterval
 		}
+		if ttlInfoArchive == nil && tblInfo.TTLInfo != nil {
+			ttlInfo.Archive = tblInfo.TTLInfo.Archive
+		}
+		if ttlInfoArchiveFormat == nil && tblInfo.TTLInfo != nil {
+			ttlInfo.ArchiveFormat = tblInfo.TTLInfo.ArchiveFormat
+		}
 		tblInfo.TTLInfo = ttlInfo
 	}
 	if ttlInfoEnable != nil {
@@ -87,6 +98,20 @@ func onTTLInfoChange(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err er
 
 		tblInfo.TTLInfo.JobInterval = *ttlInfoJobInterval
 	}
+	if ttlInfoArchive != nil {
+		if tblInfo.TTLInfo == nil {
+			return ver, errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_ARCHIVE"))
+		}
+
+		tblInfo.TTLInfo.Archive = *ttlInfoArchive
+	}
+	if ttlInfoArchiveFormat != nil {
+		if tblInfo.TTLInfo == nil {
+			return ver, errors.Trace(dbterror.ErrSetTTLOptionForNonTTLTable.FastGenByArgs("TTL_ARCHIVE_FORMAT"))
+		}
+
+		tblInfo.TTLInfo.ArchiveFormat = *ttlInfoArchiveFormat
+	}
 
 	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
 	if err != nil {
@@ -105,7 +130,7 @@ func checkTTLInfoValid(ctx sessionctx.Context, schema model.CIStr, tblInfo *mode
 		return err
 	}
 
-	return checkTTLInfoColumnType(tblInfo)
+	return checkTTLInfoColumnType(ctx, tblInfo)
 }
 
 func checkTTLIntervalExpr(ctx expression.BuildContext, ttlInfo *model.TTLInfo) error {
@@ -124,18 +149,147 @@ func checkTTLIntervalExpr(ctx expression.BuildContext, ttlInfo *model.TTLInfo) e
 	return err
 }
 
-func checkTTLInfoColumnType(tblInfo *model.TableInfo) error {
-	colInfo := findColumnByName(tblInfo.TTLInfo.ColumnName.L, tblInfo)
+func checkTTLInfoColumnType(ctx sessionctx.Context, tblInfo *model.TableInfo) error {
+	ttlInfo := tblInfo.TTLInfo
+	if ttlInfo.Expr != "" {
+		return checkTTLInfoExpr(ctx, tblInfo)
+	}
+
+	colInfo := findColumnByName(ttlInfo.ColumnName.L, tblInfo)
 	if colInfo == nil {
-		return dbterror.ErrBadField.GenWithStackByArgs(tblInfo.TTLInfo.ColumnName.O, "TTL config")
+		return dbterror.ErrBadField.GenWithStackByArgs(ttlInfo.ColumnName.O, "TTL config")
+	}
+	if !isTTLTimeType(&colInfo.FieldType, ttlInfo.EpochUnit) {
+		return dbterror.ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(ttlInfo.ColumnName.O)
+	}
+
+	return nil
+}
+
+// checkTTLInfoExpr checks the TTL time expression, which should only refer to the columns of the table and
+// should be evaluated to a time type, or an integer if the `TTL_EPOCH_UNIT` is set.
+func checkTTLInfoExpr(ctx sessionctx.Context, tblInfo *model.TableInfo) error {
+	ttlInfo := tblInfo.TTLInfo
+	astExpr, err := parseExprString(ttlInfo.Expr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkIllegalFn4Generated("TTL config", typeColumn, astExpr); err != nil {
+		return errors.Trace(err)
 	}
-	if !types.IsTypeTime(colInfo.FieldType.GetType()) {
-		return dbterror.ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(tblInfo.TTLInfo.ColumnName.O)
+	for _, colName := range FindColumnNamesInExpr(astExpr) {
+		if findColumnByName(colName.Name.L, tblInfo) == nil {
+			return dbterror.ErrBadField.GenWithStackByArgs(colName.Name.O, "TTL config")
+		}
 	}
 
+	expr, err := expression.BuildSimpleExpr(ctx.GetExprCtx(), astExpr, expression.WithTableInfo("", tblInfo))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isTTLTimeType(expr.GetType(), ttlInfo.EpochUnit) {
+		return dbterror.ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(ttlInfo.Expr)
+	}
 	return nil
 }
 
+// isTTLTimeType returns whether a column or an expression with the field type can be used for TTL. It should be
+// DATETIME, DATE or TIMESTAMP, or an integer storing the unix epoch time in `epochUnit`.
+func isTTLTimeType(tp *types.FieldType, epochUnit string) bool {
+	if epochUnit != "" {
+		return mysql.IsIntegerType(tp.GetType())
+	}
+	return types.IsTypeTime(tp.GetType())
+}
+
+// parseExprString parses the expression of the TTL or partition info.
+func parseExprString(exprStr string) (ast.ExprNode, error) {
+	stmts, _, err := parser.New().ParseSQL("select " + exprStr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return stmts[0].(*ast.SelectStmt).Fields.Fields[0].Expr, nil
+}
+
+// getTTLExprColumnNames returns the names of the columns referred by the TTL config.
+func getTTLExprColumnNames(ttlInfo *model.TTLInfo) []model.CIStr {
+	if ttlInfo.Expr == "" {
+		return []model.CIStr{ttlInfo.ColumnName}
+	}
+	astExpr, err := parseExprString(ttlInfo.Expr)
+	if err != nil {
+		return nil
+	}
+	colNames := FindColumnNamesInExpr(astExpr)
+	names := make([]model.CIStr, 0, len(colNames))
+	for _, colName := range colNames {
+		names = append(names, colName.Name)
+	}
+	return names
+}
+
+// checkModifyColumnWithTTLConfig checks whether the TTL config is still valid after the column is modified.
+func checkModifyColumnWithTTLConfig(ctx sessionctx.Context, tblInfo *model.TableInfo, oldColName model.CIStr, newCol *model.ColumnInfo) error {
+	ttlInfo := tblInfo.TTLInfo
+	if ttlInfo == nil {
+		return nil
+	}
+	if ttlInfo.Expr == "" {
+		// the column referenced by TTL should be a time type
+		if ttlInfo.ColumnName.L == oldColName.L && !isTTLTimeType(&newCol.FieldType, ttlInfo.EpochUnit) {
+			return dbterror.ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(newCol.Name.O)
+		}
+		return nil
+	}
+
+	referred := slices.ContainsFunc(getTTLExprColumnNames(ttlInfo), func(name model.CIStr) bool {
+		return name.L == oldColName.L
+	})
+	if !referred {
+		return nil
+	}
+	newTblInfo := tblInfo.Clone()
+	for i, col := range newTblInfo.Columns {
+		if col.Name.L == oldColName.L {
+			newTblInfo.Columns[i] = newCol
+		}
+	}
+	updateTTLInfoWhenModifyColumn(newTblInfo, oldColName, newCol.Name)
+	return checkTTLInfoExpr(ctx, newTblInfo)
+}
+
+// renameExprColumn returns the expression with the column renamed. It's used by the TTL and partition expressions.
+func renameExprColumn(exprStr string, oldCol, newCol model.CIStr) (string, error) {
+	astExpr, err := parseExprString(exprStr)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	astExpr.Accept(&exprColumnRenamer{oldCol: oldCol, newCol: newCol})
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreNameBackQuotes | format.RestoreKeyWordLowercase
+	if err := astExpr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
+
+type exprColumnRenamer struct {
+	oldCol model.CIStr
+	newCol model.CIStr
+}
+
+func (*exprColumnRenamer) Enter(inNode ast.Node) (outNode ast.Node, skipChildren bool) {
+	return inNode, false
+}
+
+func (r *exprColumnRenamer) Leave(inNode ast.Node) (node ast.Node, ok bool) {
+	if x, ok := inNode.(*ast.ColumnName); ok && x.Name.L == r.oldCol.L {
+		x.Name = r.newCol
+	}
+	return inNode, true
+}
+
 // checkTTLTableSuitable returns whether this table is suitable to be a TTL table
 // A temporary table or a parent table referenced by a foreign key cannot be TTL table
 func checkTTLTableSuitable(ctx sessionctx.Context, schema model.CIStr, tblInfo *model.TableInfo) error {
@@ -158,8 +312,10 @@ func checkTTLTableSuitable(ctx sessionctx.Context, schema model.CIStr, tblInfo *
 
 func checkDropColumnWithTTLConfig(tblInfo *model.TableInfo, colName string) error {
 	if tblInfo.TTLInfo != nil {
-		if tblInfo.TTLInfo.ColumnName.L == colName {
-			return dbterror.ErrTTLColumnCannotDrop.GenWithStackByArgs(colName)
+		for _, name := range getTTLExprColumnNames(tblInfo.TTLInfo) {
+			if name.L == colName {
+				return dbterror.ErrTTLColumnCannotDrop.GenWithStackByArgs(colName)
+			}
 		}
 	}
 
@@ -192,10 +348,13 @@ func checkPrimaryKeyForTTLTable(tblInfo *model.TableInfo) error {
 }
 
 // getTTLInfoInOptions returns the aggregated ttlInfo, the ttlEnable, or an error.
-// if TTL, TTL_ENABLE or TTL_JOB_INTERVAL is not set in the config, the corresponding return value will be nil.
+// if TTL, TTL_ENABLE, TTL_JOB_INTERVAL, TTL_ARCHIVE or TTL_ARCHIVE_FORMAT is not set in the config, the corresponding return value will be nil.
 // if both of TTL and TTL_ENABLE are set, the `ttlInfo.Enable` will be equal with `ttlEnable`.
 // if both of TTL and TTL_JOB_INTERVAL are set, the `ttlInfo.JobInterval` will be equal with `ttlCronJobSchedule`.
-func getTTLInfoInOptions(options []*ast.TableOption) (ttlInfo *model.TTLInfo, ttlEnable *bool, ttlCronJobSchedule *string, err error) {
+// if both of TTL and TTL_ARCHIVE are set, the `ttlInfo.Archive` will be equal with `ttlArchive`.
+// if both of TTL and TTL_ARCHIVE_FORMAT are set, the `ttlInfo.ArchiveFormat` will be equal with `ttlArchiveFormat`.
+func getTTLInfoInOptions(options []*ast.TableOption) (ttlInfo *model.TTLInfo, ttlEnable *bool, ttlCronJobSchedule *string, ttlArchive *string, ttlArchiveFormat *string, err error) {
+	var ttlEpochUnit *string
 	for _, op := range options {
 		switch op.Tp {
 		case ast.TableOptionTTL:
@@ -204,21 +363,40 @@ func getTTLInfoInOptions(options []*ast.TableOption) (ttlInfo *model.TTLInfo, tt
 			restoreCtx := format.NewRestoreCtx(restoreFlags, &sb)
 			err := op.Value.Restore(restoreCtx)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, nil, err
 			}
 
 			intervalExpr := sb.String()
 			ttlInfo = &model.TTLInfo{
-				ColumnName:       op.ColumnName.Name,
 				IntervalExprStr:  intervalExpr,
 				IntervalTimeUnit: int(op.TimeUnitValue.Unit),
 				Enable:           true,
 				JobInterval:      "1h",
 			}
+			if op.TTLExpr != nil {
+				sb.Reset()
+				restoreCtx = format.NewRestoreCtx(restoreFlags|format.RestoreKeyWordLowercase, &sb)
+				if err := op.TTLExpr.Restore(restoreCtx); err != nil {
+					return nil, nil, nil, nil, nil, err
+				}
+				ttlInfo.Expr = sb.String()
+			} else {
+				ttlInfo.ColumnName = op.ColumnName.Name
+			}
+		case ast.TableOptionTTLEpochUnit:
+			ttlEpochUnit = &op.StrValue
 		case ast.TableOptionTTLEnable:
 			ttlEnable = &op.BoolValue
 		case ast.TableOptionTTLJobInterval:
 			ttlCronJobSchedule = &op.StrValue
+		case ast.TableOptionTTLArchive:
+			archive, err := normalizeTTLArchive(op.StrValue)
+			if err != nil {
+				return nil, nil, nil, nil, nil, err
+			}
+			ttlArchive = &archive
+		case ast.TableOptionTTLArchiveFormat:
+			ttlArchiveFormat = &op.StrValue
 		}
 	}
 
@@ -229,6 +407,56 @@ func getTTLInfoInOptions(options []*ast.TableOption) (ttlInfo *model.TTLInfo, tt
 		if ttlCronJobSchedule != nil {
 			ttlInfo.JobInterval = *ttlCronJobSchedule
 		}
+		if ttlEpochUnit != nil {
+			ttlInfo.EpochUnit = *ttlEpochUnit
+		}
+		if ttlArchive != nil {
+			ttlInfo.Archive = *ttlArchive
+		}
+		if ttlArchiveFormat != nil {
+			ttlInfo.ArchiveFormat = *ttlArchiveFormat
+		}
+	} else if ttlEpochUnit != nil {
+		// the epoch unit describes the TTL column or expression, so it can only be set together with them.
+		return nil, nil, nil, nil, nil, dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("setting TTL_EPOCH_UNIT without TTL")
+	}
+	return ttlInfo, ttlEnable, ttlCronJobSchedule, ttlArchive, ttlArchiveFormat, nil
+}
+
+// ttlArchiveCredentialKeys are the query parameters of an external storage URL that carry secrets. They are
+// compared after being lowercased and having `_` replaced by `-`, the same way as the storage options are parsed.
+var ttlArchiveCredentialKeys = map[string]struct{}{
+	"access-key":        {},
+	"secret-access-key": {},
+	"session-token":     {},
+	"account-key":       {},
+	"sas-token":         {},
+	"encryption-key":    {},
+}
+
+// normalizeTTLArchive checks whether the `TTL_ARCHIVE` is a valid external storage URL, and returns it without the
+// user info and the credential query parameters, because the table info is readable by everyone who can see the table.
+// An empty string is also valid, which means the expired rows will not be archived.
+func normalizeTTLArchive(archive string) (string, error) {
+	if archive == "" {
+		return "", nil
+	}
+	if _, err := storage.ParseBackend(archive, nil); err != nil {
+		return "", errors.Annotatef(err, "invalid TTL_ARCHIVE '%s'", ast.RedactURL(archive))
+	}
+	u, err := url.Parse(archive)
+	if err != nil {
+		return "", errors.Annotatef(err, "invalid TTL_ARCHIVE '%s'", ast.RedactURL(archive))
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		values := u.Query()
+		for key := range values {
+			if _, ok := ttlArchiveCredentialKeys[strings.ReplaceAll(strings.ToLower(key), "_", "-")]; ok {
+				values.Del(key)
+			}
+		}
+		u.RawQuery = values.Encode()
 	}
-	return ttlInfo, ttlEnable, ttlCronJobSchedule, nil
+	return u.String(), nil
 }
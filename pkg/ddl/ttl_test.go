@@ -26,12 +26,17 @@ func Test_getTTLInfoInOptions(t *testing.T) {
 	falseValue := false
 	trueValue := true
 	twentyFourHours := "24h"
+	archive := "s3://bucket/archive"
+	archiveWithoutCredentials := "s3://bucket/archive?endpoint=http%3A%2F%2F127.0.0.1%3A9000"
+	parquet := "PARQUET"
 
 	cases := []struct {
 		options            []*ast.TableOption
 		ttlInfo            *model.TTLInfo
 		ttlEnable          *bool
 		ttlCronJobSchedule *string
+		ttlArchive         *string
+		ttlArchiveFormat   *string
 		err                error
 	}{
 		{
@@ -40,6 +45,8 @@ func Test_getTTLInfoInOptions(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
+			nil,
 		},
 		{
 			[]*ast.TableOption{
@@ -60,6 +67,8 @@ func Test_getTTLInfoInOptions(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
+			nil,
 		},
 		{
 			[]*ast.TableOption{
@@ -84,6 +93,8 @@ func Test_getTTLInfoInOptions(t *testing.T) {
 			&falseValue,
 			nil,
 			nil,
+			nil,
+			nil,
 		},
 		{
 			[]*ast.TableOption{
@@ -112,6 +123,8 @@ func Test_getTTLInfoInOptions(t *testing.T) {
 			&trueValue,
 			nil,
 			nil,
+			nil,
+			nil,
 		},
 		{
 			[]*ast.TableOption{
@@ -136,15 +149,158 @@ func Test_getTTLInfoInOptions(t *testing.T) {
 			nil,
 			&twentyFourHours,
 			nil,
+			nil,
+			nil,
+		},
+		{
+			[]*ast.TableOption{
+				{
+					Tp:            ast.TableOptionTTL,
+					ColumnName:    &ast.ColumnName{Name: model.NewCIStr("test_column")},
+					Value:         ast.NewValueExpr(5, "", ""),
+					TimeUnitValue: &ast.TimeUnitExpr{Unit: ast.TimeUnitYear},
+				},
+				{
+					Tp:       ast.TableOptionTTLArchive,
+					StrValue: "s3://bucket/archive",
+				},
+			},
+			&model.TTLInfo{
+				ColumnName:       model.NewCIStr("test_column"),
+				IntervalExprStr:  "5",
+				IntervalTimeUnit: int(ast.TimeUnitYear),
+				Enable:           true,
+				JobInterval:      "1h",
+				Archive:          "s3://bucket/archive",
+			},
+			nil,
+			nil,
+			&archive,
+			nil,
+			nil,
+		},
+		{
+			[]*ast.TableOption{
+				{
+					Tp:       ast.TableOptionTTLArchive,
+					StrValue: "",
+				},
+			},
+			nil,
+			nil,
+			nil,
+			new(string),
+			nil,
+			nil,
+		},
+		{
+			[]*ast.TableOption{
+				{
+					Tp:            ast.TableOptionTTL,
+					ColumnName:    &ast.ColumnName{Name: model.NewCIStr("test_column")},
+					Value:         ast.NewValueExpr(90, "", ""),
+					TimeUnitValue: &ast.TimeUnitExpr{Unit: ast.TimeUnitDay},
+				},
+				{
+					Tp:       ast.TableOptionTTLEpochUnit,
+					StrValue: "MILLISECOND",
+				},
+			},
+			&model.TTLInfo{
+				ColumnName:       model.NewCIStr("test_column"),
+				IntervalExprStr:  "90",
+				IntervalTimeUnit: int(ast.TimeUnitDay),
+				Enable:           true,
+				JobInterval:      "1h",
+				EpochUnit:        "MILLISECOND",
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		},
+		{
+			[]*ast.TableOption{
+				{
+					Tp: ast.TableOptionTTL,
+					TTLExpr: &ast.FuncCallExpr{
+						FnName: model.NewCIStr("from_unixtime"),
+						Args:   []ast.ExprNode{&ast.ColumnNameExpr{Name: &ast.ColumnName{Name: model.NewCIStr("test_column")}}},
+					},
+					Value:         ast.NewValueExpr(5, "", ""),
+					TimeUnitValue: &ast.TimeUnitExpr{Unit: ast.TimeUnitYear},
+				},
+			},
+			&model.TTLInfo{
+				Expr:             "from_unixtime(`test_column`)",
+				IntervalExprStr:  "5",
+				IntervalTimeUnit: int(ast.TimeUnitYear),
+				Enable:           true,
+				JobInterval:      "1h",
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		},
+		{
+			[]*ast.TableOption{
+				{
+					Tp:            ast.TableOptionTTL,
+					ColumnName:    &ast.ColumnName{Name: model.NewCIStr("test_column")},
+					Value:         ast.NewValueExpr(5, "", ""),
+					TimeUnitValue: &ast.TimeUnitExpr{Unit: ast.TimeUnitYear},
+				},
+				{
+					Tp:       ast.TableOptionTTLArchive,
+					StrValue: "s3://bucket/archive?access-key=ak&Secret_Access_Key=sk&session-token=st&endpoint=http://127.0.0.1:9000",
+				},
+				{
+					Tp:       ast.TableOptionTTLArchiveFormat,
+					StrValue: "PARQUET",
+				},
+			},
+			&model.TTLInfo{
+				ColumnName:       model.NewCIStr("test_column"),
+				IntervalExprStr:  "5",
+				IntervalTimeUnit: int(ast.TimeUnitYear),
+				Enable:           true,
+				JobInterval:      "1h",
+				Archive:          archiveWithoutCredentials,
+				ArchiveFormat:    "PARQUET",
+			},
+			nil,
+			nil,
+			&archiveWithoutCredentials,
+			&parquet,
+			nil,
+		},
+		{
+			[]*ast.TableOption{
+				{
+					Tp:       ast.TableOptionTTLArchiveFormat,
+					StrValue: "PARQUET",
+				},
+			},
+			nil,
+			nil,
+			nil,
+			nil,
+			&parquet,
+			nil,
 		},
 	}
 
 	for _, c := range cases {
-		ttlInfo, ttlEnable, ttlCronJobSchedule, err := getTTLInfoInOptions(c.options)
+		ttlInfo, ttlEnable, ttlCronJobSchedule, ttlArchive, ttlArchiveFormat, err := getTTLInfoInOptions(c.options)
 
 		assert.Equal(t, c.ttlInfo, ttlInfo)
 		assert.Equal(t, c.ttlEnable, ttlEnable)
 		assert.Equal(t, c.ttlCronJobSchedule, ttlCronJobSchedule)
+		assert.Equal(t, c.ttlArchive, ttlArchive)
+		assert.Equal(t, c.ttlArchiveFormat, ttlArchiveFormat)
 		assert.Equal(t, c.err, err)
 	}
 }
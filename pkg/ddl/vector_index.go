@@ -0,0 +1,383 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	sess "github.com/pingcap/tidb/pkg/ddl/internal/session"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta"
+	"github.com/pingcap/tidb/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	decoder "github.com/pingcap/tidb/pkg/util/rowDecoder"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
+	kvutil "github.com/tikv/client-go/v2/util"
+	"go.uber.org/zap"
+)
+
+// TrainVectorIndex trains the centroids of a vector index, which is added untrained. It's called after the index is
+// added, and by ANALYZE TABLE if there were too few rows in the table then.
+func (d *ddl) TrainVectorIndex(ctx sessionctx.Context, schema, table, index model.CIStr) error {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	db, ok := is.SchemaByName(schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema)
+	}
+	tbl, err := is.TableByName(schema, table)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	indexInfo := tbl.Meta().FindIndexByName(index.L)
+	if indexInfo == nil || indexInfo.VectorInfo == nil {
+		return infoschema.ErrKeyNotExists.GenWithStackByArgs(index.O, table.O)
+	}
+	if len(indexInfo.VectorInfo.Centroids) > 0 {
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:       db.ID,
+		TableID:        tbl.Meta().ID,
+		SchemaName:     db.Name.L,
+		TableName:      tbl.Meta().Name.L,
+		Type:           model.ActionTrainVectorIndex,
+		BinlogInfo:     &model.HistoryInfo{},
+		ReorgMeta:      NewDDLReorgMeta(ctx),
+		Args:           []any{index},
+		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:        ctx.GetSessionVars().SQLMode,
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// onTrainVectorIndex trains the centroids of a public vector index, and reassigns the rows in the untrained list to
+// the lists of their centroids. Both are done in the reorganization:
+//
+//  1. write reorganization: the centroids are trained with the rows sampled from the table.
+//  2. write only: the centroids are pending, the rows are still indexed in the untrained list while some instances
+//     don't know the centroids, but the entries are deleted from both the untrained list and the list of the vector
+//     by the instances which know.
+//  3. delete reorganization: the centroids are used, and the rows in the untrained list are moved to the lists of
+//     their centroids.
+//
+// The job can only be cancelled before the centroids are set.
+func (w *worker) onTrainVectorIndex(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var indexName model.CIStr
+	if err := job.DecodeArgs(&indexName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo == nil || indexInfo.VectorInfo == nil || indexInfo.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, infoschema.ErrKeyNotExists.GenWithStackByArgs(indexName.O, tblInfo.Name.O)
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		if len(indexInfo.VectorInfo.Centroids) > 0 {
+			job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+			return ver, nil
+		}
+		// none -> write reorganization
+		job.ReorgMeta.ReorgTp = model.ReorgTypeTxn
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		reorgInfo, done, err := w.runVectorIndexReorgJob(d, job, dbInfo, tblInfo, indexInfo, w.trainVectorIndex)
+		if err != nil {
+			if kv.IsTxnRetryableError(err) || dbterror.ErrNotOwner.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			logutil.BgLogger().Warn("run train vector index job failed, cancel the job", zap.String("category", "ddl"),
+				zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if !done {
+			return ver, nil
+		}
+		if len(reorgInfo.vectorCentroids) == 0 {
+			// There are still too few rows, the index is left untrained.
+			job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+			return ver, nil
+		}
+		// write reorganization -> write only
+		indexInfo.VectorInfo.Centroids = reorgInfo.vectorCentroids
+		indexInfo.VectorInfo.PendingCentroids = true
+		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> delete reorganization
+		indexInfo.VectorInfo.PendingCentroids = false
+		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// The rows are reassigned with a new snapshot, which has all the rows written in the untrained list.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateDeleteReorganization
+	case model.StateDeleteReorganization:
+		_, done, err := w.runVectorIndexReorgJob(d, job, dbInfo, tblInfo, indexInfo, w.reassignVectorIndex)
+		if err != nil {
+			if kv.IsTxnRetryableError(err) || dbterror.ErrNotOwner.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			// The centroids can't be rolled back. The rows left in the untrained list are still found by the searches
+			// and deleted by the writes, so the job is finished.
+			logutil.BgLogger().Warn("reassign the rows of vector index failed, leave them in the untrained list",
+				zap.String("category", "ddl"), zap.String("job", job.String()), zap.Error(err))
+		} else if !done {
+			return ver, nil
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		return ver, dbterror.ErrInvalidDDLState.GenWithStackByArgs("vector index", job.SchemaState)
+	}
+	return ver, nil
+}
+
+// runVectorIndexReorgJob runs f in the reorganization of the vector index, it returns done when f is finished.
+func (w *worker) runVectorIndexReorgJob(d *ddlCtx, job *model.Job, dbInfo *model.DBInfo, tblInfo *model.TableInfo,
+	indexInfo *model.IndexInfo, f func(table.Table, *model.IndexInfo, *reorgInfo) error) (*reorgInfo, bool, error) {
+	sctx, err := w.sessPool.Get()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	defer w.sessPool.Put(sctx)
+	rh := newReorgHandler(sess.NewSession(sctx))
+	tbl, err := getTable((*asAutoIDRequirement)(d), job.SchemaID, tblInfo)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
+	reorgInfo, err := getReorgInfo(d.jobContext(job.ID, job.ReorgMeta), d, rh, job, dbInfo, tbl, elements, false)
+	if err != nil || reorgInfo == nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return nil, false, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(reorgInfo, tblInfo, d.lease, func() (reorgErr error) {
+		defer util.Recover(metrics.LabelDDL, "onTrainVectorIndex",
+			func() {
+				reorgErr = dbterror.ErrCancelledDDLJob.GenWithStack("train vector index `%v` panic", indexInfo.Name)
+			}, false)
+		return f(tbl, indexInfo, reorgInfo)
+	})
+	if err != nil {
+		if dbterror.ErrPausedDDLJob.Equal(err) || dbterror.ErrWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return nil, false, nil
+		}
+		return nil, false, errors.Trace(err)
+	}
+	return reorgInfo, true, nil
+}
+
+// trainVectorIndex trains the centroids of a vector index with the rows sampled from the snapshot of the
+// reorganization. The centroids are kept in the reorgCtx, and are left empty if there are fewer than
+// vectorindex.MinTrainingRows rows.
+func (w *worker) trainVectorIndex(t table.Table, indexInfo *model.IndexInfo, reorgInfo *reorgInfo) error {
+	job := reorgInfo.Job
+	tblInfo := t.Meta()
+	col := tblInfo.Columns[indexInfo.Columns[0].Offset]
+	cols := map[int64]*types.FieldType{col.ID: &col.FieldType}
+
+	physicalIDs := []int64{tblInfo.ID}
+	if tblInfo.GetPartitionInfo() != nil {
+		physicalIDs = getPartitionIDs(tblInfo)
+	}
+	// Sample the first rows of each partition, so the samples are not all from the first partition.
+	rowsPerTable := max(vectorindex.MaxTrainingRows/len(physicalIDs), 1)
+	samples := make([]types.VectorFloat32, 0, vectorindex.MaxTrainingRows)
+	jobCtx := reorgInfo.NewJobContext()
+	for _, pid := range physicalIDs {
+		if err := reorgInfo.d.isReorgRunnable(job.ID, false); err != nil {
+			return errors.Trace(err)
+		}
+		sampled := 0
+		err := iterateSnapshotKeys(jobCtx, reorgInfo.d.store, job.Priority, tablecodec.GenTableRecordPrefix(pid),
+			reorgInfo.SnapshotVer, nil, nil, func(_ kv.Handle, _ kv.Key, rawRecord []byte) (bool, error) {
+				row, err := tablecodec.DecodeRowToDatumMap(rawRecord, cols, time.UTC)
+				if err != nil {
+					return false, errors.Trace(err)
+				}
+				if datum, ok := row[col.ID]; ok && !datum.IsNull() {
+					v, err := types.DecodeVectorFloat32(datum.GetBytes())
+					if err != nil {
+						return false, errors.Trace(err)
+					}
+					samples = append(samples, v)
+					sampled++
+				}
+				return sampled < rowsPerTable, nil
+			})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(samples) < vectorindex.MinTrainingRows {
+		logutil.BgLogger().Info("too few rows to train vector index", zap.String("category", "ddl"),
+			zap.Int64("jobID", job.ID), zap.String("index", indexInfo.Name.O), zap.Int("samples", len(samples)))
+		return nil
+	}
+	centroids := vectorindex.Train(indexInfo.VectorInfo.DistanceMetric, indexInfo.VectorInfo.Dimension, samples)
+	logutil.BgLogger().Info("train vector index", zap.String("category", "ddl"), zap.Int64("jobID", job.ID),
+		zap.String("index", indexInfo.Name.O), zap.Int("samples", len(samples)), zap.Int("lists", len(centroids)))
+	w.getReorgCtx(job.ID).vectorCentroids = centroids
+	return nil
+}
+
+// reassignVectorIndex moves the rows in the untrained list of the vector index to the lists of their centroids,
+// partition by partition.
+func (w *worker) reassignVectorIndex(t table.Table, _ *model.IndexInfo, reorgInfo *reorgInfo) error {
+	tbl, ok := t.(table.PartitionedTable)
+	if !ok {
+		//nolint:forcetypeassert
+		return w.writePhysicalTableRecord(w.sessPool, t.(table.PhysicalTable), typeReassignVectorIndexWorker, reorgInfo)
+	}
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return dbterror.ErrCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
+		}
+		err := w.writePhysicalTableRecord(w.sessPool, p, typeReassignVectorIndexWorker, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		finish, err = updateReorgInfo(w.sessPool, tbl, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+type reassignVectorIndexWorker struct {
+	baseIndexWorker
+}
+
+func newReassignVectorIndexWorker(sessCtx sessionctx.Context, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo, jc *JobContext) *reassignVectorIndexWorker {
+	indexes := make([]table.Index, 0, 1)
+	for _, index := range t.Indices() {
+		if index.Meta().ID == reorgInfo.currElement.ID {
+			indexes = append(indexes, index)
+		}
+	}
+	return &reassignVectorIndexWorker{
+		baseIndexWorker: baseIndexWorker{
+			backfillCtx: newBackfillCtx(reorgInfo.d, id, sessCtx, reorgInfo.SchemaName, t, jc, "reassign_vector_idx_rate", false),
+			indexes:     indexes,
+			tp:          typeReassignVectorIndexWorker,
+			rowDecoder:  decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+			defaultVals: make([]types.Datum, len(t.WritableCols())),
+			rowMap:      make(map[int64]types.Datum, len(decodeColMap)),
+		},
+	}
+}
+
+// BackfillData moves the entries of the rows in the untrained list to the lists of their centroids. The row key is
+// locked like addIndexTxnWorker does, so a concurrent update or delete of the row retries the batch.
+func (w *reassignVectorIndexWorker) BackfillData(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	ctx := kv.WithInternalSourceAndTaskType(context.Background(), w.jobContext.ddlJobSourceType(), kvutil.ExplicitTypeDDL)
+	errInTxn = kv.RunInNewTxn(ctx, w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		updateTxnEntrySizeLimitIfNeeded(txn)
+		txn.SetOption(kv.Priority, handleRange.priority)
+		if tagger := w.GetCtx().getResourceGroupTaggerForTopSQL(handleRange.getJobID()); tagger != nil {
+			txn.SetOption(kv.ResourceGroupTagger, tagger)
+		}
+		txn.SetOption(kv.ResourceGroupName, w.jobContext.resourceGroupName)
+
+		idxRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+		if len(w.indexes) == 0 || len(idxRecords) == 0 {
+			return nil
+		}
+
+		idx := w.indexes[0]
+		sc := w.sessCtx.GetSessionVars().StmtCtx
+		untrained := []types.Datum{vectorindex.ListDatum(vectorindex.UntrainedList)}
+		keys := make([]kv.Key, 0, len(idxRecords))
+		for _, idxRecord := range idxRecords {
+			key, _, err := idx.GenIndexKey(sc.ErrCtx(), sc.TimeZone(), untrained, idxRecord.handle, nil)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			keys = append(keys, key)
+		}
+		values, err := txn.BatchGet(ctx, keys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		for i, idxRecord := range idxRecords {
+			taskCtx.scanCount++
+			if _, ok := values[string(keys[i])]; !ok {
+				continue
+			}
+			err := txn.LockKeys(context.Background(), new(kv.LockCtx), idxRecord.key)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err = txn.Delete(keys[i]); err != nil {
+				return errors.Trace(err)
+			}
+			_, err = idx.Create(w.sessCtx.GetTableCtx(), txn, idxRecord.vals, idxRecord.handle, idxRecord.rsData,
+				table.WithIgnoreAssertion, table.FromBackfill)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "reassignVectorIndexBackfillData", 3000)
+
+	return
+}
@@ -0,0 +1,202 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/testkit/external"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
+	"github.com/stretchr/testify/require"
+)
+
+// untrainedListRows returns the number of the rows in the untrained list of the vector index.
+func untrainedListRows(t *testing.T, store kv.Storage, tblInfo *model.TableInfo, idxInfo *model.IndexInfo) int {
+	prefix, err := codec.EncodeKey(time.UTC, tablecodec.EncodeTableIndexPrefix(tblInfo.ID, idxInfo.ID),
+		vectorindex.ListDatum(vectorindex.UntrainedList))
+	require.NoError(t, err)
+	rows := 0
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnOthers)
+	err = kv.RunInNewTxn(ctx, store, false, func(_ context.Context, txn kv.Transaction) error {
+		iter, err := txn.Iter(prefix, kv.Key(prefix).PrefixNext())
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		for ; iter.Valid(); err = iter.Next() {
+			if err != nil {
+				return err
+			}
+			rows++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return rows
+}
+
+func TestVectorType(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (id int primary key, v vector(3), w vector)")
+	tk.MustQuery("show create table t").Check(testkit.RowsWithSep("|", "t|CREATE TABLE `t` (\n"+
+		"  `id` int(11) NOT NULL,\n"+
+		"  `v` vector(3) DEFAULT NULL,\n"+
+		"  `w` vector DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into t values (1, '[1,2,3]', '[1]'), (2, ' [ 4, 5.5, -6 ] ', '[1,2,3,4]'), (3, null, null)")
+	tk.MustQuery("select id, v, w from t order by id").Check(testkit.Rows("1 [1,2,3] [1]", "2 [4,5.5,-6] [1,2,3,4]", "3 <nil> <nil>"))
+	tk.MustQuery("select id from t where v = vec_from_text('[1,2,3]')").Check(testkit.Rows("1"))
+
+	tk.MustQuery("select vec_dims(v), vec_l2_norm(v), vec_as_text(v) from t where id = 1").Check(testkit.Rows("3 3.7416573867739413 [1,2,3]"))
+	tk.MustQuery("select vec_l2_distance(v, '[1,2,4]'), vec_inner_product(v, '[1,1,1]'), vec_cosine_distance(v, '[2,4,6]') from t where id = 1").
+		Check(testkit.Rows("1 6 0"))
+	tk.MustQuery("select vec_cosine_distance(v, '[0,0,0]') from t where id = 1").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select vec_as_text(vec_from_text('[1e0, 2]'))").Check(testkit.Rows("[1,2]"))
+
+	tk.MustGetErrCode("insert into t values (4, '[1,2]', null)", errno.ErrVectorDimensionMismatch)
+	tk.MustGetErrCode("insert into t values (4, '[1,2,a]', null)", errno.ErrInvalidVectorValue)
+	require.Error(t, tk.QueryToErr("select vec_l2_distance(v, '[1,2]') from t"))
+	tk.MustGetErrCode("create table t1 (v vector(16384))", errno.ErrTooBigFieldlength)
+	tk.MustGetErrCode("alter table t add index (v)", errno.ErrUnsupportedDDLOperation)
+}
+
+func TestVectorIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (id int primary key, v vector(2), vector index idx_v ((vec_cosine_distance(v))))")
+	tk.MustQuery("show create table t").Check(testkit.RowsWithSep("|", "t|CREATE TABLE `t` (\n"+
+		"  `id` int(11) NOT NULL,\n"+
+		"  `v` vector(2) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  VECTOR INDEX `idx_v` ((VEC_COSINE_DISTANCE(`v`)))\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	// All the rows are in one list because the index is created on an empty table.
+	tk.MustExec("insert into t values (1, '[1,0]'), (2, '[0,1]'), (3, '[1,1]'), (4, null)")
+	tk.MustQuery("select id from t order by vec_cosine_distance(v, '[1,0.1]') limit 2").Check(testkit.Rows("4", "1"))
+	tk.MustHavePlan("select id from t order by vec_cosine_distance(v, '[1,0.1]') limit 2", "TableFullScan")
+	tk.MustExec("admin check table t")
+
+	// The index is trained after it's added, and the rows are moved out of the untrained list.
+	tk.MustExec("create table t2 (id int primary key, v vector(3))")
+	values := make([]string, 0, 400)
+	for i := 0; i < 400; i++ {
+		values = append(values, fmt.Sprintf("(%d, '[%d,%d,%d]')", i, i%7, i%11, i%13))
+	}
+	tk.MustExec("insert into t2 values " + strings.Join(values, ","))
+	tk.MustExec("alter table t2 add vector index idx_v ((vec_l2_distance(v)))")
+	tblInfo := external.GetTableByName(t, tk, "test", "t2").Meta()
+	idx := tblInfo.FindIndexByName("idx_v")
+	require.NotNil(t, idx.VectorInfo)
+	require.Equal(t, model.DistanceMetricL2, idx.VectorInfo.DistanceMetric)
+	require.Equal(t, 3, idx.VectorInfo.Dimension)
+	require.Len(t, idx.VectorInfo.Centroids, 20)
+	require.False(t, idx.VectorInfo.PendingCentroids)
+	require.Zero(t, untrainedListRows(t, store, tblInfo, idx))
+
+	query := "select id from t2 order by vec_l2_distance(v, '[3,5,7]'), id limit 5"
+	// The lists are read by the index only if they are cheaper than the full table.
+	tk.MustExec("set @@tidb_vector_search_nprobe = 1")
+	tk.MustHavePlan(query, "IndexLookUp")
+	// A row is in the list nearest to itself.
+	tk.MustQuery("select id from t2 order by vec_l2_distance(v, '[3,4,7]') limit 1").Check(testkit.Rows("59"))
+	tk.MustExec("set @@tidb_vector_search_nprobe = 0")
+	tk.MustHavePlan(query, "TableFullScan")
+	exact := tk.MustQuery(query).Rows()
+	// The result is exact if all the lists are read.
+	tk.MustExec("set @@tidb_vector_search_nprobe = 1024")
+	tk.MustQuery(query).Check(exact)
+	// The other paths are kept, so a cheaper path is chosen by the cost.
+	tk.MustHavePlan("select id from t2 where id < 10 order by vec_l2_distance(v, '[3,5,7]') limit 5", "TableRangeScan")
+	tk.MustExec("set @@tidb_vector_search_nprobe = default")
+	// The index is not used by the other distance functions.
+	tk.MustHavePlan("select id from t2 order by vec_cosine_distance(v, '[3,5,7]') limit 1", "TableFullScan")
+
+	// The index is maintained by the writes.
+	tk.MustExec("update t2 set v = '[100,100,100]' where id = 3")
+	tk.MustExec("delete from t2 where id = 1")
+	tk.MustExec("insert into t2 values (1000, '[3,5,7]')")
+	tk.MustQuery("select id from t2 order by vec_l2_distance(v, '[3,5,7]') limit 1").Check(testkit.Rows("1000"))
+	tk.MustQuery("select id from t2 order by vec_l2_distance(v, '[100,100,100]') limit 1").Check(testkit.Rows("3"))
+	tk.MustExec("admin check table t2")
+	tk.MustGetErrMsg("admin check index t2 idx_v", "checking VECTOR index idx_v is not supported")
+
+	tk.MustGetErrCode("alter table t2 add vector index ((vec_inner_product(v)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t2 add vector index ((vec_l2_distance(v, v)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t2 add vector index (v)", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t2 add vector index ((vec_l2_distance(id)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("create table t3 (v vector, vector index ((vec_l2_distance(v))))", errno.ErrUnsupportedDDLOperation)
+}
+
+func TestVectorIndexTrainedByAnalyze(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	values := make([]string, 0, 4000)
+	for i := 0; i < 4000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, '[%d,%d,%d]')", i, i%10, i%7, i%11, i%13))
+	}
+	// The index isn't trained until there are enough rows.
+	tk.MustExec("create table t (id int primary key, c int, v vector(3), vector index idx_v ((vec_l2_distance(v))))")
+	tk.MustExec("insert into t values " + strings.Join(values[:10], ","))
+	tk.MustExec("analyze table t")
+	tblInfo := external.GetTableByName(t, tk, "test", "t").Meta()
+	idx := tblInfo.FindIndexByName("idx_v")
+	require.Empty(t, idx.VectorInfo.Centroids)
+	tk.MustExec("insert into t values " + strings.Join(values[10:], ","))
+	require.Equal(t, 4000, untrainedListRows(t, store, tblInfo, idx))
+	tk.MustExec("analyze table t")
+	idx = external.GetTableByName(t, tk, "test", "t").Meta().FindIndexByName("idx_v")
+	require.NotEmpty(t, idx.VectorInfo.Centroids)
+	require.False(t, idx.VectorInfo.PendingCentroids)
+	// The rows written before the training are moved to the lists of their centroids.
+	require.Zero(t, untrainedListRows(t, store, tblInfo, idx))
+	tk.MustExec("set @@tidb_vector_search_nprobe = 1")
+	query := "select id from t order by vec_l2_distance(v, '[3,4,7]'), id limit 1"
+	tk.MustHavePlan(query, "IndexLookUp")
+	tk.MustQuery(query).Check(testkit.Rows("59"))
+	tk.MustExec("update t set v = '[100,100,100]' where id = 3")
+	tk.MustExec("delete from t where id = 59")
+	tk.MustQuery(query).Check(testkit.Rows("1060"))
+	tk.MustExec("insert into t values (4000, 0, '[50,50,50]')")
+	tk.MustQuery("select id from t order by vec_l2_distance(v, '[50,50,50]') limit 1").Check(testkit.Rows("4000"))
+	tk.MustQuery("select id from t order by vec_l2_distance(v, '[100,100,100]') limit 1").Check(testkit.Rows("3"))
+	tk.MustExec("admin check table t")
+
+	// More lists are read if the rows are filtered, so the TopN still gets enough rows.
+	tk.MustExec("create table t2 (id int primary key, c int, v vector(3))")
+	tk.MustExec("insert into t2 values " + strings.Join(values, ","))
+	tk.MustExec("alter table t2 add vector index idx_v ((vec_l2_distance(v)))")
+	tk.MustExec("analyze table t2")
+	query = "select id from t2 where c < 9 order by vec_l2_distance(v, '[3,5,7]') limit 60"
+	tk.MustHavePlan(query, "IndexLookUp")
+	require.Len(t, tk.MustQuery(query).Rows(), 60)
+}
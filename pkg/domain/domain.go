@@ -47,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/pkg/domain/infosync"
 	"github.com/pingcap/tidb/pkg/domain/resourcegroup"
 	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/eventscheduler"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	infoschema_metrics "github.com/pingcap/tidb/pkg/infoschema/metrics"
 	"github.com/pingcap/tidb/pkg/infoschema/perfschema"
@@ -61,6 +62,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/privilege"
 	"github.com/pingcap/tidb/pkg/privilege/privileges"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/sessionstates"
@@ -171,10 +173,12 @@ type Domain struct {
 		sync.RWMutex
 		expiredTimeStamp types.Time
 	}
+	instancePlanCache sessionctx.InstancePlanCache
 
 	logBackupAdvancer        *daemon.OwnerDaemon
 	historicalStatsWorker    *HistoricalStatsWorker
 	ttlJobManager            atomic.Pointer[ttlworker.JobManager]
+	eventScheduler           atomic.Pointer[eventscheduler.Scheduler]
 	runawayManager           *resourcegroup.RunawayManager
 	runawaySyncer            *runawaySyncer
 	resourceGroupsController *rmclient.ResourceGroupsController
@@ -535,6 +539,16 @@ func (do *Domain) SetExpiredTimeStamp4PC(time types.Time) {
 	do.expiredTimeStamp4PC.expiredTimeStamp = time
 }
 
+// InstancePlanCache gets the plan cache shared by all sessions of this instance.
+func (do *Domain) InstancePlanCache() sessionctx.InstancePlanCache {
+	return do.instancePlanCache
+}
+
+// SetInstancePlanCache sets the plan cache shared by all sessions of this instance.
+func (do *Domain) SetInstancePlanCache(cache sessionctx.InstancePlanCache) {
+	do.instancePlanCache = cache
+}
+
 // DDL gets DDL from domain.
 func (do *Domain) DDL() ddl.DDL {
 	return do.ddl
@@ -1036,6 +1050,10 @@ func (do *Domain) Close() {
 			logutil.BgLogger().Info("ttlJobManager exited.")
 		}
 	}
+	if eventScheduler := do.eventScheduler.Load(); eventScheduler != nil {
+		logutil.BgLogger().Info("stopping eventScheduler")
+		eventScheduler.Stop()
+	}
 	do.releaseServerID(context.Background())
 	close(do.exit)
 	if do.etcdClient != nil {
@@ -1880,6 +1898,7 @@ func (do *Domain) LoadBindInfoLoop(ctxForHandle sessionctx.Context, ctxForEvolve
 
 	owner := do.newOwnerManager(bindinfo.Prompt, bindinfo.OwnerKey)
 	do.globalBindHandleWorkerLoop(owner)
+	do.handleEvolvePlanTasksLoop(owner)
 	return nil
 }
 
@@ -1913,6 +1932,10 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 				if err == nil && variable.TiDBOptOn(optVal) {
 					bindHandle.CaptureBaselines()
 				}
+				optVal, err = do.GetGlobalVar(variable.TiDBEnablePlanRegressionGuard)
+				if err == nil && variable.TiDBOptOn(optVal) {
+					bindHandle.RecordPlanBaselines()
+				}
 			case <-gcBindTicker.C:
 				if !owner.IsOwner() {
 					continue
@@ -1926,6 +1949,37 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 	}, "globalBindHandleWorkerLoop")
 }
 
+// handleEvolvePlanTasksLoop verifies the candidate plans of the plan baselines in the background.
+// The verification executes the queries, so it's done by the owner only and in a separate goroutine.
+func (do *Domain) handleEvolvePlanTasksLoop(owner owner.Manager) {
+	do.wg.Run(func() {
+		defer func() {
+			logutil.BgLogger().Info("handleEvolvePlanTasksLoop exited.")
+		}()
+		defer util.Recover(metrics.LabelDomain, "handleEvolvePlanTasksLoop", nil, false)
+
+		evolveTicker := time.NewTicker(bindinfo.Lease)
+		defer evolveTicker.Stop()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-evolveTicker.C:
+				if !owner.IsOwner() {
+					continue
+				}
+				optVal, err := do.GetGlobalVar(variable.TiDBEvolvePlanBaselines)
+				if err != nil || !variable.TiDBOptOn(optVal) {
+					continue
+				}
+				if err := do.BindHandle().EvolvePlanBaselines(false); err != nil {
+					logutil.BgLogger().Warn("evolve plan baselines failed", zap.Error(err))
+				}
+			}
+		}
+	}, "handleEvolvePlanTasksLoop")
+}
+
 // SetupPlanReplayerHandle setup plan replayer handle
 func (do *Domain) SetupPlanReplayerHandle(collectorSctx sessionctx.Context, workersSctxs []sessionctx.Context) {
 	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
@@ -2405,6 +2459,10 @@ func (do *Domain) updateStatsWorker(_ sessionctx.Context, owner owner.Manager) {
 			if err != nil {
 				logutil.BgLogger().Debug("dump stats delta failed", zap.Error(err))
 			}
+			err = statsHandle.DumpCardinalityFeedbackToKV()
+			if err != nil {
+				logutil.BgLogger().Debug("dump cardinality feedback failed", zap.Error(err))
+			}
 		case <-gcStatsTicker.C:
 			if !owner.IsOwner() {
 				continue
@@ -2950,6 +3008,29 @@ func (do *Domain) TTLJobManager() *ttlworker.JobManager {
 	return do.ttlJobManager.Load()
 }
 
+// StartEventScheduler creates and starts the event scheduler.
+func (do *Domain) StartEventScheduler() {
+	sessFactory := func() (pools.Resource, error) {
+		se, err := do.sysFacHack()
+		if err != nil {
+			return nil, err
+		}
+		// The events are executed with the privileges of their definers.
+		if sctx, ok := se.(sessionctx.Context); ok {
+			privilege.BindPrivilegeManager(sctx, &privileges.UserPrivileges{Handle: do.PrivilegeHandle()})
+		}
+		return se, nil
+	}
+	scheduler := eventscheduler.NewScheduler(do.sysSessionPool, do.etcdClient, sessFactory, do.ddl.OwnerManager().IsOwner)
+	do.eventScheduler.Store(scheduler)
+	scheduler.Start()
+}
+
+// EventScheduler returns the event scheduler on this domain.
+func (do *Domain) EventScheduler() *eventscheduler.Scheduler {
+	return do.eventScheduler.Load()
+}
+
 // StopAutoAnalyze stops (*Domain).autoAnalyzeWorker to launch new auto analyze jobs.
 func (do *Domain) StopAutoAnalyze() {
 	do.stopAutoAnalyze.Store(true)
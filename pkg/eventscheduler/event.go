@@ -0,0 +1,202 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	timerapi "github.com/pingcap/tidb/pkg/timer/api"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+)
+
+const (
+	timerKeyPrefix = "/tidb/event/"
+	timerHookClass = "tidb.event"
+	// oneTimeEventInterval is the schedule interval of the timer of a one-time event. The timer is disabled or
+	// deleted after the event is executed, the interval only makes sure the event isn't executed again before that.
+	oneTimeEventInterval = 24 * time.Hour
+	// maxEventInterval is the max interval of a recurring event.
+	maxEventInterval = 10 * 365 * 24 * time.Hour
+)
+
+// EventInfo is the definition of an event. It's stored as the data of the event's timer, so the timer is the
+// only place to persist an event.
+type EventInfo struct {
+	Schema  string             `json:"schema"`
+	Name    string             `json:"name"`
+	Definer *auth.UserIdentity `json:"definer"`
+	// Body is the statement executed by the event.
+	Body string `json:"body"`
+	// ExecuteAt is the execution time of a one-time event. It's zero for a recurring event.
+	ExecuteAt time.Time `json:"execute_at"`
+	// IntervalValue and IntervalField are the EVERY clause of a recurring event.
+	IntervalValue int64            `json:"interval_value"`
+	IntervalField ast.TimeUnitType `json:"interval_field"`
+	Starts        time.Time        `json:"starts"`
+	Ends          time.Time        `json:"ends"`
+	// Preserve indicates whether the event is kept after it's completed.
+	Preserve bool   `json:"preserve"`
+	Comment  string `json:"comment"`
+	// The following fields are the environment when the event is created, the body is executed in the same
+	// environment.
+	SQLMode     string    `json:"sql_mode"`
+	TimeZone    string    `json:"time_zone"`
+	Charset     string    `json:"charset"`
+	Collation   string    `json:"collation"`
+	DBCollation string    `json:"db_collation"`
+	Created     time.Time `json:"created"`
+	LastAltered time.Time `json:"last_altered"`
+}
+
+// Event is an event with its state.
+type Event struct {
+	*EventInfo
+	// Enabled indicates whether the event is enabled.
+	Enabled bool
+	// LastExecuted is the time the event was last executed, it's zero if the event has never been executed.
+	LastExecuted time.Time
+
+	timerID string
+}
+
+// eventSummary is the summary data of an event's timer.
+type eventSummary struct {
+	LastExecuted time.Time `json:"last_executed"`
+	// LastEventID is the ID of the timer event in which the event is executed last time.
+	LastEventID string `json:"last_event_id,omitempty"`
+}
+
+// IsOneTime returns whether the event is a one-time event.
+func (e *EventInfo) IsOneTime() bool {
+	return !e.ExecuteAt.IsZero()
+}
+
+// Interval returns the interval of a recurring event.
+func (e *EventInfo) Interval() (time.Duration, error) {
+	var unit time.Duration
+	switch e.IntervalField {
+	case ast.TimeUnitSecond:
+		unit = time.Second
+	case ast.TimeUnitMinute:
+		unit = time.Minute
+	case ast.TimeUnitHour:
+		unit = time.Hour
+	case ast.TimeUnitDay:
+		unit = 24 * time.Hour
+	case ast.TimeUnitWeek:
+		unit = 7 * 24 * time.Hour
+	default:
+		// The length of a month or a year varies, which can't be expressed by the interval of a timer.
+		return 0, dbterror.ErrNotSupportedYet.GenWithStackByArgs("EVERY ... " + e.IntervalField.String())
+	}
+	if e.IntervalValue <= 0 || e.IntervalValue > int64(maxEventInterval/unit) {
+		return 0, dbterror.ErrEventIntervalNotPositiveOrTooBig
+	}
+	return time.Duration(e.IntervalValue) * unit, nil
+}
+
+// Validate checks the schedule of the event.
+func (e *EventInfo) Validate() error {
+	if e.IsOneTime() {
+		return nil
+	}
+	if _, err := e.Interval(); err != nil {
+		return err
+	}
+	if !e.Ends.IsZero() && e.Ends.Before(e.Starts) {
+		return dbterror.ErrEventEndsBeforeStarts
+	}
+	return nil
+}
+
+// NextExecution returns the time the event will be executed next after the specified time. The second return value
+// is false if the event won't be executed any more.
+func (e *EventInfo) NextExecution(after time.Time) (time.Time, bool) {
+	if e.IsOneTime() {
+		return e.ExecuteAt, e.ExecuteAt.After(after)
+	}
+	interval, err := e.Interval()
+	if err != nil {
+		return time.Time{}, false
+	}
+	next := e.Starts
+	if after.Compare(next) >= 0 {
+		next = e.alignedTime(after).Add(interval)
+	}
+	return next, e.Ends.IsZero() || !next.After(e.Ends)
+}
+
+// alignedTime returns the latest time not after t when the event is scheduled, the times are aligned to STARTS.
+func (e *EventInfo) alignedTime(t time.Time) time.Time {
+	if e.IsOneTime() {
+		return e.ExecuteAt
+	}
+	interval, err := e.Interval()
+	if err != nil || t.Before(e.Starts) {
+		return e.Starts
+	}
+	return e.Starts.Add(t.Sub(e.Starts) / interval * interval)
+}
+
+// schedPolicy returns the schedule policy and the initial watermark of the event's timer.
+func (e *EventInfo) schedPolicy() (expr string, watermark time.Time, err error) {
+	if e.IsOneTime() {
+		return formatInterval(oneTimeEventInterval), e.ExecuteAt.Add(-oneTimeEventInterval), nil
+	}
+	interval, err := e.Interval()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return formatInterval(interval), e.Starts.Add(-interval), nil
+}
+
+func formatInterval(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64(d/time.Second))
+}
+
+// EventTimerKey returns the key of the timer of an event.
+func EventTimerKey(schema, name string) string {
+	return timerKeyPrefix + strings.ToLower(schema) + "/" + strings.ToLower(name)
+}
+
+func eventTimerKeyPrefix(schema string) string {
+	return timerKeyPrefix + strings.ToLower(schema) + "/"
+}
+
+func eventFromTimer(timer *timerapi.TimerRecord) (*Event, error) {
+	info := &EventInfo{}
+	if err := json.Unmarshal(timer.Data, info); err != nil {
+		return nil, errors.Trace(err)
+	}
+	event := &Event{
+		EventInfo: info,
+		Enabled:   timer.Enable,
+		timerID:   timer.ID,
+	}
+	if len(timer.SummaryData) > 0 {
+		var summary eventSummary
+		if err := json.Unmarshal(timer.SummaryData, &summary); err != nil {
+			return nil, errors.Trace(err)
+		}
+		event.LastExecuted = summary.LastExecuted
+	}
+	return event, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventscheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventInterval(t *testing.T) {
+	e := &EventInfo{IntervalValue: 90, IntervalField: ast.TimeUnitMinute}
+	interval, err := e.Interval()
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Minute, interval)
+
+	e = &EventInfo{IntervalValue: 2, IntervalField: ast.TimeUnitWeek}
+	interval, err = e.Interval()
+	require.NoError(t, err)
+	require.Equal(t, 14*24*time.Hour, interval)
+
+	for _, value := range []int64{0, -1, 10*365*24 + 1} {
+		e = &EventInfo{IntervalValue: value, IntervalField: ast.TimeUnitHour}
+		_, err = e.Interval()
+		require.True(t, dbterror.ErrEventIntervalNotPositiveOrTooBig.Equal(err), value)
+	}
+
+	e = &EventInfo{IntervalValue: 1, IntervalField: ast.TimeUnitMonth}
+	_, err = e.Interval()
+	require.True(t, dbterror.ErrNotSupportedYet.Equal(err))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	e = &EventInfo{IntervalValue: 1, IntervalField: ast.TimeUnitDay, Starts: start, Ends: start.Add(-time.Second)}
+	require.True(t, dbterror.ErrEventEndsBeforeStarts.Equal(e.Validate()))
+}
+
+func TestEventNextExecution(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := &EventInfo{IntervalValue: 1, IntervalField: ast.TimeUnitHour, Starts: start}
+
+	next, ok := e.NextExecution(start.Add(-time.Minute))
+	require.True(t, ok)
+	require.Equal(t, start, next)
+	next, ok = e.NextExecution(start)
+	require.True(t, ok)
+	require.Equal(t, start.Add(time.Hour), next)
+	next, ok = e.NextExecution(start.Add(150 * time.Minute))
+	require.True(t, ok)
+	require.Equal(t, start.Add(3*time.Hour), next)
+	require.Equal(t, start.Add(2*time.Hour), e.alignedTime(start.Add(150*time.Minute)))
+	require.Equal(t, start, e.alignedTime(start.Add(-time.Hour)))
+
+	e.Ends = start.Add(2 * time.Hour)
+	_, ok = e.NextExecution(start.Add(time.Hour))
+	require.True(t, ok)
+	_, ok = e.NextExecution(start.Add(2 * time.Hour))
+	require.False(t, ok)
+
+	expr, watermark, err := e.schedPolicy()
+	require.NoError(t, err)
+	require.Equal(t, "3600s", expr)
+	require.Equal(t, start.Add(-time.Hour), watermark)
+
+	e = &EventInfo{ExecuteAt: start}
+	require.True(t, e.IsOneTime())
+	next, ok = e.NextExecution(start.Add(-time.Second))
+	require.True(t, ok)
+	require.Equal(t, start, next)
+	_, ok = e.NextExecution(start)
+	require.False(t, ok)
+	require.Equal(t, start, e.alignedTime(start.Add(time.Hour)))
+
+	expr, watermark, err = e.schedPolicy()
+	require.NoError(t, err)
+	require.Equal(t, "86400s", expr)
+	require.Equal(t, start.Add(-24*time.Hour), watermark)
+}
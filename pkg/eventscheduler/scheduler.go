@@ -0,0 +1,392 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	timerapi "github.com/pingcap/tidb/pkg/timer/api"
+	timerrt "github.com/pingcap/tidb/pkg/timer/runtime"
+	"github.com/pingcap/tidb/pkg/timer/tablestore"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	checkRuntimeInterval = time.Second
+	timerDBName          = "mysql"
+	timerTableName       = "tidb_timers"
+)
+
+// Scheduler manages the events and executes them. Every event is a timer in mysql.tidb_timers, the timer runtime
+// only runs on the DDL owner, and the event is closed in the same transaction as its statement, so the statement is
+// executed exactly once for each schedule across the cluster. A statement that commits implicitly, e.g. a DDL
+// statement, may be executed again if the instance crashes before the event is closed.
+type Scheduler struct {
+	store *timerapi.TimerStore
+	cli   timerapi.TimerClient
+	// sessFactory creates the sessions to execute the events, the sessions are closed after the execution.
+	sessFactory func() (pools.Resource, error)
+	isOwner     func() bool
+
+	rt     *timerrt.TimerGroupRuntime
+	ctx    context.Context
+	cancel func()
+	wg     util.WaitGroupWrapper
+}
+
+// NewScheduler creates a new Scheduler.
+func NewScheduler(pool sessionPool, etcd *clientv3.Client, sessFactory func() (pools.Resource, error), isOwner func() bool) *Scheduler {
+	store := tablestore.NewTableTimerStore(1, pool, timerDBName, timerTableName, etcd)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		store:       store,
+		cli:         timerapi.NewDefaultTimerClient(store),
+		sessFactory: sessFactory,
+		isOwner:     isOwner,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start starts the scheduler. The events are executed only if this instance is the DDL owner and the event scheduler
+// is enabled.
+func (s *Scheduler) Start() {
+	s.wg.Run(s.loop)
+}
+
+// Stop stops the scheduler.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.store.Close()
+}
+
+func (s *Scheduler) loop() {
+	ticker := time.NewTicker(checkRuntimeInterval)
+	defer func() {
+		ticker.Stop()
+		s.pauseRuntime()
+	}()
+	for {
+		if s.isOwner() && variable.EnableEventScheduler.Load() {
+			s.resumeRuntime()
+		} else {
+			s.pauseRuntime()
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) resumeRuntime() {
+	if s.rt != nil {
+		return
+	}
+	s.rt = timerrt.NewTimerRuntimeBuilder("event", s.store).
+		SetCond(&timerapi.TimerCond{Key: timerapi.NewOptionalVal(timerKeyPrefix), KeyPrefix: true}).
+		RegisterHookFactory(timerHookClass, func(_ string, cli timerapi.TimerClient) timerapi.Hook {
+			return newEventHook(cli, s.sessFactory)
+		}).
+		Build()
+	s.rt.Start()
+}
+
+func (s *Scheduler) pauseRuntime() {
+	if rt := s.rt; rt != nil {
+		s.rt = nil
+		rt.Stop()
+	}
+}
+
+// CreateEvent creates an event.
+func (s *Scheduler) CreateEvent(ctx context.Context, info *EventInfo, enable bool) error {
+	spec, err := buildTimerSpec(info, enable)
+	if err != nil {
+		return err
+	}
+	if _, err = s.cli.CreateTimer(ctx, *spec); err != nil {
+		if errors.ErrorEqual(err, timerapi.ErrTimerExists) {
+			return dbterror.ErrEventAlreadyExists.GenWithStackByArgs(info.Name)
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// AlterEvent replaces the definition of an event. The schedule is reset if resetSchedule is true.
+func (s *Scheduler) AlterEvent(ctx context.Context, event *Event, info *EventInfo, enable bool, resetSchedule bool) error {
+	if EventTimerKey(event.Schema, event.Name) != EventTimerKey(info.Schema, info.Name) {
+		// The key of a timer can't be changed, so a renamed event is moved to a new timer.
+		spec, err := buildTimerSpec(info, enable)
+		if err != nil {
+			return err
+		}
+		if !resetSchedule {
+			timer, err := s.cli.GetTimerByID(ctx, event.timerID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			spec.Watermark = timer.Watermark
+		}
+		if _, err = s.cli.CreateTimer(ctx, *spec); err != nil {
+			if errors.ErrorEqual(err, timerapi.ErrTimerExists) {
+				return dbterror.ErrEventAlreadyExists.GenWithStackByArgs(info.Name)
+			}
+			return errors.Trace(err)
+		}
+		_, err = s.cli.DeleteTimer(ctx, event.timerID)
+		return errors.Trace(err)
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	opts := []timerapi.UpdateTimerOption{timerapi.WithSetData(data), timerapi.WithSetEnable(enable)}
+	if resetSchedule {
+		expr, watermark, err := info.schedPolicy()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, timerapi.WithSetSchedExpr(timerapi.SchedEventInterval, expr), timerapi.WithSetWatermark(watermark))
+	}
+	err = s.cli.UpdateTimer(ctx, event.timerID, opts...)
+	if errors.ErrorEqual(err, timerapi.ErrTimerNotExist) {
+		return dbterror.ErrEventDoesNotExist.GenWithStackByArgs(info.Name)
+	}
+	return errors.Trace(err)
+}
+
+// DropEvent drops an event. It returns false if the event doesn't exist.
+func (s *Scheduler) DropEvent(ctx context.Context, schema, name string) (bool, error) {
+	timer, err := s.cli.GetTimerByKey(ctx, EventTimerKey(schema, name))
+	if err != nil {
+		if errors.ErrorEqual(err, timerapi.ErrTimerNotExist) {
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	return s.cli.DeleteTimer(ctx, timer.ID)
+}
+
+// DropSchemaEvents drops all the events of a schema, it's called when the schema is dropped.
+func (s *Scheduler) DropSchemaEvents(ctx context.Context, schema string) error {
+	timers, err := s.cli.GetTimers(ctx, timerapi.WithKeyPrefix(eventTimerKeyPrefix(schema)))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, timer := range timers {
+		// The prefix of a schema also matches the keys of a schema whose name starts with "schema/".
+		if event, err := eventFromTimer(timer); err == nil && !strings.EqualFold(event.Schema, schema) {
+			continue
+		}
+		if _, err = s.cli.DeleteTimer(ctx, timer.ID); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// GetEvent returns an event, or nil if the event doesn't exist.
+func (s *Scheduler) GetEvent(ctx context.Context, schema, name string) (*Event, error) {
+	timer, err := s.cli.GetTimerByKey(ctx, EventTimerKey(schema, name))
+	if err != nil {
+		if errors.ErrorEqual(err, timerapi.ErrTimerNotExist) {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return eventFromTimer(timer)
+}
+
+// ListEvents returns the events in a schema, or all the events if schema is empty.
+func (s *Scheduler) ListEvents(ctx context.Context, schema string) ([]*Event, error) {
+	prefix := timerKeyPrefix
+	if schema != "" {
+		prefix = eventTimerKeyPrefix(schema)
+	}
+	timers, err := s.cli.GetTimers(ctx, timerapi.WithKeyPrefix(prefix))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	events := make([]*Event, 0, len(timers))
+	for _, timer := range timers {
+		event, err := eventFromTimer(timer)
+		if err != nil {
+			logutil.BgLogger().Warn("invalid event timer", zap.String("key", timer.Key), zap.Error(err))
+			continue
+		}
+		if schema != "" && !strings.EqualFold(event.Schema, schema) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func buildTimerSpec(info *EventInfo, enable bool) (*timerapi.TimerSpec, error) {
+	expr, watermark, err := info.schedPolicy()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &timerapi.TimerSpec{
+		Key:             EventTimerKey(info.Schema, info.Name),
+		Data:            data,
+		SchedPolicyType: timerapi.SchedEventInterval,
+		SchedPolicyExpr: expr,
+		HookClass:       timerHookClass,
+		Watermark:       watermark,
+		Enable:          enable,
+	}, nil
+}
+
+// eventHook executes the events when their timers are triggered.
+type eventHook struct {
+	cli         timerapi.TimerClient
+	sessFactory func() (pools.Resource, error)
+	ctx         context.Context
+	cancel      func()
+	wg          sync.WaitGroup
+}
+
+func newEventHook(cli timerapi.TimerClient, sessFactory func() (pools.Resource, error)) *eventHook {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &eventHook{
+		cli:         cli,
+		sessFactory: sessFactory,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (*eventHook) Start() {}
+
+func (h *eventHook) Stop() {
+	h.cancel()
+	h.wg.Wait()
+}
+
+func (*eventHook) OnPreSchedEvent(context.Context, timerapi.TimerShedEvent) (r timerapi.PreSchedEventResult, err error) {
+	return
+}
+
+func (h *eventHook) OnSchedEvent(ctx context.Context, timerEvent timerapi.TimerShedEvent) error {
+	timer := timerEvent.Timer()
+	eventID := timerEvent.EventID()
+	logger := logutil.BgLogger().With(
+		zap.String("key", timer.Key),
+		zap.String("eventID", eventID),
+		zap.Time("eventStart", timer.EventStart),
+	)
+
+	event, err := eventFromTimer(timer)
+	if err != nil {
+		logger.Error("invalid event timer data", zap.ByteString("data", timer.Data), zap.Error(err))
+		return h.cli.CloseTimerEvent(ctx, timer.ID, eventID, timerapi.WithSetWatermark(timer.EventStart))
+	}
+
+	// A one-time event may be triggered again if it's not completed after the execution, it mustn't be executed
+	// again in this case. And a recurring event mustn't be executed after ENDS.
+	scheduled := event.alignedTime(timer.EventStart)
+	expired := !timer.Watermark.Before(scheduled) || (!event.Ends.IsZero() && scheduled.After(event.Ends))
+	_, hasNext := event.NextExecution(scheduled)
+	if expired {
+		if err = h.cli.CloseTimerEvent(ctx, timer.ID, eventID, timerapi.WithSetWatermark(scheduled)); err != nil {
+			return err
+		}
+		if !hasNext {
+			h.completeEvent(logger, timer.ID, event.EventInfo)
+		}
+		return nil
+	}
+
+	// The summary records the event ID of the execution, and the event is closed only if it's still the triggering
+	// event of the timer, so an event which is retried by another runtime with the same event ID is never committed
+	// twice.
+	summary, err := json.Marshal(&eventSummary{LastExecuted: timer.EventStart, LastEventID: eventID})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	closeOpts := []timerapi.UpdateTimerOption{timerapi.WithSetWatermark(scheduled), timerapi.WithSetSummaryData(summary)}
+	closeEvent := func(ctx context.Context, exec sqlexec.SQLExecutor) error {
+		failpoint.Inject("mockEventClosed", func() {
+			failpoint.Return(timerapi.ErrEventIDNotMatch)
+		})
+		update, err := timerapi.NewCloseEventUpdate(eventID, closeOpts...)
+		if err != nil {
+			return err
+		}
+		return tablestore.UpdateTimerInTxn(ctx, exec, timerDBName, timerTableName, timer.ID, update)
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		logger.Info("execute event")
+		if err := executeEvent(h.ctx, h.sessFactory, event.EventInfo, closeEvent); err != nil {
+			if h.ctx.Err() != nil {
+				// The runtime is stopped, e.g. the instance is not the DDL owner any more. The event is left
+				// open, so it's executed again with the same event ID by the runtime of the new owner.
+				logger.Info("event execution is interrupted", zap.Error(err))
+				return
+			}
+			// Like MySQL, a failed execution isn't retried, the event is closed to wait for the next schedule.
+			logger.Warn("fail to execute event", zap.Error(err))
+			if err = h.cli.CloseTimerEvent(h.ctx, timer.ID, eventID, closeOpts...); err != nil {
+				logger.Warn("fail to close event", zap.Error(err))
+				return
+			}
+		}
+		if !hasNext {
+			h.completeEvent(logger, timer.ID, event.EventInfo)
+		}
+	}()
+	return nil
+}
+
+// completeEvent disables or drops the event which won't be executed any more according to ON COMPLETION.
+func (h *eventHook) completeEvent(logger *zap.Logger, timerID string, info *EventInfo) {
+	var err error
+	if info.Preserve {
+		logger.Info("disable completed event")
+		err = h.cli.UpdateTimer(h.ctx, timerID, timerapi.WithSetEnable(false))
+	} else {
+		logger.Info("drop completed event")
+		_, err = h.cli.DeleteTimer(h.ctx, timerID)
+	}
+	if err != nil && !errors.ErrorEqual(err, timerapi.ErrTimerNotExist) {
+		logger.Warn("fail to complete event", zap.Error(err))
+	}
+}
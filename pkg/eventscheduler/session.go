@@ -0,0 +1,118 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventscheduler
+
+import (
+	"context"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+type sessionPool interface {
+	Get() (pools.Resource, error)
+	Put(pools.Resource)
+}
+
+// executeEvent executes the body of an event in a new session, with the environment when the event was created and
+// the privileges of the definer. The body and finish run in one transaction, and nothing is committed if any of them
+// fails. If the body commits implicitly, e.g. it's a DDL statement, finish runs in a new transaction after the body.
+func executeEvent(ctx context.Context, sessFactory func() (pools.Resource, error), info *EventInfo,
+	finish func(context.Context, sqlexec.SQLExecutor) error) error {
+	resource, err := sessFactory()
+	if err != nil {
+		return err
+	}
+	defer resource.Close()
+
+	sctx, ok := resource.(sessionctx.Context)
+	if !ok {
+		return errors.Errorf("%T cannot be casted to sessionctx.Context", resource)
+	}
+	sessVars := sctx.GetSessionVars()
+	for _, v := range []struct{ name, value string }{
+		{variable.SQLModeVar, info.SQLMode},
+		{variable.TimeZone, info.TimeZone},
+		{variable.CharacterSetClient, info.Charset},
+		{variable.CollationConnection, info.Collation},
+	} {
+		if v.value == "" {
+			continue
+		}
+		if err := sessVars.SetSystemVar(v.name, v.value); err != nil {
+			return err
+		}
+	}
+	sessVars.CurrentDB = info.Schema
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil && info.Definer != nil {
+		user, host := info.Definer.Username, info.Definer.Hostname
+		sessVars.User = &auth.UserIdentity{Username: user, Hostname: host, AuthUsername: user, AuthHostname: host}
+		sessVars.ActiveRoles = pm.GetDefaultRoles(user, host)
+		pm.AuthSuccess(user, host)
+	}
+
+	p := parser.New()
+	p.SetSQLMode(sessVars.SQLMode)
+	charset, collation := sessVars.GetCharsetInfo()
+	stmt, err := p.ParseOneStmt(info.Body, charset, collation)
+	if err != nil {
+		return err
+	}
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	exec := sctx.GetSQLExecutor()
+	if _, err = exec.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_, err := exec.ExecuteInternal(ctx, "ROLLBACK")
+			terror.Log(err)
+		}
+	}()
+
+	rs, err := exec.ExecuteStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	if rs != nil {
+		_, err = sqlexec.DrainRecordSet(ctx, rs, sessVars.MaxChunkSize)
+		if closeErr := rs.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// finish works on the system tables, which are not visible to the definer.
+	privilege.BindPrivilegeManager(sctx, nil)
+	if err = finish(ctx, exec); err != nil {
+		return err
+	}
+	if _, err = exec.ExecuteInternal(ctx, "COMMIT"); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
@@ -468,6 +468,9 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	})
 	sctx := a.Ctx
 	ctx = util.SetSessionID(ctx, sctx.GetSessionVars().ConnectionID)
+	if err := checkXAState(sctx, a.StmtNode); err != nil {
+		return nil, err
+	}
 	if _, ok := a.Plan.(*plannercore.Analyze); ok && sctx.GetSessionVars().InRestrictedSQL {
 		oriStats, ok := sctx.GetSessionVars().GetSystemVar(variable.TiDBBuildStatsConcurrency)
 		if !ok {
@@ -607,7 +610,7 @@ func (a *ExecStmt) handleStmtForeignKeyTrigger(ctx context.Context, e exec.Execu
 		// then the fk cascade executor can't read the mem-buffer changed by the ExecStmt.
 		a.Ctx.StmtCommit(ctx)
 	}
-	err := a.handleForeignKeyTrigger(ctx, e, 1)
+	err := handleForeignKeyTrigger(ctx, a.Ctx, e, 1)
 	if err != nil {
 		err1 := a.handleFKTriggerError(stmtCtx)
 		if err1 != nil {
@@ -623,23 +626,30 @@ func (a *ExecStmt) handleStmtForeignKeyTrigger(ctx context.Context, e exec.Execu
 
 var maxForeignKeyCascadeDepth = 15
 
-func (a *ExecStmt) handleForeignKeyTrigger(ctx context.Context, e exec.Executor, depth int) error {
-	exec, ok := e.(WithForeignKeyTrigger)
-	if !ok {
-		return nil
-	}
-	fkChecks := exec.GetFKChecks()
-	for _, fkCheck := range fkChecks {
-		err := fkCheck.doCheck(ctx)
-		if err != nil {
-			return err
+// handleForeignKeyTrigger checks the foreign keys, executes the foreign key cascades and activates the AFTER triggers
+// of the executor.
+func handleForeignKeyTrigger(ctx context.Context, sctx sessionctx.Context, e exec.Executor, depth int) error {
+	if exec, ok := e.(WithForeignKeyTrigger); ok {
+		fkChecks := exec.GetFKChecks()
+		for _, fkCheck := range fkChecks {
+			err := fkCheck.doCheck(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		fkCascades := exec.GetFKCascades()
+		for _, fkCascade := range fkCascades {
+			err := handleForeignKeyCascade(ctx, sctx, fkCascade, depth)
+			if err != nil {
+				return err
+			}
 		}
 	}
-	fkCascades := exec.GetFKCascades()
-	for _, fkCascade := range fkCascades {
-		err := a.handleForeignKeyCascade(ctx, fkCascade, depth)
-		if err != nil {
-			return err
+	if exec, ok := e.(WithTrigger); ok {
+		for _, trigger := range exec.GetTriggers() {
+			if err := trigger.activateAfter(ctx, depth); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -656,10 +666,10 @@ func (a *ExecStmt) handleForeignKeyTrigger(ctx context.Context, e exec.Executor,
 //  3. Close the executor.
 //  4. `StmtCommit` to commit the kv change to transaction mem-buffer.
 //  5. If the foreign key cascade behaviour has more fk value need to be cascaded, go to step 1.
-func (a *ExecStmt) handleForeignKeyCascade(ctx context.Context, fkc *FKCascadeExec, depth int) error {
-	if a.Ctx.GetSessionVars().StmtCtx.RuntimeStatsColl != nil {
+func handleForeignKeyCascade(ctx context.Context, sctx sessionctx.Context, fkc *FKCascadeExec, depth int) error {
+	if sctx.GetSessionVars().StmtCtx.RuntimeStatsColl != nil {
 		fkc.stats = &FKCascadeRuntimeStats{}
-		defer a.Ctx.GetSessionVars().StmtCtx.RuntimeStatsColl.RegisterStats(fkc.plan.ID(), fkc.stats)
+		defer sctx.GetSessionVars().StmtCtx.RuntimeStatsColl.RegisterStats(fkc.plan.ID(), fkc.stats)
 	}
 	if len(fkc.fkValues) == 0 && len(fkc.fkUpdatedValuesMap) == 0 {
 		return nil
@@ -667,9 +677,9 @@ func (a *ExecStmt) handleForeignKeyCascade(ctx context.Context, fkc *FKCascadeEx
 	if depth > maxForeignKeyCascadeDepth {
 		return exeerrors.ErrForeignKeyCascadeDepthExceeded.GenWithStackByArgs(maxForeignKeyCascadeDepth)
 	}
-	a.Ctx.GetSessionVars().StmtCtx.InHandleForeignKeyTrigger = true
+	sctx.GetSessionVars().StmtCtx.InHandleForeignKeyTrigger = true
 	defer func() {
-		a.Ctx.GetSessionVars().StmtCtx.InHandleForeignKeyTrigger = false
+		sctx.GetSessionVars().StmtCtx.InHandleForeignKeyTrigger = false
 	}()
 	if fkc.stats != nil {
 		start := time.Now()
@@ -702,8 +712,8 @@ func (a *ExecStmt) handleForeignKeyCascade(ctx context.Context, fkc *FKCascadeEx
 		}
 		// Call `StmtCommit` uses to flush the fk cascade executor change into txn mem-buffer,
 		// then the later fk cascade executors can see the mem-buffer changes.
-		a.Ctx.StmtCommit(ctx)
-		err = a.handleForeignKeyTrigger(ctx, e, depth+1)
+		sctx.StmtCommit(ctx)
+		err = handleForeignKeyTrigger(ctx, sctx, e, depth+1)
 		if err != nil {
 			return err
 		}
@@ -711,10 +721,10 @@ func (a *ExecStmt) handleForeignKeyCascade(ctx context.Context, fkc *FKCascadeEx
 }
 
 // prepareFKCascadeContext records a transaction savepoint for foreign key cascade when this ExecStmt has foreign key
-// cascade behaviour and this ExecStmt is in transaction.
+// cascade behaviour or triggers and this ExecStmt is in transaction.
 func (a *ExecStmt) prepareFKCascadeContext(e exec.Executor) {
 	exec, ok := e.(WithForeignKeyTrigger)
-	if !ok || !exec.HasFKCascades() {
+	if (!ok || !exec.HasFKCascades()) && !hasTriggers(e) {
 		return
 	}
 	sessVar := a.Ctx.GetSessionVars()
@@ -1360,6 +1370,9 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	a.LogSlowQuery(txnTS, succ, hasMoreResults)
 	a.SummaryStmt(succ)
 	a.observeStmtFinishedForTopSQL()
+	if succ && a.Plan != nil && !sessVars.InRestrictedSQL {
+		plannercore.CollectCardinalityFeedback(a.Ctx.GetPlanCtx(), a.Plan)
+	}
 	if sessVars.StmtCtx.IsTiFlash.Load() {
 		if succ {
 			executor_metrics.TotalTiFlashQuerySuccCounter.Inc()
@@ -227,6 +227,10 @@ type SlidingWindowAggFunc interface {
 	// PartialResult stores the intermediate result which will be used in the next
 	// sliding window, ensure call ResetPartialResult after a frame are evaluated
 	// completely.
+	// Implementations should add the rows in [lastEnd, lastEnd+shiftEnd) before
+	// retracting the rows in [lastStart, lastStart+shiftStart), so that a retracted
+	// row has always been added before, which is required by the functions keeping
+	// the state of every value, e.g. the distinct aggregate functions.
 	Slide(sctx AggFuncUpdateContext, getRow func(uint64) chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error
 }
 
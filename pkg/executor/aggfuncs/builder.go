@@ -109,11 +109,62 @@ func BuildWindowFunctions(ctx AggFuncBuildContext, windowFuncDesc *aggregation.A
 		return buildMaxMinInWindowFunction(windowFuncDesc, ordinal, true)
 	case ast.AggFuncMin:
 		return buildMaxMinInWindowFunction(windowFuncDesc, ordinal, false)
+	case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg:
+		// The distinct aggFunc using in the window function will using the sliding window algo.
+		if windowFuncDesc.HasDistinct {
+			if agg := buildDistinctInWindowFunction(ctx, windowFuncDesc, ordinal); agg != nil {
+				return agg
+			}
+		}
+		return Build(ctx, windowFuncDesc, ordinal)
+	case ast.AggFuncGroupConcat:
+		return buildGroupConcatInWindowFunction(ctx, windowFuncDesc, ordinal)
 	default:
 		return Build(ctx, windowFuncDesc, ordinal)
 	}
 }
 
+// buildDistinctInWindowFunction builds the sliding window implementation of
+// "COUNT", "SUM" and "AVG" with distinct, it returns nil if there is none.
+func buildDistinctInWindowFunction(ctx AggFuncBuildContext, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+		retTp:   aggFuncDesc.RetTp,
+	}
+	if aggFuncDesc.Name == ast.AggFuncCount {
+		return newCountDistinct4Sliding(base)
+	}
+	switch aggFuncDesc.RetTp.EvalType() {
+	case types.ETDecimal:
+		if aggFuncDesc.Name == ast.AggFuncSum {
+			return &sum4DistinctDecimalSliding{baseSlidingDistinctDecimal{base}}
+		}
+		return &avg4DistinctDecimalSliding{baseSlidingDistinctDecimal{base}}
+	default:
+		// Retracting float values loses precision, so fall back to the non-sliding implementations.
+		if ctx.GetSessionVars().WindowingUseHighPrecision {
+			return nil
+		}
+		if aggFuncDesc.Name == ast.AggFuncSum {
+			return &sum4DistinctFloat64Sliding{baseSlidingDistinctFloat64{base}}
+		}
+		return &avg4DistinctFloat64Sliding{baseSlidingDistinctFloat64{base}}
+	}
+}
+
+// buildGroupConcatInWindowFunction builds the sliding window implementation of "GROUP_CONCAT".
+func buildGroupConcatInWindowFunction(ctx AggFuncBuildContext, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	agg := buildGroupConcat(ctx, aggFuncDesc, ordinal)
+	switch x := agg.(type) {
+	case *groupConcat:
+		return newGroupConcat4Sliding(x.baseGroupConcat4String, false)
+	case *groupConcatDistinct:
+		return newGroupConcat4Sliding(x.baseGroupConcat4String, true)
+	}
+	return agg
+}
+
 func buildApproxCountDistinct(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseApproxCountDistinct{baseAggFunc{
 		args:    aggFuncDesc.Args,
@@ -664,7 +715,7 @@ func buildFirstValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc
 		args:    aggFuncDesc.Args,
 		ordinal: ordinal,
 	}
-	return &firstValue{baseAggFunc: base, tp: aggFuncDesc.RetTp}
+	return &firstValue{baseAggFunc: base, tp: aggFuncDesc.RetTp, ignoreNull: aggFuncDesc.IgnoreNull}
 }
 
 func buildLastValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
@@ -672,7 +723,7 @@ func buildLastValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		args:    aggFuncDesc.Args,
 		ordinal: ordinal,
 	}
-	return &lastValue{baseAggFunc: base, tp: aggFuncDesc.RetTp}
+	return &lastValue{baseAggFunc: base, tp: aggFuncDesc.RetTp, ignoreNull: aggFuncDesc.IgnoreNull}
 }
 
 func buildCumeDist(ordinal int, orderByCols []*expression.Column) AggFunc {
@@ -690,7 +741,13 @@ func buildNthValue(ctx AggFuncBuildContext, aggFuncDesc *aggregation.AggFuncDesc
 	}
 	// Already checked when building the function description.
 	nth, _, _ := expression.GetUint64FromConstant(ctx.GetEvalCtx(), aggFuncDesc.Args[1])
-	return &nthValue{baseAggFunc: base, tp: aggFuncDesc.RetTp, nth: nth}
+	return &nthValue{
+		baseAggFunc: base,
+		tp:          aggFuncDesc.RetTp,
+		nth:         nth,
+		ignoreNull:  aggFuncDesc.IgnoreNull,
+		fromLast:    aggFuncDesc.FromLast,
+	}
 }
 
 func buildNtile(ctx AggFuncBuildContext, aggFuncDes *aggregation.AggFuncDesc, ordinal int) AggFunc {
@@ -730,7 +787,7 @@ func buildLeadLag(ctx AggFuncBuildContext, aggFuncDesc *aggregation.AggFuncDesc,
 		ordinal: ordinal,
 	}
 	ve, _ := buildValueEvaluator(aggFuncDesc.RetTp)
-	return baseLeadLag{baseAggFunc: base, offset: offset, defaultExpr: defaultExpr, valueEvaluator: ve}
+	return baseLeadLag{baseAggFunc: base, offset: offset, defaultExpr: defaultExpr, valueEvaluator: ve, ignoreNull: aggFuncDesc.IgnoreNull}
 }
 
 func buildLead(ctx AggFuncBuildContext, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/collate"
 	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+	"github.com/pingcap/tidb/pkg/util/hack"
 	"github.com/pingcap/tidb/pkg/util/set"
 )
 
@@ -40,6 +41,10 @@ const (
 	DefPartialResult4GroupConcatOrderSize = int64(unsafe.Sizeof(partialResult4GroupConcatOrder{}))
 	// DefPartialResult4GroupConcatOrderDistinctSize is the size of partialResult4GroupConcatOrderDistinct
 	DefPartialResult4GroupConcatOrderDistinctSize = int64(unsafe.Sizeof(partialResult4GroupConcatOrderDistinct{}))
+	// DefPartialResult4GroupConcatSlidingSize is the size of partialResult4GroupConcatSliding
+	DefPartialResult4GroupConcatSlidingSize = int64(unsafe.Sizeof(partialResult4GroupConcatSliding{}))
+	// DefGroupConcatSlidingEntrySize is the size of groupConcatSlidingEntry
+	DefGroupConcatSlidingEntrySize = int64(unsafe.Sizeof(groupConcatSlidingEntry{}))
 
 	// DefBytesBufferSize is the size of bytes.Buffer.
 	DefBytesBufferSize = int64(unsafe.Sizeof(bytes.Buffer{}))
@@ -294,6 +299,175 @@ func (e *groupConcatDistinct) GetTruncated() *int32 {
 	return e.truncated
 }
 
+type groupConcatSlidingEntry struct {
+	val string
+	// key is the collation aware encoding of the arguments, it is only set for distinct group_concat.
+	key    string
+	isNull bool
+}
+
+type partialResult4GroupConcatSliding struct {
+	// entries holds one entry for every row in the current frame.
+	entries           []groupConcatSlidingEntry
+	counter           distinctCounter
+	valsBuf           *bytes.Buffer
+	encodeBytesBuffer []byte
+}
+
+// groupConcat4Sliding evaluates `group_concat([distinct] ...)` as a window
+// function. It keeps the evaluated arguments of every row in the frame, so the
+// rows sliding out of the frame can be retracted without re-reading the whole frame.
+type groupConcat4Sliding struct {
+	baseGroupConcat4String
+	distinct  bool
+	collators []collate.Collator
+}
+
+func newGroupConcat4Sliding(base baseGroupConcat4String, distinct bool) *groupConcat4Sliding {
+	e := &groupConcat4Sliding{baseGroupConcat4String: base, distinct: distinct}
+	if distinct {
+		e.collators = make([]collate.Collator, 0, len(e.args))
+		for _, arg := range e.args {
+			e.collators = append(e.collators, collate.GetCollator(arg.GetType().GetCollate()))
+		}
+	}
+	return e
+}
+
+func (*groupConcat4Sliding) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	p := &partialResult4GroupConcatSliding{
+		counter: make(distinctCounter),
+		valsBuf: &bytes.Buffer{},
+	}
+	return PartialResult(p), DefPartialResult4GroupConcatSlidingSize + DefBytesBufferSize
+}
+
+func (*groupConcat4Sliding) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	p.entries = p.entries[:0]
+	p.counter = make(distinctCounter)
+}
+
+func (e *groupConcat4Sliding) evalRow(sctx AggFuncUpdateContext, row chunk.Row, p *partialResult4GroupConcatSliding) (entry groupConcatSlidingEntry, err error) {
+	p.valsBuf.Reset()
+	p.encodeBytesBuffer = p.encodeBytesBuffer[:0]
+	for i, arg := range e.args {
+		v, isNull, err := arg.EvalString(sctx, row)
+		if err != nil {
+			return entry, err
+		}
+		if isNull {
+			entry.isNull = true
+			return entry, nil
+		}
+		if e.distinct {
+			p.encodeBytesBuffer = codec.EncodeBytes(p.encodeBytesBuffer, e.collators[i].Key(v))
+		}
+		p.valsBuf.WriteString(v)
+	}
+	entry.val = p.valsBuf.String()
+	if e.distinct {
+		entry.key = string(p.encodeBytesBuffer)
+	}
+	return entry, nil
+}
+
+func (e *groupConcat4Sliding) pushRow(sctx AggFuncUpdateContext, row chunk.Row, p *partialResult4GroupConcatSliding) (memDelta int64, err error) {
+	entry, err := e.evalRow(sctx, row, p)
+	if err != nil {
+		return 0, err
+	}
+	p.entries = append(p.entries, entry)
+	memDelta = DefGroupConcatSlidingEntrySize + int64(len(entry.val))
+	if e.distinct && !entry.isNull {
+		_, delta := p.counter.add(hack.Slice(entry.key))
+		memDelta += delta
+	}
+	return memDelta, nil
+}
+
+func (e *groupConcat4Sliding) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	for _, row := range rowsInGroup {
+		delta, err := e.pushRow(sctx, row, p)
+		memDelta += delta
+		if err != nil {
+			return memDelta, err
+		}
+	}
+	return memDelta, nil
+}
+
+var _ SlidingWindowAggFunc = &groupConcat4Sliding{}
+
+func (e *groupConcat4Sliding) Slide(sctx AggFuncUpdateContext, getRow func(uint64) chunk.Row, _, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	for i := uint64(0); i < shiftEnd; i++ {
+		if _, err := e.pushRow(sctx, getRow(lastEnd+i), p); err != nil {
+			return err
+		}
+	}
+	// The entries are kept in the order of the rows, so the rows sliding out
+	// of the frame are always at the front.
+	shift := min(int(shiftStart), len(p.entries))
+	if e.distinct {
+		for _, entry := range p.entries[:shift] {
+			if !entry.isNull {
+				p.counter.remove(hack.Slice(entry.key))
+			}
+		}
+	}
+	p.entries = p.entries[shift:]
+	return nil
+}
+
+func (e *groupConcat4Sliding) AppendFinalResult2Chunk(sctx AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	var (
+		buffer *bytes.Buffer
+		seen   map[string]struct{}
+	)
+	if e.distinct {
+		seen = make(map[string]struct{}, len(p.counter))
+	}
+	for _, entry := range p.entries {
+		if entry.isNull {
+			continue
+		}
+		if e.distinct {
+			if _, ok := seen[entry.key]; ok {
+				continue
+			}
+			seen[entry.key] = struct{}{}
+		}
+		if buffer == nil {
+			buffer = &bytes.Buffer{}
+		} else {
+			buffer.WriteString(e.sep)
+		}
+		buffer.WriteString(entry.val)
+	}
+	if buffer == nil {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	if err := e.truncatePartialResultIfNeed(sctx, buffer); err != nil {
+		return err
+	}
+	chk.AppendString(e.ordinal, buffer.String())
+	return nil
+}
+
+// SetTruncated will be called in `executorBuilder#buildHashAgg` with duck-type.
+func (e *groupConcat4Sliding) SetTruncated(t *int32) {
+	e.truncated = t
+}
+
+// GetTruncated will be called in `executorBuilder#buildHashAgg` with duck-type.
+func (e *groupConcat4Sliding) GetTruncated() *int32 {
+	return e.truncated
+}
+
 type sortRow struct {
 	buffer  *bytes.Buffer
 	byItems []*types.Datum
@@ -15,6 +15,7 @@
 package aggfuncs
 
 import (
+	"sort"
 	"unsafe"
 
 	"github.com/pingcap/tidb/pkg/expression"
@@ -32,11 +33,14 @@ type baseLeadLag struct {
 
 	defaultExpr expression.Expression
 	offset      uint64
+	ignoreNull  bool
 }
 
 type partialResult4LeadLag struct {
 	rows   []chunk.Row
 	curIdx uint64
+	// notNullIdx records the indexes of rows whose argument is not null, it is only used for `IGNORE NULLS`.
+	notNullIdx []uint64
 }
 
 func (*baseLeadLag) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -47,15 +51,53 @@ func (*baseLeadLag) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4LeadLag)(pr)
 	p.rows = p.rows[:0]
 	p.curIdx = 0
+	p.notNullIdx = p.notNullIdx[:0]
 }
 
-func (*baseLeadLag) UpdatePartialResult(_ AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+func (v *baseLeadLag) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LeadLag)(pr)
+	if v.ignoreNull {
+		for i, row := range rowsInGroup {
+			isNull, err := isNullArg(sctx, v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if !isNull {
+				p.notNullIdx = append(p.notNullIdx, uint64(len(p.rows)+i))
+				memDelta += DefUint64Size
+			}
+		}
+	}
 	p.rows = append(p.rows, rowsInGroup...)
 	memDelta += int64(len(rowsInGroup)) * DefRowSize
 	return memDelta, nil
 }
 
+// targetRow returns the index of the row which is `offset` rows away from the current row,
+// rows with null argument are not counted when `IGNORE NULLS` is specified.
+func (v *baseLeadLag) targetRow(p *partialResult4LeadLag, forward bool) (idx uint64, ok bool) {
+	if !v.ignoreNull || v.offset == 0 {
+		if forward {
+			return p.curIdx + v.offset, p.curIdx+v.offset < uint64(len(p.rows))
+		}
+		return p.curIdx - v.offset, p.curIdx >= v.offset
+	}
+	if forward {
+		// The number of not null rows before or at the current row.
+		i := uint64(sort.Search(len(p.notNullIdx), func(i int) bool { return p.notNullIdx[i] > p.curIdx }))
+		if i+v.offset-1 < uint64(len(p.notNullIdx)) {
+			return p.notNullIdx[i+v.offset-1], true
+		}
+		return 0, false
+	}
+	// The number of not null rows before the current row.
+	i := uint64(sort.Search(len(p.notNullIdx), func(i int) bool { return p.notNullIdx[i] >= p.curIdx }))
+	if i >= v.offset {
+		return p.notNullIdx[i-v.offset], true
+	}
+	return 0, false
+}
+
 type lead struct {
 	baseLeadLag
 }
@@ -63,8 +105,8 @@ type lead struct {
 func (v *lead) AppendFinalResult2Chunk(sctx AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
 	var err error
-	if p.curIdx+v.offset < uint64(len(p.rows)) {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx+v.offset])
+	if idx, ok := v.targetRow(p, true); ok {
+		_, err = v.evaluateRow(sctx, v.args[0], p.rows[idx])
 	} else {
 		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
 	}
@@ -83,8 +125,8 @@ type lag struct {
 func (v *lag) AppendFinalResult2Chunk(sctx AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
 	var err error
-	if p.curIdx >= v.offset {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx-v.offset])
+	if idx, ok := v.targetRow(p, false); ok {
+		_, err = v.evaluateRow(sctx, v.args[0], p.rows[idx])
 	} else {
 		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
 	}
@@ -236,10 +236,21 @@ func buildValueEvaluator(tp *types.FieldType) (ve valueEvaluator, memDelta int64
 	return nil, 0
 }
 
+// isNullArg checks whether the argument of a window function evaluates to null on the given row.
+// It is used to skip the null values when `IGNORE NULLS` is specified.
+func isNullArg(ctx expression.EvalContext, arg expression.Expression, row chunk.Row) (bool, error) {
+	d, err := arg.Eval(ctx, row)
+	if err != nil {
+		return false, err
+	}
+	return d.IsNull(), nil
+}
+
 type firstValue struct {
 	baseAggFunc
 
-	tp *types.FieldType
+	tp         *types.FieldType
+	ignoreNull bool
 }
 
 type partialResult4FirstValue struct {
@@ -263,12 +274,22 @@ func (v *firstValue) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup
 	if p.gotFirstValue {
 		return 0, nil
 	}
-	if len(rowsInGroup) > 0 {
+	for _, row := range rowsInGroup {
+		if v.ignoreNull {
+			isNull, err := isNullArg(sctx, v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
+		}
 		p.gotFirstValue = true
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[0])
+		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], row)
 		if err != nil {
 			return 0, err
 		}
+		break
 	}
 	return memDelta, nil
 }
@@ -286,7 +307,8 @@ func (v *firstValue) AppendFinalResult2Chunk(_ AggFuncUpdateContext, pr PartialR
 type lastValue struct {
 	baseAggFunc
 
-	tp *types.FieldType
+	tp         *types.FieldType
+	ignoreNull bool
 }
 
 type partialResult4LastValue struct {
@@ -296,7 +318,7 @@ type partialResult4LastValue struct {
 
 func (v *lastValue) AllocPartialResult() (pr PartialResult, memDelta int64) {
 	ve, veMemDelta := buildValueEvaluator(v.tp)
-	p := &partialResult4FirstValue{evaluator: ve}
+	p := &partialResult4LastValue{evaluator: ve}
 	return PartialResult(p), DefPartialResult4LastValueSize + veMemDelta
 }
 
@@ -307,12 +329,22 @@ func (*lastValue) ResetPartialResult(pr PartialResult) {
 
 func (v *lastValue) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LastValue)(pr)
-	if len(rowsInGroup) > 0 {
+	for i := len(rowsInGroup) - 1; i >= 0; i-- {
+		if v.ignoreNull {
+			isNull, err := isNullArg(sctx, v.args[0], rowsInGroup[i])
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
+		}
 		p.gotLastValue = true
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[len(rowsInGroup)-1])
+		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[i])
 		if err != nil {
 			return 0, err
 		}
+		break
 	}
 	return memDelta, nil
 }
@@ -330,24 +362,30 @@ func (v *lastValue) AppendFinalResult2Chunk(_ AggFuncUpdateContext, pr PartialRe
 type nthValue struct {
 	baseAggFunc
 
-	tp  *types.FieldType
-	nth uint64
+	tp         *types.FieldType
+	nth        uint64
+	ignoreNull bool
+	// fromLast indicates the rows are counted from the end of the frame.
+	fromLast bool
 }
 
 type partialResult4NthValue struct {
 	seenRows  uint64
 	evaluator valueEvaluator
+	// lastRows keeps at most `nth` latest candidate rows, it is only used when counting from last.
+	lastRows []chunk.Row
 }
 
 func (v *nthValue) AllocPartialResult() (pr PartialResult, memDelta int64) {
 	ve, veMemDelta := buildValueEvaluator(v.tp)
-	p := &partialResult4FirstValue{evaluator: ve}
+	p := &partialResult4NthValue{evaluator: ve}
 	return PartialResult(p), DefPartialResult4NthValueSize + veMemDelta
 }
 
 func (*nthValue) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4NthValue)(pr)
 	p.seenRows = 0
+	p.lastRows = p.lastRows[:0]
 }
 
 func (v *nthValue) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
@@ -355,14 +393,88 @@ func (v *nthValue) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []
 		return 0, nil
 	}
 	p := (*partialResult4NthValue)(pr)
-	numRows := uint64(len(rowsInGroup))
-	if v.nth > p.seenRows && v.nth-p.seenRows <= numRows {
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[v.nth-p.seenRows-1])
+	if v.fromLast {
+		return v.updateFromLast(sctx, rowsInGroup, p)
+	}
+	if !v.ignoreNull {
+		numRows := uint64(len(rowsInGroup))
+		if v.nth > p.seenRows && v.nth-p.seenRows <= numRows {
+			memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[v.nth-p.seenRows-1])
+			if err != nil {
+				return 0, err
+			}
+		}
+		p.seenRows += numRows
+		return memDelta, nil
+	}
+	for _, row := range rowsInGroup {
+		if p.seenRows >= v.nth {
+			break
+		}
+		isNull, err := isNullArg(sctx, v.args[0], row)
+		if err != nil {
+			return 0, err
+		}
+		if isNull {
+			continue
+		}
+		p.seenRows++
+		if p.seenRows == v.nth {
+			memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	return memDelta, nil
+}
+
+// updateFromLast keeps the latest `nth` candidate rows, the result is the first of them
+// once the frame contains enough candidates.
+func (v *nthValue) updateFromLast(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, p *partialResult4NthValue) (memDelta int64, err error) {
+	// Find the latest candidates of this batch, at most `nth` of them are needed.
+	var numCandidates uint64
+	start := len(rowsInGroup)
+	for start > 0 && numCandidates < v.nth {
+		start--
+		if v.ignoreNull {
+			isNull, err := isNullArg(sctx, v.args[0], rowsInGroup[start])
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
+		}
+		numCandidates++
+	}
+	// Drop the earliest candidates kept by the previous batches, so no more than `nth` rows are kept.
+	oldLen := len(p.lastRows)
+	if dropped := oldLen + int(numCandidates) - int(v.nth); dropped > 0 {
+		p.lastRows = append(p.lastRows[:0], p.lastRows[dropped:]...)
+		memDelta -= int64(dropped) * DefRowSize
+	}
+	for _, row := range rowsInGroup[start:] {
+		if v.ignoreNull {
+			isNull, err := isNullArg(sctx, v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
+		}
+		p.lastRows = append(p.lastRows, row)
+		memDelta += DefRowSize
+	}
+	p.seenRows = uint64(len(p.lastRows))
+	if p.seenRows == v.nth {
+		evalMemDelta, err := p.evaluator.evaluateRow(sctx, v.args[0], p.lastRows[0])
 		if err != nil {
 			return 0, err
 		}
+		memDelta += evalMemDelta
 	}
-	p.seenRows += numRows
 	return memDelta, nil
 }
 
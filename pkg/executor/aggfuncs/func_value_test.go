@@ -18,10 +18,13 @@ import (
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/executor/aggfuncs"
+	"github.com/pingcap/tidb/pkg/expression/aggregation"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func getEvaluatedMemDelta(row *chunk.Row, dataType *types.FieldType) (memDelta int64) {
@@ -100,3 +103,36 @@ func TestMemValue(t *testing.T) {
 		testWindowAggMemFunc(t, test)
 	}
 }
+
+func TestMemNthValueFromLast(t *testing.T) {
+	test := buildWindowTester(ast.WindowFuncNthValue, mysql.TypeLonglong, 2, 0, 5)
+	srcChk := test.genSrcChk()
+	ctx := mock.NewContext()
+
+	desc, err := aggregation.NewAggFuncDesc(ctx, test.funcName, test.args, false)
+	require.NoError(t, err)
+	desc.FromLast = true
+	finalFunc := aggfuncs.BuildWindowFunctions(ctx, desc, 0, test.orderByCols)
+	finalPr, _ := finalFunc.AllocPartialResult()
+
+	// Only the latest 2 rows are kept, the memory of the dropped rows is released.
+	expected := []int64{aggfuncs.DefRowSize, aggfuncs.DefRowSize, 0, 0, 0}
+	var total int64
+	for i := 0; i < srcChk.NumRows(); i++ {
+		memDelta, err := finalFunc.UpdatePartialResult(ctx, []chunk.Row{srcChk.GetRow(i)}, finalPr)
+		require.NoError(t, err)
+		require.Equal(t, expected[i], memDelta)
+		total += memDelta
+	}
+	require.Equal(t, 2*aggfuncs.DefRowSize, total)
+
+	// A batch larger than `nth` only keeps its latest candidates.
+	finalFunc.ResetPartialResult(finalPr)
+	rows := make([]chunk.Row, 0, srcChk.NumRows())
+	for i := 0; i < srcChk.NumRows(); i++ {
+		rows = append(rows, srcChk.GetRow(i))
+	}
+	memDelta, err := finalFunc.UpdatePartialResult(ctx, rows, finalPr)
+	require.NoError(t, err)
+	require.Equal(t, 2*aggfuncs.DefRowSize, memDelta)
+}
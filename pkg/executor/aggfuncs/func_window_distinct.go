@@ -0,0 +1,403 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"encoding/binary"
+	"math"
+	"unsafe"
+
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/hack"
+)
+
+// The functions in this file evaluate `count(distinct ...)`, `sum(distinct ...)`
+// and `avg(distinct ...)` as window functions. Unlike their counterparts used
+// by aggregation, they count the occurrences of every distinct value inside
+// the current frame, so that a value can be retracted once its last
+// occurrence slides out of the frame.
+
+const (
+	// DefPartialResult4CountDistinctSlidingSize is the size of partialResult4CountDistinctSliding
+	DefPartialResult4CountDistinctSlidingSize = int64(unsafe.Sizeof(partialResult4CountDistinctSliding{}))
+	// DefPartialResult4DistinctDecimalSlidingSize is the size of partialResult4DistinctDecimalSliding
+	DefPartialResult4DistinctDecimalSlidingSize = int64(unsafe.Sizeof(partialResult4DistinctDecimalSliding{}))
+	// DefPartialResult4DistinctFloat64SlidingSize is the size of partialResult4DistinctFloat64Sliding
+	DefPartialResult4DistinctFloat64SlidingSize = int64(unsafe.Sizeof(partialResult4DistinctFloat64Sliding{}))
+
+	distinctCounterEntrySize = int64(unsafe.Sizeof("")) + int64(unsafe.Sizeof(int64(0)))
+)
+
+// distinctCounter records how many times every encoded value occurs in the current frame.
+type distinctCounter map[string]int64
+
+// add records one more occurrence of key and reports whether key is new to the frame.
+func (c distinctCounter) add(key []byte) (isNew bool, memDelta int64) {
+	if cnt, ok := c[string(hack.String(key))]; ok {
+		c[string(hack.String(key))] = cnt + 1
+		return false, 0
+	}
+	c[string(key)] = 1
+	return true, distinctCounterEntrySize + int64(len(key))
+}
+
+// remove drops one occurrence of key and reports whether key has left the frame.
+func (c distinctCounter) remove(key []byte) (isGone bool) {
+	cnt, ok := c[string(hack.String(key))]
+	if !ok {
+		return false
+	}
+	if cnt > 1 {
+		c[string(hack.String(key))] = cnt - 1
+		return false
+	}
+	delete(c, string(hack.String(key)))
+	return true
+}
+
+type partialResult4CountDistinctSliding struct {
+	counter distinctCounter
+	// encoded is a reusable buffer to encode the arguments of one row.
+	encoded []byte
+}
+
+type countDistinct4Sliding struct {
+	baseCount
+	collators []collate.Collator
+}
+
+func newCountDistinct4Sliding(base baseAggFunc) *countDistinct4Sliding {
+	collators := make([]collate.Collator, 0, len(base.args))
+	for _, arg := range base.args {
+		collators = append(collators, collate.GetCollator(arg.GetType().GetCollate()))
+	}
+	return &countDistinct4Sliding{baseCount: baseCount{base}, collators: collators}
+}
+
+func (*countDistinct4Sliding) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	return PartialResult(&partialResult4CountDistinctSliding{counter: make(distinctCounter)}), DefPartialResult4CountDistinctSlidingSize
+}
+
+func (*countDistinct4Sliding) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4CountDistinctSliding)(pr)
+	p.counter = make(distinctCounter)
+}
+
+func (e *countDistinct4Sliding) AppendFinalResult2Chunk(_ AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4CountDistinctSliding)(pr)
+	chk.AppendInt64(e.ordinal, int64(len(p.counter)))
+	return nil
+}
+
+// encodeRow encodes the arguments of row into p.encoded, hasNull is true if any of the arguments is null.
+func (e *countDistinct4Sliding) encodeRow(sctx AggFuncUpdateContext, row chunk.Row, p *partialResult4CountDistinctSliding) (hasNull bool, err error) {
+	// decimal struct is the biggest type we will use.
+	var buf [types.MyDecimalStructSize]byte
+	p.encoded = p.encoded[:0]
+	for i, arg := range e.args {
+		var isNull bool
+		p.encoded, isNull, err = evalAndEncode(sctx, arg, e.collators[i], row, buf[:], p.encoded)
+		if err != nil || isNull {
+			return isNull, err
+		}
+	}
+	return false, nil
+}
+
+func (e *countDistinct4Sliding) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4CountDistinctSliding)(pr)
+	for _, row := range rowsInGroup {
+		hasNull, err := e.encodeRow(sctx, row, p)
+		if err != nil {
+			return memDelta, err
+		}
+		if hasNull {
+			continue
+		}
+		_, delta := p.counter.add(p.encoded)
+		memDelta += delta
+	}
+	return memDelta, nil
+}
+
+var _ SlidingWindowAggFunc = &countDistinct4Sliding{}
+
+func (e *countDistinct4Sliding) Slide(sctx AggFuncUpdateContext, getRow func(uint64) chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4CountDistinctSliding)(pr)
+	for i := uint64(0); i < shiftEnd; i++ {
+		hasNull, err := e.encodeRow(sctx, getRow(lastEnd+i), p)
+		if err != nil {
+			return err
+		}
+		if hasNull {
+			continue
+		}
+		p.counter.add(p.encoded)
+	}
+	for i := uint64(0); i < shiftStart; i++ {
+		hasNull, err := e.encodeRow(sctx, getRow(lastStart+i), p)
+		if err != nil {
+			return err
+		}
+		if hasNull {
+			continue
+		}
+		p.counter.remove(p.encoded)
+	}
+	return nil
+}
+
+type partialResult4DistinctDecimalSliding struct {
+	partialResult4AvgDecimal
+	counter distinctCounter
+}
+
+// baseSlidingDistinctDecimal maintains the sum and the number of the distinct
+// decimal values in the frame, it is wrapped by:
+// - "sum4DistinctDecimalSliding"
+// - "avg4DistinctDecimalSliding"
+type baseSlidingDistinctDecimal struct {
+	baseAggFunc
+}
+
+func (*baseSlidingDistinctDecimal) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	return PartialResult(&partialResult4DistinctDecimalSliding{counter: make(distinctCounter)}), DefPartialResult4DistinctDecimalSlidingSize
+}
+
+func (*baseSlidingDistinctDecimal) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4DistinctDecimalSliding)(pr)
+	p.sum = *types.NewDecFromInt(0)
+	p.count = 0
+	p.counter = make(distinctCounter)
+}
+
+func (e *baseSlidingDistinctDecimal) add(sctx AggFuncUpdateContext, row chunk.Row, p *partialResult4DistinctDecimalSliding) (memDelta int64, err error) {
+	input, isNull, err := e.args[0].EvalDecimal(sctx, row)
+	if err != nil || isNull {
+		return 0, err
+	}
+	hash, err := input.ToHashKey()
+	if err != nil {
+		return 0, err
+	}
+	isNew, memDelta := p.counter.add(hash)
+	if !isNew {
+		return memDelta, nil
+	}
+	newSum := new(types.MyDecimal)
+	if err = types.DecimalAdd(&p.sum, input, newSum); err != nil {
+		return memDelta, err
+	}
+	p.sum = *newSum
+	p.count++
+	return memDelta, nil
+}
+
+func (e *baseSlidingDistinctDecimal) remove(sctx AggFuncUpdateContext, row chunk.Row, p *partialResult4DistinctDecimalSliding) error {
+	input, isNull, err := e.args[0].EvalDecimal(sctx, row)
+	if err != nil || isNull {
+		return err
+	}
+	hash, err := input.ToHashKey()
+	if err != nil {
+		return err
+	}
+	if !p.counter.remove(hash) {
+		return nil
+	}
+	newSum := new(types.MyDecimal)
+	if err = types.DecimalSub(&p.sum, input, newSum); err != nil {
+		return err
+	}
+	p.sum = *newSum
+	p.count--
+	return nil
+}
+
+func (e *baseSlidingDistinctDecimal) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4DistinctDecimalSliding)(pr)
+	for _, row := range rowsInGroup {
+		delta, err := e.add(sctx, row, p)
+		memDelta += delta
+		if err != nil {
+			return memDelta, err
+		}
+	}
+	return memDelta, nil
+}
+
+func (e *baseSlidingDistinctDecimal) Slide(sctx AggFuncUpdateContext, getRow func(uint64) chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4DistinctDecimalSliding)(pr)
+	for i := uint64(0); i < shiftEnd; i++ {
+		if _, err := e.add(sctx, getRow(lastEnd+i), p); err != nil {
+			return err
+		}
+	}
+	for i := uint64(0); i < shiftStart; i++ {
+		if err := e.remove(sctx, getRow(lastStart+i), p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type sum4DistinctDecimalSliding struct {
+	baseSlidingDistinctDecimal
+}
+
+var _ SlidingWindowAggFunc = &sum4DistinctDecimalSliding{}
+
+func (e *sum4DistinctDecimalSliding) AppendFinalResult2Chunk(_ AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4DistinctDecimalSliding)(pr)
+	if p.count == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendMyDecimal(e.ordinal, &p.sum)
+	return nil
+}
+
+type avg4DistinctDecimalSliding struct {
+	baseSlidingDistinctDecimal
+}
+
+var _ SlidingWindowAggFunc = &avg4DistinctDecimalSliding{}
+
+func (e *avg4DistinctDecimalSliding) AppendFinalResult2Chunk(ctx AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4DistinctDecimalSliding)(pr)
+	avg := baseAvgDecimal{e.baseAggFunc}
+	return avg.AppendFinalResult2Chunk(ctx, PartialResult(&p.partialResult4AvgDecimal), chk)
+}
+
+type partialResult4DistinctFloat64Sliding struct {
+	partialResult4AvgFloat64
+	counter distinctCounter
+}
+
+// baseSlidingDistinctFloat64 maintains the sum and the number of the distinct
+// float64 values in the frame, it is wrapped by:
+// - "sum4DistinctFloat64Sliding"
+// - "avg4DistinctFloat64Sliding"
+type baseSlidingDistinctFloat64 struct {
+	baseAggFunc
+}
+
+func (*baseSlidingDistinctFloat64) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	return PartialResult(&partialResult4DistinctFloat64Sliding{counter: make(distinctCounter)}), DefPartialResult4DistinctFloat64SlidingSize
+}
+
+func (*baseSlidingDistinctFloat64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4DistinctFloat64Sliding)(pr)
+	p.sum = 0
+	p.count = 0
+	p.counter = make(distinctCounter)
+}
+
+// evalKey evaluates the argument on row and encodes it as the key of the distinct counter.
+func (e *baseSlidingDistinctFloat64) evalKey(sctx AggFuncUpdateContext, row chunk.Row, key []byte) (input float64, isNull bool, err error) {
+	input, isNull, err = e.args[0].EvalReal(sctx, row)
+	if err != nil || isNull {
+		return 0, isNull, err
+	}
+	if input == 0 {
+		// -0 and +0 are the same distinct value.
+		input = 0
+	}
+	binary.LittleEndian.PutUint64(key, math.Float64bits(input))
+	return input, false, nil
+}
+
+func (e *baseSlidingDistinctFloat64) UpdatePartialResult(sctx AggFuncUpdateContext, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4DistinctFloat64Sliding)(pr)
+	var key [8]byte
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.evalKey(sctx, row, key[:])
+		if err != nil {
+			return memDelta, err
+		}
+		if isNull {
+			continue
+		}
+		isNew, delta := p.counter.add(key[:])
+		memDelta += delta
+		if isNew {
+			p.sum += input
+			p.count++
+		}
+	}
+	return memDelta, nil
+}
+
+func (e *baseSlidingDistinctFloat64) Slide(sctx AggFuncUpdateContext, getRow func(uint64) chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4DistinctFloat64Sliding)(pr)
+	var key [8]byte
+	for i := uint64(0); i < shiftEnd; i++ {
+		input, isNull, err := e.evalKey(sctx, getRow(lastEnd+i), key[:])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if isNew, _ := p.counter.add(key[:]); isNew {
+			p.sum += input
+			p.count++
+		}
+	}
+	for i := uint64(0); i < shiftStart; i++ {
+		input, isNull, err := e.evalKey(sctx, getRow(lastStart+i), key[:])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if p.counter.remove(key[:]) {
+			p.sum -= input
+			p.count--
+		}
+	}
+	return nil
+}
+
+type sum4DistinctFloat64Sliding struct {
+	baseSlidingDistinctFloat64
+}
+
+var _ SlidingWindowAggFunc = &sum4DistinctFloat64Sliding{}
+
+func (e *sum4DistinctFloat64Sliding) AppendFinalResult2Chunk(_ AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4DistinctFloat64Sliding)(pr)
+	if p.count == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendFloat64(e.ordinal, p.sum)
+	return nil
+}
+
+type avg4DistinctFloat64Sliding struct {
+	baseSlidingDistinctFloat64
+}
+
+var _ SlidingWindowAggFunc = &avg4DistinctFloat64Sliding{}
+
+func (e *avg4DistinctFloat64Sliding) AppendFinalResult2Chunk(ctx AggFuncUpdateContext, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4DistinctFloat64Sliding)(pr)
+	avg := baseAvgFloat64{e.baseAggFunc}
+	return avg.AppendFinalResult2Chunk(ctx, PartialResult(&p.partialResult4AvgFloat64), chk)
+}
@@ -209,3 +209,54 @@ func TestWindowFunctions(t *testing.T) {
 		testWindowFunc(t, test)
 	}
 }
+
+func TestSlidingDistinctWindowFunctions(t *testing.T) {
+	ctx := mock.NewContext()
+	// The input is [1, 1, 2, NULL, 2, 3], and the frame of width 3 slides
+	// from [0, 3) to [3, 6) one row at a time.
+	ft := types.NewFieldType(mysql.TypeLonglong)
+	srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, 6)
+	for _, v := range []any{1, 1, 2, nil, 2, 3} {
+		d := types.NewDatum(v)
+		srcChk.AppendDatum(0, &d)
+	}
+	getRow := func(i uint64) chunk.Row {
+		return srcChk.GetRow(int(i))
+	}
+	tests := []struct {
+		funcName string
+		results  []any
+	}{
+		{ast.AggFuncCount, []any{2, 2, 1, 2}},
+		{ast.AggFuncSum, []any{types.NewDecFromInt(3), types.NewDecFromInt(3), types.NewDecFromInt(2), types.NewDecFromInt(5)}},
+		{ast.AggFuncAvg, []any{types.NewDecFromFloatForTest(1.5), types.NewDecFromFloatForTest(1.5), types.NewDecFromInt(2), types.NewDecFromFloatForTest(2.5)}},
+	}
+	for _, test := range tests {
+		args := []expression.Expression{&expression.Column{RetType: ft, Index: 0}}
+		desc, err := aggregation.NewAggFuncDesc(ctx, test.funcName, args, true)
+		require.NoError(t, err)
+		desc.WrapCastForAggArgs(ctx)
+		finalFunc := aggfuncs.BuildWindowFunctions(ctx, desc, 0, nil)
+		slidingFunc, ok := finalFunc.(aggfuncs.SlidingWindowAggFunc)
+		require.True(t, ok)
+		finalPr, _ := finalFunc.AllocPartialResult()
+		resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+
+		_, err = finalFunc.UpdatePartialResult(ctx, []chunk.Row{getRow(0), getRow(1), getRow(2)}, finalPr)
+		require.NoError(t, err)
+		for i, expected := range test.results {
+			if i > 0 {
+				err = slidingFunc.Slide(ctx, getRow, uint64(i-1), uint64(i+2), 1, 1, finalPr)
+				require.NoError(t, err)
+			}
+			err = finalFunc.AppendFinalResult2Chunk(ctx, finalPr, resultChk)
+			require.NoError(t, err)
+			dt := resultChk.GetRow(0).GetDatum(0, desc.RetTp)
+			expectedDt := types.NewDatum(expected)
+			result, err := dt.Compare(ctx.GetSessionVars().StmtCtx.TypeCtx(), &expectedDt, collate.GetCollator(desc.RetTp.GetCollate()))
+			require.NoError(t, err)
+			require.Equal(t, 0, result, "%s at frame %d", test.funcName, i)
+			resultChk.Reset()
+		}
+	}
+}
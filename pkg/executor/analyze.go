@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/metrics"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/planner/core"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
@@ -46,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/sqlescape"
 	"github.com/pingcap/tidb/pkg/util/sqlexec"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
 	"github.com/pingcap/tipb/go-tipb"
 	"github.com/tiancaiamao/gp"
 	"go.uber.org/zap"
@@ -175,7 +177,63 @@ TASKLOOP:
 	if err != nil {
 		sessionVars.StmtCtx.AppendWarning(err)
 	}
-	return statsHandle.Update(infoSchema)
+	if err = statsHandle.Update(infoSchema); err != nil {
+		return err
+	}
+	e.trainVectorIndexes(tasks, statsHandle, infoSchema)
+	return nil
+}
+
+// trainVectorIndexes trains the vector indexes of the analyzed tables, which were added when there were too few rows
+// to train them. A failure is reported as a warning rather than failing the ANALYZE.
+func (e *AnalyzeExec) trainVectorIndexes(tasks []*analyzeTask, statsHandle *handle.Handle, is infoschema.InfoSchema) {
+	checked := make(map[int64]struct{}, len(tasks))
+	for _, task := range tasks {
+		tableID := getTableIDFromTask(task).TableID
+		if _, ok := checked[tableID]; ok {
+			continue
+		}
+		checked[tableID] = struct{}{}
+		tbl, ok := is.TableByID(tableID)
+		if !ok {
+			continue
+		}
+		tblInfo := tbl.Meta()
+		var rowCount int64
+		for _, idx := range tblInfo.Indices {
+			if idx.VectorInfo == nil || idx.State != model.StatePublic || len(idx.VectorInfo.Centroids) > 0 {
+				continue
+			}
+			if rowCount == 0 {
+				rowCount = analyzedRowCount(statsHandle, tblInfo)
+			}
+			if rowCount < vectorindex.MinTrainingRows {
+				break
+			}
+			db, ok := infoschema.SchemaByTable(is, tblInfo)
+			if !ok {
+				break
+			}
+			err := domain.GetDomain(e.Ctx()).DDL().TrainVectorIndex(e.Ctx(), db.Name, tblInfo.Name, idx.Name)
+			if err != nil {
+				e.Ctx().GetSessionVars().StmtCtx.AppendWarning(err)
+			}
+		}
+	}
+}
+
+// analyzedRowCount returns the row count of the table in the statistics, the counts of the partitions are summed up
+// since there may be no global statistics.
+func analyzedRowCount(statsHandle *handle.Handle, tblInfo *model.TableInfo) int64 {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return statsHandle.GetTableStats(tblInfo).RealtimeCount
+	}
+	var count int64
+	for _, def := range pi.Definitions {
+		count += statsHandle.GetPartitionStats(tblInfo, def.ID).RealtimeCount
+	}
+	return count
 }
 
 func (e *AnalyzeExec) waitFinish(ctx context.Context, g *errgroup.Group, resultsCh chan *statistics.AnalyzeResults) error {
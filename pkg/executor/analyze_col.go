@@ -59,6 +59,11 @@ type AnalyzeColumnsExec struct {
 	baseCount               int64
 	baseModifyCnt           int64
 
+	// extStatsItems are the extended stats to build in analyze version 2. extStatsGroups are the names of
+	// the cardinality stats whose column groups are appended to the analyze request, in the same order.
+	extStatsItems  map[string]*statistics.ExtendedStatsItem
+	extStatsGroups []string
+
 	memTracker *memory.Tracker
 }
 
@@ -278,7 +283,7 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 		fms = append(fms, collectors[i].FMSketch)
 	}
 	if needExtStats {
-		extStats, err = statistics.BuildExtendedStats(e.ctx, e.TableID.GetStatisticsID(), e.colsInfo, collectors)
+		extStats, err = statistics.BuildExtendedStats(e.ctx, e.TableID.TableID, e.colsInfo, collectors)
 		if err != nil {
 			return nil, nil, nil, nil, nil, err
 		}
@@ -108,6 +108,11 @@ func (e *AnalyzeColumnsExecV2) analyzeColumnsPushDownV2(gp *gp.Pool) *statistics
 	}
 
 	collExtStats := e.ctx.GetSessionVars().EnableExtendedStats
+	if collExtStats {
+		if err := e.prepareExtendedStats(); err != nil {
+			return &statistics.AnalyzeResults{Err: err, Job: e.job}
+		}
+	}
 	specialIndexes := make([]*model.IndexInfo, 0, len(e.indexes))
 	specialIndexesOffsets := make([]int, 0, len(e.indexes))
 	for i, idx := range e.indexes {
@@ -468,7 +473,11 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 
 	count = rootRowCollector.Base().Count
 	if needExtStats {
-		extStats, err = statistics.BuildExtendedStats(e.ctx, e.TableID.GetStatisticsID(), e.colsInfo, sampleCollectors)
+		groupFMSketches := make(map[string]*statistics.FMSketch, len(e.extStatsGroups))
+		for i, name := range e.extStatsGroups {
+			groupFMSketches[name] = rootRowCollector.Base().FMSketches[colLen+len(e.indexes)+i]
+		}
+		extStats, err = statistics.BuildExtendedStatsFromRowSamples(e.ctx, e.extStatsItems, e.colsInfo, sampleCollectors, rootRowCollector, groupFMSketches)
 		if err != nil {
 			return 0, nil, nil, nil, nil, err
 		}
@@ -477,6 +486,44 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 	return
 }
 
+// prepareExtendedStats loads the extended stats registered on the table, and appends the column groups of
+// the cardinality stats to the analyze request so that their FM sketches are collected on all the rows.
+// The partitions share the extended stats registered on the partitioned table.
+func (e *AnalyzeColumnsExecV2) prepareExtendedStats() error {
+	items, err := statistics.LoadExtendedStatsItems(e.ctx, e.tableID.TableID)
+	if err != nil {
+		return err
+	}
+	e.extStatsItems = items
+	// The request may be sent again when retrying the analyze, so the column groups appended last time are removed.
+	e.analyzePB.ColReq.ColumnGroups = e.analyzePB.ColReq.ColumnGroups[:len(e.indexes)]
+	e.extStatsGroups = e.extStatsGroups[:0]
+	names := make([]string, 0, len(items))
+	for name, item := range items {
+		if item.Tp == ast.StatsTypeCardinality {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		colGroup := &tipb.AnalyzeColumnGroup{ColumnOffsets: make([]int64, 0, len(items[name].ColIDs))}
+		for _, id := range items[name].ColIDs {
+			offset := slices.IndexFunc(e.colsInfo, func(col *model.ColumnInfo) bool { return col.ID == id })
+			if offset < 0 {
+				colGroup = nil
+				break
+			}
+			colGroup.ColumnOffsets = append(colGroup.ColumnOffsets, int64(offset))
+		}
+		if colGroup == nil {
+			continue
+		}
+		e.analyzePB.ColReq.ColumnGroups = append(e.analyzePB.ColReq.ColumnGroups, colGroup)
+		e.extStatsGroups = append(e.extStatsGroups, name)
+	}
+	return nil
+}
+
 // handleNDVForSpecialIndexes deals with the logic to analyze the index containing the virtual column when the mode is full sampling.
 func (e *AnalyzeColumnsExecV2) handleNDVForSpecialIndexes(indexInfos []*model.IndexInfo, totalResultCh chan analyzeIndexNDVTotalResult, statsConcurrncy int) {
 	defer func() {
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/globalstats"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"go.uber.org/zap"
@@ -117,6 +118,13 @@ func (e *AnalyzeExec) handleGlobalStats(ctx context.Context, globalStatsMap glob
 							zap.Int64("histID", hg.ID), zap.Error(err), zap.Int64("tableID", tableID))
 					}
 				}
+				if err == nil && info.isIndex == 0 && e.Ctx().GetSessionVars().EnableExtendedStats {
+					err = e.mergeGlobalExtendedStats(globalStatsID.tableID, globalStats)
+					if err != nil {
+						logutil.Logger(ctx).Error("merge global-level extended stats failed", zap.String("info", job.JobInfo),
+							zap.Error(err), zap.Int64("tableID", tableID))
+					}
+				}
 				return err
 			}()
 			FinishAnalyzeMergeJob(e.Ctx(), job, mergeStatsErr)
@@ -133,6 +141,23 @@ func (e *AnalyzeExec) handleGlobalStats(ctx context.Context, globalStatsMap glob
 	return nil
 }
 
+// mergeGlobalExtendedStats merges the partition-level extended stats and saves the global-level ones.
+func (e *AnalyzeExec) mergeGlobalExtendedStats(tableID int64, globalStats *globalstats.GlobalStats) error {
+	tbl, ok := e.Ctx().GetInfoSchema().(infoschema.InfoSchema).TableByID(tableID)
+	if !ok {
+		return nil
+	}
+	pt, ok := tbl.(table.PartitionedTable)
+	if !ok {
+		return nil
+	}
+	extStats, err := globalstats.MergePartitionExtendedStats(e.Ctx(), tbl.Meta(), pt.GetPartitionColumnIDs(), globalStats)
+	if err != nil {
+		return err
+	}
+	return domain.GetDomain(e.Ctx()).StatsHandle().SaveExtendedStatsToStorage(tableID, extStats, false)
+}
+
 func (e *AnalyzeExec) newAnalyzeHandleGlobalStatsJob(key globalStatsKey) *statistics.AnalyzeJob {
 	dom := domain.GetDomain(e.Ctx())
 	is := dom.InfoSchema()
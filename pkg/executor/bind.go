@@ -24,9 +24,22 @@ import (
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 )
 
+func init() {
+	// The baseline evolution needs the plan digests of the verified plans, but the bindinfo package
+	// cannot import the executor package because of the dependency cycle.
+	bindinfo.GetLastPlanDigest = func(sctx sessionctx.Context) string {
+		_, planDigest := GetPlanDigest(sctx.GetSessionVars().StmtCtx)
+		if planDigest == nil {
+			return ""
+		}
+		return planDigest.String()
+	}
+}
+
 // SQLBindExec represents a bind executor.
 type SQLBindExec struct {
 	exec.BaseExecutor
@@ -60,7 +73,7 @@ func (e *SQLBindExec) Next(_ context.Context, req *chunk.Chunk) error {
 	case plannercore.OpCaptureBindings:
 		e.captureBindings()
 	case plannercore.OpEvolveBindings:
-		return nil // not support yet
+		return e.evolveBindings()
 	case plannercore.OpReloadBindings:
 		return e.reloadBindings()
 	case plannercore.OpSetBindingStatus:
@@ -155,6 +168,12 @@ func (e *SQLBindExec) captureBindings() {
 	domain.GetDomain(e.Ctx()).BindHandle().CaptureBaselines()
 }
 
+func (e *SQLBindExec) evolveBindings() error {
+	bindHandle := domain.GetDomain(e.Ctx()).BindHandle()
+	bindHandle.RecordPlanBaselines()
+	return bindHandle.EvolvePlanBaselines(true)
+}
+
 func (e *SQLBindExec) reloadBindings() error {
 	return domain.GetDomain(e.Ctx()).BindHandle().LoadFromStorageToCache(true)
 }
@@ -104,6 +104,8 @@ type executorBuilder struct {
 	inDeleteStmt     bool
 	inInsertStmt     bool
 	inSelectLockStmt bool
+	// inFKCascade is set when building the executors of the foreign key cascades.
+	inFKCascade bool
 
 	// forDataReaderBuilder indicates whether the builder is used by a dataReaderBuilder.
 	// When forDataReader is true, the builder should use the dataReaderTS as the executor read ts. This is because
@@ -853,6 +855,9 @@ func (b *executorBuilder) buildShow(v *plannercore.PhysicalShow) exec.Executor {
 		CountWarningsOrErrors: v.CountWarningsOrErrors,
 		DBName:                model.NewCIStr(v.DBName),
 		Table:                 v.Table,
+		Procedure:             v.Procedure,
+		Trigger:               v.Trigger,
+		Event:                 v.Event,
 		Partition:             v.Partition,
 		Column:                v.Column,
 		IndexName:             v.IndexName,
@@ -896,6 +901,11 @@ func (b *executorBuilder) buildSimple(v *plannercore.Simple) exec.Executor {
 			BaseExecutor:         exec.NewBaseExecutor(b.ctx, v.Schema(), 0),
 			QueryWatchOptionList: s.QueryWatchOptionList,
 		}
+	case *ast.XAStmt:
+		return &XAExec{
+			BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+			stmt:         s,
+		}
 	case *ast.ImportIntoActionStmt:
 		return &ImportIntoActionExec{
 			BaseExecutor: exec.NewBaseExecutor(b.ctx, nil, 0),
@@ -974,6 +984,10 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) exec.Executor {
 	if b.err != nil {
 		return nil
 	}
+	ivs.triggers, b.err = b.buildTriggerExec(ivs.Table)
+	if b.err != nil {
+		return nil
+	}
 
 	if v.IsReplace {
 		return b.buildReplace(ivs)
@@ -1071,14 +1085,16 @@ func (b *executorBuilder) buildUnlockStats(v *plannercore.UnlockStats) exec.Exec
 
 func (b *executorBuilder) buildIndexAdvise(v *plannercore.IndexAdvise) exec.Executor {
 	e := &IndexAdviseExec{
-		BaseExecutor: exec.NewBaseExecutor(b.ctx, nil, v.ID()),
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		IsLocal:      v.IsLocal,
 		indexAdviseInfo: &IndexAdviseInfo{
-			Path:           v.Path,
-			MaxMinutes:     v.MaxMinutes,
-			MaxIndexNum:    v.MaxIndexNum,
-			LineFieldsInfo: v.LineFieldsInfo,
-			Ctx:            b.ctx,
+			FromStmtSummary: v.FromStmtSummary,
+			Limit:           v.Limit,
+			Path:            v.Path,
+			MaxMinutes:      v.MaxMinutes,
+			MaxIndexNum:     v.MaxIndexNum,
+			LineFieldsInfo:  v.LineFieldsInfo,
+			Ctx:             b.ctx,
 		},
 	}
 	return e
@@ -1224,10 +1240,16 @@ func (b *executorBuilder) buildSelectInto(v *plannercore.SelectInto) exec.Execut
 	if b.err != nil {
 		return nil
 	}
+	colNames := make([]string, 0, len(v.TargetNames))
+	for _, name := range v.TargetNames {
+		colNames = append(colNames, name.ColName.O)
+	}
 	return &SelectIntoExec{
 		BaseExecutor:   exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID(), child),
 		intoOpt:        v.IntoOpt,
 		LineFieldsInfo: v.LineFieldsInfo,
+		options:        v.Options,
+		colNames:       colNames,
 	}
 }
 
@@ -1963,6 +1985,9 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 			strings.ToLower(infoschema.TableStatistics),
 			strings.ToLower(infoschema.TableTiDBIndexes),
 			strings.ToLower(infoschema.TableViews),
+			strings.ToLower(infoschema.TableRoutines),
+			strings.ToLower(infoschema.TableTriggers),
+			strings.ToLower(infoschema.TableEvents),
 			strings.ToLower(infoschema.TableTables),
 			strings.ToLower(infoschema.TableReferConst),
 			strings.ToLower(infoschema.TableSequences),
@@ -2005,7 +2030,9 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 			strings.ToLower(infoschema.TableTiDBCheckConstraints),
 			strings.ToLower(infoschema.TableKeywords),
 			strings.ToLower(infoschema.TableTiDBIndexUsage),
-			strings.ToLower(infoschema.ClusterTableTiDBIndexUsage):
+			strings.ToLower(infoschema.ClusterTableTiDBIndexUsage),
+			strings.ToLower(infoschema.TableTiDBIndexAdvisorResults),
+			strings.ToLower(infoschema.TableTiDBInstancePlanCache):
 			memTracker := memory.NewTracker(v.ID(), -1)
 			memTracker.AttachTo(b.ctx.GetSessionVars().StmtCtx.MemTracker)
 			return &MemTableReaderExec{
@@ -2396,6 +2423,10 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) exec.Executor {
 	if b.err != nil {
 		return nil
 	}
+	updateExec.triggers, b.err = b.buildTblID2TriggerExecs(tblID2table)
+	if b.err != nil {
+		return nil
+	}
 	return updateExec
 }
 
@@ -2448,6 +2479,10 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) exec.Executor {
 	if b.err != nil {
 		return nil
 	}
+	deleteExec.triggers, b.err = b.buildTblID2TriggerExecs(tblID2table)
+	if b.err != nil {
+		return nil
+	}
 	return deleteExec
 }
 
@@ -3447,6 +3482,7 @@ func getPartitionKeyColOffsets(keyColIDs []int64, pt table.PartitionedTable) []i
 
 	t, ok := pt.(interface {
 		PartitionExpr() *tables.PartitionExpr
+		SubPartitionExpr() *tables.PartitionExpr
 	})
 	if !ok {
 		return nil
@@ -3455,12 +3491,16 @@ func getPartitionKeyColOffsets(keyColIDs []int64, pt table.PartitionedTable) []i
 	if pe == nil {
 		return nil
 	}
+	partColOffsets := pe.ColumnOffset
+	if spe := t.SubPartitionExpr(); spe != nil {
+		partColOffsets = append(slices.Clip(partColOffsets), spe.ColumnOffset...)
+	}
 
 	offsetMap := make(map[int]struct{})
 	for _, offset := range keyColOffsets {
 		offsetMap[offset] = struct{}{}
 	}
-	for _, offset := range pe.ColumnOffset {
+	for _, offset := range partColOffsets {
 		if _, ok := offsetMap[offset]; !ok {
 			return nil
 		}
@@ -4661,7 +4701,7 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) exec.Execut
 	resultColIdx := v.Schema().Len() - len(v.WindowFuncDescs)
 	exprCtx := b.ctx.GetExprCtx()
 	for _, desc := range v.WindowFuncDescs {
-		aggDesc, err := aggregation.NewAggFuncDescForWindowFunc(exprCtx, desc, false)
+		aggDesc, err := aggregation.NewAggFuncDescForWindowFunc(exprCtx, desc, desc.HasDistinct)
 		if err != nil {
 			b.err = err
 			return nil
@@ -4695,7 +4735,9 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) exec.Execut
 		} else {
 			exec.start = v.Frame.Start
 			exec.end = v.Frame.End
-			if v.Frame.Type == ast.Ranges {
+			if v.Frame.Type == ast.Groups {
+				exec.peerComparer = newPeerComparer(orderByCols)
+			} else if v.Frame.Type == ast.Ranges {
 				cmpResult := int64(-1)
 				if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
 					cmpResult = 1
@@ -4721,13 +4763,17 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) exec.Execut
 			windowFuncs:    windowFuncs,
 			partialResults: partialResults,
 		}
-	} else if v.Frame.Type == ast.Rows {
-		processor = &rowFrameWindowProcessor{
+	} else if v.Frame.Type == ast.Rows || v.Frame.Type == ast.Groups {
+		tmpProcessor := &rowFrameWindowProcessor{
 			windowFuncs:    windowFuncs,
 			partialResults: partialResults,
 			start:          v.Frame.Start,
 			end:            v.Frame.End,
 		}
+		if v.Frame.Type == ast.Groups {
+			tmpProcessor.peerComparer = newPeerComparer(orderByCols)
+		}
+		processor = tmpProcessor
 	} else {
 		cmpResult := int64(-1)
 		if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
@@ -5141,6 +5187,10 @@ func (b *executorBuilder) buildTableSample(v *plannercore.PhysicalTableSample) *
 			b.err = errors.New("TABLESAMPLE clause can not be applied to local temporary tables")
 			return nil
 		}
+		if tblInfo.TempTablePreserveRows {
+			b.err = errors.New("TABLESAMPLE clause can not be applied to global temporary tables with ON COMMIT PRESERVE ROWS")
+			return nil
+		}
 		e.sampler = &emptySampler{}
 	} else if v.TableSampleInfo.AstNode.SampleMethod == ast.SampleMethodTypeTiDBRegion {
 		e.sampler = newTableRegionSampler(
@@ -86,6 +86,21 @@ func (e *DDLExec) getLocalTemporaryTable(schema model.CIStr, table model.CIStr)
 	return tbl, true
 }
 
+// getPreserveRowsTemporaryTable returns the global temporary table with `ON COMMIT PRESERVE ROWS`, whose rows are kept
+// in the sessions, so the DDLs changing the layout of the rows are not supported.
+func (e *DDLExec) getPreserveRowsTemporaryTable(schema model.CIStr, table model.CIStr) (table.Table, bool) {
+	tbl, err := e.Ctx().GetInfoSchema().(infoschema.InfoSchema).TableByName(schema, table)
+	if err != nil {
+		return nil, false
+	}
+
+	if tbl.Meta().TempTableType != model.TempTableGlobal || !tbl.Meta().TempTablePreserveRows {
+		return nil, false
+	}
+
+	return tbl, true
+}
+
 // Next implements the Executor Next interface.
 func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 	if e.done {
@@ -160,10 +175,26 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 		err = e.executeCreateTable(x)
 	case *ast.CreateViewStmt:
 		err = e.executeCreateView(ctx, x)
+	case *ast.CreateTriggerStmt:
+		err = e.executeCreateTrigger(x)
+	case *ast.DropTriggerStmt:
+		err = e.executeDropTrigger(x)
+	case *ast.CreateEventStmt:
+		err = e.executeCreateEvent(ctx, x)
+	case *ast.AlterEventStmt:
+		err = e.executeAlterEvent(ctx, x)
+	case *ast.DropEventStmt:
+		err = e.executeDropEvent(ctx, x)
+	case *ast.CreateMaterializedViewStmt:
+		err = e.executeCreateMaterializedView(ctx, x)
+	case *ast.DropMaterializedViewStmt:
+		err = e.executeDropMaterializedView(ctx, x)
+	case *ast.RefreshMaterializedViewStmt:
+		err = e.executeRefreshMaterializedView(ctx, x)
 	case *ast.DropIndexStmt:
 		err = e.executeDropIndex(x)
 	case *ast.DropDatabaseStmt:
-		err = e.executeDropDatabase(x)
+		err = e.executeDropDatabase(ctx, x)
 	case *ast.DropTableStmt:
 		if x.IsView {
 			err = e.executeDropView(x)
@@ -241,6 +272,9 @@ func (e *DDLExec) executeTruncateTable(s *ast.TruncateTableStmt) error {
 	if _, exist := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); exist {
 		return e.tempTableDDL.TruncateLocalTemporaryTable(s.Table.Schema, s.Table.Name)
 	}
+	if tbl, exist := e.getPreserveRowsTemporaryTable(s.Table.Schema, s.Table.Name); exist {
+		return e.tempTableDDL.TruncateGlobalTemporaryTableData(tbl.Meta())
+	}
 	err := domain.GetDomain(e.Ctx()).DDL().TruncateTable(e.Ctx(), ident)
 	return err
 }
@@ -312,15 +346,29 @@ func (e *DDLExec) executeCreateView(ctx context.Context, s *ast.CreateViewStmt)
 	return domain.GetDomain(e.Ctx()).DDL().CreateView(e.Ctx(), s)
 }
 
+func (e *DDLExec) executeCreateTrigger(s *ast.CreateTriggerStmt) error {
+	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return dbterror.ErrTrgOnViewOrTempTable.GenWithStackByArgs(s.Table.Name.O)
+	}
+	return domain.GetDomain(e.Ctx()).DDL().CreateTrigger(e.Ctx(), s)
+}
+
+func (e *DDLExec) executeDropTrigger(s *ast.DropTriggerStmt) error {
+	return domain.GetDomain(e.Ctx()).DDL().DropTrigger(e.Ctx(), s)
+}
+
 func (e *DDLExec) executeCreateIndex(s *ast.CreateIndexStmt) error {
 	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
 		return dbterror.ErrUnsupportedLocalTempTableDDL.GenWithStackByArgs("CREATE INDEX")
 	}
+	if _, ok := e.getPreserveRowsTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return dbterror.ErrUnsupportedPreserveRowsTempTableDDL.GenWithStackByArgs("CREATE INDEX")
+	}
 
 	return domain.GetDomain(e.Ctx()).DDL().CreateIndex(e.Ctx(), s)
 }
 
-func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
+func (e *DDLExec) executeDropDatabase(ctx context.Context, s *ast.DropDatabaseStmt) error {
 	dbName := s.Name
 
 	// Protect important system table from been dropped by a mistake.
@@ -342,11 +390,34 @@ func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 			return err
 		}
 	}
+	if err == nil {
+		err = dropStoredProcedures(ctx, e.Ctx(), dbName.L)
+	}
+	if err == nil {
+		err = dropSchemaEvents(ctx, e.Ctx(), dbName.L)
+	}
 	return err
 }
 
 func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
-	return domain.GetDomain(e.Ctx()).DDL().DropTable(e.Ctx(), s)
+	var preserveRowsTempTables []*model.TableInfo
+	for _, tn := range s.Tables {
+		if tbl, ok := e.getPreserveRowsTemporaryTable(tn.Schema, tn.Name); ok {
+			preserveRowsTempTables = append(preserveRowsTempTables, tbl.Meta())
+		}
+	}
+
+	if err := domain.GetDomain(e.Ctx()).DDL().DropTable(e.Ctx(), s); err != nil {
+		return err
+	}
+
+	// Release the rows kept in the current session. The ones in other sessions are released when the sessions end.
+	for _, tblInfo := range preserveRowsTempTables {
+		if err := e.tempTableDDL.TruncateGlobalTemporaryTableData(tblInfo); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (e *DDLExec) executeDropView(s *ast.DropTableStmt) error {
@@ -376,6 +447,9 @@ func (e *DDLExec) executeDropIndex(s *ast.DropIndexStmt) error {
 	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
 		return dbterror.ErrUnsupportedLocalTempTableDDL.GenWithStackByArgs("DROP INDEX")
 	}
+	if _, ok := e.getPreserveRowsTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return dbterror.ErrUnsupportedPreserveRowsTempTableDDL.GenWithStackByArgs("DROP INDEX")
+	}
 
 	return domain.GetDomain(e.Ctx()).DDL().DropIndex(e.Ctx(), s)
 }
@@ -384,6 +458,9 @@ func (e *DDLExec) executeAlterTable(ctx context.Context, s *ast.AlterTableStmt)
 	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
 		return dbterror.ErrUnsupportedLocalTempTableDDL.GenWithStackByArgs("ALTER TABLE")
 	}
+	if _, ok := e.getPreserveRowsTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return dbterror.ErrUnsupportedPreserveRowsTempTableDDL.GenWithStackByArgs("ALTER TABLE")
+	}
 
 	return domain.GetDomain(e.Ctx()).DDL().AlterTable(ctx, e.Ctx(), s)
 }
@@ -0,0 +1,426 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/eventscheduler"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/stringutil"
+	"github.com/pingcap/tidb/pkg/util/timeutil"
+)
+
+func getEventScheduler(sctx sessionctx.Context) (*eventscheduler.Scheduler, error) {
+	scheduler := domain.GetDomain(sctx).EventScheduler()
+	if scheduler == nil {
+		return nil, errors.New("event scheduler is not started")
+	}
+	return scheduler, nil
+}
+
+// newEventInfo creates an event with the environment of the current session.
+func newEventInfo(sctx sessionctx.Context, is infoschema.InfoSchema, name *ast.TableName) (*eventscheduler.EventInfo, error) {
+	dbInfo, ok := is.SchemaByName(name.Schema)
+	if !ok {
+		return nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(name.Schema.O)
+	}
+	sessVars := sctx.GetSessionVars()
+	now := time.Now().Truncate(time.Second)
+	info := &eventscheduler.EventInfo{
+		Schema:      dbInfo.Name.O,
+		Name:        name.Name.O,
+		DBCollation: dbCollation(dbInfo),
+		Created:     now,
+		LastAltered: now,
+	}
+	info.SQLMode, _ = sessVars.GetSystemVar(variable.SQLModeVar)
+	info.TimeZone, _ = sessVars.GetSystemVar(variable.TimeZone)
+	info.Charset, _ = sessVars.GetSystemVar(variable.CharacterSetClient)
+	info.Collation, _ = sessVars.GetSystemVar(variable.CollationConnection)
+	return info, nil
+}
+
+// setEventSchedule evaluates the schedule of an event.
+func setEventSchedule(sctx sessionctx.Context, info *eventscheduler.EventInfo, schedule *ast.EventSchedule) (err error) {
+	info.ExecuteAt, info.IntervalValue, info.IntervalField = time.Time{}, 0, ast.TimeUnitInvalid
+	info.Starts, info.Ends = time.Time{}, time.Time{}
+	if schedule.At != nil {
+		info.ExecuteAt, err = evalEventTime(sctx, schedule.At)
+		return err
+	}
+	d, err := expression.EvalSimpleAst(sctx.GetExprCtx(), schedule.Interval)
+	if err != nil {
+		return err
+	}
+	if d.IsNull() {
+		return dbterror.ErrEventIntervalNotPositiveOrTooBig
+	}
+	if info.IntervalValue, err = d.ToInt64(sctx.GetSessionVars().StmtCtx.TypeCtx()); err != nil {
+		return dbterror.ErrEventIntervalNotPositiveOrTooBig
+	}
+	info.IntervalField = schedule.Unit
+	info.Starts = time.Now().Truncate(time.Second)
+	if schedule.Starts != nil {
+		if info.Starts, err = evalEventTime(sctx, schedule.Starts); err != nil {
+			return err
+		}
+	}
+	if schedule.Ends != nil {
+		if info.Ends, err = evalEventTime(sctx, schedule.Ends); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func evalEventTime(sctx sessionctx.Context, expr ast.ExprNode) (time.Time, error) {
+	d, err := expression.EvalSimpleAst(sctx.GetExprCtx(), expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if d.IsNull() {
+		return time.Time{}, types.ErrWrongValue.GenWithStackByArgs(types.DateTimeStr, "NULL")
+	}
+	d, err = d.ConvertTo(sctx.GetSessionVars().StmtCtx.TypeCtx(), types.NewFieldType(mysql.TypeDatetime))
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := d.GetMysqlTime().GoTime(sctx.GetSessionVars().Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Truncate(time.Second), nil
+}
+
+// eventInThePast returns whether the event won't be executed any more since its execution time or ENDS has passed.
+func eventInThePast(info *eventscheduler.EventInfo) bool {
+	now := time.Now().Truncate(time.Second)
+	if info.IsOneTime() {
+		return info.ExecuteAt.Before(now)
+	}
+	return !info.Ends.IsZero() && info.Ends.Before(now)
+}
+
+func (e *DDLExec) executeCreateEvent(ctx context.Context, s *ast.CreateEventStmt) error {
+	scheduler, err := getEventScheduler(e.Ctx())
+	if err != nil {
+		return err
+	}
+	info, err := newEventInfo(e.Ctx(), e.is, s.EventName)
+	if err != nil {
+		return err
+	}
+	info.Definer = s.Definer
+	info.Body = s.Body.Text()
+	info.Preserve = s.Completion == ast.EventCompletionPreserve
+	info.Comment = s.Comment
+	if err = setEventSchedule(e.Ctx(), info, s.Schedule); err != nil {
+		return err
+	}
+	if err = info.Validate(); err != nil {
+		return err
+	}
+
+	stmtCtx := e.Ctx().GetSessionVars().StmtCtx
+	enable := s.Status != ast.EventStatusDisable
+	if eventInThePast(info) {
+		if !info.Preserve {
+			stmtCtx.AppendNote(dbterror.ErrEventCannotCreateInThePast)
+			return nil
+		}
+		enable = false
+		stmtCtx.AppendNote(dbterror.ErrEventExecTimeInThePast)
+	}
+	err = scheduler.CreateEvent(ctx, info, enable)
+	if s.IfNotExists && dbterror.ErrEventAlreadyExists.Equal(err) {
+		stmtCtx.AppendNote(err)
+		return nil
+	}
+	return err
+}
+
+func (e *DDLExec) executeAlterEvent(ctx context.Context, s *ast.AlterEventStmt) error {
+	scheduler, err := getEventScheduler(e.Ctx())
+	if err != nil {
+		return err
+	}
+	event, err := scheduler.GetEvent(ctx, s.EventName.Schema.L, s.EventName.Name.L)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return dbterror.ErrEventDoesNotExist.GenWithStackByArgs(s.EventName.Name.O)
+	}
+
+	name := s.EventName
+	if s.RenameTo != nil {
+		if s.RenameTo.Schema.L == s.EventName.Schema.L && s.RenameTo.Name.L == s.EventName.Name.L {
+			return dbterror.ErrEventSameName
+		}
+		name = s.RenameTo
+	}
+	// The environment of the event is replaced with the current session's, like MySQL does.
+	info, err := newEventInfo(e.Ctx(), e.is, name)
+	if err != nil {
+		return err
+	}
+	info.Definer = s.Definer
+	info.Body = event.Body
+	if s.Body != nil {
+		info.Body = s.Body.Text()
+	}
+	info.ExecuteAt, info.IntervalValue, info.IntervalField = event.ExecuteAt, event.IntervalValue, event.IntervalField
+	info.Starts, info.Ends = event.Starts, event.Ends
+	if s.Schedule != nil {
+		if err = setEventSchedule(e.Ctx(), info, s.Schedule); err != nil {
+			return err
+		}
+	}
+	info.Preserve = event.Preserve
+	if s.Completion != ast.EventCompletionUnspecified {
+		info.Preserve = s.Completion == ast.EventCompletionPreserve
+	}
+	info.Comment = event.Comment
+	if s.Comment != nil {
+		info.Comment = *s.Comment
+	}
+	info.Created = event.Created
+	if err = info.Validate(); err != nil {
+		return err
+	}
+
+	enable := event.Enabled
+	if s.Status != ast.EventStatusUnspecified {
+		enable = s.Status == ast.EventStatusEnable
+	}
+	if s.Schedule != nil && eventInThePast(info) {
+		if !info.Preserve {
+			return dbterror.ErrEventCannotAlterInThePast
+		}
+		enable = false
+		e.Ctx().GetSessionVars().StmtCtx.AppendNote(dbterror.ErrEventExecTimeInThePast)
+	}
+	return scheduler.AlterEvent(ctx, event, info, enable, s.Schedule != nil)
+}
+
+func (e *DDLExec) executeDropEvent(ctx context.Context, s *ast.DropEventStmt) error {
+	scheduler, err := getEventScheduler(e.Ctx())
+	if err != nil {
+		return err
+	}
+	dropped, err := scheduler.DropEvent(ctx, s.EventName.Schema.L, s.EventName.Name.L)
+	if err != nil || dropped {
+		return err
+	}
+	err = dbterror.ErrEventDoesNotExist.GenWithStackByArgs(s.EventName.Name.O)
+	if s.IfExists {
+		e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+		return nil
+	}
+	return err
+}
+
+// dropSchemaEvents drops all the events of the schema. The events are kept in mysql.tidb_timers rather than the
+// schema, so they're dropped separately.
+func dropSchemaEvents(ctx context.Context, sctx sessionctx.Context, schema string) error {
+	scheduler := domain.GetDomain(sctx).EventScheduler()
+	if scheduler == nil {
+		return nil
+	}
+	return scheduler.DropSchemaEvents(ctx, schema)
+}
+
+// eventVisible checks whether the user has the EVENT privilege on the schema, which is required to see the events.
+func eventVisible(sctx sessionctx.Context, schema string) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	return checker == nil || checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, strings.ToLower(schema), "", "", mysql.EventPriv)
+}
+
+// eventTime converts a time of an event to a datetime in the time zone of the event, it returns nil for a zero time.
+func eventTime(sctx sessionctx.Context, event *eventscheduler.Event, t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	loc, err := timeutil.ParseTimeZone(event.TimeZone)
+	if err != nil {
+		loc = sctx.GetSessionVars().Location()
+	}
+	return types.NewTime(types.FromGoTime(t.In(loc)), mysql.TypeDatetime, 0)
+}
+
+func eventType(event *eventscheduler.Event) string {
+	if event.IsOneTime() {
+		return "ONE TIME"
+	}
+	return "RECURRING"
+}
+
+func eventStatus(event *eventscheduler.Event) string {
+	if event.Enabled {
+		return "ENABLED"
+	}
+	return "DISABLED"
+}
+
+func eventInterval(event *eventscheduler.Event) (value, field any) {
+	if event.IsOneTime() {
+		return nil, nil
+	}
+	return strconv.FormatInt(event.IntervalValue, 10), event.IntervalField.String()
+}
+
+func showCreateEvent(sctx sessionctx.Context, event *eventscheduler.Event) string {
+	sqlMode := sctx.GetSessionVars().SQLMode
+	timeStr := func(t time.Time) string {
+		return eventTime(sctx, event, t).(types.Time).String()
+	}
+	var buf strings.Builder
+	buf.WriteString("CREATE ")
+	if event.Definer != nil {
+		fmt.Fprintf(&buf, "DEFINER=%s@%s ", stringutil.Escape(event.Definer.Username, sqlMode), stringutil.Escape(event.Definer.Hostname, sqlMode))
+	}
+	fmt.Fprintf(&buf, "EVENT %s ON SCHEDULE ", stringutil.Escape(event.Name, sqlMode))
+	if event.IsOneTime() {
+		fmt.Fprintf(&buf, "AT '%s'", timeStr(event.ExecuteAt))
+	} else {
+		fmt.Fprintf(&buf, "EVERY %d %s STARTS '%s'", event.IntervalValue, event.IntervalField.String(), timeStr(event.Starts))
+		if !event.Ends.IsZero() {
+			fmt.Fprintf(&buf, " ENDS '%s'", timeStr(event.Ends))
+		}
+	}
+	if event.Preserve {
+		buf.WriteString(" ON COMPLETION PRESERVE")
+	} else {
+		buf.WriteString(" ON COMPLETION NOT PRESERVE")
+	}
+	if event.Enabled {
+		buf.WriteString(" ENABLE")
+	} else {
+		buf.WriteString(" DISABLE")
+	}
+	if event.Comment != "" {
+		fmt.Fprintf(&buf, " COMMENT '%s'", format.OutputFormat(event.Comment))
+	}
+	buf.WriteString(" DO ")
+	buf.WriteString(event.Body)
+	return buf.String()
+}
+
+func (e *ShowExec) fetchShowEvents(ctx context.Context) error {
+	scheduler, err := getEventScheduler(e.Ctx())
+	if err != nil {
+		return err
+	}
+	if _, ok := e.is.SchemaByName(e.DBName); !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(e.DBName.O)
+	}
+	events, err := scheduler.ListEvents(ctx, e.DBName.L)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		intervalValue, intervalField := eventInterval(event)
+		e.appendRow([]any{event.Schema, event.Name, event.TimeZone, event.Definer.String(), eventType(event),
+			eventTime(e.Ctx(), event, event.ExecuteAt), intervalValue, intervalField,
+			eventTime(e.Ctx(), event, event.Starts), eventTime(e.Ctx(), event, event.Ends), eventStatus(event), 0,
+			event.Charset, event.Collation, event.DBCollation})
+	}
+	return nil
+}
+
+func (e *ShowExec) fetchShowCreateEvent(ctx context.Context) error {
+	scheduler, err := getEventScheduler(e.Ctx())
+	if err != nil {
+		return err
+	}
+	name := e.Event
+	event, err := scheduler.GetEvent(ctx, name.Schema.L, name.Name.L)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return dbterror.ErrEventDoesNotExist.GenWithStackByArgs(name.Name.O)
+	}
+	e.appendRow([]any{event.Name, event.SQLMode, event.TimeZone, showCreateEvent(e.Ctx(), event), event.Charset,
+		event.Collation, event.DBCollation})
+	return nil
+}
+
+func (e *memtableRetriever) setDataForEvents(ctx context.Context, sctx sessionctx.Context) error {
+	scheduler, err := getEventScheduler(sctx)
+	if err != nil {
+		return err
+	}
+	events, err := scheduler.ListEvents(ctx, "")
+	if err != nil {
+		return err
+	}
+	rows := make([][]types.Datum, 0, len(events))
+	for _, event := range events {
+		if !eventVisible(sctx, event.Schema) {
+			continue
+		}
+		intervalValue, intervalField := eventInterval(event)
+		completion := "NOT PRESERVE"
+		if event.Preserve {
+			completion = "PRESERVE"
+		}
+		record := types.MakeDatums(
+			infoschema.CatalogVal,                   // EVENT_CATALOG
+			event.Schema,                            // EVENT_SCHEMA
+			event.Name,                              // EVENT_NAME
+			event.Definer.String(),                  // DEFINER
+			event.TimeZone,                          // TIME_ZONE
+			"SQL",                                   // EVENT_BODY
+			event.Body,                              // EVENT_DEFINITION
+			eventType(event),                        // EVENT_TYPE
+			eventTime(sctx, event, event.ExecuteAt), // EXECUTE_AT
+			intervalValue,                           // INTERVAL_VALUE
+			intervalField,                           // INTERVAL_FIELD
+			event.SQLMode,                           // SQL_MODE
+			eventTime(sctx, event, event.Starts),    // STARTS
+			eventTime(sctx, event, event.Ends),      // ENDS
+			eventStatus(event),                      // STATUS
+			completion,                              // ON_COMPLETION
+			eventTime(sctx, event, event.Created),   // CREATED
+			eventTime(sctx, event, event.LastAltered),  // LAST_ALTERED
+			eventTime(sctx, event, event.LastExecuted), // LAST_EXECUTED
+			event.Comment,     // EVENT_COMMENT
+			0,                 // ORIGINATOR
+			event.Charset,     // CHARACTER_SET_CLIENT
+			event.Collation,   // COLLATION_CONNECTION
+			event.DBCollation, // DATABASE_COLLATION
+		)
+		rows = append(rows, record)
+	}
+	e.rows = rows
+	return nil
+}
@@ -2067,6 +2067,13 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 			vars.DiskTracker.AttachTo(GlobalDiskUsageTracker)
 		}
 	}
+	if tempTableData := vars.TemporaryTableData; tempTableData != nil && variable.EnableTmpStorageOnOOM.Load() {
+		// The data marked by the previous statements is spilled here, because it isn't read by any statement now.
+		if err := tempTableData.Spill(); err != nil {
+			logutil.BgLogger().Warn("failed to spill the temporary table data in session", zap.Error(err))
+		}
+		vars.MemTracker.FallbackOldAndSetNewAction(tempTableData.ActionSpill())
+	}
 	if execStmt, ok := s.(*ast.ExecuteStmt); ok {
 		prepareStmt, err := plannercore.GetPreparedStmt(execStmt, vars)
 		if err != nil {
@@ -2414,8 +2421,6 @@ func (w *checkIndexWorker) initSessCtx(se sessionctx.Context) (restore func()) {
 func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.None)) {
 	defer w.e.wg.Done()
 	idxInfo := w.indexInfos[task.indexOffset]
-	bucketSize := int(CheckTableFastBucketSize.Load())
-
 	ctx := kv.WithInternalSourceType(w.e.contextCtx, kv.InternalTxnAdmin)
 
 	trySaveErr := func(err error) {
@@ -2433,6 +2438,40 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 		w.e.BaseExecutor.ReleaseSysSession(ctx, se)
 	}()
 
+	if w.e.Ctx().GetSessionVars().SnapshotTS != 0 {
+		se.GetSessionVars().SnapshotTS = w.e.Ctx().GetSessionVars().SnapshotTS
+		defer func() {
+			se.GetSessionVars().SnapshotTS = 0
+		}()
+	}
+	_, err = se.GetSQLExecutor().ExecuteInternal(ctx, "begin")
+	if err != nil {
+		trySaveErr(err)
+		return
+	}
+
+	if !idxInfo.Global {
+		if err := w.checkIndex(ctx, se, idxInfo, w.table, ""); err != nil {
+			trySaveErr(err)
+		}
+		return
+	}
+	// The handles are only unique in a partition, so the rows of a global index are compared partition by partition.
+	pt := w.table.(table.PartitionedTable)
+	for _, def := range w.table.Meta().GetPartitionInfo().Definitions {
+		partition := fmt.Sprintf(" partition(%s)", ColumnName(def.Name.O))
+		if err := w.checkIndex(ctx, se, idxInfo, pt.GetPartition(def.ID), partition); err != nil {
+			trySaveErr(err)
+			return
+		}
+	}
+}
+
+// checkIndex compares the rows read from the index with the ones read from the table, partition is the partition
+// selection of the table, and tbl is the table or the selected partition.
+func (w *checkIndexWorker) checkIndex(ctx context.Context, se sessionctx.Context, idxInfo *model.IndexInfo, tbl table.Table, partition string) error {
+	bucketSize := int(CheckTableFastBucketSize.Load())
+
 	var pkCols []string
 	var pkTypes []*types.FieldType
 	switch {
@@ -2495,21 +2534,11 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 	mod := 1
 	meetError := false
 
+	tblName := TableName(w.e.dbName, w.e.table.Meta().Name.String())
+	tblSource := tblName + partition
 	lookupCheckThreshold := int64(100)
 	checkOnce := false
 
-	if w.e.Ctx().GetSessionVars().SnapshotTS != 0 {
-		se.GetSessionVars().SnapshotTS = w.e.Ctx().GetSessionVars().SnapshotTS
-		defer func() {
-			se.GetSessionVars().SnapshotTS = 0
-		}()
-	}
-	_, err = se.GetSQLExecutor().ExecuteInternal(ctx, "begin")
-	if err != nil {
-		trySaveErr(err)
-		return
-	}
-
 	times := 0
 	const maxTimes = 10
 	for tableRowCntToCheck > lookupCheckThreshold || !checkOnce {
@@ -2525,16 +2554,15 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 		}
 		checkOnce = true
 
-		tblQuery := fmt.Sprintf("select /*+ read_from_storage(tikv[%s]) */ bit_xor(%s), %s, count(*) from %s use index() where %s = 0 group by %s", TableName(w.e.dbName, w.e.table.Meta().Name.String()), md5HandleAndIndexCol.String(), groupByKey, TableName(w.e.dbName, w.e.table.Meta().Name.String()), whereKey, groupByKey)
-		idxQuery := fmt.Sprintf("select bit_xor(%s), %s, count(*) from %s use index(`%s`) where %s = 0 group by %s", md5HandleAndIndexCol.String(), groupByKey, TableName(w.e.dbName, w.e.table.Meta().Name.String()), idxInfo.Name, whereKey, groupByKey)
+		tblQuery := fmt.Sprintf("select /*+ read_from_storage(tikv[%s]) */ bit_xor(%s), %s, count(*) from %s use index() where %s = 0 group by %s", tblName, md5HandleAndIndexCol.String(), groupByKey, tblSource, whereKey, groupByKey)
+		idxQuery := fmt.Sprintf("select bit_xor(%s), %s, count(*) from %s use index(`%s`) where %s = 0 group by %s", md5HandleAndIndexCol.String(), groupByKey, tblSource, idxInfo.Name, whereKey, groupByKey)
 
 		logutil.BgLogger().Info("fast check table by group", zap.String("table name", w.table.Meta().Name.String()), zap.String("index name", idxInfo.Name.String()), zap.Int("times", times), zap.Int("current offset", offset), zap.Int("current mod", mod), zap.String("table sql", tblQuery), zap.String("index sql", idxQuery))
 
 		// compute table side checksum.
 		tableChecksum, err := getCheckSum(w.e.contextCtx, se, tblQuery)
 		if err != nil {
-			trySaveErr(err)
-			return
+			return err
 		}
 		slices.SortFunc(tableChecksum, func(i, j groupByChecksum) int {
 			return cmp.Compare(i.bucket, j.bucket)
@@ -2543,8 +2571,7 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 		// compute index side checksum.
 		indexChecksum, err := getCheckSum(w.e.contextCtx, se, idxQuery)
 		if err != nil {
-			trySaveErr(err)
-			return
+			return err
 		}
 		slices.SortFunc(indexChecksum, func(i, j groupByChecksum) int {
 			return cmp.Compare(i.bucket, j.bucket)
@@ -2610,18 +2637,16 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 
 	if meetError {
 		groupByKey := fmt.Sprintf("((cast(%s as signed) - %d) %% %d)", md5Handle.String(), offset, mod)
-		indexSQL := fmt.Sprintf("select %s, %s, %s from %s use index(`%s`) where %s = 0 order by %s", handleColumnField, indexColumnField.String(), md5HandleAndIndexCol.String(), TableName(w.e.dbName, w.e.table.Meta().Name.String()), idxInfo.Name, groupByKey, handleColumnField)
-		tableSQL := fmt.Sprintf("select /*+ read_from_storage(tikv[%s]) */ %s, %s, %s from %s use index() where %s = 0 order by %s", TableName(w.e.dbName, w.e.table.Meta().Name.String()), handleColumnField, indexColumnField.String(), md5HandleAndIndexCol.String(), TableName(w.e.dbName, w.e.table.Meta().Name.String()), groupByKey, handleColumnField)
+		indexSQL := fmt.Sprintf("select %s, %s, %s from %s use index(`%s`) where %s = 0 order by %s", handleColumnField, indexColumnField.String(), md5HandleAndIndexCol.String(), tblSource, idxInfo.Name, groupByKey, handleColumnField)
+		tableSQL := fmt.Sprintf("select /*+ read_from_storage(tikv[%s]) */ %s, %s, %s from %s use index() where %s = 0 order by %s", tblName, handleColumnField, indexColumnField.String(), md5HandleAndIndexCol.String(), tblSource, groupByKey, handleColumnField)
 
 		idxRow, err := queryToRow(se, indexSQL)
 		if err != nil {
-			trySaveErr(err)
-			return
+			return err
 		}
 		tblRow, err := queryToRow(se, tableSQL)
 		if err != nil {
-			trySaveErr(err)
-			return
+			return err
 		}
 
 		errCtx := w.sctx.GetSessionVars().StmtCtx.ErrCtx()
@@ -2651,11 +2676,11 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 		ir := func() *consistency.Reporter {
 			return &consistency.Reporter{
 				HandleEncode: func(handle kv.Handle) kv.Key {
-					return tablecodec.EncodeRecordKey(w.table.RecordPrefix(), handle)
+					return tablecodec.EncodeRecordKey(tbl.RecordPrefix(), handle)
 				},
 				IndexEncode: func(idxRow *consistency.RecordData) kv.Key {
 					var idx table.Index
-					for _, v := range w.table.Indices() {
+					for _, v := range tbl.Indices() {
 						if strings.EqualFold(v.Meta().Name.String(), idxInfo.Name.O) {
 							idx = v
 							break
@@ -2693,13 +2718,11 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 			} else {
 				handle, err = getHandleFromRow(tblRow[i])
 				if err != nil {
-					trySaveErr(err)
-					return
+					return err
 				}
 				value, err := getValueFromRow(tblRow[i])
 				if err != nil {
-					trySaveErr(err)
-					return
+					return err
 				}
 				tableRecord = &consistency.RecordData{Handle: handle, Values: value}
 			}
@@ -2709,13 +2732,11 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 			} else {
 				indexHandle, err := getHandleFromRow(idxRow[i])
 				if err != nil {
-					trySaveErr(err)
-					return
+					return err
 				}
 				indexValue, err := getValueFromRow(idxRow[i])
 				if err != nil {
-					trySaveErr(err)
-					return
+					return err
 				}
 				indexRecord = &consistency.RecordData{Handle: indexHandle, Values: indexValue}
 			}
@@ -2741,8 +2762,7 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 				}
 			}
 			if err != nil {
-				trySaveErr(err)
-				return
+				return err
 			}
 			i++
 			if tableRecord != nil {
@@ -2752,6 +2772,7 @@ func (w *checkIndexWorker) HandleTask(task checkIndexTask, _ func(workerpool.Non
 			}
 		}
 	}
+	return nil
 }
 
 // Close implements the Worker interface.
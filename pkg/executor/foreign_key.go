@@ -711,7 +711,9 @@ func (fkc *FKCascadeExec) buildExecutor(ctx context.Context) (exec.Executor, err
 		return nil, err
 	}
 	fkc.plan.CascadePlans = append(fkc.plan.CascadePlans, p)
+	fkc.b.inFKCascade = true
 	e := fkc.b.build(p)
+	fkc.b.inFKCascade = false
 	return e, fkc.b.err
 }
 
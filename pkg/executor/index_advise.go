@@ -16,16 +16,26 @@ package executor
 
 import (
 	"context"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ngaut/pools"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/planner/indexadvisor"
+	"github.com/pingcap/tidb/pkg/privilege"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
 )
 
 // IndexAdviseExec represents a index advise executor.
@@ -34,10 +44,18 @@ type IndexAdviseExec struct {
 
 	IsLocal         bool
 	indexAdviseInfo *IndexAdviseInfo
+
+	// The fields below are used when the workload comes from the statements summary.
+	done            bool
+	recommendations []*indexadvisor.Recommendation
+	cursor          int
 }
 
 // Next implements the Executor Next interface.
-func (e *IndexAdviseExec) Next(context.Context, *chunk.Chunk) error {
+func (e *IndexAdviseExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if e.indexAdviseInfo.FromStmtSummary {
+		return e.nextFromStmtSummary(ctx, req)
+	}
 	if !e.IsLocal {
 		return errors.New("Index Advise: don't support load file without local field")
 	}
@@ -56,21 +74,59 @@ func (e *IndexAdviseExec) Next(context.Context, *chunk.Chunk) error {
 	return nil
 }
 
+func (e *IndexAdviseExec) nextFromStmtSummary(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.done {
+		e.done = true
+		ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+		limit := indexadvisor.DefMaxQueries
+		if e.indexAdviseInfo.Limit != ast.UnspecifiedSize {
+			limit = int(min(e.indexAdviseInfo.Limit, math.MaxInt32))
+		}
+		queries, err := indexadvisor.LoadQueriesFromStmtSummary(ctx, e.Ctx().GetRestrictedSQLExecutor(), limit)
+		if err != nil {
+			return err
+		}
+		e.recommendations, err = e.indexAdviseInfo.advise(ctx, queries)
+		if err != nil {
+			return err
+		}
+	}
+	for ; e.cursor < len(e.recommendations) && !req.IsFull(); e.cursor++ {
+		rec := e.recommendations[e.cursor]
+		req.AppendInt64(0, int64(e.cursor+1))
+		req.AppendString(1, rec.SchemaName.O)
+		req.AppendString(2, rec.TableName.O)
+		req.AppendString(3, rec.Name())
+		req.AppendString(4, rec.ColumnNames())
+		req.AppendFloat64(5, rec.Benefit)
+		req.AppendFloat64(6, rec.BenefitRatio)
+		req.AppendString(7, strings.Join(rec.Digests, ","))
+		req.AppendString(8, rec.DDL())
+	}
+	return nil
+}
+
 // Close implements the Executor Close interface.
 func (*IndexAdviseExec) Close() error {
 	return nil
 }
 
 // Open implements the Executor Open interface.
-func (*IndexAdviseExec) Open(context.Context) error {
+func (e *IndexAdviseExec) Open(context.Context) error {
+	if e.indexAdviseInfo.FromStmtSummary {
+		return e.indexAdviseInfo.checkOptions()
+	}
 	return nil
 }
 
 // IndexAdviseInfo saves the information of index advise operation.
 type IndexAdviseInfo struct {
-	Path        string
-	MaxMinutes  uint64
-	MaxIndexNum *ast.MaxIndexNumClause
+	FromStmtSummary bool
+	Limit           uint64
+	Path            string
+	MaxMinutes      uint64
+	MaxIndexNum     *ast.MaxIndexNumClause
 	core.LineFieldsInfo
 	Ctx       sessionctx.Context
 	StmtNodes [][]ast.StmtNode
@@ -108,7 +164,7 @@ func (e *IndexAdviseInfo) getStmtNodes(data []byte) error {
 	return nil
 }
 
-func (e *IndexAdviseInfo) prepareInfo(data []byte) error {
+func (e *IndexAdviseInfo) checkOptions() error {
 	if e.MaxMinutes == 0 {
 		return errors.New("Index Advise: the maximum execution time limit should be greater than 0")
 	}
@@ -117,21 +173,136 @@ func (e *IndexAdviseInfo) prepareInfo(data []byte) error {
 			return errors.New("Index Advise: the maximum number of indexes should be greater than 0")
 		}
 	}
+	if e.FromStmtSummary && e.Limit == 0 {
+		return errors.New("Index Advise: the number of statements should be greater than 0")
+	}
+	return nil
+}
+
+func (e *IndexAdviseInfo) prepareInfo(data []byte) error {
+	if err := e.checkOptions(); err != nil {
+		return err
+	}
 	return e.getStmtNodes(data)
 }
 
+func (e *IndexAdviseInfo) advisorOptions() indexadvisor.Options {
+	var opts indexadvisor.Options
+	if e.MaxIndexNum != nil {
+		if e.MaxIndexNum.PerTable != ast.UnspecifiedSize {
+			opts.MaxIndexesPerTable = int(min(e.MaxIndexNum.PerTable, math.MaxInt32))
+		}
+		if e.MaxIndexNum.PerDB != ast.UnspecifiedSize {
+			opts.MaxIndexesPerDB = int(min(e.MaxIndexNum.PerDB, math.MaxInt32))
+		}
+	}
+	if e.MaxMinutes != ast.UnspecifiedSize {
+		opts.Deadline = time.Now().Add(time.Duration(min(e.MaxMinutes, math.MaxInt32)) * time.Minute)
+	}
+	return opts
+}
+
+// advise runs the index advisor for the workload and saves the recommendations
+// in the session for information_schema.tidb_index_advisor_results.
+func (e *IndexAdviseInfo) advise(ctx context.Context, queries []*indexadvisor.Query) ([]*indexadvisor.Recommendation, error) {
+	dom := domain.GetDomain(e.Ctx)
+	sysSessionPool := dom.SysSessionPool()
+	res, err := sysSessionPool.Get()
+	if err != nil {
+		return nil, err
+	}
+	sctx := res.(sessionctx.Context)
+	// The session goes back to the pool, so everything changed on it is restored.
+	vars := sctx.GetSessionVars()
+	origRestrictedSQL, origDB, origHypoIndexes := vars.InRestrictedSQL, vars.CurrentDB, vars.HypoIndexes
+	origUser, origActiveRoles, origPM := vars.User, vars.ActiveRoles, privilege.GetPrivilegeManager(sctx)
+	vars.InRestrictedSQL = true
+	// The statements are explained with the privileges of the caller, so the
+	// advisor can't tell the plans of the tables that the caller can't access.
+	callerVars := e.Ctx.GetSessionVars()
+	vars.User, vars.ActiveRoles = callerVars.User, callerVars.ActiveRoles
+	privilege.BindPrivilegeManager(sctx, privilege.GetPrivilegeManager(e.Ctx))
+	defer func() {
+		vars.InRestrictedSQL, vars.CurrentDB, vars.HypoIndexes = origRestrictedSQL, origDB, origHypoIndexes
+		vars.User, vars.ActiveRoles = origUser, origActiveRoles
+		privilege.BindPrivilegeManager(sctx, origPM)
+		if _, err := sctx.GetSQLExecutor().ExecuteInternal(ctx, "rollback"); err != nil {
+			res.(pools.Resource).Close()
+			return
+		}
+		sysSessionPool.Put(res)
+	}()
+
+	opt := &hypoIndexOptimizer{sctx: sctx}
+	recommendations, err := indexadvisor.AdviseIndexes(ctx, dom.InfoSchema(), opt, queries, e.advisorOptions())
+	if err != nil {
+		return nil, err
+	}
+	indexadvisor.SaveResults(e.Ctx, recommendations, time.Now())
+	return recommendations, nil
+}
+
 // GetIndexAdvice gets the index advice by workload file.
-func (e *IndexAdviseInfo) GetIndexAdvice(data []byte) error {
+func (e *IndexAdviseInfo) GetIndexAdvice(ctx context.Context, data []byte) error {
 	if err := e.prepareInfo(data); err != nil {
 		return err
 	}
-	// TODO: Finish the index advise process. It will be done in another PR.
+	schema := e.Ctx.GetSessionVars().CurrentDB
+	queries := make([]*indexadvisor.Query, 0, len(e.StmtNodes))
+	for _, stmtNodes := range e.StmtNodes {
+		for _, stmtNode := range stmtNodes {
+			queries = append(queries, &indexadvisor.Query{SchemaName: schema, Text: stmtNode.Text()})
+		}
+	}
+	recommendations, err := e.advise(kv.WithInternalSourceType(ctx, kv.InternalTxnOthers), queries)
+	if err != nil {
+		return err
+	}
+	e.Result = &IndexAdvice{Recommendations: recommendations}
 	return nil
 }
 
 // IndexAdvice represents the index advice.
 type IndexAdvice struct {
-	// TODO: Define index advice data structure and implements the ResultSet interface. It will be done in another PR
+	Recommendations []*indexadvisor.Recommendation
+}
+
+// hypoIndexOptimizer estimates the plan cost of a statement by explaining it in
+// a system session, where the candidate indexes are hypothetical indexes.
+type hypoIndexOptimizer struct {
+	sctx sessionctx.Context
+}
+
+// QueryPlanCost implements the indexadvisor.Optimizer interface.
+func (o *hypoIndexOptimizer) QueryPlanCost(ctx context.Context, schema, sql string, indexes []*indexadvisor.Index) (float64, error) {
+	vars := o.sctx.GetSessionVars()
+	vars.CurrentDB = schema
+	vars.HypoIndexes = make(map[string]map[string]map[string]*model.IndexInfo)
+	defer func() {
+		vars.HypoIndexes = nil
+	}()
+	for _, idx := range indexes {
+		db, tbl := idx.SchemaName.L, idx.TableName.L
+		if vars.HypoIndexes[db] == nil {
+			vars.HypoIndexes[db] = make(map[string]map[string]*model.IndexInfo)
+		}
+		if vars.HypoIndexes[db][tbl] == nil {
+			vars.HypoIndexes[db][tbl] = make(map[string]*model.IndexInfo)
+		}
+		info := idx.HypoIndexInfo()
+		vars.HypoIndexes[db][tbl][info.Name.L] = info
+	}
+
+	rows, _, err := o.sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx,
+		[]sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession}, "EXPLAIN FORMAT = 'verbose' "+sql)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, errors.New("Index Advise: empty plan")
+	}
+	// The estCost of the root operator is the cost of the whole plan.
+	return strconv.ParseFloat(rows[0].GetString(2), 64)
 }
 
 // IndexAdviseVarKeyType is a dummy type to avoid naming collision in context.
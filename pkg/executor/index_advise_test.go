@@ -15,10 +15,12 @@
 package executor_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/executor"
+	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/stretchr/testify/require"
@@ -65,3 +67,66 @@ func TestIndexAdvise(t *testing.T) {
 	require.Equal(t, uint64(4), ia.MaxIndexNum.PerTable)
 	require.Equal(t, uint64(5), ia.MaxIndexNum.PerDB)
 }
+
+func TestIndexAdviseFromStmtSummary(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int, key idx_c(c))")
+	// Clear the statements summary.
+	tk.MustExec("set global tidb_enable_stmt_summary = 0")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	defer tk.MustExec("set global tidb_enable_stmt_summary = default")
+
+	for i := 0; i < 3; i++ {
+		tk.MustQuery("select * from t where a = 1 and b = 2")
+		tk.MustQuery("select * from t where c = 1")
+	}
+	tk.MustGetErrMsg("index advise limit 0", "Index Advise: the number of statements should be greater than 0")
+	tk.MustGetErrMsg("index advise max_minutes 0", "Index Advise: the maximum execution time limit should be greater than 0")
+
+	rows := tk.MustQuery("index advise limit 10 max_idxnum per_table 1").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "1", rows[0][0])
+	require.Equal(t, "test", rows[0][1])
+	require.Equal(t, "t", rows[0][2])
+	require.Equal(t, "idx_a_b", rows[0][3])
+	require.Equal(t, "a,b", rows[0][4])
+	require.Equal(t, "CREATE INDEX `idx_a_b` ON `test`.`t`(`a`, `b`)", rows[0][8])
+	tk.MustQuery("select `rank`, table_name, index_name, create_index_ddl from information_schema.tidb_index_advisor_results").Check(
+		testkit.Rows("1 t idx_a_b CREATE INDEX `idx_a_b` ON `test`.`t`(`a`, `b`)"))
+	// The hypothetical indexes don't leak into the session.
+	tk.MustQuery("explain select * from t where a = 1 and b = 2").CheckNotContain("idx_a_b")
+
+	// Advise by a workload file.
+	tk.MustExec("index advise local infile '/tmp/index_advise.sql'")
+	ctx := tk.Session().(sessionctx.Context)
+	ia, ok := ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+	require.True(t, ok)
+	ctx.SetValue(executor.IndexAdviseVarKey, nil)
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), []byte("select * from t where b > 1 and b < 10;\nselect * from t where c = 1;\n")))
+	require.Len(t, ia.Result.Recommendations, 1)
+	require.Equal(t, "CREATE INDEX `idx_b` ON `test`.`t`(`b`)", ia.Result.Recommendations[0].DDL())
+	tk.MustQuery("select index_name from information_schema.tidb_index_advisor_results").Check(testkit.Rows("idx_b"))
+
+	// The statements are explained with the privileges of the caller, and the
+	// results are kept in the session.
+	tk.MustExec("create user 'advisor'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk2.Session().Auth(&auth.UserIdentity{Username: "advisor", Hostname: "%"}, nil, nil, nil))
+	tk2.MustExec("index advise local infile '/tmp/index_advise.sql'")
+	ctx2 := tk2.Session().(sessionctx.Context)
+	ia, ok = ctx2.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+	require.True(t, ok)
+	ctx2.SetValue(executor.IndexAdviseVarKey, nil)
+	workload := []byte("select * from test.t where b > 1 and b < 10;\n")
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), workload))
+	require.Len(t, ia.Result.Recommendations, 0)
+	tk.MustExec("grant all on test.t to 'advisor'@'%'")
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), workload))
+	require.Len(t, ia.Result.Recommendations, 1)
+	tk2.MustQuery("select index_name from information_schema.tidb_index_advisor_results").Check(testkit.Rows("idx_b"))
+	tk3 := testkit.NewTestKit(t, store)
+	tk3.MustQuery("select index_name from information_schema.tidb_index_advisor_results").Check(testkit.Rows())
+}
@@ -48,6 +48,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/planner/indexadvisor"
 	"github.com/pingcap/tidb/pkg/privilege"
 	"github.com/pingcap/tidb/pkg/privilege/privileges"
 	"github.com/pingcap/tidb/pkg/session/txninfo"
@@ -137,6 +138,12 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			e.setDataFromIndexes(sctx, dbs)
 		case infoschema.TableViews:
 			e.setDataFromViews(sctx, dbs)
+		case infoschema.TableRoutines:
+			err = e.setDataForRoutines(ctx, sctx)
+		case infoschema.TableTriggers:
+			e.setDataForTriggers(sctx, dbs)
+		case infoschema.TableEvents:
+			err = e.setDataForEvents(ctx, sctx)
 		case infoschema.TableEngines:
 			e.setDataFromEngines()
 		case infoschema.TableCharacterSets:
@@ -209,6 +216,10 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			e.setDataFromIndexUsage(sctx, dbs)
 		case infoschema.ClusterTableTiDBIndexUsage:
 			err = e.setDataForClusterIndexUsage(sctx, dbs)
+		case infoschema.TableTiDBIndexAdvisorResults:
+			e.setDataFromIndexAdvisorResults(sctx)
+		case infoschema.TableTiDBInstancePlanCache:
+			err = e.setDataFromInstancePlanCache(sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -1178,18 +1189,34 @@ func (e *memtableRetriever) setDataFromPartitions(sctx sessionctx.Context, schem
 					if pi.PlacementPolicyRef != nil {
 						policyName = pi.PlacementPolicyRef.Name.O
 					}
+					partitionName, partitionPos := pi.Name.O, i+1
+					var subPartitionName, subPartitionPos, subPartitionMethod, subPartitionExpr any
+					if sub := table.Partition.Sub; sub != nil {
+						partitionName, partitionPos = pi.ParentName.O, i/int(sub.Num)+1
+						subPartitionName, subPartitionPos = pi.Name.O, i%int(sub.Num)+1
+						subPartitionMethod = sub.Type.String()
+						if sub.Type == model.PartitionTypeHash {
+							subPartitionExpr = sub.Expr
+						} else {
+							cols := make([]string, 0, len(sub.Columns))
+							for _, col := range sub.Columns {
+								cols = append(cols, "`"+col.String()+"`")
+							}
+							subPartitionExpr = strings.Join(cols, ",")
+						}
+					}
 					record := types.MakeDatums(
 						infoschema.CatalogVal, // TABLE_CATALOG
 						schema.O,              // TABLE_SCHEMA
 						table.Name.O,          // TABLE_NAME
-						pi.Name.O,             // PARTITION_NAME
-						nil,                   // SUBPARTITION_NAME
-						i+1,                   // PARTITION_ORDINAL_POSITION
-						nil,                   // SUBPARTITION_ORDINAL_POSITION
+						partitionName,         // PARTITION_NAME
+						subPartitionName,      // SUBPARTITION_NAME
+						partitionPos,          // PARTITION_ORDINAL_POSITION
+						subPartitionPos,       // SUBPARTITION_ORDINAL_POSITION
 						partitionMethod,       // PARTITION_METHOD
-						nil,                   // SUBPARTITION_METHOD
+						subPartitionMethod,    // SUBPARTITION_METHOD
 						partitionExpr,         // PARTITION_EXPRESSION
-						nil,                   // SUBPARTITION_EXPRESSION
+						subPartitionExpr,      // SUBPARTITION_EXPRESSION
 						partitionDesc,         // PARTITION_DESCRIPTION
 						rowCount,              // TABLE_ROWS
 						avgRowLength,          // AVG_ROW_LENGTH
@@ -3630,6 +3657,62 @@ func (e *memtableRetriever) setDataForClusterIndexUsage(ctx sessionctx.Context,
 	return nil
 }
 
+func (e *memtableRetriever) setDataFromIndexAdvisorResults(ctx sessionctx.Context) {
+	recommendations, adviseTime := indexadvisor.LastResults(ctx)
+	checker := privilege.GetPrivilegeManager(ctx)
+	rows := make([][]types.Datum, 0, len(recommendations))
+	for i, rec := range recommendations {
+		if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles,
+			rec.SchemaName.L, rec.TableName.L, "", mysql.AllPrivMask) {
+			continue
+		}
+		row := types.MakeDatums(
+			i+1,
+			rec.SchemaName.O,
+			rec.TableName.O,
+			rec.Name(),
+			rec.ColumnNames(),
+			rec.Benefit,
+			rec.BenefitRatio,
+			strings.Join(rec.Digests, ","),
+			rec.DDL(),
+			types.NewTime(types.FromGoTime(adviseTime.In(ctx.GetSessionVars().Location())), mysql.TypeDatetime, 0),
+		)
+		rows = append(rows, row)
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataFromInstancePlanCache(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	instanceCache := domain.GetDomain(ctx).InstancePlanCache()
+	if instanceCache == nil {
+		return nil
+	}
+	items := instanceCache.All()
+	loc := ctx.GetSessionVars().Location()
+	rows := make([][]types.Datum, 0, len(items))
+	for _, item := range items {
+		row := types.MakeDatums(
+			item.SQLDigest,
+			item.SQLText,
+			item.SchemaName,
+			item.PlanDigest,
+			item.ParamTypes,
+			item.MemSize,
+			item.Hits,
+			item.HitRate,
+			types.NewTime(types.FromGoTime(item.CreateTime.In(loc)), mysql.TypeDatetime, types.MaxFsp),
+			types.NewTime(types.FromGoTime(item.LastAccessTime.In(loc)), mysql.TypeDatetime, types.MaxFsp),
+		)
+		rows = append(rows, row)
+	}
+	e.rows = rows
+	return nil
+}
+
 func checkRule(rule *label.Rule) (dbName, tableName string, partitionName string, err error) {
 	s := strings.Split(rule.ID, "/")
 	if len(s) < 3 {
@@ -95,6 +95,8 @@ type InsertValues struct {
 	// fkChecks contains the foreign key checkers.
 	fkChecks   []*FKCheckExec
 	fkCascades []*FKCascadeExec
+	// triggers activates the triggers of the table, it's nil if the table has no trigger.
+	triggers *TriggerExec
 }
 
 type defaultVal struct {
@@ -696,6 +698,12 @@ func (e *InsertValues) fillRow(ctx context.Context, row []types.Datum, hasValue
 			}
 		}
 	}
+	// The generated columns are evaluated with the values changed by the BEFORE INSERT triggers.
+	if e.triggers != nil {
+		if err := e.triggers.activateBefore(ctx, model.TriggerInsert, nil, row); err != nil {
+			return nil, err
+		}
+	}
 
 	// Handle exchange partition
 	tbl := e.Table.Meta()
@@ -1373,6 +1381,11 @@ func (e *InsertValues) removeRow(
 		return true, nil
 	}
 
+	if e.triggers != nil {
+		if err = e.triggers.activateBefore(ctx, model.TriggerDelete, oldRow, nil); err != nil {
+			return false, err
+		}
+	}
 	err = r.t.RemoveRecord(e.Ctx().GetTableCtx(), handle, oldRow)
 	if err != nil {
 		return false, err
@@ -1381,6 +1394,9 @@ func (e *InsertValues) removeRow(
 	if err != nil {
 		return false, err
 	}
+	if e.triggers != nil {
+		e.triggers.recordAfter(model.TriggerDelete, oldRow, nil)
+	}
 	if inReplace {
 		e.Ctx().GetSessionVars().StmtCtx.AddAffectedRows(1)
 	} else {
@@ -1431,6 +1447,9 @@ func (e *InsertValues) addRecordWithAutoIDHint(
 	if e.lastInsertID != 0 {
 		vars.SetLastInsertID(e.lastInsertID)
 	}
+	if e.triggers != nil {
+		e.triggers.recordAfter(model.TriggerInsert, nil, row)
+	}
 	if !vars.StmtCtx.BatchCheck {
 		for _, fkc := range e.fkChecks {
 			err = fkc.insertRowNeedToCheck(vars.StmtCtx, row)
@@ -0,0 +1,627 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/ddl"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/hint"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// mviewLogAlias is the alias of the change logs in the statements of a fast refresh.
+const mviewLogAlias = "_tidb_mlog"
+
+// mviewDefinition is the analyzed definition of a materialized view.
+type mviewDefinition struct {
+	stmt ast.StmtNode
+	// cols are the columns of the materialized view, one for each field of the definition.
+	cols  []model.CIStr
+	bases []*mviewBaseTable
+	// fastUnsupported is the reason why the view can't be refreshed incrementally, it's empty if the view can be.
+	fastUnsupported string
+	// aggregated indicates the view is an aggregation on a single table, the groups with changed rows are recomputed
+	// by a fast refresh. Otherwise, the view is a join and the rows with changed handles are recomputed.
+	aggregated bool
+	groupCols  []mviewGroupCol
+}
+
+// mviewBaseTable is a table referenced by the definition of a materialized view.
+type mviewBaseTable struct {
+	dbName model.CIStr
+	info   *model.TableInfo
+	// refs are the occurrences of the table in the FROM clause.
+	refs []*mviewTableRef
+}
+
+// mviewTableRef is an occurrence of a base table in the FROM clause.
+type mviewTableRef struct {
+	base *mviewBaseTable
+	// qualifier is the alias of the table, or the name of the table if it has no alias.
+	qualifier model.CIStr
+	// handleCol is the view column holding the handle of the table, it's only used by a join.
+	handleCol model.CIStr
+}
+
+// mviewGroupCol is a GROUP BY column of an aggregated view.
+type mviewGroupCol struct {
+	col      *model.ColumnInfo
+	mviewCol model.CIStr
+}
+
+// mviewDefinitionChecker collects the constructs which prevent a fast refresh.
+type mviewDefinitionChecker struct {
+	tableNames []*ast.TableName
+	subquery   bool
+	window     bool
+	aggregate  bool
+}
+
+func (c *mviewDefinitionChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.TableName:
+		c.tableNames = append(c.tableNames, x)
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr:
+		c.subquery = true
+	case *ast.WindowFuncExpr:
+		c.window = true
+	case *ast.AggregateFuncExpr:
+		c.aggregate = true
+	}
+	return in, false
+}
+
+func (*mviewDefinitionChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// parseMViewDefinition parses the definition of a materialized view with the SQL mode when it's created.
+func parseMViewDefinition(sctx sessionctx.Context, mview *model.MaterializedViewInfo) (ast.StmtNode, error) {
+	p := parser.New()
+	p.SetSQLMode(mview.SQLMode)
+	p.SetParserConfig(sctx.GetSessionVars().BuildParserConfig())
+	return p.ParseOneStmt(mview.SelectStmt, "", "")
+}
+
+// analyzeMViewDefinition resolves the base tables of a materialized view and checks whether the view can be
+// refreshed incrementally.
+func analyzeMViewDefinition(is infoschema.InfoSchema, stmt ast.StmtNode, cols []model.CIStr) (*mviewDefinition, error) {
+	def := &mviewDefinition{stmt: stmt, cols: cols}
+	checker := &mviewDefinitionChecker{}
+	stmt.Accept(checker)
+	for _, tn := range checker.tableNames {
+		// The references to common table expressions aren't qualified by the schema.
+		if tn.Schema.L == "" {
+			continue
+		}
+		tbl, err := is.TableByName(tn.Schema, tn.Name)
+		if err != nil {
+			return nil, err
+		}
+		tblInfo := tbl.Meta()
+		if tblInfo.IsView() || tblInfo.IsSequence() || tblInfo.TempTableType != model.TempTableNone ||
+			util.IsMemDB(tn.Schema.L) {
+			return nil, dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(
+				"materialized view on views, sequences, temporary tables or memory tables")
+		}
+		if def.findBase(tblInfo.ID) == nil {
+			def.bases = append(def.bases, &mviewBaseTable{dbName: tn.Schema, info: tblInfo})
+		}
+	}
+
+	sel, ok := stmt.(*ast.SelectStmt)
+	switch {
+	case !ok:
+		def.fastUnsupported = "set operations are not supported"
+	case checker.subquery:
+		def.fastUnsupported = "subqueries are not supported"
+	case checker.window:
+		def.fastUnsupported = "window functions are not supported"
+	case sel.With != nil:
+		def.fastUnsupported = "common table expressions are not supported"
+	case sel.Distinct || sel.OrderBy != nil || sel.Limit != nil:
+		def.fastUnsupported = "DISTINCT, ORDER BY and LIMIT are not supported"
+	case sel.From == nil:
+		def.fastUnsupported = "the view has no base table"
+	}
+	if def.fastUnsupported != "" {
+		return def, nil
+	}
+	var refs []*mviewTableRef
+	if def.fastUnsupported = def.collectTableRefs(sel.From.TableRefs, &refs); def.fastUnsupported != "" {
+		return def, nil
+	}
+	def.aggregated = checker.aggregate || sel.GroupBy != nil
+	if def.aggregated {
+		def.fastUnsupported = def.analyzeAggregation(sel, refs)
+	} else {
+		def.fastUnsupported = def.analyzeJoin(sel, refs)
+	}
+	return def, nil
+}
+
+func (d *mviewDefinition) findBase(id int64) *mviewBaseTable {
+	for _, base := range d.bases {
+		if base.info.ID == id {
+			return base
+		}
+	}
+	return nil
+}
+
+// collectTableRefs collects the occurrences of the base tables in the FROM clause. Only inner joins of tables are
+// supported by a fast refresh, because a changed row of an outer join may affect the padded rows of other tables.
+func (d *mviewDefinition) collectTableRefs(node ast.ResultSetNode, refs *[]*mviewTableRef) string {
+	switch x := node.(type) {
+	case *ast.Join:
+		if x.Right != nil && x.Tp != ast.CrossJoin {
+			return "outer joins are not supported"
+		}
+		if reason := d.collectTableRefs(x.Left, refs); reason != "" {
+			return reason
+		}
+		if x.Right != nil {
+			return d.collectTableRefs(x.Right, refs)
+		}
+	case *ast.TableSource:
+		tn, ok := x.Source.(*ast.TableName)
+		if !ok || tn.Schema.L == "" {
+			return "derived tables are not supported"
+		}
+		var base *mviewBaseTable
+		for _, b := range d.bases {
+			if b.dbName.L == tn.Schema.L && b.info.Name.L == tn.Name.L {
+				base = b
+			}
+		}
+		ref := &mviewTableRef{base: base, qualifier: tn.Name}
+		if x.AsName.L != "" {
+			ref.qualifier = x.AsName
+		}
+		base.refs = append(base.refs, ref)
+		*refs = append(*refs, ref)
+	default:
+		return "derived tables are not supported"
+	}
+	return ""
+}
+
+// analyzeAggregation checks an aggregation can be refreshed incrementally. The GROUP BY items must be columns in the
+// select list, so the groups to recompute can be found in the view by the grouping values in the change log.
+func (d *mviewDefinition) analyzeAggregation(sel *ast.SelectStmt, refs []*mviewTableRef) string {
+	if len(refs) != 1 {
+		return "aggregation on joins is not supported"
+	}
+	if sel.GroupBy == nil {
+		// The scalar aggregation is recomputed when any row is changed.
+		return ""
+	}
+	if sel.GroupBy.Rollup {
+		return "WITH ROLLUP is not supported"
+	}
+	ref := refs[0]
+	for _, item := range sel.GroupBy.Items {
+		colExpr, ok := item.Expr.(*ast.ColumnNameExpr)
+		if !ok {
+			return "GROUP BY items must be columns"
+		}
+		col := ref.findColumn(colExpr.Name)
+		if col == nil {
+			return fmt.Sprintf("GROUP BY column '%s' is not a column of the base table", colExpr.Name.Name.O)
+		}
+		mviewCol, ok := d.findField(sel, ref, col.Name)
+		if !ok {
+			return fmt.Sprintf("GROUP BY column '%s' is not in the select list", col.Name.O)
+		}
+		d.groupCols = append(d.groupCols, mviewGroupCol{col: col, mviewCol: mviewCol})
+	}
+	return ""
+}
+
+// analyzeJoin checks a join can be refreshed incrementally. The handle of each table must be in the select list, so
+// the rows derived from the changed rows can be found in the view by the handles in the change logs.
+func (d *mviewDefinition) analyzeJoin(sel *ast.SelectStmt, refs []*mviewTableRef) string {
+	for _, ref := range refs {
+		tblInfo := ref.base.info
+		if tblInfo.IsCommonHandle {
+			return fmt.Sprintf("table '%s' has a clustered index which is not an integer", tblInfo.Name.O)
+		}
+		handleName := model.ExtraHandleName
+		if pkCol := tblInfo.GetPkColInfo(); tblInfo.PKIsHandle && pkCol != nil {
+			handleName = pkCol.Name
+		}
+		mviewCol, ok := d.findField(sel, ref, handleName)
+		if !ok {
+			return fmt.Sprintf("handle '%s' of table '%s' is not in the select list", handleName.O, ref.qualifier.O)
+		}
+		ref.handleCol = mviewCol
+	}
+	return ""
+}
+
+// findColumn returns the public column referenced by the name, or nil if the name doesn't refer to the table.
+func (r *mviewTableRef) findColumn(name *ast.ColumnName) *model.ColumnInfo {
+	if !r.matchName(name) {
+		return nil
+	}
+	col := model.FindColumnInfo(r.base.info.Columns, name.Name.L)
+	if col == nil || col.State != model.StatePublic || col.Hidden {
+		return nil
+	}
+	return col
+}
+
+func (r *mviewTableRef) matchName(name *ast.ColumnName) bool {
+	if name.Table.L == "" {
+		return len(r.base.refs) == 1
+	}
+	return name.Table.L == r.qualifier.L && (name.Schema.L == "" || name.Schema.L == r.base.dbName.L)
+}
+
+// findField returns the view column of the field which is the column of the table.
+func (d *mviewDefinition) findField(sel *ast.SelectStmt, ref *mviewTableRef, col model.CIStr) (model.CIStr, bool) {
+	for i, field := range sel.Fields.Fields {
+		colExpr, ok := field.Expr.(*ast.ColumnNameExpr)
+		if ok && colExpr.Name.Name.L == col.L && ref.matchName(colExpr.Name) && i < len(d.cols) {
+			return d.cols[i], true
+		}
+	}
+	return model.CIStr{}, false
+}
+
+// logTableName returns the change log of the base table.
+func (b *mviewBaseTable) logTableName(mview *model.TableInfo) string {
+	return ddl.MViewLogTableName(mview.ID, b.info.ID)
+}
+
+// refreshStmts builds the statements of a refresh. A complete refresh replaces all rows of the view. A fast refresh
+// replaces the rows derived from the changed rows of the base tables. At last, the change logs are cleared.
+func (d *mviewDefinition) refreshStmts(dbName model.CIStr, mview *model.TableInfo, complete bool) ([]string, error) {
+	mviewName := quoteName(dbName.O) + "." + quoteName(mview.Name.O)
+	var insertCols strings.Builder
+	for i, col := range d.cols {
+		if i > 0 {
+			insertCols.WriteString(", ")
+		}
+		insertCols.WriteString(quoteName(col.O))
+	}
+
+	var deleteStmt string
+	var cond []string
+	switch {
+	case complete || (d.aggregated && len(d.groupCols) == 0):
+		deleteStmt = "DELETE FROM " + mviewName
+	case d.aggregated:
+		// The groups of the changed rows are recomputed.
+		base := d.bases[0]
+		logName := quoteName(mysql.SystemDB) + "." + quoteName(base.logTableName(mview)) + " AS " + quoteName(mviewLogAlias)
+		mviewConds := make([]string, 0, len(d.groupCols))
+		baseConds := make([]string, 0, len(d.groupCols))
+		for _, group := range d.groupCols {
+			logCol := quoteName(mviewLogAlias) + "." + quoteName(group.col.Name.O)
+			mviewConds = append(mviewConds, fmt.Sprintf("%s.%s <=> %s", mviewName, quoteName(group.mviewCol.O), logCol))
+			baseConds = append(baseConds, fmt.Sprintf("%s.%s <=> %s", quoteName(base.refs[0].qualifier.O), quoteName(group.col.Name.O), logCol))
+		}
+		deleteStmt = fmt.Sprintf("DELETE FROM %s WHERE EXISTS (SELECT 1 FROM %s WHERE %s)", mviewName, logName, strings.Join(mviewConds, " AND "))
+		cond = append(cond, fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s)", logName, strings.Join(baseConds, " AND ")))
+	default:
+		// The rows joined with the changed rows are recomputed.
+		mviewConds := make([]string, 0, len(d.bases))
+		for _, base := range d.bases {
+			logHandles := fmt.Sprintf("(SELECT %s FROM %s.%s)", quoteName(model.MViewLogHandleColName),
+				quoteName(mysql.SystemDB), quoteName(base.logTableName(mview)))
+			for _, ref := range base.refs {
+				handleName := model.ExtraHandleName
+				if pkCol := base.info.GetPkColInfo(); base.info.PKIsHandle && pkCol != nil {
+					handleName = pkCol.Name
+				}
+				mviewConds = append(mviewConds, fmt.Sprintf("%s.%s IN %s", mviewName, quoteName(ref.handleCol.O), logHandles))
+				cond = append(cond, fmt.Sprintf("%s.%s IN %s", quoteName(ref.qualifier.O), quoteName(handleName.O), logHandles))
+			}
+		}
+		deleteStmt = fmt.Sprintf("DELETE FROM %s WHERE %s", mviewName, strings.Join(mviewConds, " OR "))
+	}
+
+	selectText, err := d.restoreDefinition(cond)
+	if err != nil {
+		return nil, err
+	}
+	stmts := make([]string, 0, 2+len(mview.MaterializedView.LogTableIDs))
+	stmts = append(stmts, deleteStmt, fmt.Sprintf("INSERT INTO %s (%s) %s", mviewName, insertCols.String(), selectText))
+	for _, base := range d.bases {
+		if len(mview.MaterializedView.LogTableIDs) > 0 {
+			stmts = append(stmts, fmt.Sprintf("DELETE FROM %s.%s", quoteName(mysql.SystemDB), quoteName(base.logTableName(mview))))
+		}
+	}
+	return stmts, nil
+}
+
+// restoreDefinition restores the definition with the extra condition ORed into the WHERE clause.
+func (d *mviewDefinition) restoreDefinition(cond []string) (string, error) {
+	sel, ok := d.stmt.(*ast.SelectStmt)
+	if ok && len(cond) > 0 {
+		stmt, err := parser.New().ParseOneStmt("SELECT 1 FROM DUAL WHERE "+strings.Join(cond, " OR "), "", "")
+		if err != nil {
+			return "", err
+		}
+		where := stmt.(*ast.SelectStmt).Where
+		if sel.Where != nil {
+			where = &ast.BinaryOperationExpr{
+				Op: opcode.LogicAnd,
+				L:  &ast.ParenthesesExpr{Expr: sel.Where},
+				R:  &ast.ParenthesesExpr{Expr: where},
+			}
+		}
+		sel.Where = where
+	}
+	var sb strings.Builder
+	restoreFlag := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+	if err := d.stmt.Restore(format.NewRestoreCtx(restoreFlag, &sb)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (e *DDLExec) executeCreateMaterializedView(ctx context.Context, s *ast.CreateMaterializedViewStmt) error {
+	sctx := e.Ctx()
+	if e.is.TableExists(s.ViewName.Schema, s.ViewName.Name) {
+		err := infoschema.ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name})
+		if s.IfNotExists {
+			sctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	ret := &core.PreprocessorReturn{}
+	if err := core.Preprocess(ctx, sctx, s.Select, core.WithPreprocessorReturn(ret)); err != nil {
+		return errors.Trace(err)
+	}
+	if ret.IsStaleness {
+		return exeerrors.ErrViewInvalid.GenWithStackByArgs(s.ViewName.Schema.L, s.ViewName.Name.L)
+	}
+	hintProcessor := hint.NewQBHintHandler(nil)
+	s.Select.Accept(hintProcessor)
+	builder, savedBlockNames := core.NewPlanBuilder().Init(sctx.GetPlanCtx(), e.is, hintProcessor)
+	plan, err := builder.Build(ctx, s.Select)
+	sctx.GetSessionVars().PlannerSelectBlockAsName.Store(&savedBlockNames)
+	if err != nil {
+		return err
+	}
+
+	names := plan.OutputNames()
+	colNames := make([]model.CIStr, 0, len(names))
+	cols := make([]*table.Column, 0, len(names))
+	for i, col := range plan.Schema().Columns {
+		name := names[i].ColName
+		if s.Cols != nil {
+			name = s.Cols[i]
+		}
+		ft := col.RetType.Clone()
+		ft.SetFlag(ft.GetFlag() & (mysql.NotNullFlag | mysql.UnsignedFlag | mysql.BinaryFlag))
+		colNames = append(colNames, name)
+		cols = append(cols, table.ToColumn(&model.ColumnInfo{
+			Name:      name,
+			Offset:    i,
+			State:     model.StatePublic,
+			FieldType: *ft,
+		}))
+	}
+
+	def, err := analyzeMViewDefinition(e.is, s.Select, colNames)
+	if err != nil {
+		return err
+	}
+	if s.Refresh == model.MViewRefreshFast && def.fastUnsupported != "" {
+		return dbterror.ErrMViewFastRefreshUnsupported.GenWithStackByArgs(s.ViewName.Name.O, def.fastUnsupported)
+	}
+	var sb strings.Builder
+	restoreFlag := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+	if err = s.Select.Restore(format.NewRestoreCtx(restoreFlag, &sb)); err != nil {
+		return err
+	}
+	mview := &model.MaterializedViewInfo{
+		Definer:       s.Definer,
+		SelectStmt:    sb.String(),
+		SQLMode:       sctx.GetSessionVars().SQLMode,
+		RefreshMethod: s.Refresh,
+	}
+	var logCols [][]int64
+	for _, base := range def.bases {
+		mview.BaseTableIDs = append(mview.BaseTableIDs, base.info.ID)
+		if s.Refresh == model.MViewRefreshFast {
+			colIDs := make([]int64, 0, len(def.groupCols))
+			for _, group := range def.groupCols {
+				colIDs = append(colIDs, group.col.ID)
+			}
+			logCols = append(logCols, colIDs)
+		}
+	}
+	if s.Schedule != nil {
+		mview.RefreshEvent = model.NewCIStr(mviewRefreshEventName(s.ViewName.Name))
+	}
+
+	dom := domain.GetDomain(sctx)
+	if err = dom.DDL().CreateMaterializedView(sctx, s, cols, mview, logCols); err != nil {
+		return err
+	}
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(s.ViewName.Schema, s.ViewName.Name)
+	if err != nil {
+		return err
+	}
+	if tbl.Meta().MaterializedView == nil {
+		// The table has been created by another statement with IF NOT EXISTS.
+		return nil
+	}
+	if err = refreshMaterializedView(ctx, sctx, is, s.ViewName.Schema, tbl.Meta(), true); err != nil {
+		return err
+	}
+	if s.Schedule != nil {
+		return createMViewRefreshEvent(ctx, sctx, is, s, tbl.Meta())
+	}
+	return nil
+}
+
+// mviewRefreshEventName returns the name of the event refreshing the materialized view on schedule.
+func mviewRefreshEventName(mviewName model.CIStr) string {
+	return "mview_refresh_" + mviewName.O
+}
+
+func createMViewRefreshEvent(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema,
+	s *ast.CreateMaterializedViewStmt, mview *model.TableInfo) error {
+	scheduler, err := getEventScheduler(sctx)
+	if err != nil {
+		return err
+	}
+	info, err := newEventInfo(sctx, is, &ast.TableName{Schema: s.ViewName.Schema, Name: mview.MaterializedView.RefreshEvent})
+	if err != nil {
+		return err
+	}
+	info.Definer = s.Definer
+	info.Body = fmt.Sprintf("REFRESH MATERIALIZED VIEW %s.%s", quoteName(s.ViewName.Schema.O), quoteName(mview.Name.O))
+	info.Preserve = true
+	if err = setEventSchedule(sctx, info, s.Schedule); err != nil {
+		return err
+	}
+	if err = info.Validate(); err != nil {
+		return err
+	}
+	return scheduler.CreateEvent(ctx, info, true)
+}
+
+func (e *DDLExec) executeDropMaterializedView(ctx context.Context, s *ast.DropMaterializedViewStmt) error {
+	tbl, err := e.is.TableByName(s.ViewName.Schema, s.ViewName.Name)
+	if err == nil && tbl.Meta().MaterializedView != nil && tbl.Meta().MaterializedView.RefreshEvent.L != "" {
+		scheduler, err := getEventScheduler(e.Ctx())
+		if err != nil {
+			return err
+		}
+		if _, err = scheduler.DropEvent(ctx, s.ViewName.Schema.L, tbl.Meta().MaterializedView.RefreshEvent.L); err != nil {
+			return err
+		}
+	}
+	return domain.GetDomain(e.Ctx()).DDL().DropMaterializedView(e.Ctx(), s)
+}
+
+func (e *DDLExec) executeRefreshMaterializedView(ctx context.Context, s *ast.RefreshMaterializedViewStmt) error {
+	tbl, err := e.is.TableByName(s.ViewName.Schema, s.ViewName.Name)
+	if err != nil {
+		return err
+	}
+	mview := tbl.Meta().MaterializedView
+	if mview == nil {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(s.ViewName.Schema.O, s.ViewName.Name.O, "MATERIALIZED VIEW")
+	}
+	complete := s.Complete || mview.RefreshMethod == model.MViewRefreshComplete
+	return refreshMaterializedView(ctx, e.Ctx(), e.is, s.ViewName.Schema, tbl.Meta(), complete)
+}
+
+// refreshMaterializedView refreshes a materialized view in an optimistic transaction of a system session, so the
+// base tables and the change logs are read from the same snapshot. The changes committed after the snapshot are left
+// in the change logs for the next refresh.
+func refreshMaterializedView(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, dbName model.CIStr,
+	mview *model.TableInfo, complete bool) error {
+	stmt, err := parseMViewDefinition(sctx, mview.MaterializedView)
+	if err != nil {
+		return err
+	}
+	cols := make([]model.CIStr, 0, len(mview.Columns))
+	for _, col := range mview.Columns {
+		if col.State == model.StatePublic && !col.Hidden {
+			cols = append(cols, col.Name)
+		}
+	}
+	def, err := analyzeMViewDefinition(is, stmt, cols)
+	if err != nil {
+		return err
+	}
+	if !complete && def.fastUnsupported != "" {
+		return dbterror.ErrMViewFastRefreshUnsupported.GenWithStackByArgs(mview.Name.O, def.fastUnsupported)
+	}
+	stmts, err := def.refreshStmts(dbName, mview, complete)
+	if err != nil {
+		return err
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	return runWithSystemSession(ctx, sctx, func(se sessionctx.Context) error {
+		sessVars := se.GetSessionVars()
+		sqlMode := sessVars.SQLMode
+		sessVars.SQLMode = mview.MaterializedView.SQLMode
+		defer func() {
+			sessVars.SQLMode = sqlMode
+		}()
+		exec := se.GetSQLExecutor()
+		if _, err := exec.ExecuteInternal(ctx, "BEGIN OPTIMISTIC"); err != nil {
+			return err
+		}
+		if !complete {
+			changed, err := mviewLogsChanged(ctx, exec, def, mview)
+			if err != nil || !changed {
+				return err
+			}
+		}
+		for _, sql := range stmts {
+			if _, err := exec.ExecuteInternal(ctx, sql); err != nil {
+				return err
+			}
+		}
+		_, err := exec.ExecuteInternal(ctx, "COMMIT")
+		return err
+	})
+}
+
+// mviewLogsChanged returns whether any change log of the materialized view isn't empty.
+func mviewLogsChanged(ctx context.Context, exec sqlexec.SQLExecutor, def *mviewDefinition, mview *model.TableInfo) (bool, error) {
+	for _, base := range def.bases {
+		rs, err := exec.ExecuteInternal(ctx, "SELECT 1 FROM %n.%n LIMIT 1", mysql.SystemDB, base.logTableName(mview))
+		if err != nil {
+			return false, err
+		}
+		rows, err := sqlexec.DrainRecordSet(ctx, rs, 1)
+		terror.Call(rs.Close)
+		if err != nil {
+			return false, err
+		}
+		if len(rows) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -69,10 +69,14 @@ type PipelinedWindowExec struct {
 	expectedCmpResult int64
 
 	// rows keeps rows starting from curStartRow
-	rows                     []chunk.Row
-	rowCnt                   uint64
-	whole                    bool
-	isRangeFrame             bool
+	rows         []chunk.Row
+	rowCnt       uint64
+	whole        bool
+	isRangeFrame bool
+	// peerComparer is only set for GROUPS frames, and peerGroupIdx keeps the peer group
+	// indexes of the rows in e.rows.
+	peerComparer             *peerComparer
+	peerGroupIdx             []uint64
 	emptyFrame               bool
 	initializedSlidingWindow bool
 }
@@ -145,6 +149,9 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 					break
 				}
 			}
+			if e.peerComparer != nil {
+				e.updatePeerGroups(e.rowCnt + e.rowToConsume)
+			}
 			e.rowCnt += e.rowToConsume
 			e.rowToConsume = 0
 		}
@@ -248,6 +255,78 @@ func (e *PipelinedWindowExec) getRows(start, end uint64) []chunk.Row {
 	return e.rows[start-e.rowStart : end-e.rowStart]
 }
 
+func (e *PipelinedWindowExec) getPeerGroup(i uint64) uint64 {
+	return e.peerGroupIdx[i-e.rowStart]
+}
+
+// updatePeerGroups computes the peer group indexes for the rows which are going to be consumed.
+// The last consumed row is always kept in e.rows before the whole partition is consumed,
+// so it can be used to decide whether the new rows are its peers.
+func (e *PipelinedWindowExec) updatePeerGroups(rowCnt uint64) {
+	for i := e.rowCnt; i < rowCnt; i++ {
+		var group uint64
+		if i > 0 {
+			group = e.getPeerGroup(i - 1)
+			if !e.peerComparer.isPeer(e.getRow(i-1), e.getRow(i)) {
+				group++
+			}
+		}
+		e.peerGroupIdx = append(e.peerGroupIdx, group)
+	}
+}
+
+// getGroupsStart returns the first row whose peer group is not before the start bound of GROUPS frame.
+func (e *PipelinedWindowExec) getGroupsStart() uint64 {
+	group := e.getPeerGroup(e.curRowIdx)
+	if e.start.Type == ast.Preceding && group < e.start.Num {
+		return 0
+	}
+	start := max(e.lastStartRow, e.stagedStartRow)
+	for ; start < e.rowCnt; start++ {
+		g := e.getPeerGroup(start)
+		var inFrame bool
+		switch e.start.Type {
+		case ast.Preceding:
+			inFrame = g >= group-e.start.Num
+		case ast.Following:
+			inFrame = g >= group && g-group >= e.start.Num
+		default: // ast.CurrentRow
+			inFrame = g >= group
+		}
+		if inFrame {
+			break
+		}
+	}
+	e.stagedStartRow = start
+	return start
+}
+
+// getGroupsEnd returns the first row whose peer group is after the end bound of GROUPS frame.
+func (e *PipelinedWindowExec) getGroupsEnd() uint64 {
+	group := e.getPeerGroup(e.curRowIdx)
+	if e.end.Type == ast.Preceding && group < e.end.Num {
+		return 0
+	}
+	end := max(e.lastEndRow, e.stagedEndRow)
+	for ; end < e.rowCnt; end++ {
+		g := e.getPeerGroup(end)
+		var afterFrame bool
+		switch e.end.Type {
+		case ast.Preceding:
+			afterFrame = g > group-e.end.Num
+		case ast.Following:
+			afterFrame = g > group && g-group > e.end.Num
+		default: // ast.CurrentRow
+			afterFrame = g > group
+		}
+		if afterFrame {
+			break
+		}
+	}
+	e.stagedEndRow = end
+	return end
+}
+
 // finish is called upon a whole partition is consumed
 func (e *PipelinedWindowExec) finish() {
 	e.whole = true
@@ -257,6 +336,9 @@ func (e *PipelinedWindowExec) getStart(ctx sessionctx.Context) (uint64, error) {
 	if e.start.UnBounded {
 		return 0, nil
 	}
+	if e.peerComparer != nil {
+		return e.getGroupsStart(), nil
+	}
 	if e.isRangeFrame {
 		var start uint64
 		for start = max(e.lastStartRow, e.stagedStartRow); start < e.rowCnt; start++ {
@@ -297,6 +379,9 @@ func (e *PipelinedWindowExec) getEnd(ctx sessionctx.Context) (uint64, error) {
 	if e.end.UnBounded {
 		return e.rowCnt, nil
 	}
+	if e.peerComparer != nil {
+		return e.getGroupsEnd(), nil
+	}
 	if e.isRangeFrame {
 		var end uint64
 		for end = max(e.lastEndRow, e.stagedEndRow); end < e.rowCnt; end++ {
@@ -418,6 +503,9 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 		numDrop := extend - e.rowStart
 		e.dropped += numDrop
 		e.rows = e.rows[numDrop:]
+		if e.peerComparer != nil {
+			e.peerGroupIdx = e.peerGroupIdx[numDrop:]
+		}
 		e.rowStart = extend
 	}
 	return
@@ -453,6 +541,7 @@ func (e *PipelinedWindowExec) reset() {
 	numDrop := e.rowCnt - e.rowStart
 	e.dropped += numDrop
 	e.rows = e.rows[numDrop:]
+	e.peerGroupIdx = e.peerGroupIdx[:0]
 	e.rowStart = 0
 	e.rowCnt = 0
 	e.initializedSlidingWindow = false
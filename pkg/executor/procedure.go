@@ -0,0 +1,485 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+	"github.com/pingcap/tidb/pkg/util/stringutil"
+)
+
+// routineTypeProcedure is the type of the stored procedures in mysql.routines.
+const routineTypeProcedure = "PROCEDURE"
+
+// securityInvoker is the security_type of the routines executed with the privileges of the invoker.
+const securityInvoker = "INVOKER"
+
+// storedProcedure is a stored procedure persisted in mysql.routines. The definition is parsed again and interpreted
+// when the procedure is called, see procInterpreter.
+type storedProcedure struct {
+	schema      string
+	name        string
+	definition  string
+	body        string
+	paramList   string
+	definer     string
+	security    string
+	sqlMode     string
+	charset     string
+	collation   string
+	dbCollation string
+	comment     string
+	created     types.Time
+	lastAltered types.Time
+}
+
+// definerIdentity returns the user and host of the definer.
+func (p *storedProcedure) definerIdentity() (user, host string) {
+	idx := strings.LastIndex(p.definer, "@")
+	if idx < 0 {
+		return p.definer, "%"
+	}
+	return p.definer[:idx], p.definer[idx+1:]
+}
+
+// showCreate returns the result of SHOW CREATE PROCEDURE.
+func (p *storedProcedure) showCreate(sqlMode mysql.SQLMode) string {
+	var buf strings.Builder
+	buf.WriteString("CREATE ")
+	if p.definer != "" {
+		user, host := p.definerIdentity()
+		fmt.Fprintf(&buf, "DEFINER=%s@%s ", stringutil.Escape(user, sqlMode), stringutil.Escape(host, sqlMode))
+	}
+	fmt.Fprintf(&buf, "PROCEDURE %s(%s)\n", stringutil.Escape(p.name, sqlMode), p.paramList)
+	if p.security == securityInvoker {
+		buf.WriteString("    SQL SECURITY INVOKER\n")
+	}
+	if p.comment != "" {
+		fmt.Fprintf(&buf, "    COMMENT '%s'\n", format.OutputFormat(p.comment))
+	}
+	buf.WriteString(p.body)
+	return buf.String()
+}
+
+func routineCtx(ctx context.Context) context.Context {
+	return kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+}
+
+// loadStoredProcedures loads the stored procedures, the schema and the name are ignored if they are empty.
+func loadStoredProcedures(ctx context.Context, sctx sessionctx.Context, schema, name string) ([]*storedProcedure, error) {
+	var sql strings.Builder
+	sql.WriteString("SELECT route_schema, name, definition, body, param_list, definer, security_type, sql_mode, " +
+		"character_set_client, collation_connection, db_collation, comment, created, last_altered FROM mysql.routines WHERE type = %?")
+	args := []any{routineTypeProcedure}
+	if schema != "" {
+		sql.WriteString(" AND route_schema = %?")
+		args = append(args, schema)
+	}
+	if name != "" {
+		sql.WriteString(" AND name = %?")
+		args = append(args, name)
+	}
+	sql.WriteString(" ORDER BY route_schema, name")
+	rows, _, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(routineCtx(ctx), nil, sql.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	procs := make([]*storedProcedure, 0, len(rows))
+	for _, row := range rows {
+		procs = append(procs, &storedProcedure{
+			schema:      row.GetString(0),
+			name:        row.GetString(1),
+			definition:  row.GetString(2),
+			body:        row.GetString(3),
+			paramList:   row.GetString(4),
+			definer:     row.GetString(5),
+			security:    row.GetEnum(6).String(),
+			sqlMode:     row.GetString(7),
+			charset:     row.GetString(8),
+			collation:   row.GetString(9),
+			dbCollation: row.GetString(10),
+			comment:     row.GetString(11),
+			created:     row.GetTime(12),
+			lastAltered: row.GetTime(13),
+		})
+	}
+	return procs, nil
+}
+
+// loadStoredProcedure loads a stored procedure. It returns nil if the procedure doesn't exist.
+func loadStoredProcedure(ctx context.Context, sctx sessionctx.Context, schema, name string) (*storedProcedure, error) {
+	procs, err := loadStoredProcedures(ctx, sctx, schema, name)
+	if err != nil || len(procs) == 0 {
+		return nil, err
+	}
+	return procs[0], nil
+}
+
+// dropStoredProcedures drops all the stored procedures of the schema.
+func dropStoredProcedures(ctx context.Context, sctx sessionctx.Context, schema string) error {
+	_, _, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(routineCtx(ctx), nil,
+		"DELETE FROM mysql.routines WHERE route_schema = %?", schema)
+	return err
+}
+
+// isDefiner checks whether the current user is the definer of the procedure.
+func (p *storedProcedure) isDefiner(sctx sessionctx.Context) bool {
+	user := sctx.GetSessionVars().User
+	return user != nil && user.String() == p.definer
+}
+
+// procedureVisible checks whether the procedure is visible to the current user. The definer and the users who have
+// any routine privilege on the database can see the procedure.
+func procedureVisible(sctx sessionctx.Context, p *storedProcedure) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	if checker == nil || p.isDefiner(sctx) {
+		return true
+	}
+	activeRoles := sctx.GetSessionVars().ActiveRoles
+	for _, priv := range []mysql.PrivilegeType{mysql.ExecutePriv, mysql.CreateRoutinePriv, mysql.AlterRoutinePriv} {
+		if checker.RequestVerification(activeRoles, p.schema, "", "", priv) {
+			return true
+		}
+	}
+	return false
+}
+
+// procedureDefinitionVisible checks whether the definition of the procedure is visible to the current user. Only the
+// definer and the users who have the global SELECT privilege can see the definition.
+func procedureDefinitionVisible(sctx sessionctx.Context, p *storedProcedure) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	if checker == nil || p.isDefiner(sctx) {
+		return true
+	}
+	return checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, "", "", "", mysql.SelectPriv)
+}
+
+func (e *SimpleExec) executeCreateProcedure(ctx context.Context, s *ast.ProcedureInfo) error {
+	name := s.ProcedureName
+	db, ok := e.is.SchemaByName(name.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(name.Schema.O)
+	}
+	if err := checkProcedure(s); err != nil {
+		return err
+	}
+	sessVars := e.Ctx().GetSessionVars()
+	sqlMode, err := sessVars.GetSessionOrGlobalSystemVar(ctx, variable.SQLModeVar)
+	if err != nil {
+		return err
+	}
+	var definer string
+	if s.Definer != nil && !s.Definer.CurrentUser {
+		definer = s.Definer.String()
+	}
+	charset, collation := sessVars.GetCharsetInfo()
+	// The duplicate entry error is converted below, so it shouldn't be appended to the warnings.
+	_, _, err = e.Ctx().GetRestrictedSQLExecutor().ExecRestrictedSQL(routineCtx(ctx), []sqlexec.OptionFuncAlias{sqlexec.ExecOptionIgnoreWarning},
+		"INSERT INTO mysql.routines (route_schema, name, type, definition, body, param_list, definer, security_type, sql_mode, "+
+			"character_set_client, collation_connection, db_collation, comment) VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)",
+		name.Schema.L, name.Name.L, routineTypeProcedure, s.Text(), s.ProcedureBody.Text(), s.ProcedureParamStr, definer,
+		s.Security.String(), sqlMode, charset, collation, db.Collate, s.Comment)
+	if kv.ErrKeyExists.Equal(err) {
+		err = exeerrors.ErrSpAlreadyExists.GenWithStackByArgs(routineTypeProcedure, name.Name.O)
+		if s.IfNotExists {
+			sessVars.StmtCtx.AppendNote(err)
+			return nil
+		}
+	}
+	return err
+}
+
+func (e *SimpleExec) executeDropProcedure(ctx context.Context, s *ast.DropProcedureStmt) error {
+	name := s.ProcedureName
+	p, err := loadStoredProcedure(ctx, e.Ctx(), name.Schema.L, name.Name.L)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		err = exeerrors.ErrSpDoesNotExist.GenWithStackByArgs(routineTypeProcedure, name.Schema.O+"."+name.Name.O)
+		if s.IfExists {
+			e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	_, _, err = e.Ctx().GetRestrictedSQLExecutor().ExecRestrictedSQL(routineCtx(ctx), nil,
+		"DELETE FROM mysql.routines WHERE route_schema = %? AND name = %? AND type = %?", p.schema, p.name, routineTypeProcedure)
+	return err
+}
+
+func (e *ShowExec) fetchShowProcedureStatus(ctx context.Context) error {
+	procs, err := loadStoredProcedures(ctx, e.Ctx(), "", "")
+	if err != nil {
+		return err
+	}
+	for _, p := range procs {
+		if !procedureVisible(e.Ctx(), p) {
+			continue
+		}
+		e.appendRow([]any{p.schema, p.name, routineTypeProcedure, p.definer, p.lastAltered, p.created, p.security,
+			p.comment, p.charset, p.collation, p.dbCollation})
+	}
+	return nil
+}
+
+func (e *ShowExec) fetchShowCreateProcedure(ctx context.Context) error {
+	name := e.Procedure
+	p, err := loadStoredProcedure(ctx, e.Ctx(), name.Schema.L, name.Name.L)
+	if err != nil {
+		return err
+	}
+	if p == nil || !procedureVisible(e.Ctx(), p) {
+		return exeerrors.ErrSpDoesNotExist.GenWithStackByArgs(routineTypeProcedure, name.Name.O)
+	}
+	var createStmt any
+	if procedureDefinitionVisible(e.Ctx(), p) {
+		createStmt = p.showCreate(e.Ctx().GetSessionVars().SQLMode)
+	}
+	e.appendRow([]any{p.name, p.sqlMode, createStmt, p.charset, p.collation, p.dbCollation})
+	return nil
+}
+
+func (e *memtableRetriever) setDataForRoutines(ctx context.Context, sctx sessionctx.Context) error {
+	procs, err := loadStoredProcedures(ctx, sctx, "", "")
+	if err != nil {
+		return err
+	}
+	rows := make([][]types.Datum, 0, len(procs))
+	for _, p := range procs {
+		if !procedureVisible(sctx, p) {
+			continue
+		}
+		var definition any
+		if procedureDefinitionVisible(sctx, p) {
+			definition = p.body
+		}
+		record := types.MakeDatums(
+			p.name,                // SPECIFIC_NAME
+			infoschema.CatalogVal, // ROUTINE_CATALOG
+			p.schema,              // ROUTINE_SCHEMA
+			p.name,                // ROUTINE_NAME
+			routineTypeProcedure,  // ROUTINE_TYPE
+			"",                    // DATA_TYPE
+			nil,                   // CHARACTER_MAXIMUM_LENGTH
+			nil,                   // CHARACTER_OCTET_LENGTH
+			nil,                   // NUMERIC_PRECISION
+			nil,                   // NUMERIC_SCALE
+			nil,                   // DATETIME_PRECISION
+			nil,                   // CHARACTER_SET_NAME
+			nil,                   // COLLATION_NAME
+			nil,                   // DTD_IDENTIFIER
+			"SQL",                 // ROUTINE_BODY
+			definition,            // ROUTINE_DEFINITION
+			nil,                   // EXTERNAL_NAME
+			"SQL",                 // EXTERNAL_LANGUAGE
+			"SQL",                 // PARAMETER_STYLE
+			"NO",                  // IS_DETERMINISTIC
+			"CONTAINS SQL",        // SQL_DATA_ACCESS
+			nil,                   // SQL_PATH
+			p.security,            // SECURITY_TYPE
+			p.created,             // CREATED
+			p.lastAltered,         // LAST_ALTERED
+			p.sqlMode,             // SQL_MODE
+			p.comment,             // ROUTINE_COMMENT
+			p.definer,             // DEFINER
+			p.charset,             // CHARACTER_SET_CLIENT
+			p.collation,           // COLLATION_CONNECTION
+			p.dbCollation,         // DATABASE_COLLATION
+		)
+		rows = append(rows, record)
+	}
+	e.rows = rows
+	return nil
+}
+
+// procLabel is a label in the procedure body.
+type procLabel struct {
+	name   string
+	isLoop bool
+}
+
+// procChecker checks the procedure body when the procedure is created, so that the obvious mistakes are reported
+// before the procedure is called.
+type procChecker struct {
+	// scopes are the variables and the cursors declared in the enclosing blocks.
+	scopes []map[string]bool
+	labels []procLabel
+}
+
+func checkProcedure(s *ast.ProcedureInfo) error {
+	c := &procChecker{}
+	params := make(map[string]bool, len(s.ProcedureParam))
+	for _, param := range s.ProcedureParam {
+		name := strings.ToLower(param.ParamName)
+		if params[name] {
+			return exeerrors.ErrSpDupParam.GenWithStackByArgs(param.ParamName)
+		}
+		params[name] = true
+	}
+	c.scopes = append(c.scopes, params)
+	return c.checkStmt(s.ProcedureBody)
+}
+
+// cursorKey is the key of a cursor in the scope, it never conflicts with the variable names.
+func cursorKey(name string) string {
+	return "cursor:" + strings.ToLower(name)
+}
+
+func (c *procChecker) declared(key string) bool {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if c.scopes[i][key] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *procChecker) checkStmts(stmts []ast.StmtNode) error {
+	for _, stmt := range stmts {
+		if err := c.checkStmt(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *procChecker) checkBlock(block *ast.ProcedureBlock) error {
+	scope := make(map[string]bool)
+	c.scopes = append(c.scopes, scope)
+	defer func() { c.scopes = c.scopes[:len(c.scopes)-1] }()
+	for _, decl := range block.ProcedureVars {
+		switch x := decl.(type) {
+		case *ast.ProcedureDecl:
+			for _, name := range x.DeclNames {
+				if scope[name] {
+					return exeerrors.ErrSpDupVar.GenWithStackByArgs(name)
+				}
+				scope[name] = true
+			}
+		case *ast.ProcedureCursor:
+			key := cursorKey(x.CurName)
+			if scope[key] {
+				return exeerrors.ErrSpDupCurs.GenWithStackByArgs(x.CurName)
+			}
+			scope[key] = true
+		case *ast.ProcedureErrorControl:
+			if err := c.checkStmt(x.Operate); err != nil {
+				return err
+			}
+		}
+	}
+	return c.checkStmts(block.ProcedureProcStmts)
+}
+
+func (c *procChecker) checkLabel(label ast.LabelInfo) error {
+	if end, mismatch := label.GetErrorStatus(); mismatch {
+		return exeerrors.ErrSpLabelMismatch.GenWithStackByArgs(end)
+	}
+	c.labels = append(c.labels, procLabel{name: strings.ToLower(label.GetLabelName()), isLoop: !label.IsBlock()})
+	defer func() { c.labels = c.labels[:len(c.labels)-1] }()
+	return c.checkStmt(label.GetBlock())
+}
+
+func (c *procChecker) checkJump(jump *ast.ProcedureJump) error {
+	name := strings.ToLower(jump.Name)
+	for i := len(c.labels) - 1; i >= 0; i-- {
+		if c.labels[i].name == name && (jump.IsLeave || c.labels[i].isLoop) {
+			return nil
+		}
+	}
+	tp := "ITERATE"
+	if jump.IsLeave {
+		tp = "LEAVE"
+	}
+	return exeerrors.ErrSpLilabelMismatch.GenWithStackByArgs(tp, jump.Name)
+}
+
+func (c *procChecker) checkIf(block *ast.ProcedureIfBlock) error {
+	if err := c.checkStmts(block.ProcedureIfStmts); err != nil {
+		return err
+	}
+	switch x := block.ProcedureElseStmt.(type) {
+	case *ast.ProcedureElseIfBlock:
+		return c.checkIf(x.ProcedureIfStmt)
+	case *ast.ProcedureElseBlock:
+		return c.checkStmts(x.ProcedureIfStmts)
+	}
+	return nil
+}
+
+func (c *procChecker) checkStmt(stmt ast.StmtNode) error {
+	switch x := stmt.(type) {
+	case *ast.ProcedureBlock:
+		return c.checkBlock(x)
+	case *ast.ProcedureLabelBlock:
+		return c.checkLabel(x)
+	case *ast.ProcedureLabelLoop:
+		return c.checkLabel(x)
+	case *ast.ProcedureJump:
+		return c.checkJump(x)
+	case *ast.ProcedureIfInfo:
+		return c.checkIf(x.IfBody)
+	case *ast.SimpleCaseStmt:
+		for _, when := range x.WhenCases {
+			if err := c.checkStmts(when.ProcedureStmts); err != nil {
+				return err
+			}
+		}
+		return c.checkStmts(x.ElseCases)
+	case *ast.SearchCaseStmt:
+		for _, when := range x.WhenCases {
+			if err := c.checkStmts(when.ProcedureStmts); err != nil {
+				return err
+			}
+		}
+		return c.checkStmts(x.ElseCases)
+	case *ast.ProcedureWhileStmt:
+		return c.checkStmts(x.Body)
+	case *ast.ProcedureRepeatStmt:
+		return c.checkStmts(x.Body)
+	case *ast.ProcedureOpenCur:
+		if !c.declared(cursorKey(x.CurName)) {
+			return exeerrors.ErrSpCursorMismatch.GenWithStackByArgs(x.CurName)
+		}
+	case *ast.ProcedureCloseCur:
+		if !c.declared(cursorKey(x.CurName)) {
+			return exeerrors.ErrSpCursorMismatch.GenWithStackByArgs(x.CurName)
+		}
+	case *ast.ProcedureFetchInto:
+		if !c.declared(cursorKey(x.CurName)) {
+			return exeerrors.ErrSpCursorMismatch.GenWithStackByArgs(x.CurName)
+		}
+		for _, name := range x.Variables {
+			if !c.declared(strings.ToLower(name)) {
+				return exeerrors.ErrSpUndeclaredVar.GenWithStackByArgs(name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1054 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// CallProcedure executes the CALL statement.
+//
+// The body of the procedure is interpreted statement by statement, and every SQL statement in the body is executed
+// by the session like a top-level statement, with the local variables replaced by their values. Only the result set
+// of the last query in the body is returned.
+func CallProcedure(ctx context.Context, sctx sessionctx.Context, call *ast.CallStmt) (sqlexec.RecordSet, error) {
+	sessVars := sctx.GetSessionVars()
+	fn := call.Procedure
+	schema := fn.Schema.L
+	if schema == "" {
+		schema = strings.ToLower(sessVars.CurrentDB)
+	}
+	if schema == "" {
+		return nil, plannererrors.ErrNoDB
+	}
+	fullName := schema + "." + fn.FnName.O
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil && !pm.RequestVerification(sessVars.ActiveRoles, schema, "", "", mysql.ExecutePriv) {
+		var user, host string
+		if sessVars.User != nil {
+			user, host = sessVars.User.AuthUsername, sessVars.User.AuthHostname
+		}
+		return nil, exeerrors.ErrProcaccessDenied.GenWithStackByArgs("execute", user, host, fullName)
+	}
+	p, err := loadStoredProcedure(ctx, sctx, schema, fn.FnName.L)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, exeerrors.ErrSpDoesNotExist.GenWithStackByArgs(routineTypeProcedure, fullName)
+	}
+	// The procedure can be called recursively at most max_sp_recursion_depth times.
+	depthKey := schema + "." + fn.FnName.L
+	if sessVars.ProcedureCallDepth[depthKey] > sessVars.MaxSpRecursionDepth {
+		return nil, exeerrors.ErrSpRecursionLimit.GenWithStackByArgs(sessVars.MaxSpRecursionDepth, fn.FnName.O)
+	}
+	if sessVars.ProcedureCallDepth == nil {
+		sessVars.ProcedureCallDepth = make(map[string]int)
+	}
+	sessVars.ProcedureCallDepth[depthKey]++
+	defer func() {
+		if sessVars.ProcedureCallDepth[depthKey]--; sessVars.ProcedureCallDepth[depthKey] == 0 {
+			delete(sessVars.ProcedureCallDepth, depthKey)
+		}
+	}()
+	info, err := parseProcedure(sctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if len(fn.Args) != len(info.ProcedureParam) {
+		return nil, exeerrors.ErrSpWrongNoOfArgs.GenWithStackByArgs(routineTypeProcedure, fullName, len(info.ProcedureParam), len(fn.Args))
+	}
+	for idx, param := range info.ProcedureParam {
+		if param.Paramstatus == ast.MODE_IN {
+			continue
+		}
+		if v, ok := fn.Args[idx].(*ast.VariableExpr); !ok || v.IsSystem {
+			return nil, exeerrors.ErrSpNotVarArg.GenWithStackByArgs(idx+1, fullName)
+		}
+	}
+
+	i, err := newProcInterpreter(sctx, p)
+	if err != nil {
+		return nil, err
+	}
+	succ := false
+	defer func() {
+		if !succ {
+			i.close()
+		}
+	}()
+	// The arguments are evaluated in the context of the caller.
+	params, err := i.bindParams(ctx, info.ProcedureParam, fn.Args)
+	if err != nil {
+		return nil, err
+	}
+	if err = runProcedure(ctx, sctx, p, i, params, info.ProcedureBody); err != nil {
+		return nil, err
+	}
+	for idx, param := range info.ProcedureParam {
+		if param.Paramstatus == ast.MODE_IN {
+			continue
+		}
+		name := strings.ToLower(fn.Args[idx].(*ast.VariableExpr).Name)
+		v := params.vars[strings.ToLower(param.ParamName)]
+		if v.val.IsNull() {
+			sessVars.UnsetUserVar(name)
+		} else {
+			sessVars.SetUserVarVal(name, v.val)
+			sessVars.SetUserVarType(name, v.tp)
+		}
+	}
+	rs := i.resultSet()
+	// The memory of the result set is released when it's closed.
+	succ = rs != nil
+	return rs, nil
+}
+
+// runProcedure runs the body of the procedure in the context of the procedure.
+func runProcedure(ctx context.Context, sctx sessionctx.Context, p *storedProcedure, i *procInterpreter, params *procScope, body ast.StmtNode) error {
+	restore, err := enterProcedure(sctx, p)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	return i.run(ctx, params, body)
+}
+
+// parseProcedure parses the definition of the stored procedure with the SQL mode when it's created.
+func parseProcedure(sctx sessionctx.Context, p *storedProcedure) (*ast.ProcedureInfo, error) {
+	sqlMode, err := mysql.GetSQLMode(p.sqlMode)
+	if err != nil {
+		return nil, err
+	}
+	prsr := parser.New()
+	prsr.SetSQLMode(sqlMode)
+	prsr.SetParserConfig(sctx.GetSessionVars().BuildParserConfig())
+	stmt, err := prsr.ParseOneStmt(p.definition, p.charset, p.collation)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := stmt.(*ast.ProcedureInfo)
+	if !ok {
+		return nil, errors.Errorf("invalid definition of procedure %s.%s", p.schema, p.name)
+	}
+	return info, nil
+}
+
+// enterProcedure switches the session to the context of the procedure, the default database and the SQL mode are
+// the ones when the procedure is created, and the definer's privileges are used if the SQL SECURITY is DEFINER. It
+// returns a function to switch the session back.
+func enterProcedure(sctx sessionctx.Context, p *storedProcedure) (func(), error) {
+	user, host := p.definerIdentity()
+	if p.security == securityInvoker {
+		user, host = "", ""
+	}
+	return enterRoutine(sctx, p.schema, p.sqlMode, user, host)
+}
+
+// enterRoutine switches the session to the default database and the SQL mode of a stored routine or a trigger, and
+// to the privileges of the definer if the user is not empty. It returns a function to switch the session back.
+func enterRoutine(sctx sessionctx.Context, schema, sqlMode, user, host string) (func(), error) {
+	sessVars := sctx.GetSessionVars()
+	originDB := sessVars.CurrentDB
+	originSQLMode, err := sessVars.GetSessionOrGlobalSystemVar(context.Background(), variable.SQLModeVar)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessVars.SetSystemVar(variable.SQLModeVar, sqlMode); err != nil {
+		return nil, err
+	}
+	sessVars.CurrentDB = schema
+
+	originUser, originRoles := sessVars.User, sessVars.ActiveRoles
+	pm := privilege.GetPrivilegeManager(sctx)
+	switchUser := pm != nil && originUser != nil && user != ""
+	if switchUser {
+		sessVars.User = &auth.UserIdentity{Username: user, Hostname: host, AuthUsername: user, AuthHostname: host}
+		sessVars.ActiveRoles = pm.GetDefaultRoles(user, host)
+		pm.AuthSuccess(user, host)
+	}
+	return func() {
+		if switchUser {
+			sessVars.User, sessVars.ActiveRoles = originUser, originRoles
+			pm.AuthSuccess(originUser.AuthUsername, originUser.AuthHostname)
+		}
+		sessVars.CurrentDB = originDB
+		terror.Log(sessVars.SetSystemVar(variable.SQLModeVar, originSQLMode))
+	}, nil
+}
+
+// procVar is a parameter or a local variable of a procedure, or a column of the OLD or NEW row of a trigger.
+type procVar struct {
+	tp  *types.FieldType
+	val types.Datum
+	// readOnlyErr is returned when the variable is assigned by SET if it's not nil.
+	readOnlyErr error
+	// invalidErr is returned when the variable is referenced if it's not nil, e.g. the OLD row in an INSERT trigger.
+	invalidErr error
+}
+
+// procCursor is a cursor declared in a procedure. The rows are read when the cursor is opened.
+type procCursor struct {
+	stmt   ast.StmtNode
+	open   bool
+	fields []*ast.ResultField
+	rows   []chunk.Row
+	pos    int
+	// memUsage is the memory of the rows tracked by the interpreter.
+	memUsage int64
+}
+
+// procScope is the scope of a block in the procedure body, the outermost scope holds the parameters.
+type procScope struct {
+	parent   *procScope
+	vars     map[string]*procVar
+	cursors  map[string]*procCursor
+	handlers []*ast.ProcedureErrorControl
+	// inHandler is set when a handler declared in the scope is running, the errors raised by the handler can only
+	// be handled by the handlers of the outer scopes.
+	inHandler bool
+}
+
+func newProcScope(parent *procScope) *procScope {
+	return &procScope{
+		parent:  parent,
+		vars:    make(map[string]*procVar),
+		cursors: make(map[string]*procCursor),
+	}
+}
+
+func (s *procScope) lookupVar(name string) *procVar {
+	name = strings.ToLower(name)
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *procScope) lookupCursor(name string) *procCursor {
+	name = strings.ToLower(name)
+	for ; s != nil; s = s.parent {
+		if c, ok := s.cursors[name]; ok {
+			return c
+		}
+	}
+	return nil
+}
+
+// Handler priorities, a handler for the specific error code is preferred to the one for the SQLSTATE value, and the
+// one for the SQLSTATE value is preferred to the one for the condition class.
+const (
+	handlerMatchNone = iota
+	handlerMatchClass
+	handlerMatchState
+	handlerMatchCode
+)
+
+func matchHandlerCondition(cond ast.ErrNode, sqlErr *mysql.SQLError) int {
+	switch x := cond.(type) {
+	case *ast.ProcedureErrorVal:
+		if x.ErrorNum == uint64(sqlErr.Code) {
+			return handlerMatchCode
+		}
+	case *ast.ProcedureErrorState:
+		if x.CodeStatus == sqlErr.State {
+			return handlerMatchState
+		}
+	case *ast.ProcedureErrorCon:
+		class := sqlErr.State
+		if len(class) > 2 {
+			class = class[:2]
+		}
+		var matched bool
+		switch x.ErrorCon {
+		case ast.PROCEDUR_SQLWARNING:
+			matched = class == "01"
+		case ast.PROCEDUR_NOT_FOUND:
+			matched = class == "02"
+		case ast.PROCEDUR_SQLEXCEPTION:
+			matched = class != "00" && class != "01" && class != "02"
+		}
+		if matched {
+			return handlerMatchClass
+		}
+	}
+	return handlerMatchNone
+}
+
+// findHandler finds the handler declared in the scope which handles the error.
+func (s *procScope) findHandler(sqlErr *mysql.SQLError) *ast.ProcedureErrorControl {
+	var handler *ast.ProcedureErrorControl
+	best := handlerMatchNone
+	for _, h := range s.handlers {
+		for _, cond := range h.ErrorCon {
+			if match := matchHandlerCondition(cond, sqlErr); match > best {
+				handler, best = h, match
+			}
+		}
+	}
+	return handler
+}
+
+// procJump is raised by LEAVE and ITERATE, and it's caught by the statement with the label.
+type procJump struct {
+	label string
+	leave bool
+}
+
+func (j *procJump) Error() string {
+	return fmt.Sprintf("jump to label %s", j.label)
+}
+
+// procExit is raised when an EXIT handler finishes, and it's caught by the block declaring the handler.
+type procExit struct {
+	scope *procScope
+}
+
+func (*procExit) Error() string {
+	return "exit from block"
+}
+
+// procUnhandledError wraps the error which can't be handled by any handler, so that the enclosing statements don't
+// look up the handlers again.
+type procUnhandledError struct {
+	err error
+}
+
+func (e *procUnhandledError) Error() string {
+	return e.err.Error()
+}
+
+// isKilledErr checks whether the error is raised by killing the statement, which can't be handled by the handlers.
+func isKilledErr(err error) bool {
+	return exeerrors.ErrQueryInterrupted.Equal(err) || exeerrors.ErrMaxExecTimeExceeded.Equal(err) ||
+		exeerrors.ErrMemoryExceedForQuery.Equal(err) || exeerrors.ErrMemoryExceedForInstance.Equal(err)
+}
+
+func toSQLError(err error) *mysql.SQLError {
+	if tErr, ok := errors.Cause(err).(*terror.Error); ok {
+		return terror.ToSQLError(tErr)
+	}
+	return mysql.NewErrf(mysql.ErrUnknown, "%s", nil, err.Error())
+}
+
+// procInterpreter interprets the body of a stored procedure.
+type procInterpreter struct {
+	sctx   sessionctx.Context
+	parser *parser.Parser
+	// charset and collation are the default ones of the database, they are used by the string variables.
+	charset   string
+	collation string
+	// sqls caches the SQL built from the statements and the expressions in the body.
+	sqls map[ast.Node]string
+	// execNested executes a statement in the body of a trigger within the statement which activates the trigger.
+	// It's nil for stored procedures, whose statements are executed by the session like top-level statements.
+	execNested func(ctx context.Context, stmt ast.StmtNode) ([]*ast.ResultField, []chunk.Row, error)
+
+	resultFields []*ast.ResultField
+	resultRows   []chunk.Row
+	// memTracker tracks the memory of the rows of the opened cursors and the result set, which are held across the
+	// statements in the body.
+	memTracker     *memory.Tracker
+	resultMemUsage int64
+}
+
+func newProcInterpreter(sctx sessionctx.Context, p *storedProcedure) (*procInterpreter, error) {
+	sqlMode, err := mysql.GetSQLMode(p.sqlMode)
+	if err != nil {
+		return nil, err
+	}
+	prsr := parser.New()
+	prsr.SetSQLMode(sqlMode)
+	prsr.SetParserConfig(sctx.GetSessionVars().BuildParserConfig())
+	i := &procInterpreter{
+		sctx:      sctx,
+		parser:    prsr,
+		charset:   mysql.DefaultCharset,
+		collation: mysql.DefaultCollationName,
+		sqls:      make(map[ast.Node]string),
+	}
+	i.memTracker = memory.NewTracker(memory.LabelForStoredRoutine, -1)
+	i.memTracker.AttachTo(sctx.GetSessionVars().MemTracker)
+	if coll, err := charset.GetCollationByName(p.dbCollation); err == nil {
+		i.charset, i.collation = coll.CharsetName, coll.Name
+	}
+	return i, nil
+}
+
+// fieldType returns the type of a parameter or a local variable.
+func (i *procInterpreter) fieldType(tp *types.FieldType) *types.FieldType {
+	tp = tp.Clone()
+	flen, decimal := mysql.GetDefaultFieldLengthAndDecimal(tp.GetType())
+	if tp.GetFlen() == types.UnspecifiedLength {
+		tp.SetFlen(flen)
+	}
+	if tp.GetDecimal() == types.UnspecifiedLength {
+		tp.SetDecimal(decimal)
+	}
+	if tp.GetCharset() == "" {
+		if types.IsString(tp.GetType()) {
+			tp.SetCharset(i.charset)
+			tp.SetCollate(i.collation)
+		} else {
+			tp.SetCharset(charset.CharsetBin)
+			tp.SetCollate(charset.CollationBin)
+		}
+	}
+	return tp
+}
+
+// bindParams builds the outermost scope with the parameters.
+func (i *procInterpreter) bindParams(ctx context.Context, params []*ast.StoreParameter, args []ast.ExprNode) (*procScope, error) {
+	scope := newProcScope(nil)
+	var values []types.Datum
+	if len(args) > 0 {
+		sql, err := i.sqlOf(args[0], func(sb *strings.Builder) error {
+			sb.WriteString("SELECT ")
+			for idx, arg := range args {
+				if idx > 0 {
+					sb.WriteString(", ")
+				}
+				if err := restoreProcNode(sb, arg); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		fields, rows, err := i.query(ctx, scope, sql)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range args {
+			values = append(values, rows[0].GetDatum(idx, &fields[idx].Column.FieldType))
+		}
+	}
+	for idx, param := range params {
+		v := &procVar{tp: i.fieldType(param.ParamType)}
+		if param.Paramstatus != ast.MODE_OUT {
+			if err := i.assign(v, values[idx]); err != nil {
+				return nil, err
+			}
+		}
+		scope.vars[strings.ToLower(param.ParamName)] = v
+	}
+	return scope, nil
+}
+
+func (i *procInterpreter) run(ctx context.Context, params *procScope, body ast.StmtNode) error {
+	err := i.execStmts(ctx, params, []ast.StmtNode{body})
+	if unhandled, ok := err.(*procUnhandledError); ok {
+		return unhandled.err
+	}
+	return err
+}
+
+func (i *procInterpreter) resultSet() sqlexec.RecordSet {
+	if i.resultFields == nil {
+		return nil
+	}
+	return &procRecordSet{
+		fields:       i.resultFields,
+		rows:         i.resultRows,
+		maxChunkSize: i.sctx.GetSessionVars().MaxChunkSize,
+		onClose:      i.close,
+	}
+}
+
+// trackMemory changes the memory tracked by the interpreter, and it returns an error if the memory quota is exceeded.
+func (i *procInterpreter) trackMemory(delta int64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = util.GetRecoverError(r)
+		}
+	}()
+	i.memTracker.Consume(delta)
+	return nil
+}
+
+// close releases the memory of the rows held by the interpreter of a stored procedure.
+func (i *procInterpreter) close() {
+	i.sctx.GetSessionVars().MemTracker.ReplaceChild(i.memTracker, nil)
+}
+
+// rowsMemUsage returns the memory usage of the chunks holding the rows.
+func rowsMemUsage(rows []chunk.Row) int64 {
+	var (
+		sum  int64
+		last *chunk.Chunk
+	)
+	for _, row := range rows {
+		if c := row.Chunk(); c != last {
+			sum += c.MemoryUsage()
+			last = c
+		}
+	}
+	return sum
+}
+
+func (i *procInterpreter) execStmts(ctx context.Context, scope *procScope, stmts []ast.StmtNode) error {
+	for _, stmt := range stmts {
+		if err := i.execStmt(ctx, scope, stmt); err != nil {
+			if err = i.handleError(ctx, scope, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// handleError looks up the handler for the error from the innermost scope to the outermost one. It returns nil if
+// the error is handled by a CONTINUE handler, so that the execution continues with the next statement.
+func (i *procInterpreter) handleError(ctx context.Context, scope *procScope, err error) error {
+	switch err.(type) {
+	case *procJump, *procExit, *procUnhandledError:
+		return err
+	}
+	if isKilledErr(err) {
+		return &procUnhandledError{err: err}
+	}
+	sqlErr := toSQLError(err)
+	for s := scope; s != nil; s = s.parent {
+		if s.inHandler {
+			continue
+		}
+		h := s.findHandler(sqlErr)
+		if h == nil {
+			continue
+		}
+		s.inHandler = true
+		err = i.execStmts(ctx, s, []ast.StmtNode{h.Operate})
+		s.inHandler = false
+		if err != nil {
+			return err
+		}
+		if h.ControlHandle == ast.PROCEDUR_EXIT {
+			return &procExit{scope: s}
+		}
+		return nil
+	}
+	return &procUnhandledError{err: err}
+}
+
+func (i *procInterpreter) execStmt(ctx context.Context, scope *procScope, stmt ast.StmtNode) error {
+	switch x := stmt.(type) {
+	case *ast.ProcedureBlock:
+		return i.execBlock(ctx, scope, x)
+	case *ast.ProcedureLabelBlock:
+		err := i.execBlock(ctx, scope, x.Block)
+		if jump, ok := err.(*procJump); ok && jump.leave && jump.label == strings.ToLower(x.LabelName) {
+			return nil
+		}
+		return err
+	case *ast.ProcedureLabelLoop:
+		return i.execLoop(ctx, scope, x.Block, strings.ToLower(x.LabelName))
+	case *ast.ProcedureWhileStmt, *ast.ProcedureRepeatStmt:
+		return i.execLoop(ctx, scope, x, "")
+	case *ast.ProcedureJump:
+		return &procJump{label: strings.ToLower(x.Name), leave: x.IsLeave}
+	case *ast.ProcedureIfInfo:
+		return i.execIf(ctx, scope, x.IfBody)
+	case *ast.SimpleCaseStmt:
+		return i.execSimpleCase(ctx, scope, x)
+	case *ast.SearchCaseStmt:
+		return i.execSearchCase(ctx, scope, x)
+	case *ast.ProcedureOpenCur:
+		return i.openCursor(ctx, scope, x.CurName)
+	case *ast.ProcedureFetchInto:
+		return i.fetchCursor(scope, x)
+	case *ast.ProcedureCloseCur:
+		c := scope.lookupCursor(x.CurName)
+		if c == nil {
+			return exeerrors.ErrSpCursorMismatch.GenWithStackByArgs(x.CurName)
+		}
+		if !c.open {
+			return exeerrors.ErrSpCursorNotOpen
+		}
+		c.open, c.fields, c.rows = false, nil, nil
+		return i.trackMemory(-c.memUsage)
+	case *ast.SetStmt:
+		return i.execSet(ctx, scope, x)
+	}
+	sql, err := i.sqlOf(stmt, func(sb *strings.Builder) error {
+		return restoreProcNode(sb, stmt)
+	})
+	if err != nil {
+		return err
+	}
+	fields, rows, err := i.query(ctx, scope, sql)
+	if err != nil {
+		return err
+	}
+	if fields != nil {
+		if i.execNested != nil {
+			return exeerrors.ErrSpNoRetset.GenWithStackByArgs("trigger")
+		}
+		memUsage := rowsMemUsage(rows)
+		if err := i.trackMemory(memUsage - i.resultMemUsage); err != nil {
+			return err
+		}
+		i.resultFields, i.resultRows, i.resultMemUsage = fields, rows, memUsage
+	}
+	return nil
+}
+
+func (i *procInterpreter) execBlock(ctx context.Context, parent *procScope, block *ast.ProcedureBlock) error {
+	scope := newProcScope(parent)
+	for _, decl := range block.ProcedureVars {
+		switch x := decl.(type) {
+		case *ast.ProcedureDecl:
+			var val types.Datum
+			if x.DeclDefault != nil {
+				d, err := i.evalExpr(ctx, scope, x.DeclDefault)
+				if err != nil {
+					return err
+				}
+				val = d
+			}
+			for _, name := range x.DeclNames {
+				v := &procVar{tp: i.fieldType(x.DeclType)}
+				if err := i.assign(v, val); err != nil {
+					return err
+				}
+				scope.vars[name] = v
+			}
+		case *ast.ProcedureCursor:
+			scope.cursors[strings.ToLower(x.CurName)] = &procCursor{stmt: x.Selectstring}
+		case *ast.ProcedureErrorControl:
+			scope.handlers = append(scope.handlers, x)
+		}
+	}
+	err := i.execStmts(ctx, scope, block.ProcedureProcStmts)
+	if exit, ok := err.(*procExit); ok && exit.scope == scope {
+		return nil
+	}
+	return err
+}
+
+// execLoop executes a WHILE or REPEAT loop, the label is empty if the loop has no label.
+func (i *procInterpreter) execLoop(ctx context.Context, scope *procScope, loop ast.StmtNode, label string) error {
+	// done checks whether the loop is finished by the error raised by the body.
+	done := func(err error) (bool, error) {
+		if jump, ok := err.(*procJump); ok && label != "" && jump.label == label {
+			return jump.leave, nil
+		}
+		return err != nil, err
+	}
+	for {
+		switch x := loop.(type) {
+		case *ast.ProcedureWhileStmt:
+			ok, err := i.evalCond(ctx, scope, x.Condition)
+			if err != nil || !ok {
+				return err
+			}
+			if finished, err := done(i.execStmts(ctx, scope, x.Body)); finished {
+				return err
+			}
+		case *ast.ProcedureRepeatStmt:
+			finished, err := done(i.execStmts(ctx, scope, x.Body))
+			if finished {
+				return err
+			}
+			if err == nil {
+				ok, err := i.evalCond(ctx, scope, x.Condition)
+				if err != nil || ok {
+					return err
+				}
+			}
+		default:
+			return errors.Errorf("unsupported loop %T", loop)
+		}
+	}
+}
+
+func (i *procInterpreter) execIf(ctx context.Context, scope *procScope, block *ast.ProcedureIfBlock) error {
+	ok, err := i.evalCond(ctx, scope, block.IfExpr)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return i.execStmts(ctx, scope, block.ProcedureIfStmts)
+	}
+	switch x := block.ProcedureElseStmt.(type) {
+	case *ast.ProcedureElseIfBlock:
+		return i.execIf(ctx, scope, x.ProcedureIfStmt)
+	case *ast.ProcedureElseBlock:
+		return i.execStmts(ctx, scope, x.ProcedureIfStmts)
+	}
+	return nil
+}
+
+func (i *procInterpreter) execSimpleCase(ctx context.Context, scope *procScope, stmt *ast.SimpleCaseStmt) error {
+	for _, when := range stmt.WhenCases {
+		sql, err := i.sqlOf(when, func(sb *strings.Builder) error {
+			sb.WriteString("SELECT (")
+			if err := restoreProcNode(sb, stmt.Condition); err != nil {
+				return err
+			}
+			sb.WriteString(") = (")
+			if err := restoreProcNode(sb, when.Expr); err != nil {
+				return err
+			}
+			sb.WriteString(")")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		ok, err := i.evalCondSQL(ctx, scope, sql)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return i.execStmts(ctx, scope, when.ProcedureStmts)
+		}
+	}
+	if stmt.ElseCases == nil {
+		return exeerrors.ErrSpCaseNotFound
+	}
+	return i.execStmts(ctx, scope, stmt.ElseCases)
+}
+
+func (i *procInterpreter) execSearchCase(ctx context.Context, scope *procScope, stmt *ast.SearchCaseStmt) error {
+	for _, when := range stmt.WhenCases {
+		ok, err := i.evalCond(ctx, scope, when.Expr)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return i.execStmts(ctx, scope, when.ProcedureStmts)
+		}
+	}
+	if stmt.ElseCases == nil {
+		return exeerrors.ErrSpCaseNotFound
+	}
+	return i.execStmts(ctx, scope, stmt.ElseCases)
+}
+
+func (i *procInterpreter) openCursor(ctx context.Context, scope *procScope, name string) error {
+	c := scope.lookupCursor(name)
+	if c == nil {
+		return exeerrors.ErrSpCursorMismatch.GenWithStackByArgs(name)
+	}
+	if c.open {
+		return exeerrors.ErrSpCursorAlreadyOpen
+	}
+	sql, err := i.sqlOf(c.stmt, func(sb *strings.Builder) error {
+		return restoreProcNode(sb, c.stmt)
+	})
+	if err != nil {
+		return err
+	}
+	fields, rows, err := i.query(ctx, scope, sql)
+	if err != nil {
+		return err
+	}
+	c.memUsage = rowsMemUsage(rows)
+	if err := i.trackMemory(c.memUsage); err != nil {
+		return err
+	}
+	c.open, c.fields, c.rows, c.pos = true, fields, rows, 0
+	return nil
+}
+
+func (i *procInterpreter) fetchCursor(scope *procScope, stmt *ast.ProcedureFetchInto) error {
+	c := scope.lookupCursor(stmt.CurName)
+	if c == nil {
+		return exeerrors.ErrSpCursorMismatch.GenWithStackByArgs(stmt.CurName)
+	}
+	if !c.open {
+		return exeerrors.ErrSpCursorNotOpen
+	}
+	if len(stmt.Variables) != len(c.fields) {
+		return exeerrors.ErrSpWrongNoOfFetchArgs
+	}
+	if c.pos >= len(c.rows) {
+		return exeerrors.ErrSpFetchNoData
+	}
+	row := c.rows[c.pos]
+	c.pos++
+	for idx, name := range stmt.Variables {
+		v := scope.lookupVar(name)
+		if v == nil {
+			return exeerrors.ErrSpUndeclaredVar.GenWithStackByArgs(name)
+		}
+		if err := i.assign(v, row.GetDatum(idx, &c.fields[idx].Column.FieldType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execSet executes the SET statement, the local variables are assigned by the interpreter and the others are set by
+// the session.
+func (i *procInterpreter) execSet(ctx context.Context, scope *procScope, stmt *ast.SetStmt) error {
+	for _, assignment := range stmt.Variables {
+		if assignment.IsSystem && !assignment.IsGlobal {
+			if v := scope.lookupVar(assignment.Name); v != nil {
+				if v.invalidErr != nil {
+					return v.invalidErr
+				}
+				if v.readOnlyErr != nil {
+					return v.readOnlyErr
+				}
+				d, err := i.evalExpr(ctx, scope, assignment.Value)
+				if err != nil {
+					return err
+				}
+				if err := i.assign(v, d); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		sql, err := i.sqlOf(assignment, func(sb *strings.Builder) error {
+			sb.WriteString("SET ")
+			return restoreProcNode(sb, assignment)
+		})
+		if err != nil {
+			return err
+		}
+		if _, _, err := i.query(ctx, scope, sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assign converts the value to the type of the variable and assigns it to the variable.
+func (i *procInterpreter) assign(v *procVar, d types.Datum) error {
+	if d.IsNull() {
+		v.val.SetNull()
+		return nil
+	}
+	sessVars := i.sctx.GetSessionVars()
+	tc := types.NewContext(types.DefaultStmtFlags, sessVars.Location(), sessVars.StmtCtx)
+	val, err := d.ConvertTo(tc, v.tp)
+	if err != nil {
+		if sessVars.SQLMode.HasStrictMode() {
+			return err
+		}
+		tc.AppendWarning(err)
+	}
+	v.val = val
+	return nil
+}
+
+func (i *procInterpreter) evalExpr(ctx context.Context, scope *procScope, expr ast.ExprNode) (types.Datum, error) {
+	sql, err := i.sqlOf(expr, func(sb *strings.Builder) error {
+		sb.WriteString("SELECT ")
+		return restoreProcNode(sb, expr)
+	})
+	if err != nil {
+		return types.Datum{}, err
+	}
+	return i.evalSQL(ctx, scope, sql)
+}
+
+func (i *procInterpreter) evalSQL(ctx context.Context, scope *procScope, sql string) (types.Datum, error) {
+	fields, rows, err := i.query(ctx, scope, sql)
+	if err != nil || len(rows) == 0 {
+		return types.Datum{}, err
+	}
+	return rows[0].GetDatum(0, &fields[0].Column.FieldType), nil
+}
+
+// evalCond evaluates the condition, NULL is regarded as false.
+func (i *procInterpreter) evalCond(ctx context.Context, scope *procScope, expr ast.ExprNode) (bool, error) {
+	sql, err := i.sqlOf(expr, func(sb *strings.Builder) error {
+		sb.WriteString("SELECT ")
+		return restoreProcNode(sb, expr)
+	})
+	if err != nil {
+		return false, err
+	}
+	return i.evalCondSQL(ctx, scope, sql)
+}
+
+func (i *procInterpreter) evalCondSQL(ctx context.Context, scope *procScope, sql string) (bool, error) {
+	d, err := i.evalSQL(ctx, scope, sql)
+	if err != nil || d.IsNull() {
+		return false, err
+	}
+	sessVars := i.sctx.GetSessionVars()
+	tc := types.NewContext(types.DefaultStmtFlags|types.FlagTruncateAsWarning, sessVars.Location(), sessVars.StmtCtx)
+	v, err := d.ToBool(tc)
+	return v != 0, err
+}
+
+// sqlOf returns the SQL built from the node, the SQL is cached because the node may be executed many times.
+func (i *procInterpreter) sqlOf(node ast.Node, build func(sb *strings.Builder) error) (string, error) {
+	if sql, ok := i.sqls[node]; ok {
+		return sql, nil
+	}
+	var sb strings.Builder
+	if err := build(&sb); err != nil {
+		return "", err
+	}
+	i.sqls[node] = sb.String()
+	return sb.String(), nil
+}
+
+func restoreProcNode(sb *strings.Builder, node ast.Node) error {
+	return node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags|format.RestoreStringWithoutDefaultCharset, sb))
+}
+
+// query parses the SQL, replaces the local variables with their values and executes it. The fields are nil if the
+// statement returns no result set.
+func (i *procInterpreter) query(ctx context.Context, scope *procScope, sql string) ([]*ast.ResultField, []chunk.Row, error) {
+	sessVars := i.sctx.GetSessionVars()
+	// The kill signal is reset when the next statement starts, so it must be checked before it.
+	if err := sessVars.SQLKiller.HandleSignal(); err != nil {
+		return nil, nil, err
+	}
+	charset, collation := sessVars.GetCharsetInfo()
+	stmt, err := i.parser.ParseOneStmt(sql, charset, collation)
+	if err != nil {
+		return nil, nil, err
+	}
+	replacer := &procVarReplacer{scope: scope}
+	stmt.Accept(replacer)
+	if replacer.err != nil {
+		return nil, nil, replacer.err
+	}
+	if i.execNested != nil {
+		return i.execNested(ctx, stmt)
+	}
+	rs, err := i.sctx.GetSQLExecutor().ExecuteStmt(ctx, stmt)
+	if err != nil || rs == nil {
+		return nil, nil, err
+	}
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, sessVars.MaxChunkSize)
+	if closeErr := rs.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return rs.Fields(), rows, nil
+}
+
+// procVarReplacer replaces the references of the local variables in a statement with their values.
+type procVarReplacer struct {
+	scope *procScope
+	err   error
+}
+
+func (r *procVarReplacer) lookup(node ast.Node) *procVar {
+	col, ok := node.(*ast.ColumnNameExpr)
+	if !ok || col.Name.Schema.L != "" {
+		return nil
+	}
+	if col.Name.Table.L != "" {
+		// The columns of the OLD and NEW rows of a trigger are referenced like `NEW.a`.
+		return r.scope.lookupVar(col.Name.Table.L + "." + col.Name.Name.L)
+	}
+	return r.scope.lookupVar(col.Name.Name.L)
+}
+
+// Enter implements ast.Visitor interface.
+func (r *procVarReplacer) Enter(n ast.Node) (ast.Node, bool) {
+	switch x := n.(type) {
+	case *ast.ValuesExpr:
+		// The argument of VALUES() is always a column.
+		return n, true
+	case *ast.SelectField:
+		// Keep the name of the result column.
+		if x.AsName.L == "" && r.lookup(x.Expr) != nil {
+			x.AsName = x.Expr.(*ast.ColumnNameExpr).Name.Name
+		}
+	}
+	return n, false
+}
+
+// Leave implements ast.Visitor interface.
+func (r *procVarReplacer) Leave(n ast.Node) (ast.Node, bool) {
+	if v := r.lookup(n); v != nil {
+		if v.invalidErr != nil && r.err == nil {
+			r.err = v.invalidErr
+		}
+		return ast.NewValueExpr(v.val.GetValue(), v.tp.GetCharset(), v.tp.GetCollate()), true
+	}
+	return n, true
+}
+
+// procRecordSet is the result set of the last query in the procedure.
+type procRecordSet struct {
+	fields       []*ast.ResultField
+	rows         []chunk.Row
+	maxChunkSize int
+	idx          int
+	onClose      func()
+}
+
+// Fields implements the sqlexec.RecordSet interface.
+func (r *procRecordSet) Fields() []*ast.ResultField {
+	return r.fields
+}
+
+// Next implements the sqlexec.RecordSet interface.
+func (r *procRecordSet) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for r.idx < len(r.rows) && !req.IsFull() {
+		req.AppendRow(r.rows[r.idx])
+		r.idx++
+	}
+	return nil
+}
+
+// NewChunk implements the sqlexec.RecordSet interface.
+func (r *procRecordSet) NewChunk(alloc chunk.Allocator) *chunk.Chunk {
+	fields := make([]*types.FieldType, 0, len(r.fields))
+	for _, field := range r.fields {
+		fields = append(fields, &field.Column.FieldType)
+	}
+	if alloc != nil {
+		return alloc.Alloc(fields, 0, r.maxChunkSize)
+	}
+	return chunk.New(fields, r.maxChunkSize, r.maxChunkSize)
+}
+
+// Close implements the sqlexec.RecordSet interface.
+func (r *procRecordSet) Close() error {
+	if r.onClose != nil {
+		r.onClose()
+		r.onClose = nil
+	}
+	return nil
+}
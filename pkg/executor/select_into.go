@@ -18,17 +18,41 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
 	"math"
 	"os"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/executor/importer"
 	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/lightning/config"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
 	"github.com/pingcap/tidb/pkg/planner/core"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	// maxFileSizeOption is the option of SELECT INTO OUTFILE to roll over the output to multiple files, each of which
+	// is no larger than the given uncompressed size unless a single row is larger than it.
+	maxFileSizeOption = "max_file_size"
+	// compressOption is the option of SELECT INTO OUTFILE to compress the output, which can be 'gzip', 'snappy'
+	// or 'zstd'. The output is never compressed unless the option is specified, whatever the file extension is.
+	compressOption = "compress"
+	// selectIntoChunkSize is the buffer size of the compressed writer of the output file.
+	selectIntoChunkSize = 5 * 1024 * 1024
+	// defaultParquetRowGroupSize is the row group size of the parquet output, which is also the memory used to
+	// buffer the rows of a parquet file.
+	defaultParquetRowGroupSize = 64 * 1024 * 1024
 )
 
 // SelectIntoExec represents a SelectInto executor.
@@ -36,16 +60,59 @@ type SelectIntoExec struct {
 	exec.BaseExecutor
 	intoOpt *ast.SelectIntoOption
 	core.LineFieldsInfo
+	options  []*core.LoadDataOpt
+	colNames []string
+
+	format      string
+	maxFileSize int64
 
 	lineBuf   []byte
 	realBuf   []byte
 	fieldBuf  []byte
 	escapeBuf []byte
 	enclosed  bool
-	writer    *bufio.Writer
-	dstFile   *os.File
 	chk       *chunk.Chunk
 	started   bool
+
+	// store is nil when the output is written to the disk of TiDB server.
+	store       storage.ExternalStorage
+	fileName    string
+	compression storage.CompressType
+	fileSeq     int
+	// fileSize is the uncompressed size of the data written to the current file.
+	fileSize int64
+	dst      *outfileWriter
+	// writer is used by the csv format.
+	writer *bufio.Writer
+	// parquetWriter is used by the parquet format.
+	parquetWriter *writer.CSVWriter
+}
+
+// outfileWriter adapts storage.ExternalFileWriter to io.Writer.
+type outfileWriter struct {
+	ctx context.Context
+	w   storage.ExternalFileWriter
+}
+
+func (w *outfileWriter) Write(p []byte) (int, error) {
+	return w.w.Write(w.ctx, p)
+}
+
+func (w *outfileWriter) Close() error {
+	return w.w.Close(w.ctx)
+}
+
+// serverFileWriter writes a file on the disk of TiDB server.
+type serverFileWriter struct {
+	f *os.File
+}
+
+func (w *serverFileWriter) Write(_ context.Context, p []byte) (int, error) {
+	return w.f.Write(p)
+}
+
+func (w *serverFileWriter) Close(_ context.Context) error {
+	return w.f.Close()
 }
 
 // Open implements the Executor Open interface.
@@ -54,15 +121,21 @@ func (s *SelectIntoExec) Open(ctx context.Context) error {
 	if s.intoOpt.Tp != ast.SelectIntoOutfile {
 		return errors.New("unsupported SelectInto type")
 	}
-
-	// MySQL-compatible behavior: allow files to be group-readable
-	f, err := os.OpenFile(s.intoOpt.FileName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0640) // #nosec G302
-	if err != nil {
-		return errors.Trace(err)
+	if err := s.initOptions(); err != nil {
+		return err
+	}
+	if err := s.initStore(ctx); err != nil {
+		return err
+	}
+	if err := s.openFile(ctx); err != nil {
+		terror.Log(s.closeFile())
+		if s.store != nil {
+			s.store.Close()
+			s.store = nil
+		}
+		return err
 	}
 	s.started = true
-	s.dstFile = f
-	s.writer = bufio.NewWriter(s.dstFile)
 	s.chk = exec.TryNewCacheChunk(s.Children(0))
 	s.lineBuf = make([]byte, 0, 1024)
 	s.fieldBuf = make([]byte, 0, 64)
@@ -70,6 +143,204 @@ func (s *SelectIntoExec) Open(ctx context.Context) error {
 	return s.BaseExecutor.Open(ctx)
 }
 
+func (s *SelectIntoExec) initOptions() error {
+	s.format = importer.DataFormatCSV
+	if s.intoOpt.Format != nil {
+		s.format = strings.ToLower(*s.intoOpt.Format)
+	}
+	switch s.format {
+	case importer.DataFormatCSV:
+	case importer.DataFormatParquet:
+		if s.intoOpt.FieldsInfo != nil {
+			return exeerrors.ErrLoadDataUnsupportedOption.FastGenByArgs("FIELDS", "non-CSV format")
+		}
+		if s.intoOpt.LinesInfo != nil {
+			return exeerrors.ErrLoadDataUnsupportedOption.FastGenByArgs("LINES", "non-CSV format")
+		}
+	default:
+		return exeerrors.ErrLoadDataUnsupportedFormat.GenWithStackByArgs(s.format)
+	}
+
+	specifiedOptions := make(map[string]*core.LoadDataOpt, len(s.options))
+	for _, opt := range s.options {
+		if opt.Name != maxFileSizeOption && opt.Name != compressOption {
+			return exeerrors.ErrUnknownOption.FastGenByArgs(opt.Name)
+		}
+		if opt.Value == nil {
+			return exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		if _, ok := specifiedOptions[opt.Name]; ok {
+			return exeerrors.ErrDuplicateOption.FastGenByArgs(opt.Name)
+		}
+		specifiedOptions[opt.Name] = opt
+	}
+	if opt, ok := specifiedOptions[maxFileSizeOption]; ok {
+		if opt.Value.GetType().GetType() != mysql.TypeVarString {
+			return exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		v, isNull, err := opt.Value.EvalString(s.Ctx().GetExprCtx().GetEvalCtx(), chunk.Row{})
+		if err != nil || isNull {
+			return exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		var size config.ByteSize
+		if err = size.UnmarshalText([]byte(v)); err != nil || size <= 0 {
+			return exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		s.maxFileSize = int64(size)
+	}
+	if opt, ok := specifiedOptions[compressOption]; ok {
+		if opt.Value.GetType().GetType() != mysql.TypeVarString {
+			return exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		v, isNull, err := opt.Value.EvalString(s.Ctx().GetExprCtx().GetEvalCtx(), chunk.Row{})
+		if err != nil || isNull {
+			return exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		switch strings.ToLower(v) {
+		case "gzip":
+			s.compression = storage.Gzip
+		case "snappy":
+			s.compression = storage.Snappy
+		case "zstd":
+			s.compression = storage.Zstd
+		default:
+			return exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		if s.format == importer.DataFormatParquet {
+			return exeerrors.ErrLoadDataUnsupportedOption.FastGenByArgs(opt.Name, "parquet format")
+		}
+	}
+	return nil
+}
+
+// initStore initializes the external storage of the output, and leaves it nil if the output is on the disk of TiDB
+// server.
+func (s *SelectIntoExec) initStore(ctx context.Context) error {
+	u, err := storage.ParseRawURL(s.intoOpt.FileName)
+	if err != nil {
+		return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs(core.SelectIntoTarget, err.Error())
+	}
+	if storage.IsLocal(u) {
+		s.fileName = s.intoOpt.FileName
+	} else {
+		s.fileName = strings.Trim(u.Path, "/")
+		if s.fileName == "" {
+			return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs(core.SelectIntoTarget, "file name is empty")
+		}
+		u.Path = ""
+		backend, err := storage.ParseBackendFromURL(u, nil)
+		if err != nil {
+			return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs(core.SelectIntoTarget, importer.GetMsgFromBRError(err))
+		}
+		s.store, err = storage.NewWithDefaultOpt(ctx, backend)
+		if err != nil {
+			return exeerrors.ErrLoadDataCantAccess.GenWithStackByArgs(core.SelectIntoTarget, importer.GetMsgFromBRError(err))
+		}
+	}
+	return nil
+}
+
+// nextFileName returns the name of the next output file. When the output is rolled over, a sequence number is
+// inserted before the extensions of the file name, such as `data.000000001.csv.gz`, so all the files can be matched
+// by a wildcard like `data.*.csv.gz` in IMPORT INTO.
+func (s *SelectIntoExec) nextFileName() string {
+	if s.maxFileSize <= 0 {
+		return s.fileName
+	}
+	s.fileSeq++
+	dir, base := path.Split(s.fileName)
+	stem, ext := base, ""
+	if idx := strings.IndexByte(base, '.'); idx > 0 {
+		stem, ext = base[:idx], base[idx:]
+	}
+	return fmt.Sprintf("%s%s.%09d%s", dir, stem, s.fileSeq, ext)
+}
+
+func (s *SelectIntoExec) openFile(ctx context.Context) error {
+	name := s.nextFileName()
+	var w storage.ExternalFileWriter
+	if s.store == nil {
+		// MySQL-compatible behavior: allow files to be group-readable
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0640) // #nosec G302
+		if err != nil {
+			return errors.Trace(err)
+		}
+		w = &serverFileWriter{f: f}
+		if s.compression != storage.NoCompression {
+			w = storage.NewUploaderWriter(w, selectIntoChunkSize, s.compression)
+		}
+	} else {
+		// MySQL-compatible behavior: the output file must not exist
+		exists, err := s.store.FileExists(ctx, name)
+		if err != nil {
+			return exeerrors.ErrLoadDataCantAccess.GenWithStackByArgs(core.SelectIntoTarget, importer.GetMsgFromBRError(err))
+		}
+		if exists {
+			return exeerrors.ErrFileExists.GenWithStackByArgs(name)
+		}
+		w, err = storage.WithCompression(s.store, s.compression, storage.DecompressConfig{}).Create(ctx, name, nil)
+		if err != nil {
+			return exeerrors.ErrLoadDataCantAccess.GenWithStackByArgs(core.SelectIntoTarget, importer.GetMsgFromBRError(err))
+		}
+	}
+	s.dst = &outfileWriter{ctx: ctx, w: w}
+	s.fileSize = 0
+
+	if s.format == importer.DataFormatParquet {
+		pw, err := writer.NewCSVWriterFromWriter(s.parquetSchema(), s.dst, 1)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pw.RowGroupSize = defaultParquetRowGroupSize
+		if s.maxFileSize > 0 && s.maxFileSize < pw.RowGroupSize {
+			pw.RowGroupSize = s.maxFileSize
+		}
+		s.parquetWriter = pw
+		return nil
+	}
+	s.writer = bufio.NewWriter(s.dst)
+	return nil
+}
+
+func (s *SelectIntoExec) closeFile() error {
+	var err1 error
+	if s.parquetWriter != nil {
+		err1 = s.parquetWriter.WriteStop()
+		s.parquetWriter = nil
+	} else if s.writer != nil {
+		err1 = s.writer.Flush()
+		s.writer = nil
+	}
+	// dst is nil if opening the next file fails while rolling over.
+	if s.dst == nil {
+		return errors.Trace(err1)
+	}
+	err2 := s.dst.Close()
+	s.dst = nil
+	if err1 != nil {
+		return errors.Trace(err1)
+	}
+	return errors.Trace(err2)
+}
+
+// rollOverIfNeeded closes the current file and opens the next one if writing the next rowSize bytes makes the current
+// file exceed the max file size.
+func (s *SelectIntoExec) rollOverIfNeeded(rowSize int) error {
+	if s.maxFileSize <= 0 || s.fileSize == 0 || s.fileSize+int64(rowSize) <= s.maxFileSize {
+		s.fileSize += int64(rowSize)
+		return nil
+	}
+	ctx := s.dst.ctx
+	if err := s.closeFile(); err != nil {
+		return err
+	}
+	if err := s.openFile(ctx); err != nil {
+		return err
+	}
+	s.fileSize = int64(rowSize)
+	return nil
+}
+
 // Next implements the Executor Next interface.
 func (s *SelectIntoExec) Next(ctx context.Context, _ *chunk.Chunk) error {
 	for {
@@ -79,7 +350,13 @@ func (s *SelectIntoExec) Next(ctx context.Context, _ *chunk.Chunk) error {
 		if s.chk.NumRows() == 0 {
 			break
 		}
-		if err := s.dumpToOutfile(); err != nil {
+		var err error
+		if s.format == importer.DataFormatParquet {
+			err = s.dumpToParquet()
+		} else {
+			err = s.dumpToOutfile()
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -203,6 +480,9 @@ func (s *SelectIntoExec) dumpToOutfile() error {
 			}
 		}
 		s.lineBuf = append(s.lineBuf, s.LinesTerminatedBy...)
+		if err := s.rollOverIfNeeded(len(s.lineBuf)); err != nil {
+			return err
+		}
 		if _, err := s.writer.Write(s.lineBuf); err != nil {
 			return errors.Trace(err)
 		}
@@ -211,20 +491,140 @@ func (s *SelectIntoExec) dumpToOutfile() error {
 	return nil
 }
 
+// parquetSchema returns the metadata of the parquet columns. The names of the columns are the same as the output names
+// of the query, so the files can be imported by IMPORT INTO with the column names.
+func (s *SelectIntoExec) parquetSchema() []string {
+	cols := s.Children(0).Schema().Columns
+	md := make([]string, 0, len(cols))
+	used := make(map[string]struct{}, len(cols))
+	for i, col := range cols {
+		name := fmt.Sprintf("col%d", i)
+		if i < len(s.colNames) && s.colNames[i] != "" {
+			// ',' and '=' are the separators of the metadata.
+			name = strings.NewReplacer(",", "_", "=", "_").Replace(s.colNames[i])
+		}
+		// The output names of the query may be duplicated, but the parquet writer identifies the columns by the
+		// variable names converted from the column names, and the parquet parser matches the columns case-insensitively.
+		for {
+			key := common.StringToVariableName(strings.ToLower(name))
+			if _, ok := used[key]; !ok {
+				used[key] = struct{}{}
+				break
+			}
+			name = fmt.Sprintf("%s_%d", name, i)
+		}
+		md = append(md, fmt.Sprintf("name=%s, type=%s", name, parquetType(col.GetType())))
+	}
+	return md
+}
+
+// parquetType returns the parquet type of the column. The types which can't be represented by parquet exactly, such as
+// decimal and datetime, are written as their string formats, the same as the csv format.
+func parquetType(tp *types.FieldType) string {
+	switch tp.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeYear:
+		return "INT64"
+	case mysql.TypeLonglong:
+		if mysql.HasUnsignedFlag(tp.GetFlag()) {
+			return "UINT_64"
+		}
+		return "INT64"
+	case mysql.TypeBit:
+		return "UINT_64"
+	case mysql.TypeFloat:
+		return "FLOAT"
+	case mysql.TypeDouble:
+		return "DOUBLE"
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if types.IsBinaryStr(tp) {
+			return "BYTE_ARRAY"
+		}
+		return "UTF8"
+	default:
+		return "UTF8"
+	}
+}
+
+func (s *SelectIntoExec) dumpToParquet() error {
+	cols := s.Children(0).Schema().Columns
+	for i := 0; i < s.chk.NumRows(); i++ {
+		row := s.chk.GetRow(i)
+		// the parquet writer buffers the rows until a row group is full, so every row needs a new slice.
+		values := make([]any, len(cols))
+		// rowSize is an estimation of the uncompressed size of the row, which is used to roll over the output.
+		rowSize := 0
+		for j, col := range cols {
+			if row.IsNull(j) {
+				continue
+			}
+			tp := col.GetType()
+			var v any
+			switch tp.GetType() {
+			case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeYear, mysql.TypeLonglong:
+				v = row.GetInt64(j)
+				rowSize += 8
+			case mysql.TypeBit:
+				bit, err := types.BinaryLiteral(row.GetBytes(j)).ToInt(s.Ctx().GetSessionVars().StmtCtx.TypeCtx())
+				if err != nil {
+					return errors.Trace(err)
+				}
+				v = int64(bit)
+				rowSize += 8
+			case mysql.TypeFloat:
+				v = row.GetFloat32(j)
+				rowSize += 4
+			case mysql.TypeDouble:
+				v = row.GetFloat64(j)
+				rowSize += 8
+			default:
+				var str string
+				switch tp.GetType() {
+				case mysql.TypeNewDecimal:
+					str = row.GetMyDecimal(j).String()
+				case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+					str = row.GetTime(j).String()
+				case mysql.TypeDuration:
+					str = row.GetDuration(j, tp.GetDecimal()).String()
+				case mysql.TypeEnum:
+					str = row.GetEnum(j).String()
+				case mysql.TypeSet:
+					str = row.GetSet(j).String()
+				case mysql.TypeJSON:
+					str = row.GetJSON(j).String()
+				default:
+					str = string(row.GetBytes(j))
+				}
+				v = str
+				rowSize += len(str)
+			}
+			values[j] = v
+		}
+		if err := s.rollOverIfNeeded(rowSize); err != nil {
+			return err
+		}
+		if err := s.parquetWriter.Write(values); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	s.Ctx().GetSessionVars().StmtCtx.AddAffectedRows(uint64(s.chk.NumRows()))
+	return nil
+}
+
 // Close implements the Executor Close interface.
 func (s *SelectIntoExec) Close() error {
 	if !s.started {
 		return nil
 	}
-	err1 := s.writer.Flush()
-	err2 := s.dstFile.Close()
-	err3 := s.BaseExecutor.Close()
+	err1 := s.closeFile()
+	err2 := s.BaseExecutor.Close()
+	if s.store != nil {
+		s.store.Close()
+	}
 	if err1 != nil {
-		return errors.Trace(err1)
-	} else if err2 != nil {
-		return errors.Trace(err2)
+		return err1
 	}
-	return err3
+	return err2
 }
 
 const (
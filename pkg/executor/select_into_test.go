@@ -15,14 +15,21 @@
 package executor_test
 
 import (
+	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/errno"
 	"github.com/pingcap/tidb/pkg/executor"
+	"github.com/pingcap/tidb/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/types"
@@ -290,3 +297,123 @@ func TestYearType(t *testing.T) {
 	tk.MustExec(fmt.Sprintf("select * from t into outfile '%v' fields terminated by ',' optionally enclosed by '\"' lines terminated by '\\n';", outfile))
 	cmpAndRm("2010\n2011\n2012\n2030\n", outfile, t)
 }
+
+func TestSelectIntoOutfileRollOverAndCompression(t *testing.T) {
+	dir := t.TempDir()
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (i int, s varchar(20))")
+	tk.MustExec("insert into t values (1, 'aaaa'), (2, 'bbbb'), (3, 'cccc'), (4, 'dddd'), (5, 'eeee')")
+
+	// every line has 7 bytes, so a file holds 2 lines at most.
+	tk.MustExec(fmt.Sprintf("select * from t order by i into outfile %q with max_file_size='14'", filepath.Join(dir, "data.csv")))
+	require.Equal(t, uint64(5), tk.Session().GetSessionVars().StmtCtx.AffectedRows())
+	cmpAndRm("1\taaaa\n2\tbbbb\n", filepath.Join(dir, "data.000000001.csv"), t)
+	cmpAndRm("3\tcccc\n4\tdddd\n", filepath.Join(dir, "data.000000002.csv"), t)
+	cmpAndRm("5\teeee\n", filepath.Join(dir, "data.000000003.csv"), t)
+	_, err := os.Stat(filepath.Join(dir, "data.000000004.csv"))
+	require.True(t, os.IsNotExist(err))
+
+	// the statement fails without panicking if the next file can't be opened while rolling over.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.000000002.csv"), nil, 0644))
+	err = tk.ExecToErr(fmt.Sprintf("select * from t order by i into outfile %q with max_file_size='14'", filepath.Join(dir, "data.csv")))
+	require.ErrorContains(t, err, "file exists")
+	cmpAndRm("1\taaaa\n2\tbbbb\n", filepath.Join(dir, "data.000000001.csv"), t)
+	cmpAndRm("", filepath.Join(dir, "data.000000002.csv"), t)
+
+	// the output isn't compressed by the file extension.
+	outfile := filepath.Join(dir, "plain.csv.gz")
+	tk.MustExec(fmt.Sprintf("select * from t order by i into outfile %q", outfile))
+	cmpAndRm("1\taaaa\n2\tbbbb\n3\tcccc\n4\tdddd\n5\teeee\n", outfile, t)
+
+	// the compression is specified by the option.
+	outfile = filepath.Join(dir, "data.csv.gz")
+	tk.MustExec(fmt.Sprintf("select * from t order by i into outfile %q with compress='gzip'", outfile))
+	f, err := os.Open(outfile)
+	require.NoError(t, err)
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Equal(t, "1\taaaa\n2\tbbbb\n3\tcccc\n4\tdddd\n5\teeee\n", string(content))
+
+	outfile = filepath.Join(dir, "err.csv")
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q with max_file_size='abc'", outfile),
+		errno.ErrInvalidOptionVal)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q with max_file_size=10", outfile),
+		errno.ErrInvalidOptionVal)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q with max_file_size", outfile),
+		errno.ErrInvalidOptionVal)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q with max_file_size='1', max_file_size='2'", outfile),
+		errno.ErrDuplicateOption)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q with thread=1", outfile),
+		errno.ErrUnknownOption)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q format 'sql'", outfile),
+		errno.ErrLoadDataUnsupportedFormat)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q format 'parquet' fields terminated by ','", outfile),
+		errno.ErrLoadDataUnsupportedOption)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q with compress='lzo'", outfile),
+		errno.ErrInvalidOptionVal)
+	tk.MustGetErrCode(fmt.Sprintf("select * from t into outfile %q format 'parquet' with compress='gzip'", outfile),
+		errno.ErrLoadDataUnsupportedOption)
+	_, err = os.Stat(outfile)
+	require.True(t, os.IsNotExist(err))
+
+	// the FILE privilege is required whatever the output is.
+	tk.MustExec("create user 'select_into'@'%'")
+	tk.MustExec("grant select on test.* to 'select_into'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk2.Session().Auth(&auth.UserIdentity{Username: "select_into", Hostname: "%"}, nil, nil, nil))
+	tk2.MustGetErrCode(fmt.Sprintf("select * from test.t into outfile %q", outfile), errno.ErrSpecificAccessDenied)
+	tk2.MustGetErrCode("select * from test.t into outfile 's3://bucket/data.csv'", errno.ErrSpecificAccessDenied)
+}
+
+func TestSelectIntoOutfileParquet(t *testing.T) {
+	dir := t.TempDir()
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (i int, u bigint unsigned, f float, d double, dc decimal(10, 2), s varchar(20), " +
+		"b varbinary(20), dt datetime, j json, bt bit(8))")
+	tk.MustExec("insert into t values (1, 18446744073709551615, 1.5, 2.25, 3.14, 'abc', 'x\\0y', '2020-01-02 03:04:05', '[1, 2]', b'101')")
+	tk.MustExec("insert into t values (null, null, null, null, null, null, null, null, null, null)")
+
+	tk.MustExec(fmt.Sprintf("select i, u, f, d, dc, s, b, dt, j, bt, i as `a,b`, s as S from t order by i desc into outfile %q format 'parquet'",
+		filepath.Join(dir, "t.parquet")))
+	require.Equal(t, uint64(2), tk.Session().GetSessionVars().StmtCtx.AffectedRows())
+
+	ctx := context.Background()
+	s, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	reader, err := s.Open(ctx, "t.parquet", nil)
+	require.NoError(t, err)
+	parser, err := mydump.NewParquetParser(ctx, s, reader, "t.parquet")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, parser.Close())
+	}()
+	require.Equal(t, []string{"i", "u", "f", "d", "dc", "s", "b", "dt", "j", "bt", "a_b", "s_11"}, parser.Columns())
+
+	require.NoError(t, parser.ReadRow())
+	row := parser.LastRow().Row
+	require.Equal(t, int64(1), row[0].GetInt64())
+	require.Equal(t, uint64(18446744073709551615), row[1].GetUint64())
+	require.Equal(t, 1.5, row[2].GetFloat64())
+	require.Equal(t, 2.25, row[3].GetFloat64())
+	require.Equal(t, "3.14", row[4].GetString())
+	require.Equal(t, "abc", row[5].GetString())
+	require.Equal(t, "x\x00y", row[6].GetString())
+	require.Equal(t, "2020-01-02 03:04:05", row[7].GetString())
+	require.Equal(t, "[1, 2]", row[8].GetString())
+	require.Equal(t, uint64(5), row[9].GetUint64())
+	require.Equal(t, int64(1), row[10].GetInt64())
+	require.Equal(t, "abc", row[11].GetString())
+
+	require.NoError(t, parser.ReadRow())
+	for _, d := range parser.LastRow().Row {
+		require.True(t, d.IsNull())
+	}
+	require.ErrorIs(t, parser.ReadRow(), io.EOF)
+}
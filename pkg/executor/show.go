@@ -93,6 +93,9 @@ type ShowExec struct {
 	Tp                ast.ShowStmtType // Databases/Tables/Columns/....
 	DBName            model.CIStr
 	Table             *ast.TableName       // Used for showing columns.
+	Procedure         *ast.TableName       // Used for showing create procedure.
+	Trigger           *ast.TableName       // Used for showing create trigger.
+	Event             *ast.TableName       // Used for showing create event.
 	Partition         model.CIStr          // Used for showing partition
 	Column            *ast.ColumnName      // Used for `desc table column`.
 	IndexName         model.CIStr          // Used for show table regions.
@@ -190,6 +193,12 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowCreateUser(ctx)
 	case ast.ShowCreateView:
 		return e.fetchShowCreateView()
+	case ast.ShowCreateProcedure:
+		return e.fetchShowCreateProcedure(ctx)
+	case ast.ShowCreateTrigger:
+		return e.fetchShowCreateTrigger()
+	case ast.ShowCreateEvent:
+		return e.fetchShowCreateEvent(ctx)
 	case ast.ShowCreateDatabase:
 		return e.fetchShowCreateDatabase()
 	case ast.ShowCreatePlacementPolicy:
@@ -207,7 +216,7 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 	case ast.ShowIndex:
 		return e.fetchShowIndex()
 	case ast.ShowProcedureStatus:
-		return e.fetchShowProcedureStatus()
+		return e.fetchShowProcedureStatus(ctx)
 	case ast.ShowPumpStatus:
 		return e.fetchShowPumpOrDrainerStatus(node.PumpNode)
 	case ast.ShowStatus:
@@ -229,7 +238,7 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 	case ast.ShowProcessList:
 		return e.fetchShowProcessList()
 	case ast.ShowEvents:
-		// empty result
+		return e.fetchShowEvents(ctx)
 	case ast.ShowStatsExtended:
 		return e.fetchShowStatsExtended()
 	case ast.ShowStatsMeta:
@@ -1124,6 +1133,10 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 			buf.WriteString("  PRIMARY KEY ")
 		} else if idxInfo.Unique {
 			fmt.Fprintf(buf, "  UNIQUE KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
+		} else if idxInfo.FullTextInfo != nil {
+			fmt.Fprintf(buf, "  FULLTEXT KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
+		} else if idxInfo.VectorInfo != nil {
+			fmt.Fprintf(buf, "  VECTOR INDEX %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		} else {
 			fmt.Fprintf(buf, "  KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		}
@@ -1133,6 +1146,8 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 		for _, c := range idxInfo.Columns {
 			if tableInfo.Columns[c.Offset].Hidden {
 				colInfo = fmt.Sprintf("(%s)", tableInfo.Columns[c.Offset].GeneratedExprString)
+			} else if idxInfo.VectorInfo != nil {
+				colInfo = fmt.Sprintf("(%s(%s))", strings.ToUpper(idxInfo.VectorInfo.DistanceMetric.DistanceFunction()), stringutil.Escape(c.Name.O, sqlMode))
 			} else {
 				colInfo = stringutil.Escape(c.Name.O, sqlMode)
 				if c.Length != types.UnspecifiedLength {
@@ -1142,6 +1157,9 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 			cols = append(cols, colInfo)
 		}
 		fmt.Fprintf(buf, "(%s)", strings.Join(cols, ","))
+		if idxInfo.FullTextInfo != nil && idxInfo.FullTextInfo.ParserType == model.FullTextParserTypeNgram {
+			fmt.Fprintf(buf, ` /*!50100 WITH PARSER %s */`, stringutil.Escape(string(model.FullTextParserTypeNgram), sqlMode))
+		}
 		if idxInfo.Invisible {
 			fmt.Fprintf(buf, ` /*!80000 INVISIBLE */`)
 		}
@@ -1158,6 +1176,9 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 				buf.WriteString(" /*T![clustered_index] NONCLUSTERED */")
 			}
 		}
+		if idxInfo.Global {
+			buf.WriteString(" /*T![global_index] GLOBAL */")
+		}
 		if i != len(publicIndices)-1 {
 			buf.WriteString(",\n")
 		}
@@ -1277,7 +1298,11 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 	}
 
 	if tableInfo.TempTableType == model.TempTableGlobal {
-		fmt.Fprintf(buf, " ON COMMIT DELETE ROWS")
+		if tableInfo.TempTablePreserveRows {
+			fmt.Fprintf(buf, " ON COMMIT PRESERVE ROWS")
+		} else {
+			fmt.Fprintf(buf, " ON COMMIT DELETE ROWS")
+		}
 	}
 
 	if tableInfo.PlacementPolicyRef != nil {
@@ -1299,11 +1324,15 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 
 		restoreCtx.WritePlain(" ")
 		err = restoreCtx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() error {
-			columnName := ast.ColumnName{Name: tableInfo.TTLInfo.ColumnName}
 			timeUnit := ast.TimeUnitExpr{Unit: ast.TimeUnitType(tableInfo.TTLInfo.IntervalTimeUnit)}
 			restoreCtx.WriteKeyWord("TTL")
 			restoreCtx.WritePlain("=")
-			restoreCtx.WriteName(columnName.String())
+			if tableInfo.TTLInfo.Expr != "" {
+				restoreCtx.WritePlainf("(%s)", tableInfo.TTLInfo.Expr)
+			} else {
+				columnName := ast.ColumnName{Name: tableInfo.TTLInfo.ColumnName}
+				restoreCtx.WriteName(columnName.String())
+			}
 			restoreCtx.WritePlainf(" + INTERVAL %s ", tableInfo.TTLInfo.IntervalExprStr)
 			return timeUnit.Restore(restoreCtx)
 		})
@@ -1312,6 +1341,20 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 			return err
 		}
 
+		if tableInfo.TTLInfo.EpochUnit != "" {
+			restoreCtx.WritePlain(" ")
+			err = restoreCtx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() error {
+				restoreCtx.WriteKeyWord("TTL_EPOCH_UNIT")
+				restoreCtx.WritePlain("=")
+				restoreCtx.WriteString(tableInfo.TTLInfo.EpochUnit)
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
 		restoreCtx.WritePlain(" ")
 		err = restoreCtx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() error {
 			restoreCtx.WriteKeyWord("TTL_ENABLE")
@@ -1343,6 +1386,34 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 		if err != nil {
 			return err
 		}
+
+		if tableInfo.TTLInfo.Archive != "" {
+			restoreCtx.WritePlain(" ")
+			err = restoreCtx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() error {
+				restoreCtx.WriteKeyWord("TTL_ARCHIVE")
+				restoreCtx.WritePlain("=")
+				restoreCtx.WriteString(ast.RedactURL(tableInfo.TTLInfo.Archive))
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if tableInfo.TTLInfo.ArchiveFormat != "" {
+			restoreCtx.WritePlain(" ")
+			err = restoreCtx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() error {
+				restoreCtx.WriteKeyWord("TTL_ARCHIVE_FORMAT")
+				restoreCtx.WritePlain("=")
+				restoreCtx.WriteString(tableInfo.TTLInfo.ArchiveFormat)
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -1840,14 +1911,6 @@ func (e *ShowExec) fetchShowPrivileges() error {
 	return nil
 }
 
-func (*ShowExec) fetchShowTriggers() error {
-	return nil
-}
-
-func (*ShowExec) fetchShowProcedureStatus() error {
-	return nil
-}
-
 func (e *ShowExec) fetchShowPlugins() error {
 	tiPlugins := plugin.GetAll()
 	for _, ps := range tiPlugins {
@@ -177,6 +177,10 @@ func (e *SimpleExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 		err = e.executeSetPwd(ctx, x)
 	case *ast.SetSessionStatesStmt:
 		err = e.executeSetSessionStates(ctx, x)
+	case *ast.ProcedureInfo:
+		err = e.executeCreateProcedure(ctx, x)
+	case *ast.DropProcedureStmt:
+		err = e.executeDropProcedure(ctx, x)
 	case *ast.KillStmt:
 		err = e.executeKillStmt(ctx, x)
 	case *ast.BinlogStmt:
@@ -2721,7 +2725,8 @@ func (e *SimpleExec) autoNewTxn() bool {
 	// Data definition language (DDL) statements that define or modify database objects.
 	// (handled in DDL package)
 	// Statements that implicitly use or modify tables in the mysql database.
-	case *ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt, *ast.RevokeRoleStmt, *ast.GrantRoleStmt:
+	case *ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt, *ast.RevokeRoleStmt, *ast.GrantRoleStmt,
+		*ast.ProcedureInfo, *ast.DropProcedureStmt:
 		return true
 	// Transaction-control and locking statements.  BEGIN, LOCK TABLES, SET autocommit = 1 (if the value is not already 1), START TRANSACTION, UNLOCK TABLES.
 	// (handled in other place)
@@ -2790,6 +2795,8 @@ func (e *SimpleExec) executeAdmin(s *ast.AdminStmt) error {
 		return e.executeAdminReloadStatistics(s)
 	case ast.AdminFlushPlanCache:
 		return e.executeAdminFlushPlanCache(s)
+	case ast.AdminResetCardinalityFeedback:
+		return e.executeAdminResetCardinalityFeedback(s)
 	case ast.AdminSetBDRRole:
 		return e.executeAdminSetBDRRole(s)
 	case ast.AdminUnsetBDRRole:
@@ -2825,11 +2832,37 @@ func (e *SimpleExec) executeAdminFlushPlanCache(s *ast.AdminStmt) error {
 	if s.StatementScope == ast.StatementScopeInstance {
 		// Record the timestamp. When other sessions want to use the plan cache,
 		// it will check the timestamp first to decide whether the plan cache should be flushed.
-		domain.GetDomain(e.Ctx()).SetExpiredTimeStamp4PC(now)
+		dom := domain.GetDomain(e.Ctx())
+		dom.SetExpiredTimeStamp4PC(now)
+		if instanceCache := dom.InstancePlanCache(); instanceCache != nil {
+			instanceCache.DeleteAll()
+		}
 	}
 	return nil
 }
 
+func (e *SimpleExec) executeAdminResetCardinalityFeedback(s *ast.AdminStmt) error {
+	if s.Tp != ast.AdminResetCardinalityFeedback {
+		return errors.New("This AdminStmt is not ADMIN RESET CARDINALITY FEEDBACK")
+	}
+	tableIDs := make([]int64, 0, len(s.Tables))
+	for _, tn := range s.Tables {
+		tbl, err := e.is.TableByName(tn.Schema, tn.Name)
+		if err != nil {
+			return err
+		}
+		tblInfo := tbl.Meta()
+		tableIDs = append(tableIDs, tblInfo.ID)
+		// The corrections are learned on the stats of each partition.
+		if pi := tblInfo.GetPartitionInfo(); pi != nil {
+			for _, def := range pi.Definitions {
+				tableIDs = append(tableIDs, def.ID)
+			}
+		}
+	}
+	return domain.GetDomain(e.Ctx()).StatsHandle().ResetCardinalityFeedback(tableIDs)
+}
+
 func (e *SimpleExec) executeAdminSetBDRRole(s *ast.AdminStmt) error {
 	if s.Tp != ast.AdminSetBDRRole {
 		return errors.New("This AdminStmt is not ADMIN SET BDR_ROLE")
@@ -0,0 +1,197 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAlterDropEvent(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("set global event_scheduler = off")
+	defer tk.MustExec("set global event_scheduler = on")
+	tk.MustExec("use test")
+	tk.MustExec("set @@time_zone = '+00:00'")
+	tk.MustExec("create table t (a int)")
+
+	tk.MustExec("create event e1 on schedule every 1 day starts '2030-01-01 00:00:00' comment 'daily' do insert into t values (1)")
+	tk.MustGetErrCode("create event e1 on schedule every 1 hour do insert into t values (1)", errno.ErrEventAlreadyExists)
+	tk.MustExec("create event if not exists e1 on schedule every 1 hour do insert into t values (1)")
+	tk.MustQuery("show warnings").Check(testkit.RowsWithSep("|", "Note|1537|Event 'e1' already exists"))
+	tk.MustExec("create event test.e2 on schedule at '2030-01-01 00:00:00' on completion preserve disable do call test.nightly_rollup()")
+	tk.MustGetErrCode("create event e3 on schedule every 0 second do insert into t values (1)", errno.ErrEventIntervalNotPositiveOrTooBig)
+	tk.MustGetErrCode("create event e3 on schedule every 1 month do insert into t values (1)", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("create event e3 on schedule every 1 hour starts '2030-01-02' ends '2030-01-01' do insert into t values (1)", errno.ErrEventEndsBeforeStarts)
+	tk.MustGetErrCode("create event no_db.e3 on schedule every 1 hour do insert into t values (1)", errno.ErrBadDB)
+
+	// The events whose execution time is in the past are dropped or disabled right after they're created.
+	tk.MustExec("create event e3 on schedule at '2000-01-01 00:00:00' do insert into t values (1)")
+	tk.MustQuery("show warnings").CheckContain("The event was dropped immediately after creation")
+	tk.MustExec("create event e3 on schedule every 1 hour starts '1999-01-01 00:00:00' ends '2000-01-01 00:00:00' on completion preserve do insert into t values (1)")
+	tk.MustQuery("show warnings").CheckContain("Event has been disabled")
+
+	tk.MustQuery("show events").Check(testkit.RowsWithSep("|",
+		"test|e1|+00:00|root@%|RECURRING|<nil>|1|DAY|2030-01-01 00:00:00|<nil>|ENABLED|0|utf8mb4|utf8mb4_bin|utf8mb4_bin",
+		"test|e2|+00:00|root@%|ONE TIME|2030-01-01 00:00:00|<nil>|<nil>|<nil>|<nil>|DISABLED|0|utf8mb4|utf8mb4_bin|utf8mb4_bin",
+		"test|e3|+00:00|root@%|RECURRING|<nil>|1|HOUR|1999-01-01 00:00:00|2000-01-01 00:00:00|DISABLED|0|utf8mb4|utf8mb4_bin|utf8mb4_bin",
+	))
+	tk.MustQuery("show events like 'e2'").CheckAt([]int{1}, testkit.Rows("e2"))
+	tk.MustQuery("select event_name, event_definition, on_completion, event_comment from information_schema.events where event_schema = 'test'").
+		Check(testkit.RowsWithSep("|",
+			"e1|insert into t values (1)|NOT PRESERVE|daily",
+			"e2|call test.nightly_rollup()|PRESERVE|",
+			"e3|insert into t values (1)|PRESERVE|",
+		))
+	tk.MustQuery("show create event e1").CheckAt([]int{0, 2, 3}, testkit.RowsWithSep("|",
+		"e1|+00:00|CREATE DEFINER=`root`@`%` EVENT `e1` ON SCHEDULE EVERY 1 DAY STARTS '2030-01-01 00:00:00' "+
+			"ON COMPLETION NOT PRESERVE ENABLE COMMENT 'daily' DO insert into t values (1)"))
+	require.ErrorContains(t, tk.QueryToErr("show create event e4"), "Unknown event 'e4'")
+
+	tk.MustExec("alter event e1 on schedule every 2 hour starts '2030-01-01 00:00:00' ends '2030-02-01 00:00:00' on completion preserve disable comment '' do insert into t values (2)")
+	tk.MustQuery("show create event e1").CheckAt([]int{3}, testkit.RowsWithSep("|",
+		"CREATE DEFINER=`root`@`%` EVENT `e1` ON SCHEDULE EVERY 2 HOUR STARTS '2030-01-01 00:00:00' ENDS '2030-02-01 00:00:00' "+
+			"ON COMPLETION PRESERVE DISABLE DO insert into t values (2)"))
+	tk.MustExec("alter event e1 enable")
+	tk.MustQuery("show events like 'e1'").CheckAt([]int{6, 7, 10}, testkit.Rows("2 HOUR ENABLED"))
+	tk.MustGetErrCode("alter event e1 rename to e1", errno.ErrEventSameName)
+	tk.MustGetErrCode("alter event e1 rename to e2", errno.ErrEventAlreadyExists)
+	tk.MustGetErrCode("alter event e4 enable", errno.ErrEventDoesNotExist)
+	tk.MustGetErrCode("alter event e2 on schedule at '2000-01-01 00:00:00' on completion not preserve", errno.ErrEventCannotAlterInThePast)
+	tk.MustExec("create database test2")
+	tk.MustExec("alter event e1 rename to test2.e4")
+	tk.MustQuery("show events").CheckAt([]int{1}, testkit.Rows("e2", "e3"))
+	tk.MustQuery("show events from test2").CheckAt([]int{0, 1, 6, 7, 10}, testkit.Rows("test2 e4 2 HOUR ENABLED"))
+
+	tk.MustExec("drop event e2")
+	tk.MustExec("drop event test2.e4")
+	tk.MustGetErrCode("drop event e2", errno.ErrEventDoesNotExist)
+	tk.MustExec("drop event if exists e2")
+	tk.MustQuery("show warnings").Check(testkit.RowsWithSep("|", "Note|1539|Unknown event 'e2'"))
+	tk.MustQuery("select event_name from information_schema.events").Check(testkit.Rows("e3"))
+
+	// The events are dropped with the schema, and they don't come back when a schema with the same name is created.
+	tk.MustExec("create event test2.e5 on schedule every 1 hour do select 1")
+	tk.MustExec("create database `test2/x`")
+	tk.MustExec("create event `test2/x`.e6 on schedule every 1 hour do select 1")
+	tk.MustExec("drop database test2")
+	tk.MustQuery("select event_schema, event_name from information_schema.events order by event_name").
+		Check(testkit.Rows("test e3", "test2/x e6"))
+	tk.MustExec("create database test2")
+	tk.MustQuery("show events from test2").Check(testkit.Rows())
+	tk.MustExec("drop database `test2/x`")
+	tk.MustQuery("select event_name from information_schema.events").Check(testkit.Rows("e3"))
+}
+
+func TestEventPrivileges(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("set global event_scheduler = off")
+	defer tk.MustExec("set global event_scheduler = on")
+	tk.MustExec("create user u1, u2")
+	tk.MustExec("grant event on test.* to u1")
+	tk.MustExec("create event test.e1 on schedule every 1 hour do select 1")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil, nil))
+	tk1.MustExec("use test")
+	tk1.MustExec("create event e2 on schedule every 1 hour do select 1")
+	tk1.MustQuery("show events").CheckAt([]int{1, 3}, testkit.Rows("e1 root@%", "e2 u1@%"))
+	tk1.MustGetErrCode("create definer = 'u2'@'%' event e3 on schedule every 1 hour do select 1", errno.ErrSpecificAccessDenied)
+	tk1.MustGetErrCode("create event mysql.e3 on schedule every 1 hour do select 1", errno.ErrDBaccessDenied)
+	tk1.MustExec("alter event e1 disable")
+	tk1.MustQuery("show events like 'e1'").CheckAt([]int{3}, testkit.Rows("u1@%"))
+
+	tk2 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, nil, nil, nil))
+	tk2.MustGetErrCode("show events from test", errno.ErrDBaccessDenied)
+	tk2.MustGetErrCode("show create event test.e1", errno.ErrDBaccessDenied)
+	tk2.MustGetErrCode("drop event test.e1", errno.ErrDBaccessDenied)
+	tk2.MustQuery("select event_name from information_schema.events").Check(testkit.Rows())
+}
+
+func TestExecuteEvent(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create user u1")
+	tk.MustExec("grant insert on test.t to u1")
+
+	// The recurring event is executed as the definer until it's disabled.
+	tk.MustExec("create definer = 'u1'@'%' event e1 on schedule every 1 second do insert into t values (1)")
+	require.Eventually(t, func() bool {
+		return len(tk.MustQuery("select * from t where a = 1").Rows()) >= 2
+	}, 30*time.Second, 100*time.Millisecond)
+	tk.MustExec("alter event e1 disable")
+	tk.MustQuery("select last_executed is not null from information_schema.events where event_name = 'e1'").Check(testkit.Rows("1"))
+
+	// The one-time event is executed once, and then it's dropped or disabled.
+	tk.MustExec("create event e2 on schedule at now() + interval 1 second do insert into t values (2)")
+	tk.MustExec("create event e3 on schedule at now() + interval 1 second on completion preserve do insert into t values (3)")
+	require.Eventually(t, func() bool {
+		return len(tk.MustQuery("select event_name from information_schema.events where status = 'ENABLED'").Rows()) == 0
+	}, 30*time.Second, 100*time.Millisecond)
+	tk.MustQuery("select event_name, status from information_schema.events").Sort().Check(testkit.Rows("e1 DISABLED", "e3 DISABLED"))
+	tk.MustQuery("select a, count(*) from t where a > 1 group by a order by a").Check(testkit.Rows("2 1", "3 1"))
+
+	// The statement fails without the privileges of the definer.
+	tk.MustExec("revoke insert on test.t from u1")
+	tk.MustExec("delete from t")
+	// ALTER EVENT changes the definer to the current user if DEFINER isn't specified.
+	tk.MustExec("alter definer = 'u1'@'%' event e1 enable")
+	require.Eventually(t, func() bool {
+		return len(tk.MustQuery("select last_executed > now() - interval 2 second from information_schema.events where event_name = 'e1'").Rows()) == 1
+	}, 30*time.Second, 100*time.Millisecond)
+	time.Sleep(2 * time.Second)
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+	tk.MustExec("drop event e1")
+}
+
+func TestExecuteEventOnce(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+
+	// The statement isn't committed if the event can't be closed in the same transaction, e.g. the event has been
+	// executed by another runtime with the same event ID.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/pkg/eventscheduler/mockEventClosed", "return"))
+	tk.MustExec("create event e1 on schedule at now() + interval 1 second do insert into t values (1)")
+	require.Eventually(t, func() bool {
+		return len(tk.MustQuery("select event_name from information_schema.events").Rows()) == 0
+	}, 30*time.Second, 100*time.Millisecond)
+	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/pkg/eventscheduler/mockEventClosed"))
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+
+	// The event ID of the execution is recorded in the summary when the event is closed.
+	tk.MustExec("create event e2 on schedule at now() + interval 1 second on completion preserve do insert into t values (2)")
+	require.Eventually(t, func() bool {
+		return len(tk.MustQuery("select event_name from information_schema.events where status = 'ENABLED'").Rows()) == 0
+	}, 30*time.Second, 100*time.Millisecond)
+	tk.MustQuery("select * from t").Check(testkit.Rows("2"))
+	tk.MustQuery("select event_status, event_id, json_extract(convert(summary_data using utf8mb4), '$.last_event_id') != '' " +
+		"from mysql.tidb_timers where timer_key = '/tidb/event/test/e2'").Check(testkit.Rows("IDLE  1"))
+}
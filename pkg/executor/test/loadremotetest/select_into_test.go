@@ -0,0 +1,60 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadremotetest
+
+import (
+	"fmt"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/pingcap/tidb/pkg/testkit"
+)
+
+func (s *mockGCSSuite) TestSelectIntoOutfile() {
+	s.tk.MustExec("DROP DATABASE IF EXISTS select_into;")
+	s.tk.MustExec("CREATE DATABASE select_into;")
+	s.tk.MustExec("CREATE TABLE select_into.t (i INT, s varchar(32));")
+	s.tk.MustExec("CREATE TABLE select_into.t2 (i INT, s varchar(32));")
+	s.tk.MustExec("INSERT INTO select_into.t VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, NULL);")
+	s.server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "test-select-into"})
+
+	sql := fmt.Sprintf(`SELECT * FROM select_into.t ORDER BY i INTO OUTFILE 'gs://test-select-into/data.csv?endpoint=%s'
+		FIELDS TERMINATED BY ',' ENCLOSED BY '"'`, gcsEndpoint)
+	s.tk.MustExec(sql)
+	obj, err := s.server.GetObject("test-select-into", "data.csv")
+	s.NoError(err)
+	s.Equal("\"1\",\"a\"\n\"2\",\"b\"\n\"3\",\"c\"\n\"4\",\\N\n", string(obj.Content))
+	// the output file must not exist.
+	s.tk.MustContainErrMsg(sql, "File 'data.csv' already exists")
+
+	// roll over to multiple compressed files, and load them back.
+	sql = fmt.Sprintf(`SELECT * FROM select_into.t ORDER BY i INTO OUTFILE 'gs://test-select-into/part.csv.gz?endpoint=%s'
+		FIELDS TERMINATED BY ',' ENCLOSED BY '"' WITH max_file_size='16', compress='gzip'`, gcsEndpoint)
+	s.tk.MustExec(sql)
+	_, err = s.server.GetObject("test-select-into", "part.000000001.csv.gz")
+	s.NoError(err)
+	_, err = s.server.GetObject("test-select-into", "part.000000002.csv.gz")
+	s.NoError(err)
+	_, err = s.server.GetObject("test-select-into", "part.000000003.csv.gz")
+	s.Error(err)
+	sql = fmt.Sprintf(`LOAD DATA INFILE 'gs://test-select-into/part.*.csv.gz?endpoint=%s' INTO TABLE select_into.t2
+		FIELDS TERMINATED BY ',' ENCLOSED BY '"'`, gcsEndpoint)
+	s.tk.MustExec(sql)
+	s.tk.MustQuery("SELECT * FROM select_into.t2 ORDER BY i;").Check(testkit.Rows(
+		"1 a", "2 b", "3 c", "4 <nil>",
+	))
+
+	sql = fmt.Sprintf(`SELECT * FROM select_into.t INTO OUTFILE 'gs://test-select-into/?endpoint=%s'`, gcsEndpoint)
+	s.tk.MustContainErrMsg(sql, "file name is empty")
+}
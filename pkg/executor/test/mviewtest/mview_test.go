@@ -0,0 +1,208 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mviewtest
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaterializedViewCompleteRefresh(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (1, 2), (2, 3)")
+
+	tk.MustExec("create materialized view mv (a, cnt, s) refresh complete as select a, count(*), sum(b) from t group by a")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 3", "2 1 3"))
+	tk.MustGetErrCode("create materialized view mv refresh complete as select a from t", errno.ErrTableExists)
+	tk.MustExec("create materialized view if not exists mv refresh complete as select a from t")
+	tk.MustQuery("show warnings").Check(testkit.RowsWithSep("|", "Note|1050|Table 'test.mv' already exists"))
+
+	// The view isn't changed until it's refreshed.
+	tk.MustExec("insert into t values (3, 4)")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 3", "2 1 3"))
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 3", "2 1 3", "3 1 4"))
+
+	// The view is only modified by the refreshes.
+	tk.MustGetErrCode("insert into mv values (4, 1, 1)", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("replace into mv values (4, 1, 1)", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("update mv set cnt = 0", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("delete from mv", errno.ErrNonUpdatableTable)
+	tk.MustGetErrCode("truncate table mv", errno.ErrWrongObject)
+	tk.MustGetErrCode("drop table mv", errno.ErrWrongObject)
+	tk.MustGetErrCode("refresh materialized view t", errno.ErrWrongObject)
+	tk.MustGetErrCode("drop materialized view t", errno.ErrWrongObject)
+
+	// The base table of a view refreshed completely can be dropped like the base table of a normal view.
+	tk.MustExec("drop table t")
+	tk.MustGetErrCode("refresh materialized view mv", errno.ErrNoSuchTable)
+	tk.MustExec("drop materialized view mv")
+	tk.MustExec("drop materialized view if exists mv")
+	tk.MustQuery("show warnings").Check(testkit.RowsWithSep("|", "Note|1051|Unknown table 'test.mv'"))
+	tk.MustGetErrCode("drop materialized view mv", errno.ErrBadTable)
+}
+
+func TestMaterializedViewFastRefreshAggregation(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, a int, b int)")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 1, 2), (3, 2, 3)")
+
+	tk.MustGetErrCode("create materialized view mv refresh fast as select a + 1, count(*) from t group by a + 1", errno.ErrMViewFastRefreshUnsupported)
+	tk.MustGetErrCode("create materialized view mv refresh fast as select count(*) from t group by a", errno.ErrMViewFastRefreshUnsupported)
+	tk.MustGetErrCode("create materialized view mv refresh fast as select a, count(*) from t where b in (select b from t) group by a", errno.ErrMViewFastRefreshUnsupported)
+	tk.MustExec("create materialized view mv (a, cnt, s) refresh fast as select a, count(*), sum(b) from t where b < 100 group by a")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 3", "2 1 3"))
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'mysql' and table_name like 'tidb_mlog_%'").Check(testkit.Rows("1"))
+
+	// The changes are logged in the write path, and the groups of the changed rows are recomputed.
+	tk.MustExec("insert into t values (4, 3, 4)")
+	tk.MustExec("update t set a = 3 where id = 1")
+	tk.MustExec("delete from t where id = 3")
+	tk.MustExec("insert into t values (5, 1, 100)")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 3", "2 1 3"))
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 1 2", "3 2 5"))
+
+	// A row changed and changed back in a transaction leaves no change in the log.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (6, 4, 1)")
+	tk.MustExec("delete from t where id = 6")
+	tk.MustExec("commit")
+	tk.MustQuery("select count(*) from mysql.tidb_mlog_" + mviewID(tk, "mv") + "_" + tableID(tk, "t")).Check(testkit.Rows("0"))
+
+	tk.MustExec("insert into t values (6, 1, 5)")
+	tk.MustExec("refresh materialized view mv complete")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 2 7", "3 2 5"))
+	tk.MustQuery("select count(*) from mysql.tidb_mlog_" + mviewID(tk, "mv") + "_" + tableID(tk, "t")).Check(testkit.Rows("0"))
+
+	// The base table and the logged columns are tracked by the view.
+	tk.MustGetErrCode("drop table t", errno.ErrMViewDependency)
+	tk.MustGetErrCode("truncate table t", errno.ErrMViewDependency)
+	tk.MustGetErrCode("alter table t drop column a", errno.ErrMViewDependency)
+	tk.MustGetErrCode("alter table t modify column a bigint", errno.ErrMViewDependency)
+	tk.MustGetErrCode("alter table t rename column a to c", errno.ErrMViewDependency)
+	tk.MustGetErrCode("drop database test", errno.ErrMViewDependency)
+	tk.MustExec("alter table t modify column b bigint")
+
+	tk.MustExec("drop materialized view mv")
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'mysql' and table_name like 'tidb_mlog_%'").Check(testkit.Rows("0"))
+	tk.MustExec("insert into t values (7, 1, 1)")
+	tk.MustExec("drop table t")
+}
+
+func TestMaterializedViewFastRefreshJoin(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (id int primary key, a int)")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("insert into t2 values (1, 10), (1, 11), (3, 30)")
+
+	tk.MustGetErrCode("create materialized view mv refresh fast as select t1.id, t2.b from t1 join t2 on t1.a = t2.a", errno.ErrMViewFastRefreshUnsupported)
+	tk.MustGetErrCode("create materialized view mv refresh fast as select t1.id, t2._tidb_rowid, t2.b from t1 left join t2 on t1.a = t2.a", errno.ErrMViewFastRefreshUnsupported)
+	tk.MustExec("create materialized view mv (id1, id2, b) refresh fast as select t1.id, t2._tidb_rowid, t2.b from t1 join t2 on t1.a = t2.a")
+	tk.MustQuery("select id1, b from mv order by b").Check(testkit.Rows("1 10", "1 11"))
+
+	tk.MustExec("insert into t2 values (2, 20)")
+	tk.MustExec("update t1 set a = 3 where id = 1")
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery("select id1, b from mv order by b").Check(testkit.Rows("2 20", "1 30"))
+	tk.MustExec("delete from t2 where b = 30")
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery("select id1, b from mv order by b").Check(testkit.Rows("2 20"))
+	tk.MustExec("drop materialized view mv")
+}
+
+func TestMaterializedViewRefreshOnSchedule(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global event_scheduler = off")
+	defer tk.MustExec("set global event_scheduler = on")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create materialized view mv refresh complete on schedule every 1 hour as select count(*) as cnt from t")
+	tk.MustQuery("select event_name, event_definition from information_schema.events where event_schema = 'test'").
+		Check(testkit.RowsWithSep("|", "mview_refresh_mv|REFRESH MATERIALIZED VIEW `test`.`mv`"))
+	tk.MustExec("drop materialized view mv")
+	tk.MustQuery("select count(*) from information_schema.events where event_schema = 'test'").Check(testkit.Rows("0"))
+}
+
+func mviewID(tk *testkit.TestKit, name string) string {
+	return tk.MustQuery("select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = '" + name + "'").Rows()[0][0].(string)
+}
+
+func tableID(tk *testkit.TestKit, name string) string {
+	return mviewID(tk, name)
+}
+
+func TestMaterializedViewRewrite(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, 2), (2, 1, 3), (2, 1, 4)")
+	tk.MustExec("create materialized view mv (a, b, cnt, s, mx) refresh complete as select a, b, count(*), sum(c), max(c) from t where c > 0 group by a, b")
+	tk.MustExec("create materialized view mv_scalar (cnt) refresh complete as select count(*) from t")
+	// Make the view stale, so the results show which table is read.
+	tk.MustExec("insert into t values (3, 3, 3)")
+
+	tk.MustQuery("select @@tidb_opt_enable_materialized_view_rewrite").Check(testkit.Rows("0"))
+	tk.MustQuery("select a, b, count(*) from t where c > 0 group by a, b order by a, b").Check(testkit.Rows("1 1 1", "1 2 1", "2 1 2", "3 3 1"))
+
+	tk.MustExec("set @@tidb_opt_enable_materialized_view_rewrite = on")
+	// The groups of the view are the same as the groups of the query.
+	tk.MustQuery("select a, b, count(*), sum(c), max(c) from t where c > 0 group by a, b order by a, b").
+		Check(testkit.Rows("1 1 1 1 1", "1 2 1 2 2", "2 1 2 7 4"))
+	tk.MustQuery("explain format = 'brief' select a, b, count(*) from t where c > 0 group by a, b").CheckContain("table:mv")
+	// The rows of the view are aggregated again by fewer GROUP BY columns.
+	tk.MustQuery("select a, count(*), sum(c), max(c) from t where c > 0 group by a order by a").
+		Check(testkit.Rows("1 2 3 2", "2 2 7 4"))
+	tk.MustQuery("select count(*), sum(c) from t where c > 0").Check(testkit.Rows("4 10"))
+	tk.MustQuery("select count(*) from t where c > 0 and a > 10 group by a").Check(testkit.Rows())
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("4"))
+
+	// The queries not matching the view read the table.
+	tk.MustQuery("select a, count(*) from t group by a order by a").Check(testkit.Rows("1 2", "2 2", "3 1"))
+	tk.MustQuery("select a, min(c) from t where c > 0 group by a order by a").Check(testkit.Rows("1 1", "2 3", "3 3"))
+	tk.MustQuery("select c, count(*) from t where c > 0 group by c order by c").Check(testkit.Rows("1 1", "2 1", "3 2", "4 1"))
+	tk.MustQuery("select a, count(distinct b) from t where c > 0 group by a order by a").Check(testkit.Rows("1 2", "2 1", "3 1"))
+	tk.MustQuery("explain format = 'brief' select a, count(*) from t group by a").CheckContain("table:t")
+
+	// The view isn't read without the privilege.
+	tk.MustExec("create user u")
+	tk.MustExec("grant select on test.t to u")
+	tk2 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u", Hostname: "%"}, nil, nil, nil))
+	tk2.MustExec("set @@tidb_opt_enable_materialized_view_rewrite = on")
+	tk2.MustQuery("select count(*) from test.t").Check(testkit.Rows("5"))
+
+	// The views are found by the base table, including the views in other schemas.
+	tk.MustExec("create database mvdb")
+	tk.MustExec("create materialized view mvdb.mv_b (b, cnt) refresh complete as select b, count(*) from test.t group by b")
+	tk.MustExec("insert into t values (4, 1, 5)")
+	tk.MustQuery("select b, count(*) from t group by b order by b").Check(testkit.Rows("1 3", "2 1", "3 1"))
+	tk.MustExec("drop materialized view mvdb.mv_b")
+	tk.MustQuery("select b, count(*) from t group by b order by b").Check(testkit.Rows("1 4", "2 1", "3 1"))
+}
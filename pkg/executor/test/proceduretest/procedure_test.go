@@ -0,0 +1,272 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proceduretest
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndDropProcedure(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create procedure p1(in a int, out b varchar(10)) comment 'hello' begin select a; end")
+	tk.MustGetErrCode("create procedure p1() select 1", 1304)
+	tk.MustExec("create procedure if not exists p1() select 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1304 PROCEDURE p1 already exists"))
+	tk.MustGetErrCode("create procedure p2(a int, a int) select 1", 1330)
+	tk.MustGetErrCode("create procedure p2() begin declare a int; declare a int; end", 1331)
+	tk.MustGetErrCode("create procedure p2() begin open c; end", 1324)
+	tk.MustGetErrCode("create procedure p2() lbl: begin leave lbl2; end", 1308)
+	tk.MustGetErrCode("create procedure nodb.p2() select 1", 1049)
+
+	tk.MustQuery("select routine_schema, routine_name, routine_type, routine_definition, routine_comment from information_schema.routines").
+		Check(testkit.Rows("test p1 PROCEDURE begin select a; end hello"))
+	tk.MustQuery("show procedure status like 'p1'").CheckAt([]int{0, 1, 2, 6, 7}, testkit.Rows("test p1 PROCEDURE DEFINER hello"))
+	tk.MustQuery("show procedure status like 'p2'").Check(testkit.Rows())
+	rows := tk.MustQuery("show create procedure p1").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "p1", rows[0][0])
+	require.Equal(t, "CREATE PROCEDURE `p1`(in a int, out b varchar(10))\n    COMMENT 'hello'\nbegin select a; end", rows[0][2])
+
+	tk.MustExec("drop procedure p1")
+	tk.MustGetErrCode("drop procedure p1", 1305)
+	tk.MustExec("drop procedure if exists p1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1305 PROCEDURE test.p1 does not exist"))
+
+	// The procedures are dropped with the database.
+	tk.MustExec("create database db1")
+	tk.MustExec("create procedure db1.p() select 1")
+	tk.MustQuery("select count(*) from information_schema.routines where routine_schema = 'db1'").Check(testkit.Rows("1"))
+	tk.MustExec("drop database db1")
+	tk.MustQuery("select count(*) from information_schema.routines where routine_schema = 'db1'").Check(testkit.Rows("0"))
+}
+
+func TestCallProcedure(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v varchar(20))")
+
+	tk.MustExec("create procedure p_in(a int, b varchar(20)) begin insert into t values (a, b); select * from t order by id; end")
+	tk.MustQuery("call p_in(1, 'a')").Check(testkit.Rows("1 a"))
+	tk.MustQuery("call p_in(1 + 1, concat('b', 'c'))").Check(testkit.Rows("1 a", "2 bc"))
+	tk.MustGetErrCode("call p_in(1)", 1318)
+	tk.MustGetErrCode("call p_not_exists()", 1305)
+
+	tk.MustExec("create procedure p_out(in a int, out b int, inout c int) begin set b = a * 2; set c = c + a; end")
+	tk.MustExec("set @b = 100, @c = 10")
+	tk.MustExec("call p_out(3, @b, @c)")
+	tk.MustQuery("select @b, @c").Check(testkit.Rows("6 13"))
+	tk.MustGetErrCode("call p_out(3, 4, @c)", 1414)
+
+	// Local variables, loops and conditions.
+	tk.MustExec(`create procedure p_loop(n int, out total int) begin
+		declare i int default 0;
+		set total = 0;
+		while i < n do
+			set i = i + 1;
+			if i % 2 = 0 then set total = total + i; elseif i = 3 then set total = total + 100; end if;
+		end while;
+		repeat set i = i - 1; until i <= 0 end repeat;
+		set total = total + i;
+	end`)
+	tk.MustExec("call p_loop(5, @total)")
+	tk.MustQuery("select @total").Check(testkit.Rows("106"))
+
+	tk.MustExec(`create procedure p_label(out r int) begin
+		declare i int default 0;
+		lbl: while true do
+			set i = i + 1;
+			if i < 3 then iterate lbl; end if;
+			leave lbl;
+		end while lbl;
+		set r = i;
+	end`)
+	tk.MustExec("call p_label(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("3"))
+
+	tk.MustExec(`create procedure p_case(a int) begin
+		case a when 1 then select 'one'; when 2 then select 'two'; end case;
+	end`)
+	tk.MustQuery("call p_case(2)").Check(testkit.Rows("two"))
+	tk.MustGetErrCode("call p_case(3)", 1339)
+
+	// The local variable shadows the column with the same name.
+	tk.MustExec("create procedure p_shadow(id int) select id, v from t where t.id = id")
+	tk.MustQuery("call p_shadow(2)").Check(testkit.Rows("2 bc"))
+
+	// The recursion is limited by max_sp_recursion_depth, which disables it by default.
+	tk.MustExec("create table t_rec (n int)")
+	tk.MustExec(`create procedure p_rec(n int) begin
+		insert into t_rec values (n);
+		if n > 1 then call p_rec(n - 1); end if;
+	end`)
+	tk.MustExec("call p_rec(1)")
+	tk.MustGetErrCode("call p_rec(3)", errno.ErrSpRecursionLimit)
+	tk.MustExec("set @@max_sp_recursion_depth = 2")
+	tk.MustExec("delete from t_rec")
+	tk.MustExec("call p_rec(3)")
+	tk.MustQuery("select n from t_rec order by n").Check(testkit.Rows("1", "2", "3"))
+	tk.MustContainErrMsg("call p_rec(4)", "Recursive limit 2 (as set by the maxSpRecursionDepth variable) was exceeded for routine p_rec")
+	// The depth is restored after the error.
+	tk.MustExec("delete from t_rec")
+	tk.MustExec("call p_rec(3)")
+	tk.MustQuery("select count(*) from t_rec").Check(testkit.Rows("3"))
+}
+
+func TestProcedureCursorAndHandler(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustExec("insert into t values (1, 10), (2, 20), (3, 30)")
+
+	tk.MustExec(`create procedure p_cursor(out s int) begin
+		declare done int default 0;
+		declare x int;
+		declare c cursor for select v from t order by id;
+		declare continue handler for not found set done = 1;
+		set s = 0;
+		open c;
+		while done = 0 do
+			fetch c into x;
+			if done = 0 then set s = s + x; end if;
+		end while;
+		close c;
+	end`)
+	tk.MustExec("call p_cursor(@s)")
+	tk.MustQuery("select @s").Check(testkit.Rows("60"))
+
+	tk.MustExec(`create procedure p_fetch_no_data() begin
+		declare x int;
+		declare c cursor for select v from t where id > 10;
+		open c;
+		fetch c into x;
+	end`)
+	tk.MustGetErrCode("call p_fetch_no_data()", 1329)
+
+	tk.MustExec(`create procedure p_exit(out r varchar(10)) begin
+		declare exit handler for 1062 set r = 'dup';
+		set r = 'start';
+		insert into t values (1, 1);
+		set r = 'end';
+	end`)
+	tk.MustExec("call p_exit(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("dup"))
+
+	tk.MustExec(`create procedure p_continue(out r varchar(20)) begin
+		declare continue handler for sqlexception set r = concat(r, '-err');
+		set r = 'start';
+		insert into t values (1, 1);
+		set r = concat(r, '-end');
+	end`)
+	tk.MustExec("call p_continue(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("start-err-end"))
+
+	// The handler for the specific error code is preferred.
+	tk.MustExec(`create procedure p_priority(out r varchar(10)) begin
+		declare continue handler for sqlexception set r = 'exception';
+		declare continue handler for 1062 set r = 'code';
+		insert into t values (1, 1);
+	end`)
+	tk.MustExec("call p_priority(@r)")
+	tk.MustQuery("select @r").Check(testkit.Rows("code"))
+
+	tk.MustExec("create procedure p_unhandled() begin insert into t values (1, 1); end")
+	err := tk.ExecToErr("call p_unhandled()")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Duplicate entry")
+
+	// The rows of the cursors are tracked by the memory quota.
+	tk.MustExec("create table big (s varchar(1024))")
+	tk.MustExec("insert into big values (repeat('a', 1024))")
+	for i := 0; i < 11; i++ {
+		tk.MustExec("insert into big select * from big")
+	}
+	tk.MustExec(`create procedure p_big() begin
+		declare c cursor for select s from big;
+		open c;
+		close c;
+		open c;
+		close c;
+	end`)
+	tk.MustExec(`create procedure p_big2() begin
+		declare c1 cursor for select s from big;
+		declare c2 cursor for select s from big;
+		open c1;
+		open c2;
+	end`)
+	tk.MustExec("set global tidb_mem_oom_action = 'CANCEL'")
+	defer tk.MustExec("set global tidb_mem_oom_action = default")
+	tk.MustExec("set @@tidb_mem_quota_query = 3584 << 10")
+	// The rows of a cursor are released when it's closed.
+	tk.MustExec("call p_big()")
+	tk.MustGetErrCode("call p_big2()", errno.ErrMemoryExceedForQuery)
+	// The context of the caller is restored.
+	tk.MustQuery("select database()").Check(testkit.Rows("test"))
+	tk.MustExec("set @@tidb_mem_quota_query = default")
+	tk.MustExec("call p_big2()")
+	require.Zero(t, tk.Session().GetSessionVars().MemTracker.BytesConsumed())
+}
+
+func TestProcedurePrivilege(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int)")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("create user 'owner'@'%', 'caller'@'%'")
+	tk.MustExec("grant create routine, select on test.* to 'owner'@'%'")
+
+	owner := testkit.NewTestKit(t, store)
+	require.NoError(t, owner.Session().Auth(&auth.UserIdentity{Username: "owner", Hostname: "%"}, nil, nil, nil))
+	owner.MustExec("use test")
+	owner.MustExec("create procedure p_definer() select count(*) from t")
+	owner.MustExec("create procedure p_invoker() sql security invoker select count(*) from t")
+	owner.MustGetErrCode("create definer = 'root'@'%' procedure p_other() select 1", 1227)
+	owner.MustQuery("select definer, security_type from information_schema.routines where routine_name = 'p_definer'").
+		Check(testkit.Rows("owner@% DEFINER"))
+
+	caller := testkit.NewTestKit(t, store)
+	require.NoError(t, caller.Session().Auth(&auth.UserIdentity{Username: "caller", Hostname: "%"}, nil, nil, nil))
+	err := caller.ExecToErr("call test.p_definer()")
+	require.True(t, exeerrors.ErrProcaccessDenied.Equal(err), "%v", err)
+	caller.MustQuery("select count(*) from information_schema.routines").Check(testkit.Rows("0"))
+
+	tk.MustExec("grant execute on test.* to 'caller'@'%'")
+	caller.MustQuery("call test.p_definer()").Check(testkit.Rows("1"))
+	err = caller.ExecToErr("call test.p_invoker()")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "SELECT command denied")
+	// The privileges and the current database are restored after the call.
+	caller.MustGetErrCode("select * from test.t", 1142)
+	caller.MustQuery("select database()").Check(testkit.Rows("<nil>"))
+
+	// Only the definer can see the definition.
+	caller.MustQuery("select routine_name, routine_definition from information_schema.routines order by routine_name").
+		Check(testkit.Rows("p_definer <nil>", "p_invoker <nil>"))
+	owner.MustQuery("select routine_name, routine_definition from information_schema.routines order by routine_name").
+		Check(testkit.Rows("p_definer select count(*) from t", "p_invoker select count(*) from t"))
+
+	caller.MustGetErrCode("drop procedure test.p_definer", 1044)
+}
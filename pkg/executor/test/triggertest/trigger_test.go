@@ -0,0 +1,200 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggertest
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndDropTrigger(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create table t1 (a int)")
+
+	tk.MustExec("create trigger tr1 before insert on t for each row set new.a = new.a + 1")
+	tk.MustGetErrCode("create trigger tr1 after insert on t1 for each row set @a = 1", errno.ErrTrgAlreadyExists)
+	tk.MustExec("create trigger if not exists tr1 before insert on t for each row set new.a = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1359 Trigger already exists"))
+	tk.MustExec("create trigger tr2 before insert on t for each row precedes tr1 set new.a = new.a * 10")
+	tk.MustGetErrCode("create trigger tr3 after insert on t for each row follows tr1 set @a = 1", errno.ErrReferencedTrgDoesNotExist)
+	tk.MustGetErrCode("create trigger mysql.tr3 before insert on test.t for each row set @a = 1", errno.ErrTrgInWrongSchema)
+	tk.MustGetErrCode("create trigger mysql.tr3 before insert on mysql.user for each row set @a = 1", errno.ErrNoTriggersOnSystemSchema)
+	tk.MustExec("create view v as select * from t")
+	tk.MustGetErrCode("create trigger tr3 before insert on v for each row set @a = 1", errno.ErrTrgOnViewOrTempTable)
+	tk.MustGetErrCode("create trigger tr3 before insert on t_not_exists for each row set @a = 1", errno.ErrNoSuchTable)
+
+	tk.MustQuery("select trigger_name, event_manipulation, event_object_table, action_order, action_statement, action_timing, definer " +
+		"from information_schema.triggers where trigger_schema = 'test'").
+		Check(testkit.Rows("tr2 INSERT t 1 set new.a = new.a * 10 BEFORE root@%", "tr1 INSERT t 2 set new.a = new.a + 1 BEFORE root@%"))
+	tk.MustQuery("show triggers").CheckAt([]int{0, 1, 2, 3, 4}, testkit.RowsWithSep("|",
+		"tr2|INSERT|t|set new.a = new.a * 10|BEFORE", "tr1|INSERT|t|set new.a = new.a + 1|BEFORE"))
+	tk.MustQuery("show triggers like 't1'").Check(testkit.Rows())
+	tk.MustQuery("show create trigger tr1").CheckAt([]int{0, 2}, testkit.RowsWithSep("|",
+		"tr1|CREATE DEFINER=`root`@`%` TRIGGER `tr1` BEFORE INSERT ON `t` FOR EACH ROW set new.a = new.a + 1"))
+	require.ErrorContains(t, tk.QueryToErr("show create trigger tr3"), "Trigger does not exist")
+
+	// The triggers are activated in order.
+	tk.MustExec("insert into t values (1)")
+	tk.MustQuery("select a from t").Check(testkit.Rows("11"))
+
+	tk.MustExec("drop trigger tr2")
+	tk.MustGetErrCode("drop trigger tr2", errno.ErrTrgDoesNotExist)
+	tk.MustExec("drop trigger if exists tr2")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1360 Trigger does not exist"))
+	tk.MustExec("insert into t values (1)")
+	tk.MustQuery("select a from t order by a").Check(testkit.Rows("2", "11"))
+
+	// The triggers are not copied by CREATE TABLE LIKE, and they are dropped with the table.
+	tk.MustExec("create table t2 like t")
+	tk.MustExec("insert into t2 values (1)")
+	tk.MustQuery("select a from t2").Check(testkit.Rows("1"))
+	tk.MustExec("drop table t")
+	tk.MustQuery("select count(*) from information_schema.triggers where trigger_schema = 'test'").Check(testkit.Rows("0"))
+	tk.MustExec("create trigger tr1 before insert on t1 for each row set new.a = 0")
+}
+
+func TestTriggerRows(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int, g int as (v * 2) stored)")
+	tk.MustExec("create table log (id int auto_increment primary key, msg varchar(64))")
+
+	tk.MustExec("create trigger bi before insert on t for each row begin if new.v < 0 then set new.v = 0; end if; end")
+	tk.MustExec("create trigger ai after insert on t for each row insert into log (msg) values (concat('insert ', new.id, ' ', new.v))")
+	tk.MustExec("create trigger bu before update on t for each row set new.v = new.v + old.v")
+	tk.MustExec("create trigger au after update on t for each row insert into log (msg) values (concat('update ', old.v, ' ', new.v, ' ', new.g))")
+	tk.MustExec("create trigger bd before delete on t for each row insert into log (msg) values (concat('before delete ', old.id))")
+	tk.MustExec("create trigger ad after delete on t for each row insert into log (msg) values (concat('delete ', old.id, ' ', (select count(*) from t)))")
+
+	tk.MustExec("insert into t (id, v) values (1, -5), (2, 3)")
+	// The rows written by the triggers are not counted.
+	require.Equal(t, uint64(2), tk.Session().AffectedRows())
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 0 0", "2 3 6"))
+	tk.MustExec("update t set v = 10 where id = 2")
+	// The generated columns are evaluated with the values changed by the BEFORE triggers.
+	tk.MustQuery("select * from t where id = 2").Check(testkit.Rows("2 13 26"))
+	tk.MustExec("delete from t where id = 1")
+	tk.MustQuery("select msg from log order by id").Check(testkit.Rows(
+		"insert 1 0", "insert 2 3", "update 3 13 26", "before delete 1", "delete 1 1"))
+
+	// REPLACE deletes the duplicate row, and INSERT ... ON DUPLICATE KEY UPDATE updates it.
+	tk.MustExec("delete from log")
+	tk.MustExec("replace into t (id, v) values (2, 1)")
+	tk.MustExec("insert into t (id, v) values (2, 1) on duplicate key update v = 5")
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 6 12"))
+	tk.MustQuery("select msg from log order by id").Check(testkit.Rows(
+		"before delete 2", "delete 2 1", "insert 2 1", "update 1 6 12"))
+
+	// The changes of the trigger are rolled back with the statement.
+	tk.MustExec("delete from log")
+	tk.MustExec("create trigger ai2 after insert on t for each row follows ai insert into log values (new.v, 'x')")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t (id, v) values (3, 3)")
+	tk.MustGetErrCode("insert into t (id, v) values (4, 7), (5, 7)", errno.ErrDupEntry)
+	tk.MustExec("commit")
+	tk.MustQuery("select id from t order by id").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select msg from log order by id").Check(testkit.Rows("x", "insert 3 3"))
+}
+
+func TestTriggerErrors(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create table t1 (a int)")
+
+	tk.MustExec("create trigger tr before insert on t for each row set old.a = 1")
+	tk.MustGetErrCode("insert into t values (1)", errno.ErrTrgNoSuchRowInTrg)
+	tk.MustExec("drop trigger tr")
+	tk.MustExec("create trigger tr after insert on t for each row set new.a = 1")
+	tk.MustGetErrCode("insert into t values (1)", errno.ErrTrgCantChangeRow)
+	tk.MustExec("drop trigger tr")
+	tk.MustExec("create trigger tr after insert on t for each row insert into t values (1)")
+	tk.MustGetErrCode("insert into t values (1)", errno.ErrCantUpdateUsedTableInSfOrTrg)
+	tk.MustExec("drop trigger tr")
+	tk.MustExec("create trigger tr after insert on t for each row commit")
+	tk.MustGetErrCode("insert into t values (1)", errno.ErrCommitNotAllowedInSfOrTrg)
+	tk.MustExec("drop trigger tr")
+	tk.MustExec("create trigger tr after insert on t for each row select 1")
+	tk.MustGetErrCode("insert into t values (1)", errno.ErrSpNoRetset)
+	tk.MustExec("drop trigger tr")
+
+	// The triggers on the other tables are activated, but they can't write the tables in use.
+	tk.MustExec("create trigger tr after insert on t for each row insert into t1 values (new.a)")
+	tk.MustExec("create trigger tr1 after insert on t1 for each row delete from t")
+	tk.MustGetErrCode("insert into t values (1)", errno.ErrCantUpdateUsedTableInSfOrTrg)
+	tk.MustExec("drop trigger tr1")
+	tk.MustExec("insert into t values (1)")
+	tk.MustQuery("select a from t1").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t").Check(testkit.Rows("1"))
+
+	// The rows recorded for the AFTER triggers are tracked by the memory quota of the statement.
+	tk.MustExec("create table src (s varchar(1024))")
+	tk.MustExec("insert into src values (repeat('a', 1024))")
+	for i := 0; i < 10; i++ {
+		tk.MustExec("insert into src select * from src")
+	}
+	tk.MustExec("set global tidb_mem_oom_action = 'CANCEL'")
+	defer tk.MustExec("set global tidb_mem_oom_action = default")
+	tk.MustExec("set @@tidb_mem_quota_query = 2 << 20")
+	tk.MustExec("begin")
+	tk.MustExec("delete from src")
+	tk.MustExec("rollback")
+	tk.MustExec("create trigger tr2 after delete on src for each row set @n = 1")
+	tk.MustGetErrCode("delete from src", errno.ErrMemoryExceedForQuery)
+	tk.MustExec("set @@tidb_mem_quota_query = default")
+	tk.MustExec("delete from src")
+	tk.MustQuery("select count(*) from src").Check(testkit.Rows("0"))
+}
+
+func TestTriggerPrivilege(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create user u1")
+	tk.MustExec("grant insert, select on test.t to u1")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil, nil))
+	tk1.MustExec("use test")
+	tk1.MustGetErrCode("create trigger tr after insert on t for each row insert into t1 values (new.a)", errno.ErrTableaccessDenied)
+	tk.MustExec("grant trigger on test.t to u1")
+	tk1.MustExec("create trigger tr after insert on t for each row insert into t1 values (new.a)")
+	tk1.MustQuery("show triggers").CheckAt([]int{0, 7}, testkit.Rows("tr u1@%"))
+
+	// The trigger is executed with the privileges of the definer.
+	tk.MustExec("create user u2")
+	tk.MustExec("grant insert on test.t to u2")
+	tk2 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, nil, nil, nil))
+	tk2.MustExec("use test")
+	tk2.MustGetErrCode("insert into t values (1)", errno.ErrTableaccessDenied)
+	tk.MustExec("grant insert on test.t1 to u1")
+	tk2.MustExec("insert into t values (1)")
+	tk.MustQuery("select a from t1").Check(testkit.Rows("1"))
+	tk2.MustQuery("show triggers").Check(testkit.Rows())
+	tk2.MustGetErrCode("drop trigger tr", errno.ErrDBaccessDenied)
+}
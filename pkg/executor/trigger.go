@@ -0,0 +1,530 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/planner"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+	"github.com/pingcap/tidb/pkg/util/memory"
+)
+
+// WithTrigger indicates the executor activates triggers.
+type WithTrigger interface {
+	GetTriggers() []*TriggerExec
+}
+
+func hasTriggers(e exec.Executor) bool {
+	x, ok := e.(WithTrigger)
+	return ok && len(x.GetTriggers()) > 0
+}
+
+// TriggerExec activates the triggers of a table for the rows written by a DML executor.
+//
+// The BEFORE triggers are activated right before a row is written, and they can change the NEW row by
+// `SET NEW.col = value`. The AFTER triggers are activated after the statement like the foreign key cascades, so the
+// statements in their bodies can read all the rows written by the statement.
+//
+// The statements in the trigger bodies are executed within the statement which activates the triggers. Since
+// `StmtCommit` can't be called in the middle of a statement, the foreign key cascades and the AFTER triggers of the
+// statements executed by the BEFORE triggers are handled after the statement too.
+type TriggerExec struct {
+	b      *executorBuilder
+	tbl    table.Table
+	dbInfo *model.DBInfo
+	// parent is the trigger executor which activates the trigger executing the statement, the tables of the parent
+	// and its ancestors can't be written by the statement.
+	parent *TriggerExec
+
+	programs map[*model.TriggerInfo]*triggerProgram
+	genExprs []expression.Expression
+	// afterRows are the rows for the AFTER triggers, and their memory is tracked by memTracker.
+	afterRows  []triggerRow
+	memTracker *memory.Tracker
+	// nested are the executors of the statements executed by the BEFORE triggers.
+	nested []exec.Executor
+	// inAfter is set when the AFTER triggers are being activated, and depth is the depth of the foreign key cascades
+	// and the triggers at that time.
+	inAfter bool
+	depth   int
+}
+
+// triggerRow is a row written by a statement, OLD is nil for INSERT and NEW is nil for DELETE.
+type triggerRow struct {
+	event  model.TriggerEvent
+	oldRow []types.Datum
+	newRow []types.Datum
+}
+
+// triggerProgram is a parsed trigger body and the interpreter executing it.
+type triggerProgram struct {
+	trigger *model.TriggerInfo
+	body    ast.StmtNode
+	interp  *procInterpreter
+}
+
+func (b *executorBuilder) buildTriggerExec(tbl table.Table) (*TriggerExec, error) {
+	tblInfo := tbl.Meta()
+	// The foreign key cascades don't activate triggers.
+	if len(tblInfo.Triggers) == 0 || b.inFKCascade {
+		return nil, nil
+	}
+	dbInfo, ok := infoschema.SchemaByTable(b.is, tblInfo)
+	if !ok {
+		return nil, errors.Errorf("schema of table %s not found", tblInfo.Name.O)
+	}
+	memTracker := memory.NewTracker(memory.LabelForTriggerRows, -1)
+	memTracker.AttachTo(b.ctx.GetSessionVars().StmtCtx.MemTracker)
+	return &TriggerExec{
+		b:          b,
+		tbl:        tbl,
+		dbInfo:     dbInfo,
+		programs:   make(map[*model.TriggerInfo]*triggerProgram),
+		memTracker: memTracker,
+	}, nil
+}
+
+func (b *executorBuilder) buildTblID2TriggerExecs(tblID2Table map[int64]table.Table) (map[int64]*TriggerExec, error) {
+	var triggers map[int64]*TriggerExec
+	for tid, tbl := range tblID2Table {
+		t, err := b.buildTriggerExec(tbl)
+		if err != nil || t == nil {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if triggers == nil {
+			triggers = make(map[int64]*TriggerExec)
+		}
+		triggers[tid] = t
+	}
+	return triggers, nil
+}
+
+func (t *TriggerExec) hasTrigger(timing model.TriggerTiming, event model.TriggerEvent) bool {
+	for _, trigger := range t.tbl.Meta().Triggers {
+		if trigger.Timing == timing && trigger.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+// activateBefore activates the BEFORE triggers for a row, the NEW row is changed if it's assigned by the triggers.
+func (t *TriggerExec) activateBefore(ctx context.Context, event model.TriggerEvent, oldRow, newRow []types.Datum) error {
+	if !t.hasTrigger(model.TriggerBefore, event) {
+		return nil
+	}
+	scope := t.rowScope(model.TriggerBefore, oldRow, newRow)
+	if err := t.activate(ctx, model.TriggerBefore, event, scope); err != nil {
+		return err
+	}
+	if newRow == nil {
+		return nil
+	}
+	ec := t.b.ctx.GetSessionVars().StmtCtx.ErrCtx()
+	for _, col := range t.tbl.Cols() {
+		if col.IsGenerated() {
+			continue
+		}
+		newRow[col.Offset] = scope.vars["new."+col.Name.L].val
+		if !mysql.HasAutoIncrementFlag(col.GetFlag()) {
+			if err := col.HandleBadNull(ec, &newRow[col.Offset], 0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// evalGeneratedColumns evaluates the generated columns of the NEW row again after it's changed by the BEFORE triggers.
+func (t *TriggerExec) evalGeneratedColumns(event model.TriggerEvent, newRow []types.Datum) error {
+	if !t.hasTrigger(model.TriggerBefore, event) {
+		return nil
+	}
+	sctx := t.b.ctx
+	evalCtx := sctx.GetExprCtx().GetEvalCtx()
+	idx := 0
+	for _, col := range t.tbl.Cols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		if idx == len(t.genExprs) {
+			expr, err := expression.ParseSimpleExpr(sctx.GetExprCtx(), col.GeneratedExprString,
+				expression.WithTableInfo(t.dbInfo.Name.L, t.tbl.Meta()))
+			if err != nil {
+				return err
+			}
+			t.genExprs = append(t.genExprs, expr)
+		}
+		val, err := t.genExprs[idx].Eval(evalCtx, chunk.MutRowFromDatums(newRow).ToRow())
+		if err != nil {
+			return err
+		}
+		if newRow[col.Offset], err = table.CastValue(sctx, val, col.ToInfo(), false, false); err != nil {
+			return err
+		}
+		idx++
+	}
+	return nil
+}
+
+// recordAfter records a row for the AFTER triggers.
+func (t *TriggerExec) recordAfter(event model.TriggerEvent, oldRow, newRow []types.Datum) {
+	if !t.hasTrigger(model.TriggerAfter, event) {
+		return
+	}
+	row := triggerRow{event: event}
+	if oldRow != nil {
+		row.oldRow = types.CloneRow(oldRow)
+	}
+	if newRow != nil {
+		row.newRow = types.CloneRow(newRow)
+	}
+	t.afterRows = append(t.afterRows, row)
+	t.memTracker.Consume(types.EstimatedMemUsage(row.oldRow, 1) + types.EstimatedMemUsage(row.newRow, 1))
+}
+
+// activateAfter handles the statements executed by the BEFORE triggers and activates the AFTER triggers for the rows
+// recorded by the statement.
+func (t *TriggerExec) activateAfter(ctx context.Context, depth int) error {
+	t.inAfter, t.depth = true, depth
+	nested := t.nested
+	t.nested = nil
+	for _, e := range nested {
+		if err := handleForeignKeyTrigger(ctx, t.b.ctx, e, depth); err != nil {
+			return err
+		}
+	}
+	rows := t.afterRows
+	t.afterRows = nil
+	defer t.memTracker.Consume(-t.memTracker.BytesConsumed())
+	for _, row := range rows {
+		scope := t.rowScope(model.TriggerAfter, row.oldRow, row.newRow)
+		if err := t.activate(ctx, model.TriggerAfter, row.event, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowScope builds the scope holding the columns of the OLD and NEW rows.
+func (t *TriggerExec) rowScope(timing model.TriggerTiming, oldRow, newRow []types.Datum) *procScope {
+	scope := newProcScope(nil)
+	for _, col := range t.tbl.Cols() {
+		oldVar := &procVar{tp: &col.FieldType, readOnlyErr: exeerrors.ErrTrgCantChangeRow.GenWithStackByArgs("OLD", "")}
+		if oldRow != nil {
+			oldVar.val = oldRow[col.Offset]
+		} else {
+			oldVar.invalidErr = exeerrors.ErrTrgNoSuchRowInTrg.GenWithStackByArgs("OLD", "INSERT")
+		}
+		scope.vars["old."+col.Name.L] = oldVar
+		newVar := &procVar{tp: &col.FieldType}
+		if newRow != nil {
+			newVar.val = newRow[col.Offset]
+		} else {
+			newVar.invalidErr = exeerrors.ErrTrgNoSuchRowInTrg.GenWithStackByArgs("NEW", "DELETE")
+		}
+		if timing == model.TriggerAfter {
+			newVar.readOnlyErr = exeerrors.ErrTrgCantChangeRow.GenWithStackByArgs("NEW", "after ")
+		}
+		scope.vars["new."+col.Name.L] = newVar
+	}
+	return scope
+}
+
+// activate runs the triggers with the timing and the event in order.
+func (t *TriggerExec) activate(ctx context.Context, timing model.TriggerTiming, event model.TriggerEvent, scope *procScope) error {
+	sc := t.b.ctx.GetSessionVars().StmtCtx
+	if !sc.InHandleForeignKeyTrigger {
+		// The rows written by the triggers are not counted in the affected rows.
+		sc.InHandleForeignKeyTrigger = true
+		defer func() {
+			sc.InHandleForeignKeyTrigger = false
+		}()
+	}
+	for _, trigger := range t.tbl.Meta().Triggers {
+		if trigger.Timing != timing || trigger.Event != event {
+			continue
+		}
+		if err := t.run(ctx, trigger, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run executes the body of a trigger as its definer.
+func (t *TriggerExec) run(ctx context.Context, trigger *model.TriggerInfo, scope *procScope) error {
+	p, err := t.program(trigger)
+	if err != nil {
+		return err
+	}
+	var user, host string
+	if trigger.Definer != nil {
+		user, host = trigger.Definer.Username, trigger.Definer.Hostname
+	}
+	restore, err := enterRoutine(t.b.ctx, t.dbInfo.Name.O, trigger.SQLMode, user, host)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	return p.interp.run(ctx, scope, p.body)
+}
+
+// program parses the definition of the trigger, the parsed body is cached for the other rows.
+func (t *TriggerExec) program(trigger *model.TriggerInfo) (*triggerProgram, error) {
+	if p, ok := t.programs[trigger]; ok {
+		return p, nil
+	}
+	sctx := t.b.ctx
+	sqlMode, err := mysql.GetSQLMode(trigger.SQLMode)
+	if err != nil {
+		return nil, err
+	}
+	prsr := parser.New()
+	prsr.SetSQLMode(sqlMode)
+	prsr.SetParserConfig(sctx.GetSessionVars().BuildParserConfig())
+	stmt, err := prsr.ParseOneStmt(trigger.Definition, trigger.Charset, trigger.Collation)
+	if err != nil {
+		return nil, err
+	}
+	create, ok := stmt.(*ast.CreateTriggerStmt)
+	if !ok {
+		return nil, errors.Errorf("invalid definition of trigger %s.%s", t.dbInfo.Name.O, trigger.Name.O)
+	}
+	i := &procInterpreter{
+		sctx:      sctx,
+		parser:    prsr,
+		charset:   mysql.DefaultCharset,
+		collation: mysql.DefaultCollationName,
+		sqls:      make(map[ast.Node]string),
+	}
+	if t.dbInfo.Charset != "" && t.dbInfo.Collate != "" {
+		i.charset, i.collation = t.dbInfo.Charset, t.dbInfo.Collate
+	}
+	i.execNested = t.execNested
+	i.memTracker = memory.NewTracker(memory.LabelForStoredRoutine, -1)
+	i.memTracker.AttachTo(sctx.GetSessionVars().StmtCtx.MemTracker)
+	p := &triggerProgram{trigger: trigger, body: create.Body, interp: i}
+	t.programs[trigger] = p
+	return p, nil
+}
+
+// checkTriggerStmt checks whether the statement can be executed in a trigger.
+func checkTriggerStmt(stmt ast.StmtNode) error {
+	switch stmt.(type) {
+	case *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.LockTablesStmt, *ast.UnlockTablesStmt,
+		*ast.XAStmt, *ast.ProcedureInfo, *ast.DropProcedureStmt, ast.DDLNode:
+		return exeerrors.ErrCommitNotAllowedInSfOrTrg
+	case *ast.PrepareStmt, *ast.ExecuteStmt, *ast.DeallocateStmt:
+		return exeerrors.ErrStmtNotAllowedInSfOrTrg.GenWithStackByArgs("Dynamic SQL")
+	case *ast.CallStmt:
+		return exeerrors.ErrStmtNotAllowedInSfOrTrg.GenWithStackByArgs("CALL")
+	}
+	return nil
+}
+
+// execNested executes a statement in the trigger body within the statement which activates the trigger.
+func (t *TriggerExec) execNested(ctx context.Context, stmt ast.StmtNode) ([]*ast.ResultField, []chunk.Row, error) {
+	if err := checkTriggerStmt(stmt); err != nil {
+		return nil, nil, err
+	}
+	sctx := t.b.ctx
+	if err := plannercore.Preprocess(ctx, sctx, stmt); err != nil {
+		return nil, nil, err
+	}
+	p, names, err := planner.OptimizeForTrigger(ctx, sctx.GetPlanCtx(), stmt, t.b.is)
+	if err != nil {
+		return nil, nil, err
+	}
+	e := t.b.build(p)
+	if t.b.err != nil {
+		return nil, nil, t.b.err
+	}
+	if x, ok := e.(WithTrigger); ok {
+		for _, nested := range x.GetTriggers() {
+			for parent := t; parent != nil; parent = parent.parent {
+				if parent.tbl.Meta().ID == nested.tbl.Meta().ID {
+					return nil, nil, exeerrors.ErrCantUpdateUsedTableInSfOrTrg.GenWithStackByArgs(parent.tbl.Meta().Name.O)
+				}
+			}
+			nested.parent = t
+		}
+	}
+
+	if err := exec.Open(ctx, e); err != nil {
+		terror.Log(exec.Close(e))
+		return nil, nil, err
+	}
+	var rows []chunk.Row
+	for {
+		chk := exec.NewFirstChunk(e)
+		if err = exec.Next(ctx, e, chk); err != nil || chk.NumRows() == 0 {
+			break
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			rows = append(rows, chk.GetRow(i))
+		}
+	}
+	if closeErr := exec.Close(e); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.inAfter {
+		// Flush the changes like the foreign key cascades, so the following statements can read them.
+		sctx.StmtCommit(ctx)
+		if err := handleForeignKeyTrigger(ctx, sctx, e, t.depth+1); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		t.nested = append(t.nested, e)
+	}
+	if e.Schema().Len() == 0 {
+		return nil, nil, nil
+	}
+	return colNames2ResultFields(e.Schema(), names, sctx.GetSessionVars().CurrentDB), rows, nil
+}
+
+// triggerVisible checks whether the user has the TRIGGER privilege on the table, which is required to see the triggers.
+func triggerVisible(sctx sessionctx.Context, schema model.CIStr, tblInfo *model.TableInfo) bool {
+	checker := privilege.GetPrivilegeManager(sctx)
+	return checker == nil || checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.L, tblInfo.Name.L, "", mysql.TriggerPriv)
+}
+
+func triggerCreated(sctx sessionctx.Context, trigger *model.TriggerInfo) types.Time {
+	return types.NewTime(types.FromGoTime(trigger.Created.In(sctx.GetSessionVars().Location())), mysql.TypeDatetime, 2)
+}
+
+func dbCollation(dbInfo *model.DBInfo) string {
+	if dbInfo.Collate == "" {
+		return mysql.DefaultCollationName
+	}
+	return dbInfo.Collate
+}
+
+func (e *ShowExec) fetchShowTriggers() error {
+	dbInfo, ok := e.is.SchemaByName(e.DBName)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(e.DBName.O)
+	}
+	for _, tblInfo := range e.is.SchemaTableInfos(dbInfo.Name) {
+		if len(tblInfo.Triggers) == 0 || !triggerVisible(e.Ctx(), dbInfo.Name, tblInfo) {
+			continue
+		}
+		for _, trigger := range tblInfo.Triggers {
+			e.appendRow([]any{trigger.Name.O, trigger.Event.String(), tblInfo.Name.O, trigger.Body, trigger.Timing.String(),
+				triggerCreated(e.Ctx(), trigger), trigger.SQLMode, trigger.Definer.String(), trigger.Charset,
+				trigger.Collation, dbCollation(dbInfo)})
+		}
+	}
+	return nil
+}
+
+func (e *ShowExec) fetchShowCreateTrigger() error {
+	name := e.Trigger
+	dbInfo, ok := e.is.SchemaByName(name.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(name.Schema.O)
+	}
+	for _, tblInfo := range e.is.SchemaTableInfos(dbInfo.Name) {
+		trigger := tblInfo.FindTriggerByName(name.Name.L)
+		if trigger == nil {
+			continue
+		}
+		if !triggerVisible(e.Ctx(), dbInfo.Name, tblInfo) {
+			user := e.Ctx().GetSessionVars().User
+			return plannererrors.ErrTableaccessDenied.GenWithStackByArgs("TRIGGER", user.AuthUsername, user.AuthHostname, tblInfo.Name.O)
+		}
+		e.appendRow([]any{trigger.Name.O, trigger.SQLMode, trigger.Definition, trigger.Charset, trigger.Collation,
+			dbCollation(dbInfo), triggerCreated(e.Ctx(), trigger)})
+		return nil
+	}
+	return dbterror.ErrTrgDoesNotExist
+}
+
+func (e *memtableRetriever) setDataForTriggers(sctx sessionctx.Context, schemas []model.CIStr) {
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		dbInfo, ok := e.is.SchemaByName(schema)
+		if !ok {
+			continue
+		}
+		for _, tblInfo := range e.is.SchemaTableInfos(schema) {
+			if len(tblInfo.Triggers) == 0 || !triggerVisible(sctx, schema, tblInfo) {
+				continue
+			}
+			for _, trigger := range tblInfo.Triggers {
+				// The action order is the position among the triggers with the same timing and event.
+				order := 0
+				for _, other := range tblInfo.Triggers {
+					if other.Timing == trigger.Timing && other.Event == trigger.Event {
+						order++
+					}
+					if other == trigger {
+						break
+					}
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal,         // TRIGGER_CATALOG
+					dbInfo.Name.O,                 // TRIGGER_SCHEMA
+					trigger.Name.O,                // TRIGGER_NAME
+					trigger.Event.String(),        // EVENT_MANIPULATION
+					infoschema.CatalogVal,         // EVENT_OBJECT_CATALOG
+					dbInfo.Name.O,                 // EVENT_OBJECT_SCHEMA
+					tblInfo.Name.O,                // EVENT_OBJECT_TABLE
+					order,                         // ACTION_ORDER
+					nil,                           // ACTION_CONDITION
+					trigger.Body,                  // ACTION_STATEMENT
+					"ROW",                         // ACTION_ORIENTATION
+					trigger.Timing.String(),       // ACTION_TIMING
+					nil,                           // ACTION_REFERENCE_OLD_TABLE
+					nil,                           // ACTION_REFERENCE_NEW_TABLE
+					"OLD",                         // ACTION_REFERENCE_OLD_ROW
+					"NEW",                         // ACTION_REFERENCE_NEW_ROW
+					triggerCreated(sctx, trigger), // CREATED
+					trigger.SQLMode,               // SQL_MODE
+					trigger.Definer.String(),      // DEFINER
+					trigger.Charset,               // CHARACTER_SET_CLIENT
+					trigger.Collation,             // COLLATION_CONNECTION
+					dbCollation(dbInfo),           // DATABASE_COLLATION
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
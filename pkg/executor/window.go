@@ -234,18 +234,110 @@ func (p *aggWindowProcessor) resetPartialResult() {
 	}
 }
 
+// peerComparer decides whether two rows are peers, that is, they are equal on the order by columns.
+type peerComparer struct {
+	cmpFuncs []chunk.CompareFunc
+	colIdx   []int
+}
+
+func newPeerComparer(orderByCols []*expression.Column) *peerComparer {
+	c := &peerComparer{
+		cmpFuncs: make([]chunk.CompareFunc, 0, len(orderByCols)),
+		colIdx:   make([]int, 0, len(orderByCols)),
+	}
+	for _, col := range orderByCols {
+		cmpFunc := chunk.GetCompareFunc(col.RetType)
+		if cmpFunc == nil {
+			continue
+		}
+		c.cmpFuncs = append(c.cmpFuncs, cmpFunc)
+		c.colIdx = append(c.colIdx, col.Index)
+	}
+	return c
+}
+
+func (c *peerComparer) isPeer(prev, curr chunk.Row) bool {
+	for i, idx := range c.colIdx {
+		if c.cmpFuncs[i](prev, idx, curr, idx) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// rowFrameWindowProcessor processes the ROWS and GROUPS frames, whose offsets are
+// counted by rows and peer groups respectively.
 type rowFrameWindowProcessor struct {
 	windowFuncs    []aggfuncs.AggFunc
 	partialResults []aggfuncs.PartialResult
 	start          *core.FrameBound
 	end            *core.FrameBound
 	curRowIdx      uint64
+
+	// peerComparer is only set for GROUPS frames.
+	peerComparer *peerComparer
+	// peerGroupIdx[i] is the index of the peer group that the i-th row of the partition belongs to.
+	peerGroupIdx []uint64
+	// peerGroupStart[g] is the offset of the first row of the g-th peer group,
+	// the last element is the number of rows in the partition.
+	peerGroupStart []uint64
+}
+
+func (p *rowFrameWindowProcessor) buildPeerGroups(rows []chunk.Row) {
+	p.peerGroupIdx = p.peerGroupIdx[:0]
+	p.peerGroupStart = p.peerGroupStart[:0]
+	for i := range rows {
+		if i == 0 || !p.peerComparer.isPeer(rows[i-1], rows[i]) {
+			p.peerGroupStart = append(p.peerGroupStart, uint64(i))
+		}
+		p.peerGroupIdx = append(p.peerGroupIdx, uint64(len(p.peerGroupStart)-1))
+	}
+	p.peerGroupStart = append(p.peerGroupStart, uint64(len(rows)))
+}
+
+func (p *rowFrameWindowProcessor) getGroupsStartOffset(numRows uint64) uint64 {
+	group, numGroups := p.peerGroupIdx[p.curRowIdx], uint64(len(p.peerGroupStart)-1)
+	switch p.start.Type {
+	case ast.Preceding:
+		if group >= p.start.Num {
+			return p.peerGroupStart[group-p.start.Num]
+		}
+		return 0
+	case ast.Following:
+		if p.start.Num >= numGroups-group {
+			return numRows
+		}
+		return p.peerGroupStart[group+p.start.Num]
+	default: // ast.CurrentRow
+		return p.peerGroupStart[group]
+	}
+}
+
+func (p *rowFrameWindowProcessor) getGroupsEndOffset(numRows uint64) uint64 {
+	group, numGroups := p.peerGroupIdx[p.curRowIdx], uint64(len(p.peerGroupStart)-1)
+	switch p.end.Type {
+	case ast.Preceding:
+		if group >= p.end.Num {
+			return p.peerGroupStart[group-p.end.Num+1]
+		}
+		return 0
+	case ast.Following:
+		if p.end.Num >= numGroups-group {
+			return numRows
+		}
+		return p.peerGroupStart[group+p.end.Num+1]
+	default: // ast.CurrentRow
+		return p.peerGroupStart[group+1]
+	}
 }
 
 func (p *rowFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
 	if p.start.UnBounded {
 		return 0
 	}
+	if p.peerComparer != nil {
+		return p.getGroupsStartOffset(numRows)
+	}
 	switch p.start.Type {
 	case ast.Preceding:
 		if p.curRowIdx >= p.start.Num {
@@ -269,6 +361,9 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	if p.end.UnBounded {
 		return numRows
 	}
+	if p.peerComparer != nil {
+		return p.getGroupsEndOffset(numRows)
+	}
 	switch p.end.Type {
 	case ast.Preceding:
 		if p.curRowIdx >= p.end.Num {
@@ -288,7 +383,11 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (*rowFrameWindowProcessor) consumeGroupRows(_ sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+func (p *rowFrameWindowProcessor) consumeGroupRows(_ sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+	// The rows of the whole partition are passed in, the peer groups only need to be built once.
+	if p.peerComparer != nil && len(p.peerGroupIdx) != len(rows) {
+		p.buildPeerGroups(rows)
+	}
 	return rows, nil
 }
 
@@ -374,6 +473,8 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
 	p.curRowIdx = 0
+	p.peerGroupIdx = p.peerGroupIdx[:0]
+	p.peerGroupStart = p.peerGroupStart[:0]
 }
 
 type rangeFrameWindowProcessor struct {
@@ -237,6 +237,115 @@ func TestWindowFunctionsDataReference(t *testing.T) {
 		Check(testkit.Rows("1 1", "2 1", "3 1"))
 }
 
+func TestWindowGroupsFrameAndNullTreatment(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a int, v int)")
+	tk.MustExec("insert into t values (1,1,null),(2,1,10),(3,2,null),(4,2,20),(5,3,30),(6,4,null)")
+	for _, pipelined := range []string{"0", "1"} {
+		tk.MustExec("set @@tidb_enable_pipelined_window_function = " + pipelined)
+		for _, chunkSize := range []int{1, 32} {
+			tk.Session().GetSessionVars().MaxChunkSize = chunkSize
+			// GROUPS frames.
+			tk.MustQuery("select id, sum(id) over (order by a groups between 1 preceding and current row) from t order by id").
+				Check(testkit.Rows("1 3", "2 3", "3 10", "4 10", "5 12", "6 11"))
+			tk.MustQuery("select id, count(*) over (order by a groups between current row and 1 following) from t order by id").
+				Check(testkit.Rows("1 4", "2 4", "3 3", "4 3", "5 2", "6 1"))
+			tk.MustQuery("select id, sum(id) over (order by a groups between 2 preceding and 1 preceding) from t order by id").
+				Check(testkit.Rows("1 <nil>", "2 <nil>", "3 3", "4 3", "5 10", "6 12"))
+			tk.MustQuery("select id, sum(id) over (order by a groups between 1 following and unbounded following) from t order by id").
+				Check(testkit.Rows("1 18", "2 18", "3 11", "4 11", "5 6", "6 <nil>"))
+			tk.MustQuery("select id, max(id) over (order by a desc groups between 1 preceding and 1 preceding) from t order by id").
+				Check(testkit.Rows("1 4", "2 4", "3 5", "4 5", "5 6", "6 <nil>"))
+
+			// IGNORE NULLS and FROM LAST.
+			tk.MustQuery("select id, last_value(v) ignore nulls over (order by id rows between unbounded preceding and current row) from t").
+				Check(testkit.Rows("1 <nil>", "2 10", "3 10", "4 20", "5 30", "6 30"))
+			tk.MustQuery("select id, first_value(v) ignore nulls over (order by id rows between current row and unbounded following) from t").
+				Check(testkit.Rows("1 10", "2 10", "3 20", "4 20", "5 30", "6 <nil>"))
+			tk.MustQuery("select id, nth_value(v, 2) ignore nulls over (order by id rows between unbounded preceding and unbounded following) from t").
+				Check(testkit.Rows("1 20", "2 20", "3 20", "4 20", "5 20", "6 20"))
+			tk.MustQuery("select id, nth_value(v, 2) from last over (order by id rows between unbounded preceding and current row) from t").
+				Check(testkit.Rows("1 <nil>", "2 <nil>", "3 10", "4 <nil>", "5 20", "6 30"))
+			tk.MustQuery("select id, nth_value(v, 2) from last ignore nulls over (order by id rows between unbounded preceding and current row) from t").
+				Check(testkit.Rows("1 <nil>", "2 <nil>", "3 <nil>", "4 10", "5 20", "6 20"))
+			tk.MustQuery("select id, lead(v) ignore nulls over (order by id), lag(v, 1, -1) ignore nulls over (order by id) from t").
+				Check(testkit.Rows("1 10 -1", "2 20 -1", "3 20 10", "4 30 10", "5 <nil> 20", "6 <nil> 30"))
+			tk.MustQuery("select id, lead(v, 2) ignore nulls over (order by id) from t").
+				Check(testkit.Rows("1 20", "2 30", "3 30", "4 <nil>", "5 <nil>", "6 <nil>"))
+		}
+	}
+	tk.MustQuery("explain format = 'brief' select nth_value(v, 2) from last ignore nulls over (order by a groups between 1 preceding and 1 following) from t").
+		CheckContain("nth_value(test.t.v, 2) from last ignore nulls")
+	tk.MustQuery("explain format = 'brief' select nth_value(v, 2) from last ignore nulls over (order by a groups between 1 preceding and 1 following) from t").
+		CheckContain("groups between 1 preceding and 1 following")
+	tk.MustGetErrMsg("select sum(id) over (order by a groups between interval 1 day preceding and current row) from t",
+		"[planner:3596]Window '<unnamed window>': INTERVAL can only be used with RANGE frames.")
+}
+
+func TestWindowDistinctAndGroupConcat(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, p int, v int, d decimal(5,2), f double, s varchar(10))")
+	tk.MustExec("insert into t values (1,1,1,1.5,0.5,'a'),(2,1,1,1.5,0.5,'a'),(3,1,2,null,1,null),(4,1,null,2.25,null,'b')," +
+		"(5,1,2,2.25,1,'a'),(6,2,3,3,1.5,'c'),(7,2,3,3,1.5,'c'),(8,2,4,4,2,'d'),(9,2,3,3,1.5,'c')")
+	funcs := []string{
+		"count(distinct v)", "count(distinct v, s)", "sum(distinct v)", "avg(distinct v)",
+		"sum(distinct d)", "avg(distinct d)", "sum(distinct f)", "avg(distinct f)",
+	}
+	// frames are [id+lo, id+hi] as the ids in a partition are consecutive.
+	frames := []struct {
+		spec   string
+		lo, hi int
+	}{
+		{"rows between 1 preceding and 1 following", -1, 1},
+		{"rows between 2 preceding and current row", -2, 0},
+		{"rows between current row and 2 following", 0, 2},
+		{"rows between unbounded preceding and current row", -100, 0},
+		{"rows between 1 following and 3 following", 1, 3},
+		{"range between 1 preceding and 2 following", -1, 2},
+	}
+	for _, pipelined := range []string{"0", "1"} {
+		tk.MustExec("set @@tidb_enable_pipelined_window_function = " + pipelined)
+		for _, chunkSize := range []int{1, 32} {
+			tk.Session().GetSessionVars().MaxChunkSize = chunkSize
+			for _, f := range funcs {
+				for _, frame := range frames {
+					expected := tk.MustQuery(fmt.Sprintf("select id, (select %s from t t2 where t2.p = t1.p and t2.id between t1.id + %d and t1.id + %d) from t t1 order by id",
+						f, frame.lo, frame.hi)).Rows()
+					tk.MustQuery(fmt.Sprintf("select id, %s over (partition by p order by id %s) from t order by id", f, frame.spec)).
+						Check(expected)
+				}
+				// Without frame, the window is the whole partition.
+				expected := tk.MustQuery(fmt.Sprintf("select id, (select %s from t t2 where t2.p = t1.p) from t t1 order by id", f)).Rows()
+				tk.MustQuery(fmt.Sprintf("select id, %s over (partition by p) from t order by id", f)).Check(expected)
+			}
+
+			tk.MustQuery("select id, group_concat(s) over (partition by p order by id rows between 1 preceding and 1 following) from t order by id").
+				Check(testkit.Rows("1 a,a", "2 a,a", "3 a,b", "4 b,a", "5 b,a", "6 c,c", "7 c,c,d", "8 c,d,c", "9 d,c"))
+			tk.MustQuery("select id, group_concat(distinct s separator '') over (partition by p order by id rows between current row and 2 following) from t order by id").
+				Check(testkit.Rows("1 a", "2 ab", "3 ba", "4 ba", "5 a", "6 cd", "7 cd", "8 dc", "9 c"))
+			tk.MustQuery("select id, group_concat(distinct v, s) over (partition by p order by id rows between 2 preceding and current row) from t order by id").
+				Check(testkit.Rows("1 1a", "2 1a", "3 1a", "4 1a", "5 2a", "6 3c", "7 3c", "8 3c,4d", "9 3c,4d"))
+			tk.MustQuery("select id, group_concat(s separator '-') over (partition by p order by id desc) from t order by id").
+				Check(testkit.Rows("1 a-b-a-a", "2 a-b-a", "3 a-b", "4 a-b", "5 a", "6 c-d-c-c", "7 c-d-c", "8 c-d", "9 c"))
+			tk.MustQuery("select id, group_concat(s) over (partition by p) from t order by id").
+				Check(testkit.Rows("1 a,a,b,a", "2 a,a,b,a", "3 a,a,b,a", "4 a,a,b,a", "5 a,a,b,a", "6 c,c,d,c", "7 c,c,d,c", "8 c,c,d,c", "9 c,c,d,c"))
+		}
+	}
+	tk.MustQuery("explain format = 'brief' select count(distinct v) over (order by id) from t").CheckContain("count(distinct test.t.v)")
+	tk.MustExec("set @@group_concat_max_len = 4")
+	tk.MustQuery("select id, group_concat(s) over (order by id rows between current row and 3 following) from t order by id limit 2").
+		Check(testkit.Rows("1 a,a,", "2 a,b,"))
+	tk.MustQuery("show warnings").CheckContain("Some rows were cut by GROUPCONCAT")
+	tk.MustGetErrMsg("select group_concat(s order by id) over () from t",
+		"[parser:1221]Incorrect usage of ORDER BY in GROUP_CONCAT and OVER")
+}
+
 func TestSlidingWindowFunctions(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
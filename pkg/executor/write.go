@@ -50,13 +50,14 @@ var (
 // updateRecord updates the row specified by the handle `h`, from `oldData` to `newData`.
 // `modified` means which columns are really modified. It's used for secondary indices.
 // Length of `oldData` and `newData` equals to length of `t.WritableCols()`.
+// `triggers` activates the UPDATE triggers of the table, it's nil if the table has no trigger.
 // The return values:
 //  1. changed (bool) : does the update really change the row values. e.g. update set i = 1 where i = 1;
 //  2. err (error) : error in the update.
 func updateRecord(
 	ctx context.Context, sctx sessionctx.Context, h kv.Handle, oldData, newData []types.Datum, modified []bool,
 	t table.Table,
-	onDup bool, _ *memory.Tracker, fkChecks []*FKCheckExec, fkCascades []*FKCascadeExec,
+	onDup bool, _ *memory.Tracker, fkChecks []*FKCheckExec, fkCascades []*FKCascadeExec, triggers *TriggerExec,
 ) (bool, error) {
 	r, ctx := tracing.StartRegionEx(ctx, "executor.updateRecord")
 	defer r.End()
@@ -71,6 +72,16 @@ func updateRecord(
 	// because all of them are sorted by their `Offset`, which
 	// causes all writable columns are after public columns.
 
+	// The BEFORE UPDATE triggers may change the new row, so the generated columns are evaluated again.
+	if triggers != nil {
+		if err := triggers.activateBefore(ctx, model.TriggerUpdate, oldData, newData); err != nil {
+			return false, err
+		}
+		if err := triggers.evalGeneratedColumns(model.TriggerUpdate, newData); err != nil {
+			return false, err
+		}
+	}
+
 	// Handle the bad null error.
 	for i, col := range t.Cols() {
 		var err error
@@ -137,8 +148,13 @@ func updateRecord(
 		if sctx.GetSessionVars().LockUnchangedKeys {
 			keySet |= lockUniqueKeys
 		}
-		_, err := addUnchangedKeysForLockByRow(sctx, t, h, oldData, keySet)
-		return false, err
+		if _, err := addUnchangedKeysForLockByRow(sctx, t, h, oldData, keySet); err != nil {
+			return false, err
+		}
+		if triggers != nil {
+			triggers.recordAfter(model.TriggerUpdate, oldData, newData)
+		}
+		return false, nil
 	}
 
 	// Fill values into on-update-now fields, only if they are really changed.
@@ -227,6 +243,9 @@ func updateRecord(
 	}
 	sc.AddUpdatedRows(1)
 	sc.AddCopiedRows(1)
+	if triggers != nil {
+		triggers.recordAfter(model.TriggerUpdate, oldData, newData)
+	}
 
 	return true, nil
 }
@@ -0,0 +1,362 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/domain/infosync"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/sessiontxn"
+	"github.com/pingcap/tidb/pkg/sessiontxn/xa"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// maxXIDPartLen is the max length of the gtrid and the bqual of an XID.
+const maxXIDPartLen = 64
+
+// XAExec executes the XA statements.
+//
+// A branch is an ordinary pessimistic transaction until XA PREPARE. When XA PREPARE finishes, the session persists
+// the mutations of the branch instead of committing it, and detaches the transaction from the session, see
+// session.prepareXATxn. A prepared branch is committed or rolled back with the held transaction if it's prepared in
+// this instance. The persisted mutations are only replayed when the instance preparing the branch is gone, because the
+// held transaction may still be committed there. The locks of the branch are kept after it's prepared, see
+// xa.KeepLocks, so the replayed branch can always be committed.
+type XAExec struct {
+	exec.BaseExecutor
+
+	stmt *ast.XAStmt
+	done bool
+}
+
+var _ exec.Executor = (*XAExec)(nil)
+
+// Next implements the Executor Next interface.
+func (e *XAExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	switch e.stmt.Tp {
+	case ast.XAStart:
+		return e.start(ctx)
+	case ast.XAEnd:
+		return e.end()
+	case ast.XAPrepare:
+		return e.prepare()
+	case ast.XACommit:
+		return e.commit(ctx)
+	case ast.XARollback:
+		return e.rollback(ctx)
+	case ast.XARecover:
+		return e.recover(ctx, req)
+	}
+	return nil
+}
+
+func toXID(x *ast.XID) (variable.XID, error) {
+	xid := variable.XID{GTRID: x.GTRID.Value}
+	if x.BQUAL != nil {
+		xid.BQUAL = x.BQUAL.Value
+	}
+	if len(xid.GTRID) == 0 || len(xid.GTRID) > maxXIDPartLen || len(xid.BQUAL) > maxXIDPartLen || x.FormatID > math.MaxInt64 {
+		return xid, exeerrors.ErrXaerInval
+	}
+	xid.FormatID = int64(x.FormatID)
+	return xid, nil
+}
+
+// sessionBranch returns the branch associated with the session and checks whether it matches the XID.
+func (e *XAExec) sessionBranch() (*variable.XATxnContext, error) {
+	xaTxn := e.Ctx().GetSessionVars().XATxn
+	if xaTxn == nil {
+		return nil, exeerrors.ErrXaerRmfail.GenWithStackByArgs("NON-EXISTING")
+	}
+	xid, err := toXID(e.stmt.XID)
+	if err != nil {
+		return nil, err
+	}
+	if xid != xaTxn.XID {
+		return nil, exeerrors.ErrXaerNota
+	}
+	return xaTxn, nil
+}
+
+func (e *XAExec) start(ctx context.Context) error {
+	sessVars := e.Ctx().GetSessionVars()
+	if e.stmt.Option != ast.XAOptionNone {
+		return exeerrors.ErrXaerInval
+	}
+	if sessVars.XATxn != nil {
+		return exeerrors.ErrXaerRmfail.GenWithStackByArgs(sessVars.XATxn.State.String())
+	}
+	if sessVars.InTxn() {
+		return exeerrors.ErrXaerOutside
+	}
+	xid, err := toXID(e.stmt.XID)
+	if err != nil {
+		return err
+	}
+	if xa.IsHeld(xid) {
+		return exeerrors.ErrXaerDupid
+	}
+	exists, err := xa.BranchExists(ctx, e.Ctx().GetRestrictedSQLExecutor(), xid)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return exeerrors.ErrXaerDupid
+	}
+	// The branch must be pessimistic, otherwise the prepared branch may fail to commit because of write conflicts.
+	err = sessiontxn.GetTxnManager(e.Ctx()).EnterNewTxn(ctx, &sessiontxn.EnterNewTxnRequest{
+		Type:    sessiontxn.EnterNewTxnWithBeginStmt,
+		TxnMode: ast.Pessimistic,
+	})
+	if err != nil {
+		return err
+	}
+	// Lock the primary key of the branch first, so that the locks of the branch can be kept after it's prepared.
+	lockCtx, err := newLockCtx(e.Ctx(), sessVars.LockWaitTimeout, 1)
+	if err != nil {
+		return err
+	}
+	if err = doLockKeys(ctx, e.Ctx(), lockCtx, xa.PrimaryKey(xid)); err != nil {
+		return err
+	}
+	sessVars.XATxn = &variable.XATxnContext{XID: xid, State: variable.XAStateActive}
+	return nil
+}
+
+func (e *XAExec) end() error {
+	xaTxn, err := e.sessionBranch()
+	if err != nil {
+		return err
+	}
+	if e.stmt.Option != ast.XAOptionNone {
+		return exeerrors.ErrXaerInval
+	}
+	if xaTxn.State != variable.XAStateActive {
+		return exeerrors.ErrXaerRmfail.GenWithStackByArgs(xaTxn.State.String())
+	}
+	xaTxn.State = variable.XAStateIdle
+	return nil
+}
+
+func (e *XAExec) prepare() error {
+	xaTxn, err := e.sessionBranch()
+	if err != nil {
+		return err
+	}
+	if xaTxn.State != variable.XAStateIdle {
+		return exeerrors.ErrXaerRmfail.GenWithStackByArgs(xaTxn.State.String())
+	}
+	// Activate the transaction, so that the branch is persisted even if it's empty.
+	if _, err := e.Ctx().Txn(true); err != nil {
+		return err
+	}
+	// The transaction is finished by the session after the statement, see session.prepareXATxn.
+	xaTxn.State = variable.XAStatePrepared
+	e.Ctx().GetSessionVars().SetInTxn(false)
+	return nil
+}
+
+func (e *XAExec) commit(ctx context.Context) error {
+	sessVars := e.Ctx().GetSessionVars()
+	if sessVars.XATxn != nil {
+		xaTxn, err := e.sessionBranch()
+		if err != nil {
+			return err
+		}
+		if xaTxn.State != variable.XAStateIdle || e.stmt.Option != ast.XAOptionOnePhase {
+			return exeerrors.ErrXaerRmfail.GenWithStackByArgs(xaTxn.State.String())
+		}
+		// The transaction is committed by the session after the statement like COMMIT.
+		sessVars.XATxn = nil
+		sessVars.SetInTxn(false)
+		return nil
+	}
+	if sessVars.InTxn() {
+		return exeerrors.ErrXaerOutside
+	}
+	if e.stmt.Option == ast.XAOptionOnePhase {
+		return exeerrors.ErrXaerRmfail.GenWithStackByArgs(variable.XAStatePrepared.String())
+	}
+	return e.finishPreparedBranch(ctx, true)
+}
+
+func (e *XAExec) rollback(ctx context.Context) error {
+	sessVars := e.Ctx().GetSessionVars()
+	if sessVars.XATxn != nil {
+		xaTxn, err := e.sessionBranch()
+		if err != nil {
+			return err
+		}
+		if xaTxn.State != variable.XAStateIdle {
+			return exeerrors.ErrXaerRmfail.GenWithStackByArgs(xaTxn.State.String())
+		}
+		sessVars.XATxn = nil
+		sessVars.SetInTxn(false)
+		txn, err := e.Ctx().Txn(false)
+		if err != nil {
+			return err
+		}
+		if txn.Valid() {
+			sessVars.TxnCtx.ClearDelta()
+			return txn.Rollback()
+		}
+		return nil
+	}
+	if sessVars.InTxn() {
+		return exeerrors.ErrXaerOutside
+	}
+	return e.finishPreparedBranch(ctx, false)
+}
+
+// finishPreparedBranch commits or rolls back a prepared branch.
+func (e *XAExec) finishPreparedBranch(ctx context.Context, commit bool) error {
+	xid, err := toXID(e.stmt.XID)
+	if err != nil {
+		return err
+	}
+	txn, ok := xa.Acquire(xid)
+	if !ok {
+		// The branch is being committed or rolled back by another session.
+		return exeerrors.ErrXaerRmfail.GenWithStackByArgs(variable.XAStatePrepared.String())
+	}
+	defer xa.Release(xid)
+
+	sqlExec := e.Ctx().GetRestrictedSQLExecutor()
+	if txn == nil {
+		branch, err := xa.LoadBranch(ctx, sqlExec, xid)
+		if err != nil {
+			return err
+		}
+		if branch == nil {
+			return exeerrors.ErrXaerNota
+		}
+		serverInfo, err := infosync.GetServerInfo()
+		if err != nil {
+			return err
+		}
+		// The branch prepared in this instance is lost if it isn't held, so it's safe to replay it.
+		if branch.Owner != serverInfo.ID {
+			alive, err := xa.OwnerIsAlive(ctx, branch)
+			if err != nil {
+				return err
+			}
+			if alive {
+				return exeerrors.ErrXaerRmfail.GenWithStackByArgs(variable.XAStatePrepared.String())
+			}
+		}
+		if err := e.replayBranch(ctx, branch, commit); err != nil {
+			return err
+		}
+	} else if commit {
+		if err := txn.Commit(ctx); err != nil {
+			return err
+		}
+	} else {
+		terror.Log(txn.Rollback())
+	}
+	return xa.DeleteBranch(ctx, sqlExec, xid)
+}
+
+// replayBranch commits the persisted mutations of a branch whose transaction has been lost, or releases the locks of
+// it if the branch is rolled back.
+func (e *XAExec) replayBranch(ctx context.Context, branch *xa.Branch, commit bool) error {
+	schemaChecker := domain.NewSchemaChecker(domain.GetDomain(e.Ctx()), branch.SchemaVersion, branch.TableIDs, true)
+	if commit {
+		// Check the schema before the branch is recovered, so that the locks of the branch are kept if the schema
+		// has changed, and the branch can still be rolled back.
+		ts, err := e.Ctx().GetStore().GetOracle().GetTimestamp(ctx, &oracle.Option{TxnScope: kv.GlobalTxnScope})
+		if err != nil {
+			return err
+		}
+		if _, err = schemaChecker.Check(ts); err != nil {
+			return err
+		}
+	}
+	txn, err := xa.RecoverBranch(ctx, e.Ctx().GetStore(), branch)
+	if err != nil {
+		return err
+	}
+	if !commit {
+		return txn.Rollback()
+	}
+	txn.SetOption(kv.SchemaChecker, schemaChecker)
+	txn.SetOption(kv.InfoSchema, branch)
+	if err = txn.Commit(ctx); err != nil && !terror.ErrResultUndetermined.Equal(err) {
+		// The locks of the branch are cleaned up when it fails to commit, so the branch is rolled back.
+		terror.Log(xa.DeleteBranch(ctx, e.Ctx().GetRestrictedSQLExecutor(), branch.XID))
+	}
+	return err
+}
+
+func (e *XAExec) recover(ctx context.Context, req *chunk.Chunk) error {
+	xids, err := xa.ListBranches(ctx, e.Ctx().GetRestrictedSQLExecutor())
+	if err != nil {
+		return err
+	}
+	for _, xid := range xids {
+		req.AppendInt64(0, xid.FormatID)
+		req.AppendInt64(1, int64(len(xid.GTRID)))
+		req.AppendInt64(2, int64(len(xid.BQUAL)))
+		data := xid.GTRID + xid.BQUAL
+		if e.stmt.Option == ast.XAOptionConvertXID {
+			data = fmt.Sprintf("0x%X", data)
+		}
+		req.AppendString(3, data)
+	}
+	return nil
+}
+
+// checkXAState checks whether the statement can be executed in the current state of the XA branch. Statements
+// which end the transaction implicitly are not allowed in an active branch, and only XA statements are allowed in
+// an idle branch.
+func checkXAState(sctx sessionctx.Context, node ast.StmtNode) error {
+	sessVars := sctx.GetSessionVars()
+	xaTxn := sessVars.XATxn
+	if xaTxn == nil || sessVars.InRestrictedSQL {
+		return nil
+	}
+	switch stmt := node.(type) {
+	case *ast.XAStmt:
+		return nil
+	case *ast.BeginStmt, *ast.CommitStmt, ast.DDLNode, *ast.GrantStmt, *ast.RevokeStmt, *ast.CreateUserStmt,
+		*ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt, *ast.SetPwdStmt, *ast.ProcedureInfo, *ast.DropProcedureStmt:
+	case *ast.RollbackStmt:
+		if stmt.SavepointName != "" && xaTxn.State == variable.XAStateActive {
+			return nil
+		}
+	default:
+		if xaTxn.State == variable.XAStateActive {
+			return nil
+		}
+	}
+	return exeerrors.ErrXaerRmfail.GenWithStackByArgs(xaTxn.State.String())
+}
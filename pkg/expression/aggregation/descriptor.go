@@ -43,6 +43,9 @@ type AggFuncDesc struct {
 	OrderByItems []*util.ByItems
 	// GroupingID is used for distinguishing with not-set 0, starting from 1.
 	GroupingID int
+	// IgnoreNull and FromLast are only set for window functions, see WindowFuncDesc.
+	IgnoreNull bool
+	FromLast   bool
 }
 
 // NewAggFuncDesc creates an aggregation function signature descriptor.
@@ -58,9 +61,19 @@ func NewAggFuncDesc(ctx expression.BuildContext, name string, args []expression.
 // NewAggFuncDescForWindowFunc creates an aggregation function from window functions, where baseFuncDesc may be ready.
 func NewAggFuncDescForWindowFunc(ctx expression.BuildContext, desc *WindowFuncDesc, hasDistinct bool) (*AggFuncDesc, error) {
 	if desc.RetTp == nil { // safety check
-		return NewAggFuncDesc(ctx, desc.Name, desc.Args, hasDistinct)
+		aggDesc, err := NewAggFuncDesc(ctx, desc.Name, desc.Args, hasDistinct)
+		if err != nil {
+			return nil, err
+		}
+		aggDesc.IgnoreNull, aggDesc.FromLast = desc.IgnoreNull, desc.FromLast
+		return aggDesc, nil
 	}
-	return &AggFuncDesc{baseFuncDesc: baseFuncDesc{desc.Name, desc.Args, desc.RetTp}, HasDistinct: hasDistinct}, nil
+	return &AggFuncDesc{
+		baseFuncDesc: baseFuncDesc{desc.Name, desc.Args, desc.RetTp},
+		HasDistinct:  hasDistinct,
+		IgnoreNull:   desc.IgnoreNull,
+		FromLast:     desc.FromLast,
+	}, nil
 }
 
 // String implements the fmt.Stringer interface.
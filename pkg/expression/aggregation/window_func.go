@@ -27,6 +27,14 @@ import (
 // WindowFuncDesc describes a window function signature, only used in planner.
 type WindowFuncDesc struct {
 	baseFuncDesc
+	// IgnoreNull indicates that null values of the argument should be skipped,
+	// it is only valid for `first_value`, `last_value`, `nth_value`, `lead` and `lag`.
+	IgnoreNull bool
+	// FromLast indicates that `nth_value` counts rows from the end of the frame.
+	FromLast bool
+	// HasDistinct indicates whether the aggregate window function is evaluated over distinct argument values,
+	// e.g. `count(distinct a) over w`.
+	HasDistinct bool
 }
 
 // NewWindowFuncDesc creates a window function signature descriptor.
@@ -79,7 +87,7 @@ func NewWindowFuncDesc(ctx expression.BuildContext, name string, args []expressi
 	if err != nil {
 		return nil, err
 	}
-	return &WindowFuncDesc{base}, nil
+	return &WindowFuncDesc{baseFuncDesc: base}, nil
 }
 
 // noFrameWindowFuncs is the functions that operate on the entire partition,
@@ -117,9 +125,24 @@ func NeedFrame(name string) bool {
 	return !ok
 }
 
+// String implements the fmt.Stringer interface.
+func (s *WindowFuncDesc) String() string {
+	str := s.baseFuncDesc.String()
+	if s.HasDistinct {
+		str = strings.Replace(str, "(", "(distinct ", 1)
+	}
+	if s.FromLast {
+		str += " from last"
+	}
+	if s.IgnoreNull {
+		str += " ignore nulls"
+	}
+	return str
+}
+
 // Clone makes a copy of SortItem.
 func (s *WindowFuncDesc) Clone() *WindowFuncDesc {
-	return &WindowFuncDesc{*s.baseFuncDesc.clone()}
+	return &WindowFuncDesc{baseFuncDesc: *s.baseFuncDesc.clone(), IgnoreNull: s.IgnoreNull, FromLast: s.FromLast, HasDistinct: s.HasDistinct}
 }
 
 // WindowFuncToPBExpr converts aggregate function to pb.
@@ -143,6 +166,10 @@ func WindowFuncToPBExpr(sctx expression.EvalContext, client kv.Client, desc *Win
 
 // CanPushDownToTiFlash control whether a window function desc can be push down to tiflash.
 func (s *WindowFuncDesc) CanPushDownToTiFlash(ctx expression.PushDownContext) bool {
+	// TiFlash only supports the default null treatment and counting direction, and does not support distinct.
+	if s.IgnoreNull || s.FromLast || s.HasDistinct {
+		return false
+	}
 	// args
 	if !expression.CanExprsPushDown(ctx, s.Args, kv.TiFlash) {
 		return false
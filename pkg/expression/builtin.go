@@ -909,6 +909,37 @@ var funcs = map[string]functionClass{
 	ast.JSONKeys:          &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
 
+	// spatial functions
+	ast.Point:              &pointFunctionClass{baseFunctionClass{ast.Point, 2, 2}},
+	ast.STGeomFromText:     &geomFromTextFunctionClass{baseFunctionClass{ast.STGeomFromText, 1, 2}},
+	ast.STGeometryFromText: &geomFromTextFunctionClass{baseFunctionClass{ast.STGeometryFromText, 1, 2}},
+	ast.STGeomFromWKB:      &geomFromWKBFunctionClass{baseFunctionClass{ast.STGeomFromWKB, 1, 2}},
+	ast.STGeometryFromWKB:  &geomFromWKBFunctionClass{baseFunctionClass{ast.STGeometryFromWKB, 1, 2}},
+	ast.STAsText:           &asTextFunctionClass{baseFunctionClass{ast.STAsText, 1, 1}},
+	ast.STAsWKT:            &asTextFunctionClass{baseFunctionClass{ast.STAsWKT, 1, 1}},
+	ast.STAsBinary:         &asBinaryFunctionClass{baseFunctionClass{ast.STAsBinary, 1, 1}},
+	ast.STAsWKB:            &asBinaryFunctionClass{baseFunctionClass{ast.STAsWKB, 1, 1}},
+	ast.STAsGeoJSON:        &asGeoJSONFunctionClass{baseFunctionClass{ast.STAsGeoJSON, 1, 2}},
+	ast.STContains:         &stContainsFunctionClass{baseFunctionClass{ast.STContains, 2, 2}},
+	ast.STWithin:           &stWithinFunctionClass{baseFunctionClass{ast.STWithin, 2, 2}},
+	ast.STDistanceSphere:   &distanceSphereFunctionClass{baseFunctionClass{ast.STDistanceSphere, 2, 3}},
+	ast.STX:                &stXFunctionClass{baseFunctionClass{ast.STX, 1, 1}},
+	ast.STY:                &stYFunctionClass{baseFunctionClass{ast.STY, 1, 1}},
+	ast.STSRID:             &stSRIDFunctionClass{baseFunctionClass{ast.STSRID, 1, 1}},
+	ast.STGeometryType:     &geometryTypeFunctionClass{baseFunctionClass{ast.STGeometryType, 1, 1}},
+
+	// vector functions
+	ast.VecAsText:         &vecAsTextFunctionClass{baseFunctionClass{ast.VecAsText, 1, 1}},
+	ast.VecCosineDistance: &vecDistanceFunctionClass{baseFunctionClass{ast.VecCosineDistance, 2, 2}},
+	ast.VecDims:           &vecDimsFunctionClass{baseFunctionClass{ast.VecDims, 1, 1}},
+	ast.VecFromText:       &vecFromTextFunctionClass{baseFunctionClass{ast.VecFromText, 1, 1}},
+	ast.VecInnerProduct:   &vecDistanceFunctionClass{baseFunctionClass{ast.VecInnerProduct, 2, 2}},
+	ast.VecL2Distance:     &vecDistanceFunctionClass{baseFunctionClass{ast.VecL2Distance, 2, 2}},
+	ast.VecL2Norm:         &vecL2NormFunctionClass{baseFunctionClass{ast.VecL2Norm, 1, 1}},
+
+	// fulltext search functions
+	ast.FTSMatchAgainst: &matchAgainstFunctionClass{baseFunctionClass{ast.FTSMatchAgainst, 5, 5}},
+
 	// TiDB internal function.
 	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
 	// This function is used to show tidb-server version info.
@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/hack"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
@@ -280,11 +281,17 @@ func (c *castAsStringFunctionClass) getFunction(ctx BuildContext, args []Express
 	if err != nil {
 		return nil, err
 	}
+	if c.tp.GetType() == mysql.TypeTiDBVectorFloat32 {
+		return &builtinCastAsVectorSig{bf}, nil
+	}
 	if args[0].GetType().Hybrid() {
 		sig = &builtinCastStringAsStringSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_CastStringAsString)
 		return sig, nil
 	}
+	if args[0].GetType().GetType() == mysql.TypeTiDBVectorFloat32 {
+		return &builtinCastVectorAsStringSig{bf}, nil
+	}
 	argTp := args[0].GetType().EvalType()
 	switch argTp {
 	case types.ETInt:
@@ -1908,6 +1915,52 @@ func (b *builtinCastJSONAsStringSig) evalString(ctx EvalContext, row chunk.Row)
 	return s, false, nil
 }
 
+type builtinCastVectorAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastVectorAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastVectorAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastVectorAsStringSig) evalString(ctx EvalContext, row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	s, err := types.ProduceStrWithSpecifiedTp(types.FormatVectorFloat32(hack.Slice(val)), b.tp, typeCtx(ctx), false)
+	if err != nil {
+		return res, false, err
+	}
+	return s, false, nil
+}
+
+type builtinCastAsVectorSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastAsVectorSig) Clone() builtinFunc {
+	newSig := &builtinCastAsVectorSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString converts a vector or the text form of a vector to a vector of the dimension of the target type.
+func (b *builtinCastAsVectorSig) evalString(ctx EvalContext, row chunk.Row) (res string, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	d := types.NewBytesDatum(hack.Slice(val))
+	d, err = d.ConvertTo(typeCtx(ctx), b.tp)
+	if err != nil {
+		return res, false, err
+	}
+	return string(d.GetBytes()), false, nil
+}
+
 type builtinCastJSONAsTimeSig struct {
 	baseBuiltinFunc
 }
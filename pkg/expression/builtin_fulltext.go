@@ -0,0 +1,157 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
+)
+
+var (
+	_ functionClass = &matchAgainstFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinMatchAgainstSig{}
+)
+
+// BuildMatchAgainstFunction builds the function of `MATCH(col) AGAINST(against modifier)`, which is evaluated with
+// the parser of the FULLTEXT index on col.
+func BuildMatchAgainstFunction(ctx BuildContext, col *Column, against Expression, modifier ast.FulltextSearchModifier,
+	info *model.FullTextIndexInfo) (Expression, error) {
+	args := []Expression{
+		col,
+		against,
+		&Constant{Value: types.NewIntDatum(int64(modifier)), RetType: types.NewFieldType(mysql.TypeLonglong)},
+		&Constant{Value: types.NewStringDatum(string(info.ParserType)), RetType: types.NewFieldType(mysql.TypeVarString)},
+		&Constant{Value: types.NewIntDatum(int64(info.NgramSize)), RetType: types.NewFieldType(mysql.TypeLonglong)},
+	}
+	return NewFunction(ctx, ast.FTSMatchAgainst, types.NewFieldType(mysql.TypeDouble), args...)
+}
+
+// ExtractMatchAgainst extracts the column and the parsed search query from the function built by
+// BuildMatchAgainstFunction, and checks whether the function is evaluated with the parser of the FULLTEXT index.
+// ok is false if the search query isn't a constant.
+func ExtractMatchAgainst(ctx EvalContext, sf *ScalarFunction, info *model.FullTextIndexInfo) (col *Column, query *fulltext.Query, ok bool) {
+	args := sf.GetArgs()
+	col, ok = args[0].(*Column)
+	if !ok {
+		return nil, nil, false
+	}
+	for _, arg := range args[1:] {
+		if _, ok := arg.(*Constant); !ok {
+			return nil, nil, false
+		}
+	}
+	query, tokenizer, isNull, err := evalMatchAgainstQuery(ctx, args, chunk.Row{})
+	if isNull || err != nil || tokenizer != fulltext.NewTokenizer(info) {
+		return nil, nil, false
+	}
+	return col, query, true
+}
+
+// evalMatchAgainstQuery parses the search query with the tokenizer of the FULLTEXT index.
+func evalMatchAgainstQuery(ctx EvalContext, args []Expression, row chunk.Row) (*fulltext.Query, fulltext.Tokenizer, bool, error) {
+	var tokenizer fulltext.Tokenizer
+	against, isNull, err := args[1].EvalString(ctx, row)
+	if isNull || err != nil {
+		return nil, tokenizer, isNull, err
+	}
+	modifier, _, err := args[2].EvalInt(ctx, row)
+	if err != nil {
+		return nil, tokenizer, false, err
+	}
+	parser, _, err := args[3].EvalString(ctx, row)
+	if err != nil {
+		return nil, tokenizer, false, err
+	}
+	ngramSize, _, err := args[4].EvalInt(ctx, row)
+	if err != nil {
+		return nil, tokenizer, false, err
+	}
+	tokenizer = fulltext.NewTokenizer(&model.FullTextIndexInfo{
+		ParserType: model.FullTextParserType(parser),
+		NgramSize:  int(ngramSize),
+	})
+	booleanMode := ast.FulltextSearchModifier(modifier).IsBooleanMode()
+	return fulltext.ParseQuery(against, booleanMode, tokenizer), tokenizer, false, nil
+}
+
+type matchAgainstFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *matchAgainstFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal,
+		types.ETString, types.ETString, types.ETInt, types.ETString, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinMatchAgainstSig{baseBuiltinFunc: bf}, nil
+}
+
+// matchAgainstMemorizedQuery is the parsed search query memorized for a constant AGAINST argument.
+type matchAgainstMemorizedQuery struct {
+	query     *fulltext.Query
+	tokenizer fulltext.Tokenizer
+	isNull    bool
+}
+
+type builtinMatchAgainstSig struct {
+	baseBuiltinFunc
+	memorizedQuery builtinFuncCache[matchAgainstMemorizedQuery]
+}
+
+func (b *builtinMatchAgainstSig) Clone() builtinFunc {
+	newSig := &builtinMatchAgainstSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// getQuery returns the parsed search query. If the search query and the parser arguments are constant, the query is
+// parsed once and memorized for the statement, instead of being parsed for every row.
+func (b *builtinMatchAgainstSig) getQuery(ctx EvalContext, row chunk.Row) (*fulltext.Query, fulltext.Tokenizer, bool, error) {
+	for _, arg := range b.args[1:] {
+		if arg.ConstLevel() < ConstOnlyInContext {
+			return evalMatchAgainstQuery(ctx, b.args, row)
+		}
+	}
+	memorized, err := b.memorizedQuery.getOrInitCache(ctx, func() (ret matchAgainstMemorizedQuery, err error) {
+		ret.query, ret.tokenizer, ret.isNull, err = evalMatchAgainstQuery(ctx, b.args, row)
+		return
+	})
+	return memorized.query, memorized.tokenizer, memorized.isNull, err
+}
+
+// evalReal evals the relevance of `MATCH(col) AGAINST(...)`, which is 0 if the row doesn't match.
+// See https://dev.mysql.com/doc/refman/8.0/en/fulltext-search.html#function_match
+func (b *builtinMatchAgainstSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	query, tokenizer, isNull, err := b.getQuery(ctx, row)
+	if isNull || err != nil {
+		return 0, false, err
+	}
+	text, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return 0, false, err
+	}
+	return query.Score(tokenizer.Tokenize(text)), false, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchAgainstQueryCache(t *testing.T) {
+	ctx := createContext(t)
+	info := &model.FullTextIndexInfo{ParserType: model.FullTextParserTypeStandard}
+	ftps := []*types.FieldType{types.NewFieldType(mysql.TypeVarString), types.NewFieldType(mysql.TypeVarString)}
+	body := &Column{Index: 0, RetType: ftps[0]}
+	rows := chunk.MutRowFromTypes(ftps)
+
+	// The constant search query is parsed once, and memorized for the following rows.
+	var against Expression = &Constant{Value: types.NewStringDatum("database"), RetType: types.NewFieldType(mysql.TypeVarString)}
+	f, err := BuildMatchAgainstFunction(ctx, body, against, ast.FulltextSearchModifierNaturalLanguageMode, info)
+	require.NoError(t, err)
+	sig := f.(*ScalarFunction).Function.(*builtinMatchAgainstSig)
+	_, ok := sig.memorizedQuery.getCache(ctx.GetSessionVars().StmtCtx.CtxID())
+	require.False(t, ok)
+	for _, c := range []struct {
+		text    string
+		matched bool
+	}{
+		{"a database", true},
+		{"a document", false},
+		{"Database and database", true},
+	} {
+		rows.SetDatums(types.NewStringDatum(c.text), types.NewStringDatum(""))
+		score, isNull, err := f.EvalReal(ctx, rows.ToRow())
+		require.NoError(t, err)
+		require.False(t, isNull)
+		require.Equal(t, c.matched, score > 0, c.text)
+		memorized, ok := sig.memorizedQuery.getCache(ctx.GetSessionVars().StmtCtx.CtxID())
+		require.True(t, ok)
+		require.NotNil(t, memorized.query)
+	}
+
+	// The search query from a column is parsed for every row.
+	against = &Column{Index: 1, RetType: ftps[1]}
+	f, err = BuildMatchAgainstFunction(ctx, body, against, ast.FulltextSearchModifierNaturalLanguageMode, info)
+	require.NoError(t, err)
+	sig = f.(*ScalarFunction).Function.(*builtinMatchAgainstSig)
+	for _, c := range []struct {
+		query   string
+		matched bool
+	}{
+		{"database", true},
+		{"document", false},
+	} {
+		rows.SetDatums(types.NewStringDatum("a database"), types.NewStringDatum(c.query))
+		score, _, err := f.EvalReal(ctx, rows.ToRow())
+		require.NoError(t, err)
+		require.Equal(t, c.matched, score > 0, c.query)
+	}
+	_, ok = sig.memorizedQuery.getCache(ctx.GetSessionVars().StmtCtx.CtxID())
+	require.False(t, ok)
+}
@@ -0,0 +1,614 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/hack"
+)
+
+var (
+	_ functionClass = &pointFunctionClass{}
+	_ functionClass = &geomFromTextFunctionClass{}
+	_ functionClass = &geomFromWKBFunctionClass{}
+	_ functionClass = &asTextFunctionClass{}
+	_ functionClass = &asBinaryFunctionClass{}
+	_ functionClass = &asGeoJSONFunctionClass{}
+	_ functionClass = &stContainsFunctionClass{}
+	_ functionClass = &stWithinFunctionClass{}
+	_ functionClass = &distanceSphereFunctionClass{}
+	_ functionClass = &stXFunctionClass{}
+	_ functionClass = &stYFunctionClass{}
+	_ functionClass = &stSRIDFunctionClass{}
+	_ functionClass = &geometryTypeFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinPointSig{}
+	_ builtinFunc = &builtinGeomFromTextSig{}
+	_ builtinFunc = &builtinGeomFromWKBSig{}
+	_ builtinFunc = &builtinAsTextSig{}
+	_ builtinFunc = &builtinAsBinarySig{}
+	_ builtinFunc = &builtinAsGeoJSONSig{}
+	_ builtinFunc = &builtinSTContainsSig{}
+	_ builtinFunc = &builtinDistanceSphereSig{}
+	_ builtinFunc = &builtinSTCoordinateSig{}
+	_ builtinFunc = &builtinSTSRIDSig{}
+	_ builtinFunc = &builtinGeometryTypeSig{}
+)
+
+// setGeometryRetType sets the return type of the functions which return spatial values.
+func setGeometryRetType(bf *baseBuiltinFunc) {
+	bf.tp.SetType(mysql.TypeGeometry)
+	bf.tp.SetGeometryType(types.GeometryTypeGeometry)
+	bf.tp.SetFlen(mysql.MaxLongBlobWidth)
+	bf.tp.AddFlag(mysql.BinaryFlag)
+}
+
+// evalGeometry evaluates the argument as a spatial value.
+func evalGeometry(ctx EvalContext, arg Expression, row chunk.Row, funcName string) (types.Geometry, bool, error) {
+	val, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return types.Geometry{}, isNull, err
+	}
+	g, err := types.DecodeGeometry(hack.Slice(val))
+	if err != nil {
+		return types.Geometry{}, true, types.ErrGISInvalidData.GenWithStackByArgs(funcName)
+	}
+	return g, false, nil
+}
+
+// evalSRID evaluates the optional SRID argument.
+func evalSRID(ctx EvalContext, args []Expression, idx int, row chunk.Row, funcName string) (uint32, bool, error) {
+	if len(args) <= idx {
+		return 0, false, nil
+	}
+	srid, isNull, err := args[idx].EvalInt(ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if srid < 0 || srid > math.MaxUint32 {
+		return 0, true, errIncorrectArgs.GenWithStackByArgs(funcName)
+	}
+	return uint32(srid), false, nil
+}
+
+type pointFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *pointFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETReal, types.ETReal)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryRetType(&bf)
+	return &builtinPointSig{bf}, nil
+}
+
+type builtinPointSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinPointSig) Clone() builtinFunc {
+	newSig := &builtinPointSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals POINT(x, y).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-mysql-specific-functions.html#function_point
+func (b *builtinPointSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	x, isNull, err := b.args[0].EvalReal(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	y, isNull, err := b.args[1].EvalReal(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return string(types.NewPointGeometry(x, y, 0).Encode()), false, nil
+}
+
+type geomFromTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomFromTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryRetType(&bf)
+	return &builtinGeomFromTextSig{bf}, nil
+}
+
+type builtinGeomFromTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGeomFromTextSig) Clone() builtinFunc {
+	newSig := &builtinGeomFromTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals ST_GeomFromText(wkt[, srid]).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-wkt-functions.html#function_st-geomfromtext
+func (b *builtinGeomFromTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	wkt, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	srid, isNull, err := evalSRID(ctx, b.args, 1, row, "st_geomfromtext")
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	g, err := types.ParseGeometryWKT(wkt, srid)
+	if err != nil {
+		return "", true, types.ErrGISInvalidData.GenWithStackByArgs("st_geomfromtext")
+	}
+	return string(g.Encode()), false, nil
+}
+
+type geomFromWKBFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geomFromWKBFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryRetType(&bf)
+	return &builtinGeomFromWKBSig{bf}, nil
+}
+
+type builtinGeomFromWKBSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGeomFromWKBSig) Clone() builtinFunc {
+	newSig := &builtinGeomFromWKBSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals ST_GeomFromWKB(wkb[, srid]).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-wkb-functions.html#function_st-geomfromwkb
+func (b *builtinGeomFromWKBSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	wkb, isNull, err := b.args[0].EvalString(ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	srid, isNull, err := evalSRID(ctx, b.args, 1, row, "st_geomfromwkb")
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	g, err := types.ParseGeometryWKB(hack.Slice(wkb), srid)
+	if err != nil {
+		return "", true, types.ErrGISInvalidData.GenWithStackByArgs("st_geomfromwkb")
+	}
+	return string(g.Encode()), false, nil
+}
+
+type asTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *asTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxLongBlobWidth)
+	return &builtinAsTextSig{bf}, nil
+}
+
+type builtinAsTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAsTextSig) Clone() builtinFunc {
+	newSig := &builtinAsTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals ST_AsText(g).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-format-conversion-functions.html#function_st-astext
+func (b *builtinAsTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, "st_astext")
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return g.WKT(), false, nil
+}
+
+type asBinaryFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *asBinaryFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxLongBlobWidth)
+	bf.tp.AddFlag(mysql.BinaryFlag)
+	return &builtinAsBinarySig{bf}, nil
+}
+
+type builtinAsBinarySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAsBinarySig) Clone() builtinFunc {
+	newSig := &builtinAsBinarySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals ST_AsBinary(g).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-format-conversion-functions.html#function_st-asbinary
+func (b *builtinAsBinarySig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, "st_asbinary")
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return string(g.WKB()), false, nil
+}
+
+type asGeoJSONFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *asGeoJSONFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinAsGeoJSONSig{bf}, nil
+}
+
+type builtinAsGeoJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAsGeoJSONSig) Clone() builtinFunc {
+	newSig := &builtinAsGeoJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals ST_AsGeoJSON(g[, max_dec_digits]).
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-geojson-functions.html#function_st-asgeojson
+func (b *builtinAsGeoJSONSig) evalJSON(ctx EvalContext, row chunk.Row) (types.BinaryJSON, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, "st_asgeojson")
+	if isNull || err != nil {
+		return types.BinaryJSON{}, isNull, err
+	}
+	maxDecimalDigits := int64(math.MaxInt32)
+	if len(b.args) > 1 {
+		maxDecimalDigits, isNull, err = b.args[1].EvalInt(ctx, row)
+		if isNull || err != nil {
+			return types.BinaryJSON{}, isNull, err
+		}
+		if maxDecimalDigits < 0 {
+			return types.BinaryJSON{}, true, errIncorrectArgs.GenWithStackByArgs("st_asgeojson")
+		}
+		if maxDecimalDigits > math.MaxInt32 {
+			maxDecimalDigits = math.MaxInt32
+		}
+	}
+	j, err := g.GeoJSON(int(maxDecimalDigits))
+	return j, err != nil, err
+}
+
+// evalGeometryPair evaluates the two spatial arguments of the binary functions, which must have the same SRID.
+func evalGeometryPair(ctx EvalContext, args []Expression, row chunk.Row, funcName string) (g1, g2 types.Geometry, isNull bool, err error) {
+	g1, isNull, err = evalGeometry(ctx, args[0], row, funcName)
+	if isNull || err != nil {
+		return g1, g2, isNull, err
+	}
+	g2, isNull, err = evalGeometry(ctx, args[1], row, funcName)
+	if isNull || err != nil {
+		return g1, g2, isNull, err
+	}
+	if g1.SRID != g2.SRID {
+		return g1, g2, true, types.ErrGISDifferentSRIDs.GenWithStackByArgs(funcName, g1.SRID, g2.SRID)
+	}
+	return g1, g2, false, nil
+}
+
+type stContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stContainsFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(1)
+	return &builtinSTContainsSig{baseBuiltinFunc: bf}, nil
+}
+
+type stWithinFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stWithinFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(1)
+	return &builtinSTContainsSig{baseBuiltinFunc: bf, within: true}, nil
+}
+
+// builtinSTContainsSig evaluates ST_Contains and ST_Within.
+type builtinSTContainsSig struct {
+	baseBuiltinFunc
+
+	// within indicates the function is ST_Within, whose arguments are in the reversed order.
+	within bool
+}
+
+func (b *builtinSTContainsSig) Clone() builtinFunc {
+	newSig := &builtinSTContainsSig{within: b.within}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ST_Contains(g1, g2) or ST_Within(g1, g2).
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-relation-functions-object-shapes.html#function_st-contains
+func (b *builtinSTContainsSig) evalInt(ctx EvalContext, row chunk.Row) (int64, bool, error) {
+	funcName := "st_contains"
+	if b.within {
+		funcName = "st_within"
+	}
+	g1, g2, isNull, err := evalGeometryPair(ctx, b.args, row, funcName)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b.within {
+		g1, g2 = g2, g1
+	}
+	contains, ok := g1.Contains(g2)
+	if !ok {
+		return 0, true, types.ErrGISUnsupportedArgument.GenWithStackByArgs(funcName)
+	}
+	if contains {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type distanceSphereFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *distanceSphereFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETReal}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinDistanceSphereSig{bf}, nil
+}
+
+type builtinDistanceSphereSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDistanceSphereSig) Clone() builtinFunc {
+	newSig := &builtinDistanceSphereSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ST_Distance_Sphere(g1, g2[, radius]).
+// See https://dev.mysql.com/doc/refman/8.0/en/spatial-convenience-functions.html#function_st-distance-sphere
+func (b *builtinDistanceSphereSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(ctx, b.args, row, "st_distance_sphere")
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	radius := types.DefaultSphereRadius
+	if len(b.args) > 2 {
+		radius, isNull, err = b.args[2].EvalReal(ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+	}
+	distance, err := g1.DistanceSphere(g2, radius)
+	if err != nil {
+		return 0, true, err
+	}
+	return distance, false, nil
+}
+
+type stXFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stXFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinSTCoordinateSig{baseBuiltinFunc: bf}, nil
+}
+
+type stYFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stYFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinSTCoordinateSig{baseBuiltinFunc: bf, y: true}, nil
+}
+
+// builtinSTCoordinateSig evaluates ST_X and ST_Y.
+type builtinSTCoordinateSig struct {
+	baseBuiltinFunc
+
+	// y indicates the function is ST_Y.
+	y bool
+}
+
+func (b *builtinSTCoordinateSig) Clone() builtinFunc {
+	newSig := &builtinSTCoordinateSig{y: b.y}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ST_X(p) or ST_Y(p).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-point-property-functions.html#function_st-x
+func (b *builtinSTCoordinateSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	funcName := "st_x"
+	if b.y {
+		funcName = "st_y"
+	}
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, funcName)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if g.Type != types.GeometryTypePoint {
+		return 0, true, types.ErrGISUnsupportedArgument.GenWithStackByArgs(funcName)
+	}
+	if b.y {
+		return g.Points[0].Y, false, nil
+	}
+	return g.Points[0].X, false, nil
+}
+
+type stSRIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stSRIDFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.AddFlag(mysql.UnsignedFlag)
+	bf.tp.SetFlen(10)
+	return &builtinSTSRIDSig{bf}, nil
+}
+
+type builtinSTSRIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSTSRIDSig) Clone() builtinFunc {
+	newSig := &builtinSTSRIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ST_SRID(g).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-general-property-functions.html#function_st-srid
+func (b *builtinSTSRIDSig) evalInt(ctx EvalContext, row chunk.Row) (int64, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, "st_srid")
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(g.SRID), false, nil
+}
+
+type geometryTypeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *geometryTypeFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(len("MULTILINESTRING"))
+	return &builtinGeometryTypeSig{bf}, nil
+}
+
+type builtinGeometryTypeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGeometryTypeSig) Clone() builtinFunc {
+	newSig := &builtinGeometryTypeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals ST_GeometryType(g).
+// See https://dev.mysql.com/doc/refman/8.0/en/gis-general-property-functions.html#function_st-geometrytype
+func (b *builtinGeometryTypeSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	g, isNull, err := evalGeometry(ctx, b.args[0], row, "st_geometrytype")
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return strings.ToUpper(g.Type.String()), false, nil
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/hack"
+)
+
+var (
+	_ functionClass = &vecDistanceFunctionClass{}
+	_ functionClass = &vecFromTextFunctionClass{}
+	_ functionClass = &vecAsTextFunctionClass{}
+	_ functionClass = &vecDimsFunctionClass{}
+	_ functionClass = &vecL2NormFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinVecL2DistanceSig{}
+	_ builtinFunc = &builtinVecCosineDistanceSig{}
+	_ builtinFunc = &builtinVecInnerProductSig{}
+	_ builtinFunc = &builtinVecFromTextSig{}
+	_ builtinFunc = &builtinVecAsTextSig{}
+	_ builtinFunc = &builtinVecDimsSig{}
+	_ builtinFunc = &builtinVecL2NormSig{}
+)
+
+// evalVector evaluates the argument as a vector. The argument can be a vector or the text form of a vector.
+func evalVector(ctx EvalContext, arg Expression, row chunk.Row) (types.VectorFloat32, bool, error) {
+	val, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	v, err := types.ConvertToVectorFloat32(hack.Slice(val))
+	if err != nil {
+		return nil, true, err
+	}
+	return v, false, nil
+}
+
+// evalVectorPair evaluates the two arguments of a distance function.
+func evalVectorPair(ctx EvalContext, args []Expression, row chunk.Row) (v1, v2 types.VectorFloat32, isNull bool, err error) {
+	v1, isNull, err = evalVector(ctx, args[0], row)
+	if isNull || err != nil {
+		return nil, nil, isNull, err
+	}
+	v2, isNull, err = evalVector(ctx, args[1], row)
+	if isNull || err != nil {
+		return nil, nil, isNull, err
+	}
+	return v1, v2, false, nil
+}
+
+type vecDistanceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecDistanceFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	switch c.funcName {
+	case ast.VecL2Distance:
+		return &builtinVecL2DistanceSig{bf}, nil
+	case ast.VecCosineDistance:
+		return &builtinVecCosineDistanceSig{bf}, nil
+	default:
+		return &builtinVecInnerProductSig{bf}, nil
+	}
+}
+
+type builtinVecL2DistanceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecL2DistanceSig) Clone() builtinFunc {
+	newSig := &builtinVecL2DistanceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals VEC_L2_DISTANCE(v1, v2), the Euclidean distance between two vectors.
+func (b *builtinVecL2DistanceSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	v1, v2, isNull, err := evalVectorPair(ctx, b.args, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, err := v1.L2Distance(v2)
+	if err != nil {
+		return 0, true, err
+	}
+	return d, false, nil
+}
+
+type builtinVecCosineDistanceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecCosineDistanceSig) Clone() builtinFunc {
+	newSig := &builtinVecCosineDistanceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals VEC_COSINE_DISTANCE(v1, v2), which is 1 - the cosine similarity of two vectors.
+// The result is NULL if either vector is a zero vector.
+func (b *builtinVecCosineDistanceSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	v1, v2, isNull, err := evalVectorPair(ctx, b.args, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, isNull, err := v1.CosineDistance(v2)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return d, false, nil
+}
+
+type builtinVecInnerProductSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecInnerProductSig) Clone() builtinFunc {
+	newSig := &builtinVecInnerProductSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals VEC_INNER_PRODUCT(v1, v2).
+func (b *builtinVecInnerProductSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	v1, v2, isNull, err := evalVectorPair(ctx, b.args, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, err := v1.InnerProduct(v2)
+	if err != nil {
+		return 0, true, err
+	}
+	return d, false, nil
+}
+
+type vecFromTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecFromTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetType(mysql.TypeTiDBVectorFloat32)
+	bf.tp.SetFlen(types.UnspecifiedLength)
+	bf.tp.AddFlag(mysql.BinaryFlag)
+	return &builtinVecFromTextSig{bf}, nil
+}
+
+type builtinVecFromTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecFromTextSig) Clone() builtinFunc {
+	newSig := &builtinVecFromTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals VEC_FROM_TEXT(str), which converts the text form of a vector to a vector.
+func (b *builtinVecFromTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	v, isNull, err := evalVector(ctx, b.args[0], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return string(types.EncodeVectorFloat32(v)), false, nil
+}
+
+type vecAsTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecAsTextFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(mysql.MaxLongBlobWidth)
+	return &builtinVecAsTextSig{bf}, nil
+}
+
+type builtinVecAsTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecAsTextSig) Clone() builtinFunc {
+	newSig := &builtinVecAsTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals VEC_AS_TEXT(v), which returns the text form of a vector.
+func (b *builtinVecAsTextSig) evalString(ctx EvalContext, row chunk.Row) (string, bool, error) {
+	v, isNull, err := evalVector(ctx, b.args[0], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return v.String(), false, nil
+}
+
+type vecDimsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecDimsFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinVecDimsSig{bf}, nil
+}
+
+type builtinVecDimsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecDimsSig) Clone() builtinFunc {
+	newSig := &builtinVecDimsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals VEC_DIMS(v), which returns the dimension of a vector.
+func (b *builtinVecDimsSig) evalInt(ctx EvalContext, row chunk.Row) (int64, bool, error) {
+	v, isNull, err := evalVector(ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(len(v)), false, nil
+}
+
+type vecL2NormFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecL2NormFunctionClass) getFunction(ctx BuildContext, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinVecL2NormSig{bf}, nil
+}
+
+type builtinVecL2NormSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecL2NormSig) Clone() builtinFunc {
+	newSig := &builtinVecL2NormSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals VEC_L2_NORM(v), the Euclidean norm of a vector.
+func (b *builtinVecL2NormSig) evalReal(ctx EvalContext, row chunk.Row) (float64, bool, error) {
+	v, isNull, err := evalVector(ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return v.L2Norm(), false, nil
+}
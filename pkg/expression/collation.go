@@ -283,6 +283,10 @@ func deriveCollation(ctx BuildContext, funcName string, args []Expression, retTy
 		ec = &ExprCollation{Coer: CoercibilityCoercible, Repe: ASCII}
 		ec.Charset, ec.Collation = ctx.GetSessionVars().GetCharsetInfo()
 		return ec, nil
+	case ast.Point, ast.STGeomFromText, ast.STGeometryFromText, ast.STGeomFromWKB, ast.STGeometryFromWKB,
+		ast.STAsBinary, ast.STAsWKB, ast.VecFromText:
+		// Spatial values, vectors and WKB are binary strings.
+		return &ExprCollation{CoercibilityCoercible, ASCII, charset.CharsetBin, charset.CollationBin}, nil
 	case ast.JSONPretty, ast.JSONQuote:
 		// JSON function always return utf8mb4 and utf8mb4_bin.
 		ec = &ExprCollation{Coer: CoercibilityCoercible, Repe: UNICODE, Charset: charset.CharsetUTF8MB4, Collation: charset.CollationUTF8MB4}
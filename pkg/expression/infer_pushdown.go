@@ -123,7 +123,7 @@ func canExprPushDown(ctx PushDownContext, expr Expression, storeType kv.StoreTyp
 	pc := ctx.PbConverter()
 	if storeType == kv.TiFlash {
 		switch expr.GetType().GetType() {
-		case mysql.TypeEnum, mysql.TypeBit, mysql.TypeSet, mysql.TypeGeometry, mysql.TypeUnspecified:
+		case mysql.TypeEnum, mysql.TypeBit, mysql.TypeSet, mysql.TypeGeometry, mysql.TypeTiDBVectorFloat32, mysql.TypeUnspecified:
 			if expr.GetType().GetType() == mysql.TypeEnum && canEnumPush {
 				break
 			}
@@ -3002,3 +3002,41 @@ func TestIssue43527(t *testing.T) {
 		"SELECT @total := @total + d FROM (SELECT d FROM test) AS temp, (SELECT @total := b FROM test) AS T1 where @total >= 100",
 	).Check(testkit.Rows("200", "300", "400", "500"))
 }
+
+func TestSpatialFunctions(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table zones (id int primary key, zone polygon, center point, shape geometry)")
+	tk.MustExec(`insert into zones values
+		(1, st_geomfromtext('POLYGON((0 0,10 0,10 10,0 10,0 0))'), point(5, 5), st_geomfromtext('LINESTRING(0 0,1 1)')),
+		(2, st_geomfromtext('POLYGON((20 20,30 20,30 30,20 30,20 20))', 4326), st_geomfromtext('POINT(25 25)', 4326), null)`)
+	tk.MustQuery("select id, st_astext(zone), st_aswkt(center), st_srid(zone), st_geometrytype(shape) from zones order by id").Check(testkit.RowsWithSep("|",
+		"1|POLYGON((0 0,10 0,10 10,0 10,0 0))|POINT(5 5)|0|LINESTRING",
+		"2|POLYGON((20 20,30 20,30 30,20 30,20 20))|POINT(25 25)|4326|<nil>"))
+	tk.MustQuery("select id from zones where st_contains(zone, st_geomfromtext('POINT(21 22)', st_srid(zone)))").Check(testkit.Rows("2"))
+	tk.MustQuery("select id from zones where st_within(center, zone) order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select st_x(center), st_y(center) from zones where id = 1").Check(testkit.Rows("5 5"))
+	tk.MustQuery("select hex(st_asbinary(center)), hex(center) from zones where id = 1").Check(testkit.Rows(
+		"010100000000000000000014400000000000001440 00000000010100000000000000000014400000000000001440"))
+	tk.MustQuery("select st_astext(st_geomfromwkb(st_asbinary(zone))) from zones where id = 1").Check(testkit.RowsWithSep("|",
+		"POLYGON((0 0,10 0,10 10,0 10,0 0))"))
+	tk.MustQuery("select st_asgeojson(center), st_asgeojson(point(1.2345, 2), 2) from zones where id = 1").Check(testkit.RowsWithSep("|",
+		`{"coordinates": [5, 5], "type": "Point"}|{"coordinates": [1.23, 2], "type": "Point"}`))
+	tk.MustQuery("select round(st_distance_sphere(point(0, 0), point(0, 1))), st_distance_sphere(point(0, 0), point(0, 1), 1) < 0.02").Check(testkit.Rows("111195 1"))
+
+	// Spatial values are NULL if any argument is NULL.
+	tk.MustQuery("select st_astext(st_geomfromtext(null)), st_contains(shape, null) from zones where id = 1").Check(testkit.Rows("<nil> <nil>"))
+
+	// Errors.
+	tk.MustGetErrCode("insert into zones(id, zone) values (3, point(1, 1))", errno.ErrCantCreateGeometryObject)
+	tk.MustGetErrCode("insert into zones(id, center) values (3, 'abc')", errno.ErrCantCreateGeometryObject)
+	require.True(t, types.ErrGISInvalidData.Equal(tk.QueryToErr("select st_geomfromtext('POINT(1)')")))
+	require.True(t, types.ErrGISDifferentSRIDs.Equal(tk.QueryToErr("select st_contains(zone, point(1, 1)) from zones")))
+	tk.MustQuery("select st_contains(zone, center) from zones where id = 2").Check(testkit.Rows("1"))
+	require.True(t, types.ErrNonpositiveRadius.Equal(tk.QueryToErr("select st_distance_sphere(point(0, 0), point(0, 1), 0)")))
+	require.True(t, types.ErrLatitudeOutOfRange.Equal(tk.QueryToErr("select st_distance_sphere(point(0, 0), point(0, 100))")))
+	require.True(t, types.ErrGISUnsupportedArgument.Equal(tk.QueryToErr("select st_distance_sphere(zone, point(0, 1)) from zones")))
+	tk.MustGetErrCode("create index idx on zones(center)", errno.ErrBlobKeyWithoutLength)
+	tk.MustGetErrCode("create table t_default (p point default 'a')", errno.ErrBlobCantHaveDefault)
+}
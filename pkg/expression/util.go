@@ -1179,6 +1179,29 @@ func ParamMarkerExpression(ctx variable.SessionVarsProvider, v *driver.ParamMark
 	return value, nil
 }
 
+// RebindParamMarkers makes the parameter markers in the expressions read the parameter values from the given session.
+// It's used by the plans shared between sessions, so the expressions must be cloned before rebinding.
+func RebindParamMarkers(ctx variable.SessionVarsProvider, exprs ...Expression) {
+	for _, expr := range exprs {
+		switch x := expr.(type) {
+		case *Constant:
+			if x.ParamMarker != nil {
+				x.ParamMarker = &ParamMarker{
+					order: x.ParamMarker.order,
+					ctx:   ctx,
+				}
+			}
+			if x.DeferredExpr != nil {
+				// Constant.Clone doesn't clone the deferred expression.
+				x.DeferredExpr = x.DeferredExpr.Clone()
+				RebindParamMarkers(ctx, x.DeferredExpr)
+			}
+		case *ScalarFunction:
+			RebindParamMarkers(ctx, x.GetArgs()...)
+		}
+	}
+}
+
 // ParamMarkerInPrepareChecker checks whether the given ast tree has paramMarker and is in prepare statement.
 type ParamMarkerInPrepareChecker struct {
 	InPrepareStmt bool
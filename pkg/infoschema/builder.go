@@ -96,6 +96,8 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		return applyReorganizePartition(b, m, diff)
 	case model.ActionExchangeTablePartition:
 		return applyExchangeTablePartition(b, m, diff)
+	case model.ActionCreateMaterializedView, model.ActionDropMaterializedView:
+		return b.applyMaterializedViewChange(m, diff)
 	case model.ActionFlashbackCluster:
 		return []int64{-1}, nil
 	default:
@@ -311,6 +313,42 @@ func (b *Builder) applyAffectedOpts(m *meta.Meta, tblIDs []int64, diff *model.Sc
 	return tblIDs, nil
 }
 
+// applyMaterializedViewChange applies the creation or dropping of a materialized view. The diff and each affected
+// option creates a table if only the new table ID is set, drops a table if only the old table ID is set, and reloads
+// the table otherwise. The base tables are reloaded because their change logs are changed.
+func (b *Builder) applyMaterializedViewChange(m *meta.Meta, diff *model.SchemaDiff) ([]int64, error) {
+	opts := make([]*model.AffectedOption, 0, len(diff.AffectedOpts)+1)
+	opts = append(opts, &model.AffectedOption{
+		SchemaID:    diff.SchemaID,
+		OldSchemaID: diff.SchemaID,
+		TableID:     diff.TableID,
+		OldTableID:  diff.OldTableID,
+	})
+	opts = append(opts, diff.AffectedOpts...)
+	tblIDs := make([]int64, 0, len(opts))
+	for _, opt := range opts {
+		affectedDiff := &model.SchemaDiff{
+			Version:     diff.Version,
+			Type:        model.ActionCreateTable,
+			SchemaID:    opt.SchemaID,
+			TableID:     opt.TableID,
+			OldSchemaID: opt.OldSchemaID,
+			OldTableID:  opt.OldTableID,
+		}
+		if opt.TableID == 0 {
+			affectedDiff.Type = model.ActionDropTable
+			affectedDiff.SchemaID = opt.OldSchemaID
+			affectedDiff.TableID = opt.OldTableID
+		}
+		affectedIDs, err := b.ApplyDiff(m, affectedDiff)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		tblIDs = append(tblIDs, affectedIDs...)
+	}
+	return tblIDs, nil
+}
+
 func applyDefaultAction(b *Builder, m *meta.Meta, diff *model.SchemaDiff) ([]int64, error) {
 	tblIDs, err := applyTableUpdate(b, m, diff)
 	if err != nil {
@@ -340,7 +378,7 @@ func (b *Builder) getTableIDs(diff *model.SchemaDiff) (oldTableID, newTableID in
 		oldTableID = diff.TableID
 	case model.ActionTruncateTable, model.ActionCreateView,
 		model.ActionExchangeTablePartition, model.ActionAlterTablePartitioning,
-		model.ActionRemovePartitioning:
+		model.ActionRemovePartitioning, model.ActionAlterPrimaryKey:
 		oldTableID = diff.OldTableID
 		newTableID = diff.TableID
 	default:
@@ -357,7 +395,7 @@ func (b *Builder) updateBundleForTableUpdate(diff *model.SchemaDiff, newTableID,
 		b.markTableBundleShouldUpdate(newTableID)
 	case model.ActionDropTable:
 		b.deleteBundle(b.infoSchema, oldTableID)
-	case model.ActionTruncateTable:
+	case model.ActionTruncateTable, model.ActionAlterPrimaryKey:
 		b.deleteBundle(b.infoSchema, oldTableID)
 		b.markTableBundleShouldUpdate(newTableID)
 	case model.ActionRecoverTable:
@@ -623,6 +661,13 @@ func (b *Builder) buildAllocsForCreateTable(tp model.ActionType, dbInfo *model.D
 				newAlloc := autoid.NewAllocator(b.Requirement, dbInfo.ID, tblInfo.ID, tblInfo.IsAutoRandomBitColUnsigned(), autoid.AutoRandomType, tblVer)
 				allocs = allocs.Append(newAlloc)
 			}
+		case model.ActionAlterPrimaryKey:
+			// The table the writes are applied to is changed in every state, so are its allocators.
+			allocs.PrimaryKeyReorg = nil
+			if pi := tblInfo.PrimaryKeyReorgInfo; pi != nil && pi.Table != nil {
+				reorgAllocs := autoid.NewAllocatorsFromTblInfo(b.Requirement, dbInfo.ID, pi.Table)
+				allocs.PrimaryKeyReorg = &reorgAllocs
+			}
 		}
 		return allocs
 	}
@@ -670,6 +715,7 @@ func applyCreateTable(b *Builder, m *meta.Meta, dbInfo *model.DBInfo, tableID in
 	}
 
 	b.infoSchema.addReferredForeignKeys(dbInfo.Name, tblInfo)
+	b.infoSchema.addMaterializedView(tblInfo)
 
 	if !b.enableV2 {
 		tableNames := b.infoSchema.schemaMap[dbInfo.Name.L]
@@ -757,6 +803,7 @@ func (b *Builder) deleteReferredForeignKeys(dbInfo *model.DBInfo, tableID int64)
 				dbInfo.Tables = append(dbInfo.Tables[:i], dbInfo.Tables[i+1:]...)
 			}
 			b.infoSchema.deleteReferredForeignKeys(dbInfo.Name, tblInfo)
+			b.infoSchema.deleteMaterializedView(tblInfo)
 			break
 		}
 	}
@@ -793,6 +840,7 @@ func (b *Builder) InitWithOldInfoSchema(oldSchema InfoSchema) (*Builder, error)
 	b.copyResourceGroupMap(oldIS)
 	b.copyTemporaryTableIDsMap(oldIS)
 	b.copyReferredForeignKeyMap(oldIS)
+	b.copyMaterializedViewMap(oldIS)
 
 	copy(b.infoSchema.sortedTablesBuckets, oldIS.sortedTablesBuckets)
 	return b, nil
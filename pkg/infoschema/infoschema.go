@@ -82,6 +82,10 @@ type infoSchemaMisc struct {
 	// referredForeignKeyMap records all table's ReferredFKInfo.
 	// referredSchemaAndTableName => child SchemaAndTableAndForeignKeyName => *model.ReferredFKInfo
 	referredForeignKeyMap map[SchemaAndTableName][]*model.ReferredFKInfo
+
+	// materializedViewMap records the IDs of the materialized views of every table.
+	// base table ID => materialized view IDs
+	materializedViewMap map[int64][]int64
 }
 
 // SchemaAndTableName contains the lower-case schema name and table name.
@@ -553,6 +557,46 @@ func (is *infoSchemaMisc) GetTableReferredForeignKeys(schema, table string) []*m
 	return is.referredForeignKeyMap[name]
 }
 
+func (is *infoSchemaMisc) addMaterializedView(tbInfo *model.TableInfo) {
+	if tbInfo.MaterializedView == nil {
+		return
+	}
+	for _, baseID := range tbInfo.MaterializedView.BaseTableIDs {
+		views := is.materializedViewMap[baseID]
+		if slices.Contains(views, tbInfo.ID) {
+			continue
+		}
+		newViews := make([]int64, 0, len(views)+1)
+		newViews = append(newViews, views...)
+		is.materializedViewMap[baseID] = append(newViews, tbInfo.ID)
+	}
+}
+
+func (is *infoSchemaMisc) deleteMaterializedView(tbInfo *model.TableInfo) {
+	if tbInfo.MaterializedView == nil {
+		return
+	}
+	for _, baseID := range tbInfo.MaterializedView.BaseTableIDs {
+		views := is.materializedViewMap[baseID]
+		newViews := make([]int64, 0, len(views))
+		for _, id := range views {
+			if id != tbInfo.ID {
+				newViews = append(newViews, id)
+			}
+		}
+		if len(newViews) == 0 {
+			delete(is.materializedViewMap, baseID)
+		} else {
+			is.materializedViewMap[baseID] = newViews
+		}
+	}
+}
+
+// GetMaterializedViews gets the IDs of the materialized views defined on the table.
+func (is *infoSchemaMisc) GetMaterializedViews(tableID int64) []int64 {
+	return is.materializedViewMap[tableID]
+}
+
 // SessionTables store local temporary tables
 type SessionTables struct {
 	// Session tables can be accessed after the db is dropped, so there needs a way to retain the DBInfo.
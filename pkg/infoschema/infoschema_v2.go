@@ -233,6 +233,7 @@ func NewInfoSchemaV2(r autoid.Requirement, infoData *Data) infoschemaV2 {
 				resourceGroupMap:      map[string]*model.ResourceGroupInfo{},
 				ruleBundleMap:         map[int64]*placement.Bundle{},
 				referredForeignKeyMap: make(map[SchemaAndTableName][]*model.ReferredFKInfo),
+				materializedViewMap:   make(map[int64][]int64),
 			},
 			schemaMap:           map[string]*schemaTables{},
 			sortedTablesBuckets: make([]sortedTables, bucketCount),
@@ -774,6 +775,7 @@ func (b *Builder) applyDropTableV2(diff *model.SchemaDiff, dbInfo *model.DBInfo,
 
 	// The old DBInfo still holds a reference to old table info, we need to remove it.
 	b.infoSchema.deleteReferredForeignKeys(dbInfo.Name, table.Meta())
+	b.infoSchema.deleteMaterializedView(table.Meta())
 
 	b.infoData.remove(tableItem{
 		dbName:        dbInfo.Name.L,
@@ -50,4 +50,6 @@ type Misc interface {
 	HasTemporaryTable() bool
 	// GetTableReferredForeignKeys gets the table's ReferredFKInfo by lowercase schema and table name.
 	GetTableReferredForeignKeys(schema, table string) []*model.ReferredFKInfo
+	// GetMaterializedViews gets the IDs of the materialized views defined on the table.
+	GetMaterializedViews(tableID int64) []int64
 }
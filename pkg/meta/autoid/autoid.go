@@ -206,6 +206,9 @@ type Allocator interface {
 type Allocators struct {
 	SepAutoInc bool
 	Allocs     []Allocator
+	// PrimaryKeyReorg is the allocators of the table the writes are applied to when the primary key
+	// of the table is being changed. The IDs of the rows in it are allocated with its own table ID.
+	PrimaryKeyReorg *Allocators
 }
 
 // NewAllocators packs multiple `Allocator`s into Allocators.
@@ -219,8 +222,9 @@ func NewAllocators(sepAutoInc bool, allocators ...Allocator) Allocators {
 // Append add an allocator to the allocators.
 func (all Allocators) Append(a Allocator) Allocators {
 	return Allocators{
-		SepAutoInc: all.SepAutoInc,
-		Allocs:     append(all.Allocs, a),
+		SepAutoInc:      all.SepAutoInc,
+		Allocs:          append(all.Allocs, a),
+		PrimaryKeyReorg: all.PrimaryKeyReorg,
 	}
 }
 
@@ -249,8 +253,9 @@ func (all Allocators) Filter(pred func(Allocator) bool) Allocators {
 		}
 	}
 	return Allocators{
-		SepAutoInc: all.SepAutoInc,
-		Allocs:     ret,
+		SepAutoInc:      all.SepAutoInc,
+		Allocs:          ret,
+		PrimaryKeyReorg: all.PrimaryKeyReorg,
 	}
 }
 
@@ -675,7 +680,12 @@ func NewAllocatorsFromTblInfo(r Requirement, schemaID int64, tblInfo *model.Tabl
 	if tblInfo.IsSequence() {
 		allocs = append(allocs, NewSequenceAllocator(r.Store(), dbID, tblInfo.ID, tblInfo.Sequence))
 	}
-	return NewAllocators(tblInfo.SepAutoInc(), allocs...)
+	ret := NewAllocators(tblInfo.SepAutoInc(), allocs...)
+	if pi := tblInfo.PrimaryKeyReorgInfo; pi != nil && pi.Table != nil {
+		reorgAllocs := NewAllocatorsFromTblInfo(r, schemaID, pi.Table)
+		ret.PrimaryKeyReorg = &reorgAllocs
+	}
+	return ret
 }
 
 // Alloc implements autoid.Allocator Alloc interface.
@@ -24,6 +24,11 @@ var _ StmtNode = &IndexAdviseStmt{}
 type IndexAdviseStmt struct {
 	stmtNode
 
+	// FromStmtSummary indicates the workload is the top statements of the
+	// statements summary instead of a SQL file.
+	FromStmtSummary bool
+	// Limit is the maximum number of statements taken from the statements summary.
+	Limit       uint64
 	IsLocal     bool
 	Path        string
 	MaxMinutes  uint64
@@ -33,12 +38,19 @@ type IndexAdviseStmt struct {
 
 // Restore implements Node Accept interface.
 func (n *IndexAdviseStmt) Restore(ctx *format.RestoreCtx) error {
-	ctx.WriteKeyWord("INDEX ADVISE ")
-	if n.IsLocal {
-		ctx.WriteKeyWord("LOCAL ")
+	ctx.WriteKeyWord("INDEX ADVISE")
+	if n.FromStmtSummary {
+		if n.Limit != UnspecifiedSize {
+			ctx.WriteKeyWord(" LIMIT ")
+			ctx.WritePlainf("%d", n.Limit)
+		}
+	} else {
+		if n.IsLocal {
+			ctx.WriteKeyWord(" LOCAL")
+		}
+		ctx.WriteKeyWord(" INFILE ")
+		ctx.WriteString(n.Path)
 	}
-	ctx.WriteKeyWord("INFILE ")
-	ctx.WriteString(n.Path)
 	if n.MaxMinutes != UnspecifiedSize {
 		ctx.WriteKeyWord(" MAX_MINUTES ")
 		ctx.WritePlainf("%d", n.MaxMinutes)
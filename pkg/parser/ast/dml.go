@@ -1569,6 +1569,14 @@ func (n *SelectStmt) Accept(v Visitor) (Node, bool) {
 		}
 	}
 
+	if n.SelectIntoOpt != nil {
+		node, ok := n.SelectIntoOpt.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.SelectIntoOpt = node.(*SelectIntoOption)
+	}
+
 	return v.Leave(n)
 }
 
@@ -3051,6 +3059,8 @@ const (
 	ShowCreateProcedure
 	ShowBinlogStatus
 	ShowReplicaStatus
+	ShowCreateTrigger
+	ShowCreateEvent
 )
 
 const (
@@ -3076,6 +3086,8 @@ type ShowStmt struct {
 	Table  *TableName // Used for showing columns.
 	// Procedure's naming method is consistent with the table name
 	Procedure         *TableName
+	Trigger           *TableName  // Used for `show create trigger`.
+	Event             *TableName  // Used for `show create event`.
 	Partition         model.CIStr // Used for showing partition.
 	Column            *ColumnName // Used for `desc table column`.
 	IndexName         model.CIStr
@@ -3152,6 +3164,16 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := n.Procedure.Restore(ctx); err != nil {
 			return errors.Annotate(err, "An error occurred while restore ShowStmt.Procedure")
 		}
+	case ShowCreateTrigger:
+		ctx.WriteKeyWord("CREATE TRIGGER ")
+		if err := n.Trigger.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ShowStmt.Trigger")
+		}
+	case ShowCreateEvent:
+		ctx.WriteKeyWord("CREATE EVENT ")
+		if err := n.Event.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ShowStmt.Event")
+		}
 	case ShowCreateView:
 		ctx.WriteKeyWord("CREATE VIEW ")
 		if err := n.Table.Restore(ctx); err != nil {
@@ -3622,8 +3644,10 @@ type SelectIntoOption struct {
 
 	Tp         SelectIntoType
 	FileName   string
+	Format     *string
 	FieldsInfo *FieldsClause
 	LinesInfo  *LinesClause
+	Options    []*LoadDataOpt
 }
 
 // Restore implements Node interface.
@@ -3635,6 +3659,10 @@ func (n *SelectIntoOption) Restore(ctx *format.RestoreCtx) error {
 
 	ctx.WriteKeyWord("INTO OUTFILE ")
 	ctx.WriteString(n.FileName)
+	if n.Format != nil {
+		ctx.WriteKeyWord(" FORMAT ")
+		ctx.WriteString(*n.Format)
+	}
 	if n.FieldsInfo != nil {
 		if err := n.FieldsInfo.Restore(ctx); err != nil {
 			return errors.Annotate(err, "An error occurred while restore SelectInto.FieldsInfo")
@@ -3645,6 +3673,18 @@ func (n *SelectIntoOption) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotate(err, "An error occurred while restore SelectInto.LinesInfo")
 		}
 	}
+	if len(n.Options) > 0 {
+		ctx.WriteKeyWord(" WITH")
+		for i, option := range n.Options {
+			if i != 0 {
+				ctx.WritePlain(",")
+			}
+			ctx.WritePlain(" ")
+			if err := option.Restore(ctx); err != nil {
+				return errors.Annotate(err, "An error occurred while restore SelectInto.Options")
+			}
+		}
+	}
 	return nil
 }
 
@@ -3654,6 +3694,17 @@ func (n *SelectIntoOption) Accept(v Visitor) (Node, bool) {
 	if skipChildren {
 		return v.Leave(newNode)
 	}
+	n = newNode.(*SelectIntoOption)
+	for _, opt := range n.Options {
+		if opt.Value == nil {
+			continue
+		}
+		node, ok := opt.Value.Accept(v)
+		if !ok {
+			return n, false
+		}
+		opt.Value = node.(ExprNode)
+	}
 	return v.Leave(n)
 }
 
@@ -3699,7 +3750,7 @@ func (n *PartitionByClause) Accept(v Visitor) (Node, bool) {
 type FrameType int
 
 // Window function frame types.
-// MySQL only supports `ROWS` and `RANGES`.
+// MySQL only supports `ROWS` and `RANGES`, `GROUPS` is an extension of TiDB.
 const (
 	Rows = iota
 	Ranges
@@ -3721,6 +3772,8 @@ func (n *FrameClause) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("ROWS")
 	case Ranges:
 		ctx.WriteKeyWord("RANGE")
+	case Groups:
+		ctx.WriteKeyWord("GROUPS")
 	default:
 		return errors.New("Unsupported window function frame type")
 	}
@@ -355,6 +355,37 @@ const (
 	JSONKeys          = "json_keys"
 	JSONLength        = "json_length"
 
+	// spatial functions
+	Point              = "point"
+	STAsBinary         = "st_asbinary"
+	STAsGeoJSON        = "st_asgeojson"
+	STAsText           = "st_astext"
+	STAsWKB            = "st_aswkb"
+	STAsWKT            = "st_aswkt"
+	STContains         = "st_contains"
+	STDistanceSphere   = "st_distance_sphere"
+	STGeomFromText     = "st_geomfromtext"
+	STGeomFromWKB      = "st_geomfromwkb"
+	STGeometryFromText = "st_geometryfromtext"
+	STGeometryFromWKB  = "st_geometryfromwkb"
+	STGeometryType     = "st_geometrytype"
+	STSRID             = "st_srid"
+	STWithin           = "st_within"
+	STX                = "st_x"
+	STY                = "st_y"
+
+	// vector functions
+	VecAsText         = "vec_as_text"
+	VecCosineDistance = "vec_cosine_distance"
+	VecDims           = "vec_dims"
+	VecFromText       = "vec_from_text"
+	VecInnerProduct   = "vec_inner_product"
+	VecL2Distance     = "vec_l2_distance"
+	VecL2Norm         = "vec_l2_norm"
+
+	// fulltext search functions
+	FTSMatchAgainst = "fts_match_against"
+
 	// TiDB internal function.
 	TiDBDecodeKey       = "tidb_decode_key"
 	TiDBDecodeBase64Key = "tidb_decode_base64_key"
@@ -904,10 +935,11 @@ type WindowFuncExpr struct {
 	// We need to raise error if it is not allowed to be true.
 	Distinct bool
 	// IgnoreNull indicates how to handle null value.
-	// MySQL only supports `RESPECT NULLS`, so we need to raise error if it is true.
+	// MySQL only supports `RESPECT NULLS`, TiDB also supports `IGNORE NULLS` for
+	// `FIRST_VALUE`, `LAST_VALUE`, `NTH_VALUE`, `LEAD` and `LAG`.
 	IgnoreNull bool
 	// FromLast indicates the calculation direction of this window function.
-	// MySQL only supports calculation from first, so we need to raise error if it is true.
+	// MySQL only supports calculation from first, TiDB also supports `FROM LAST` for `NTH_VALUE`.
 	FromLast bool
 	// Spec is the specification of this window.
 	Spec WindowSpec
@@ -917,7 +949,13 @@ type WindowFuncExpr struct {
 func (n *WindowFuncExpr) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord(n.Name)
 	ctx.WritePlain("(")
-	for i, v := range n.Args {
+	args := n.Args
+	isGroupConcat := strings.ToLower(n.Name) == AggFuncGroupConcat && len(args) > 0
+	if isGroupConcat {
+		// The last argument of group_concat is the separator.
+		args = args[:len(args)-1]
+	}
+	for i, v := range args {
 		if i != 0 {
 			ctx.WritePlain(", ")
 		} else if n.Distinct {
@@ -927,6 +965,12 @@ func (n *WindowFuncExpr) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore WindowFuncExpr.Args[%d]", i)
 		}
 	}
+	if isGroupConcat {
+		ctx.WriteKeyWord(" SEPARATOR ")
+		if err := n.Args[len(n.Args)-1].Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore WindowFuncExpr.Args SEPARATOR")
+		}
+	}
 	ctx.WritePlain(")")
 	if n.FromLast {
 		ctx.WriteKeyWord(" FROM LAST")
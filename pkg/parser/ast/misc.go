@@ -769,6 +769,130 @@ func (n *RollbackStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// XAStmtType is the type of XAStmt.
+type XAStmtType int
+
+// XAStmt types.
+const (
+	XAStart XAStmtType = iota
+	XAEnd
+	XAPrepare
+	XACommit
+	XARollback
+	XARecover
+)
+
+// XAOption is the trailing option of XAStmt.
+type XAOption int
+
+// XAStmt options.
+const (
+	XAOptionNone XAOption = iota
+	XAOptionJoin
+	XAOptionResume
+	XAOptionSuspend
+	XAOptionSuspendForMigrate
+	XAOptionOnePhase
+	XAOptionConvertXID
+)
+
+// XID is the identifier of an XA transaction branch, which consists of a global transaction identifier,
+// a branch qualifier and a format ID.
+type XID struct {
+	GTRID *TextString
+	// BQUAL is nil if the branch qualifier is not specified.
+	BQUAL    *TextString
+	FormatID uint64
+}
+
+func restoreXIDPart(ctx *format.RestoreCtx, s *TextString) {
+	if s.IsBinaryLiteral {
+		ctx.WritePlainf("0x%x", s.Value)
+		return
+	}
+	ctx.WriteString(s.Value)
+}
+
+// Restore implements Node interface.
+func (n *XID) Restore(ctx *format.RestoreCtx) error {
+	restoreXIDPart(ctx, n.GTRID)
+	if n.BQUAL == nil && n.FormatID == 1 {
+		return nil
+	}
+	ctx.WritePlain(",")
+	if n.BQUAL != nil {
+		restoreXIDPart(ctx, n.BQUAL)
+	} else {
+		ctx.WriteString("")
+	}
+	if n.FormatID != 1 {
+		ctx.WritePlainf(",%d", n.FormatID)
+	}
+	return nil
+}
+
+// XAStmt is a statement to manage XA transactions.
+// See https://dev.mysql.com/doc/refman/8.0/en/xa-statements.html
+type XAStmt struct {
+	stmtNode
+
+	Tp XAStmtType
+	// XID is nil for XA RECOVER.
+	XID    *XID
+	Option XAOption
+}
+
+// Restore implements Node interface.
+func (n *XAStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("XA ")
+	switch n.Tp {
+	case XAStart:
+		ctx.WriteKeyWord("START ")
+	case XAEnd:
+		ctx.WriteKeyWord("END ")
+	case XAPrepare:
+		ctx.WriteKeyWord("PREPARE ")
+	case XACommit:
+		ctx.WriteKeyWord("COMMIT ")
+	case XARollback:
+		ctx.WriteKeyWord("ROLLBACK ")
+	case XARecover:
+		ctx.WriteKeyWord("RECOVER")
+	default:
+		return errors.Errorf("invalid XAStmt type: %d", n.Tp)
+	}
+	if n.XID != nil {
+		if err := n.XID.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore XAStmt.XID")
+		}
+	}
+	switch n.Option {
+	case XAOptionJoin:
+		ctx.WriteKeyWord(" JOIN")
+	case XAOptionResume:
+		ctx.WriteKeyWord(" RESUME")
+	case XAOptionSuspend:
+		ctx.WriteKeyWord(" SUSPEND")
+	case XAOptionSuspendForMigrate:
+		ctx.WriteKeyWord(" SUSPEND FOR MIGRATE")
+	case XAOptionOnePhase:
+		ctx.WriteKeyWord(" ONE PHASE")
+	case XAOptionConvertXID:
+		ctx.WriteKeyWord(" CONVERT XID")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *XAStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*XAStmt)
+	return v.Leave(n)
+}
+
 // UseStmt is a statement to use the DBName database as the current database.
 // See https://dev.mysql.com/doc/refman/5.7/en/use.html
 type UseStmt struct {
@@ -2333,6 +2457,7 @@ const (
 	AdminSetBDRRole
 	AdminShowBDRRole
 	AdminUnsetBDRRole
+	AdminResetCardinalityFeedback
 )
 
 // HandleRange represents a range where handle value >= Begin and < End.
@@ -2635,6 +2760,14 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("SHOW BDR ROLE")
 	case AdminUnsetBDRRole:
 		ctx.WriteKeyWord("UNSET BDR ROLE")
+	case AdminResetCardinalityFeedback:
+		ctx.WriteKeyWord("RESET CARDINALITY FEEDBACK")
+		if len(n.Tables) > 0 {
+			ctx.WritePlain(" ")
+			if err := restoreTables(); err != nil {
+				return err
+			}
+		}
 	default:
 		return errors.New("Unsupported AdminStmt type")
 	}
@@ -17,7 +17,9 @@ import (
 	"strconv"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/types"
 )
 
@@ -233,15 +235,27 @@ func (n *ProcedureBlock) Accept(v Visitor) (Node, bool) {
 type ProcedureInfo struct {
 	stmtNode
 	IfNotExists       bool
+	Definer           *auth.UserIdentity
 	ProcedureName     *TableName
 	ProcedureParam    []*StoreParameter //procedure param
 	ProcedureBody     StmtNode          //procedure body statement
 	ProcedureParamStr string            //procedure parameter string
+	Security          model.ViewSecurity
+	Comment           string
 }
 
 // Restore implements Node interface.
 func (n *ProcedureInfo) Restore(ctx *format.RestoreCtx) error {
-	ctx.WriteKeyWord("CREATE PROCEDURE ")
+	ctx.WriteKeyWord("CREATE ")
+	if n.Definer != nil && !n.Definer.CurrentUser {
+		ctx.WriteKeyWord("DEFINER")
+		ctx.WritePlain(" = ")
+		if err := n.Definer.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ProcedureInfo.Definer")
+		}
+		ctx.WritePlain(" ")
+	}
+	ctx.WriteKeyWord("PROCEDURE ")
 	if n.IfNotExists {
 		ctx.WriteKeyWord("IF NOT EXISTS ")
 	}
@@ -260,6 +274,14 @@ func (n *ProcedureInfo) Restore(ctx *format.RestoreCtx) error {
 		}
 	}
 	ctx.WritePlain(") ")
+	if n.Security == model.SecurityInvoker {
+		ctx.WriteKeyWord("SQL SECURITY INVOKER ")
+	}
+	if n.Comment != "" {
+		ctx.WriteKeyWord("COMMENT ")
+		ctx.WriteString(n.Comment)
+		ctx.WritePlain(" ")
+	}
 	err = (n.ProcedureBody).Restore(ctx)
 	if err != nil {
 		return err
@@ -99,6 +99,9 @@ func TestProcedure(t *testing.T) {
 		`create procedure proc_2() begin labelname: while id < 10 do set id = id + 1; select 1; end while; end`,
 		`create procedure proc_2() begin labelname: while id < 10 do set id = id + 1; select 1; end while labelname; end`,
 		`create procedure proc_2(id int) begin labelname: REPEAT set id = id + 1; select 1; UNTIL id < 10 end REPEAT labelname; end`,
+		`create definer = 'root'@'%' procedure proc_2() select 1`,
+		`create definer = current_user procedure if not exists proc_2() sql security invoker comment 'x' select 1`,
+		`create procedure proc_2() comment 'x' sql security definer lbl: begin select 1; end lbl`,
 	}
 	for _, testcase := range testcases {
 		stmt, _, err := p.Parse(testcase, "", "")
@@ -109,6 +112,15 @@ func TestProcedure(t *testing.T) {
 		_, ok := stmt[0].(*ast.ProcedureInfo)
 		require.True(t, ok, testcase)
 	}
+
+	for _, testcase := range []string{
+		`create or replace procedure proc_2() select 1`,
+		`create algorithm = merge procedure proc_2() select 1`,
+		`create procedure proc_2() sql security select 1`,
+	} {
+		_, _, err := p.Parse(testcase, "", "")
+		require.Error(t, err, testcase)
+	}
 }
 
 func TestShowCreateProcedure(t *testing.T) {
@@ -217,6 +229,14 @@ func TestProcedureRestore(t *testing.T) {
 			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN `labelname`: REPEAT SET @@SESSION.`id`=`id`+1;SELECT 1;UNTIL `id`<10 END REPEAT `labelname`; END",
 			"CREATE PROCEDURE `proc_2`( IN `id` INT(11)) BEGIN `labelname`: REPEAT SET @@SESSION.`id`=`id`+1;SELECT 1;UNTIL `id`<10 END REPEAT `labelname`; END",
 		},
+		{
+			"CREATE DEFINER = `root`@`%` PROCEDURE `proc_2`() SQL SECURITY INVOKER COMMENT 'x' SELECT 1",
+			"CREATE DEFINER = `root`@`%` PROCEDURE `proc_2`() SQL SECURITY INVOKER COMMENT 'x' SELECT 1",
+		},
+		{
+			"CREATE DEFINER = CURRENT_USER PROCEDURE `proc_2`() SQL SECURITY DEFINER SELECT 1",
+			"CREATE PROCEDURE `proc_2`() SELECT 1",
+		},
 	}
 	extractNodeFunc := func(node ast.Node) ast.Node {
 		return node.(*ast.ProcedureInfo)
@@ -106,6 +106,12 @@ const (
 	ActionDropResourceGroup      ActionType = 70
 	ActionAlterTablePartitioning ActionType = 71
 	ActionRemovePartitioning     ActionType = 72
+	ActionCreateTrigger          ActionType = 73
+	ActionDropTrigger            ActionType = 74
+	ActionCreateMaterializedView ActionType = 75
+	ActionDropMaterializedView   ActionType = 76
+	ActionAlterPrimaryKey        ActionType = 77
+	ActionTrainVectorIndex       ActionType = 78
 )
 
 // ActionMap is the map of DDL ActionType to string.
@@ -177,6 +183,12 @@ var ActionMap = map[ActionType]string{
 	ActionDropResourceGroup:             "drop resource group",
 	ActionAlterTablePartitioning:        "alter table partition by",
 	ActionRemovePartitioning:            "alter table remove partitioning",
+	ActionCreateTrigger:                 "create trigger",
+	ActionDropTrigger:                   "drop trigger",
+	ActionCreateMaterializedView:        "create materialized view",
+	ActionDropMaterializedView:          "drop materialized view",
+	ActionAlterPrimaryKey:               "alter primary key",
+	ActionTrainVectorIndex:              "train vector index",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
@@ -266,6 +278,12 @@ var BDRActionMap = map[DDLBDRType][]ActionType{
 		ActionReorganizePartition,
 		ActionAlterTablePartitioning,
 		ActionRemovePartitioning,
+		ActionCreateTrigger,
+		ActionDropTrigger,
+		ActionCreateMaterializedView,
+		ActionDropMaterializedView,
+		ActionAlterPrimaryKey,
+		ActionTrainVectorIndex,
 	},
 	UnmanagementDDL: {
 		ActionCreatePlacementPolicy,
@@ -942,7 +960,8 @@ func (job *Job) NotStarted() bool {
 func (job *Job) MayNeedReorg() bool {
 	switch job.Type {
 	case ActionAddIndex, ActionAddPrimaryKey, ActionReorganizePartition,
-		ActionRemovePartitioning, ActionAlterTablePartitioning:
+		ActionRemovePartitioning, ActionAlterTablePartitioning, ActionAlterPrimaryKey,
+		ActionTrainVectorIndex:
 		return true
 	case ActionModifyColumn:
 		if len(job.CtxVars) > 0 {
@@ -979,6 +998,14 @@ func (job *Job) IsRollbackable() bool {
 		}
 	case ActionAddTablePartition:
 		return job.SchemaState == StateNone || job.SchemaState == StateReplicaOnly
+	case ActionExchangeTablePartition:
+		// In StateDeleteReorganization the partition is already exchanged,
+		// only the replaced global indexes are left to be removed.
+		return job.SchemaState != StateDeleteReorganization
+	case ActionAlterPrimaryKey:
+		// In StateDeleteReorganization the table is already switched to the
+		// new primary key, only the old table data is left to be removed.
+		return job.SchemaState != StateDeleteReorganization
 	case ActionDropColumn, ActionDropSchema, ActionDropTable, ActionDropSequence,
 		ActionDropForeignKey, ActionDropTablePartition, ActionTruncateTablePartition:
 		return job.SchemaState == StatePublic
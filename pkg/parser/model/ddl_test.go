@@ -80,6 +80,7 @@ func TestMayNeedReorg(t *testing.T) {
 		model.ActionAlterTablePartitioning,
 		model.ActionAddIndex,
 		model.ActionAddPrimaryKey,
+		model.ActionAlterPrimaryKey,
 	}
 	generalJobTypes := []model.ActionType{
 		model.ActionCreateTable,
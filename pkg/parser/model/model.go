@@ -535,6 +535,11 @@ type TableInfo struct {
 	TableCacheStatusType `json:"cache_table_status"`
 	PlacementPolicyRef   *PolicyRefInfo `json:"policy_ref_info"`
 
+	// TempTablePreserveRows means the global temporary table is defined with `ON COMMIT PRESERVE ROWS`, whose rows are
+	// kept in the session after the transaction commits. The rows are spilled to disk when the memory quota of the
+	// session is exceeded.
+	TempTablePreserveRows bool `json:"temp_table_preserve_rows,omitempty"`
+
 	// StatsOptions is used when do analyze/auto-analyze for each table
 	StatsOptions *StatsOptions `json:"stats_options"`
 
@@ -542,6 +547,18 @@ type TableInfo struct {
 
 	TTLInfo *TTLInfo `json:"ttl_info"`
 
+	// Triggers are the triggers defined on the table. The triggers with the same timing and event are activated in
+	// the order in which they appear here.
+	Triggers []*TriggerInfo `json:"triggers,omitempty"`
+
+	// MaterializedView is the definition of a materialized view. The rows of the view are stored in the table.
+	MaterializedView *MaterializedViewInfo `json:"materialized_view,omitempty"`
+	// MViewLogs are the change logs of the table, they're consumed by the fast refresh of the materialized views.
+	MViewLogs []*MViewLogInfo `json:"mview_logs,omitempty"`
+
+	// PrimaryKeyReorgInfo is set when the primary key of the table is being changed.
+	PrimaryKeyReorgInfo *PrimaryKeyReorgInfo `json:"pk_reorg_info,omitempty"`
+
 	DBID int64 `json:"-"`
 }
 
@@ -556,6 +573,12 @@ func (t *TableInfo) SepAutoInc() bool {
 	return t.Version >= TableInfoVersion5 && t.AutoIdCache == 1
 }
 
+// IsTempTableDataInSession returns whether the committed rows of the temporary table are kept in the session. It's
+// true for local temporary tables and global temporary tables with `ON COMMIT PRESERVE ROWS`.
+func (t *TableInfo) IsTempTableDataInSession() bool {
+	return t.TempTableType == TempTableLocal || (t.TempTableType == TempTableGlobal && t.TempTablePreserveRows)
+}
+
 // TableCacheStatusType is the type of the table cache status
 type TableCacheStatusType int
 
@@ -760,6 +783,26 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.TTLInfo = t.TTLInfo.Clone()
 	}
 
+	if t.Triggers != nil {
+		nt.Triggers = make([]*TriggerInfo, len(t.Triggers))
+		for i := range t.Triggers {
+			nt.Triggers[i] = t.Triggers[i].Clone()
+		}
+	}
+
+	if t.MaterializedView != nil {
+		nt.MaterializedView = t.MaterializedView.Clone()
+	}
+	if t.MViewLogs != nil {
+		nt.MViewLogs = make([]*MViewLogInfo, len(t.MViewLogs))
+		for i := range t.MViewLogs {
+			nt.MViewLogs[i] = t.MViewLogs[i].Clone()
+		}
+	}
+	if t.PrimaryKeyReorgInfo != nil {
+		nt.PrimaryKeyReorgInfo = t.PrimaryKeyReorgInfo.Clone()
+	}
+
 	return &nt
 }
 
@@ -1102,6 +1145,146 @@ func (v *ViewCheckOption) String() string {
 	}
 }
 
+// TriggerTiming is the action time of a trigger.
+// See https://dev.mysql.com/doc/refman/8.0/en/create-trigger.html
+type TriggerTiming int
+
+//revive:disable:exported
+const (
+	TriggerBefore TriggerTiming = iota
+	TriggerAfter
+)
+
+//revive:enable:exported
+
+// String implements fmt.Stringer interface.
+func (t TriggerTiming) String() string {
+	if t == TriggerAfter {
+		return "AFTER"
+	}
+	return "BEFORE"
+}
+
+// TriggerEvent is the kind of the operation activating a trigger.
+type TriggerEvent int
+
+//revive:disable:exported
+const (
+	TriggerInsert TriggerEvent = iota
+	TriggerUpdate
+	TriggerDelete
+)
+
+//revive:enable:exported
+
+// String implements fmt.Stringer interface.
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerUpdate:
+		return "UPDATE"
+	case TriggerDelete:
+		return "DELETE"
+	default:
+		return "INSERT"
+	}
+}
+
+// TriggerInfo provides meta data describing a trigger.
+type TriggerInfo struct {
+	Name    CIStr              `json:"name"`
+	Timing  TriggerTiming      `json:"timing"`
+	Event   TriggerEvent       `json:"event"`
+	Definer *auth.UserIdentity `json:"definer"`
+	// Definition is the CREATE TRIGGER statement. It's parsed with the SQLMode and executed in the database of the
+	// table when the trigger is activated.
+	Definition string `json:"definition"`
+	// Body is the text of the trigger body in the definition.
+	Body      string    `json:"body"`
+	SQLMode   string    `json:"sql_mode"`
+	Charset   string    `json:"charset"`
+	Collation string    `json:"collation"`
+	Created   time.Time `json:"created"`
+}
+
+// Clone clones TriggerInfo.
+func (t *TriggerInfo) Clone() *TriggerInfo {
+	nt := *t
+	return &nt
+}
+
+// MViewRefreshMethod is the refresh method of a materialized view.
+type MViewRefreshMethod byte
+
+// Refresh methods of a materialized view.
+const (
+	// MViewRefreshComplete replaces all the rows of the view by the result of the definition.
+	MViewRefreshComplete MViewRefreshMethod = iota
+	// MViewRefreshFast only recomputes the rows affected by the changes recorded in the change logs.
+	MViewRefreshFast
+)
+
+// String implements fmt.Stringer interface.
+func (m MViewRefreshMethod) String() string {
+	if m == MViewRefreshFast {
+		return "FAST"
+	}
+	return "COMPLETE"
+}
+
+// MaterializedViewInfo provides meta data describing a materialized view.
+type MaterializedViewInfo struct {
+	Definer *auth.UserIdentity `json:"definer"`
+	// SelectStmt is the definition of the view, the table names in it are qualified by the schema names.
+	SelectStmt    string             `json:"select_stmt"`
+	SQLMode       mysql.SQLMode      `json:"sql_mode"`
+	RefreshMethod MViewRefreshMethod `json:"refresh_method"`
+	// BaseTableIDs are the IDs of the tables referenced by the definition.
+	BaseTableIDs []int64 `json:"base_table_ids"`
+	// LogTableIDs are the IDs of the change logs of the base tables, in the same order as BaseTableIDs. They're only
+	// set for a fast refresh view.
+	LogTableIDs []int64 `json:"log_table_ids,omitempty"`
+	// RefreshEvent is the name of the event in the same schema which refreshes the view periodically, it's empty if
+	// the view is only refreshed on demand.
+	RefreshEvent CIStr `json:"refresh_event,omitempty"`
+}
+
+// Clone clones MaterializedViewInfo.
+func (m *MaterializedViewInfo) Clone() *MaterializedViewInfo {
+	nm := *m
+	nm.BaseTableIDs = append([]int64(nil), m.BaseTableIDs...)
+	nm.LogTableIDs = append([]int64(nil), m.LogTableIDs...)
+	return &nm
+}
+
+// The reserved columns of a change log table, the recorded columns of the base table follow them. The column IDs
+// of a change log table are their offsets plus one.
+const (
+	// MViewLogTSColName is the start ts of the transaction that changed the row.
+	MViewLogTSColName = "_tidb_mlog_ts"
+	// MViewLogHandleColName is the handle of the changed row.
+	MViewLogHandleColName = "_tidb_mlog_handle"
+	// MViewLogSignColName is 1 for the row image after the change and -1 for the image before the change.
+	MViewLogSignColName = "_tidb_mlog_sign"
+	// MViewLogReservedCols is the number of the reserved columns.
+	MViewLogReservedCols = 3
+)
+
+// MViewLogInfo describes a change log of a table. The changed rows are recorded in the log table when they're
+// written, and removed from the log when the materialized view is refreshed.
+type MViewLogInfo struct {
+	MViewID    int64 `json:"mview_id"`
+	LogTableID int64 `json:"log_table_id"`
+	// Columns are the IDs of the recorded columns.
+	Columns []int64 `json:"columns"`
+}
+
+// Clone clones MViewLogInfo.
+func (l *MViewLogInfo) Clone() *MViewLogInfo {
+	nl := *l
+	nl.Columns = append([]int64(nil), l.Columns...)
+	return &nl
+}
+
 // ViewInfo provides meta data describing a DB view.
 //
 //revive:disable:exported
@@ -1184,6 +1367,31 @@ type ExchangePartitionInfo struct {
 	ExchangePartitionDefID   int64 `json:"exchange_partition_def_id"`
 	// Deprecated, not used
 	XXXExchangePartitionFlag bool `json:"exchange_partition_flag"`
+	// RejectWrites is set when the partitioned table has global indexes.
+	// The global indexes are rebuilt from the rows of both tables during the exchange,
+	// so neither the non-partitioned table nor the exchanged partition accepts writes until it is done.
+	RejectWrites bool `json:"reject_writes"`
+}
+
+// PrimaryKeyReorgInfo provides the info of changing the primary key of a table. The rows are
+// rewritten into another table ID with the new row key layout, all the writes to the table are
+// also applied to that table until the change is done.
+type PrimaryKeyReorgInfo struct {
+	// Table is the table the writes are applied to. It's the table with the new primary key
+	// before the switch, and the table with the old primary key after it.
+	Table *TableInfo `json:"table"`
+	// TempIndexID is the ID of the temporary unique index on the columns of the new primary key.
+	// It's built in the table with the old primary key to check the uniqueness of the new key.
+	TempIndexID int64 `json:"temp_index_id"`
+}
+
+// Clone clones PrimaryKeyReorgInfo.
+func (pi *PrimaryKeyReorgInfo) Clone() *PrimaryKeyReorgInfo {
+	npi := *pi
+	if pi.Table != nil {
+		npi.Table = pi.Table.Clone()
+	}
+	return &npi
 }
 
 // PartitionInfo provides table partition info.
@@ -1223,6 +1431,31 @@ type PartitionInfo struct {
 	DDLType    PartitionType `json:"ddl_type"`
 	DDLExpr    string        `json:"ddl_expr"`
 	DDLColumns []CIStr       `json:"ddl_columns"`
+	// DDLChangedIndex is set when the global indexes are rebuilt during
+	// REORGANIZE PARTITION or EXCHANGE PARTITION, maps from the index ID to
+	// true if it is the new index and false if it is the replaced one.
+	DDLChangedIndex map[int64]bool `json:"ddl_changed_index"`
+	// Sub is set if every partition is further divided into subpartitions.
+	// Definitions then holds the subpartitions, Sub.Num consecutive ones for
+	// each partition, see TopLevelDefinitions.
+	Sub *SubPartitionInfo `json:"sub"`
+}
+
+// SubPartitionInfo provides subpartition info.
+type SubPartitionInfo struct {
+	Type    PartitionType `json:"type"`
+	Expr    string        `json:"expr"`
+	Columns []CIStr       `json:"columns"`
+	// Num is the number of subpartitions in each partition.
+	Num uint64 `json:"num"`
+}
+
+// Clone clones itself.
+func (si *SubPartitionInfo) Clone() *SubPartitionInfo {
+	newSi := *si
+	newSi.Columns = make([]CIStr, len(si.Columns))
+	copy(newSi.Columns, si.Columns)
+	return &newSi
 }
 
 // Clone clones itself.
@@ -1246,6 +1479,17 @@ func (pi *PartitionInfo) Clone() *PartitionInfo {
 		newPi.DroppingDefinitions[i] = pi.DroppingDefinitions[i].Clone()
 	}
 
+	if pi.DDLChangedIndex != nil {
+		newPi.DDLChangedIndex = make(map[int64]bool, len(pi.DDLChangedIndex))
+		for id, isNew := range pi.DDLChangedIndex {
+			newPi.DDLChangedIndex[id] = isNew
+		}
+	}
+
+	if pi.Sub != nil {
+		newPi.Sub = pi.Sub.Clone()
+	}
+
 	return &newPi
 }
 
@@ -1263,6 +1507,50 @@ func (pi *PartitionInfo) GetNameByID(id int64) string {
 	return ""
 }
 
+// TopLevelDefinitions returns the partition definitions of a subpartitioned
+// table, one for each group of subpartitions in defs, named after the
+// partition and without an ID. The bounds are shared with the first
+// subpartition of each group. If the table has no subpartitions, defs is
+// returned as is.
+func (pi *PartitionInfo) TopLevelDefinitions(defs []PartitionDefinition) []PartitionDefinition {
+	if pi.Sub == nil || pi.Sub.Num == 0 {
+		return defs
+	}
+	num := int(pi.Sub.Num)
+	topDefs := make([]PartitionDefinition, 0, len(defs)/num)
+	for i := 0; i < len(defs); i += num {
+		def := defs[i]
+		def.ID = 0
+		def.Name = def.ParentName
+		def.ParentName = CIStr{}
+		topDefs = append(topDefs, def)
+	}
+	return topDefs
+}
+
+// ExpandPartitionNames replaces the partition names of a subpartitioned table
+// with the names of their subpartitions. Subpartition names and unknown names
+// are kept as is.
+func (pi *PartitionInfo) ExpandPartitionNames(names []CIStr) []CIStr {
+	if pi.Sub == nil || len(names) == 0 {
+		return names
+	}
+	res := make([]CIStr, 0, len(names))
+	for _, name := range names {
+		found := false
+		for i := range pi.Definitions {
+			if pi.Definitions[i].ParentName.L == name.L {
+				res = append(res, pi.Definitions[i].Name)
+				found = true
+			}
+		}
+		if !found {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
 // GetStateByID gets the partition state by ID.
 func (pi *PartitionInfo) GetStateByID(id int64) SchemaState {
 	for _, pstate := range pi.States {
@@ -1325,6 +1613,7 @@ func (pi *PartitionInfo) ClearReorgIntermediateInfo() {
 	pi.DDLExpr = ""
 	pi.DDLColumns = nil
 	pi.NewTableID = 0
+	pi.DDLChangedIndex = nil
 }
 
 // PartitionState is the state of the partition.
@@ -1341,6 +1630,9 @@ type PartitionDefinition struct {
 	InValues           [][]string     `json:"in_values"`
 	PlacementPolicyRef *PolicyRefInfo `json:"policy_ref_info"`
 	Comment            string         `json:"comment,omitempty"`
+	// ParentName is the name of the partition this subpartition belongs to,
+	// only set if the table is subpartitioned.
+	ParentName CIStr `json:"parent_name"`
 }
 
 // Clone clones ConstraintInfo.
@@ -1359,7 +1651,7 @@ func (ci *PartitionDefinition) MemoryUsage() (sum int64) {
 		return
 	}
 
-	sum = emptyPartitionDefinitionSize + ci.Name.MemoryUsage()
+	sum = emptyPartitionDefinitionSize + ci.Name.MemoryUsage() + ci.ParentName.MemoryUsage()
 	if ci.PlacementPolicyRef != nil {
 		sum += int64(unsafe.Sizeof(ci.PlacementPolicyRef.ID)) + ci.PlacementPolicyRef.Name.MemoryUsage()
 	}
@@ -1452,6 +1744,10 @@ func (t IndexType) String() string {
 		return "RTREE"
 	case IndexTypeHypo:
 		return "HYPO"
+	case IndexTypeFullText:
+		return "FULLTEXT"
+	case IndexTypeIVF:
+		return "IVF"
 	default:
 		return ""
 	}
@@ -1464,6 +1760,78 @@ const (
 	IndexTypeHash
 	IndexTypeRtree
 	IndexTypeHypo
+	IndexTypeFullText
+	IndexTypeIVF
+)
+
+// FullTextParserType is the parser which splits the text of a FULLTEXT index into tokens.
+type FullTextParserType string
+
+// FullTextParserTypes
+const (
+	// FullTextParserTypeStandard splits the text into words by whitespaces and punctuations.
+	FullTextParserTypeStandard FullTextParserType = "standard"
+	// FullTextParserTypeNgram splits the words into n-character tokens, which is useful for CJK text.
+	FullTextParserTypeNgram FullTextParserType = "ngram"
+)
+
+// DefaultNgramSize is the default number of characters of the tokens produced by the ngram parser.
+const DefaultNgramSize = 2
+
+// FullTextIndexInfo is the information of a FULLTEXT index.
+type FullTextIndexInfo struct {
+	ParserType FullTextParserType `json:"parser_type"`
+	// NgramSize is the number of characters of the tokens produced by the ngram parser.
+	NgramSize int `json:"ngram_size,omitempty"`
+}
+
+// DistanceMetric is the distance metric used by a vector index.
+type DistanceMetric string
+
+// DistanceMetrics
+const (
+	DistanceMetricL2     DistanceMetric = "L2"
+	DistanceMetricCosine DistanceMetric = "COSINE"
+)
+
+// DistanceMetric4VectorIndex maps the distance functions to the distance metrics supported by vector indexes.
+var DistanceMetric4VectorIndex = map[string]DistanceMetric{
+	"vec_l2_distance":     DistanceMetricL2,
+	"vec_cosine_distance": DistanceMetricCosine,
+}
+
+// DistanceFunction returns the name of the distance function of the metric, which is the key part of a vector index.
+func (m DistanceMetric) DistanceFunction() string {
+	for fn, metric := range DistanceMetric4VectorIndex {
+		if metric == m {
+			return fn
+		}
+	}
+	return ""
+}
+
+// VectorIndexInfo is the information of a vector index, which is an IVF (inverted file) index. The rows are clustered
+// into lists by their nearest centroids, and the index entries are keyed by the lists, so a search only scans the
+// lists whose centroids are the nearest to the searched vector.
+type VectorIndexInfo struct {
+	Dimension      int            `json:"dimension"`
+	DistanceMetric DistanceMetric `json:"distance_metric"`
+	// Centroids are trained with the rows in the table after the index is added, or later by ANALYZE TABLE if there
+	// are too few rows then. The rows indexed before the centroids are trained are in the untrained list, and are
+	// moved to the lists of their centroids by the reorganization after training.
+	Centroids [][]float32 `json:"centroids,omitempty"`
+	// PendingCentroids indicates the centroids are trained but the rows are still indexed in the untrained list, so
+	// the instances which don't know the centroids yet delete the same index entries as the others.
+	PendingCentroids bool `json:"pending_centroids,omitempty"`
+}
+
+const (
+	// GlobalIndexVersionLegacy is the version of the global indexes created before the partition ID is encoded in
+	// the key, the handles in their non-unique keys are assumed to be unique across partitions.
+	GlobalIndexVersionLegacy = 0
+	// GlobalIndexVersionV1 encodes the partition ID before the handle in the non-unique keys of a global index,
+	// since a handle is only unique in its partition, e.g. after EXCHANGE PARTITION.
+	GlobalIndexVersionV1 = 1
 )
 
 // IndexInfo provides meta data describing a DB index.
@@ -1483,6 +1851,12 @@ type IndexInfo struct {
 	Invisible     bool           `json:"is_invisible"` // Whether the index is invisible.
 	Global        bool           `json:"is_global"`    // Whether the index is global.
 	MVIndex       bool           `json:"mv_index"`     // Whether the index is multivalued index.
+	// GlobalIndexVersion is the encoding version of a global index, see GlobalIndexVersionV1.
+	GlobalIndexVersion uint8 `json:"global_index_version,omitempty"`
+	// FullTextInfo is not nil if the index is a FULLTEXT index, whose entries are the tokens of the indexed text.
+	FullTextInfo *FullTextIndexInfo `json:"full_text_info,omitempty"`
+	// VectorInfo is not nil if the index is a vector index, whose entries are the lists of the indexed vectors.
+	VectorInfo *VectorIndexInfo `json:"vector_info,omitempty"`
 }
 
 // Clone clones IndexInfo.
@@ -1495,6 +1869,15 @@ func (index *IndexInfo) Clone() *IndexInfo {
 	for i := range index.Columns {
 		ni.Columns[i] = index.Columns[i].Clone()
 	}
+	if index.FullTextInfo != nil {
+		fullTextInfo := *index.FullTextInfo
+		ni.FullTextInfo = &fullTextInfo
+	}
+	if index.VectorInfo != nil {
+		// The centroids are never modified after they are trained, so they are shared.
+		vectorInfo := *index.VectorInfo
+		ni.VectorInfo = &vectorInfo
+	}
 	return &ni
 }
 
@@ -1571,6 +1954,17 @@ func (t *TableInfo) FindConstraintInfoByName(constrName string) *ConstraintInfo
 	return nil
 }
 
+// FindTriggerByName finds the trigger by name.
+func (t *TableInfo) FindTriggerByName(name string) *TriggerInfo {
+	lowName := strings.ToLower(name)
+	for _, trigger := range t.Triggers {
+		if trigger.Name.L == lowName {
+			return trigger
+		}
+	}
+	return nil
+}
+
 // FindIndexNameByID finds index name by id.
 func (t *TableInfo) FindIndexNameByID(id int64) string {
 	indexInfo := FindIndexInfoByID(t.Indices, id)
@@ -1852,6 +2246,17 @@ type TTLInfo struct {
 	// JobInterval is the interval between two TTL scan jobs.
 	// It's suggested to get a duration with `(*TTLInfo).GetJobInterval`
 	JobInterval string `json:"job_interval"`
+	// Expr is the time expression used instead of `ColumnName` if it's not empty.
+	Expr string `json:"expr"`
+	// EpochUnit is the unit of an integer time column or expression, which is one of "SECOND", "MILLISECOND" and
+	// "MICROSECOND". It's empty for DATETIME, DATE and TIMESTAMP.
+	EpochUnit string `json:"epoch_unit"`
+	// Archive is the URL of the external storage to which the expired rows are exported before they are deleted.
+	// The expired rows are deleted without archiving if it's empty. The credentials are removed from the URL before
+	// it's stored, so the files are written with the default credentials of the TiDB servers.
+	Archive string `json:"archive"`
+	// ArchiveFormat is the format of the archive files, which is "CSV" or "PARQUET". It's "CSV" if it's empty.
+	ArchiveFormat string `json:"archive_format,omitempty"`
 }
 
 // Clone clones TTLInfo
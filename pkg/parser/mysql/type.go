@@ -34,6 +34,9 @@ const (
 	TypeVarchar  byte = 15
 	TypeBit      byte = 16
 
+	// TypeTiDBVectorFloat32 is the type of VECTOR columns, which isn't a MySQL type.
+	TypeTiDBVectorFloat32 byte = 0xe1
+
 	TypeJSON       byte = 0xf5
 	TypeNewDecimal byte = 0xf6
 	TypeEnum       byte = 0xf7
@@ -322,6 +322,7 @@ import (
 	always                "ALWAYS"
 	any                   "ANY"
 	ascii                 "ASCII"
+	at                    "AT"
 	attribute             "ATTRIBUTE"
 	attributes            "ATTRIBUTES"
 	autoIdCache           "AUTO_ID_CACHE"
@@ -334,6 +335,7 @@ import (
 	backup                "BACKUP"
 	backups               "BACKUPS"
 	bdr                   "BDR"
+	before                "BEFORE"
 	begin                 "BEGIN"
 	bernoulli             "BERNOULLI"
 	binding               "BINDING"
@@ -370,6 +372,8 @@ import (
 	commit                "COMMIT"
 	committed             "COMMITTED"
 	compact               "COMPACT"
+	complete              "COMPLETE"
+	completion            "COMPLETION"
 	compressed            "COMPRESSED"
 	compression           "COMPRESSION"
 	concurrency           "CONCURRENCY"
@@ -405,10 +409,12 @@ import (
 	do                    "DO"
 	duplicate             "DUPLICATE"
 	dynamic               "DYNAMIC"
+	each                  "EACH"
 	enable                "ENABLE"
 	enabled               "ENABLED"
 	encryption            "ENCRYPTION"
 	end                   "END"
+	ends                  "ENDS"
 	enforced              "ENFORCED"
 	engine                "ENGINE"
 	engines               "ENGINES"
@@ -418,6 +424,7 @@ import (
 	escape                "ESCAPE"
 	event                 "EVENT"
 	events                "EVENTS"
+	every                 "EVERY"
 	evolve                "EVOLVE"
 	exchange              "EXCHANGE"
 	exclusive             "EXCLUSIVE"
@@ -426,18 +433,24 @@ import (
 	expire                "EXPIRE"
 	extended              "EXTENDED"
 	failedLoginAttempts   "FAILED_LOGIN_ATTEMPTS"
+	fast                  "FAST"
 	faultsSym             "FAULTS"
+	feedback              "FEEDBACK"
 	fields                "FIELDS"
 	file                  "FILE"
 	first                 "FIRST"
 	fixed                 "FIXED"
 	flush                 "FLUSH"
 	following             "FOLLOWING"
+	follows               "FOLLOWS"
 	format                "FORMAT"
 	found                 "FOUND"
 	full                  "FULL"
 	function              "FUNCTION"
 	general               "GENERAL"
+	geomCollection        "GEOMCOLLECTION"
+	geometry              "GEOMETRY"
+	geometryCollection    "GEOMETRYCOLLECTION"
 	global                "GLOBAL"
 	grants                "GRANTS"
 	handler               "HANDLER"
@@ -471,12 +484,14 @@ import (
 	lastBackup            "LAST_BACKUP"
 	less                  "LESS"
 	level                 "LEVEL"
+	lineString            "LINESTRING"
 	list                  "LIST"
 	local                 "LOCAL"
 	location              "LOCATION"
 	locked                "LOCKED"
 	logs                  "LOGS"
 	master                "MASTER"
+	materialized          "MATERIALIZED"
 	maxConnectionsPerHour "MAX_CONNECTIONS_PER_HOUR"
 	max_idxnum            "MAX_IDXNUM"
 	max_minutes           "MAX_MINUTES"
@@ -489,12 +504,16 @@ import (
 	memory                "MEMORY"
 	merge                 "MERGE"
 	microsecond           "MICROSECOND"
+	migrate               "MIGRATE"
 	minute                "MINUTE"
 	minValue              "MINVALUE"
 	minRows               "MIN_ROWS"
 	mode                  "MODE"
 	modify                "MODIFY"
 	month                 "MONTH"
+	multiLineString       "MULTILINESTRING"
+	multiPoint            "MULTIPOINT"
+	multiPolygon          "MULTIPOLYGON"
 	names                 "NAMES"
 	national              "NATIONAL"
 	ncharType             "NCHAR"
@@ -517,6 +536,7 @@ import (
 	oltpReadOnly          "OLTP_READ_ONLY"
 	oltpReadWrite         "OLTP_READ_WRITE"
 	oltpWriteOnly         "OLTP_WRITE_ONLY"
+	one                   "ONE"
 	online                "ONLINE"
 	only                  "ONLY"
 	onDuplicate           "ON_DUPLICATE"
@@ -534,10 +554,13 @@ import (
 	percent               "PERCENT"
 	per_db                "PER_DB"
 	per_table             "PER_TABLE"
+	phase                 "PHASE"
 	pipesAsOr
 	plugins               "PLUGINS"
 	point                 "POINT"
 	policy                "POLICY"
+	polygon               "POLYGON"
+	precedes              "PRECEDES"
 	preceding             "PRECEDING"
 	prepare               "PREPARE"
 	preserve              "PRESERVE"
@@ -557,6 +580,7 @@ import (
 	rebuild               "REBUILD"
 	recover               "RECOVER"
 	redundant             "REDUNDANT"
+	refresh               "REFRESH"
 	reload                "RELOAD"
 	remove                "REMOVE"
 	reorganize            "REORGANIZE"
@@ -621,6 +645,7 @@ import (
 	sqlTsiWeek            "SQL_TSI_WEEK"
 	sqlTsiYear            "SQL_TSI_YEAR"
 	start                 "START"
+	starts                "STARTS"
 	statsAutoRecalc       "STATS_AUTO_RECALC"
 	statsColChoice        "STATS_COL_CHOICE"
 	statsColList          "STATS_COL_LIST"
@@ -635,6 +660,7 @@ import (
 	subpartition          "SUBPARTITION"
 	subpartitions         "SUBPARTITIONS"
 	super                 "SUPER"
+	suspend               "SUSPEND"
 	swaps                 "SWAPS"
 	switchesSym           "SWITCHES"
 	system                "SYSTEM"
@@ -659,7 +685,10 @@ import (
 	truncate              "TRUNCATE"
 	tsoType               "TSO"
 	ttl                   "TTL"
+	ttlArchive            "TTL_ARCHIVE"
+	ttlArchiveFormat      "TTL_ARCHIVE_FORMAT"
 	ttlEnable             "TTL_ENABLE"
+	ttlEpochUnit          "TTL_EPOCH_UNIT"
 	ttlJobInterval        "TTL_JOB_INTERVAL"
 	tp                    "TYPE"
 	unbounded             "UNBOUNDED"
@@ -672,6 +701,7 @@ import (
 	validation            "VALIDATION"
 	value                 "VALUE"
 	variables             "VARIABLES"
+	vectorType            "VECTOR"
 	view                  "VIEW"
 	visible               "VISIBLE"
 	wait                  "WAIT"
@@ -681,6 +711,8 @@ import (
 	without               "WITHOUT"
 	workload              "WORKLOAD"
 	x509                  "X509"
+	xa                    "XA"
+	xid                   "XID"
 	yearType              "YEAR"
 
 	/* The following tokens belong to NotKeywordToken. Notice: make sure these tokens are contained in NotKeywordToken. */
@@ -906,6 +938,8 @@ import (
 %token not2
 %type	<expr>
 	Expression                      "expression"
+	EventStartsOpt                  "Optional event start time"
+	EventEndsOpt                    "Optional event end time"
 	MaxValueOrExpression            "maxvalue or expression"
 	DefaultOrExpression             "default or expression"
 	BoolPri                         "boolean primary expression"
@@ -963,6 +997,10 @@ import (
 	CreateBindingStmt          "CREATE BINDING statement"
 	CreatePolicyStmt           "CREATE PLACEMENT POLICY statement"
 	CreateProcedureStmt        "CREATE PROCEDURE statement"
+	CreateTriggerStmt          "CREATE TRIGGER statement"
+	CreateEventStmt            "CREATE EVENT statement"
+	AlterEventStmt             "ALTER EVENT statement"
+	CreateMaterializedViewStmt "CREATE MATERIALIZED VIEW statement"
 	AddQueryWatchStmt          "ADD QUERY WATCH statement"
 	CreateResourceGroupStmt    "CREATE RESOURCE GROUP statement"
 	CreateSequenceStmt         "CREATE SEQUENCE statement"
@@ -971,6 +1009,9 @@ import (
 	DropDatabaseStmt           "DROP DATABASE statement"
 	DropIndexStmt              "DROP INDEX statement"
 	DropProcedureStmt          "DROP PROCEDURE statement"
+	DropTriggerStmt            "DROP TRIGGER statement"
+	DropEventStmt              "DROP EVENT statement"
+	DropMaterializedViewStmt   "DROP MATERIALIZED VIEW statement"
 	DropQueryWatchStmt         "DROP QUERY WATCH statement"
 	DropResourceGroupStmt      "DROP RESOURCE GROUP statement"
 	DropStatisticsStmt         "DROP STATISTICS statement"
@@ -1014,12 +1055,14 @@ import (
 	PreparedStmt               "PreparedStmt"
 	ProcedureProcStmt          "The entrance of procedure statements which contains all kinds of statements in procedure"
 	ProcedureStatementStmt     "The normal statements in procedure, such as dml, select, set ..."
+	EventBody                  "Event body"
 	SelectStmt                 "SELECT statement"
 	SelectStmtWithClause       "common table expression SELECT statement"
 	RenameTableStmt            "rename table statement"
 	RenameUserStmt             "rename user statement"
 	ReplaceIntoStmt            "REPLACE INTO statement"
 	RecoverTableStmt           "recover table statement"
+	RefreshMViewStmt           "REFRESH MATERIALIZED VIEW statement"
 	RevokeStmt                 "Revoke statement"
 	RevokeRoleStmt             "Revoke role statement"
 	RollbackStmt               "ROLLBACK statement"
@@ -1050,6 +1093,7 @@ import (
 	HelpStmt                   "HELP statement"
 	ShardableStmt              "Shardable statement that can be used in non-transactional DMLs"
 	CancelImportStmt           "CANCEL IMPORT JOB statement"
+	XAStmt                     "XA statement"
 	ProcedureUnlabeledBlock    "The statement block without label in procedure"
 	ProcedureBlockContent      "The statement block in procedure expressed with 'Begin ... End'"
 	SimpleWhenThen             "Procedure case when then"
@@ -1123,6 +1167,9 @@ import (
 	ConnectionOptionList                   "connection options for CREATE USER statement"
 	ConnectionOptions                      "optional connection options for CREATE USER statement"
 	Constraint                             "table constraint"
+	VectorIndexConstraint                  "vector index constraint"
+	AddColumnKeyword                       "COLUMN keyword or IF NOT EXISTS of ADD COLUMN"
+	AddColumnKeywordOpt                    "COLUMN keyword or IF NOT EXISTS of ADD COLUMN or empty"
 	ConstraintElem                         "table constraint element"
 	ConstraintKeywordOpt                   "Constraint Keyword or empty"
 	CreateSequenceOptionListOpt            "create sequence list opt"
@@ -1281,6 +1328,7 @@ import (
 	RowFormat                              "Row format option"
 	RowValue                               "Row value"
 	RowStmt                                "Row constructor"
+	SelectIntoOptionListOpt                "SELECT INTO OUTFILE option list"
 	SelectLockOpt                          "SELECT lock options"
 	SelectStmtSQLCache                     "SELECT statement optional SQL_CAHCE/SQL_NO_CACHE"
 	SelectStmtFieldList                    "SELECT statement field list"
@@ -1413,6 +1461,11 @@ import (
 	WindowSpec                             "WINDOW spec"
 	WindowSpecDetails                      "WINDOW spec details"
 	WithRollupClause                       "With rollup clause"
+	XAID                                   "XA transaction branch identifier"
+	XACommitOptionOpt                      "XA COMMIT option"
+	XAEndOptionOpt                         "XA END option"
+	XARecoverOptionOpt                     "XA RECOVER option"
+	XAStartOptionOpt                       "XA START option"
 	BetweenOrNotOp                         "Between predicate"
 	IsOrNotOp                              "Is predicate"
 	InOrNotOp                              "In predicate"
@@ -1429,6 +1482,9 @@ import (
 	BlobType                               "Blob types"
 	TextType                               "Text types"
 	DateAndTimeType                        "Date and Time types"
+	SpatialType                            "Spatial types"
+	VectorType                             "Vector types"
+	SpatialTypeName                        "Spatial type name"
 	OptFieldLen                            "Field length or empty"
 	FieldLen                               "Field length"
 	FieldOpts                              "Field type definition option list"
@@ -1451,6 +1507,7 @@ import (
 	EnforcedOrNotOrNotNullOpt              "{[ENFORCED|NOT ENFORCED|NOT NULL]}"
 	Match                                  "[MATCH FULL | MATCH PARTIAL | MATCH SIMPLE]"
 	MatchOpt                               "optional MATCH clause"
+	IndexAdviseLimitOpt                    "LIMIT clause of index advise"
 	MaxMinutesOpt                          "MAX_MINUTES num(int)"
 	MaxIndexNumOpt                         "MAX_IDXNUM clause"
 	PerTable                               "Max index number PER_TABLE"
@@ -1505,6 +1562,21 @@ import (
 	ProcedureFetchList                     "Procedure fetch into variables"
 	ProcedureHandlerType                   "Procedure handler operation type"
 	ProcedureHcondList                     "Procedure handler condition value list"
+	ProcedureCharacteristicListOpt         "Optional procedure characteristic list"
+	TriggerTiming                          "Trigger action time"
+	TriggerEvent                           "Trigger event"
+	TriggerOrderOpt                        "Optional trigger order"
+	EventSchedule                          "Event schedule"
+	EventCompletionOpt                     "Optional event ON COMPLETION clause"
+	EventStatusOpt                         "Optional event ENABLE or DISABLE clause"
+	AlterEventScheduleOpt                  "Optional ON SCHEDULE and ON COMPLETION clauses of ALTER EVENT"
+	AlterEventRenameOpt                    "Optional RENAME TO clause of ALTER EVENT"
+	EventCommentOpt                        "Optional event COMMENT clause"
+	MViewRefreshOpt                        "Optional REFRESH clause of CREATE MATERIALIZED VIEW"
+	MViewRefreshMethod                     "Materialized view refresh method"
+	MViewScheduleOpt                       "Optional ON SCHEDULE clause of materialized view refresh"
+	RefreshCompleteOpt                     "Optional COMPLETE of REFRESH MATERIALIZED VIEW"
+	AlterEventBodyOpt                      "Optional DO clause of ALTER EVENT"
 
 %type	<ident>
 	AsOpt             "AS or EmptyString"
@@ -2166,18 +2238,26 @@ AlterTableSpec:
 		}
 		$$ = op
 	}
-|	"ADD" ColumnKeywordOpt IfNotExists ColumnDef ColumnPosition
+|	"ADD" ColumnDef ColumnPosition
 	{
 		$$ = &ast.AlterTableSpec{
-			IfNotExists: $3.(bool),
+			Tp:         ast.AlterTableAddColumns,
+			NewColumns: []*ast.ColumnDef{$2.(*ast.ColumnDef)},
+			Position:   $3.(*ast.ColumnPosition),
+		}
+	}
+|	"ADD" AddColumnKeyword ColumnDef ColumnPosition
+	{
+		$$ = &ast.AlterTableSpec{
+			IfNotExists: $2.(bool),
 			Tp:          ast.AlterTableAddColumns,
-			NewColumns:  []*ast.ColumnDef{$4.(*ast.ColumnDef)},
-			Position:    $5.(*ast.ColumnPosition),
+			NewColumns:  []*ast.ColumnDef{$3.(*ast.ColumnDef)},
+			Position:    $4.(*ast.ColumnPosition),
 		}
 	}
-|	"ADD" ColumnKeywordOpt IfNotExists '(' TableElementList ')'
+|	"ADD" AddColumnKeywordOpt '(' TableElementList ')'
 	{
-		tes := $5.([]interface{})
+		tes := $4.([]interface{})
 		var columnDefs []*ast.ColumnDef
 		var constraints []*ast.Constraint
 		for _, te := range tes {
@@ -2189,7 +2269,7 @@ AlterTableSpec:
 			}
 		}
 		$$ = &ast.AlterTableSpec{
-			IfNotExists:    $3.(bool),
+			IfNotExists:    $2.(bool),
 			Tp:             ast.AlterTableAddColumns,
 			NewColumns:     columnDefs,
 			NewConstraints: constraints,
@@ -2203,6 +2283,13 @@ AlterTableSpec:
 			Constraint: constraint,
 		}
 	}
+|	"ADD" VectorIndexConstraint
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp:         ast.AlterTableAddConstraint,
+			Constraint: $2.(*ast.Constraint),
+		}
+	}
 |	"ADD" "PARTITION" IfNotExists NoWriteToBinLogAliasOpt PartitionDefinitionListOpt
 	{
 		var defs []*ast.PartitionDefinition
@@ -2812,6 +2899,26 @@ ColumnKeywordOpt:
 	{}
 |	"COLUMN"
 
+/*
+ * The optional COLUMN keyword and IF NOT EXISTS of ADD COLUMN are not empty here, otherwise they conflict with
+ * ADD VECTOR INDEX.
+ */
+AddColumnKeyword:
+	"COLUMN" IfNotExists
+	{
+		$$ = $2
+	}
+|	"IF" NotSym "EXISTS"
+	{
+		$$ = true
+	}
+
+AddColumnKeywordOpt:
+	{
+		$$ = false
+	}
+|	AddColumnKeyword
+
 ColumnPosition:
 	{
 		$$ = &ast.ColumnPosition{Tp: ast.ColumnPositionNone}
@@ -4239,6 +4346,10 @@ IndexKeyTypeOpt:
 	{
 		$$ = ast.IndexKeyTypeFullText
 	}
+|	"VECTOR"
+	{
+		$$ = ast.IndexKeyTypeVector
+	}
 
 /**************************************AlterDatabaseStmt***************************************
  * See https://dev.mysql.com/doc/refman/5.7/en/alter-database.html
@@ -6410,6 +6521,8 @@ IndexOptionList:
 				opt1.Visibility = opt2.Visibility
 			} else if opt2.PrimaryKeyTp != model.PrimaryKeyTypeDefault {
 				opt1.PrimaryKeyTp = opt2.PrimaryKeyTp
+			} else if opt2.Scope != ast.IndexScopeDefault {
+				opt1.Scope = opt2.Scope
 			}
 			$$ = opt1
 		}
@@ -6454,6 +6567,18 @@ IndexOption:
 			PrimaryKeyTp: $1.(model.PrimaryKeyType),
 		}
 	}
+|	"GLOBAL"
+	{
+		$$ = &ast.IndexOption{
+			Scope: ast.IndexScopeGlobal,
+		}
+	}
+|	"LOCAL"
+	{
+		$$ = &ast.IndexOption{
+			Scope: ast.IndexScopeLocal,
+		}
+	}
 
 /*
   See: https://github.com/mysql/mysql-server/blob/8.0/sql/sql_yacc.yy#L7179
@@ -6543,6 +6668,7 @@ UnReservedKeyword:
 	"ACTION"
 |	"ADVISE"
 |	"ASCII"
+|	"AT"
 |	"ATTRIBUTE"
 |	"ATTRIBUTES"
 |	"BINDING_CACHE"
@@ -6556,6 +6682,7 @@ UnReservedKeyword:
 |	"ALWAYS"
 |	"AVG"
 |	"BDR"
+|	"BEFORE"
 |	"BEGIN"
 |	"BIT"
 |	"BOOL"
@@ -6574,6 +6701,8 @@ UnReservedKeyword:
 |	"COMMIT"
 |	"COMPACT"
 |	"COMPRESSED"
+|	"COMPLETE"
+|	"COMPLETION"
 |	"CONSISTENCY"
 |	"CONSISTENT"
 |	"CURRENT"
@@ -6585,8 +6714,10 @@ UnReservedKeyword:
 |	"DO"
 |	"DUPLICATE"
 |	"DYNAMIC"
+|	"EACH"
 |	"ENCRYPTION"
 |	"END"
+|	"ENDS"
 |	"ENFORCED"
 |	"ENGINE"
 |	"ENGINES"
@@ -6603,6 +6734,7 @@ UnReservedKeyword:
 |	"FIXED"
 |	"FLUSH"
 |	"FOLLOWING"
+|	"FOLLOWS"
 |	"FORMAT"
 |	"FULL"
 |	"GENERAL"
@@ -6625,6 +6757,7 @@ UnReservedKeyword:
 |	"PROXY"
 |	"QUICK"
 |	"REBUILD"
+|	"REFRESH"
 |	"REDUNDANT"
 |	"REORGANIZE"
 |	"RESOURCE"
@@ -6638,9 +6771,18 @@ UnReservedKeyword:
 |	"SHUTDOWN"
 |	"SNAPSHOT"
 |	"START"
+|	"STARTS"
 |	"STATUS"
 |	"OPEN"
 |	"POINT"
+|	"GEOMETRY"
+|	"LINESTRING"
+|	"POLYGON"
+|	"MULTIPOINT"
+|	"MULTILINESTRING"
+|	"MULTIPOLYGON"
+|	"GEOMETRYCOLLECTION"
+|	"GEOMCOLLECTION"
 |	"SUBPARTITIONS"
 |	"SUBPARTITION"
 |	"TABLES"
@@ -6674,6 +6816,7 @@ UnReservedKeyword:
 |	"COMPRESSION"
 |	"KEY_BLOCK_SIZE"
 |	"MASTER"
+|	"MATERIALIZED"
 |	"MAX_ROWS"
 |	"MIN_ROWS"
 |	"NATIONAL"
@@ -6712,6 +6855,7 @@ UnReservedKeyword:
 |	"BINDINGS"
 |	"MODIFY"
 |	"EVENTS"
+|	"EVERY"
 |	"PARTITIONS"
 |	"NONE"
 |	"NULLS"
@@ -6728,6 +6872,7 @@ UnReservedKeyword:
 |	"MICROSECOND"
 |	"MINUTE"
 |	"PLUGINS"
+|	"PRECEDES"
 |	"PRECEDING"
 |	"QUERY"
 |	"QUERIES"
@@ -6773,6 +6918,7 @@ UnReservedKeyword:
 |	"CONTEXT"
 |	"SWITCHES"
 |	"PAGE"
+|	"FAST"
 |	"FAULTS"
 |	"IPC"
 |	"SWAPS"
@@ -6821,6 +6967,7 @@ UnReservedKeyword:
 |	"SQL_TSI_YEAR"
 |	"INVISIBLE"
 |	"VISIBLE"
+|	"VECTOR"
 |	"TYPE"
 |	"NOWAIT"
 |	"INSTANCE"
@@ -6893,7 +7040,10 @@ UnReservedKeyword:
 |	"PRESERVE"
 |	"TOKEN_ISSUER"
 |	"TTL"
+|	"TTL_ARCHIVE"
+|	"TTL_ARCHIVE_FORMAT"
 |	"TTL_ENABLE"
+|	"TTL_EPOCH_UNIT"
 |	"TTL_JOB_INTERVAL"
 |	"FAILED_LOGIN_ATTEMPTS"
 |	"PASSWORD_LOCK_TIME"
@@ -6909,6 +7059,13 @@ UnReservedKeyword:
 |	"OLTP_READ_ONLY"
 |	"OLTP_WRITE_ONLY"
 |	"TPCH_10"
+|	"XA"
+|	"XID"
+|	"ONE"
+|	"PHASE"
+|	"SUSPEND"
+|	"MIGRATE"
+|	"FEEDBACK"
 
 TiDBKeyword:
 	"ADMIN"
@@ -8263,9 +8420,13 @@ SumExpr:
 			$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}}
 		}
 	}
-|	builtinCount '(' DistinctKwd ExpressionList ')'
+|	builtinCount '(' DistinctKwd ExpressionList ')' OptWindowingClause
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: true}
+		if $6 != nil {
+			$$ = &ast.WindowFuncExpr{Name: $1, Args: $4.([]ast.ExprNode), Distinct: true, Spec: *($6.(*ast.WindowSpec))}
+		} else {
+			$$ = &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: true}
+		}
 	}
 |	builtinCount '(' "ALL" Expression ')' OptWindowingClause
 	{
@@ -8297,6 +8458,10 @@ SumExpr:
 		args := $4.([]ast.ExprNode)
 		args = append(args, $6.(ast.ExprNode))
 		if $8 != nil {
+			if $5 != nil {
+				yylex.AppendError(ErrWrongUsage.GenWithStackByArgs("ORDER BY in GROUP_CONCAT", "OVER"))
+				return 1
+			}
 			$$ = &ast.WindowFuncExpr{Name: $1, Args: args, Distinct: $3.(bool), Spec: *($8.(*ast.WindowSpec))}
 		} else {
 			agg := &ast.AggregateFuncExpr{F: $1, Args: args, Distinct: $3.(bool)}
@@ -8947,6 +9112,98 @@ RollbackStmt:
 		$$ = &ast.RollbackStmt{SavepointName: $4}
 	}
 
+XAStmt:
+	"XA" "START" XAID XAStartOptionOpt
+	{
+		$$ = &ast.XAStmt{Tp: ast.XAStart, XID: $3.(*ast.XID), Option: $4.(ast.XAOption)}
+	}
+|	"XA" "BEGIN" XAID XAStartOptionOpt
+	{
+		$$ = &ast.XAStmt{Tp: ast.XAStart, XID: $3.(*ast.XID), Option: $4.(ast.XAOption)}
+	}
+|	"XA" "END" XAID XAEndOptionOpt
+	{
+		$$ = &ast.XAStmt{Tp: ast.XAEnd, XID: $3.(*ast.XID), Option: $4.(ast.XAOption)}
+	}
+|	"XA" "PREPARE" XAID
+	{
+		$$ = &ast.XAStmt{Tp: ast.XAPrepare, XID: $3.(*ast.XID)}
+	}
+|	"XA" "COMMIT" XAID XACommitOptionOpt
+	{
+		$$ = &ast.XAStmt{Tp: ast.XACommit, XID: $3.(*ast.XID), Option: $4.(ast.XAOption)}
+	}
+|	"XA" "ROLLBACK" XAID
+	{
+		$$ = &ast.XAStmt{Tp: ast.XARollback, XID: $3.(*ast.XID)}
+	}
+|	"XA" "RECOVER" XARecoverOptionOpt
+	{
+		$$ = &ast.XAStmt{Tp: ast.XARecover, Option: $3.(ast.XAOption)}
+	}
+
+XAID:
+	TextString
+	{
+		$$ = &ast.XID{GTRID: $1.(*ast.TextString), FormatID: 1}
+	}
+|	TextString ',' TextString
+	{
+		$$ = &ast.XID{GTRID: $1.(*ast.TextString), BQUAL: $3.(*ast.TextString), FormatID: 1}
+	}
+|	TextString ',' TextString ',' LengthNum
+	{
+		$$ = &ast.XID{GTRID: $1.(*ast.TextString), BQUAL: $3.(*ast.TextString), FormatID: $5.(uint64)}
+	}
+
+XAStartOptionOpt:
+	/* EMPTY */
+	{
+		$$ = ast.XAOptionNone
+	}
+|	"JOIN"
+	{
+		$$ = ast.XAOptionJoin
+	}
+|	"RESUME"
+	{
+		$$ = ast.XAOptionResume
+	}
+
+XAEndOptionOpt:
+	/* EMPTY */
+	{
+		$$ = ast.XAOptionNone
+	}
+|	"SUSPEND"
+	{
+		$$ = ast.XAOptionSuspend
+	}
+|	"SUSPEND" "FOR" "MIGRATE"
+	{
+		$$ = ast.XAOptionSuspendForMigrate
+	}
+
+XACommitOptionOpt:
+	/* EMPTY */
+	{
+		$$ = ast.XAOptionNone
+	}
+|	"ONE" "PHASE"
+	{
+		$$ = ast.XAOptionOnePhase
+	}
+
+XARecoverOptionOpt:
+	/* EMPTY */
+	{
+		$$ = ast.XAOptionNone
+	}
+|	"CONVERT" "XID"
+	{
+		$$ = ast.XAOptionConvertXID
+	}
+
 CompletionTypeWithinTransaction:
 	"AND" "CHAIN" "NO" "RELEASE"
 	{
@@ -10049,22 +10306,34 @@ SelectStmtIntoOption:
 	{
 		$$ = nil
 	}
-|	"INTO" "OUTFILE" stringLit Fields Lines
+|	"INTO" "OUTFILE" stringLit FormatOpt Fields Lines SelectIntoOptionListOpt
 	{
 		x := &ast.SelectIntoOption{
 			Tp:       ast.SelectIntoOutfile,
 			FileName: $3,
-		}
-		if $4 != nil {
-			x.FieldsInfo = $4.(*ast.FieldsClause)
+			Format:   $4.(*string),
+			Options:  $7.([]*ast.LoadDataOpt),
 		}
 		if $5 != nil {
-			x.LinesInfo = $5.(*ast.LinesClause)
+			x.FieldsInfo = $5.(*ast.FieldsClause)
+		}
+		if $6 != nil {
+			x.LinesInfo = $6.(*ast.LinesClause)
 		}
 
 		$$ = x
 	}
 
+SelectIntoOptionListOpt:
+	%prec lowerThanWith
+	{
+		$$ = []*ast.LoadDataOpt{}
+	}
+|	"WITH" LoadDataOptionList
+	{
+		$$ = $2.([]*ast.LoadDataOpt)
+	}
+
 // See https://dev.mysql.com/doc/refman/5.7/en/subqueries.html
 SubSelect:
 	'(' SelectStmt ')'
@@ -11136,6 +11405,19 @@ AdminStmt:
 			Tp: ast.AdminReloadStatistics,
 		}
 	}
+|	"ADMIN" "RESET" "CARDINALITY" "FEEDBACK"
+	{
+		$$ = &ast.AdminStmt{
+			Tp: ast.AdminResetCardinalityFeedback,
+		}
+	}
+|	"ADMIN" "RESET" "CARDINALITY" "FEEDBACK" TableNameList
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminResetCardinalityFeedback,
+			Tables: $5.([]*ast.TableName),
+		}
+	}
 |	"ADMIN" "FLUSH" StatementScope "PLAN_CACHE"
 	{
 		$$ = &ast.AdminStmt{
@@ -11423,6 +11705,20 @@ ShowStmt:
 			Procedure: $4.(*ast.TableName),
 		}
 	}
+|	"SHOW" "CREATE" "TRIGGER" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:      ast.ShowCreateTrigger,
+			Trigger: $4.(*ast.TableName),
+		}
+	}
+|	"SHOW" "CREATE" "EVENT" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:    ast.ShowCreateEvent,
+			Event: $4.(*ast.TableName),
+		}
+	}
 
 ShowPlacementTarget:
 	DatabaseSym DBName
@@ -11978,6 +12274,7 @@ Statement:
 	EmptyStmt
 |	AdminStmt
 |	AlterDatabaseStmt
+|	AlterEventStmt
 |	AlterTableStmt
 |	AlterUserStmt
 |	AlterInstanceStmt
@@ -12005,6 +12302,9 @@ Statement:
 |	CreateBindingStmt
 |	CreatePolicyStmt
 |	CreateProcedureStmt
+|	CreateTriggerStmt
+|	CreateEventStmt
+|	CreateMaterializedViewStmt
 |	CreateResourceGroupStmt
 |	AddQueryWatchStmt
 |	CreateSequenceStmt
@@ -12014,6 +12314,9 @@ Statement:
 |	DropIndexStmt
 |	DropTableStmt
 |	DropProcedureStmt
+|	DropTriggerStmt
+|	DropEventStmt
+|	DropMaterializedViewStmt
 |	DropPolicyStmt
 |	DropSequenceStmt
 |	DropViewStmt
@@ -12047,6 +12350,7 @@ Statement:
 |	RenameUserStmt
 |	ReplaceIntoStmt
 |	RecoverTableStmt
+|	RefreshMViewStmt
 |	ReleaseSavepointStmt
 |	RevokeStmt
 |	RevokeRoleStmt
@@ -12085,6 +12389,7 @@ Statement:
 |	NonTransactionalDMLStmt
 |	OptimizeTableStmt
 |	CancelImportStmt
+|	XAStmt
 
 TraceableStmt:
 	DeleteFromStmt
@@ -12177,9 +12482,30 @@ CheckConstraintKeyword:
 	"CHECK"
 |	"CONSTRAINT"
 
+/*
+ * VECTOR is an unreserved keyword, so the vector index isn't a ConstraintElem, which may be preceded by an empty
+ * ConstraintKeywordOpt, to tell it from the column named vector.
+ */
+VectorIndexConstraint:
+	"VECTOR" KeyOrIndex IfNotExists IndexName '(' IndexPartSpecificationList ')' IndexOptionList
+	{
+		c := &ast.Constraint{
+			IfNotExists:  $3.(bool),
+			Tp:           ast.ConstraintVector,
+			Keys:         $6.([]*ast.IndexPartSpecification),
+			Name:         $4.(*ast.NullString).String,
+			IsEmptyIndex: $4.(*ast.NullString).Empty,
+		}
+		if $8 != nil {
+			c.Option = $8.(*ast.IndexOption)
+		}
+		$$ = c
+	}
+
 TableElement:
 	ColumnDef
 |	Constraint
+|	VectorIndexConstraint
 
 TableElementList:
 	TableElement
@@ -12413,6 +12739,37 @@ TableOption:
 			TimeUnitValue: &ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)},
 		}
 	}
+|	"TTL" EqOpt '(' Expression ')' '+' "INTERVAL" Literal TimeUnit
+	{
+		$$ = &ast.TableOption{
+			Tp:            ast.TableOptionTTL,
+			TTLExpr:       $4,
+			Value:         ast.NewValueExpr($8, parser.charset, parser.collation),
+			TimeUnitValue: &ast.TimeUnitExpr{Unit: $9.(ast.TimeUnitType)},
+		}
+	}
+|	"TTL_EPOCH_UNIT" EqOpt stringLit
+	{
+		unit := strings.ToUpper($3)
+		if unit != "SECOND" && unit != "MILLISECOND" && unit != "MICROSECOND" {
+			yylex.AppendError(yylex.Errorf("The TTL_EPOCH_UNIT option has to be 'SECOND', 'MILLISECOND' or 'MICROSECOND'"))
+			return 1
+		}
+		$$ = &ast.TableOption{Tp: ast.TableOptionTTLEpochUnit, StrValue: unit}
+	}
+|	"TTL_ARCHIVE" EqOpt stringLit
+	{
+		$$ = &ast.TableOption{Tp: ast.TableOptionTTLArchive, StrValue: $3}
+	}
+|	"TTL_ARCHIVE_FORMAT" EqOpt stringLit
+	{
+		format := strings.ToUpper($3)
+		if format != "CSV" && format != "PARQUET" {
+			yylex.AppendError(yylex.Errorf("The TTL_ARCHIVE_FORMAT option has to be 'CSV' or 'PARQUET'"))
+			return 1
+		}
+		$$ = &ast.TableOption{Tp: ast.TableOptionTTLArchiveFormat, StrValue: format}
+	}
 |	"TTL_ENABLE" EqOpt stringLit
 	{
 		onOrOff := strings.ToLower($3)
@@ -12549,6 +12906,8 @@ Type:
 	NumericType
 |	StringType
 |	DateAndTimeType
+|	SpatialType
+|	VectorType
 
 NumericType:
 	IntegerType OptFieldLen FieldOpts
@@ -13080,6 +13439,66 @@ DateAndTimeType:
 		$$ = tp
 	}
 
+SpatialType:
+	SpatialTypeName
+	{
+		tp := types.NewFieldType(mysql.TypeGeometry)
+		tp.SetGeometryType($1.(types.GeometryType))
+		tp.SetCharset(charset.CharsetBin)
+		tp.SetCollate(charset.CollationBin)
+		tp.AddFlag(mysql.BinaryFlag)
+		$$ = tp
+	}
+
+VectorType:
+	"VECTOR" OptFieldLen
+	{
+		tp := types.NewFieldType(mysql.TypeTiDBVectorFloat32)
+		tp.SetFlen($2.(int))
+		tp.SetCharset(charset.CharsetBin)
+		tp.SetCollate(charset.CollationBin)
+		tp.AddFlag(mysql.BinaryFlag)
+		$$ = tp
+	}
+
+SpatialTypeName:
+	"GEOMETRY"
+	{
+		$$ = types.GeometryTypeGeometry
+	}
+|	"POINT"
+	{
+		$$ = types.GeometryTypePoint
+	}
+|	"LINESTRING"
+	{
+		$$ = types.GeometryTypeLineString
+	}
+|	"POLYGON"
+	{
+		$$ = types.GeometryTypePolygon
+	}
+|	"MULTIPOINT"
+	{
+		$$ = types.GeometryTypeMultiPoint
+	}
+|	"MULTILINESTRING"
+	{
+		$$ = types.GeometryTypeMultiLineString
+	}
+|	"MULTIPOLYGON"
+	{
+		$$ = types.GeometryTypeMultiPolygon
+	}
+|	"GEOMETRYCOLLECTION"
+	{
+		$$ = types.GeometryTypeGeometryCollection
+	}
+|	"GEOMCOLLECTION"
+	{
+		$$ = types.GeometryTypeGeometryCollection
+	}
+
 FieldLen:
 	'(' LengthNum ')'
 	{
@@ -15147,8 +15566,7 @@ AlterSequenceOption:
  * Index Advisor Statement
  *
  * INDEX ADVISE
- * 	[LOCAL]
- *	INFILE 'file_name'
+ * 	{[LOCAL] INFILE 'file_name' | [LIMIT number]}
  *	[MAX_MINUTES number]
  *	[MAX_IDXNUM
  *  	[PER_TABLE number]
@@ -15177,6 +15595,27 @@ IndexAdviseStmt:
 		}
 		$$ = x
 	}
+|	"INDEX" "ADVISE" IndexAdviseLimitOpt MaxMinutesOpt MaxIndexNumOpt
+	{
+		x := &ast.IndexAdviseStmt{
+			FromStmtSummary: true,
+			Limit:           $3.(uint64),
+			MaxMinutes:      $4.(uint64),
+		}
+		if $5 != nil {
+			x.MaxIndexNum = $5.(*ast.MaxIndexNumClause)
+		}
+		$$ = x
+	}
+
+IndexAdviseLimitOpt:
+	{
+		$$ = uint64(ast.UnspecifiedSize)
+	}
+|	"LIMIT" NUM
+	{
+		$$ = getUint64FromNUM($2)
+	}
 
 MaxMinutesOpt:
 	{
@@ -15511,6 +15950,8 @@ ProcedureStatementStmt:
 |	DeleteFromStmt
 |	AnalyzeTableStmt
 |	TruncateTableStmt
+|	RefreshMViewStmt
+|	CallStmt
 
 ProcedureCursorSelectStmt:
 	SelectStmt
@@ -15980,37 +16421,68 @@ ProcedureProcStmt:
  *	CREATE
  *  [DEFINER = user]
  *  PROCEDURE [IF NOT EXISTS] sp_name ([proc_parameter[,...]])
- *  routine_body
+ *  [characteristic ...] routine_body
  *  proc_parameter:
  *  [ IN | OUT | INOUT ] param_name type
  *  func_parameter:
  *  param_name type
  *  type:
  *  Any valid MySQL data type
+ *  characteristic:
+ *  COMMENT 'string' | SQL SECURITY { DEFINER | INVOKER }
  * routine_body:
  *  Valid SQL routine statement
  ********************************************************************************************/
 CreateProcedureStmt:
-	"CREATE" "PROCEDURE" IfNotExists TableName '(' OptSpPdparams ')' ProcedureProcStmt
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "PROCEDURE" IfNotExists TableName '(' OptSpPdparams ')' ProcedureCharacteristicListOpt ProcedureProcStmt
 	{
-		x := &ast.ProcedureInfo{
-			IfNotExists:    $3.(bool),
-			ProcedureName:  $4.(*ast.TableName),
-			ProcedureParam: $6.([]*ast.StoreParameter),
-			ProcedureBody:  $8,
+		// The prefix is shared with CREATE VIEW to avoid conflicts, but only DEFINER is allowed here.
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(yylex.Errorf("OR REPLACE and ALGORITHM are not supported by CREATE PROCEDURE"))
+			return 1
 		}
+		x := $11.(*ast.ProcedureInfo)
+		x.IfNotExists = $6.(bool)
+		x.Definer = $4.(*auth.UserIdentity)
+		x.ProcedureName = $7.(*ast.TableName)
+		x.ProcedureParam = $9.([]*ast.StoreParameter)
+		x.ProcedureBody = $12
 		startOffset := parser.startOffset(&yyS[yypt])
-		originStmt := $8
+		originStmt := $12
 		originStmt.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:parser.yylval.offset]))
-		startOffset = parser.startOffset(&yyS[yypt-3])
+		startOffset = parser.startOffset(&yyS[yypt-4])
 		if parser.src[startOffset] == '(' {
 			startOffset++
 		}
-		endOffset := parser.startOffset(&yyS[yypt-1])
+		endOffset := parser.startOffset(&yyS[yypt-2])
 		x.ProcedureParamStr = strings.TrimSpace(parser.src[startOffset:endOffset])
 		$$ = x
 	}
 
+ProcedureCharacteristicListOpt:
+	/* empty */
+	{
+		$$ = &ast.ProcedureInfo{Security: model.SecurityDefiner}
+	}
+|	ProcedureCharacteristicListOpt "COMMENT" stringLit
+	{
+		x := $1.(*ast.ProcedureInfo)
+		x.Comment = $3
+		$$ = x
+	}
+|	ProcedureCharacteristicListOpt "SQL" "SECURITY" "DEFINER"
+	{
+		x := $1.(*ast.ProcedureInfo)
+		x.Security = model.SecurityDefiner
+		$$ = x
+	}
+|	ProcedureCharacteristicListOpt "SQL" "SECURITY" "INVOKER"
+	{
+		x := $1.(*ast.ProcedureInfo)
+		x.Security = model.SecurityInvoker
+		$$ = x
+	}
+
 /********************************************************************************************
 *  DROP PROCEDURE  [IF EXISTS] sp_name
 ********************************************************************************************/
@@ -16023,6 +16495,393 @@ DropProcedureStmt:
 		}
 	}
 
+/********************************************************************************************
+ *
+ *  Create Trigger Statement
+ *
+ *  Example:
+ *	CREATE
+ *  [DEFINER = user]
+ *  TRIGGER [IF NOT EXISTS] trigger_name
+ *  trigger_time trigger_event
+ *  ON tbl_name FOR EACH ROW
+ *  [trigger_order]
+ *  trigger_body
+ *  trigger_time: { BEFORE | AFTER }
+ *  trigger_event: { INSERT | UPDATE | DELETE }
+ *  trigger_order: { FOLLOWS | PRECEDES } other_trigger_name
+ ********************************************************************************************/
+CreateTriggerStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "TRIGGER" IfNotExists TableName TriggerTiming TriggerEvent "ON" TableName "FOR" "EACH" "ROW" TriggerOrderOpt ProcedureProcStmt
+	{
+		// The prefix is shared with CREATE VIEW to avoid conflicts, but only DEFINER is allowed here.
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(yylex.Errorf("OR REPLACE and ALGORITHM are not supported by CREATE TRIGGER"))
+			return 1
+		}
+		x := $15.(*ast.CreateTriggerStmt)
+		x.IfNotExists = $6.(bool)
+		x.Definer = $4.(*auth.UserIdentity)
+		x.TriggerName = $7.(*ast.TableName)
+		x.Timing = $8.(model.TriggerTiming)
+		x.Event = $9.(model.TriggerEvent)
+		x.Table = $11.(*ast.TableName)
+		x.Body = $16
+		startOffset := parser.startOffset(&yyS[yypt])
+		x.Body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:parser.yylval.offset]))
+		$$ = x
+	}
+
+TriggerTiming:
+	"BEFORE"
+	{
+		$$ = model.TriggerBefore
+	}
+|	"AFTER"
+	{
+		$$ = model.TriggerAfter
+	}
+
+TriggerEvent:
+	"INSERT"
+	{
+		$$ = model.TriggerInsert
+	}
+|	"UPDATE"
+	{
+		$$ = model.TriggerUpdate
+	}
+|	"DELETE"
+	{
+		$$ = model.TriggerDelete
+	}
+
+TriggerOrderOpt:
+	/* empty */
+	{
+		$$ = &ast.CreateTriggerStmt{}
+	}
+|	"FOLLOWS" Identifier
+	{
+		$$ = &ast.CreateTriggerStmt{Order: ast.TriggerOrderFollows, OtherTrigger: model.NewCIStr($2)}
+	}
+|	"PRECEDES" Identifier
+	{
+		$$ = &ast.CreateTriggerStmt{Order: ast.TriggerOrderPrecedes, OtherTrigger: model.NewCIStr($2)}
+	}
+
+/********************************************************************************************
+*  DROP TRIGGER [IF EXISTS] [schema_name.]trigger_name
+********************************************************************************************/
+DropTriggerStmt:
+	"DROP" "TRIGGER" IfExists TableName
+	{
+		$$ = &ast.DropTriggerStmt{
+			IfExists:    $3.(bool),
+			TriggerName: $4.(*ast.TableName),
+		}
+	}
+
+/********************************************************************************************
+ *
+ *  Create Event Statement
+ *
+ *  Example:
+ *	CREATE
+ *  [DEFINER = user]
+ *  EVENT [IF NOT EXISTS] event_name
+ *  ON SCHEDULE schedule
+ *  [ON COMPLETION [NOT] PRESERVE]
+ *  [ENABLE | DISABLE]
+ *  [COMMENT 'string']
+ *  DO event_body
+ *  schedule: { AT timestamp | EVERY interval [STARTS timestamp] [ENDS timestamp] }
+ ********************************************************************************************/
+CreateEventStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "EVENT" IfNotExists TableName "ON" "SCHEDULE" EventSchedule EventCompletionOpt EventStatusOpt EventCommentOpt "DO" EventBody
+	{
+		// The prefix is shared with CREATE VIEW to avoid conflicts, but only DEFINER is allowed here.
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(yylex.Errorf("OR REPLACE and ALGORITHM are not supported by CREATE EVENT"))
+			return 1
+		}
+		x := &ast.CreateEventStmt{
+			IfNotExists: $6.(bool),
+			Definer:     $4.(*auth.UserIdentity),
+			EventName:   $7.(*ast.TableName),
+			Schedule:    $10.(*ast.EventSchedule),
+			Completion:  $11.(ast.EventCompletionType),
+			Status:      $12.(ast.EventStatusType),
+			Body:        $15,
+		}
+		if $13 != nil {
+			x.Comment = $13.(string)
+		}
+		startOffset := parser.startOffset(&yyS[yypt])
+		x.Body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:parser.yylval.offset]))
+		$$ = x
+	}
+
+EventSchedule:
+	"AT" Expression
+	{
+		$$ = &ast.EventSchedule{At: $2}
+	}
+|	"EVERY" Expression TimeUnit EventStartsOpt EventEndsOpt
+	{
+		$$ = &ast.EventSchedule{
+			Interval: $2,
+			Unit:     $3.(ast.TimeUnitType),
+			Starts:   $4,
+			Ends:     $5,
+		}
+	}
+
+EventStartsOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"STARTS" Expression
+	{
+		$$ = $2
+	}
+
+EventEndsOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"ENDS" Expression
+	{
+		$$ = $2
+	}
+
+EventCompletionOpt:
+	/* empty */
+	{
+		$$ = ast.EventCompletionUnspecified
+	}
+|	"ON" "COMPLETION" "PRESERVE"
+	{
+		$$ = ast.EventCompletionPreserve
+	}
+|	"ON" "COMPLETION" "NOT" "PRESERVE"
+	{
+		$$ = ast.EventCompletionNotPreserve
+	}
+
+EventStatusOpt:
+	/* empty */
+	{
+		$$ = ast.EventStatusUnspecified
+	}
+|	"ENABLE"
+	{
+		$$ = ast.EventStatusEnable
+	}
+|	"DISABLE"
+	{
+		$$ = ast.EventStatusDisable
+	}
+
+EventBody:
+	ProcedureStatementStmt
+
+/********************************************************************************************
+ *
+ *  Alter Event Statement
+ *
+ *  Example:
+ *	ALTER
+ *  [DEFINER = user]
+ *  EVENT event_name
+ *  [ON SCHEDULE schedule]
+ *  [ON COMPLETION [NOT] PRESERVE]
+ *  [RENAME TO new_event_name]
+ *  [ENABLE | DISABLE]
+ *  [COMMENT 'string']
+ *  [DO event_body]
+ ********************************************************************************************/
+AlterEventStmt:
+	"ALTER" ViewDefiner "EVENT" TableName AlterEventScheduleOpt AlterEventRenameOpt EventStatusOpt EventCommentOpt AlterEventBodyOpt
+	{
+		x := $5.(*ast.AlterEventStmt)
+		x.Definer = $2.(*auth.UserIdentity)
+		x.EventName = $4.(*ast.TableName)
+		if $6 != nil {
+			x.RenameTo = $6.(*ast.TableName)
+		}
+		x.Status = $7.(ast.EventStatusType)
+		if $8 != nil {
+			comment := $8.(string)
+			x.Comment = &comment
+		}
+		if $9 != nil {
+			x.Body = $9.(ast.StmtNode)
+		}
+		$$ = x
+	}
+
+AlterEventScheduleOpt:
+	EventCompletionOpt
+	{
+		$$ = &ast.AlterEventStmt{Completion: $1.(ast.EventCompletionType)}
+	}
+|	"ON" "SCHEDULE" EventSchedule EventCompletionOpt
+	{
+		$$ = &ast.AlterEventStmt{
+			Schedule:   $3.(*ast.EventSchedule),
+			Completion: $4.(ast.EventCompletionType),
+		}
+	}
+
+AlterEventRenameOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"RENAME" "TO" TableName
+	{
+		$$ = $3
+	}
+
+EventCommentOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"COMMENT" stringLit
+	{
+		$$ = $2
+	}
+
+AlterEventBodyOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"DO" EventBody
+	{
+		body := $2
+		startOffset := parser.startOffset(&yyS[yypt])
+		body.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:parser.yylval.offset]))
+		$$ = body
+	}
+
+/********************************************************************************************
+*  DROP EVENT [IF EXISTS] event_name
+********************************************************************************************/
+DropEventStmt:
+	"DROP" "EVENT" IfExists TableName
+	{
+		$$ = &ast.DropEventStmt{
+			IfExists:  $3.(bool),
+			EventName: $4.(*ast.TableName),
+		}
+	}
+
+/********************************************************************************************
+ *
+ *  Create Materialized View Statement
+ *
+ *  Example:
+ *	CREATE
+ *  [DEFINER = user]
+ *  MATERIALIZED VIEW [IF NOT EXISTS] view_name [(column_list)]
+ *  [REFRESH {COMPLETE | FAST} [ON SCHEDULE schedule]]
+ *  AS select_statement
+ ********************************************************************************************/
+CreateMaterializedViewStmt:
+	"CREATE" OrReplace ViewAlgorithm ViewDefiner "MATERIALIZED" "VIEW" IfNotExists ViewName ViewFieldList MViewRefreshOpt "AS" CreateViewSelectOpt
+	{
+		// The prefix is shared with CREATE VIEW to avoid conflicts, but only DEFINER is allowed here.
+		if $2.(bool) || $3.(model.ViewAlgorithm) != model.AlgorithmUndefined {
+			yylex.AppendError(yylex.Errorf("OR REPLACE and ALGORITHM are not supported by CREATE MATERIALIZED VIEW"))
+			return 1
+		}
+		startOffset := parser.startOffset(&yyS[yypt])
+		selStmt := $12.(ast.StmtNode)
+		selStmt.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:]))
+		x := $10.(*ast.CreateMaterializedViewStmt)
+		x.IfNotExists = $7.(bool)
+		x.Definer = $4.(*auth.UserIdentity)
+		x.ViewName = $8.(*ast.TableName)
+		x.Select = selStmt
+		if $9 != nil {
+			x.Cols = $9.([]model.CIStr)
+		}
+		$$ = x
+	}
+
+MViewRefreshOpt:
+	/* empty */
+	{
+		$$ = &ast.CreateMaterializedViewStmt{Refresh: model.MViewRefreshComplete}
+	}
+|	"REFRESH" MViewRefreshMethod MViewScheduleOpt
+	{
+		x := &ast.CreateMaterializedViewStmt{Refresh: $2.(model.MViewRefreshMethod)}
+		if $3 != nil {
+			x.Schedule = $3.(*ast.EventSchedule)
+		}
+		$$ = x
+	}
+
+MViewRefreshMethod:
+	"COMPLETE"
+	{
+		$$ = model.MViewRefreshComplete
+	}
+|	"FAST"
+	{
+		$$ = model.MViewRefreshFast
+	}
+
+MViewScheduleOpt:
+	/* empty */
+	{
+		$$ = nil
+	}
+|	"ON" "SCHEDULE" EventSchedule
+	{
+		$$ = $3
+	}
+
+/********************************************************************************************
+*  DROP MATERIALIZED VIEW [IF EXISTS] view_name
+********************************************************************************************/
+DropMaterializedViewStmt:
+	"DROP" "MATERIALIZED" "VIEW" IfExists TableName
+	{
+		$$ = &ast.DropMaterializedViewStmt{
+			IfExists: $4.(bool),
+			ViewName: $5.(*ast.TableName),
+		}
+	}
+
+/********************************************************************************************
+*  REFRESH MATERIALIZED VIEW view_name [COMPLETE]
+********************************************************************************************/
+RefreshMViewStmt:
+	"REFRESH" "MATERIALIZED" "VIEW" TableName RefreshCompleteOpt
+	{
+		$$ = &ast.RefreshMaterializedViewStmt{
+			ViewName: $4.(*ast.TableName),
+			Complete: $5.(bool),
+		}
+	}
+
+RefreshCompleteOpt:
+	/* empty */
+	{
+		$$ = false
+	}
+|	"COMPLETE"
+	{
+		$$ = true
+	}
+
 /********************************************************************
  *
  * Calibrate Resource Statement
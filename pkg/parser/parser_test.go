@@ -99,7 +99,7 @@ func TestSimple(t *testing.T) {
 		"following", "preceding", "unbounded", "respect", "nulls", "current", "last", "against", "expansion",
 		"chain", "error", "general", "nvarchar", "pack_keys", "p", "shard_row_id_bits", "pre_split_regions",
 		"constraints", "role", "replicas", "policy", "s3", "strict", "running", "stop", "preserve", "placement", "attributes", "attribute", "resource",
-		"burstable", "calibrate", "rollup",
+		"burstable", "calibrate", "rollup", "xa", "xid", "one", "phase", "suspend", "migrate",
 	}
 	for _, kw := range unreservedKws {
 		src := fmt.Sprintf("SELECT %s FROM tbl;", kw)
@@ -517,6 +517,11 @@ func TestAdminStmt(t *testing.T) {
 		{"admin set bdr role secondary", true, "ADMIN SET BDR ROLE SECONDARY"},
 		{"admin unset bdr role", true, "ADMIN UNSET BDR ROLE"},
 		{"admin show bdr role", true, "ADMIN SHOW BDR ROLE"},
+		// for cardinality feedback
+		{"admin reset cardinality feedback", true, "ADMIN RESET CARDINALITY FEEDBACK"},
+		{"admin reset cardinality feedback t1, test.t2", true, "ADMIN RESET CARDINALITY FEEDBACK `t1`, `test`.`t2`"},
+		{"admin reset cardinality feedback where a = 1", false, ""},
+		{"create table feedback (feedback int)", true, "CREATE TABLE `feedback` (`feedback` INT)"},
 	}
 	RunTest(t, table, false)
 }
@@ -613,6 +618,30 @@ func TestDMLStmt(t *testing.T) {
 		{"ROLLBACK TO X", true, "ROLLBACK TO X"},
 		{"ROLLBACK TO SAVEPOINT x", true, "ROLLBACK TO x"},
 
+		// xa statement
+		{"XA START 'gtrid'", true, "XA START 'gtrid'"},
+		{"XA BEGIN 'gtrid', 'bqual'", true, "XA START 'gtrid','bqual'"},
+		{"XA START 'gtrid', 'bqual', 3", true, "XA START 'gtrid','bqual',3"},
+		{"XA START 0x6774726964, 0x627175616c, 1", true, "XA START 0x6774726964,0x627175616c"},
+		{"XA START X'6774726964'", true, "XA START 0x6774726964"},
+		{"XA START 'gtrid', '', 2", true, "XA START 'gtrid','',2"},
+		{"XA START 'gtrid' JOIN", true, "XA START 'gtrid' JOIN"},
+		{"XA START 'gtrid' RESUME", true, "XA START 'gtrid' RESUME"},
+		{"XA START", false, ""},
+		{"XA START gtrid", false, ""},
+		{"XA START 'gtrid', 'bqual', -1", false, ""},
+		{"XA END 'gtrid'", true, "XA END 'gtrid'"},
+		{"XA END 'gtrid' SUSPEND", true, "XA END 'gtrid' SUSPEND"},
+		{"XA END 'gtrid' SUSPEND FOR MIGRATE", true, "XA END 'gtrid' SUSPEND FOR MIGRATE"},
+		{"XA PREPARE 'gtrid', 'bqual'", true, "XA PREPARE 'gtrid','bqual'"},
+		{"XA COMMIT 'gtrid'", true, "XA COMMIT 'gtrid'"},
+		{"XA COMMIT 'gtrid' ONE PHASE", true, "XA COMMIT 'gtrid' ONE PHASE"},
+		{"XA ROLLBACK 'gtrid'", true, "XA ROLLBACK 'gtrid'"},
+		{"XA ROLLBACK 'gtrid' ONE PHASE", false, ""},
+		{"XA RECOVER", true, "XA RECOVER"},
+		{"XA RECOVER CONVERT XID", true, "XA RECOVER CONVERT XID"},
+		{"XA RECOVER 'gtrid'", false, ""},
+
 		// table statement
 		{"TABLE t", true, "TABLE `t`"},
 		{"(TABLE t)", true, "(TABLE `t`)"},
@@ -790,6 +819,10 @@ func TestDMLStmt(t *testing.T) {
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' enclosed BY '\"' lines terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' ENCLOSED BY '\"' LINES TERMINATED BY '\r'"},
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' optionally enclosed BY '\"' lines starting by 'xy' terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' LINES STARTING BY 'xy' TERMINATED BY '\r'"},
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' enclosed BY '\"' lines starting by 'xy' terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' ENCLOSED BY '\"' LINES STARTING BY 'xy' TERMINATED BY '\r'"},
+		{"select a, b from t into outfile 's3://bucket/prefix/result.parquet' format 'parquet'", true, "SELECT `a`,`b` FROM `t` INTO OUTFILE 's3://bucket/prefix/result.parquet' FORMAT 'parquet'"},
+		{"select a, b from t into outfile 's3://bucket/prefix/result.csv.gz' fields terminated BY ',' with max_file_size=1048576", true, "SELECT `a`,`b` FROM `t` INTO OUTFILE 's3://bucket/prefix/result.csv.gz' FIELDS TERMINATED BY ',' WITH max_file_size=1048576"},
+		{"select a, b from t into outfile 's3://bucket/prefix/result.csv' format 'csv' fields terminated BY ',' lines terminated BY '\n' with max_file_size=1024, header", true, "SELECT `a`,`b` FROM `t` INTO OUTFILE 's3://bucket/prefix/result.csv' FORMAT 'csv' FIELDS TERMINATED BY ',' LINES TERMINATED BY '\n' WITH max_file_size=1024, header"},
+		{"select a from t into outfile 's3://bucket/prefix/result.csv' with", false, ""},
 
 		// from join
 		{"SELECT * from t1, t2, t3", true, "SELECT * FROM ((`t1`) JOIN `t2`) JOIN `t3`"},
@@ -3300,6 +3333,13 @@ func TestDDL(t *testing.T) {
 		{"CREATE INDEX idx ON t ( a ) VISIBLE INVISIBLE", true, "CREATE INDEX `idx` ON `t` (`a`) INVISIBLE"},
 		{"CREATE INDEX idx ON t ( a ) USING HASH VISIBLE", true, "CREATE INDEX `idx` ON `t` (`a`) USING HASH VISIBLE"},
 		{"CREATE INDEX idx ON t ( a ) USING HASH INVISIBLE", true, "CREATE INDEX `idx` ON `t` (`a`) USING HASH INVISIBLE"},
+		{"CREATE INDEX idx ON t ( a ) GLOBAL", true, "CREATE INDEX `idx` ON `t` (`a`) GLOBAL"},
+		{"CREATE INDEX idx ON t ( a ) LOCAL", true, "CREATE INDEX `idx` ON `t` (`a`) LOCAL"},
+		{"CREATE INDEX idx ON t ( a ) GLOBAL LOCAL", true, "CREATE INDEX `idx` ON `t` (`a`) LOCAL"},
+		{"CREATE UNIQUE INDEX idx ON t ( a ) USING HASH INVISIBLE GLOBAL", true, "CREATE UNIQUE INDEX `idx` ON `t` (`a`) USING HASH INVISIBLE GLOBAL"},
+		{"CREATE INDEX idx ON t ( a ) /*T![global_index] GLOBAL */ COMMENT 'foo'", true, "CREATE INDEX `idx` ON `t` (`a`) COMMENT 'foo' GLOBAL"},
+		{"ALTER TABLE t ADD INDEX idx (a) GLOBAL", true, "ALTER TABLE `t` ADD INDEX `idx`(`a`) GLOBAL"},
+		{"ALTER TABLE t ADD UNIQUE KEY idx (a) LOCAL", true, "ALTER TABLE `t` ADD UNIQUE `idx`(`a`) LOCAL"},
 
 		// For create index with algorithm
 		{"CREATE INDEX idx ON t ( a ) ALGORITHM = DEFAULT", true, "CREATE INDEX `idx` ON `t` (`a`)"},
@@ -3603,6 +3643,20 @@ func TestDDL(t *testing.T) {
 		{`CREATE TABLE IF NOT EXISTS table_ident (ident1 BOOL COMMENT "text_string" unique, ident2 SQL_TSI_YEAR(4) ZEROFILL);`, true, "CREATE TABLE IF NOT EXISTS `table_ident` (`ident1` TINYINT(1) COMMENT 'text_string' UNIQUE KEY,`ident2` YEAR(4))"},
 		{"create table t (y sql_tsi_year(4), y1 sql_tsi_year)", true, "CREATE TABLE `t` (`y` YEAR(4),`y1` YEAR)"},
 		{"create table t (y sql_tsi_year(4) unsigned zerofill zerofill, y1 sql_tsi_year signed unsigned zerofill)", true, "CREATE TABLE `t` (`y` YEAR(4),`y1` YEAR)"},
+		{"create table t (g geometry, p point not null, l linestring, pg polygon, mp multipoint, ml multilinestring, mpg multipolygon, gc geometrycollection, gc1 geomcollection)", true, "CREATE TABLE `t` (`g` GEOMETRY,`p` POINT NOT NULL,`l` LINESTRING,`pg` POLYGON,`mp` MULTIPOINT,`ml` MULTILINESTRING,`mpg` MULTIPOLYGON,`gc` GEOMCOLLECTION,`gc1` GEOMCOLLECTION)"},
+		{"create table t (point int, polygon int)", true, "CREATE TABLE `t` (`point` INT,`polygon` INT)"},
+		{"create table t (g point(10))", false, ""},
+		{"create table t (v vector, v3 vector(3) not null, vector int)", true, "CREATE TABLE `t` (`v` VECTOR,`v3` VECTOR(3) NOT NULL,`vector` INT)"},
+		{"create table t (v vector(3), vector index idx ((vec_cosine_distance(v))))", true, "CREATE TABLE `t` (`v` VECTOR(3),VECTOR INDEX `idx`((VEC_COSINE_DISTANCE(`v`))))"},
+		{"create table t (v vector(3), vector key if not exists ((vec_l2_distance(v))) comment 'ann')", true, "CREATE TABLE `t` (`v` VECTOR(3),VECTOR INDEX IF NOT EXISTS((VEC_L2_DISTANCE(`v`))) COMMENT 'ann')"},
+		{"create vector index idx on t ((vec_cosine_distance(v)))", true, "CREATE VECTOR INDEX `idx` ON `t` ((VEC_COSINE_DISTANCE(`v`)))"},
+		{"alter table t add vector index idx ((vec_l2_distance(v)))", true, "ALTER TABLE `t` ADD VECTOR INDEX `idx`((VEC_L2_DISTANCE(`v`)))"},
+		{"alter table t add vector int", true, "ALTER TABLE `t` ADD COLUMN `vector` INT"},
+		{"alter table t add vector vector(3) first", true, "ALTER TABLE `t` ADD COLUMN `vector` VECTOR(3) FIRST"},
+		{"alter table t add column if not exists vector int", true, "ALTER TABLE `t` ADD COLUMN IF NOT EXISTS `vector` INT"},
+		{"alter table t add if not exists vector int", true, "ALTER TABLE `t` ADD COLUMN IF NOT EXISTS `vector` INT"},
+		{"alter table t add if not exists (vector int, vector index ((vec_l2_distance(vector))))", true, "ALTER TABLE `t` ADD COLUMN IF NOT EXISTS (`vector` INT, VECTOR INDEX((VEC_L2_DISTANCE(`vector`))))"},
+		{"alter table t add vector (v)", false, ""},
 
 		// for issue 549
 		{"insert into t set a = default", true, "INSERT INTO `t` SET `a`=DEFAULT"},
@@ -3750,6 +3804,7 @@ func TestDDL(t *testing.T) {
 		{"create table t (a int, b varchar(255) primary key nonclustered, primary key(b, a) nonclustered)", true, "CREATE TABLE `t` (`a` INT,`b` VARCHAR(255) PRIMARY KEY NONCLUSTERED,PRIMARY KEY(`b`, `a`) NONCLUSTERED)"},
 		{"create table t (a int, b varchar(255), primary key(b, a) using RTREE nonclustered)", true, "CREATE TABLE `t` (`a` INT,`b` VARCHAR(255),PRIMARY KEY(`b`, `a`) NONCLUSTERED USING RTREE)"},
 		{"create table t (a int, b varchar(255), primary key(b, a) using RTREE clustered nonclustered)", true, "CREATE TABLE `t` (`a` INT,`b` VARCHAR(255),PRIMARY KEY(`b`, `a`) NONCLUSTERED USING RTREE)"},
+		{"create table t (a int, b int, key idx(b) global, unique key (a, b) local, primary key(b) nonclustered global)", true, "CREATE TABLE `t` (`a` INT,`b` INT,INDEX `idx`(`b`) GLOBAL,UNIQUE(`a`, `b`) LOCAL,PRIMARY KEY(`b`) NONCLUSTERED GLOBAL)"},
 		{"create table t (a int, b varchar(255), primary key(b, a) using RTREE nonclustered clustered)", true, "CREATE TABLE `t` (`a` INT,`b` VARCHAR(255),PRIMARY KEY(`b`, `a`) CLUSTERED USING RTREE)"},
 		{"create table t (a int, b varchar(255) clustered primary key)", false, ""},
 		{"create table t (a int, b varchar(255) primary key nonclustered clustered)", false, ""},
@@ -5717,6 +5772,83 @@ func TestTimestampDiffUnit(t *testing.T) {
 	RunTest(t, table, false)
 }
 
+func TestTrigger(t *testing.T) {
+	table := []testCase{
+		{"create trigger tr before insert on t for each row set new.a = new.a + 1", true, "CREATE TRIGGER `tr` BEFORE INSERT ON `t` FOR EACH ROW SET @@SESSION.`new.a`=`new`.`a`+1"},
+		{"create trigger if not exists test.tr after update on test.t for each row insert into log values (old.a, new.a)", true, "CREATE TRIGGER IF NOT EXISTS `test`.`tr` AFTER UPDATE ON `test`.`t` FOR EACH ROW INSERT INTO `log` VALUES (`old`.`a`,`new`.`a`)"},
+		{"create definer = 'root'@'%' trigger tr after delete on t for each row follows tr1 delete from t1 where a = old.a", true, "CREATE DEFINER = `root`@`%` TRIGGER `tr` AFTER DELETE ON `t` FOR EACH ROW FOLLOWS `tr1` DELETE FROM `t1` WHERE `a`=`old`.`a`"},
+		{"create definer = current_user trigger tr before update on t for each row precedes tr1 set new.a = 0", true, "CREATE TRIGGER `tr` BEFORE UPDATE ON `t` FOR EACH ROW PRECEDES `tr1` SET @@SESSION.`new.a`=0"},
+		{"create trigger tr before insert on t set new.a = 1", false, ""},
+		{"create trigger tr instead of insert on t for each row set new.a = 1", false, ""},
+		{"create or replace trigger tr before insert on t for each row set new.a = 1", false, ""},
+		{"drop trigger tr", true, "DROP TRIGGER `tr`"},
+		{"drop trigger if exists test.tr", true, "DROP TRIGGER IF EXISTS `test`.`tr`"},
+		{"show create trigger test.tr", true, "SHOW CREATE TRIGGER `test`.`tr`"},
+		{"show triggers from test like 't%'", true, "SHOW TRIGGERS IN `test` LIKE _UTF8MB4't%'"},
+		// The new keywords are unreserved.
+		{"create table before (each int, follows int, precedes int)", true, "CREATE TABLE `before` (`each` INT,`follows` INT,`precedes` INT)"},
+	}
+	RunTest(t, table, false)
+
+	// The statements in a block are not traversed, so the restored SQL is checked only.
+	p := parser.New()
+	stmt, err := p.ParseOneStmt("create trigger tr before update on t for each row begin if new.a < 0 then set new.a = 0; end if; end", "", "")
+	require.NoError(t, err)
+	trigger := stmt.(*ast.CreateTriggerStmt)
+	require.Equal(t, "begin if new.a < 0 then set new.a = 0; end if; end", trigger.Body.Text())
+	var sb strings.Builder
+	require.NoError(t, stmt.Restore(NewRestoreCtx(DefaultRestoreFlags, &sb)))
+	require.Equal(t, "CREATE TRIGGER `tr` BEFORE UPDATE ON `t` FOR EACH ROW BEGIN IF `new`.`a`<0 THEN SET @@SESSION.`new.a`=0;END IF; END", sb.String())
+}
+
+func TestEvent(t *testing.T) {
+	table := []testCase{
+		{"create event ev on schedule at '2024-01-01 00:00:00' do insert into t values (1)", true, "CREATE EVENT `ev` ON SCHEDULE AT _UTF8MB4'2024-01-01 00:00:00' DO INSERT INTO `t` VALUES (1)"},
+		{"create event if not exists test.ev on schedule at current_timestamp + interval 1 hour on completion preserve disable comment 'once' do delete from t", true, "CREATE EVENT IF NOT EXISTS `test`.`ev` ON SCHEDULE AT DATE_ADD(CURRENT_TIMESTAMP(), INTERVAL 1 HOUR) ON COMPLETION PRESERVE DISABLE COMMENT 'once' DO DELETE FROM `t`"},
+		{"create definer = 'root'@'%' event ev on schedule every 1 day starts '2024-01-01' ends '2025-01-01' on completion not preserve enable do call test.nightly_rollup()", true, "CREATE DEFINER = `root`@`%` EVENT `ev` ON SCHEDULE EVERY 1 DAY STARTS _UTF8MB4'2024-01-01' ENDS _UTF8MB4'2025-01-01' ON COMPLETION NOT PRESERVE ENABLE DO CALL `test`.`nightly_rollup`()"},
+		{"create event ev on schedule every 10 minute do update t set a = a + 1", true, "CREATE EVENT `ev` ON SCHEDULE EVERY 10 MINUTE DO UPDATE `t` SET `a`=`a`+1"},
+		{"create event ev on schedule every 1 day", false, ""},
+		{"create event ev do select 1", false, ""},
+		{"create or replace event ev on schedule every 1 day do select 1", false, ""},
+		{"alter event ev enable", true, "ALTER EVENT `ev` ENABLE"},
+		{"alter definer = 'root'@'%' event test.ev on schedule every 2 hour ends '2025-01-01' on completion preserve rename to test.ev2 disable comment '' do insert into t select 1", true, "ALTER DEFINER = `root`@`%` EVENT `test`.`ev` ON SCHEDULE EVERY 2 HOUR ENDS _UTF8MB4'2025-01-01' ON COMPLETION PRESERVE RENAME TO `test`.`ev2` DISABLE COMMENT '' DO INSERT INTO `t` SELECT 1"},
+		{"alter event ev on completion not preserve", true, "ALTER EVENT `ev` ON COMPLETION NOT PRESERVE"},
+		{"drop event ev", true, "DROP EVENT `ev`"},
+		{"drop event if exists test.ev", true, "DROP EVENT IF EXISTS `test`.`ev`"},
+		{"show create event test.ev", true, "SHOW CREATE EVENT `test`.`ev`"},
+		{"show events from test like 'e%'", true, "SHOW EVENTS IN `test` LIKE _UTF8MB4'e%'"},
+		// The new keywords are unreserved.
+		{"create table at (every int, starts int, ends int, completion int)", true, "CREATE TABLE `at` (`every` INT,`starts` INT,`ends` INT,`completion` INT)"},
+	}
+	RunTest(t, table, false)
+
+	p := parser.New()
+	stmt, err := p.ParseOneStmt("create event ev on schedule every 1 hour do  insert into t values (now()) ", "", "")
+	require.NoError(t, err)
+	require.Equal(t, "insert into t values (now())", stmt.(*ast.CreateEventStmt).Body.Text())
+	stmt, err = p.ParseOneStmt("alter event ev do delete from t", "", "")
+	require.NoError(t, err)
+	require.Equal(t, "delete from t", stmt.(*ast.AlterEventStmt).Body.Text())
+}
+
+func TestMaterializedView(t *testing.T) {
+	table := []testCase{
+		{"create materialized view mv as select a, count(*) from t group by a", true, "CREATE MATERIALIZED VIEW `mv` REFRESH COMPLETE AS SELECT `a`,COUNT(1) FROM `t` GROUP BY `a`"},
+		{"create definer = 'root'@'%' materialized view if not exists test.mv (a, c) refresh fast as select a, count(*) from t group by a", true, "CREATE DEFINER = `root`@`%` MATERIALIZED VIEW IF NOT EXISTS `test`.`mv` (`a`,`c`) REFRESH FAST AS SELECT `a`,COUNT(1) FROM `t` GROUP BY `a`"},
+		{"create materialized view mv refresh complete on schedule every 1 hour starts '2024-01-01' as select * from t1 join t2 on t1.a = t2.a", true, "CREATE MATERIALIZED VIEW `mv` REFRESH COMPLETE ON SCHEDULE EVERY 1 HOUR STARTS _UTF8MB4'2024-01-01' AS SELECT * FROM `t1` JOIN `t2` ON `t1`.`a`=`t2`.`a`"},
+		{"create materialized view mv refresh on schedule every 1 hour as select 1", false, ""},
+		{"create or replace materialized view mv as select 1", false, ""},
+		{"drop materialized view mv", true, "DROP MATERIALIZED VIEW `mv`"},
+		{"drop materialized view if exists test.mv", true, "DROP MATERIALIZED VIEW IF EXISTS `test`.`mv`"},
+		{"refresh materialized view mv", true, "REFRESH MATERIALIZED VIEW `mv`"},
+		{"refresh materialized view test.mv complete", true, "REFRESH MATERIALIZED VIEW `test`.`mv` COMPLETE"},
+		{"create procedure p() refresh materialized view mv", true, "CREATE PROCEDURE `p`() REFRESH MATERIALIZED VIEW `mv`"},
+		// The new keywords are unreserved.
+		{"create table materialized (refresh int, fast int, complete int)", true, "CREATE TABLE `materialized` (`refresh` INT,`fast` INT,`complete` INT)"},
+	}
+	RunTest(t, table, false)
+}
+
 func TestFuncCallExprOffset(t *testing.T) {
 	// Test case for offset field on func call expr.
 	p := parser.New()
@@ -6353,6 +6485,11 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT COUNT(profit) OVER() AS country_profit FROM sales;`, true, "SELECT COUNT(`profit`) OVER () AS `country_profit` FROM `sales`"},
 		{`SELECT COUNT(ALL profit) OVER() AS country_profit FROM sales;`, true, "SELECT COUNT(`profit`) OVER () AS `country_profit` FROM `sales`"},
 		{`SELECT COUNT(*) OVER() AS country_profit FROM sales;`, true, "SELECT COUNT(1) OVER () AS `country_profit` FROM `sales`"},
+		{`SELECT COUNT(DISTINCT profit) OVER() AS country_profit FROM sales;`, true, "SELECT COUNT(DISTINCT `profit`) OVER () AS `country_profit` FROM `sales`"},
+		{`SELECT COUNT(DISTINCT profit, year) OVER w FROM sales;`, true, "SELECT COUNT(DISTINCT `profit`, `year`) OVER `w` FROM `sales`"},
+		{`SELECT GROUP_CONCAT(profit) OVER w FROM sales;`, true, "SELECT GROUP_CONCAT(`profit` SEPARATOR ',') OVER `w` FROM `sales`"},
+		{`SELECT GROUP_CONCAT(DISTINCT profit, year SEPARATOR ';') OVER (PARTITION BY country) FROM sales;`, true, "SELECT GROUP_CONCAT(DISTINCT `profit`, `year` SEPARATOR ';') OVER (PARTITION BY `country`) FROM `sales`"},
+		{`SELECT GROUP_CONCAT(profit ORDER BY year) OVER w FROM sales;`, false, ""},
 		{`SELECT MAX(profit) OVER() AS country_profit FROM sales;`, true, "SELECT MAX(`profit`) OVER () AS `country_profit` FROM `sales`"},
 		{`SELECT MIN(profit) OVER() AS country_profit FROM sales;`, true, "SELECT MIN(`profit`) OVER () AS `country_profit` FROM `sales`"},
 		{`SELECT SUM(profit) OVER() AS country_profit FROM sales;`, true, "SELECT SUM(`profit`) OVER () AS `country_profit` FROM `sales`"},
@@ -6365,6 +6502,7 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT AVG(val) OVER (PARTITION BY subject ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (PARTITION BY `subject` ORDER BY `time` ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (ROWS BETWEEN 1 PRECEDING AND UNBOUNDED FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (ROWS BETWEEN 1 PRECEDING AND UNBOUNDED FOLLOWING) FROM `t`"},
+		{`SELECT AVG(val) OVER (ORDER BY time GROUPS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (ORDER BY `time` GROUPS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL '2:30' MINUTE_SECOND FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL _UTF8MB4'2:30' MINUTE_SECOND FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
@@ -6851,6 +6989,15 @@ func TestIndexAdviseStmt(t *testing.T) {
 		{"INDEX ADVISE INFILE '/tmp/t.sql' MAX_MINUTES 0 MAX_IDXNUM PER_TABLE 8 PER_DB 4 LINES STARTING BY 'ab' TERMINATED BY 'cd'", true, "INDEX ADVISE INFILE '/tmp/t.sql' MAX_MINUTES 0 MAX_IDXNUM PER_TABLE 8 PER_DB 4 LINES STARTING BY 'ab' TERMINATED BY 'cd'"},
 		{"INDEX ADVISE INFILE '/tmp/t.sql' MAX_MINUTES -1 MAX_IDXNUM PER_TABLE 8 PER_DB 4 LINES STARTING BY 'ab' TERMINATED BY '\n'", false, ""},
 		{"INDEX ADVISE INFILE '/tmp/t.sql' MAX_MINUTES -1 MAX_IDXNUM PER_TABLE 8 PER_DB 4 LINES STARTING BY 'ab' TERMINATED BY 'cd'", false, ""},
+
+		{"INDEX ADVISE", true, "INDEX ADVISE"},
+		{"INDEX ADVISE LIMIT 10", true, "INDEX ADVISE LIMIT 10"},
+		{"INDEX ADVISE MAX_MINUTES 3", true, "INDEX ADVISE MAX_MINUTES 3"},
+		{"INDEX ADVISE LIMIT 10 MAX_IDXNUM PER_TABLE 2", true, "INDEX ADVISE LIMIT 10 MAX_IDXNUM PER_TABLE 2"},
+		{"INDEX ADVISE LIMIT 10 MAX_MINUTES 3 MAX_IDXNUM PER_TABLE 2 PER_DB 4", true, "INDEX ADVISE LIMIT 10 MAX_MINUTES 3 MAX_IDXNUM PER_TABLE 2 PER_DB 4"},
+		{"INDEX ADVISE LIMIT -1", false, ""},
+		{"INDEX ADVISE MAX_MINUTES 3 LIMIT 10", false, ""},
+		{"INDEX ADVISE LIMIT 10 LINES TERMINATED BY 'cd'", false, ""},
 	}
 
 	RunTest(t, table, false)
@@ -7452,6 +7599,10 @@ func TestTTLTableOption(t *testing.T) {
 		{"create table t (created_at datetime) TTL created_at + INTERVAL 1 YEAR TTL_ENABLE 'OFF'", true, "CREATE TABLE `t` (`created_at` DATETIME) TTL = `created_at` + INTERVAL 1 YEAR TTL_ENABLE = 'OFF'"},
 		{"create table t (created_at datetime) TTL created_at + INTERVAL 1 YEAR TTL_ENABLE 'OFF' TTL_JOB_INTERVAL='8h'", true, "CREATE TABLE `t` (`created_at` DATETIME) TTL = `created_at` + INTERVAL 1 YEAR TTL_ENABLE = 'OFF' TTL_JOB_INTERVAL = '8h'"},
 		{"create table t (created_at datetime) /*T![ttl] ttl=created_at + INTERVAL 1 YEAR ttl_enable='ON'*/", true, "CREATE TABLE `t` (`created_at` DATETIME) TTL = `created_at` + INTERVAL 1 YEAR TTL_ENABLE = 'ON'"},
+		{"create table t (created_at datetime) TTL = created_at + INTERVAL 1 YEAR TTL_ARCHIVE 's3://bucket/prefix?region=us-west-2'", true, "CREATE TABLE `t` (`created_at` DATETIME) TTL = `created_at` + INTERVAL 1 YEAR TTL_ARCHIVE = 's3://bucket/prefix?region=us-west-2'"},
+		{"create table t (created_at bigint) TTL = created_at + INTERVAL 90 DAY TTL_EPOCH_UNIT = 'millisecond'", true, "CREATE TABLE `t` (`created_at` BIGINT) TTL = `created_at` + INTERVAL 90 DAY TTL_EPOCH_UNIT = 'MILLISECOND'"},
+		{"create table t (created_at bigint) TTL = (from_unixtime(created_at / 1000)) + INTERVAL 1 DAY", true, "CREATE TABLE `t` (`created_at` BIGINT) TTL = (FROM_UNIXTIME(`created_at`/1000)) + INTERVAL 1 DAY"},
+		{"create table t (a datetime, b datetime) /*T![ttl] ttl=(greatest(a, b)) + INTERVAL 1 YEAR*/", true, "CREATE TABLE `t` (`a` DATETIME,`b` DATETIME) TTL = (GREATEST(`a`, `b`)) + INTERVAL 1 YEAR"},
 
 		// alter table with various temporal interval
 		{"alter table t TTL = created_at + INTERVAL 1 MONTH", true, "ALTER TABLE `t` TTL = `created_at` + INTERVAL 1 MONTH"},
@@ -7462,6 +7613,13 @@ func TestTTLTableOption(t *testing.T) {
 		{"alter table t /*T![ttl] ttl=created_at + INTERVAL 1 YEAR ttl_enable='ON'*/", true, "ALTER TABLE `t` TTL = `created_at` + INTERVAL 1 YEAR TTL_ENABLE = 'ON'"},
 		{"alter table t /*T![ttl] ttl=created_at + INTERVAL 1 YEAR ttl_enable='ON' TTL_JOB_INTERVAL='8h'*/", true, "ALTER TABLE `t` TTL = `created_at` + INTERVAL 1 YEAR TTL_ENABLE = 'ON' TTL_JOB_INTERVAL = '8h'"},
 		{"alter table t /*T![ttl] ttl=created_at + INTERVAL 1 YEAR ttl_enable='ON' TTL_JOB_INTERVAL='8.645124531235h'*/", true, "ALTER TABLE `t` TTL = `created_at` + INTERVAL 1 YEAR TTL_ENABLE = 'ON' TTL_JOB_INTERVAL = '8.645124531235h'"},
+		{"alter table t TTL = created_at + INTERVAL 1 MONTH TTL_EPOCH_UNIT 'SECOND'", true, "ALTER TABLE `t` TTL = `created_at` + INTERVAL 1 MONTH TTL_EPOCH_UNIT = 'SECOND'"},
+		{"alter table t TTL = (a + b) + INTERVAL 1 MONTH TTL_EPOCH_UNIT 'MICROSECOND'", true, "ALTER TABLE `t` TTL = (`a`+`b`) + INTERVAL 1 MONTH TTL_EPOCH_UNIT = 'MICROSECOND'"},
+		{"alter table t TTL_ARCHIVE = 's3://bucket/archive'", true, "ALTER TABLE `t` TTL_ARCHIVE = 's3://bucket/archive'"},
+		{"alter table t /*T![ttl] TTL_ARCHIVE = '' */", true, "ALTER TABLE `t` TTL_ARCHIVE = ''"},
+		{"alter table t TTL_ARCHIVE = 's3://bucket/archive' TTL_ARCHIVE_FORMAT = 'parquet'", true, "ALTER TABLE `t` TTL_ARCHIVE = 's3://bucket/archive' TTL_ARCHIVE_FORMAT = 'PARQUET'"},
+		{"alter table t TTL_ARCHIVE_FORMAT 'csv'", true, "ALTER TABLE `t` TTL_ARCHIVE_FORMAT = 'CSV'"},
+		{"alter table t TTL_ARCHIVE_FORMAT = 'json'", false, ""},
 
 		// alter table to remove ttl settings
 		{"alter table t remove ttl", true, "ALTER TABLE `t` REMOVE TTL"},
@@ -7475,6 +7633,10 @@ func TestTTLTableOption(t *testing.T) {
 		{"create table t (created_at datetime) TTL_JOB_INTERVAL = '@monthly'", false, ""},
 		{"create table t (created_at datetime) TTL_JOB_INTERVAL = '10hourxx'", false, ""},
 		{"create table t (created_at datetime) TTL_JOB_INTERVAL = '10.10.255h'", false, ""},
+
+		// validate invalid TTL_EPOCH_UNIT settings
+		{"create table t (created_at bigint) TTL_EPOCH_UNIT = 'DAY'", false, ""},
+		{"alter table t /*T![ttl] TTL_EPOCH_UNIT = 'NANOSECOND' */", false, ""},
 	}
 
 	RunTest(t, table, false)
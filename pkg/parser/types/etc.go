@@ -68,6 +68,8 @@ var type2Str = map[byte]string{
 	mysql.TypeVarchar:     "varchar",
 	mysql.TypeVarString:   "var_string",
 	mysql.TypeYear:        "year",
+
+	mysql.TypeTiDBVectorFloat32: "vector",
 }
 
 var str2Type = map[string]byte{
@@ -98,6 +100,7 @@ var str2Type = map[string]byte{
 	"varchar":     mysql.TypeVarchar,
 	"var_string":  mysql.TypeVarString,
 	"year":        mysql.TypeYear,
+	"vector":      mysql.TypeTiDBVectorFloat32,
 }
 
 // TypeStr converts tp to a string.
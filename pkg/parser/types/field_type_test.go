@@ -14,6 +14,7 @@
 package types_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -329,3 +330,26 @@ func TestCompactStr(t *testing.T) {
 		require.Equal(t, cc.e2, ft.CompactStr())
 	}
 }
+
+func TestGeometryFieldType(t *testing.T) {
+	ft := NewFieldType(mysql.TypeGeometry)
+	require.Equal(t, "geometry", ft.CompactStr())
+	ft.SetGeometryType(GeometryTypePoint)
+	require.Equal(t, "point", ft.CompactStr())
+	require.Equal(t, "point", ft.String())
+
+	other := ft.Clone()
+	require.True(t, ft.Equal(other))
+	other.SetGeometryType(GeometryTypePolygon)
+	require.False(t, ft.Equal(other))
+
+	data, err := json.Marshal(ft)
+	require.NoError(t, err)
+	var decoded FieldType
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, GeometryTypePoint, decoded.GetGeometryType())
+
+	require.True(t, GeometryTypeGeometry.Accepts(GeometryTypePolygon))
+	require.True(t, GeometryTypePoint.Accepts(GeometryTypePoint))
+	require.False(t, GeometryTypePoint.Accepts(GeometryTypePolygon))
+}
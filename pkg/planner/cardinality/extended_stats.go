@@ -0,0 +1,134 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/planner/context"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+// eqColSel is the selectivity of the equal conditions on a column, which is estimated by the column stats alone.
+type eqColSel struct {
+	uniqueID int64
+	sel      float64
+}
+
+// adjustSelectivityByExtendedStats returns the factor to adjust the selectivity of the equal conditions on
+// correlated columns. These conditions are estimated by the column stats independently, which underestimates
+// the selectivity when the columns are correlated, e.g, `country = ? and city = ?`.
+//   - For cardinality stats on columns c1...cn, the independent selectivity is scaled up by
+//     min(ndv(c1)*...*ndv(cn), row count) / ndv(c1...cn), and it's no larger than the selectivity of any single column.
+//   - For dependency stats x -> y with degree d, sel(x, y) = sel(x) * (d + (1-d) * sel(y)).
+//
+// Each column is adjusted by at most one extended stats, the cardinality stats on more columns go first.
+func adjustSelectivityByExtendedStats(ctx context.PlanContext, coll *statistics.HistColl, usedSets []*StatsNode) float64 {
+	if coll.ExtStats == nil || len(coll.ExtStats.Stats) == 0 {
+		return 1
+	}
+	eqCols := make(map[int64]eqColSel)
+	for _, set := range usedSets {
+		if set.Tp != ColType || set.partCover || !isPointRanges(ctx, set.Ranges) {
+			continue
+		}
+		if colInfoID, ok := coll.UniqueID2colInfoID[set.ID]; ok {
+			eqCols[colInfoID] = eqColSel{uniqueID: set.ID, sel: set.Selectivity}
+		}
+	}
+	if len(eqCols) < 2 {
+		return 1
+	}
+
+	items := make([]*statistics.ExtendedStatsItem, 0, len(coll.ExtStats.Stats))
+	for _, item := range coll.ExtStats.Stats {
+		if item.Tp == ast.StatsTypeCardinality || item.Tp == ast.StatsTypeDependency {
+			items = append(items, item)
+		}
+	}
+	slices.SortFunc(items, func(a, b *statistics.ExtendedStatsItem) int {
+		if a.Tp != b.Tp {
+			// Cardinality stats go first.
+			return cmp.Compare(a.Tp, b.Tp)
+		}
+		if c := cmp.Compare(len(b.ColIDs), len(a.ColIDs)); c != 0 {
+			return c
+		}
+		return slices.Compare(a.ColIDs, b.ColIDs)
+	})
+
+	factor := 1.0
+	adjusted := make(map[int64]struct{}, len(eqCols))
+	for _, item := range items {
+		covered := true
+		for _, id := range item.ColIDs {
+			_, ok := eqCols[id]
+			_, done := adjusted[id]
+			if !ok || done {
+				covered = false
+				break
+			}
+		}
+		if !covered || item.ScalarVals <= 0 {
+			continue
+		}
+		var indepSel, newSel float64
+		switch item.Tp {
+		case ast.StatsTypeCardinality:
+			indepSel, newSel = 1, 1
+			ndvProduct := 1.0
+			for _, id := range item.ColIDs {
+				col := eqCols[id]
+				indepSel *= col.sel
+				newSel = math.Min(newSel, col.sel)
+				if colStats := coll.Columns[col.uniqueID]; colStats != nil && colStats.Histogram.NDV > 0 {
+					ndvProduct *= float64(colStats.Histogram.NDV)
+				}
+			}
+			ndvProduct = math.Min(ndvProduct, float64(coll.RealtimeCount))
+			newSel = math.Min(newSel, indepSel*math.Max(1, ndvProduct/item.ScalarVals))
+		case ast.StatsTypeDependency:
+			if len(item.ColIDs) != 2 {
+				continue
+			}
+			selX, selY := eqCols[item.ColIDs[0]].sel, eqCols[item.ColIDs[1]].sel
+			degree := math.Min(item.ScalarVals, 1)
+			indepSel = selX * selY
+			newSel = selX * (degree + (1-degree)*selY)
+		}
+		if indepSel <= 0 {
+			continue
+		}
+		factor *= newSel / indepSel
+		for _, id := range item.ColIDs {
+			adjusted[id] = struct{}{}
+		}
+	}
+	return factor
+}
+
+// isPointRanges checks whether all the ranges are points.
+func isPointRanges(ctx context.PlanContext, ranges []*ranger.Range) bool {
+	for _, ran := range ranges {
+		if !ran.IsPoint(ctx) {
+			return false
+		}
+	}
+	return len(ranges) > 0
+}
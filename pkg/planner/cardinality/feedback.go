@@ -0,0 +1,136 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/planner/context"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+)
+
+// predicateShape returns the column of the predicate and its shape for the cardinality feedback. It returns false
+// if the predicate isn't on exactly one column of the table.
+func predicateShape(expr expression.Expression) (*expression.Column, string, bool) {
+	cols := expression.ExtractColumns(expr)
+	if len(cols) == 0 {
+		return nil, "", false
+	}
+	for _, col := range cols[1:] {
+		if col.UniqueID != cols[0].UniqueID {
+			return nil, "", false
+		}
+	}
+	if cols[0].ID <= 0 {
+		// It's not a column of the table, e.g, the extra handle column.
+		return nil, "", false
+	}
+	shape := statistics.FeedbackShapeOther
+	if sf, ok := expr.(*expression.ScalarFunction); ok && (sf.FuncName.L == ast.LogicOr || hasColumnArg(sf)) {
+		switch sf.FuncName.L {
+		case ast.EQ, ast.NullEQ:
+			shape = statistics.FeedbackShapeEq
+		case ast.NE:
+			shape = statistics.FeedbackShapeNe
+		case ast.LT, ast.LE, ast.GT, ast.GE:
+			shape = statistics.FeedbackShapeRange
+		case ast.In:
+			shape = statistics.FeedbackShapeIn
+		case ast.Like, ast.Ilike, ast.Regexp, ast.RegexpLike:
+			shape = statistics.FeedbackShapeLike
+		case ast.IsNull:
+			shape = statistics.FeedbackShapeNull
+		case ast.LogicOr:
+			shape = statistics.FeedbackShapeOr
+		}
+	}
+	return cols[0], shape, true
+}
+
+// hasColumnArg checks whether the column is an argument of the function itself, so `a + 1 = 2` is not of the same
+// shape as `a = 2`.
+func hasColumnArg(sf *expression.ScalarFunction) bool {
+	for _, arg := range sf.GetArgs() {
+		if _, ok := arg.(*expression.Column); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupPredicatesForFeedback groups the predicates on a single column by the column ID, and returns the shape of
+// the predicates on each column. allOnSingleColumn is false if some predicates are on several columns, which are
+// ignored by the cardinality feedback.
+func GroupPredicatesForFeedback(exprs []expression.Expression) (shapes map[int64]string, allOnSingleColumn bool) {
+	allOnSingleColumn = true
+	colShapes := make(map[int64][]string, len(exprs))
+	for _, expr := range exprs {
+		col, shape, ok := predicateShape(expr)
+		if !ok {
+			allOnSingleColumn = false
+			continue
+		}
+		if !slices.Contains(colShapes[col.ID], shape) {
+			colShapes[col.ID] = append(colShapes[col.ID], shape)
+		}
+	}
+	shapes = make(map[int64]string, len(colShapes))
+	for id, s := range colShapes {
+		slices.Sort(s)
+		shapes[id] = strings.Join(s, ",")
+	}
+	return shapes, allOnSingleColumn
+}
+
+// GetCardinalityFeedback returns the ratio of the cardinality correction of the key, and records it as applied in
+// the statement, so the correction can be learned again from the actual row counts.
+func GetCardinalityFeedback(ctx context.PlanContext, key statistics.FeedbackKey) float64 {
+	if !variable.EnableCardinalityFeedback.Load() {
+		return 1
+	}
+	correction, ok := statistics.CardinalityFeedback.Get(key)
+	if !ok || !correction.IsApplicable() {
+		return 1
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	if sc.AppliedCardinalityFeedback == nil {
+		sc.AppliedCardinalityFeedback = make(map[string]float64)
+	}
+	sc.AppliedCardinalityFeedback[key.String()] = correction.Ratio
+	return correction.Ratio
+}
+
+// AdjustSelectivityByFeedback returns the factor to adjust the selectivity by the cardinality corrections learned
+// from the actual row counts. The predicates on the same column are corrected together by their shape, and the
+// corrections on different columns are applied independently.
+func AdjustSelectivityByFeedback(ctx context.PlanContext, coll *statistics.HistColl, exprs []expression.Expression) float64 {
+	if !variable.EnableCardinalityFeedback.Load() || !statistics.CardinalityFeedback.HasTable(coll.PhysicalID) {
+		return 1
+	}
+	shapes, _ := GroupPredicatesForFeedback(exprs)
+	factor := 1.0
+	for colID, shape := range shapes {
+		factor *= GetCardinalityFeedback(ctx, statistics.FeedbackKey{
+			Shape:    shape,
+			TableID:  coll.PhysicalID,
+			ColumnID: colID,
+		})
+	}
+	return factor
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareCardinalityFeedbackTable(t *testing.T, tk *testkit.TestKit, dom *domain.Domain) statistics.FeedbackKey {
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8)")
+	for i := 0; i < 7; i++ {
+		tk.MustExec("insert into t select a + (select max(a) from t), b from t")
+	}
+	tk.MustExec("analyze table t")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	return statistics.FeedbackKey{
+		Shape:    statistics.FeedbackShapeOther,
+		TableID:  tbl.Meta().ID,
+		ColumnID: tbl.Meta().Columns[1].ID,
+	}
+}
+
+func TestCardinalityFeedback(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	statistics.CardinalityFeedback.Reset()
+	defer statistics.CardinalityFeedback.Reset()
+	tk.MustExec("set global tidb_enable_cardinality_feedback = on")
+	defer tk.MustExec("set global tidb_enable_cardinality_feedback = default")
+	key := prepareCardinalityFeedbackTable(t, tk, dom)
+	h := dom.StatsHandle()
+
+	// The selectivity of `b + 1 = 2` is estimated by the default selection factor.
+	sql := "select * from t where b + 1 = 2"
+	tk.MustQuery("explain format='brief' "+sql).CheckAt([]int{1}, testkit.Rows("819.20", "819.20", "1024.00"))
+	_, ok := statistics.CardinalityFeedback.Get(key)
+	require.False(t, ok)
+
+	// The gap between the estimated and actual row counts is learned by the execution, but it isn't applied until
+	// it's observed by enough executions.
+	tk.MustQuery(sql)
+	correction, ok := statistics.CardinalityFeedback.Get(key)
+	require.True(t, ok)
+	require.Equal(t, 0.15625, correction.Ratio)
+	require.Equal(t, int64(1), correction.Count)
+	tk.MustQuery("explain format='brief' "+sql).CheckAt([]int{1}, testkit.Rows("819.20", "819.20", "1024.00"))
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select shape, ratio, count from mysql.stats_cardinality_feedback").Check(testkit.Rows("other 0.15625 1"))
+	for i := 1; i < statistics.MinFeedbackObservations; i++ {
+		tk.MustQuery(sql)
+	}
+	correction, ok = statistics.CardinalityFeedback.Get(key)
+	require.True(t, ok)
+	require.Equal(t, 0.15625, correction.Ratio)
+	require.Equal(t, int64(statistics.MinFeedbackObservations), correction.Count)
+	tk.MustQuery("explain format='brief' "+sql).CheckAt([]int{1}, testkit.Rows("128.00", "128.00", "1024.00"))
+
+	// The corrected estimation is accurate, so the correction is kept by the executions.
+	tk.MustQuery(sql)
+	correction, ok = statistics.CardinalityFeedback.Get(key)
+	require.True(t, ok)
+	require.Equal(t, 0.15625, correction.Ratio)
+	require.Equal(t, int64(statistics.MinFeedbackObservations), correction.Count)
+
+	// The correction decays when new stats arrive, and it's removed once the gap isn't significant.
+	tk.MustExec("analyze table t")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select shape, ratio from mysql.stats_cardinality_feedback").Check(testkit.Rows("other 0.39528470752104744"))
+	tk.MustExec("analyze table t")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("0"))
+	_, ok = statistics.CardinalityFeedback.Get(key)
+	require.False(t, ok)
+
+	// Nothing is learned if the feature is disabled.
+	tk.MustExec("set global tidb_enable_cardinality_feedback = off")
+	tk.MustQuery(sql)
+	_, ok = statistics.CardinalityFeedback.Get(key)
+	require.False(t, ok)
+}
+
+func TestResetCardinalityFeedback(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	statistics.CardinalityFeedback.Reset()
+	defer statistics.CardinalityFeedback.Reset()
+	tk.MustExec("set global tidb_enable_cardinality_feedback = on")
+	defer tk.MustExec("set global tidb_enable_cardinality_feedback = default")
+	key := prepareCardinalityFeedbackTable(t, tk, dom)
+	h := dom.StatsHandle()
+
+	tk.MustQuery("explain analyze select * from t where b + 1 = 2")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("1"))
+
+	tk.MustExec("create table t2(a int)")
+	tk.MustExec("admin reset cardinality feedback t2")
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("1"))
+	_, ok := statistics.CardinalityFeedback.Get(key)
+	require.True(t, ok)
+
+	tk.MustExec("admin reset cardinality feedback t")
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("0"))
+	_, ok = statistics.CardinalityFeedback.Get(key)
+	require.False(t, ok)
+
+	tk.MustQuery("explain analyze select * from t where b + 1 = 2")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustExec("admin reset cardinality feedback")
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("0"))
+	_, ok = statistics.CardinalityFeedback.Get(key)
+	require.False(t, ok)
+
+	// The corrections of the dropped tables are removed by the GC of stats.
+	tk.MustQuery("explain analyze select * from t where b + 1 = 2")
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustExec("drop table t")
+	require.NoError(t, h.GCStats(dom.InfoSchema(), 0))
+	require.NoError(t, h.DumpCardinalityFeedbackToKV())
+	tk.MustQuery("select count(*) from mysql.stats_cardinality_feedback").Check(testkit.Rows("0"))
+	_, ok = statistics.CardinalityFeedback.Get(key)
+	require.False(t, ok)
+}
+
+func TestCardinalityFeedbackWithAlternatingParameters(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	statistics.CardinalityFeedback.Reset()
+	defer statistics.CardinalityFeedback.Reset()
+	tk.MustExec("set global tidb_enable_cardinality_feedback = on")
+	defer tk.MustExec("set global tidb_enable_cardinality_feedback = default")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, c varchar(10))")
+	tk.MustExec("insert into t values (1, 'rare')")
+	for i := 0; i < 23; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, 'x%02d')", i+2, i))
+	}
+	tk.MustExec("insert into t values (25, 'common'), (26, 'common'), (27, 'common'), (28, 'common')")
+	for i := 0; i < 8; i++ {
+		tk.MustExec("insert into t select a + (select max(a) from t), c from t where c = 'common'")
+	}
+	// Without TopN, both values are estimated by the average row count of the values.
+	tk.MustExec("analyze table t with 0 topn, 1 buckets")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	key := statistics.FeedbackKey{
+		Shape:    statistics.FeedbackShapeEq,
+		TableID:  tbl.Meta().ID,
+		ColumnID: tbl.Meta().Columns[1].ID,
+	}
+	rare := "explain format='brief' select * from t where c = 'rare'"
+	common := "explain format='brief' select * from t where c = 'common'"
+	// The stats of c are loaded by the execution, and the feedback learned by it is dropped.
+	tk.MustQuery("select * from t where c = 'x01'")
+	statistics.CardinalityFeedback.Reset()
+	rareRows := tk.MustQuery(rare).Rows()
+	require.Equal(t, "41.92", rareRows[0][1])
+	commonRows := tk.MustQuery(common).Rows()
+	require.Equal(t, "41.92", commonRows[0][1])
+
+	// The executions disagree on the direction of the correction, so nothing is applied.
+	for i := 0; i < 2*statistics.MinFeedbackObservations; i++ {
+		tk.MustQuery("select * from t where c = 'rare'").Check(testkit.Rows("1 rare"))
+		tk.MustQuery("select count(*) from t where c = 'common'").Check(testkit.Rows("1024"))
+		correction, ok := statistics.CardinalityFeedback.Get(key)
+		require.False(t, ok && correction.IsApplicable())
+	}
+	tk.MustQuery(rare).Check(rareRows)
+	tk.MustQuery(common).Check(commonRows)
+}
+
+func TestJoinCardinalityFeedback(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	statistics.CardinalityFeedback.Reset()
+	defer statistics.CardinalityFeedback.Reset()
+	tk.MustExec("set global tidb_enable_cardinality_feedback = on")
+	defer tk.MustExec("set global tidb_enable_cardinality_feedback = default")
+	tk.MustExec("use test")
+	for _, tbl := range []string{"t1", "t2"} {
+		tk.MustExec("create table " + tbl + "(a int, b int, key ib(b))")
+		tk.MustExec("insert into " + tbl + " values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8)")
+		tk.MustExec("insert into " + tbl + " select a + 8, a + 8 from " + tbl)
+		tk.MustExec("insert into " + tbl + " select a + 16, a + 16 from " + tbl)
+		// Most rows are of the same value, which is not known by the stats without TopN.
+		for i := 0; i < 5; i++ {
+			tk.MustExec("insert into " + tbl + " select a + (select max(a) from " + tbl + "), 1 from " + tbl)
+		}
+		tk.MustExec("analyze table " + tbl + " with 0 topn")
+	}
+
+	sql := "select /*+ hash_join(t1, t2) */ count(*) from t1, t2 where t1.b = t2.b"
+	tk.MustQuery("explain format='brief' "+sql).CheckAt([]int{0, 1}, [][]any{
+		{"HashAgg", "1.00"},
+		{"└─HashJoin", "32768.00"},
+		{"  ├─IndexReader(Build)", "1024.00"},
+		{"  │ └─IndexFullScan", "1024.00"},
+		{"  └─IndexReader(Probe)", "1024.00"},
+		{"    └─IndexFullScan", "1024.00"},
+	})
+	for i := 0; i < statistics.MinFeedbackObservations; i++ {
+		tk.MustQuery(sql).Check(testkit.Rows("986080"))
+	}
+	tk.MustQuery("explain format='brief' "+sql).CheckAt([]int{1}, testkit.Rows("1.00", "986080.00", "1024.00", "1024.00", "1024.00", "1024.00"))
+}
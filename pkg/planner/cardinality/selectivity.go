@@ -254,6 +254,13 @@ func Selectivity(
 		}
 	}
 
+	if factor := adjustSelectivityByExtendedStats(ctx, coll, usedSets); factor != 1 {
+		ret *= factor
+		if sc.EnableOptimizerDebugTrace {
+			debugtrace.RecordAnyValuesWithNames(ctx, "Extended stats adjustment", factor)
+		}
+	}
+
 	notCoveredConstants := make(map[int]*expression.Constant)
 	notCoveredDNF := make(map[int]*expression.ScalarFunction)
 	notCoveredStrMatch := make(map[int]*expression.ScalarFunction)
@@ -434,6 +441,13 @@ OUTER:
 		}
 	}
 
+	if factor := AdjustSelectivityByFeedback(ctx, coll, exprs); factor != 1 {
+		ret = min(ret*factor, 1)
+		if sc.EnableOptimizerDebugTrace {
+			debugtrace.RecordAnyValuesWithNames(ctx, "Cardinality feedback adjustment", factor)
+		}
+	}
+
 	if sc.EnableOptimizerCETrace {
 		// Tracing for the expression estimation results after applying the default selectivity.
 		totalExpr := expression.ComposeCNFCondition(ctx.GetExprCtx(), remainedExprs...)
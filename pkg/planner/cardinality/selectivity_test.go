@@ -1310,3 +1310,42 @@ func TestSubsetIdxCardinality(t *testing.T) {
 		testKit.MustQuery(input[i]).Check(testkit.Rows(output[i].Result...))
 	}
 }
+
+func TestExtendedStatsEstimation(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int, b int, c int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d, %d)", i%10, i%10*2, i%7))
+	}
+	tk.MustExec("analyze table t")
+	// Without extended stats, the columns are assumed to be independent.
+	tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 2").Check(testkit.Rows(
+		"TableReader 1.00 root  data:Selection",
+		"└─Selection 1.00 cop[tikv]  eq(test.t.a, 1), eq(test.t.b, 2)",
+		"  └─TableFullScan 100.00 cop[tikv] table:t keep order:false"))
+	tk.MustQuery("explain format = 'brief' select a, c, count(*) from t group by a, c").Check(testkit.Rows(
+		"Projection 10.00 root  test.t.a, test.t.c, Column#5",
+		"└─HashAgg 10.00 root  group by:test.t.a, test.t.c, funcs:count(Column#6)->Column#5, funcs:firstrow(test.t.a)->test.t.a, funcs:firstrow(test.t.c)->test.t.c",
+		"  └─TableReader 10.00 root  data:HashAgg",
+		"    └─HashAgg 10.00 cop[tikv]  group by:test.t.a, test.t.c, funcs:count(1)->Column#6",
+		"      └─TableFullScan 100.00 cop[tikv] table:t keep order:false"))
+
+	// `b` depends on `a` and the group NDV of (a, c) is larger than NDV(a) and NDV(c).
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,c)")
+	tk.MustExec("alter table t add stats_extended s2 dependency(a,b)")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 2").Check(testkit.Rows(
+		"TableReader 10.00 root  data:Selection",
+		"└─Selection 10.00 cop[tikv]  eq(test.t.a, 1), eq(test.t.b, 2)",
+		"  └─TableFullScan 100.00 cop[tikv] table:t keep order:false"))
+	tk.MustQuery("explain format = 'brief' select a, c, count(*) from t group by a, c").Check(testkit.Rows(
+		"Projection 70.00 root  test.t.a, test.t.c, Column#5",
+		"└─HashAgg 70.00 root  group by:test.t.a, test.t.c, funcs:count(Column#6)->Column#5, funcs:firstrow(test.t.a)->test.t.a, funcs:firstrow(test.t.c)->test.t.c",
+		"  └─TableReader 70.00 root  data:HashAgg",
+		"    └─HashAgg 70.00 cop[tikv]  group by:test.t.a, test.t.c, funcs:count(1)->Column#6",
+		"      └─TableFullScan 100.00 cop[tikv] table:t keep order:false"))
+}
@@ -0,0 +1,345 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/planner/cardinality"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/util/execdetails"
+)
+
+// feedbackMinRows is the minimal actual row count of the input of an operator to learn a cardinality correction
+// from it, the gaps on fewer rows are more likely to be noises.
+const feedbackMinRows = 100
+
+// CollectCardinalityFeedback learns the cardinality corrections from the actual row counts of an executed plan.
+// The estimated and actual row counts are compared for:
+//   - the filters on a single column, relative to the input of the filters.
+//   - the access conditions on a single column of the index and table scans, relative to the table.
+//   - the equal join conditions on two columns, relative to the cartesian product of the join inputs.
+//
+// The significant gaps are kept in statistics.CardinalityFeedback and applied in the estimation later.
+func CollectCardinalityFeedback(sctx PlanContext, p Plan) {
+	vars := sctx.GetSessionVars()
+	if !variable.EnableCardinalityFeedback.Load() || vars.StmtCtx.RuntimeStatsColl == nil || vars.FoundInPlanCache {
+		// The estimation of the cached plans may be made before the corrections applied now.
+		return
+	}
+	if explain, ok := p.(*Explain); ok && explain.Analyze {
+		p = explain.TargetPlan
+	}
+	var physicalPlan PhysicalPlan
+	switch x := p.(type) {
+	case PhysicalPlan:
+		physicalPlan = x
+	case *Insert:
+		physicalPlan = x.SelectPlan
+	case *Update:
+		physicalPlan = x.SelectPlan
+	case *Delete:
+		physicalPlan = x.SelectPlan
+	}
+	if physicalPlan == nil {
+		return
+	}
+	c := &feedbackCollector{
+		sctx:      sctx,
+		statsColl: vars.StmtCtx.RuntimeStatsColl,
+		applied:   vars.StmtCtx.AppliedCardinalityFeedback,
+	}
+	c.collect(physicalPlan, feedbackScope{})
+}
+
+// feedbackScope describes where an operator is in the plan, which decides the corrections it can tell.
+type feedbackScope struct {
+	// underLimit means the operator may stop early, so its actual row count is smaller than the estimated one.
+	underLimit bool
+	// repeated means the operator is the inner side of an index join or apply, which is executed many times while
+	// its row count is estimated for one execution.
+	repeated bool
+	// inCop means the operator is executed by the storage.
+	inCop bool
+}
+
+type feedbackCollector struct {
+	sctx      PlanContext
+	statsColl *execdetails.RuntimeStatsColl
+	applied   map[string]float64
+}
+
+func (c *feedbackCollector) collect(p PhysicalPlan, scope feedbackScope) {
+	switch x := p.(type) {
+	case *PhysicalLimit, *PhysicalTopN:
+		scope.underLimit = true
+	case *PhysicalSelection:
+		c.collectSelection(x)
+	case *PhysicalTableScan:
+		if !scope.underLimit && !scope.repeated {
+			c.collectScan(x, x.AccessCondition, x.tblColHists)
+		}
+	case *PhysicalIndexScan:
+		if !scope.underLimit && !scope.repeated {
+			c.collectScan(x, x.AccessCondition, x.tblColHists)
+		}
+	case *PhysicalHashJoin:
+		if !scope.underLimit && !scope.repeated && !scope.inCop && len(x.NAEqualConditions) == 0 {
+			c.collectJoin(&x.basePhysicalJoin)
+		}
+	case *PhysicalMergeJoin:
+		if !scope.underLimit && !scope.repeated && !scope.inCop {
+			c.collectJoin(&x.basePhysicalJoin)
+		}
+	case *PhysicalTableReader:
+		scope.inCop = true
+		c.collect(x.tablePlan, scope)
+	case *PhysicalIndexReader:
+		scope.inCop = true
+		c.collect(x.indexPlan, scope)
+	case *PhysicalIndexLookUpReader:
+		scope.inCop = true
+		c.collect(x.indexPlan, scope)
+		c.collect(x.tablePlan, scope)
+	case *PhysicalIndexMergeReader:
+		scope.inCop = true
+		for _, partialPlan := range x.partialPlans {
+			c.collect(partialPlan, scope)
+		}
+		if x.tablePlan != nil {
+			c.collect(x.tablePlan, scope)
+		}
+	}
+	innerIdx := -1
+	switch x := p.(type) {
+	case *PhysicalIndexJoin:
+		innerIdx = x.InnerChildIdx
+	case *PhysicalIndexHashJoin:
+		innerIdx = x.InnerChildIdx
+	case *PhysicalIndexMergeJoin:
+		innerIdx = x.InnerChildIdx
+	case *PhysicalApply:
+		innerIdx = x.InnerChildIdx
+	}
+	for i, child := range p.Children() {
+		childScope := scope
+		if i == innerIdx {
+			childScope.repeated = true
+		}
+		c.collect(child, childScope)
+	}
+}
+
+func (c *feedbackCollector) collectSelection(sel *PhysicalSelection) {
+	child := sel.Children()[0]
+	shapes, allOnSingleColumn := cardinality.GroupPredicatesForFeedback(sel.Conditions)
+	if !allOnSingleColumn || len(shapes) != 1 {
+		return
+	}
+	tableID, ok := feedbackStatsTableID(child)
+	if !ok {
+		return
+	}
+	actRows, ok1 := c.actRows(sel)
+	actInput, ok2 := c.actRows(child)
+	estRows, estInput := sel.StatsCount(), child.StatsCount()
+	if !ok1 || !ok2 || actInput < feedbackMinRows || estInput <= 0 || estRows <= 0 {
+		return
+	}
+	for colID, shape := range shapes {
+		c.observe(statistics.FeedbackKey{Shape: shape, TableID: tableID, ColumnID: colID},
+			(max(actRows, 0.5)/actInput)/(estRows/estInput))
+	}
+}
+
+func (c *feedbackCollector) collectScan(scan PhysicalPlan, accessConds []expression.Expression, coll *statistics.HistColl) {
+	if len(accessConds) == 0 || coll == nil || coll.RealtimeCount < feedbackMinRows {
+		return
+	}
+	shapes, allOnSingleColumn := cardinality.GroupPredicatesForFeedback(accessConds)
+	if !allOnSingleColumn || len(shapes) != 1 {
+		return
+	}
+	actRows, ok := c.actRows(scan)
+	estRows := scan.StatsCount()
+	if !ok || estRows <= 0 {
+		return
+	}
+	for colID, shape := range shapes {
+		c.observe(statistics.FeedbackKey{Shape: shape, TableID: coll.PhysicalID, ColumnID: colID},
+			max(actRows, 0.5)/estRows)
+	}
+}
+
+func (c *feedbackCollector) collectJoin(join *basePhysicalJoin) {
+	if join.JoinType != InnerJoin || len(join.LeftJoinKeys) != 1 || len(join.RightJoinKeys) != 1 ||
+		len(join.LeftConditions) > 0 || len(join.RightConditions) > 0 || len(join.OtherConditions) > 0 {
+		return
+	}
+	lChild, rChild := join.children[0], join.children[1]
+	lTableID, lColID, ok1 := feedbackPhysicalColumnSource(lChild, join.LeftJoinKeys[0])
+	rTableID, rColID, ok2 := feedbackPhysicalColumnSource(rChild, join.RightJoinKeys[0])
+	if !ok1 || !ok2 {
+		return
+	}
+	actRows, ok1 := c.actRows(join.self)
+	actLeft, ok2 := c.actRows(lChild)
+	actRight, ok3 := c.actRows(rChild)
+	estRows, estLeft, estRight := join.StatsCount(), lChild.StatsCount(), rChild.StatsCount()
+	if !ok1 || !ok2 || !ok3 || actLeft*actRight < feedbackMinRows || estRows <= 0 || estLeft <= 0 || estRight <= 0 {
+		return
+	}
+	c.observe(statistics.NewJoinFeedbackKey(lTableID, lColID, rTableID, rColID),
+		(max(actRows, 0.5)/actLeft/actRight)/(estRows/estLeft/estRight))
+}
+
+// observe learns the correction of the key from the gap between the actual and the estimated row count. The
+// estimation may have been corrected by the key, so the correction is learned against the original estimation.
+func (c *feedbackCollector) observe(key statistics.FeedbackKey, gap float64) {
+	if !statistics.IsSignificantFeedback(gap) {
+		return
+	}
+	if applied, ok := c.applied[key.String()]; ok {
+		gap *= applied
+	}
+	statistics.CardinalityFeedback.Observe(key, gap, c.statsVersion(key.TableID))
+}
+
+func (c *feedbackCollector) actRows(p PhysicalPlan) (float64, bool) {
+	id := p.ID()
+	if c.statsColl.ExistsRootStats(id) {
+		return float64(c.statsColl.GetRootStats(id).GetActRows()), true
+	}
+	if c.statsColl.ExistsCopStats(id) {
+		return float64(c.statsColl.GetCopStats(id).GetActRows()), true
+	}
+	return 0, false
+}
+
+func (c *feedbackCollector) statsVersion(tableID int64) uint64 {
+	statsHandle := domain.GetDomain(c.sctx).StatsHandle()
+	if statsHandle == nil {
+		return 0
+	}
+	tbl, ok := statsHandle.Get(tableID)
+	if !ok {
+		return 0
+	}
+	return tbl.LastAnalyzeVersion
+}
+
+// feedbackStatsTableID returns the ID of the stats used by the scan which produces the rows of the plan.
+func feedbackStatsTableID(p PhysicalPlan) (int64, bool) {
+	for p != nil {
+		switch x := p.(type) {
+		case *PhysicalTableScan:
+			if x.tblColHists == nil {
+				return 0, false
+			}
+			return x.tblColHists.PhysicalID, true
+		case *PhysicalIndexScan:
+			if x.tblColHists == nil {
+				return 0, false
+			}
+			return x.tblColHists.PhysicalID, true
+		case *PhysicalTableReader:
+			p = x.tablePlan
+		case *PhysicalIndexReader:
+			p = x.indexPlan
+		case *PhysicalIndexLookUpReader:
+			p = x.tablePlan
+		case *PhysicalSelection:
+			p = x.children[0]
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// feedbackPhysicalColumnSource returns the table and column ID of the column by the reader which produces it.
+func feedbackPhysicalColumnSource(p PhysicalPlan, col *expression.Column) (tableID, colID int64, ok bool) {
+	if col.ID <= 0 {
+		return 0, 0, false
+	}
+	for p.Schema().Contains(col) {
+		switch p.(type) {
+		case *PhysicalTableReader, *PhysicalIndexReader, *PhysicalIndexLookUpReader:
+			tableID, ok = feedbackStatsTableID(p)
+			return tableID, col.ID, ok
+		}
+		var next PhysicalPlan
+		for _, child := range p.Children() {
+			if child.Schema().Contains(col) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return 0, 0, false
+		}
+		p = next
+	}
+	return 0, 0, false
+}
+
+// feedbackLogicalColumnSource returns the table and column ID of the column by the data source which produces it.
+func feedbackLogicalColumnSource(p LogicalPlan, col *expression.Column) (tableID, colID int64, ok bool) {
+	if col.ID <= 0 {
+		return 0, 0, false
+	}
+	for p.Schema().Contains(col) {
+		if ds, isDS := p.(*DataSource); isDS {
+			return ds.tableStats.HistColl.PhysicalID, col.ID, true
+		}
+		var next LogicalPlan
+		for _, child := range p.Children() {
+			if child.Schema().Contains(col) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return 0, 0, false
+		}
+		p = next
+	}
+	return 0, 0, false
+}
+
+// adjustJoinRowCountByFeedback returns the factor to adjust the row count of the join on one equal condition by the
+// cardinality correction learned from the executions.
+func (p *LogicalJoin) adjustJoinRowCountByFeedback(leftJoinKeys, rightJoinKeys []*expression.Column) float64 {
+	if !variable.EnableCardinalityFeedback.Load() || len(leftJoinKeys) != 1 || len(rightJoinKeys) != 1 {
+		return 1
+	}
+	lTableID, lColID, ok1 := feedbackLogicalColumnSource(p.children[0], leftJoinKeys[0])
+	rTableID, rColID, ok2 := feedbackLogicalColumnSource(p.children[1], rightJoinKeys[0])
+	if !ok1 || !ok2 {
+		return 1
+	}
+	return cardinality.GetCardinalityFeedback(p.SCtx(), statistics.NewJoinFeedbackKey(lTableID, lColID, rTableID, rColID))
+}
+
+// adjustCountAfterAccessByFeedback adjusts the row count of the access conditions of a path by the cardinality
+// corrections learned from the executions.
+func adjustCountAfterAccessByFeedback(sctx PlanContext, coll *statistics.HistColl, accessConds []expression.Expression, count float64) float64 {
+	factor := cardinality.AdjustSelectivityByFeedback(sctx, coll, accessConds)
+	if factor == 1 {
+		return count
+	}
+	return min(count*factor, float64(coll.RealtimeCount))
+}
@@ -233,3 +233,37 @@ func TestPointGetIntHandleNotFirst(t *testing.T) {
 	tk.MustQuery("select * from t WHERE a BETWEEN 13 AND 13").Check(testkit.Rows("1 13 1"))
 	tk.MustQuery(`select * from t`).Check(testkit.Rows("1 13 1"))
 }
+
+func TestSubPartitionPruner(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table t (a int, b int, key (b))
+		partition by range (a) subpartition by hash (b) subpartitions 2
+		(partition p0 values less than (10),
+		 partition p1 values less than (20),
+		 partition p2 values less than (maxvalue))`)
+	tk.MustExec(`insert into t values (1, 1), (2, 2), (11, 11), (12, 12), (21, 21), (22, 22)`)
+	tk.MustExec(`analyze table t`)
+
+	tk.MustExec(`set @@tidb_partition_prune_mode = 'dynamic'`)
+	tk.MustQuery(`explain format = 'brief' select * from t where a = 12 and b = 12`).CheckAt([]int{0, 3}, testkit.RowsWithSep("|",
+		"TableReader|partition:p1sp0", "└─Selection|", "  └─TableFullScan|table:t"))
+	tk.MustQuery(`explain format = 'brief' select * from t where a < 10`).CheckAt([]int{0, 3}, testkit.RowsWithSep("|",
+		"TableReader|partition:p0sp0,p0sp1", "└─Selection|", "  └─TableFullScan|table:t"))
+	tk.MustQuery(`explain format = 'brief' select * from t where b = 21`).CheckAt([]int{0, 3}, testkit.RowsWithSep("|",
+		"TableReader|partition:p0sp1,p1sp1,p2sp1", "└─Selection|", "  └─TableFullScan|table:t"))
+	tk.MustQuery(`explain format = 'brief' select * from t partition (p1) where b in (11, 13)`).CheckAt([]int{0, 3}, testkit.RowsWithSep("|",
+		"TableReader|partition:p1sp1", "└─Selection|", "  └─TableFullScan|table:t"))
+	tk.MustQuery(`explain format = 'brief' select * from t partition (p2sp0) where a > 0`).CheckAt([]int{0, 3}, testkit.RowsWithSep("|",
+		"TableReader|partition:p2sp0", "└─Selection|", "  └─TableFullScan|table:t"))
+
+	for _, mode := range []string{"static", "dynamic"} {
+		tk.MustExec(`set @@tidb_partition_prune_mode = '` + mode + `'`)
+		tk.MustQuery(`select * from t where a = 12 and b = 12`).Check(testkit.Rows("12 12"))
+		tk.MustQuery(`select * from t where a < 10`).Sort().Check(testkit.Rows("1 1", "2 2"))
+		tk.MustQuery(`select * from t where b = 21`).Check(testkit.Rows("21 21"))
+		tk.MustQuery(`select * from t partition (p1) where b in (11, 13)`).Check(testkit.Rows("11 11"))
+		tk.MustQuery(`select * from t partition (p2sp0) where a > 0`).Check(testkit.Rows("22 22"))
+	}
+}
@@ -629,10 +629,12 @@ type PlanReplayer struct {
 type IndexAdvise struct {
 	baseSchemaProducer
 
-	IsLocal     bool
-	Path        string
-	MaxMinutes  uint64
-	MaxIndexNum *ast.MaxIndexNumClause
+	FromStmtSummary bool
+	Limit           uint64
+	IsLocal         bool
+	Path            string
+	MaxMinutes      uint64
+	MaxIndexNum     *ast.MaxIndexNumClause
 	LineFieldsInfo
 }
 
@@ -678,8 +680,11 @@ type SelectInto struct {
 	baseSchemaProducer
 
 	TargetPlan Plan
-	IntoOpt    *ast.SelectIntoOption
+	// TargetNames is the output names of TargetPlan, which are used as the column names of the output file.
+	TargetNames types.NameSlice
+	IntoOpt     *ast.SelectIntoOption
 	LineFieldsInfo
+	Options []*LoadDataOpt
 }
 
 // LineFieldsInfo used in load-data/select-into/index-advise stmt.
@@ -1523,6 +1523,10 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		withPlanCtx(func(planCtx *exprRewriterPlanCtx) {
 			er.positionToScalarFunc(planCtx, v)
 		})
+	case *ast.MatchAgainst:
+		withPlanCtx(func(planCtx *exprRewriterPlanCtx) {
+			er.matchAgainstToScalarFunc(planCtx, v)
+		})
 	case *ast.IsNullExpr:
 		er.isNullToExpression(v)
 	case *ast.IsTruthExpr:
@@ -1831,6 +1835,52 @@ func (er *expressionRewriter) positionToScalarFunc(planCtx *exprRewriterPlanCtx,
 	}
 }
 
+// matchAgainstToScalarFunc rewrites `MATCH(col) AGAINST(...)`, which requires a FULLTEXT index on the column to
+// know how to tokenize the text.
+func (er *expressionRewriter) matchAgainstToScalarFunc(planCtx *exprRewriterPlanCtx, v *ast.MatchAgainst) {
+	intest.AssertNotNil(planCtx)
+	stkLen := len(er.ctxStack)
+	colLen := len(v.ColumnNames)
+	against := er.ctxStack[stkLen-1]
+	if _, ok := against.(*expression.Constant); !ok || expression.GetRowLen(against) != 1 {
+		er.err = plannererrors.ErrWrongArguments.GenWithStackByArgs("AGAINST")
+		return
+	}
+	if colLen != 1 {
+		er.err = plannererrors.ErrFtMatchingKeyNotFound
+		return
+	}
+	col, ok := er.ctxStack[stkLen-2].(*expression.Column)
+	name := er.ctxNameStk[stkLen-2]
+	if !ok || name == nil || name.OrigTblName.L == "" {
+		er.err = plannererrors.ErrFtMatchingKeyNotFound
+		return
+	}
+	tbl, err := planCtx.builder.is.TableByName(name.DBName, name.OrigTblName)
+	if err != nil {
+		er.err = plannererrors.ErrFtMatchingKeyNotFound
+		return
+	}
+	var fullTextInfo *model.FullTextIndexInfo
+	for _, idx := range tbl.Meta().Indices {
+		if idx.FullTextInfo != nil && idx.State == model.StatePublic && idx.Columns[0].Name.L == name.OrigColName.L {
+			fullTextInfo = idx.FullTextInfo
+			break
+		}
+	}
+	if fullTextInfo == nil {
+		er.err = plannererrors.ErrFtMatchingKeyNotFound
+		return
+	}
+	function, err := expression.BuildMatchAgainstFunction(er.sctx, col, against, v.Modifier, fullTextInfo)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(colLen + 1)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 func (er *expressionRewriter) isTrueToScalarFunc(v *ast.IsTruthExpr) {
 	stkLen := len(er.ctxStack)
 	op := ast.IsTruthWithoutNull
@@ -1163,9 +1163,15 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 			// 4. The needed columns are all covered by index columns(and handleCol).
 			currentCandidate = ds.getIndexCandidate(path, prop)
 		}
+		if isVectorSearchPath(path) {
+			// A vector search path has no access conditions but only reads a few lists, so it can only be compared
+			// with the other paths by the cost.
+			candidates = append(candidates, currentCandidate)
+			continue
+		}
 		pruned := false
 		for i := len(candidates) - 1; i >= 0; i-- {
-			if candidates[i].path.StoreType == kv.TiFlash {
+			if candidates[i].path.StoreType == kv.TiFlash || isVectorSearchPath(candidates[i].path) {
 				continue
 			}
 			result := compareCandidates(ds.SCtx(), prop, candidates[i], currentCandidate)
@@ -2107,7 +2113,12 @@ func (ds *DataSource) convertToIndexScan(prop *property.PhysicalProperty,
 	}
 	// prop.IsSortItemEmpty() would always return true when coming to here,
 	// so we can just use prop.ExpectedCnt as parameter of addPushedDownSelection.
-	finalStats := ds.StatsInfo().ScaleByExpectCnt(prop.ExpectedCnt)
+	finalStats := ds.StatsInfo()
+	if isVectorSearchPath(path) && ds.tableStats.HistColl.RealtimeCount > 0 {
+		// A vector search only reads the rows in a few lists, and the filters are applied to them.
+		finalStats = finalStats.Scale(min(path.CountAfterIndex/float64(ds.tableStats.HistColl.RealtimeCount), 1))
+	}
+	finalStats = finalStats.ScaleByExpectCnt(prop.ExpectedCnt)
 	is.addPushedDownSelection(cop, ds, path, finalStats)
 	if prop.TaskTp == property.RootTaskType {
 		task = task.convertToRootTask(ds.SCtx())
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/ranger"
 	"go.uber.org/zap"
@@ -77,7 +78,7 @@ func (ds *DataSource) generateIndexMergePath() error {
 		return nil
 	}
 
-	if ds.tableInfo.TempTableType == model.TempTableLocal {
+	if ds.tableInfo.IsTempTableDataInSession() {
 		warningMsg = "IndexMerge is inapplicable or disabled. Cannot use IndexMerge on temporary table."
 		return nil
 	}
@@ -90,6 +91,9 @@ func (ds *DataSource) generateIndexMergePath() error {
 	if err := ds.generateIndexMerge4MVIndex(regularPathCount, indexMergeConds); err != nil {
 		return err
 	}
+	if err := ds.generateIndexMerge4FullTextIndex(indexMergeConds); err != nil {
+		return err
+	}
 	oldIndexMergeCount := len(ds.possibleAccessPaths)
 	if err := ds.generateIndexMerge4ComposedIndex(regularPathCount, indexMergeConds); err != nil {
 		return err
@@ -1082,6 +1086,114 @@ func (ds *DataSource) generateIndexMerge4MVIndex(normalPathCnt int, filters []ex
 	return nil
 }
 
+// generateIndexMerge4FullTextIndex generates the IndexMerge paths to access FULLTEXT indexes by `MATCH ... AGAINST`.
+// Every token of the search query is looked up in the index by a partial path, and the matched documents are the
+// union or the intersection of them. The index may find documents which don't match the query, e.g. the ones which
+// don't contain a phrase, so all the filters are kept as table filters.
+func (ds *DataSource) generateIndexMerge4FullTextIndex(filters []expression.Expression) error {
+	for _, idx := range ds.tableInfo.Indices {
+		if idx.FullTextInfo == nil || idx.State != model.StatePublic {
+			continue
+		}
+		if idx.Invisible && !ds.SCtx().GetSessionVars().OptimizerUseInvisibleIndexes {
+			continue
+		}
+		if !ds.isInIndexMergeHints(idx.Name.L) {
+			continue
+		}
+		idxCols, ok := PrepareIdxColsAndUnwrapArrayType(ds.tableInfo, idx, ds.TblCols, false)
+		if !ok {
+			continue
+		}
+		for _, filter := range filters {
+			sf, ok := filter.(*expression.ScalarFunction)
+			if !ok || sf.FuncName.L != ast.FTSMatchAgainst {
+				continue
+			}
+			col, query, ok := expression.ExtractMatchAgainst(ds.SCtx().GetExprCtx().GetEvalCtx(), sf, idx.FullTextInfo)
+			if !ok || !col.EqualColumn(idxCols[0]) {
+				continue
+			}
+			tokens, isIntersection := query.IndexAccess()
+			if len(tokens) == 0 {
+				continue
+			}
+			if expression.MaybeOverOptimized4PlanCache(ds.SCtx().GetExprCtx(), sf.GetArgs()[1:2]) {
+				ds.SCtx().GetSessionVars().StmtCtx.SetSkipPlanCache(errors.NewNoStackError("MATCH ... AGAINST with a parameter is used to access the FULLTEXT index"))
+			}
+			partialPaths := make([]*util.AccessPath, 0, len(tokens))
+			for _, token := range tokens {
+				partialPath, ok, err := buildPartialPath4FullTextIndex(ds.SCtx(), token, idxCols[0], idx, ds.tableStats.HistColl)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					partialPaths = nil
+					break
+				}
+				partialPaths = append(partialPaths, partialPath)
+			}
+			if len(partialPaths) == 0 {
+				continue
+			}
+			isIntersection = isIntersection && len(partialPaths) > 1
+			indexMergePath := &util.AccessPath{PartialIndexPaths: partialPaths, IndexMergeIsIntersection: isIntersection}
+			indexMergePath.TableFilters = filters
+			indexMergePath.CountAfterAccess = float64(ds.tableStats.HistColl.RealtimeCount) *
+				cardinality.CalcTotalSelectivityForMVIdxPath(ds.tableStats.HistColl, partialPaths, isIntersection)
+			ds.possibleAccessPaths = append(ds.possibleAccessPaths, indexMergePath)
+		}
+	}
+	return nil
+}
+
+// buildPartialPath4FullTextIndex builds a partial path on the FULLTEXT index to look up the token.
+func buildPartialPath4FullTextIndex(
+	sctx context.PlanContext,
+	token fulltext.AccessToken,
+	idxCol *expression.Column,
+	idx *model.IndexInfo,
+	histColl *statistics.HistColl,
+) (*util.AccessPath, bool, error) {
+	accessFilter, err := buildFullTextAccessFilter(sctx, idxCol, token)
+	if err != nil {
+		return nil, false, err
+	}
+	partialPath := &util.AccessPath{Index: idx}
+	partialPath.Ranges = ranger.FullRange()
+	partialPath.IdxCols = []*expression.Column{idxCol}
+	partialPath.IdxColLens = []int{idx.Columns[0].Length}
+	partialPath.FullIdxCols = []*expression.Column{idxCol}
+	partialPath.FullIdxColLens = []int{idx.Columns[0].Length}
+	if err := detachCondAndBuildRangeForPath(sctx, partialPath, []expression.Expression{accessFilter}, histColl); err != nil {
+		return nil, false, err
+	}
+	if len(partialPath.AccessConds) != 1 {
+		return nil, false, nil
+	}
+	// For PAD SPACE collations, the LIKE filter of a token prefix is kept to check the trailing spaces, which is
+	// unnecessary because the tokens never have spaces.
+	partialPath.TableFilters = nil
+	return partialPath, true, nil
+}
+
+// buildFullTextAccessFilter builds the filter to look up the token in the FULLTEXT index on col, which is `col = token`,
+// or `col LIKE 'token%'` for a token prefix.
+func buildFullTextAccessFilter(sctx context.PlanContext, col *expression.Column, token fulltext.AccessToken) (expression.Expression, error) {
+	tp := types.NewFieldType(mysql.TypeVarString)
+	tp.SetCharset(col.GetType().GetCharset())
+	tp.SetCollate(col.GetType().GetCollate())
+	if !token.Prefix {
+		val := &expression.Constant{Value: types.NewCollationStringDatum(token.Token, tp.GetCollate()), RetType: tp}
+		return expression.NewFunction(sctx.GetExprCtx(), ast.EQ, types.NewFieldType(mysql.TypeTiny), col, val)
+	}
+	// Underscores are the only wildcards which may appear in the tokens.
+	pattern := strings.ReplaceAll(token.Token, "_", `\_`) + "%"
+	val := &expression.Constant{Value: types.NewCollationStringDatum(pattern, tp.GetCollate()), RetType: tp}
+	escape := &expression.Constant{Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}
+	return expression.NewFunction(sctx.GetExprCtx(), ast.Like, types.NewFieldType(mysql.TypeTiny), col, val, escape)
+}
+
 // buildPartialPathUp4MVIndex builds these partial paths up to a complete index merge path.
 func (*DataSource) buildPartialPathUp4MVIndex(
 	partialPaths []*util.AccessPath,
@@ -2104,11 +2104,19 @@ func (b *PlanBuilder) buildSemiJoinForSetOperator(
 	joinPlan.SetSchema(leftPlan.Schema())
 	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len())
 	copy(joinPlan.names, leftPlan.OutputNames())
-	for j := 0; j < len(rightPlan.Schema().Columns); j++ {
-		leftCol, rightCol := leftPlan.Schema().Columns[j], rightPlan.Schema().Columns[j]
+	if err = b.buildJoinConds4SetOperator(joinPlan, leftPlan.Schema().Columns, rightPlan.Schema().Columns); err != nil {
+		return nil, err
+	}
+	return joinPlan, nil
+}
+
+// buildJoinConds4SetOperator builds the null-safe equal conditions between the columns of two set operator children.
+func (b *PlanBuilder) buildJoinConds4SetOperator(joinPlan *LogicalJoin, leftCols, rightCols []*expression.Column) error {
+	for j := 0; j < len(rightCols); j++ {
+		leftCol, rightCol := leftCols[j], rightCols[j]
 		eqCond, err := expression.NewFunction(b.ctx.GetExprCtx(), ast.NullEQ, types.NewFieldType(mysql.TypeTiny), leftCol, rightCol)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		_, leftArgIsColumn := eqCond.(*expression.ScalarFunction).GetArgs()[0].(*expression.Column)
 		_, rightArgIsColumn := eqCond.(*expression.ScalarFunction).GetArgs()[1].(*expression.Column)
@@ -2118,7 +2126,145 @@ func (b *PlanBuilder) buildSemiJoinForSetOperator(
 			joinPlan.EqualConditions = append(joinPlan.EqualConditions, eqCond.(*expression.ScalarFunction))
 		}
 	}
-	return joinPlan, nil
+	return nil
+}
+
+// buildCountedSemiJoinForSetOperator builds the set operators 'intersect all' and 'except all', which keep the
+// duplicated rows. The rows of each side are numbered by `row_number()` among their duplicates, so that the n-th
+// duplicate of a left row can only be matched by the n-th duplicate of the same row on the right side. Then, for a row
+// which has m duplicates on the left side and n duplicates on the right side,
+//   - 'intersect all' is built as a semi join, which keeps min(m, n) duplicates,
+//   - 'except all' is built as an anti semi join, which keeps max(m-n, 0) duplicates.
+func (b *PlanBuilder) buildCountedSemiJoinForSetOperator(
+	leftOriginPlan LogicalPlan,
+	rightOriginPlan LogicalPlan,
+	joinType JoinType) (LogicalPlan, error) {
+	// Cast both sides to the types which can carry them, as what UNION does, so the rows are numbered by the same
+	// equality as they are compared by the join, and no value is truncated by the cast.
+	opName := "INTERSECT"
+	if joinType == AntiSemiJoin {
+		opName = "EXCEPT"
+	}
+	targetTps, err := b.unionFieldTypes4SetOperator(leftOriginPlan, rightOriginPlan, opName)
+	if err != nil {
+		return nil, err
+	}
+	leftOriginPlan = b.buildCast4SetOperator(leftOriginPlan, targetTps)
+	rightOriginPlan = b.buildCast4SetOperator(rightOriginPlan, targetTps)
+	leftPlan, err := b.buildRowNumber4SetOperator(leftOriginPlan)
+	if err != nil {
+		return nil, err
+	}
+	rightPlan, err := b.buildRowNumber4SetOperator(rightOriginPlan)
+	if err != nil {
+		return nil, err
+	}
+	joinPlan := LogicalJoin{JoinType: joinType}.Init(b.ctx, b.getSelectOffset())
+	joinPlan.SetChildren(leftPlan, rightPlan)
+	joinPlan.SetSchema(leftPlan.Schema())
+	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len())
+	copy(joinPlan.names, leftPlan.OutputNames())
+	if err = b.buildJoinConds4SetOperator(joinPlan, leftPlan.Schema().Columns, rightPlan.Schema().Columns); err != nil {
+		return nil, err
+	}
+
+	// Remove the row number column.
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(leftOriginPlan.Schema().Columns)}.Init(b.ctx, b.getSelectOffset())
+	proj.SetChildren(joinPlan)
+	schema := leftOriginPlan.Schema().Clone()
+	for _, col := range schema.Columns {
+		col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+	}
+	proj.SetSchema(schema)
+	proj.names = leftOriginPlan.OutputNames()
+	return proj, nil
+}
+
+// unionFieldTypes4SetOperator infers the result types of a set operator by the schemas of its two sides, in the same
+// way as buildProjection4Union.
+func (b *PlanBuilder) unionFieldTypes4SetOperator(left, right LogicalPlan, opName string) ([]*types.FieldType, error) {
+	tps := make([]*types.FieldType, 0, left.Schema().Len())
+	for i, leftCol := range left.Schema().Columns {
+		rightCol := right.Schema().Columns[i]
+		tmpExprs := []expression.Expression{leftCol, rightCol}
+		resultTp := unionJoinFieldType(leftCol.RetType, rightCol.RetType).Clone()
+		collation, err := expression.CheckAndDeriveCollationFromExprs(b.ctx.GetExprCtx(), opName, resultTp.EvalType(), tmpExprs...)
+		if err != nil || collation.Coer == expression.CoercibilityNone {
+			return nil, collate.ErrIllegalMixCollation.GenWithStackByArgs(opName)
+		}
+		resultTp.SetCharset(collation.Charset)
+		resultTp.SetCollate(collation.Collation)
+		b.setUnionFlen(resultTp, tmpExprs)
+		tps = append(tps, resultTp)
+	}
+	return tps, nil
+}
+
+// buildCast4SetOperator casts the columns of p to the target types if they are different.
+func (b *PlanBuilder) buildCast4SetOperator(p LogicalPlan, targetTps []*types.FieldType) LogicalPlan {
+	needCast := false
+	exprs := make([]expression.Expression, 0, p.Schema().Len())
+	for i, col := range p.Schema().Columns {
+		tp := targetTps[i]
+		if col.RetType.Equal(tp) {
+			exprs = append(exprs, col)
+			continue
+		}
+		needCast = true
+		exprs = append(exprs, expression.BuildCastFunction4Union(b.ctx.GetExprCtx(), col, tp.Clone()))
+	}
+	if !needCast {
+		return p
+	}
+	proj := LogicalProjection{Exprs: exprs}.Init(b.ctx, b.getSelectOffset())
+	proj.SetChildren(p)
+	schema := make([]*expression.Column, 0, len(exprs))
+	for _, expr := range exprs {
+		schema = append(schema, &expression.Column{
+			RetType:  expr.GetType(),
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		})
+	}
+	proj.SetSchema(expression.NewSchema(schema...))
+	proj.names = p.OutputNames()
+	return proj
+}
+
+// buildRowNumber4SetOperator appends a `row_number() over (partition by <all columns>)` column to p, which numbers
+// the rows among their duplicates.
+func (b *PlanBuilder) buildRowNumber4SetOperator(p LogicalPlan) (LogicalPlan, error) {
+	desc, err := aggregation.NewWindowFuncDesc(b.ctx.GetExprCtx(), ast.WindowFuncRowNumber, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	partitionBy := make([]property.SortItem, 0, p.Schema().Len())
+	for _, col := range p.Schema().Columns {
+		partitionBy = append(partitionBy, property.SortItem{Col: col})
+	}
+	window := LogicalWindow{
+		WindowFuncDescs: []*aggregation.WindowFuncDesc{desc},
+		PartitionBy:     partitionBy,
+	}.Init(b.ctx, b.getSelectOffset())
+	if b.ctx.GetSessionVars().EnablePipelinedWindowExec {
+		// Keep the same frame as `row_number()` written by users, see `handleDefaultFrame`.
+		_, defaultFrame := aggregation.UseDefaultFrame(ast.WindowFuncRowNumber)
+		window.Frame = &WindowFrame{
+			Type:  defaultFrame.Type,
+			Start: &FrameBound{Type: defaultFrame.Extent.Start.Type},
+			End:   &FrameBound{Type: defaultFrame.Extent.End.Type},
+		}
+	}
+	schema := p.Schema().Clone()
+	schema.Append(&expression.Column{
+		UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  desc.RetTp,
+	})
+	window.SetChildren(p)
+	window.SetSchema(schema)
+	window.names = make([]*types.FieldName, 0, schema.Len())
+	window.names = append(window.names, p.OutputNames()...)
+	window.names = append(window.names, types.EmptyName)
+	return window, nil
 }
 
 // buildIntersect build the set operator for 'intersect'. It is called before buildExcept and buildUnion because of its
@@ -2145,18 +2291,13 @@ func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []ast.Node) (L
 	columnNums := leftPlan.Schema().Len()
 	for i := 1; i < len(selects); i++ {
 		var rightPlan LogicalPlan
+		var isAll bool
 		switch x := selects[i].(type) {
 		case *ast.SelectStmt:
-			if *x.AfterSetOperator == ast.IntersectAll {
-				// TODO: support intersect all
-				return nil, nil, errors.Errorf("TiDB do not support intersect all")
-			}
+			isAll = *x.AfterSetOperator == ast.IntersectAll
 			rightPlan, err = b.buildSelect(ctx, x)
 		case *ast.SetOprSelectList:
-			if *x.AfterSetOperator == ast.IntersectAll {
-				// TODO: support intersect all
-				return nil, nil, errors.Errorf("TiDB do not support intersect all")
-			}
+			isAll = *x.AfterSetOperator == ast.IntersectAll
 			rightPlan, err = b.buildSetOpr(ctx, &ast.SetOprStmt{SelectList: x, With: x.With, Limit: x.Limit, OrderBy: x.OrderBy})
 		}
 		if err != nil {
@@ -2165,7 +2306,11 @@ func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []ast.Node) (L
 		if rightPlan.Schema().Len() != columnNums {
 			return nil, nil, plannererrors.ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
 		}
-		leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		if isAll {
+			leftPlan, err = b.buildCountedSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		} else {
+			leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		}
 		if err != nil {
 			return nil, nil, err
 		}
@@ -2184,20 +2329,21 @@ func (b *PlanBuilder) buildExcept(ctx context.Context, selects []LogicalPlan, af
 		if rightPlan.Schema().Len() != columnNums {
 			return nil, plannererrors.ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
 		}
-		if *afterSetOpts[i] == ast.Except {
+		if *afterSetOpts[i] == ast.Except || *afterSetOpts[i] == ast.ExceptAll {
 			leftPlan, err := b.buildUnion(ctx, unionPlans, tmpAfterSetOpts)
 			if err != nil {
 				return nil, err
 			}
-			leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			if *afterSetOpts[i] == ast.ExceptAll {
+				leftPlan, err = b.buildCountedSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			} else {
+				leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			}
 			if err != nil {
 				return nil, err
 			}
 			unionPlans = []LogicalPlan{leftPlan}
 			tmpAfterSetOpts = []*ast.SetOprType{nil}
-		} else if *afterSetOpts[i] == ast.ExceptAll {
-			// TODO: support except all.
-			return nil, errors.Errorf("TiDB do not support except all")
 		} else {
 			unionPlans = append(unionPlans, rightPlan)
 			tmpAfterSetOpts = append(tmpAfterSetOpts, afterSetOpts[i])
@@ -2671,6 +2817,16 @@ func (a *havingWindowAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, o
 		if a.inWindowSpec {
 			a.popCurClause()
 		}
+	case *ast.MatchAgainst:
+		// The columns of MATCH aren't ColumnNameExpr, so add them to the select fields here to make them available in
+		// the ORDER BY clause.
+		if a.curClause == orderByClause && !a.inAggFunc && !a.inWindowFunc {
+			for _, colName := range v.ColumnNames {
+				if _, a.err = a.resolveFromPlan(&ast.ColumnNameExpr{Name: colName}, a.p, false); a.err != nil {
+					return node, false
+				}
+			}
+		}
 	case *ast.ColumnNameExpr:
 		resolveFieldsFirst := true
 		if a.inAggFunc || a.inWindowFunc || a.inWindowSpec || (a.curClause == orderByClause && a.inExpr) || a.curClause == fieldList {
@@ -4929,6 +5085,10 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 							break
 						}
 					}
+					// FULLTEXT indexes aren't in the possible paths, see generateIndexMerge4FullTextIndex.
+					if idx := tableInfo.FindIndexByName(idxName.L); idx != nil && idx.FullTextInfo != nil {
+						hasIdxName = true
+					}
 					if !hasIdxName {
 						invalidIdxNames = append(invalidIdxNames, idxName.String())
 					}
@@ -5065,7 +5225,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 
 	var result LogicalPlan = ds
 	dirty := tableHasDirtyContent(b.ctx, tableInfo)
-	if dirty || tableInfo.TempTableType == model.TempTableLocal || tableInfo.TableCacheStatusType == model.TableCacheStatusEnable {
+	if dirty || tableInfo.IsTempTableDataInSession() || tableInfo.TableCacheStatusType == model.TableCacheStatusEnable {
 		us := LogicalUnionScan{handleCols: handleCols}.Init(b.ctx, b.getSelectOffset())
 		us.SetChildren(ds)
 		if tableInfo.Partition != nil && b.optFlag&flagPartitionProcessor == 0 {
@@ -5953,7 +6113,7 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		foundListItem := false
 		for _, tl := range tableList {
 			if (tl.Schema.L == "" || tl.Schema.L == name.DBName.L) && (tl.Name.L == name.TblName.L) {
-				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() {
+				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() || b.isReadOnlyMView(tl.TableInfo) {
 					return nil, nil, false, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(name.TblName.O, "UPDATE")
 				}
 				foundListItem = true
@@ -6275,6 +6435,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan
 			if tn.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now", tn.Name.O)
 			}
+			if b.isReadOnlyMView(tn.TableInfo) {
+				return nil, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(tn.Name.O, "DELETE")
+			}
 			if sessionVars.User != nil {
 				authErr = plannererrors.ErrTableaccessDenied.FastGenByArgs("DELETE", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tb.Name.L)
 			}
@@ -6294,6 +6457,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan
 			if v.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now", v.Name.O)
 			}
+			if b.isReadOnlyMView(v.TableInfo) {
+				return nil, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(v.Name.O, "DELETE")
+			}
 			dbName := v.Schema.L
 			if dbName == "" {
 				dbName = b.ctx.GetSessionVars().CurrentDB
@@ -6517,7 +6683,7 @@ func (b *PlanBuilder) buildByItemsForWindow(
 }
 
 // buildWindowFunctionFrameBound builds the bounds of window function frames.
-// For type `Rows`, the bound expr must be an unsigned integer.
+// For type `Rows` and `Groups`, the bound expr must be an unsigned integer.
 // For type `Range`, the bound expr must be temporal or numeric types.
 func (b *PlanBuilder) buildWindowFunctionFrameBound(_ context.Context, spec *ast.WindowSpec, orderByItems []property.SortItem, boundClause *ast.FrameBound) (*FrameBound, error) {
 	frameType := spec.Frame.Type
@@ -6526,7 +6692,7 @@ func (b *PlanBuilder) buildWindowFunctionFrameBound(_ context.Context, spec *ast
 		return bound, nil
 	}
 
-	if frameType == ast.Rows {
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Type == ast.CurrentRow {
 			return bound, nil
 		}
@@ -6634,9 +6800,6 @@ func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.Wi
 func (b *PlanBuilder) checkWindowFuncArgs(ctx context.Context, p LogicalPlan, windowFuncExprs []*ast.WindowFuncExpr, windowAggMap map[*ast.AggregateFuncExpr]int) error {
 	checker := &expression.ParamMarkerInPrepareChecker{}
 	for _, windowFuncExpr := range windowFuncExprs {
-		if strings.ToLower(windowFuncExpr.Name) == ast.AggFuncGroupConcat {
-			return plannererrors.ErrNotSupportedYet.GenWithStackByArgs("group_concat as window function")
-		}
 		args, err := b.buildArgs4WindowFunc(ctx, p, windowFuncExpr.Args, windowAggMap)
 		if err != nil {
 			return err
@@ -6772,6 +6935,7 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 				return nil, nil, plannererrors.ErrWrongArguments.GenWithStackByArgs(strings.ToLower(windowFunc.Name))
 			}
 			preArgs += len(windowFunc.Args)
+			desc.IgnoreNull, desc.FromLast, desc.HasDistinct = windowFunc.IgnoreNull, windowFunc.FromLast, windowFunc.Distinct
 			desc.WrapCastForAggArgs(b.ctx.GetExprCtx())
 			descs = append(descs, desc)
 			windowMap[windowFunc] = schema.Len()
@@ -6793,15 +6957,6 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 // Because the grouped specification is different from them, we should especially check them before build window frame.
 func (b *PlanBuilder) checkOriginWindowFuncs(funcs []*ast.WindowFuncExpr, orderByItems []property.SortItem) error {
 	for _, f := range funcs {
-		if f.IgnoreNull {
-			return plannererrors.ErrNotSupportedYet.GenWithStackByArgs("IGNORE NULLS")
-		}
-		if f.Distinct {
-			return plannererrors.ErrNotSupportedYet.GenWithStackByArgs("<window function>(DISTINCT ..)")
-		}
-		if f.FromLast {
-			return plannererrors.ErrNotSupportedYet.GenWithStackByArgs("FROM LAST")
-		}
 		spec := &f.Spec
 		if f.Spec.Name.L != "" {
 			spec = b.windowSpecs[f.Spec.Name.L]
@@ -6818,9 +6973,6 @@ func (b *PlanBuilder) checkOriginWindowSpec(spec *ast.WindowSpec, orderByItems [
 	if spec.Frame == nil {
 		return nil
 	}
-	if spec.Frame.Type == ast.Groups {
-		return plannererrors.ErrNotSupportedYet.GenWithStackByArgs("GROUPS")
-	}
 	start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
 	if start.Type == ast.Following && start.UnBounded {
 		return plannererrors.ErrWindowFrameStartIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
@@ -6852,7 +7004,9 @@ func (b *PlanBuilder) checkOriginWindowFrameBound(bound *ast.FrameBound, spec *a
 	}
 
 	frameType := spec.Frame.Type
-	if frameType == ast.Rows {
+	// The offset of ROWS and GROUPS frames counts rows and peer groups respectively,
+	// so it must be an unsigned integer.
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Unit != ast.TimeUnitInvalid {
 			return plannererrors.ErrWindowRowsIntervalUse.GenWithStackByArgs(getWindowName(spec.Name.O))
 		}
@@ -1487,6 +1487,12 @@ type DataSource struct {
 	// It's calculated after we generated the access paths and estimated row count for them, and before entering findBestTask.
 	// It considers CountAfterIndex for index paths and CountAfterAccess for table paths and index merge paths.
 	accessPathMinSelectivity float64
+
+	// vectorSearchOrder is the order of the TopN pushed down to the data source, which may be a vector search.
+	// See generateVectorSearchPath.
+	vectorSearchOrder *util.ByItems
+	// vectorSearchCount is the number of rows required by the TopN, including the offset.
+	vectorSearchCount uint64
 }
 
 // ExtractCorrelatedCols implements LogicalPlan interface.
@@ -1648,7 +1654,11 @@ func detachCondAndBuildRangeForPath(
 		}
 	}
 	path.CountAfterAccess, err = cardinality.GetRowCountByIndexRanges(sctx, histColl, path.Index.ID, path.Ranges)
-	return err
+	if err != nil {
+		return err
+	}
+	path.CountAfterAccess = adjustCountAfterAccessByFeedback(sctx, histColl, path.AccessConds, path.CountAfterAccess)
+	return nil
 }
 
 func (ds *DataSource) deriveCommonHandleTablePathStats(path *util.AccessPath, conds []expression.Expression, isIm bool) error {
@@ -1767,6 +1777,10 @@ func (ds *DataSource) deriveTablePathStats(path *util.AccessPath, conds []expres
 		return err
 	}
 	path.CountAfterAccess, err = cardinality.GetRowCountByIntColumnRanges(ds.SCtx(), &ds.statisticTable.HistColl, pkCol.ID, path.Ranges)
+	if err != nil {
+		return err
+	}
+	path.CountAfterAccess = adjustCountAfterAccessByFeedback(ds.SCtx(), &ds.statisticTable.HistColl, path.AccessConds, path.CountAfterAccess)
 	// If the `CountAfterAccess` is less than `stats.RowCount`, there must be some inconsistent stats info.
 	// We prefer the `stats.RowCount` because it could use more stats info to calculate the selectivity.
 	if path.CountAfterAccess < ds.StatsInfo().RowCount && !isIm {
@@ -2241,6 +2255,9 @@ type ShowContents struct {
 	Tp                ast.ShowStmtType // Databases/Tables/Columns/....
 	DBName            string
 	Table             *ast.TableName  // Used for showing columns.
+	Procedure         *ast.TableName  // Used for showing create procedure.
+	Trigger           *ast.TableName  // Used for showing create trigger.
+	Event             *ast.TableName  // Used for showing create event.
 	Partition         model.CIStr     // Use for showing partition
 	Column            *ast.ColumnName // Used for `desc table column`.
 	IndexName         model.CIStr
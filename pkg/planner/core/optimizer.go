@@ -70,6 +70,7 @@ var IsReadOnly func(node ast.Node, vars *variable.SessionVars) bool
 const (
 	flagGcSubstitute uint64 = 1 << iota
 	flagPrunColumns
+	flagMaterializedViewRewrite
 	flagStabilizeResults
 	flagBuildKeyInfo
 	flagDecorrelate
@@ -97,6 +98,7 @@ const (
 var optRuleList = []logicalOptRule{
 	&gcSubstituter{},
 	&columnPruner{},
+	&materializedViewRewriter{},
 	&resultReorder{},
 	&buildKeySolver{},
 	&decorrelateSolver{},
@@ -321,6 +323,9 @@ func adjustOptimizationFlags(flag uint64, logic LogicalPlan) uint64 {
 	if checkStableResultMode(logic.SCtx()) {
 		flag |= flagStabilizeResults
 	}
+	if sessVars := logic.SCtx().GetSessionVars(); sessVars.EnableMaterializedViewRewrite && !sessVars.InRestrictedSQL {
+		flag |= flagMaterializedViewRewrite
+	}
 	if logic.SCtx().GetSessionVars().StmtCtx.StraightJoinOrder {
 		// When we use the straight Join Order hint, we should disable the join reorder optimization.
 		flag &= ^flagJoinReOrder
@@ -28,6 +28,9 @@ func PartitionPruning(ctx PlanContext, tbl table.PartitionedTable, conds []expre
 	columns []*expression.Column, names types.NameSlice) ([]int, error) {
 	s := partitionProcessor{}
 	pi := tbl.Meta().Partition
+	if pi.Sub != nil {
+		return s.pruneSubPartitionedTable(ctx, tbl, partitionNames, conds, columns, names)
+	}
 	switch pi.Type {
 	case model.PartitionTypeHash, model.PartitionTypeKey:
 		return s.pruneHashOrKeyPartition(ctx, tbl, partitionNames, conds, columns, names)
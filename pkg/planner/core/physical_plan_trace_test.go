@@ -160,7 +160,7 @@ func TestPhysicalOptimizerTrace(t *testing.T) {
 	require.NoError(t, err)
 	flag := uint64(0)
 	// flagGcSubstitute | flagStabilizeResults | flagSkewDistinctAgg | flagEliminateOuterJoin | flagPushDownAgg
-	flag |= flag | 1<<1 | 1<<3 | 1<<8 | 1<<13 | 1<<16
+	flag |= flag | 1<<1 | 1<<4 | 1<<9 | 1<<14 | 1<<17
 	_, _, err = core.DoOptimize(context.TODO(), sctx, flag, plan.(core.LogicalPlan))
 	require.NoError(t, err)
 	otrace := sctx.GetSessionVars().StmtCtx.OptimizeTracer.Physical
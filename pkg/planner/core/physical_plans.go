@@ -16,6 +16,7 @@ package core
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -149,6 +150,17 @@ type PhysPlanPartInfo struct {
 	ColumnNames    types.NameSlice
 }
 
+// Clone clones the PhysPlanPartInfo.
+func (pi *PhysPlanPartInfo) Clone() PhysPlanPartInfo {
+	cloned := PhysPlanPartInfo{
+		PruningConds:   util.CloneExprs(pi.PruningConds),
+		PartitionNames: slices.Clone(pi.PartitionNames),
+		Columns:        util.CloneCols(pi.Columns),
+		ColumnNames:    slices.Clone(pi.ColumnNames),
+	}
+	return cloned
+}
+
 const emptyPartitionInfoSize = int64(unsafe.Sizeof(PhysPlanPartInfo{}))
 
 // MemoryUsage return the memory usage of PhysPlanPartInfo
@@ -267,6 +279,7 @@ func (p *PhysicalTableReader) Clone() (PhysicalPlan, error) {
 	cloned.StoreType = p.StoreType
 	cloned.ReadReqType = p.ReadReqType
 	cloned.IsCommonHandle = p.IsCommonHandle
+	cloned.PlanPartInfo = p.PlanPartInfo.Clone()
 	if cloned.tablePlan, err = p.tablePlan.Clone(); err != nil {
 		return nil, err
 	}
@@ -329,10 +342,10 @@ func (p *PhysicalIndexReader) Clone() (PhysicalPlan, error) {
 	if cloned.indexPlan, err = p.indexPlan.Clone(); err != nil {
 		return nil, err
 	}
-	if cloned.IndexPlans, err = clonePhysicalPlan(p.IndexPlans); err != nil {
-		return nil, err
-	}
+	// IndexPlans are actually the flattened plans in indexPlan, so can't copy them, just need to extract from indexPlan
+	cloned.IndexPlans = flattenPushDownPlan(cloned.indexPlan)
 	cloned.OutputColumns = util.CloneCols(p.OutputColumns)
+	cloned.PlanPartInfo = p.PlanPartInfo.Clone()
 	return cloned, err
 }
 
@@ -465,18 +478,19 @@ func (p *PhysicalIndexLookUpReader) Clone() (PhysicalPlan, error) {
 		return nil, err
 	}
 	cloned.physicalSchemaProducer = *base
-	if cloned.IndexPlans, err = clonePhysicalPlan(p.IndexPlans); err != nil {
-		return nil, err
-	}
-	if cloned.TablePlans, err = clonePhysicalPlan(p.TablePlans); err != nil {
-		return nil, err
-	}
 	if cloned.indexPlan, err = p.indexPlan.Clone(); err != nil {
 		return nil, err
 	}
 	if cloned.tablePlan, err = p.tablePlan.Clone(); err != nil {
 		return nil, err
 	}
+	// IndexPlans and TablePlans are the flattened plans in indexPlan and tablePlan, extract them from the cloned trees.
+	cloned.IndexPlans = flattenPushDownPlan(cloned.indexPlan)
+	cloned.TablePlans = flattenPushDownPlan(cloned.tablePlan)
+	cloned.Paging = p.Paging
+	cloned.PlanPartInfo = p.PlanPartInfo.Clone()
+	cloned.expectedCnt = p.expectedCnt
+	cloned.keepOrder = p.keepOrder
 	if p.ExtraHandleCol != nil {
 		cloned.ExtraHandleCol = p.ExtraHandleCol.Clone().(*expression.Column)
 	}
@@ -1264,6 +1278,7 @@ func (p *basePhysicalJoin) cloneWithSelf(newSelf PhysicalPlan) (*basePhysicalJoi
 	cloned.RightConditions = util.CloneExprs(p.RightConditions)
 	cloned.OtherConditions = util.CloneExprs(p.OtherConditions)
 	cloned.InnerChildIdx = p.InnerChildIdx
+	cloned.IsNullEQ = slices.Clone(p.IsNullEQ)
 	cloned.OuterJoinKeys = util.CloneCols(p.OuterJoinKeys)
 	cloned.InnerJoinKeys = util.CloneCols(p.InnerJoinKeys)
 	cloned.LeftJoinKeys = util.CloneCols(p.LeftJoinKeys)
@@ -1368,6 +1383,8 @@ func (p *PhysicalHashJoin) Clone() (PhysicalPlan, error) {
 	cloned.basePhysicalJoin = *base
 	cloned.Concurrency = p.Concurrency
 	cloned.UseOuterToBuild = p.UseOuterToBuild
+	cloned.storeTp = p.storeTp
+	cloned.mppShuffleJoin = p.mppShuffleJoin
 	for _, c := range p.EqualConditions {
 		cloned.EqualConditions = append(cloned.EqualConditions, c.Clone().(*expression.ScalarFunction))
 	}
@@ -1835,6 +1852,7 @@ func (p *PhysicalUnionAll) Clone() (PhysicalPlan, error) {
 		return nil, err
 	}
 	cloned.physicalSchemaProducer = *base
+	cloned.mpp = p.mpp
 	return cloned, nil
 }
 
@@ -1892,6 +1910,10 @@ func (p *basePhysicalAgg) cloneWithSelf(newSelf PhysicalPlan) (*basePhysicalAgg,
 		cloned.AggFuncs = append(cloned.AggFuncs, aggDesc.Clone())
 	}
 	cloned.GroupByItems = util.CloneExprs(p.GroupByItems)
+	cloned.MppRunMode = p.MppRunMode
+	for _, col := range p.MppPartitionCols {
+		cloned.MppPartitionCols = append(cloned.MppPartitionCols, col.Clone())
+	}
 	return cloned, nil
 }
 
@@ -2187,6 +2209,7 @@ func (p *PhysicalSelection) Clone() (PhysicalPlan, error) {
 	}
 	cloned.basePhysicalPlan = *base
 	cloned.Conditions = util.CloneExprs(p.Conditions)
+	cloned.fromDataSource = p.fromDataSource
 	return cloned, nil
 }
 
@@ -267,8 +267,16 @@ func getCachedPlan(sctx sessionctx.Context, isNonPrepared bool, cacheKey kvcache
 	stmtCtx := sessVars.StmtCtx
 
 	candidate, exist := sctx.GetSessionPlanCache().Get(cacheKey, matchOpts)
+	fromInstanceCache := false
 	if !exist {
-		return nil, nil, false, nil
+		// try the plans shared by other sessions, the instance plan cache returns a copy bound to this session.
+		if instanceCache := getInstancePlanCache(sctx); instanceCache != nil {
+			candidate, exist = instanceCache.Get(sctx, cacheKey, matchOpts)
+			fromInstanceCache = exist
+		}
+		if !exist {
+			return nil, nil, false, nil
+		}
 	}
 	cachedVal := candidate.(*PlanCacheValue)
 	if err := CheckPreparedPriv(sctx, stmt, is); err != nil {
@@ -278,7 +286,10 @@ func getCachedPlan(sctx sessionctx.Context, isNonPrepared bool, cacheKey kvcache
 		if !unionScan && tableHasDirtyContent(sctx.GetPlanCtx(), tblInfo) {
 			// TODO we can inject UnionScan into cached plan to avoid invalidating it, though
 			// rebuilding the filters in UnionScan is pretty trivial.
-			sctx.GetSessionPlanCache().Delete(cacheKey)
+			if !fromInstanceCache {
+				// the shared plans are still valid for the other sessions, so only delete the session's plan.
+				sctx.GetSessionPlanCache().Delete(cacheKey)
+			}
 			return nil, nil, false, nil
 		}
 	}
@@ -296,6 +307,10 @@ func getCachedPlan(sctx sessionctx.Context, isNonPrepared bool, cacheKey kvcache
 	} else {
 		core_metrics.GetPlanCacheHitCounter(isNonPrepared).Inc()
 	}
+	if fromInstanceCache && stmt.PlanDigest == nil {
+		// the plan is generated by another session.
+		stmt.NormalizedPlan, stmt.PlanDigest = NormalizePlan(cachedVal.Plan)
+	}
 	stmtCtx.SetPlanDigest(stmt.NormalizedPlan, stmt.PlanDigest)
 	stmtCtx.StmtHints = *cachedVal.stmtHints
 	return cachedVal.Plan, cachedVal.OutPutNames, true, nil
@@ -344,12 +359,44 @@ func generateNewPlan(ctx context.Context, sctx sessionctx.Context, isNonPrepared
 		stmt.NormalizedPlan, stmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlan(p)
 		stmtCtx.SetPlanDigest(stmt.NormalizedPlan, stmt.PlanDigest)
-		sctx.GetSessionPlanCache().Put(cacheKey, cached, matchOpts)
+		if !putToInstancePlanCache(sctx, stmt, cacheKey, cached, matchOpts) {
+			sctx.GetSessionPlanCache().Put(cacheKey, cached, matchOpts)
+		}
 	}
 	sessVars.FoundInPlanCache = false
 	return p, names, err
 }
 
+// getInstancePlanCache returns the plan cache shared by all sessions, it returns nil if it's disabled.
+func getInstancePlanCache(sctx sessionctx.Context) sessionctx.InstancePlanCache {
+	if !variable.EnableInstancePlanCache.Load() {
+		return nil
+	}
+	dom := domain.GetDomain(sctx)
+	if dom == nil {
+		return nil
+	}
+	return dom.InstancePlanCache()
+}
+
+// putToInstancePlanCache tries to put the plan into the instance plan cache, it returns false if the plan
+// can't be shared, then the plan should be put into the session plan cache.
+func putToInstancePlanCache(sctx sessionctx.Context, stmt *PlanCacheStmt, cacheKey kvcache.Key,
+	cached *PlanCacheValue, matchOpts *utilpc.PlanCacheMatchOpts) bool {
+	instanceCache := getInstancePlanCache(sctx)
+	if instanceCache == nil {
+		return false
+	}
+	cached.normalizedSQL = stmt.NormalizedSQL
+	if stmt.SQLDigest != nil {
+		cached.sqlDigest = stmt.SQLDigest.String()
+	}
+	if stmt.PlanDigest != nil {
+		cached.planDigest = stmt.PlanDigest.String()
+	}
+	return instanceCache.Put(sctx, cacheKey, cached, matchOpts)
+}
+
 // RebuildPlan4CachedPlan will rebuild this plan under current user parameters.
 func RebuildPlan4CachedPlan(p Plan) (ok bool) {
 	sc := p.SCtx().GetSessionVars().StmtCtx
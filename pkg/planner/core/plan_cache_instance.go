@@ -0,0 +1,418 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	core_metrics "github.com/pingcap/tidb/pkg/planner/core/metrics"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/kvcache"
+	utilpc "github.com/pingcap/tidb/pkg/util/plancache"
+	"github.com/pingcap/tidb/pkg/util/syncutil"
+)
+
+// instancePlanCacheEntry is a plan in the instance plan cache.
+// The plan is a template which isn't bound to any session, sessions use the copies of it.
+type instancePlanCacheEntry struct {
+	bucket     *instancePlanCacheBucket
+	value      *PlanCacheValue
+	schemaName string
+	memUsage   int64
+	createTime time.Time
+	hits       atomic.Int64
+	lastAccess atomic.Int64 // unix nano
+}
+
+// instancePlanCacheBucket holds the plans of the same statement under the same environment,
+// and it records the number of lookups and hits of the statement to calculate the hit rate.
+type instancePlanCacheBucket struct {
+	hash    string
+	entries []*instancePlanCacheEntry
+	lookups atomic.Int64
+	hits    atomic.Int64
+}
+
+// InstancePlanCache is the plan cache shared by all sessions of the instance. It's keyed by the statement,
+// the schema version and the session variables which affect the plan, and its memory usage is limited by
+// tidb_instance_plan_cache_max_mem_size. Only the read-only plans on non-partitioned TiKV tables are shared,
+// see rebindPlan for details.
+type InstancePlanCache struct {
+	mu       syncutil.RWMutex
+	buckets  map[string]*instancePlanCacheBucket
+	size     int
+	memUsage int64
+}
+
+// NewInstancePlanCache creates an empty InstancePlanCache.
+func NewInstancePlanCache() *InstancePlanCache {
+	return &InstancePlanCache{
+		buckets: make(map[string]*instancePlanCacheBucket),
+	}
+}
+
+// Get implements sessionctx.InstancePlanCache interface.
+func (c *InstancePlanCache) Get(sctx sessionctx.Context, key kvcache.Key, opts *utilpc.PlanCacheMatchOpts) (kvcache.Value, bool) {
+	hash := instancePlanCacheHash(key, sctx.GetSessionVars())
+	if hash == "" {
+		return nil, false
+	}
+	var template *PlanCacheValue
+	c.mu.RLock()
+	if bucket, ok := c.buckets[hash]; ok {
+		bucket.lookups.Add(1)
+		for _, entry := range bucket.entries {
+			if matchCachedPlan(sctx.GetSessionVars(), entry.value.matchOpts, opts) {
+				template = entry.value
+				entry.hits.Add(1)
+				entry.lastAccess.Store(time.Now().UnixNano())
+				bucket.hits.Add(1)
+				break
+			}
+		}
+	}
+	c.mu.RUnlock()
+	if template == nil {
+		return nil, false
+	}
+	// The template is never modified after it's put into the cache, so it's safe to clone it without the lock.
+	value, ok := clonePlanCacheValue(template, sctx.GetPlanCtx())
+	if !ok {
+		return nil, false
+	}
+	core_metrics.GetInstancePlanCacheHitCounter().Inc()
+	return value, true
+}
+
+// Put implements sessionctx.InstancePlanCache interface.
+func (c *InstancePlanCache) Put(sctx sessionctx.Context, key kvcache.Key, value kvcache.Value, opts *utilpc.PlanCacheMatchOpts) bool {
+	hash := instancePlanCacheHash(key, sctx.GetSessionVars())
+	if hash == "" {
+		return false
+	}
+	src := value.(*PlanCacheValue)
+	for _, unionScan := range src.TblInfo2UnionScan {
+		if unionScan {
+			return false
+		}
+	}
+	memUsage := src.MemoryUsage() + int64(len(hash))
+	budget := variable.InstancePlanCacheMaxMemSize.Load()
+	if uint64(memUsage) > budget {
+		return false
+	}
+	// unbind the template from the session, so the session can be released after it's closed.
+	template, ok := clonePlanCacheValue(src, nil)
+	if !ok {
+		return false
+	}
+	template.memoryUsage = src.MemoryUsage()
+	now := time.Now()
+	entry := &instancePlanCacheEntry{
+		value:      template,
+		schemaName: key.(*planCacheKey).database,
+		memUsage:   memUsage,
+		createTime: now,
+	}
+	entry.lastAccess.Store(now.UnixNano())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bucket, ok := c.buckets[hash]
+	if !ok {
+		bucket = &instancePlanCacheBucket{hash: hash}
+		// the plan is put after the lookup missed.
+		bucket.lookups.Store(1)
+		c.buckets[hash] = bucket
+	}
+	entry.bucket = bucket
+	replaced := false
+	for i, old := range bucket.entries {
+		if matchCachedPlan(sctx.GetSessionVars(), old.value.matchOpts, opts) {
+			entry.hits.Store(old.hits.Load())
+			bucket.entries[i] = entry
+			c.updateUsage(-old.memUsage, -1)
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		bucket.entries = append(bucket.entries, entry)
+	}
+	c.updateUsage(entry.memUsage, 1)
+	c.evictIfNeeded(budget, entry)
+	return true
+}
+
+// DeleteAll implements sessionctx.InstancePlanCache interface.
+func (c *InstancePlanCache) DeleteAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updateUsage(-c.memUsage, -c.size)
+	c.buckets = make(map[string]*instancePlanCacheBucket)
+}
+
+// Size implements sessionctx.InstancePlanCache interface.
+func (c *InstancePlanCache) Size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.size
+}
+
+// MemUsage implements sessionctx.InstancePlanCache interface.
+func (c *InstancePlanCache) MemUsage() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.memUsage
+}
+
+// All implements sessionctx.InstancePlanCache interface.
+func (c *InstancePlanCache) All() []*utilpc.InstancePlanCacheItem {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	items := make([]*utilpc.InstancePlanCacheItem, 0, c.size)
+	for _, bucket := range c.buckets {
+		var hitRate float64
+		if lookups := bucket.lookups.Load(); lookups > 0 {
+			hitRate = float64(bucket.hits.Load()) / float64(lookups)
+		}
+		for _, entry := range bucket.entries {
+			paramTypes := make([]string, 0, len(entry.value.matchOpts.ParamTypes))
+			for _, tp := range entry.value.matchOpts.ParamTypes {
+				paramTypes = append(paramTypes, types.TypeStr(tp.GetType()))
+			}
+			items = append(items, &utilpc.InstancePlanCacheItem{
+				SQLDigest:      entry.value.sqlDigest,
+				SQLText:        entry.value.normalizedSQL,
+				SchemaName:     entry.schemaName,
+				PlanDigest:     entry.value.planDigest,
+				ParamTypes:     strings.Join(paramTypes, ", "),
+				MemSize:        entry.memUsage,
+				Hits:           entry.hits.Load(),
+				HitRate:        hitRate,
+				CreateTime:     entry.createTime,
+				LastAccessTime: time.Unix(0, entry.lastAccess.Load()),
+			})
+		}
+	}
+	slices.SortFunc(items, func(a, b *utilpc.InstancePlanCacheItem) int {
+		if c := cmp.Compare(a.SQLDigest, b.SQLDigest); c != 0 {
+			return c
+		}
+		return a.CreateTime.Compare(b.CreateTime)
+	})
+	return items
+}
+
+// evictIfNeeded evicts the plans by the eviction policy until the memory usage is within the budget.
+// The plan just put is never evicted, otherwise it'll always be evicted first by LFU.
+func (c *InstancePlanCache) evictIfNeeded(budget uint64, newEntry *instancePlanCacheEntry) {
+	if uint64(c.memUsage) <= budget {
+		return
+	}
+	entries := make([]*instancePlanCacheEntry, 0, c.size)
+	for _, bucket := range c.buckets {
+		entries = append(entries, bucket.entries...)
+	}
+	lfu := variable.InstancePlanCacheEvictionPolicy.Load() == variable.InstancePlanCacheEvictLFU
+	slices.SortFunc(entries, func(a, b *instancePlanCacheEntry) int {
+		if lfu {
+			if c := cmp.Compare(a.hits.Load(), b.hits.Load()); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.lastAccess.Load(), b.lastAccess.Load())
+	})
+	for _, entry := range entries {
+		if uint64(c.memUsage) <= budget {
+			break
+		}
+		if entry == newEntry {
+			continue
+		}
+		bucket := entry.bucket
+		bucket.entries = slices.DeleteFunc(bucket.entries, func(e *instancePlanCacheEntry) bool { return e == entry })
+		if len(bucket.entries) == 0 {
+			delete(c.buckets, bucket.hash)
+		}
+		c.updateUsage(-entry.memUsage, -1)
+	}
+}
+
+// updateUsage updates the memory usage and the plan num of the cache, the lock must be held.
+func (c *InstancePlanCache) updateUsage(memDelta int64, numDelta int) {
+	c.memUsage += memDelta
+	c.size += numDelta
+	core_metrics.GetInstancePlanCacheMemoryUsage().Add(float64(memDelta))
+	core_metrics.GetInstancePlanCachePlanNum().Add(float64(numDelta))
+}
+
+// instancePlanCacheHash returns the key of the instance plan cache. Different from the session plan cache,
+// the connection ID is excluded and the variables which affect the optimizer are included.
+func instancePlanCacheHash(key kvcache.Key, sessVars *variable.SessionVars) string {
+	pcKey, ok := key.(*planCacheKey)
+	if !ok {
+		return ""
+	}
+	instanceKey := *pcKey
+	instanceKey.connID = 0
+	instanceKey.hash = nil
+	instanceKey.memoryUsage = 0
+	return string(sessVars.AppendOptimizerVars(instanceKey.Hash()))
+}
+
+// clonePlanCacheValue clones the cached value and binds the plan to the given context.
+// It returns false if the plan can't be shared between sessions.
+func clonePlanCacheValue(src *PlanCacheValue, ctx PlanContext) (*PlanCacheValue, bool) {
+	p, ok := src.Plan.(PhysicalPlan)
+	if !ok {
+		return nil, false
+	}
+	cloned, err := p.Clone()
+	if err != nil || !rebindPlan(cloned, ctx) {
+		return nil, false
+	}
+	return &PlanCacheValue{
+		Plan:              cloned,
+		OutPutNames:       src.OutPutNames,
+		TblInfo2UnionScan: src.TblInfo2UnionScan,
+		memoryUsage:       src.memoryUsage,
+		matchOpts:         src.matchOpts,
+		stmtHints:         src.stmtHints,
+		normalizedSQL:     src.normalizedSQL,
+		sqlDigest:         src.sqlDigest,
+		planDigest:        src.planDigest,
+	}, true
+}
+
+// rebindPlan binds the cloned plan and the parameter markers in it to the given context.
+// Only the operators whose states can be fully cloned are supported, it returns false for the others.
+func rebindPlan(p PhysicalPlan, ctx PlanContext) bool {
+	var vars variable.SessionVarsProvider = ctx
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		if x.StoreType != kv.TiKV || x.ReadReqType != Cop || !rebindPlan(x.tablePlan, ctx) {
+			return false
+		}
+		x.SetSCtx(ctx)
+	case *PhysicalIndexReader:
+		if !rebindPlan(x.indexPlan, ctx) {
+			return false
+		}
+		x.SetSCtx(ctx)
+	case *PhysicalIndexLookUpReader:
+		if !rebindPlan(x.indexPlan, ctx) || !rebindPlan(x.tablePlan, ctx) {
+			return false
+		}
+		x.SetSCtx(ctx)
+	case *PhysicalTableScan:
+		if x.StoreType != kv.TiKV || x.isPartition || !isSharableTable(x.Table) ||
+			x.SampleInfo != nil || len(x.runtimeFilterList) > 0 {
+			return false
+		}
+		expression.RebindParamMarkers(vars, x.AccessCondition...)
+		expression.RebindParamMarkers(vars, x.filterCondition...)
+		expression.RebindParamMarkers(vars, x.LateMaterializationFilterCondition...)
+		x.SetSCtx(ctx)
+	case *PhysicalIndexScan:
+		if x.isPartition || !isSharableTable(x.Table) {
+			return false
+		}
+		expression.RebindParamMarkers(vars, x.AccessCondition...)
+		x.SetSCtx(ctx)
+	case *PhysicalSelection:
+		expression.RebindParamMarkers(vars, x.Conditions...)
+		x.SetSCtx(ctx)
+	case *PhysicalProjection:
+		expression.RebindParamMarkers(vars, x.Exprs...)
+		x.SetSCtx(ctx)
+	case *PhysicalTopN:
+		for _, item := range x.ByItems {
+			expression.RebindParamMarkers(vars, item.Expr)
+		}
+		x.SetSCtx(ctx)
+	case *PhysicalSort:
+		for _, item := range x.ByItems {
+			expression.RebindParamMarkers(vars, item.Expr)
+		}
+		x.SetSCtx(ctx)
+	case *PhysicalLimit:
+		x.SetSCtx(ctx)
+	case *PhysicalUnionAll:
+		if x.mpp {
+			return false
+		}
+		x.SetSCtx(ctx)
+	case *PhysicalMaxOneRow:
+		x.SetSCtx(ctx)
+	case *PhysicalHashAgg:
+		rebindAgg(&x.basePhysicalAgg, vars)
+		x.SetSCtx(ctx)
+	case *PhysicalStreamAgg:
+		rebindAgg(&x.basePhysicalAgg, vars)
+		x.SetSCtx(ctx)
+	case *PhysicalHashJoin:
+		if len(x.runtimeFilterList) > 0 || x.storeTp == kv.TiFlash || x.mppShuffleJoin {
+			return false
+		}
+		for _, cond := range x.EqualConditions {
+			expression.RebindParamMarkers(vars, cond)
+		}
+		for _, cond := range x.NAEqualConditions {
+			expression.RebindParamMarkers(vars, cond)
+		}
+		rebindJoin(&x.basePhysicalJoin, vars)
+		x.SetSCtx(ctx)
+	case *PhysicalMergeJoin:
+		rebindJoin(&x.basePhysicalJoin, vars)
+		x.SetSCtx(ctx)
+	default:
+		return false
+	}
+	for _, child := range p.Children() {
+		if !rebindPlan(child, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func rebindAgg(p *basePhysicalAgg, vars variable.SessionVarsProvider) {
+	expression.RebindParamMarkers(vars, p.GroupByItems...)
+	for _, aggFunc := range p.AggFuncs {
+		expression.RebindParamMarkers(vars, aggFunc.Args...)
+	}
+}
+
+func rebindJoin(p *basePhysicalJoin, vars variable.SessionVarsProvider) {
+	expression.RebindParamMarkers(vars, p.LeftConditions...)
+	expression.RebindParamMarkers(vars, p.RightConditions...)
+	expression.RebindParamMarkers(vars, p.OtherConditions...)
+}
+
+// isSharableTable checks whether the plans on the table can be shared between sessions.
+func isSharableTable(tblInfo *model.TableInfo) bool {
+	return tblInfo != nil && tblInfo.GetPartitionInfo() == nil && tblInfo.TempTableType == model.TempTableNone &&
+		tblInfo.TableCacheStatusType == model.TableCacheStatusDisable
+}
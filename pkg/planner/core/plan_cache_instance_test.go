@@ -0,0 +1,171 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancePlanCacheShareBetweenSessions(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("set global tidb_enable_instance_plan_cache = 1")
+	defer tk1.MustExec("set global tidb_enable_instance_plan_cache = default")
+	tk1.MustExec("use test")
+	tk1.MustExec("create table t (a int, b int, key(a))")
+	tk1.MustExec("insert into t values (1, 1), (1, 2), (2, 3), (3, 4)")
+
+	tk1.MustExec("prepare st from 'select b from t where a = ? and b > ? order by b'")
+	tk1.MustExec("set @a = 1, @b = 0")
+	tk1.MustQuery("execute st using @a, @b").Check(testkit.Rows("1", "2"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk1.MustQuery("select count(*), sum(hits) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("1 0"))
+
+	// the plan generated by tk1 is used by tk2 with its own parameters.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("prepare st from 'select b from t where a = ? and b > ? order by b'")
+	tk2.MustExec("set @a = 2, @b = 0")
+	tk2.MustQuery("execute st using @a, @b").Check(testkit.Rows("3"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk1.MustExec("set @a = 1, @b = 1")
+	tk1.MustQuery("execute st using @a, @b").Check(testkit.Rows("2"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk1.MustQuery("select schema_name, sql_text, param_types, hits, hit_rate from information_schema.tidb_instance_plan_cache").Check(
+		testkit.Rows("test select `b` from `t` where `a` = ? and `b` > ? order by `b` bigint, bigint 2 0.6666666666666666"))
+
+	// the session with different optimizer variables doesn't share the plan.
+	tk2.MustExec("set @@tidb_opt_prefer_range_scan = 1")
+	tk2.MustQuery("execute st using @a, @b").Check(testkit.Rows("3"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk1.MustQuery("select count(*) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("2"))
+
+	tk1.MustExec("admin flush instance plan_cache")
+	tk1.MustQuery("select count(*) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("0"))
+	tk1.MustQuery("execute st using @a, @b").Check(testkit.Rows("2"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
+
+func TestInstancePlanCacheUnsharablePlan(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_instance_plan_cache = 1")
+	defer tk.MustExec("set global tidb_enable_instance_plan_cache = default")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, key(a)) partition by hash(a) partitions 4")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	// the plans on partitioned tables are kept in the session plan cache.
+	tk.MustExec("prepare st from 'select b from t where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute st using @a").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute st using @a").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("0"))
+
+	// the plans reading dirty tables are never shared.
+	tk.MustExec("create table t2 (a int, b int, key(a))")
+	tk.MustExec("prepare st2 from 'select b from t2 where a = ?'")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t2 values (1, 1)")
+	tk.MustQuery("execute st2 using @a").Check(testkit.Rows())
+	tk.MustExec("commit")
+	tk.MustQuery("select count(*) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("0"))
+}
+
+func TestInstancePlanCacheEviction(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_instance_plan_cache = 1")
+	defer tk.MustExec("set global tidb_enable_instance_plan_cache = default")
+	defer tk.MustExec("set global tidb_instance_plan_cache_max_mem_size = default")
+	defer tk.MustExec("set global tidb_instance_plan_cache_eviction_policy = default")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, key(a))")
+	for i := 0; i < 3; i++ {
+		tk.MustExec(fmt.Sprintf("prepare st%d from 'select b from t where a = ? and b = %d'", i, i))
+	}
+	tk.MustExec("set @a = 1")
+
+	// keep at most 2 plans.
+	tk.MustQuery("execute st0 using @a")
+	memSize, err := strconv.Atoi(tk.MustQuery("select mem_size from information_schema.tidb_instance_plan_cache").Rows()[0][0].(string))
+	require.NoError(t, err)
+	tk.MustExec(fmt.Sprintf("set global tidb_instance_plan_cache_max_mem_size = %d", memSize*5/2))
+
+	tk.MustExec("set global tidb_instance_plan_cache_eviction_policy = 'LRU'")
+	tk.MustQuery("execute st1 using @a")
+	tk.MustQuery("execute st0 using @a")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("execute st2 using @a")
+	// st1 is the least recently used one.
+	tk.MustQuery("select count(*) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("2"))
+	tk.MustQuery("execute st1 using @a")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("admin flush instance plan_cache")
+	tk.MustExec("set global tidb_instance_plan_cache_eviction_policy = 'LFU'")
+	tk.MustQuery("execute st0 using @a")
+	tk.MustQuery("execute st1 using @a")
+	for i := 0; i < 3; i++ {
+		tk.MustQuery("execute st1 using @a")
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	}
+	tk.MustQuery("execute st0 using @a")
+	tk.MustQuery("execute st2 using @a")
+	// st0 is the least frequently used one.
+	tk.MustQuery("select count(*) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("2"))
+	tk.MustQuery("execute st1 using @a")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("execute st0 using @a")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
+
+func TestInstancePlanCacheConcurrency(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_instance_plan_cache = 1")
+	defer tk.MustExec("set global tidb_enable_instance_plan_cache = default")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, key(a))")
+	for i := 0; i < 10; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i*10))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			tk := testkit.NewTestKit(t, store)
+			tk.MustExec("use test")
+			tk.MustExec("prepare st from 'select b from t where a = ? and b >= ?'")
+			for j := 0; j < 20; j++ {
+				a := (id + j) % 10
+				tk.MustExec(fmt.Sprintf("set @a = %d, @b = 0", a))
+				tk.MustQuery("execute st using @a, @b").Check(testkit.Rows(strconv.Itoa(a * 10)))
+			}
+		}(i)
+	}
+	wg.Wait()
+	tk.MustQuery("select count(*) from information_schema.tidb_instance_plan_cache").Check(testkit.Rows("1"))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/errors"
 	core_metrics "github.com/pingcap/tidb/pkg/planner/core/metrics"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/util/hack"
 	"github.com/pingcap/tidb/pkg/util/kvcache"
 	"github.com/pingcap/tidb/pkg/util/logutil"
@@ -254,39 +255,41 @@ func (l *LRUPlanCache) memoryControl() {
 func (l *LRUPlanCache) pickFromBucket(bucket map[*list.Element]struct{}, matchOpts *utilpc.PlanCacheMatchOpts) (*list.Element, bool) {
 	for k := range bucket {
 		plan := k.Value.(*planCacheEntry).PlanValue.(*PlanCacheValue)
-		// check param types' compatibility
-		ok1 := checkTypesCompatibility4PC(plan.matchOpts.ParamTypes, matchOpts.ParamTypes)
-		if !ok1 {
-			continue
+		if matchCachedPlan(l.sctx.GetSessionVars(), plan.matchOpts, matchOpts) {
+			return k, true
 		}
+	}
+	return nil, false
+}
 
-		// check limit offset and key if equal and check switch if enabled
-		ok2 := checkUint64SliceIfEqual(plan.matchOpts.LimitOffsetAndCount, matchOpts.LimitOffsetAndCount)
-		if !ok2 {
-			continue
-		}
-		if len(plan.matchOpts.LimitOffsetAndCount) > 0 && !l.sctx.GetSessionVars().EnablePlanCacheForParamLimit {
-			// offset and key slice matched, but it is a plan with param limit and the switch is disabled
-			continue
-		}
-		// check subquery switch state
-		if plan.matchOpts.HasSubQuery && !l.sctx.GetSessionVars().EnablePlanCacheForSubquery {
-			continue
-		}
-		// table stats has changed
-		// this check can be disabled by turning off system variable tidb_plan_cache_invalidation_on_fresh_stats
-		if l.sctx.GetSessionVars().PlanCacheInvalidationOnFreshStats &&
-			plan.matchOpts.StatsVersionHash != matchOpts.StatsVersionHash {
-			continue
-		}
+// matchCachedPlan checks whether the cached plan can be used by the current statement.
+func matchCachedPlan(sessVars *variable.SessionVars, cached, matchOpts *utilpc.PlanCacheMatchOpts) bool {
+	// check param types' compatibility
+	if !checkTypesCompatibility4PC(cached.ParamTypes, matchOpts.ParamTypes) {
+		return false
+	}
 
-		// below are some SQL variables that can affect the plan
-		if plan.matchOpts.ForeignKeyChecks != matchOpts.ForeignKeyChecks {
-			continue
-		}
-		return k, true
+	// check limit offset and key if equal and check switch if enabled
+	if !checkUint64SliceIfEqual(cached.LimitOffsetAndCount, matchOpts.LimitOffsetAndCount) {
+		return false
 	}
-	return nil, false
+	if len(cached.LimitOffsetAndCount) > 0 && !sessVars.EnablePlanCacheForParamLimit {
+		// offset and key slice matched, but it is a plan with param limit and the switch is disabled
+		return false
+	}
+	// check subquery switch state
+	if cached.HasSubQuery && !sessVars.EnablePlanCacheForSubquery {
+		return false
+	}
+	// table stats has changed
+	// this check can be disabled by turning off system variable tidb_plan_cache_invalidation_on_fresh_stats
+	if sessVars.PlanCacheInvalidationOnFreshStats &&
+		cached.StatsVersionHash != matchOpts.StatsVersionHash {
+		return false
+	}
+
+	// below are some SQL variables that can affect the plan
+	return cached.ForeignKeyChecks == matchOpts.ForeignKeyChecks
 }
 
 func checkUint64SliceIfEqual(a, b []uint64) bool {
@@ -352,6 +352,11 @@ type PlanCacheValue struct {
 	matchOpts *utilpc.PlanCacheMatchOpts
 	// stmtHints stores the hints which set session variables, because the hints won't be processed using cached plan.
 	stmtHints *hint.StmtHints
+
+	// below fields describe the statement of the plan, they are shown by the instance plan cache.
+	normalizedSQL string
+	sqlDigest     string
+	planDigest    string
 }
 
 // unKnownMemoryUsage represent the memory usage of uncounted structure, maybe need implement later
@@ -555,7 +555,8 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.AlterRangeStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
 		*ast.RenameUserStmt, *ast.NonTransactionalDMLStmt, *ast.SetSessionStatesStmt, *ast.SetResourceGroupStmt,
-		*ast.ImportIntoActionStmt, *ast.CalibrateResourceStmt, *ast.AddQueryWatchStmt, *ast.DropQueryWatchStmt:
+		*ast.ImportIntoActionStmt, *ast.CalibrateResourceStmt, *ast.AddQueryWatchStmt, *ast.DropQueryWatchStmt, *ast.XAStmt,
+		*ast.ProcedureInfo, *ast.DropProcedureStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -1101,6 +1102,14 @@ func getPossibleAccessPaths(ctx PlanContext, tableHints *hint.PlanHints, indexHi
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
+			// FULLTEXT indexes can only be accessed by MATCH ... AGAINST, see generateIndexMerge4FullTextIndex.
+			if index.FullTextInfo != nil {
+				continue
+			}
+			// Vector indexes can only be accessed by vector search, see generateVectorSearchPath.
+			if index.VectorInfo != nil {
+				continue
+			}
 			if check && latestIndexes == nil {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
@@ -1451,18 +1460,15 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 	case ast.AdminCaptureBindings:
 		return &SQLBindPlan{SQLBindOp: OpCaptureBindings}, nil
 	case ast.AdminEvolveBindings:
-		var err error
-		// The 'baseline evolution' only work in the test environment before the feature is GA.
-		if !config.CheckTableBeforeDrop {
-			err = errors.Errorf("Cannot enable baseline evolution feature, it is not generally available now")
-		}
-		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, err
+		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, nil
 	case ast.AdminReloadBindings:
 		return &SQLBindPlan{SQLBindOp: OpReloadBindings}, nil
 	case ast.AdminReloadStatistics:
 		return &Simple{Statement: as}, nil
 	case ast.AdminFlushPlanCache:
 		return &Simple{Statement: as}, nil
+	case ast.AdminResetCardinalityFeedback:
+		return &Simple{Statement: as}, nil
 	case ast.AdminSetBDRRole, ast.AdminUnsetBDRRole:
 		ret = &Simple{Statement: as}
 	case ast.AdminShowBDRRole:
@@ -1652,6 +1658,14 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if idxInfo.FullTextInfo != nil {
+			// Skip checking FULLTEXT index, whose entries are the tokens rather than the column values.
+			continue
+		}
+		if idxInfo.VectorInfo != nil {
+			// Skip checking vector index, whose entries are the lists rather than the column values.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1735,6 +1749,12 @@ func (b *PlanBuilder) buildAdminCheckTable(ctx context.Context, as *ast.AdminStm
 		if idx.Meta().State != model.StatePublic {
 			return nil, errors.Errorf("index %s state %s isn't public", as.Index, idx.Meta().State)
 		}
+		if idx.Meta().FullTextInfo != nil {
+			return nil, errors.Errorf("checking FULLTEXT index %s is not supported", as.Index)
+		}
+		if idx.Meta().VectorInfo != nil {
+			return nil, errors.Errorf("checking VECTOR index %s is not supported", as.Index)
+		}
 		p.CheckIndex = true
 		readerPlans, indexInfos, err = b.buildPhysicalIndexLookUpReaders(ctx, tblName.Schema, tbl, []table.Index{idx})
 	} else {
@@ -2153,6 +2173,11 @@ func getModifiedIndexesInfoForAnalyze(
 			independentIdxsInfo = append(independentIdxsInfo, originIdx)
 			continue
 		}
+		// The statistics of the texts are useless for the tokens in FULLTEXT indexes, and so are the statistics of
+		// the vectors for the lists in vector indexes.
+		if originIdx.FullTextInfo != nil || originIdx.VectorInfo != nil {
+			continue
+		}
 		if allColumns {
 			// If all the columns need to be analyzed, we don't need to modify IndexColumn.Offset.
 			idxsInfo = append(idxsInfo, originIdx)
@@ -2526,6 +2551,14 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing multi-valued indexes is not supported, skip %s", idx.Name.L))
 				continue
 			}
+			if idx.FullTextInfo != nil {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing FULLTEXT indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
+			if idx.VectorInfo != nil {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing VECTOR indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
 			p.IdxTasks = append(p.IdxTasks, generateIndexTasks(idx, as, tbl.TableInfo, partitionNames, physicalIDs, version)...)
 		}
 		handleCols := BuildHandleColsForAnalyze(b.ctx, tbl.TableInfo, true, nil)
@@ -2602,6 +2635,14 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing multi-valued indexes is not supported, skip %s", idx.Name.L))
 			continue
 		}
+		if idx.FullTextInfo != nil {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing FULLTEXT indexes is not supported, skip %s", idx.Name.L))
+			continue
+		}
+		if idx.VectorInfo != nil {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing VECTOR indexes is not supported, skip %s", idx.Name.L))
+			continue
+		}
 		p.IdxTasks = append(p.IdxTasks, generateIndexTasks(idx, as, tblInfo, names, physicalIDs, version)...)
 	}
 	return p, nil
@@ -2632,6 +2673,14 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing multi-valued indexes is not supported, skip %s", idx.Name.L))
 				continue
 			}
+			if idx.FullTextInfo != nil {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing FULLTEXT indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
+			if idx.VectorInfo != nil {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("analyzing VECTOR indexes is not supported, skip %s", idx.Name.L))
+				continue
+			}
 
 			p.IdxTasks = append(p.IdxTasks, generateIndexTasks(idx, as, tblInfo, names, physicalIDs, version)...)
 		}
@@ -3060,6 +3109,17 @@ func buildAddQueryWatchSchema() (*expression.Schema, types.NameSlice) {
 	return cols.col2Schema(), cols.names
 }
 
+func buildXARecoverSchema() (*expression.Schema, types.NameSlice) {
+	longlongSize, _ := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeLonglong)
+	cols := newColumnsWithNames(4)
+	cols.Append(buildColumnWithName("", "formatID", mysql.TypeLonglong, longlongSize))
+	cols.Append(buildColumnWithName("", "gtrid_length", mysql.TypeLonglong, longlongSize))
+	cols.Append(buildColumnWithName("", "bqual_length", mysql.TypeLonglong, longlongSize))
+	cols.Append(buildColumnWithName("", "data", mysql.TypeVarchar, 256))
+
+	return cols.col2Schema(), cols.names
+}
+
 func buildColumnWithName(tableName, name string, tp byte, size int) (*expression.Column, *types.FieldName) {
 	cs, cl := types.DefaultCharsetForType(tp)
 	flag := mysql.UnsignedFlag
@@ -3128,6 +3188,9 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			CountWarningsOrErrors: show.CountWarningsOrErrors,
 			DBName:                show.DBName,
 			Table:                 show.Table,
+			Procedure:             show.Procedure,
+			Trigger:               show.Trigger,
+			Event:                 show.Event,
 			Partition:             show.Partition,
 			Column:                show.Column,
 			IndexName:             show.IndexName,
@@ -3175,6 +3238,39 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			}
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AllPrivMask, show.Table.Schema.L, show.Table.Name.L, "", err)
 		}
+	case ast.ShowCreateProcedure:
+		// The privileges are checked by the executor, because the definer can always see the procedure.
+		if show.Procedure.Schema.O == "" {
+			show.Procedure.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+		}
+		if show.Procedure.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+	case ast.ShowCreateTrigger:
+		// The privileges are checked by the executor, because the trigger must be found to know its table.
+		if show.Trigger.Schema.O == "" {
+			show.Trigger.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+		}
+		if show.Trigger.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+	case ast.ShowTriggers:
+		if p.DBName == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+	case ast.ShowCreateEvent:
+		if show.Event.Schema.O == "" {
+			show.Event.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+		}
+		if show.Event.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+		b.appendEventVisitInfo(show.Event.Schema.L)
+	case ast.ShowEvents:
+		if p.DBName == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+		b.appendEventVisitInfo(strings.ToLower(p.DBName))
 	case ast.ShowConfig:
 		privErr := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("CONFIG")
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ConfigPriv, "", "", "", privErr)
@@ -3241,8 +3337,16 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 	np = p
 	// If we have ShowPredicateExtractor, we do not buildSelection with Pattern
 	if show.Pattern != nil && buildPattern {
+		patternCol := p.OutputNames()[0].ColName
+		if show.Tp == ast.ShowProcedureStatus || show.Tp == ast.ShowFunctionStatus || show.Tp == ast.ShowEvents {
+			// The pattern matches the name of the routine rather than the database.
+			patternCol = p.OutputNames()[1].ColName
+		} else if show.Tp == ast.ShowTriggers {
+			// The pattern matches the name of the table.
+			patternCol = p.OutputNames()[2].ColName
+		}
 		show.Pattern.Expr = &ast.ColumnNameExpr{
-			Name: &ast.ColumnName{Name: p.OutputNames()[0].ColName},
+			Name: &ast.ColumnName{Name: patternCol},
 		}
 		np, err = b.buildSelection(ctx, np, show.Pattern, nil)
 		if err != nil {
@@ -3289,6 +3393,22 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 	return np, nil
 }
 
+// checkRoutinePriv fills the default database of the routine name and requires the privilege on the database.
+func (b *PlanBuilder) checkRoutinePriv(name *ast.TableName, priv mysql.PrivilegeType) error {
+	if name.Schema.O == "" {
+		name.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+	}
+	if name.Schema.O == "" {
+		return plannererrors.ErrNoDB
+	}
+	var err error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		err = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, name.Schema.O)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, priv, name.Schema.L, "", "", err)
+	return nil
+}
+
 func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan, error) {
 	p := &Simple{Statement: node}
 
@@ -3328,6 +3448,12 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 	case *ast.DropQueryWatchStmt:
 		err := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESOURCE_GROUP_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "RESOURCE_GROUP_ADMIN", false, err)
+	case *ast.XAStmt:
+		if raw.Tp == ast.XARecover {
+			err := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or XA_RECOVER_ADMIN")
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "XA_RECOVER_ADMIN", false, err)
+			p.setSchemaAndNames(buildXARecoverSchema())
+		}
 	case *ast.GrantRoleStmt:
 		err := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or ROLE_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROLE_ADMIN", false, err)
@@ -3367,6 +3493,22 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 		if raw.DBName == "" {
 			return nil, plannererrors.ErrNoDB
 		}
+	case *ast.ProcedureInfo:
+		if err := b.checkRoutinePriv(raw.ProcedureName, mysql.CreateRoutinePriv); err != nil {
+			return nil, err
+		}
+		sessVars := b.ctx.GetSessionVars()
+		if raw.Definer.CurrentUser && sessVars.User != nil {
+			raw.Definer = sessVars.User
+		}
+		if sessVars.User != nil && raw.Definer.String() != sessVars.User.String() {
+			err := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+		}
+	case *ast.DropProcedureStmt:
+		if err := b.checkRoutinePriv(raw.ProcedureName, mysql.AlterRoutinePriv); err != nil {
+			return nil, err
+		}
 	case *ast.DropUserStmt:
 		// The main privilege checks for DROP USER are currently performed in executor/simple.go
 		// because they use complex OR conditions (not supported by visitInfo).
@@ -3608,6 +3750,11 @@ func (b *PlanBuilder) resolveGeneratedColumns(ctx context.Context, columns []*ta
 	return igc, nil
 }
 
+// isReadOnlyMView checks whether the table is a materialized view, which is only modified by the refreshes.
+func (b *PlanBuilder) isReadOnlyMView(tblInfo *model.TableInfo) bool {
+	return tblInfo.MaterializedView != nil && !b.ctx.GetSessionVars().InRestrictedSQL
+}
+
 func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (Plan, error) {
 	ts, ok := insert.Table.TableRefs.Left.(*ast.TableSource)
 	if !ok {
@@ -3632,6 +3779,13 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 		return nil, err
 	}
+	if b.isReadOnlyMView(tableInfo) {
+		op := "INSERT"
+		if insert.IsReplace {
+			op = "REPLACE"
+		}
+		return nil, plannererrors.ErrNonUpdatableTable.GenWithStackByArgs(tableInfo.Name.O, op)
+	}
 	// Build Schema with DBName otherwise ColumnRef with DBName cannot match any Column in Schema.
 	schema, names, err := expression.TableInfo2SchemaAndNames(b.ctx.GetExprCtx(), tn.Schema, tableInfo)
 	if err != nil {
@@ -3653,7 +3807,7 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	if tableInfo.GetPartitionInfo() != nil && len(insert.PartitionNames) != 0 {
 		givenPartitionSets := make(map[int64]struct{}, len(insert.PartitionNames))
 		// check partition by name.
-		for _, name := range insert.PartitionNames {
+		for _, name := range tableInfo.Partition.ExpandPartitionNames(insert.PartitionNames) {
 			id, err := tables.FindPartitionByName(tableInfo, name.L)
 			if err != nil {
 				return nil, err
@@ -4146,13 +4300,32 @@ var (
 
 	// ImportIntoDataSource used inplannererrors.ErrLoadDataInvalidURI.
 	ImportIntoDataSource = "data source"
+	// SelectIntoTarget used in plannererrors.ErrLoadDataInvalidURI.
+	SelectIntoTarget = "outfile"
 )
 
+// buildLoadDataOpts rewrites the values of the options in the WITH clause into expressions.
+func (b *PlanBuilder) buildLoadDataOpts(ctx context.Context, opts []*ast.LoadDataOpt) ([]*LoadDataOpt, error) {
+	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
+	options := make([]*LoadDataOpt, 0, len(opts))
+	for _, opt := range opts {
+		loadDataOpt := LoadDataOpt{Name: opt.Name}
+		if opt.Value != nil {
+			var err error
+			loadDataOpt.Value, _, err = b.rewrite(ctx, opt.Value, mockTablePlan, nil, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		options = append(options, &loadDataOpt)
+	}
+	return options, nil
+}
+
 func (b *PlanBuilder) buildImportInto(ctx context.Context, ld *ast.ImportIntoStmt) (Plan, error) {
 	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
 	var (
 		err              error
-		options          = make([]*LoadDataOpt, 0, len(ld.Options))
 		importFromServer bool
 	)
 
@@ -4167,15 +4340,9 @@ func (b *PlanBuilder) buildImportInto(ctx context.Context, ld *ast.ImportIntoStm
 		return nil, plannererrors.ErrNotSupportedWithSem.GenWithStackByArgs("IMPORT INTO from server disk")
 	}
 
-	for _, opt := range ld.Options {
-		loadDataOpt := LoadDataOpt{Name: opt.Name}
-		if opt.Value != nil {
-			loadDataOpt.Value, _, err = b.rewrite(ctx, opt.Value, mockTablePlan, nil, true)
-			if err != nil {
-				return nil, err
-			}
-		}
-		options = append(options, &loadDataOpt)
+	options, err := b.buildLoadDataOpts(ctx, ld.Options)
+	if err != nil {
+		return nil, err
 	}
 	p := ImportInto{
 		Path:               ld.Path,
@@ -4300,17 +4467,38 @@ func (b *PlanBuilder) requireInsertAndSelectPriv(tables []*ast.TableName) {
 	}
 }
 
-func (*PlanBuilder) buildIndexAdvise(node *ast.IndexAdviseStmt) Plan {
+func (b *PlanBuilder) buildIndexAdvise(node *ast.IndexAdviseStmt) Plan {
 	p := &IndexAdvise{
-		IsLocal:        node.IsLocal,
-		Path:           node.Path,
-		MaxMinutes:     node.MaxMinutes,
-		MaxIndexNum:    node.MaxIndexNum,
-		LineFieldsInfo: NewLineFieldsInfo(nil, node.LinesInfo),
+		FromStmtSummary: node.FromStmtSummary,
+		Limit:           node.Limit,
+		IsLocal:         node.IsLocal,
+		Path:            node.Path,
+		MaxMinutes:      node.MaxMinutes,
+		MaxIndexNum:     node.MaxIndexNum,
+		LineFieldsInfo:  NewLineFieldsInfo(nil, node.LinesInfo),
+	}
+	if node.FromStmtSummary {
+		// The workload comes from the statements of all users.
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ProcessPriv, "", "", "", nil)
+		p.setSchemaAndNames(buildIndexAdviseFields())
 	}
 	return p
 }
 
+func buildIndexAdviseFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(9)
+	schema.Append(buildColumnWithName("", "RANK", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "TABLE_SCHEMA", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "TABLE_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "INDEX_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "INDEX_COLUMNS", mysql.TypeVarchar, 256))
+	schema.Append(buildColumnWithName("", "EST_BENEFIT", mysql.TypeDouble, 8))
+	schema.Append(buildColumnWithName("", "EST_BENEFIT_RATIO", mysql.TypeDouble, 8))
+	schema.Append(buildColumnWithName("", "AFFECTED_DIGESTS", mysql.TypeVarchar, 4096))
+	schema.Append(buildColumnWithName("", "CREATE_INDEX_DDL", mysql.TypeVarchar, 512))
+	return schema.col2Schema(), schema.names
+}
+
 func (b *PlanBuilder) buildSplitRegion(node *ast.SplitRegionStmt) (Plan, error) {
 	if node.Table.TableInfo.TempTableType != model.TempTableNone {
 		return nil, plannererrors.ErrOptOnTemporaryTable.GenWithStackByArgs("split table")
@@ -4548,6 +4736,25 @@ func convertValueListToData(valueList []ast.ExprNode, handleColInfos []*model.Co
 	return data, nil
 }
 
+// appendTTLArchiveVisitInfo requires the FILE privilege when the TTL_ARCHIVE points to the disk of TiDB servers,
+// because the TTL jobs write the expired rows there, the same as `SELECT ... INTO OUTFILE`.
+func (b *PlanBuilder) appendTTLArchiveVisitInfo(options []*ast.TableOption) error {
+	for _, op := range options {
+		if op.Tp != ast.TableOptionTTLArchive || op.StrValue == "" {
+			continue
+		}
+		// an invalid URL will be rejected by the DDL later, so it's safe to treat it as a local path here.
+		local, err := storage.IsLocalPath(op.StrValue)
+		if err != nil || local {
+			if sem.IsEnabled() {
+				return plannererrors.ErrNotSupportedWithSem.GenWithStackByArgs("TTL_ARCHIVE on server disk")
+			}
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
+		}
+	}
+	return nil
+}
+
 func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, error) {
 	var authErr error
 	switch v := node.(type) {
@@ -4603,6 +4810,10 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 				}
 				b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.Table.Schema.L,
 					v.Table.Name.L, "", authErr)
+			} else if spec.Tp == ast.AlterTableOption {
+				if err := b.appendTTLArchiveVisitInfo(spec.Options); err != nil {
+					return nil, err
+				}
 			} else if spec.Tp == ast.AlterTableWriteable {
 				b.visitInfo[0].alterWritable = true
 			} else if spec.Tp == ast.AlterTableAddStatistics {
@@ -4694,6 +4905,9 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L,
 				v.ReferTable.Name.L, "", authErr)
 		}
+		if err := b.appendTTLArchiveVisitInfo(v.Options); err != nil {
+			return nil, err
+		}
 	case *ast.CreateViewStmt:
 		b.isCreateView = true
 		b.capFlag |= canExpandAST | renameView
@@ -4738,6 +4952,112 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
 				"", "", err)
 		}
+	case *ast.CreateMaterializedViewStmt:
+		plan, err := b.Build(ctx, v.Select)
+		if err != nil {
+			return nil, err
+		}
+		if v.Cols != nil && len(v.Cols) != plan.Schema().Len() {
+			return nil, dbterror.ErrViewWrongList
+		}
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("CREATE", user.AuthUsername,
+				user.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
+		if v.Schedule != nil {
+			b.appendEventVisitInfo(v.ViewName.Schema.L)
+		}
+		v.Definer = b.resolveEventDefiner(v.Definer)
+	case *ast.DropMaterializedViewStmt:
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("DROP", user.AuthUsername,
+				user.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
+	case *ast.RefreshMaterializedViewStmt:
+		// The rows of the materialized view are replaced by a refresh.
+		var insertErr, deleteErr error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			insertErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername,
+				user.AuthHostname, v.ViewName.Name.L)
+			deleteErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("DELETE", user.AuthUsername,
+				user.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, v.ViewName.Schema.L, v.ViewName.Name.L, "", insertErr)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, v.ViewName.Schema.L, v.ViewName.Name.L, "", deleteErr)
+	case *ast.CreateTriggerStmt:
+		currentDB := b.ctx.GetSessionVars().CurrentDB
+		if v.Table.Schema.O == "" {
+			v.Table.Schema = model.NewCIStr(currentDB)
+		}
+		if v.TriggerName.Schema.O == "" {
+			v.TriggerName.Schema = model.NewCIStr(currentDB)
+		}
+		if v.Table.Schema.O == "" || v.TriggerName.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("TRIGGER", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.TriggerPriv, v.Table.Schema.L,
+			v.Table.Name.L, "", authErr)
+		if v.Definer.CurrentUser && b.ctx.GetSessionVars().User != nil {
+			v.Definer = b.ctx.GetSessionVars().User
+		}
+		if b.ctx.GetSessionVars().User != nil && v.Definer.String() != b.ctx.GetSessionVars().User.String() {
+			err := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
+				"", "", err)
+		}
+	case *ast.CreateEventStmt:
+		if v.EventName.Schema.O == "" {
+			v.EventName.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+		}
+		if v.EventName.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+		b.appendEventVisitInfo(v.EventName.Schema.L)
+		v.Definer = b.resolveEventDefiner(v.Definer)
+	case *ast.AlterEventStmt:
+		if v.EventName.Schema.O == "" {
+			v.EventName.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+		}
+		if v.EventName.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+		b.appendEventVisitInfo(v.EventName.Schema.L)
+		if v.RenameTo != nil {
+			if v.RenameTo.Schema.O == "" {
+				v.RenameTo.Schema = v.EventName.Schema
+			}
+			b.appendEventVisitInfo(v.RenameTo.Schema.L)
+		}
+		v.Definer = b.resolveEventDefiner(v.Definer)
+	case *ast.DropEventStmt:
+		if v.EventName.Schema.O == "" {
+			v.EventName.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+		}
+		if v.EventName.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+		b.appendEventVisitInfo(v.EventName.Schema.L)
+	case *ast.DropTriggerStmt:
+		if v.TriggerName.Schema.O == "" {
+			v.TriggerName.Schema = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+		}
+		if v.TriggerName.Schema.O == "" {
+			return nil, plannererrors.ErrNoDB
+		}
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.TriggerName.Schema.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.TriggerPriv, v.TriggerName.Schema.L,
+			"", "", authErr)
 	case *ast.CreateSequenceStmt:
 		if b.ctx.GetSessionVars().User != nil {
 			authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("CREATE", b.ctx.GetSessionVars().User.AuthUsername,
@@ -5014,24 +5334,34 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 }
 
 func (b *PlanBuilder) buildSelectInto(ctx context.Context, sel *ast.SelectStmt) (Plan, error) {
-	if sem.IsEnabled() {
+	selectIntoInfo := sel.SelectIntoOpt
+	intoServerDisk, err := storage.IsLocalPath(selectIntoInfo.FileName)
+	if err != nil {
+		return nil, exeerrors.ErrLoadDataInvalidURI.FastGenByArgs(SelectIntoTarget, err.Error())
+	}
+	if intoServerDisk && sem.IsEnabled() {
 		return nil, plannererrors.ErrNotSupportedWithSem.GenWithStackByArgs("SELECT INTO")
 	}
-	selectIntoInfo := sel.SelectIntoOpt
+	options, err := b.buildLoadDataOpts(ctx, selectIntoInfo.Options)
+	if err != nil {
+		return nil, err
+	}
 	sel.SelectIntoOpt = nil
 	sctx, err := AsSctx(b.ctx)
 	if err != nil {
 		return nil, err
 	}
-	targetPlan, _, err := OptimizeAstNode(ctx, sctx, sel, b.is)
+	targetPlan, targetNames, err := OptimizeAstNode(ctx, sctx, sel, b.is)
 	if err != nil {
 		return nil, err
 	}
 	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
 	return &SelectInto{
 		TargetPlan:     targetPlan,
+		TargetNames:    targetNames,
 		IntoOpt:        selectIntoInfo,
 		LineFieldsInfo: NewLineFieldsInfo(selectIntoInfo.FieldsInfo, selectIntoInfo.LinesInfo),
+		Options:        options,
 	}, nil
 }
 
@@ -5069,6 +5399,32 @@ func buildShowTriggerSchema() (*expression.Schema, []*types.FieldName) {
 	return schema.col2Schema(), schema.names
 }
 
+// appendEventVisitInfo requires the EVENT privilege on the schema of an event.
+func (b *PlanBuilder) appendEventVisitInfo(schema string) {
+	var authErr error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		authErr = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, schema)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.EventPriv, schema, "", "", authErr)
+}
+
+// resolveEventDefiner fills the definer of an event with the current user if it's not specified, and requires the
+// SUPER privilege to specify another user as the definer.
+func (b *PlanBuilder) resolveEventDefiner(definer *auth.UserIdentity) *auth.UserIdentity {
+	user := b.ctx.GetSessionVars().User
+	if user == nil {
+		return definer
+	}
+	if definer == nil || definer.CurrentUser {
+		return user
+	}
+	if definer.String() != user.String() {
+		err := plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+	}
+	return definer
+}
+
 func buildShowEventsSchema() (*expression.Schema, []*types.FieldName) {
 	tblName := "EVENTS"
 	schema := newColumnsWithNames(15)
@@ -5182,6 +5538,13 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		}
 	case ast.ShowCreateView:
 		names = []string{"View", "Create View", "character_set_client", "collation_connection"}
+	case ast.ShowCreateProcedure:
+		names = []string{"Procedure", "sql_mode", "Create Procedure", "character_set_client", "collation_connection", "Database Collation"}
+	case ast.ShowCreateTrigger:
+		names = []string{"Trigger", "sql_mode", "SQL Original Statement", "character_set_client", "collation_connection", "Database Collation", "Created"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime}
+	case ast.ShowCreateEvent:
+		names = []string{"Event", "sql_mode", "time_zone", "Create Event", "character_set_client", "collation_connection", "Database Collation"}
 	case ast.ShowCreateDatabase:
 		names = []string{"Database", "Create Database"}
 	case ast.ShowDrainerStatus:
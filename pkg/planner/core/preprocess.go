@@ -266,6 +266,24 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.flag |= inCreateOrDropTable
 		p.checkCreateViewGrammar(node)
 		p.checkCreateViewWithSelectGrammar(node)
+	case *ast.CreateMaterializedViewStmt:
+		p.stmtTp = TypeCreate
+		p.flag |= inCreateOrDropTable
+		stmt := &ast.CreateViewStmt{ViewName: node.ViewName, Cols: node.Cols, Definer: node.Definer, Select: node.Select}
+		p.checkCreateViewGrammar(stmt)
+		p.checkCreateViewWithSelectGrammar(stmt)
+	case *ast.DropMaterializedViewStmt:
+		p.stmtTp = TypeDrop
+		p.flag |= inCreateOrDropTable
+	case *ast.ProcedureInfo:
+		// The statements in the body are checked when the procedure is called.
+		return in, true
+	case *ast.CreateTriggerStmt:
+		// The statements in the body are checked when the trigger is activated.
+		return in, true
+	case *ast.CreateEventStmt, *ast.AlterEventStmt:
+		// The statements in the body are checked when the event is executed.
+		return in, true
 	case *ast.DropTableStmt:
 		p.flag |= inCreateOrDropTable
 		p.stmtTp = TypeDrop
@@ -568,7 +586,7 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		p.flag &= ^inCreateOrDropTable
 		p.checkAutoIncrement(x)
 		p.checkContainDotColumn(x)
-	case *ast.CreateViewStmt:
+	case *ast.CreateViewStmt, *ast.CreateMaterializedViewStmt, *ast.DropMaterializedViewStmt:
 		p.flag &= ^inCreateOrDropTable
 	case *ast.DropTableStmt, *ast.AlterTableStmt, *ast.RenameTableStmt:
 		p.flag &= ^inCreateOrDropTable
@@ -1593,6 +1611,10 @@ func (p *preprocessor) handleTableName(tn *ast.TableName) {
 			return
 		}
 	}
+	if pi := tableInfo.GetPartitionInfo(); pi != nil && pi.Sub != nil && len(tn.PartitionNames) > 0 {
+		// Selecting a partition selects all of its subpartitions.
+		tn.PartitionNames = pi.ExpandPartitionNames(tn.PartitionNames)
+	}
 	tn.TableInfo = tableInfo
 	tn.DBInfo = dbInfo
 }
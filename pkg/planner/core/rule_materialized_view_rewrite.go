@@ -0,0 +1,377 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/expression/aggregation"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/planner/util"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/types"
+	h "github.com/pingcap/tidb/pkg/util/hint"
+)
+
+// materializedViewRewriter rewrites an aggregation on a table to read a materialized view, which aggregates the same
+// rows of the table by the same or more GROUP BY columns. In the latter case, the rows of the view are aggregated
+// again by the GROUP BY columns of the query. The rows of a materialized view are as of its last refresh, so the
+// rule is only applied when tidb_opt_enable_materialized_view_rewrite is on.
+//
+// For example, with the view `create materialized view mv as select a, b, count(*) as cnt from t group by a, b`,
+// `select a, count(*) from t group by a` is rewritten to `select a, sum(cnt) from mv group by a`.
+type materializedViewRewriter struct {
+}
+
+func (*materializedViewRewriter) name() string {
+	return "materialized_view_rewrite"
+}
+
+// optimize implements the logicalOptRule interface.
+func (r *materializedViewRewriter) optimize(ctx context.Context, p LogicalPlan, opt *util.LogicalOptimizeOp) (LogicalPlan, bool, error) {
+	planChanged := false
+	newPlan, err := r.rewrite(ctx, p, opt, &planChanged)
+	return newPlan, planChanged, err
+}
+
+func (r *materializedViewRewriter) rewrite(ctx context.Context, p LogicalPlan, opt *util.LogicalOptimizeOp, planChanged *bool) (LogicalPlan, error) {
+	if agg, ok := p.(*LogicalAggregation); ok {
+		newPlan, err := r.rewriteAggregation(ctx, agg, opt)
+		if err != nil {
+			return nil, err
+		}
+		if newPlan != nil {
+			*planChanged = true
+			return newPlan, nil
+		}
+	}
+	for i, child := range p.Children() {
+		newChild, err := r.rewrite(ctx, child, opt, planChanged)
+		if err != nil {
+			return nil, err
+		}
+		p.SetChild(i, newChild)
+	}
+	return p, nil
+}
+
+// rewriteAggregation returns the plan reading a materialized view for the aggregation, or nil if no view matches.
+func (r *materializedViewRewriter) rewriteAggregation(ctx context.Context, agg *LogicalAggregation, opt *util.LogicalOptimizeOp) (LogicalPlan, error) {
+	var conds []expression.Expression
+	child := agg.children[0]
+	if sel, ok := child.(*LogicalSelection); ok {
+		conds, child = sel.Conditions, sel.children[0]
+	}
+	ds, ok := child.(*DataSource)
+	if !ok || ds.tableInfo.MaterializedView != nil || ds.tableInfo.TempTableType != model.TempTableNone ||
+		len(ds.partitionNames) > 0 || len(ds.astIndexHints) > 0 || ds.isForUpdateRead {
+		return nil, nil
+	}
+	sctx := agg.SCtx()
+	pm := privilege.GetPrivilegeManager(sctx)
+	for _, id := range ds.is.GetMaterializedViews(ds.tableInfo.ID) {
+		tbl, ok := ds.is.TableByID(id)
+		if !ok {
+			continue
+		}
+		mview := tbl.Meta()
+		if mview.MaterializedView == nil || len(mview.MaterializedView.BaseTableIDs) != 1 {
+			continue
+		}
+		dbInfo, ok := infoschema.SchemaByTable(ds.is, mview)
+		if !ok {
+			continue
+		}
+		if pm != nil && !pm.RequestVerification(sctx.GetSessionVars().ActiveRoles, dbInfo.Name.L, mview.Name.L, "", mysql.SelectPriv) {
+			continue
+		}
+		newPlan, err := r.match(ctx, agg, conds, ds, dbInfo.Name, mview)
+		if err != nil {
+			return nil, err
+		}
+		if newPlan != nil {
+			appendMaterializedViewRewriteTraceStep(agg, newPlan, ds, mview, opt)
+			return newPlan, nil
+		}
+	}
+	return nil, nil
+}
+
+// mviewAggregation is the aggregation in the definition of a materialized view, whose arguments refer to the
+// columns of the table in the query.
+type mviewAggregation struct {
+	conds        []expression.Expression
+	groupByItems []expression.Expression
+	// aggFuncs are the aggregate functions stored by the columns of the view, which are nil for the columns
+	// not stored aggregate functions.
+	aggFuncs []*aggregation.AggFuncDesc
+}
+
+// match returns the plan reading the materialized view for the aggregation, or nil if the view doesn't match.
+func (r *materializedViewRewriter) match(ctx context.Context, agg *LogicalAggregation, conds []expression.Expression,
+	ds *DataSource, mviewSchema model.CIStr, mview *model.TableInfo) (LogicalPlan, error) {
+	def := r.buildDefinition(ctx, ds, mview)
+	if def == nil {
+		return nil, nil
+	}
+	sctx := agg.SCtx()
+	evalCtx := sctx.GetExprCtx().GetEvalCtx()
+	if len(def.conds) != len(conds) {
+		return nil, nil
+	}
+	for _, cond := range def.conds {
+		if !containsEqualExpr(evalCtx, conds, cond) {
+			return nil, nil
+		}
+	}
+	for _, item := range agg.GroupByItems {
+		if !containsEqualExpr(evalCtx, def.groupByItems, item) {
+			return nil, nil
+		}
+	}
+	rollup := len(agg.GroupByItems) != len(def.groupByItems)
+	for _, item := range def.groupByItems {
+		if !containsEqualExpr(evalCtx, agg.GroupByItems, item) {
+			rollup = true
+		}
+	}
+
+	mviewPlan, err := r.buildMViewDataSource(ctx, ds, mviewSchema, mview)
+	if err != nil || mviewPlan == nil {
+		return nil, err
+	}
+	mviewCols := mviewPlan.Schema().Columns[:len(def.aggFuncs)]
+	findMViewCol := func(aggFunc *aggregation.AggFuncDesc) *expression.Column {
+		for i, f := range def.aggFuncs {
+			if f != nil && f.Equal(evalCtx, aggFunc) {
+				return mviewCols[i]
+			}
+		}
+		return nil
+	}
+
+	exprCtx := sctx.GetExprCtx()
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, len(agg.AggFuncs))}.Init(sctx, agg.QueryBlockOffset())
+	proj.SetSchema(agg.Schema().Clone())
+	if !rollup {
+		for i, aggFunc := range agg.AggFuncs {
+			col := findMViewCol(aggFunc)
+			if col == nil {
+				return nil, nil
+			}
+			proj.Exprs = append(proj.Exprs, castIfNeeded(exprCtx, col, agg.schema.Columns[i].RetType))
+		}
+		proj.SetChildren(mviewPlan)
+		return proj, nil
+	}
+
+	// The rows of the view are aggregated again by the GROUP BY columns of the query.
+	newAgg := LogicalAggregation{
+		AggFuncs:       make([]*aggregation.AggFuncDesc, 0, len(agg.AggFuncs)),
+		GroupByItems:   make([]expression.Expression, 0, len(agg.GroupByItems)),
+		PreferAggType:  agg.PreferAggType,
+		PreferAggToCop: agg.PreferAggToCop,
+	}.Init(sctx, agg.QueryBlockOffset())
+	for _, item := range agg.GroupByItems {
+		firstRow, err := aggregation.NewAggFuncDesc(exprCtx, ast.AggFuncFirstRow, []expression.Expression{item}, false)
+		if err != nil {
+			return nil, err
+		}
+		col := findMViewCol(firstRow)
+		if col == nil {
+			return nil, nil
+		}
+		newAgg.GroupByItems = append(newAgg.GroupByItems, col)
+	}
+	newAggCols := make([]*expression.Column, 0, len(agg.AggFuncs))
+	for i, aggFunc := range agg.AggFuncs {
+		if aggFunc.HasDistinct || len(aggFunc.OrderByItems) > 0 {
+			return nil, nil
+		}
+		col := findMViewCol(aggFunc)
+		if col == nil {
+			return nil, nil
+		}
+		name := aggFunc.Name
+		switch aggFunc.Name {
+		case ast.AggFuncCount, ast.AggFuncSum:
+			name = ast.AggFuncSum
+		case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
+		default:
+			return nil, nil
+		}
+		newAggFunc, err := aggregation.NewAggFuncDesc(exprCtx, name, []expression.Expression{col}, false)
+		if err != nil {
+			return nil, err
+		}
+		newAgg.AggFuncs = append(newAgg.AggFuncs, newAggFunc)
+		newAggCol := &expression.Column{
+			UniqueID: sctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  newAggFunc.RetTp,
+		}
+		newAggCols = append(newAggCols, newAggCol)
+
+		var expr expression.Expression = newAggCol
+		if aggFunc.Name == ast.AggFuncCount {
+			// The sum of no rows is NULL, while the count of no rows is 0.
+			expr, err = expression.NewFunction(exprCtx, ast.Ifnull, newAggFunc.RetTp, newAggCol, expression.NewZero())
+			if err != nil {
+				return nil, err
+			}
+		}
+		proj.Exprs = append(proj.Exprs, castIfNeeded(exprCtx, expr, agg.schema.Columns[i].RetType))
+	}
+	newAgg.SetSchema(expression.NewSchema(newAggCols...))
+	newAgg.SetChildren(mviewPlan)
+	proj.SetChildren(newAgg)
+	return proj, nil
+}
+
+// buildDefinition builds the definition of the materialized view, and returns nil if the definition isn't an
+// aggregation on the table of the data source.
+func (*materializedViewRewriter) buildDefinition(ctx context.Context, ds *DataSource, mview *model.TableInfo) *mviewAggregation {
+	sctx := ds.SCtx()
+	sessVars := sctx.GetSessionVars()
+	charset, collation := sessVars.GetCharsetInfo()
+	p := parser.New()
+	p.SetSQLMode(mview.MaterializedView.SQLMode)
+	p.SetParserConfig(sessVars.BuildParserConfig())
+	stmt, err := p.ParseOneStmt(mview.MaterializedView.SelectStmt, charset, collation)
+	if err != nil {
+		return nil
+	}
+	hintProcessor := h.NewQBHintHandler(nil)
+	stmt.Accept(hintProcessor)
+	b, savedBlockNames := NewPlanBuilder().Init(sctx, ds.is, hintProcessor)
+	plan, err := b.Build(ctx, stmt)
+	sessVars.PlannerSelectBlockAsName.Store(&savedBlockNames)
+	if err != nil {
+		// The definition may be invalid after the base table is altered.
+		return nil
+	}
+
+	proj, ok := plan.(*LogicalProjection)
+	if !ok {
+		return nil
+	}
+	agg, ok := proj.children[0].(*LogicalAggregation)
+	if !ok {
+		return nil
+	}
+	def := &mviewAggregation{}
+	child := agg.children[0]
+	if sel, ok := child.(*LogicalSelection); ok {
+		def.conds, child = sel.Conditions, sel.children[0]
+	}
+	defDS, ok := child.(*DataSource)
+	if !ok || defDS.tableInfo.ID != ds.tableInfo.ID {
+		return nil
+	}
+
+	// Substitute the columns of the definition with the columns of the query by column IDs. The columns pruned from
+	// the query are left as is, which won't be equal to any expression of the query.
+	newExprs := make([]expression.Expression, 0, defDS.schema.Len())
+	for _, defCol := range defDS.schema.Columns {
+		var newExpr expression.Expression = defCol
+		for _, col := range ds.schema.Columns {
+			if col.ID == defCol.ID {
+				newExpr = col
+				break
+			}
+		}
+		newExprs = append(newExprs, newExpr)
+	}
+	exprCtx := sctx.GetExprCtx()
+	substitute := func(exprs []expression.Expression) []expression.Expression {
+		substituted := make([]expression.Expression, 0, len(exprs))
+		for _, expr := range exprs {
+			substituted = append(substituted, expression.ColumnSubstitute(exprCtx, expr, defDS.schema, newExprs))
+		}
+		return substituted
+	}
+	def.conds = substitute(def.conds)
+	def.groupByItems = substitute(agg.GroupByItems)
+	def.aggFuncs = make([]*aggregation.AggFuncDesc, 0, len(proj.Exprs))
+	for _, expr := range proj.Exprs {
+		var aggFunc *aggregation.AggFuncDesc
+		if col, ok := expr.(*expression.Column); ok {
+			if idx := agg.schema.ColumnIndex(col); idx >= 0 {
+				aggFunc = agg.AggFuncs[idx].Clone()
+				aggFunc.Args = substitute(aggFunc.Args)
+			}
+		}
+		def.aggFuncs = append(def.aggFuncs, aggFunc)
+	}
+	return def
+}
+
+// buildMViewDataSource builds the data source reading the materialized view.
+func (*materializedViewRewriter) buildMViewDataSource(ctx context.Context, ds *DataSource, mviewSchema model.CIStr, mview *model.TableInfo) (LogicalPlan, error) {
+	sctx := ds.SCtx()
+	b, savedBlockNames := NewPlanBuilder().Init(sctx, ds.is, h.NewQBHintHandler(nil))
+	defer sctx.GetSessionVars().PlannerSelectBlockAsName.Store(&savedBlockNames)
+	b.pushSelectOffset(ds.QueryBlockOffset())
+	b.pushTableHints(nil, ds.QueryBlockOffset())
+	defer func() {
+		b.popTableHints()
+		b.popSelectOffset()
+	}()
+	tn := &ast.TableName{Schema: mviewSchema, Name: mview.Name}
+	plan, err := b.buildDataSource(ctx, tn, &model.CIStr{})
+	if err != nil {
+		return nil, err
+	}
+	mviewDS, ok := plan.(*DataSource)
+	if !ok || mviewDS.schema.Len() < len(mview.Columns) {
+		return nil, nil
+	}
+	return mviewDS, nil
+}
+
+func containsEqualExpr(ctx expression.EvalContext, exprs []expression.Expression, e expression.Expression) bool {
+	for _, expr := range exprs {
+		if expr.Equal(ctx, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// castIfNeeded casts the expression to the type if the types are different.
+func castIfNeeded(ctx expression.BuildContext, expr expression.Expression, tp *types.FieldType) expression.Expression {
+	exprTp := expr.GetType()
+	if exprTp.GetType() == tp.GetType() && exprTp.GetFlen() == tp.GetFlen() && exprTp.GetDecimal() == tp.GetDecimal() &&
+		mysql.HasUnsignedFlag(exprTp.GetFlag()) == mysql.HasUnsignedFlag(tp.GetFlag()) {
+		return expr
+	}
+	return expression.BuildCastFunction(ctx, expr, tp)
+}
+
+func appendMaterializedViewRewriteTraceStep(agg *LogicalAggregation, newPlan LogicalPlan, ds *DataSource,
+	mview *model.TableInfo, opt *util.LogicalOptimizeOp) {
+	reason := func() string {
+		return fmt.Sprintf("materialized view %s aggregates the same rows of table %s", mview.Name.O, ds.tableInfo.Name.O)
+	}
+	action := func() string {
+		return fmt.Sprintf("%v_%v is replaced by %v_%v reading materialized view %s", agg.TP(), agg.ID(), newPlan.TP(), newPlan.ID(), mview.Name.O)
+	}
+	opt.AppendStepToCurrent(agg.ID(), agg.TP(), reason, action)
+}
@@ -117,6 +117,7 @@ func (s *partitionProcessor) rewriteDataSource(lp LogicalPlan, opt *util.Logical
 // partitionTable is for those tables which implement partition.
 type partitionTable interface {
 	PartitionExpr() *tables.PartitionExpr
+	SubPartitionExpr() *tables.PartitionExpr
 }
 
 func generateHashPartitionExpr(ctx PlanContext, pi *model.PartitionInfo, columns []*expression.Column, names types.NameSlice) (expression.Expression, error) {
@@ -146,9 +147,8 @@ func getPartColumnsForHashPartition(hashExpr expression.Expression) ([]*expressi
 }
 
 func (s *partitionProcessor) getUsedHashPartitions(ctx PlanContext,
-	tbl table.Table, partitionNames []model.CIStr, columns []*expression.Column,
+	pi *model.PartitionInfo, partitionNames []model.CIStr, columns []*expression.Column,
 	conds []expression.Expression, names types.NameSlice) ([]int, error) {
-	pi := tbl.Meta().Partition
 	hashExpr, err := generateHashPartitionExpr(ctx, pi, columns, names)
 	if err != nil {
 		return nil, err
@@ -264,10 +264,8 @@ func (s *partitionProcessor) getUsedHashPartitions(ctx PlanContext,
 }
 
 func (s *partitionProcessor) getUsedKeyPartitions(ctx PlanContext,
-	tbl table.Table, partitionNames []model.CIStr, columns []*expression.Column,
+	pi *model.PartitionInfo, partExpr *tables.PartitionExpr, partitionNames []model.CIStr, columns []*expression.Column,
 	conds []expression.Expression, _ types.NameSlice) ([]int, error) {
-	pi := tbl.Meta().Partition
-	partExpr := tbl.(partitionTable).PartitionExpr()
 	partCols, colLen := partExpr.GetPartColumnsForKeyPartition(columns)
 	pe := &tables.ForKeyPruning{KeyPartCols: partCols}
 	detachedResult, err := ranger.DetachCondAndBuildRangeForPartition(ctx, conds, partCols, colLen, ctx.GetSessionVars().RangeMaxSize)
@@ -371,13 +369,13 @@ func (s *partitionProcessor) getUsedKeyPartitions(ctx PlanContext,
 }
 
 // getUsedPartitions is used to get used partitions for hash or key partition tables
-func (s *partitionProcessor) getUsedPartitions(ctx PlanContext, tbl table.Table,
+func (s *partitionProcessor) getUsedPartitions(ctx PlanContext, pi *model.PartitionInfo, partExpr *tables.PartitionExpr,
 	partitionNames []model.CIStr, columns []*expression.Column, conds []expression.Expression,
-	names types.NameSlice, partType model.PartitionType) ([]int, error) {
-	if partType == model.PartitionTypeHash {
-		return s.getUsedHashPartitions(ctx, tbl, partitionNames, columns, conds, names)
+	names types.NameSlice) ([]int, error) {
+	if pi.Type == model.PartitionTypeHash {
+		return s.getUsedHashPartitions(ctx, pi, partitionNames, columns, conds, names)
 	}
-	return s.getUsedKeyPartitions(ctx, tbl, partitionNames, columns, conds, names)
+	return s.getUsedKeyPartitions(ctx, pi, partExpr, partitionNames, columns, conds, names)
 }
 
 // findUsedPartitions is used to get used partitions for hash or key partition tables.
@@ -386,7 +384,7 @@ func (s *partitionProcessor) findUsedPartitions(ctx PlanContext,
 	tbl table.Table, partitionNames []model.CIStr, conds []expression.Expression,
 	columns []*expression.Column, names types.NameSlice) ([]int, error) {
 	pi := tbl.Meta().Partition
-	used, err := s.getUsedPartitions(ctx, tbl, partitionNames, columns, conds, names, pi.Type)
+	used, err := s.getUsedPartitions(ctx, pi, tbl.(partitionTable).PartitionExpr(), partitionNames, columns, conds, names)
 	if err != nil {
 		return nil, err
 	}
@@ -532,7 +530,7 @@ type listPartitionPruner struct {
 	listPrune      *tables.ForListPruning
 }
 
-func newListPartitionPruner(ctx PlanContext, tbl table.Table, partitionNames []model.CIStr, s *partitionProcessor, pruneList *tables.ForListPruning, columns []*expression.Column) *listPartitionPruner {
+func newListPartitionPruner(ctx PlanContext, pi *model.PartitionInfo, partitionNames []model.CIStr, s *partitionProcessor, pruneList *tables.ForListPruning, columns []*expression.Column) *listPartitionPruner {
 	pruneList = pruneList.Clone()
 	for i := range pruneList.PruneExprCols {
 		for j := range columns {
@@ -555,7 +553,7 @@ func newListPartitionPruner(ctx PlanContext, tbl table.Table, partitionNames []m
 	return &listPartitionPruner{
 		partitionProcessor: s,
 		ctx:                ctx,
-		pi:                 tbl.Meta().Partition,
+		pi:                 pi,
 		partitionNames:     partitionNames,
 		fullRange:          fullRange,
 		listPrune:          pruneList,
@@ -787,12 +785,9 @@ func (l *listPartitionPruner) findUsedListPartitions(conds []expression.Expressi
 	return used, nil
 }
 
-func (s *partitionProcessor) findUsedListPartitions(ctx PlanContext, tbl table.Table, partitionNames []model.CIStr,
-	conds []expression.Expression, columns []*expression.Column) ([]int, error) {
-	pi := tbl.Meta().Partition
-	partExpr := tbl.(partitionTable).PartitionExpr()
-
-	listPruner := newListPartitionPruner(ctx, tbl, partitionNames, s, partExpr.ForListPruning, columns)
+func (s *partitionProcessor) findUsedListPartitions(ctx PlanContext, pi *model.PartitionInfo, partExpr *tables.PartitionExpr,
+	partitionNames []model.CIStr, conds []expression.Expression, columns []*expression.Column) ([]int, error) {
+	listPruner := newListPartitionPruner(ctx, pi, partitionNames, s, partExpr.ForListPruning, columns)
 	var used map[int]struct{}
 	var err error
 	if partExpr.ForListPruning.ColPrunes == nil {
@@ -817,7 +812,7 @@ func (s *partitionProcessor) findUsedListPartitions(ctx PlanContext, tbl table.T
 
 func (s *partitionProcessor) pruneListPartition(ctx PlanContext, tbl table.Table, partitionNames []model.CIStr,
 	conds []expression.Expression, columns []*expression.Column) ([]int, error) {
-	used, err := s.findUsedListPartitions(ctx, tbl, partitionNames, conds, columns)
+	used, err := s.findUsedListPartitions(ctx, tbl.Meta().Partition, tbl.(partitionTable).PartitionExpr(), partitionNames, conds, columns)
 	if err != nil {
 		return nil, err
 	}
@@ -835,6 +830,9 @@ func (s *partitionProcessor) prune(ds *DataSource, opt *util.LogicalOptimizeOp)
 	for i, cond := range ds.allConds {
 		ds.allConds[i] = expression.PushDownNot(ds.SCtx().GetExprCtx(), cond)
 	}
+	if pi.Sub != nil {
+		return s.processSubPartitionedTable(ds, pi, opt)
+	}
 	// Try to locate partition directly for hash partition.
 	// TODO: See if there is a way to remove conditions that does not
 	// apply for some partitions like:
@@ -1053,6 +1051,91 @@ func (s *partitionProcessor) processListPartition(ds *DataSource, pi *model.Part
 	return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi), opt)
 }
 
+// pruneSubPartitionedTable prunes a subpartitioned table on both levels, the
+// partitions by RANGE or LIST and the subpartitions by HASH or KEY. It returns
+// the indexes of the used subpartitions in pi.Definitions.
+func (s *partitionProcessor) pruneSubPartitionedTable(ctx PlanContext, tbl table.PartitionedTable,
+	partitionNames []model.CIStr, conds []expression.Expression,
+	columns []*expression.Column, names types.NameSlice) ([]int, error) {
+	pi := tbl.Meta().Partition
+	// Prune the partitions as if the table was not subpartitioned.
+	topPi := *pi
+	topPi.Definitions = pi.TopLevelDefinitions(pi.Definitions)
+	topPi.Sub = nil
+	var used []int
+	switch pi.Type {
+	case model.PartitionTypeRange:
+		rangeOr, err := s.pruneRangePartition(ctx, &topPi, tbl, conds, columns, names)
+		if err != nil {
+			return nil, err
+		}
+		used = s.convertToIntSlice(rangeOr, &topPi, nil)
+	case model.PartitionTypeList:
+		var err error
+		used, err = s.findUsedListPartitions(ctx, &topPi, tbl.(partitionTable).PartitionExpr(), nil, conds, columns)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		used = []int{FullRange}
+	}
+	if len(used) == 1 && used[0] == FullRange {
+		used = make([]int, len(topPi.Definitions))
+		for i := range used {
+			used[i] = i
+		}
+	}
+
+	// Prune the subpartitions, which are the same for every partition.
+	sub := pi.Sub
+	subPi := &model.PartitionInfo{
+		Type:        sub.Type,
+		Expr:        sub.Expr,
+		Columns:     sub.Columns,
+		Num:         sub.Num,
+		Definitions: pi.Definitions[:sub.Num],
+	}
+	subUsed, err := s.getUsedPartitions(ctx, subPi, tbl.(partitionTable).SubPartitionExpr(), nil, columns, conds, names)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(subUsed, FullRange) {
+		subUsed = make([]int, sub.Num)
+		for i := range subUsed {
+			subUsed[i] = i
+		}
+	}
+	slices.Sort(subUsed)
+	subUsed = slices.Compact(subUsed)
+
+	ret := make([]int, 0, len(used)*len(subUsed))
+	for _, i := range used {
+		for _, j := range subUsed {
+			idx := i*int(sub.Num) + j
+			if len(partitionNames) > 0 && !s.findByName(partitionNames, pi.Definitions[idx].Name.L) {
+				continue
+			}
+			ret = append(ret, idx)
+		}
+	}
+	if len(ret) == len(pi.Definitions) && len(ret) > 1 {
+		return []int{FullRange}, nil
+	}
+	return ret, nil
+}
+
+func (s *partitionProcessor) processSubPartitionedTable(ds *DataSource, pi *model.PartitionInfo, opt *util.LogicalOptimizeOp) (LogicalPlan, error) {
+	names, err := s.reconstructTableColNames(ds)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.pruneSubPartitionedTable(ds.SCtx(), ds.table.(table.PartitionedTable), ds.partitionNames, ds.allConds, ds.TblCols, names)
+	if err != nil {
+		return nil, err
+	}
+	return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi), opt)
+}
+
 // makePartitionByFnCol extracts the column and function information in 'partition by ... fn(col)'.
 func makePartitionByFnCol(sctx PlanContext, columns []*expression.Column, names types.NameSlice, partitionExpr string) (*expression.Column, *expression.ScalarFunction, monotoneMode, error) {
 	monotonous := monotoneModeInvalid
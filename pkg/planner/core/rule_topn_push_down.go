@@ -43,6 +43,15 @@ func (s *baseLogicalPlan) pushDownTopN(topN *LogicalTopN, opt *util.LogicalOptim
 	return p
 }
 
+func (ds *DataSource) pushDownTopN(topN *LogicalTopN, opt *util.LogicalOptimizeOp) LogicalPlan {
+	// A TopN may be a vector search if it's ordered by the distance first, see generateVectorSearchPath.
+	if topN != nil && !topN.isLimit() {
+		ds.vectorSearchOrder = topN.ByItems[0]
+		ds.vectorSearchCount = topN.Count + topN.Offset
+	}
+	return ds.baseLogicalPlan.pushDownTopN(topN, opt)
+}
+
 func (p *LogicalCTE) pushDownTopN(topN *LogicalTopN, opt *util.LogicalOptimizeOp) LogicalPlan {
 	if topN != nil {
 		return topN.setChild(p, opt)
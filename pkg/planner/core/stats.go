@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/cardinality"
@@ -211,6 +212,47 @@ func (ds *DataSource) getGroupNDVs(colGroups [][]*expression.Column) []property.
 			}
 		}
 	}
+	if tbl.ExtStats == nil {
+		return ndvs
+	}
+	colInfoID2UniqueID := make(map[int64]int64, len(tbl.UniqueID2colInfoID))
+	for uniqueID, colInfoID := range tbl.UniqueID2colInfoID {
+		colInfoID2UniqueID[colInfoID] = uniqueID
+	}
+	// The NDVs of the column groups covered by the cardinality extended stats.
+	for _, item := range tbl.ExtStats.Stats {
+		if item.Tp != ast.StatsTypeCardinality || item.ScalarVals <= 0 {
+			continue
+		}
+		cols := make([]int64, 0, len(item.ColIDs))
+		for _, id := range item.ColIDs {
+			uniqueID, ok := colInfoID2UniqueID[id]
+			if !ok {
+				break
+			}
+			cols = append(cols, uniqueID)
+		}
+		if len(cols) != len(item.ColIDs) {
+			continue
+		}
+		slices.Sort(cols)
+		for _, g := range colGroups {
+			if len(g) != len(cols) {
+				continue
+			}
+			match := true
+			for i, col := range g {
+				if col.UniqueID != cols[i] {
+					match = false
+					break
+				}
+			}
+			if match {
+				ndvs = append(ndvs, property.GroupNDV{Cols: cols, NDV: item.ScalarVals})
+				break
+			}
+		}
+	}
 	return ndvs
 }
 
@@ -256,6 +298,9 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 		HistColl:     ds.statisticTable.GenerateHistCollFromColumnInfo(ds.tableInfo, ds.TblCols),
 		StatsVersion: ds.statisticTable.Version,
 	}
+	if ds.SCtx().GetSessionVars().EnableExtendedStats {
+		tableStats.HistColl.ExtStats = ds.statisticTable.ExtendedStats
+	}
 	if ds.statisticTable.Pseudo {
 		tableStats.StatsVersion = statistics.PseudoVersion
 	}
@@ -496,6 +541,7 @@ func (ds *DataSource) DeriveStats(_ []*property.StatsInfo, _ *expression.Schema,
 	if err := ds.generateIndexMergePath(); err != nil {
 		return nil, err
 	}
+	ds.generateVectorSearchPath()
 
 	if ds.SCtx().GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
 		debugTraceAccessPaths(ds.SCtx(), ds.possibleAccessPaths)
@@ -811,6 +857,7 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		leftJoinKeys, rightJoinKeys,
 		childSchema[0], childSchema[1],
 		nil, nil)
+	p.equalCondOutCnt *= p.adjustJoinRowCountByFeedback(leftJoinKeys, rightJoinKeys)
 	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
 		p.SetStats(&property.StatsInfo{
 			RowCount: leftProfile.RowCount * SelectionFactor,
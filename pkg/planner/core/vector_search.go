@@ -0,0 +1,158 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"math"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/planner/util"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	h "github.com/pingcap/tidb/pkg/util/hint"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
+)
+
+// generateVectorSearchPath generates the path to access a vector index by a vector search, which is a TopN ordered by
+// the distance between the indexed column and a constant vector, e.g.
+// `SELECT * FROM t ORDER BY VEC_COSINE_DISTANCE(v, '[1,2,3]') LIMIT 10`.
+// Only the rows in the `tidb_vector_search_nprobe` lists nearest to the constant vector are read, so the result is
+// approximate. The rows whose vectors are NULL are read too, because their distances are NULL and go first.
+// If there are filters, more lists are read so that the expected number of the rows passing the filters is enough
+// for the TopN. The path is added to the other paths, and the cheapest one is chosen by the cost.
+func (ds *DataSource) generateVectorSearchPath() {
+	nprobe := ds.SCtx().GetSessionVars().VectorSearchNProbe
+	if ds.vectorSearchOrder == nil || ds.vectorSearchOrder.Desc || nprobe <= 0 {
+		return
+	}
+	if _, ok := ds.SCtx().GetSessionVars().GetIsolationReadEngines()[kv.TiKV]; !ok || ds.preferStoreType&h.PreferTiFlash != 0 {
+		return
+	}
+	// A point lookup is exact and cheaper than a vector search.
+	for _, path := range ds.possibleAccessPaths {
+		if len(path.Ranges) == 0 || ((path.IsTablePath() || path.Index.Unique) && path.OnlyPointRange(ds.SCtx().GetSessionVars().StmtCtx.TypeCtx())) {
+			return
+		}
+	}
+	sf, ok := ds.vectorSearchOrder.Expr.(*expression.ScalarFunction)
+	if !ok {
+		return
+	}
+	metric, ok := model.DistanceMetric4VectorIndex[sf.FuncName.L]
+	if !ok {
+		return
+	}
+	col, query := extractVectorSearchArgs(sf.GetArgs())
+	if col == nil {
+		return
+	}
+	for _, idx := range ds.tableInfo.Indices {
+		if idx.VectorInfo == nil || idx.VectorInfo.DistanceMetric != metric || idx.State != model.StatePublic {
+			continue
+		}
+		if idx.Invisible && !ds.SCtx().GetSessionVars().OptimizerUseInvisibleIndexes {
+			continue
+		}
+		idxCols, ok := PrepareIdxColsAndUnwrapArrayType(ds.tableInfo, idx, ds.TblCols, false)
+		if !ok || !col.EqualColumn(idxCols[0]) {
+			continue
+		}
+		val, err := query.Eval(ds.SCtx().GetExprCtx().GetEvalCtx(), chunk.Row{})
+		if err != nil || val.IsNull() {
+			return
+		}
+		v, err := types.ConvertToVectorFloat32(val.GetBytes())
+		if err != nil || len(v) != idx.VectorInfo.Dimension {
+			return
+		}
+		if expression.MaybeOverOptimized4PlanCache(ds.SCtx().GetExprCtx(), []expression.Expression{query}) {
+			ds.SCtx().GetSessionVars().StmtCtx.SetSkipPlanCache(errors.NewNoStackError("vector search with a parameter is used to access the vector index"))
+		}
+		lists := vectorindex.Probe(idx.VectorInfo, v, ds.vectorSearchNProbe(idx.VectorInfo, nprobe))
+		path := &util.AccessPath{Index: idx}
+		path.IdxCols = idxCols
+		path.IdxColLens = []int{idx.Columns[0].Length}
+		path.FullIdxCols = idxCols
+		path.FullIdxColLens = []int{idx.Columns[0].Length}
+		path.Ranges = ranger.NullRange()
+		for _, list := range lists {
+			d := vectorindex.ListDatum(list)
+			path.Ranges = append(path.Ranges, &ranger.Range{
+				LowVal:    []types.Datum{d},
+				HighVal:   []types.Datum{d},
+				Collators: collate.GetBinaryCollatorSlice(1),
+			})
+		}
+		// The index entries are the lists rather than the vectors, so all the filters are table filters.
+		path.TableFilters = ds.pushedDownConds
+		// The untrained list is assumed to be small once the centroids are trained.
+		path.CountAfterAccess = float64(ds.tableStats.HistColl.RealtimeCount)
+		if len(idx.VectorInfo.Centroids) > 0 {
+			path.CountAfterAccess *= float64(len(lists)-1) / float64(vectorindex.NumListsOf(idx.VectorInfo))
+		}
+		path.CountAfterAccess = max(path.CountAfterAccess, 1)
+		path.CountAfterIndex = path.CountAfterAccess
+		// The path has no access conditions, so it must be forced to be considered.
+		path.Forced = true
+		ds.possibleAccessPaths = append(ds.possibleAccessPaths, path)
+		return
+	}
+}
+
+// isVectorSearchPath checks whether the path is generated by generateVectorSearchPath.
+func isVectorSearchPath(path *util.AccessPath) bool {
+	return path.Index != nil && path.Index.VectorInfo != nil
+}
+
+// vectorSearchOverFetchRatio is the ratio of the rows read by a vector search to the rows expected to be required by
+// the TopN, because the estimated selectivity of the filters is inaccurate and the rows aren't evenly distributed.
+const vectorSearchOverFetchRatio = 2
+
+// vectorSearchNProbe returns the number of the lists read by a vector search, which is at least nprobe. If there are
+// filters, the number is increased by the selectivity of the filters, so the rows passing the filters are expected
+// to be enough for the TopN.
+func (ds *DataSource) vectorSearchNProbe(info *model.VectorIndexInfo, nprobe int) int {
+	numLists := vectorindex.NumListsOf(info)
+	rowCount := float64(ds.tableStats.HistColl.RealtimeCount)
+	if len(ds.pushedDownConds) == 0 || rowCount <= 0 || nprobe >= numLists {
+		return nprobe
+	}
+	selectivity := ds.StatsInfo().RowCount / rowCount
+	expectedRows := float64(ds.vectorSearchCount) * vectorSearchOverFetchRatio
+	if selectivity <= 0 || expectedRows/selectivity >= rowCount {
+		return numLists
+	}
+	return max(nprobe, int(math.Ceil(expectedRows/selectivity/(rowCount/float64(numLists)))))
+}
+
+// extractVectorSearchArgs extracts the column and the constant vector from the arguments of a distance function.
+func extractVectorSearchArgs(args []expression.Expression) (*expression.Column, *expression.Constant) {
+	if len(args) != 2 {
+		return nil, nil
+	}
+	for i := range args {
+		col, ok1 := args[i].(*expression.Column)
+		query, ok2 := args[1-i].(*expression.Constant)
+		if ok1 && ok2 {
+			return col, query
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,347 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefMaxIndexesPerTable is the default max number of indexes advised for a table.
+	DefMaxIndexesPerTable = 3
+	// DefMaxIndexesPerDB is the default max number of indexes advised for a database.
+	DefMaxIndexesPerDB = 10
+	// DefMaxIndexColumns is the default max number of columns of an advised index.
+	DefMaxIndexColumns = 3
+	// DefMaxQueries is the default number of statements taken from the statements summary.
+	DefMaxQueries = 100
+
+	// minCostReduction is the min ratio of the plan cost that an index should
+	// reduce to be considered useful for a query.
+	minCostReduction = 0.01
+	// maxCandidatesPerRound limits the candidates evaluated in each round of
+	// the greedy search, they are the ones with the largest benefit alone.
+	maxCandidatesPerRound = 32
+)
+
+// Query is a statement of the workload.
+type Query struct {
+	SchemaName string
+	Text       string
+	// Digest is the SQL digest of the statement, it's computed from Text if empty.
+	Digest string
+	// Frequency is the execution count of the statement in the workload.
+	Frequency int64
+}
+
+// Optimizer estimates the cost of the plan of a statement.
+type Optimizer interface {
+	// QueryPlanCost returns the cost of the best plan of the statement running
+	// in the schema, when the given hypothetical indexes are also available.
+	QueryPlanCost(ctx context.Context, schema, sql string, indexes []*Index) (float64, error)
+}
+
+// Options controls the scope of the index advisor.
+type Options struct {
+	MaxIndexesPerTable int
+	MaxIndexesPerDB    int
+	MaxIndexColumns    int
+	// Deadline is the time after which the advisor stops searching and returns
+	// the indexes found so far. Zero means no deadline.
+	Deadline time.Time
+}
+
+func (o *Options) adjust() {
+	if o.MaxIndexesPerTable <= 0 {
+		o.MaxIndexesPerTable = DefMaxIndexesPerTable
+	}
+	if o.MaxIndexesPerDB <= 0 {
+		o.MaxIndexesPerDB = DefMaxIndexesPerDB
+	}
+	if o.MaxIndexColumns <= 0 {
+		o.MaxIndexColumns = DefMaxIndexColumns
+	}
+}
+
+// Recommendation is an index advised for the workload.
+type Recommendation struct {
+	*Index
+	// Benefit is the estimated reduction of the workload cost, which is the
+	// sum of the plan costs of the statements weighted by their frequencies.
+	Benefit float64
+	// BenefitRatio is Benefit divided by the original cost of the statements
+	// that have candidate indexes.
+	BenefitRatio float64
+	// Digests are the SQL digests of the statements that the index speeds up.
+	Digests []string
+}
+
+// query is a statement of the workload being evaluated.
+type query struct {
+	*Query
+	// sql is the restored text of the statement that is sent to the optimizer.
+	sql    string
+	tables map[string]struct{}
+	// cost is the plan cost with the indexes chosen so far.
+	cost float64
+}
+
+type candidate struct {
+	idx     *Index
+	queries []*query
+	benefit float64
+}
+
+type advisor struct {
+	ctx     context.Context
+	opt     Optimizer
+	options Options
+	chosen  []*Index
+}
+
+// AdviseIndexes enumerates the candidate indexes for the workload, estimates
+// their benefits with hypothetical indexes and returns the recommended ones,
+// ordered by their benefits.
+//
+// The indexes are chosen greedily: each round picks the candidate that reduces
+// the workload cost the most, taking the indexes chosen by former rounds into
+// account, until no candidate reduces the cost or the limits are reached.
+func AdviseIndexes(ctx context.Context, is infoschema.InfoSchema, opt Optimizer, workload []*Query, options Options) ([]*Recommendation, error) {
+	options.adjust()
+	a := &advisor{ctx: ctx, opt: opt, options: options}
+
+	candidates := make(map[string]*candidate)
+	queries := make([]*query, 0, len(workload))
+	for _, q := range workload {
+		indexes, qry := analyzeQuery(is, q, options.MaxIndexColumns)
+		if qry == nil || len(indexes) == 0 {
+			continue
+		}
+		for _, idx := range indexes {
+			if _, ok := candidates[idx.Key()]; !ok {
+				candidates[idx.Key()] = &candidate{idx: idx}
+			}
+		}
+		queries = append(queries, qry)
+	}
+
+	var totalCost float64
+	valid := make(map[*query]struct{}, len(queries))
+	for _, q := range queries {
+		if err := ctx.Err(); err != nil || a.expired() {
+			return nil, err
+		}
+		cost, err := a.opt.QueryPlanCost(ctx, q.SchemaName, q.sql, nil)
+		if err != nil {
+			logutil.BgLogger().Info("skip the statement that fails to get the plan cost",
+				zap.String("category", "index-advisor"), zap.String("digest", q.Digest), zap.Error(err))
+			continue
+		}
+		q.cost = cost
+		totalCost += cost * float64(q.Frequency)
+		valid[q] = struct{}{}
+	}
+	if totalCost <= 0 {
+		return nil, nil
+	}
+
+	// A candidate is evaluated against all the statements accessing its table,
+	// not only the ones it's generated from.
+	pool := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		for _, q := range queries {
+			if _, ok := valid[q]; !ok {
+				continue
+			}
+			if _, ok := q.tables[c.idx.tableKey()]; ok {
+				c.queries = append(c.queries, q)
+			}
+		}
+		if len(c.queries) > 0 {
+			pool = append(pool, c)
+		}
+	}
+	// Make the search deterministic.
+	slices.SortFunc(pool, func(i, j *candidate) int {
+		return strings.Compare(i.idx.Key(), j.idx.Key())
+	})
+
+	// Estimate the benefit of each candidate alone, the ones that help nothing
+	// are dropped.
+	for _, c := range pool {
+		if a.expired() {
+			break
+		}
+		benefit, _, err := a.evaluate(c)
+		if err != nil {
+			return nil, err
+		}
+		c.benefit = benefit
+	}
+	pool = slices.DeleteFunc(pool, func(c *candidate) bool { return c.benefit <= 0 })
+	slices.SortStableFunc(pool, func(i, j *candidate) int {
+		switch {
+		case i.benefit > j.benefit:
+			return -1
+		case i.benefit < j.benefit:
+			return 1
+		}
+		return 0
+	})
+
+	var (
+		result   []*Recommendation
+		perTable = make(map[string]int)
+		perDB    = make(map[string]int)
+	)
+	for len(pool) > 0 && !a.expired() {
+		var (
+			best        *candidate
+			bestBenefit float64
+			bestCosts   map[*query]float64
+		)
+		evaluated := 0
+		for _, c := range pool {
+			if perTable[c.idx.tableKey()] >= a.options.MaxIndexesPerTable || perDB[c.idx.SchemaName.L] >= a.options.MaxIndexesPerDB {
+				continue
+			}
+			if evaluated >= maxCandidatesPerRound || a.expired() {
+				break
+			}
+			evaluated++
+			benefit, costs, err := a.evaluate(c)
+			if err != nil {
+				return nil, err
+			}
+			if benefit > bestBenefit {
+				best, bestBenefit, bestCosts = c, benefit, costs
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		rec := &Recommendation{
+			Index:        best.idx,
+			Benefit:      bestBenefit,
+			BenefitRatio: bestBenefit / totalCost,
+		}
+		for _, q := range best.queries {
+			if cost, ok := bestCosts[q]; ok {
+				q.cost = cost
+				if !slices.Contains(rec.Digests, q.Digest) {
+					rec.Digests = append(rec.Digests, q.Digest)
+				}
+			}
+		}
+		result = append(result, rec)
+		a.chosen = append(a.chosen, best.idx)
+		perTable[best.idx.tableKey()]++
+		perDB[best.idx.SchemaName.L]++
+		pool = slices.DeleteFunc(pool, func(c *candidate) bool { return c == best })
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// evaluate estimates how much the candidate reduces the workload cost when it
+// is added to the chosen indexes. It returns the reduced plan costs of the
+// statements that the candidate speeds up.
+func (a *advisor) evaluate(c *candidate) (float64, map[*query]float64, error) {
+	var benefit float64
+	costs := make(map[*query]float64)
+	for _, q := range c.queries {
+		if err := a.ctx.Err(); err != nil {
+			return 0, nil, err
+		}
+		indexes := make([]*Index, 0, len(a.chosen)+1)
+		for _, idx := range a.chosen {
+			if _, ok := q.tables[idx.tableKey()]; ok {
+				indexes = append(indexes, idx)
+			}
+		}
+		indexes = append(indexes, c.idx)
+		cost, err := a.opt.QueryPlanCost(a.ctx, q.SchemaName, q.sql, indexes)
+		if err != nil {
+			logutil.BgLogger().Info("fail to get the plan cost with hypothetical indexes",
+				zap.String("category", "index-advisor"), zap.String("digest", q.Digest),
+				zap.String("index", c.idx.Key()), zap.Error(err))
+			continue
+		}
+		if cost < q.cost*(1-minCostReduction) {
+			benefit += (q.cost - cost) * float64(q.Frequency)
+			costs[q] = cost
+		}
+	}
+	return benefit, costs, nil
+}
+
+func (a *advisor) expired() bool {
+	return !a.options.Deadline.IsZero() && time.Now().After(a.options.Deadline)
+}
+
+// analyzeQuery parses the statement and returns its candidate indexes. It
+// returns a nil query if the statement can't benefit from indexes.
+func analyzeQuery(is infoschema.InfoSchema, q *Query, maxColumns int) ([]*Index, *query) {
+	stmt, err := parser.New().ParseOneStmt(q.Text, "", "")
+	if err != nil {
+		logutil.BgLogger().Info("skip the statement that fails to be parsed",
+			zap.String("category", "index-advisor"), zap.String("digest", q.Digest), zap.Error(err))
+		return nil, nil
+	}
+	switch stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return nil, nil
+	}
+	var sb strings.Builder
+	if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return nil, nil
+	}
+
+	analyzer := &queryAnalyzer{is: is, defaultSchema: model.NewCIStr(q.SchemaName)}
+	stmt.Accept(analyzer)
+
+	cp := *q
+	qry := &query{Query: &cp, sql: sb.String(), tables: make(map[string]struct{}, len(analyzer.refs))}
+	for _, ref := range analyzer.refs {
+		qry.tables[ref.key()] = struct{}{}
+	}
+	if qry.Digest == "" {
+		_, digest := parser.NormalizeDigest(q.Text)
+		qry.Digest = digest.String()
+	}
+	if qry.Frequency <= 0 {
+		qry.Frequency = 1
+	}
+	var indexes []*Index
+	for _, cols := range analyzer.indexableColumns() {
+		indexes = append(indexes, cols.candidateIndexes(maxColumns)...)
+	}
+	return indexes, qry
+}
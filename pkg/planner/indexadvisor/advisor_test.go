@@ -0,0 +1,159 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/ddl"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func mockInfoSchema(t *testing.T, sqls ...string) infoschema.InfoSchema {
+	tblInfos := make([]*model.TableInfo, 0, len(sqls))
+	for i, sql := range sqls {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		require.NoError(t, err)
+		tblInfo, err := ddl.BuildTableInfoFromAST(stmt.(*ast.CreateTableStmt))
+		require.NoError(t, err)
+		tblInfo.ID = int64(i + 1)
+		for _, idx := range tblInfo.Indices {
+			idx.State = model.StatePublic
+		}
+		tblInfos = append(tblInfos, tblInfo)
+	}
+	return infoschema.MockInfoSchema(tblInfos)
+}
+
+func candidateKeys(t *testing.T, is infoschema.InfoSchema, sql string) []string {
+	indexes, q := analyzeQuery(is, &Query{SchemaName: "test", Text: sql}, DefMaxIndexColumns)
+	require.NotNil(t, q)
+	keys := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		keys = append(keys, idx.Key())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestCandidateIndexes(t *testing.T) {
+	is := mockInfoSchema(t,
+		"create table t1 (a int, b int, c int, d varchar(10), e text, f int primary key, key idx_d(d))",
+		"create table t2 (a int, b int, c int)",
+	)
+
+	require.Equal(t, []string{"test.t1(a)", "test.t1(a,b)", "test.t1(b)"},
+		candidateKeys(t, is, "select * from t1 where a = 1 and b > 2"))
+	// Existing indexes, the int handle and text columns are skipped.
+	require.Empty(t, candidateKeys(t, is, "select * from t1 where d = 'x' and e = 'y'"))
+	require.Empty(t, candidateKeys(t, is, "select * from t1 where f = 1"))
+	require.Equal(t, []string{"test.t1(a)", "test.t1(a,c)", "test.t1(c)", "test.t2(a)", "test.t2(b)", "test.t2(b,a)"},
+		candidateKeys(t, is, "select * from t1 x join t2 on x.a = t2.a where t2.b = 1 order by x.c"))
+	require.Equal(t, []string{"test.t1(a)", "test.t1(b)", "test.t1(c)", "test.t1(c,a)", "test.t1(c,b)"},
+		candidateKeys(t, is, "update t1 set a = 1 where c in (1, 2) and a between 1 and 5 and b like 'x%'"))
+	// Ambiguous columns, OR conditions and non-constant comparisons are ignored.
+	require.Equal(t, []string{"test.t2(c)"},
+		candidateKeys(t, is, "delete from t2 where t2.c is null and (a = 1 or b = 2) and a > b and exists (select 1 from t1 where t1.c = c)"))
+
+	indexes, _ := analyzeQuery(is, &Query{SchemaName: "test", Text: "select * from t2 where a = 1 and b = 2"}, DefMaxIndexColumns)
+	for _, idx := range indexes {
+		if idx.Key() == "test.t2(a,b)" {
+			require.Equal(t, "CREATE INDEX `idx_a_b` ON `test`.`t2`(`a`, `b`)", idx.DDL())
+			info := idx.HypoIndexInfo()
+			require.Equal(t, model.IndexTypeHypo, info.Tp)
+			require.Equal(t, 2, len(info.Columns))
+			require.Equal(t, 1, info.Columns[1].Offset)
+		}
+	}
+}
+
+// mockOptimizer returns the plan cost of a statement by its marker, each index
+// reduces the cost to the value in indexCosts and the lowest one wins.
+type mockOptimizer struct {
+	baseCosts  map[string]float64
+	indexCosts map[string]map[string]float64
+}
+
+func (o *mockOptimizer) QueryPlanCost(_ context.Context, _, sql string, indexes []*Index) (float64, error) {
+	for marker, cost := range o.baseCosts {
+		if !strings.Contains(sql, marker) {
+			continue
+		}
+		for _, idx := range indexes {
+			if c, ok := o.indexCosts[marker][idx.Key()]; ok && c < cost {
+				cost = c
+			}
+		}
+		return cost, nil
+	}
+	return 0, nil
+}
+
+func TestAdviseIndexes(t *testing.T) {
+	is := mockInfoSchema(t,
+		"create table t1 (a int, b int, c int)",
+		"create table t2 (a int, b int, c int)",
+	)
+	workload := []*Query{
+		{SchemaName: "test", Text: "select * from t1 where a = 11 and b > 12", Digest: "d1", Frequency: 10},
+		{SchemaName: "test", Text: "select * from t1 where c = 21", Digest: "d2", Frequency: 1},
+		{SchemaName: "test", Text: "select * from t2 where a = 31", Digest: "d3", Frequency: 1},
+		{SchemaName: "test", Text: "select * from t1 where a = 41", Digest: "d4", Frequency: 2},
+		{SchemaName: "test", Text: "insert into t1 values (1, 2, 3)"},
+	}
+	opt := &mockOptimizer{
+		baseCosts: map[string]float64{"11": 100, "21": 100, "31": 100, "41": 100},
+		indexCosts: map[string]map[string]float64{
+			"11": {"test.t1(a)": 20, "test.t1(b)": 50, "test.t1(a,b)": 5},
+			"21": {"test.t1(c)": 10},
+			// The index barely helps.
+			"31": {"test.t2(a)": 99.5},
+			"41": {"test.t1(a)": 10, "test.t1(a,b)": 12},
+		},
+	}
+
+	recs, err := AdviseIndexes(context.Background(), is, opt, workload, Options{})
+	require.NoError(t, err)
+	require.Len(t, recs, 3)
+	require.Equal(t, "test.t1(a,b)", recs[0].Key())
+	require.Equal(t, 10*95+2*88.0, recs[0].Benefit)
+	require.InDelta(t, (10*95+2*88.0)/1400, recs[0].BenefitRatio, 1e-9)
+	require.Equal(t, []string{"d1", "d4"}, recs[0].Digests)
+	require.Equal(t, "test.t1(c)", recs[1].Key())
+	require.Equal(t, 90.0, recs[1].Benefit)
+	require.Equal(t, []string{"d2"}, recs[1].Digests)
+	require.Equal(t, "CREATE INDEX `idx_c` ON `test`.`t1`(`c`)", recs[1].DDL())
+	// (a) only improves d4 a little once (a,b) is chosen.
+	require.Equal(t, "test.t1(a)", recs[2].Key())
+	require.Equal(t, 4.0, recs[2].Benefit)
+	require.Equal(t, []string{"d4"}, recs[2].Digests)
+
+	recs, err = AdviseIndexes(context.Background(), is, opt, workload, Options{MaxIndexesPerTable: 1})
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	require.Equal(t, "test.t1(a,b)", recs[0].Key())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = AdviseIndexes(ctx, is, opt, workload, Options{})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,415 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+	"github.com/pingcap/tidb/pkg/util"
+)
+
+// maxIndexedStringLen is the max length of a string column that can be put
+// into an index without a prefix length, assuming utf8mb4.
+const maxIndexedStringLen = 3072 / 4
+
+// tableRef is a table referenced by a query.
+type tableRef struct {
+	schema  model.CIStr
+	alias   model.CIStr
+	tblInfo *model.TableInfo
+}
+
+func (t *tableRef) key() string {
+	return t.schema.L + "." + t.tblInfo.Name.L
+}
+
+// indexableColumns are the columns of a table that an index may help a query
+// to access, in the order they are first seen in the query.
+type indexableColumns struct {
+	ref *tableRef
+	// eq are the columns compared by equal conditions, IN lists or join keys.
+	eq []*model.ColumnInfo
+	// ranges are the columns compared by range conditions.
+	ranges []*model.ColumnInfo
+	// orders are the column lists of ORDER BY and GROUP BY clauses.
+	orders [][]*model.ColumnInfo
+}
+
+func appendColumn(cols []*model.ColumnInfo, col *model.ColumnInfo) []*model.ColumnInfo {
+	for _, c := range cols {
+		if c.ID == col.ID {
+			return cols
+		}
+	}
+	return append(cols, col)
+}
+
+// queryAnalyzer collects the indexable columns of the tables referenced by a
+// statement. All the tables of the statement, including the ones in subqueries,
+// are put into one scope, and unqualified column names that are ambiguous in
+// this scope are ignored.
+type queryAnalyzer struct {
+	is            infoschema.InfoSchema
+	defaultSchema model.CIStr
+
+	refs   []*tableRef
+	conds  []ast.ExprNode
+	orders [][]*ast.ByItem
+}
+
+// Enter implements ast.Visitor interface.
+func (a *queryAnalyzer) Enter(n ast.Node) (ast.Node, bool) {
+	switch x := n.(type) {
+	case *ast.TableSource:
+		if tn, ok := x.Source.(*ast.TableName); ok {
+			a.addTable(tn, x.AsName)
+		}
+	case *ast.SelectStmt:
+		a.conds = append(a.conds, x.Where)
+		if x.GroupBy != nil {
+			a.orders = append(a.orders, x.GroupBy.Items)
+		}
+		if x.OrderBy != nil {
+			a.orders = append(a.orders, x.OrderBy.Items)
+		}
+	case *ast.Join:
+		if x.On != nil {
+			a.conds = append(a.conds, x.On.Expr)
+		}
+	case *ast.UpdateStmt:
+		a.conds = append(a.conds, x.Where)
+		if x.Order != nil {
+			a.orders = append(a.orders, x.Order.Items)
+		}
+	case *ast.DeleteStmt:
+		a.conds = append(a.conds, x.Where)
+		if x.Order != nil {
+			a.orders = append(a.orders, x.Order.Items)
+		}
+	}
+	return n, false
+}
+
+// Leave implements ast.Visitor interface.
+func (*queryAnalyzer) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func (a *queryAnalyzer) addTable(tn *ast.TableName, asName model.CIStr) {
+	schema := tn.Schema
+	if schema.L == "" {
+		schema = a.defaultSchema
+	}
+	if schema.L == "" || util.IsMemOrSysDB(schema.L) {
+		return
+	}
+	tbl, err := a.is.TableByName(schema, tn.Name)
+	if err != nil {
+		return
+	}
+	tblInfo := tbl.Meta()
+	if tblInfo.IsView() || tblInfo.IsSequence() || tblInfo.TempTableType != model.TempTableNone {
+		return
+	}
+	alias := asName
+	if alias.L == "" {
+		alias = tn.Name
+	}
+	a.refs = append(a.refs, &tableRef{schema: schema, alias: alias, tblInfo: tblInfo})
+}
+
+type columnRef struct {
+	table *tableRef
+	col   *model.ColumnInfo
+}
+
+// resolveColumn finds the table column referenced by expr.
+func (a *queryAnalyzer) resolveColumn(expr ast.ExprNode) *columnRef {
+	for {
+		p, ok := expr.(*ast.ParenthesesExpr)
+		if !ok {
+			break
+		}
+		expr = p.Expr
+	}
+	colExpr, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return nil
+	}
+	name := colExpr.Name
+	var found *columnRef
+	for _, ref := range a.refs {
+		if name.Table.L != "" {
+			if ref.alias.L != name.Table.L || (name.Schema.L != "" && ref.schema.L != name.Schema.L) {
+				continue
+			}
+		}
+		col := model.FindColumnInfo(ref.tblInfo.Columns, name.Name.L)
+		if col == nil || col.Hidden {
+			continue
+		}
+		if found != nil && found.table.key() != ref.key() {
+			// Ambiguous in the flattened scope.
+			return nil
+		}
+		found = &columnRef{table: ref, col: col}
+	}
+	return found
+}
+
+// isConstant checks whether the expression has no column reference and no subquery.
+func isConstant(expr ast.ExprNode) bool {
+	checker := &constantChecker{isConst: true}
+	expr.Accept(checker)
+	return checker.isConst
+}
+
+type constantChecker struct {
+	isConst bool
+}
+
+// Enter implements ast.Visitor interface.
+func (c *constantChecker) Enter(n ast.Node) (ast.Node, bool) {
+	switch n.(type) {
+	case *ast.ColumnNameExpr, *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.DefaultExpr:
+		c.isConst = false
+		return n, true
+	}
+	return n, false
+}
+
+// Leave implements ast.Visitor interface.
+func (c *constantChecker) Leave(n ast.Node) (ast.Node, bool) {
+	return n, c.isConst
+}
+
+func splitConjunctions(expr ast.ExprNode, result []ast.ExprNode) []ast.ExprNode {
+	switch x := expr.(type) {
+	case nil:
+		return result
+	case *ast.ParenthesesExpr:
+		return splitConjunctions(x.Expr, result)
+	case *ast.BinaryOperationExpr:
+		if x.Op == opcode.LogicAnd {
+			return splitConjunctions(x.R, splitConjunctions(x.L, result))
+		}
+	}
+	return append(result, expr)
+}
+
+// indexableColumns returns the indexable columns of each referenced table,
+// in the order the tables are referenced.
+func (a *queryAnalyzer) indexableColumns() []*indexableColumns {
+	tables := make(map[string]*indexableColumns, len(a.refs))
+	result := make([]*indexableColumns, 0, len(a.refs))
+	get := func(ref *tableRef) *indexableColumns {
+		cols, ok := tables[ref.key()]
+		if !ok {
+			cols = &indexableColumns{ref: ref}
+			tables[ref.key()] = cols
+			result = append(result, cols)
+		}
+		return cols
+	}
+	addEq := func(c *columnRef) {
+		t := get(c.table)
+		t.eq = appendColumn(t.eq, c.col)
+	}
+	addRange := func(c *columnRef) {
+		t := get(c.table)
+		t.ranges = appendColumn(t.ranges, c.col)
+	}
+
+	var conds []ast.ExprNode
+	for _, cond := range a.conds {
+		conds = splitConjunctions(cond, conds)
+	}
+	for _, cond := range conds {
+		switch x := cond.(type) {
+		case *ast.BinaryOperationExpr:
+			l, r := a.resolveColumn(x.L), a.resolveColumn(x.R)
+			switch x.Op {
+			case opcode.EQ, opcode.NullEQ:
+				switch {
+				case l != nil && r != nil:
+					// Join keys.
+					addEq(l)
+					addEq(r)
+				case l != nil && isConstant(x.R):
+					addEq(l)
+				case r != nil && isConstant(x.L):
+					addEq(r)
+				}
+			case opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+				if l != nil && isConstant(x.R) {
+					addRange(l)
+				} else if r != nil && isConstant(x.L) {
+					addRange(r)
+				}
+			}
+		case *ast.PatternInExpr:
+			if c := a.resolveColumn(x.Expr); c != nil && !x.Not && x.Sel == nil {
+				allConst := true
+				for _, item := range x.List {
+					allConst = allConst && isConstant(item)
+				}
+				if allConst {
+					addEq(c)
+				}
+			}
+		case *ast.IsNullExpr:
+			if c := a.resolveColumn(x.Expr); c != nil && !x.Not {
+				addEq(c)
+			}
+		case *ast.BetweenExpr:
+			if c := a.resolveColumn(x.Expr); c != nil && !x.Not && isConstant(x.Left) && isConstant(x.Right) {
+				addRange(c)
+			}
+		case *ast.PatternLikeOrIlikeExpr:
+			c := a.resolveColumn(x.Expr)
+			if c == nil || x.Not || !x.IsLike {
+				continue
+			}
+			// Only a pattern with a constant prefix can be converted to a range.
+			if v, ok := x.Pattern.(ast.ValueExpr); ok {
+				if p := v.GetString(); p != "" && !strings.ContainsAny(p[:1], "%_\\") {
+					addRange(c)
+				}
+			}
+		}
+	}
+
+	for _, items := range a.orders {
+		var (
+			ref  *tableRef
+			cols []*model.ColumnInfo
+		)
+		for _, item := range items {
+			c := a.resolveColumn(item.Expr)
+			if c == nil || (ref != nil && ref.key() != c.table.key()) {
+				cols = nil
+				break
+			}
+			ref = c.table
+			cols = appendColumn(cols, c.col)
+		}
+		if len(cols) > 0 {
+			t := get(ref)
+			t.orders = append(t.orders, cols)
+		}
+	}
+	return result
+}
+
+func isIndexableColumn(col *model.ColumnInfo) bool {
+	switch col.GetType() {
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob,
+		mysql.TypeJSON, mysql.TypeGeometry, mysql.TypeTiDBVectorFloat32:
+		return false
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString:
+		return col.GetFlen() <= maxIndexedStringLen
+	}
+	return true
+}
+
+// candidateIndexes enumerates the single- and multi-column indexes that may
+// help the query to access the table. The multi-column ones put the columns
+// of equal conditions first, followed by a range column or the columns of an
+// ORDER BY or GROUP BY clause.
+func (t *indexableColumns) candidateIndexes(maxColumns int) []*Index {
+	filter := func(cols []*model.ColumnInfo) []*model.ColumnInfo {
+		result := make([]*model.ColumnInfo, 0, len(cols))
+		for _, col := range cols {
+			if isIndexableColumn(col) {
+				result = append(result, col)
+			}
+		}
+		return result
+	}
+	eq, ranges := filter(t.eq), filter(t.ranges)
+
+	var colLists [][]*model.ColumnInfo
+	add := func(cols []*model.ColumnInfo) {
+		if len(cols) == 0 {
+			return
+		}
+		if len(cols) > maxColumns {
+			cols = cols[:maxColumns]
+		}
+		colLists = append(colLists, cols)
+	}
+	concat := func(prefix, suffix []*model.ColumnInfo) []*model.ColumnInfo {
+		cols := make([]*model.ColumnInfo, 0, len(prefix)+len(suffix))
+		cols = append(cols, prefix...)
+		for _, col := range suffix {
+			cols = appendColumn(cols, col)
+		}
+		return cols
+	}
+
+	for _, col := range eq {
+		add([]*model.ColumnInfo{col})
+	}
+	for _, col := range ranges {
+		add([]*model.ColumnInfo{col})
+	}
+	if maxColumns > 1 {
+		for i := range eq {
+			for j := i + 1; j < len(eq); j++ {
+				add([]*model.ColumnInfo{eq[i], eq[j]})
+			}
+		}
+		if len(eq) > 2 {
+			add(eq)
+		}
+		eqPrefix := eq
+		if len(eqPrefix) >= maxColumns {
+			eqPrefix = eqPrefix[:maxColumns-1]
+		}
+		if len(eqPrefix) > 0 {
+			for _, col := range ranges {
+				add(concat(eqPrefix, []*model.ColumnInfo{col}))
+			}
+		}
+		for _, order := range t.orders {
+			order = filter(order)
+			add(order)
+			if len(eqPrefix) > 0 {
+				add(concat(eqPrefix, order))
+			}
+		}
+	} else {
+		for _, order := range t.orders {
+			add(filter(order))
+		}
+	}
+
+	indexes := make([]*Index, 0, len(colLists))
+	seen := make(map[string]struct{}, len(colLists))
+	for _, cols := range colLists {
+		idx := newIndex(t.ref.schema, t.ref.tblInfo, cols)
+		if _, ok := seen[idx.Key()]; ok || idx.coveredByExistingIndex() {
+			continue
+		}
+		seen[idx.Key()] = struct{}{}
+		indexes = append(indexes, idx)
+	}
+	return indexes
+}
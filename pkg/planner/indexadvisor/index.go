@@ -0,0 +1,165 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// Index is a candidate index of the index advisor. It is never built, the
+// optimizer only sees it as a hypothetical index.
+type Index struct {
+	SchemaName model.CIStr
+	TableName  model.CIStr
+	Columns    []model.CIStr
+
+	name    string
+	tblInfo *model.TableInfo
+}
+
+func newIndex(schema model.CIStr, tblInfo *model.TableInfo, cols []*model.ColumnInfo) *Index {
+	idx := &Index{
+		SchemaName: schema,
+		TableName:  tblInfo.Name,
+		Columns:    make([]model.CIStr, 0, len(cols)),
+		tblInfo:    tblInfo,
+	}
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		idx.Columns = append(idx.Columns, col.Name)
+		names = append(names, col.Name.L)
+	}
+	idx.name = uniqueIndexName(tblInfo, "idx_"+strings.Join(names, "_"))
+	return idx
+}
+
+// uniqueIndexName truncates the name to the max index identifier length and
+// adds a suffix if it's used by an existing index of the table.
+func uniqueIndexName(tblInfo *model.TableInfo, name string) string {
+	if len(name) > mysql.MaxIndexIdentifierLen {
+		name = name[:mysql.MaxIndexIdentifierLen]
+	}
+	candidate := name
+	for i := 2; tblInfo.FindIndexByName(candidate) != nil; i++ {
+		suffix := fmt.Sprintf("_%d", i)
+		if len(name)+len(suffix) > mysql.MaxIndexIdentifierLen {
+			name = name[:mysql.MaxIndexIdentifierLen-len(suffix)]
+		}
+		candidate = name + suffix
+	}
+	return candidate
+}
+
+// Key identifies the index by its table and columns.
+func (idx *Index) Key() string {
+	var sb strings.Builder
+	sb.WriteString(idx.SchemaName.L)
+	sb.WriteByte('.')
+	sb.WriteString(idx.TableName.L)
+	sb.WriteByte('(')
+	for i, col := range idx.Columns {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(col.L)
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// tableKey identifies the table of the index.
+func (idx *Index) tableKey() string {
+	return idx.SchemaName.L + "." + idx.TableName.L
+}
+
+// Name returns the name of the index.
+func (idx *Index) Name() string {
+	return idx.name
+}
+
+// ColumnNames returns the column names of the index separated by commas.
+func (idx *Index) ColumnNames() string {
+	names := make([]string, 0, len(idx.Columns))
+	for _, col := range idx.Columns {
+		names = append(names, col.O)
+	}
+	return strings.Join(names, ",")
+}
+
+// DDL returns the statement to create the index.
+func (idx *Index) DDL() string {
+	cols := make([]string, 0, len(idx.Columns))
+	for _, col := range idx.Columns {
+		cols = append(cols, quoteIdent(col.O))
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s.%s(%s)", quoteIdent(idx.name),
+		quoteIdent(idx.SchemaName.O), quoteIdent(idx.TableName.O), strings.Join(cols, ", "))
+}
+
+// HypoIndexInfo returns the hypothetical index info used by the optimizer.
+func (idx *Index) HypoIndexInfo() *model.IndexInfo {
+	cols := make([]*model.IndexColumn, 0, len(idx.Columns))
+	for _, name := range idx.Columns {
+		col := model.FindColumnInfo(idx.tblInfo.Columns, name.L)
+		cols = append(cols, &model.IndexColumn{
+			Name:   col.Name,
+			Offset: col.Offset,
+			Length: types.UnspecifiedLength,
+		})
+	}
+	return &model.IndexInfo{
+		Name:    model.NewCIStr(idx.name),
+		Table:   idx.tblInfo.Name,
+		Columns: cols,
+		State:   model.StatePublic,
+		Tp:      model.IndexTypeHypo,
+	}
+}
+
+// coveredByExistingIndex checks whether the columns of the index are a prefix
+// of an existing index, in which case creating it brings nothing.
+func (idx *Index) coveredByExistingIndex() bool {
+	for _, existing := range idx.tblInfo.Indices {
+		if existing.State != model.StatePublic || len(existing.Columns) < len(idx.Columns) ||
+			existing.FullTextInfo != nil || existing.VectorInfo != nil {
+			continue
+		}
+		covered := true
+		for i, col := range idx.Columns {
+			if existing.Columns[i].Name.L != col.L || existing.Columns[i].Length != types.UnspecifiedLength {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	if len(idx.Columns) == 1 && idx.tblInfo.PKIsHandle {
+		if pk := idx.tblInfo.GetPkColInfo(); pk != nil && pk.Name.L == idx.Columns[0].L {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
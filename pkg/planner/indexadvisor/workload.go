@@ -0,0 +1,96 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexadvisor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// selectTopStmtsSQL selects the statements that take the most time in the
+// statements summary. A digest may have several plans, so the rows are grouped.
+const selectTopStmtsSQL = `SELECT ANY_VALUE(SCHEMA_NAME), ANY_VALUE(QUERY_SAMPLE_TEXT), DIGEST, SUM(EXEC_COUNT)
+	FROM information_schema.statements_summary
+	WHERE STMT_TYPE IN ('Select', 'Update', 'Delete') AND SCHEMA_NAME IS NOT NULL
+		AND LOWER(SCHEMA_NAME) NOT IN ('mysql', 'information_schema', 'performance_schema', 'metrics_schema')
+	GROUP BY DIGEST
+	ORDER BY SUM(SUM_LATENCY) DESC
+	LIMIT %?`
+
+// LoadQueriesFromStmtSummary returns the statements that take the most time
+// in the statements summary as the workload.
+func LoadQueriesFromStmtSummary(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, limit int) ([]*Query, error) {
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, selectTopStmtsSQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]*Query, 0, len(rows))
+	for _, row := range rows {
+		if row.IsNull(1) {
+			continue
+		}
+		// ToInt truncates the value on overflow, which is fine for a frequency.
+		frequency, _ := row.GetMyDecimal(3).ToInt()
+		queries = append(queries, &Query{
+			SchemaName: row.GetString(0),
+			Text:       row.GetString(1),
+			Digest:     row.GetString(2),
+			Frequency:  frequency,
+		})
+	}
+	return queries, nil
+}
+
+// resultsKeyType is a dummy type to avoid naming collision in the session values.
+type resultsKeyType int
+
+// String implements the fmt.Stringer interface.
+func (resultsKeyType) String() string {
+	return "index_advisor_results"
+}
+
+// resultsKey is the session value key of the latest index advise results.
+const resultsKey resultsKeyType = 0
+
+// results keeps the recommendations of an index advise and the time it finished.
+type results struct {
+	recommendations []*Recommendation
+	adviseTime      time.Time
+}
+
+// ValueStore stores the values of a session.
+type ValueStore interface {
+	// SetValue saves a value associated with this context for key.
+	SetValue(key fmt.Stringer, value any)
+	// Value returns the value associated with this context for key.
+	Value(key fmt.Stringer) any
+}
+
+// SaveResults saves the recommendations of the latest index advise in the session.
+func SaveResults(store ValueStore, recommendations []*Recommendation, adviseTime time.Time) {
+	store.SetValue(resultsKey, &results{recommendations: recommendations, adviseTime: adviseTime})
+}
+
+// LastResults returns the recommendations of the latest index advise in the
+// session and the time it finished.
+func LastResults(store ValueStore) ([]*Recommendation, time.Time) {
+	if res, ok := store.Value(resultsKey).(*results); ok {
+		return res.recommendations, res.adviseTime
+	}
+	return nil, time.Time{}
+}
@@ -336,35 +336,6 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		}
 	}
 
-	// Add a baseline evolution task if:
-	// 1. the returned plan is from bindings;
-	// 2. the query is a select statement;
-	// 3. the original binding contains no read_from_storage hint;
-	// 4. the plan when ignoring bindings contains no tiflash hint;
-	// 5. the pending verified binding has not been added already;
-	savedStmtHints := sessVars.StmtCtx.StmtHints
-	defer func() {
-		sessVars.StmtCtx.StmtHints = savedStmtHints
-	}()
-	if sessVars.EvolvePlanBaselines && bestPlanFromBind != nil &&
-		sessVars.SelectLimit == math.MaxUint64 { // do not evolve this query if sql_select_limit is enabled
-		// Check bestPlanFromBind firstly to avoid nil stmtNode.
-		if _, ok := stmtNode.(*ast.SelectStmt); ok && !bindings[0].Hint.ContainTableHint(hint.HintReadFromStorage) {
-			sessVars.StmtCtx.StmtHints = originStmtHints
-			defPlan, _, _, err := optimize(ctx, pctx, node, is)
-			if err != nil {
-				// Ignore this evolution task.
-				return bestPlan, names, nil
-			}
-			defPlanHints := core.GenHintsFromPhysicalPlan(defPlan)
-			for _, h := range defPlanHints {
-				if h.HintName.String() == hint.HintReadFromStorage {
-					return bestPlan, names, nil
-				}
-			}
-		}
-	}
-
 	return bestPlan, names, nil
 }
 
@@ -386,6 +357,37 @@ func OptimizeForForeignKeyCascade(ctx context.Context, sctx pctx.PlanContext, no
 	return p, nil
 }
 
+// OptimizeForTrigger does optimization and creates a Plan for a statement in the body of a trigger. The statement is
+// executed within the statement which activates the trigger, so neither the plan cache nor the bindings are used.
+func OptimizeForTrigger(ctx context.Context, sctx pctx.PlanContext, node ast.StmtNode, is infoschema.InfoSchema) (core.Plan, types.NameSlice, error) {
+	hintProcessor := hint.NewQBHintHandler(sctx.GetSessionVars().StmtCtx)
+	node.Accept(hintProcessor)
+	builder := planBuilderPool.Get().(*core.PlanBuilder)
+	defer planBuilderPool.Put(builder.ResetForReuse())
+	builder.Init(sctx, is, hintProcessor)
+	p, err := builder.Build(ctx, node)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		visitInfo := core.VisitInfo4PrivCheck(is, node, builder.GetVisitInfo())
+		if err := core.CheckPrivilege(sctx.GetSessionVars().ActiveRoles, pm, visitInfo); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := core.CheckTableLock(sctx, is, builder.GetVisitInfo()); err != nil {
+		return nil, nil, err
+	}
+	names := p.OutputNames()
+	logic, isLogicalPlan := p.(core.LogicalPlan)
+	if !isLogicalPlan {
+		return p, names, nil
+	}
+	core.RecheckCTE(logic)
+	finalPlan, _, err := core.DoOptimize(ctx, sctx, builder.GetOptFlag(), logic)
+	return finalPlan, names, err
+}
+
 func allowInReadOnlyMode(sctx pctx.PlanContext, node ast.Node) (bool, error) {
 	pm := privilege.GetPrivilegeManager(sctx)
 	if pm == nil {
@@ -115,6 +115,14 @@ func (partitionCol *MPPPartitionColumn) Equal(other *MPPPartitionColumn) bool {
 	return partitionCol.Col.EqualColumn(other.Col)
 }
 
+// Clone makes a copy of MPPPartitionColumn.
+func (partitionCol *MPPPartitionColumn) Clone() *MPPPartitionColumn {
+	return &MPPPartitionColumn{
+		Col:       partitionCol.Col.Clone().(*expression.Column),
+		CollateID: partitionCol.CollateID,
+	}
+}
+
 // MemoryUsage return the memory usage of MPPPartitionColumn
 func (partitionCol *MPPPartitionColumn) MemoryUsage() (sum int64) {
 	if partitionCol == nil {
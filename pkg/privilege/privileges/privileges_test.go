@@ -1014,6 +1014,37 @@ func TestLoadDataPrivilege(t *testing.T) {
 	require.True(t, terror.ErrorEqual(err, plannererrors.ErrTableaccessDenied))
 }
 
+func TestTTLArchivePrivilege(t *testing.T) {
+	store := createStoreAndPrepareDB(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil, nil))
+	tk.MustExec(`CREATE USER 'test_ttl_archive'@'localhost';`)
+	tk.MustExec(`GRANT CREATE, ALTER on test.* to 'test_ttl_archive'@'localhost'`)
+
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "test_ttl_archive", Hostname: "localhost"}, nil, nil, nil))
+	tk.MustExec("create table t_s3(t datetime) TTL = t + interval 1 day TTL_ARCHIVE = 's3://bucket/archive'")
+	err := tk.ExecToErr("create table t_local(t datetime) TTL = t + interval 1 day TTL_ARCHIVE = 'file:///tmp/ttl_archive'")
+	require.True(t, terror.ErrorEqual(err, plannererrors.ErrSpecificAccessDenied))
+	err = tk.ExecToErr("alter table t_s3 TTL_ARCHIVE = '/tmp/ttl_archive'")
+	require.True(t, terror.ErrorEqual(err, plannererrors.ErrSpecificAccessDenied))
+	tk.MustExec("alter table t_s3 TTL_ARCHIVE = ''")
+
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil, nil))
+	tk.MustExec(`GRANT FILE on *.* to 'test_ttl_archive'@'localhost'`)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "test_ttl_archive", Hostname: "localhost"}, nil, nil, nil))
+	tk.MustExec("create table t_local(t datetime) TTL = t + interval 1 day TTL_ARCHIVE = 'file:///tmp/ttl_archive'")
+	tk.MustExec("alter table t_s3 TTL_ARCHIVE = '/tmp/ttl_archive'")
+
+	// the credentials in TTL_ARCHIVE are not kept in the table info, which can be read by everyone who can see the table.
+	tk.MustExec("alter table t_s3 TTL_ARCHIVE = 's3://bucket/archive?access-key=ak&secret-access-key=sk&region=us-west-2' TTL_ARCHIVE_FORMAT = 'parquet'")
+	tk.MustQuery("show create table t_s3").Check(testkit.Rows("t_s3 CREATE TABLE `t_s3` (\n" +
+		"  `t` datetime DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![ttl] TTL=`t` + INTERVAL 1 DAY */ /*T![ttl] TTL_ENABLE='ON' */ " +
+		"/*T![ttl] TTL_JOB_INTERVAL='1h' */ /*T![ttl] TTL_ARCHIVE='s3://bucket/archive?region=us-west-2' */ /*T![ttl] TTL_ARCHIVE_FORMAT='PARQUET' */"))
+}
+
 func TestAuthHost(t *testing.T) {
 	store := createStoreAndPrepareDB(t)
 
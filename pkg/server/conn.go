@@ -1631,12 +1631,9 @@ func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *ex
 		return errors.New("Index Advise: infile is empty")
 	}
 
-	if err := indexAdviseInfo.GetIndexAdvice(data); err != nil {
-		return err
-	}
-
-	// TODO: Write the rss []ResultSet. It will be done in another PR.
-	return nil
+	// The client is waiting for the response of the file transfer, so the
+	// advice is exposed by information_schema.tidb_index_advisor_results.
+	return indexAdviseInfo.GetIndexAdvice(ctx, data)
 }
 
 func (cc *clientConn) handlePlanReplayerLoad(ctx context.Context, planReplayerLoadInfo *executor.PlanReplayerLoadInfo) error {
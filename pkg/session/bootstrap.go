@@ -357,6 +357,7 @@ const (
 		stats blob DEFAULT NULL,
 		version bigint(64) unsigned NOT NULL,
 		status tinyint(4) NOT NULL,
+		fm_sketch longblob DEFAULT NULL,
 		PRIMARY KEY(name, table_id),
 		KEY idx_1 (table_id, status, version),
 		KEY idx_2 (status, version)
@@ -568,6 +569,73 @@ const (
     	key(create_time)
 	);`
 
+	// CreateTTLArchiveFiles is a table that stores the files of the expired rows archived by ttl jobs
+	CreateTTLArchiveFiles = `CREATE TABLE IF NOT EXISTS mysql.tidb_ttl_archive_files (
+		job_id varchar(64) NOT NULL,
+		scan_id int NOT NULL,
+		table_id bigint(64) NOT NULL,
+		parent_table_id bigint(64) NOT NULL,
+		table_schema varchar(64) NOT NULL,
+		table_name varchar(64) NOT NULL,
+		partition_name varchar(64) DEFAULT NULL,
+		ttl_expire timestamp NOT NULL,
+		archive text NOT NULL,
+		file_path varchar(512) NOT NULL,
+		columns text NOT NULL,
+		row_count bigint(64) NOT NULL,
+		create_time timestamp NOT NULL,
+		primary key(job_id, scan_id, file_path),
+		key(parent_table_id, ttl_expire),
+		key(create_time)
+	);`
+
+	// CreatePlanBaselinesTable stores the plans of statements recorded by the plan regression guard and the baseline evolution.
+	CreatePlanBaselinesTable = `CREATE TABLE IF NOT EXISTS mysql.plan_baselines (
+		sql_digest varchar(64) NOT NULL,
+		plan_digest varchar(64) NOT NULL,
+		original_sql TEXT NOT NULL,
+		bind_sql TEXT NOT NULL,
+		default_db varchar(64) NOT NULL,
+		charset TEXT NOT NULL,
+		collation TEXT NOT NULL,
+		status varchar(16) NOT NULL,
+		avg_latency bigint(20) NOT NULL DEFAULT 0,
+		exec_count bigint(20) NOT NULL DEFAULT 0,
+		create_time TIMESTAMP(3) NOT NULL,
+		update_time TIMESTAMP(3) NOT NULL,
+		PRIMARY KEY(sql_digest, plan_digest)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+
+	// CreatePlanBaselineHistoryTable stores the history of accepting, rejecting and falling back to plan baselines.
+	CreatePlanBaselineHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.plan_baseline_history (
+		id bigint(20) NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		sql_digest varchar(64) NOT NULL,
+		plan_digest varchar(64) NOT NULL,
+		action varchar(16) NOT NULL,
+		reason TEXT NOT NULL,
+		avg_latency bigint(20) NOT NULL DEFAULT 0,
+		baseline_plan_digest varchar(64) NOT NULL DEFAULT '',
+		baseline_avg_latency bigint(20) NOT NULL DEFAULT 0,
+		create_time TIMESTAMP(3) NOT NULL,
+		KEY(sql_digest),
+		KEY(create_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+
+	// CreateStatsCardinalityFeedbackTable stores the cardinality corrections learned from the actual row counts of the executions.
+	CreateStatsCardinalityFeedbackTable = `CREATE TABLE IF NOT EXISTS mysql.stats_cardinality_feedback (
+		table_id bigint(64) NOT NULL,
+		column_id bigint(64) NOT NULL,
+		shape varchar(64) NOT NULL,
+		ref_table_id bigint(64) NOT NULL DEFAULT 0,
+		ref_column_id bigint(64) NOT NULL DEFAULT 0,
+		ratio double NOT NULL,
+		count bigint(64) NOT NULL DEFAULT 0,
+		stats_version bigint(64) unsigned NOT NULL DEFAULT 0,
+		update_time TIMESTAMP(3) NOT NULL,
+		PRIMARY KEY(table_id, column_id, shape, ref_table_id, ref_column_id),
+		KEY(ref_table_id)
+	);`
+
 	// CreateGlobalTask is a table about global task.
 	CreateGlobalTask = `CREATE TABLE IF NOT EXISTS mysql.tidb_global_task (
 		id BIGINT(20) NOT NULL AUTO_INCREMENT PRIMARY KEY,
@@ -688,6 +756,41 @@ const (
 		KEY (created_by),
 		KEY (status));`
 
+	// CreateXABranchesTable stores the prepared XA transaction branches.
+	CreateXABranchesTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_xa_branches (
+		gtrid VARBINARY(64) NOT NULL,
+		bqual VARBINARY(64) NOT NULL,
+		format_id BIGINT(64) NOT NULL,
+		seq INT NOT NULL,
+		start_ts BIGINT(64) UNSIGNED NOT NULL,
+		schema_version BIGINT(64) NOT NULL,
+		table_ids JSON NOT NULL,
+		mutations LONGBLOB NOT NULL,
+		owner VARCHAR(64) NOT NULL DEFAULT '',
+		create_time TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (gtrid, bqual, format_id, seq)
+	);`
+
+	// CreateRoutinesTable stores the definitions of the stored routines.
+	CreateRoutinesTable = `CREATE TABLE IF NOT EXISTS mysql.routines (
+		route_schema VARCHAR(64) NOT NULL,
+		name VARCHAR(64) NOT NULL,
+		type ENUM('FUNCTION','PROCEDURE') NOT NULL,
+		definition LONGTEXT NOT NULL,
+		body LONGTEXT NOT NULL,
+		param_list TEXT NOT NULL,
+		definer VARCHAR(288) NOT NULL,
+		security_type ENUM('DEFINER','INVOKER') NOT NULL DEFAULT 'DEFINER',
+		sql_mode VARCHAR(1024) NOT NULL,
+		character_set_client VARCHAR(32) NOT NULL,
+		collation_connection VARCHAR(32) NOT NULL,
+		db_collation VARCHAR(32) NOT NULL,
+		comment TEXT NOT NULL,
+		created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		last_altered TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (route_schema, name, type)
+	);`
+
 	// DropMySQLIndexUsageTable removes the table `mysql.schema_index_usage`
 	DropMySQLIndexUsageTable = "DROP TABLE IF EXISTS mysql.schema_index_usage"
 
@@ -1082,11 +1185,39 @@ const (
 	//   create `sys` schema
 	//   create `sys.schema_unused_indexes` table
 	version195 = 195
+
+	// version 196
+	//   create `mysql.tidb_xa_branches` table
+	version196 = 196
+
+	// version 197
+	//   create `mysql.routines` table
+	version197 = 197
+
+	// version 198
+	//   create `mysql.tidb_ttl_archive_files` table
+	version198 = 198
+
+	// version 199
+	//   create `mysql.plan_baselines` and `mysql.plan_baseline_history` tables
+	version199 = 199
+
+	// version 200
+	//   create `mysql.stats_cardinality_feedback` table
+	version200 = 200
+
+	// version 201
+	//   add column `fm_sketch` to `mysql.stats_extended`
+	version201 = 201
+
+	// version 202
+	//   add column `owner` to `mysql.tidb_xa_branches`
+	version202 = 202
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version195
+var currentBootstrapVersion int64 = version202
 
 // DDL owner key's expired time is ManagerSessionTTL seconds, we should wait the time and give more time to have a chance to finish it.
 var internalSQLTimeout = owner.ManagerSessionTTL + 15
@@ -1247,6 +1378,13 @@ var (
 		upgradeToVer193,
 		upgradeToVer194,
 		upgradeToVer195,
+		upgradeToVer196,
+		upgradeToVer197,
+		upgradeToVer198,
+		upgradeToVer199,
+		upgradeToVer200,
+		upgradeToVer201,
+		upgradeToVer202,
 	}
 )
 
@@ -3069,6 +3207,56 @@ func upgradeToVer195(s sessiontypes.Session, ver int64) {
 	doReentrantDDL(s, DropMySQLIndexUsageTable)
 }
 
+func upgradeToVer196(s sessiontypes.Session, ver int64) {
+	if ver >= version196 {
+		return
+	}
+	doReentrantDDL(s, CreateXABranchesTable)
+}
+
+func upgradeToVer197(s sessiontypes.Session, ver int64) {
+	if ver >= version197 {
+		return
+	}
+	doReentrantDDL(s, CreateRoutinesTable)
+}
+
+func upgradeToVer198(s sessiontypes.Session, ver int64) {
+	if ver >= version198 {
+		return
+	}
+	doReentrantDDL(s, CreateTTLArchiveFiles)
+}
+
+func upgradeToVer199(s sessiontypes.Session, ver int64) {
+	if ver >= version199 {
+		return
+	}
+	doReentrantDDL(s, CreatePlanBaselinesTable)
+	doReentrantDDL(s, CreatePlanBaselineHistoryTable)
+}
+
+func upgradeToVer200(s sessiontypes.Session, ver int64) {
+	if ver >= version200 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsCardinalityFeedbackTable)
+}
+
+func upgradeToVer201(s sessiontypes.Session, ver int64) {
+	if ver >= version201 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.stats_extended ADD COLUMN `fm_sketch` LONGBLOB DEFAULT NULL AFTER `status`", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer202(s sessiontypes.Session, ver int64) {
+	if ver >= version202 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.tidb_xa_branches ADD COLUMN `owner` VARCHAR(64) NOT NULL DEFAULT '' AFTER `mutations`", infoschema.ErrColumnExists)
+}
+
 func writeOOMAction(s sessiontypes.Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -3200,6 +3388,17 @@ func doDDLWorks(s sessiontypes.Session) {
 	mustExecute(s, CreateSysSchema)
 	// create `sys.schema_unused_indexes` view
 	mustExecute(s, CreateSchemaUnusedIndexesView)
+	// create tidb_xa_branches
+	mustExecute(s, CreateXABranchesTable)
+	// create routines
+	mustExecute(s, CreateRoutinesTable)
+	// create tidb_ttl_archive_files
+	mustExecute(s, CreateTTLArchiveFiles)
+	// create plan_baselines and plan_baseline_history
+	mustExecute(s, CreatePlanBaselinesTable)
+	mustExecute(s, CreatePlanBaselineHistoryTable)
+	// create stats_cardinality_feedback
+	mustExecute(s, CreateStatsCardinalityFeedbackTable)
 }
 
 // doBootstrapSQLFile executes SQL commands in a file as the last stage of bootstrap.
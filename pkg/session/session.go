@@ -500,6 +500,9 @@ func (s *session) doCommit(ctx context.Context) error {
 		s.sessionVars.SetInTxn(false)
 		s.ClearDiskFullOpt()
 	}()
+	if xaTxn := s.sessionVars.XATxn; xaTxn != nil && xaTxn.State == variable.XAStatePrepared {
+		return s.prepareXATxn(ctx)
+	}
 	// check if the transaction is read-only
 	if s.txn.IsReadOnly() {
 		return nil
@@ -775,14 +778,28 @@ func (s *session) commitTxnWithTemporaryData(ctx context.Context, txn kv.Transac
 			continue
 		}
 
-		if tbl.GetMeta().TempTableType != model.TempTableLocal {
-			continue
-		}
-		if _, ok := localTempTables.TableByID(tblID); !ok {
+		// The rows of local temporary tables and global temporary tables with `ON COMMIT PRESERVE ROWS` are kept in
+		// the session, while the ones of other global temporary tables are discarded.
+		switch tblInfo := tbl.GetMeta(); tblInfo.TempTableType {
+		case model.TempTableLocal:
+			if _, ok := localTempTables.TableByID(tblID); !ok {
+				continue
+			}
+		case model.TempTableGlobal:
+			if !tblInfo.TempTablePreserveRows {
+				continue
+			}
+		default:
 			continue
 		}
 
 		if stage == kv.InvalidStagingHandle {
+			if sessionData == nil {
+				var err error
+				if sessionData, err = temptable.EnsureSessionData(s); err != nil {
+					return err
+				}
+			}
 			stage = sessionData.Staging()
 		}
 
@@ -1042,6 +1059,7 @@ func (s *session) RollbackTxn(ctx context.Context) {
 	s.sessionVars.TxnCtx.Cleanup()
 	s.sessionVars.CleanupTxnReadTSIfUsed()
 	s.sessionVars.SetInTxn(false)
+	s.sessionVars.XATxn = nil
 	sessiontxn.GetTxnManager(s).OnTxnEnd()
 }
 
@@ -2119,6 +2137,10 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	if err := executor.ResetContextOfStmt(s, stmtNode); err != nil {
 		return nil, err
 	}
+	// The statements in the stored procedure are executed by the session one by one.
+	if call, ok := stmtNode.(*ast.CallStmt); ok {
+		return executor.CallProcedure(ctx, s, call)
+	}
 	if execStmt, ok := stmtNode.(*ast.ExecuteStmt); ok {
 		if binParam, ok := execStmt.BinaryArgs.([]param.BinaryParam); ok {
 			args, err := param.ExecArgs(s.GetSessionVars().StmtCtx.TypeCtx(), binParam)
@@ -2606,6 +2628,12 @@ func (s *session) Close() {
 	s.RollbackTxn(ctx)
 	if s.sessionVars != nil {
 		s.sessionVars.WithdrawAllPreparedStmt()
+		if tempTableData := s.sessionVars.TemporaryTableData; tempTableData != nil {
+			if err := tempTableData.Close(); err != nil {
+				logutil.BgLogger().Warn("failed to remove the temporary table data in session",
+					zap.Uint64("conn", s.sessionVars.ConnectionID), zap.Error(err))
+			}
+		}
 	}
 	if s.stmtStats != nil {
 		s.stmtStats.SetFinished()
@@ -3434,6 +3462,7 @@ func bootstrapSessionImpl(store kv.Storage, createSessionsImpl func(store kv.Sto
 	rebuildAllPartitionValueMapAndSorted(ses[0])
 
 	dom := domain.GetDomain(ses[0])
+	dom.SetInstancePlanCache(plannercore.NewInstancePlanCache())
 
 	// We should make the load bind-info loop before other loops which has internal SQL.
 	// Because the internal SQL may access the global bind-info handler. As the result, the data race occurs here as the
@@ -3559,6 +3588,7 @@ func bootstrapSessionImpl(store kv.Storage, createSessionsImpl func(store kv.Sto
 		return s
 	}
 	dom.StartTTLJobManager()
+	dom.StartEventScheduler()
 
 	analyzeCtxs, err := createSessions(store, analyzeConcurrencyQuota)
 	if err != nil {
@@ -4227,6 +4257,9 @@ func (s *session) EncodeSessionStates(ctx context.Context,
 			return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has local temporary tables")
 		}
 	}
+	if s.sessionVars.HasPreservedTemporaryTables() {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has global temporary tables with ON COMMIT PRESERVE ROWS")
+	}
 	// The advisory locks will be released when the session is closed.
 	if len(s.advisoryLocks) > 0 {
 		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has advisory locks")
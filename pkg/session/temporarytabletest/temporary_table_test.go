@@ -16,6 +16,7 @@ package temporarytabletest
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,11 +24,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/pkg/config"
 	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser/terror"
 	"github.com/pingcap/tidb/pkg/session"
+	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/util/memory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -417,3 +421,128 @@ func TestSchemaCheckerTempTable(t *testing.T) {
 	err = tk1.ExecToErr(`commit;`)
 	require.True(t, terror.ErrorEqual(err, domain.ErrInfoSchemaChanged), fmt.Sprintf("err %v", err))
 }
+
+func TestGlobalTemporaryTablePreserveRows(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create global temporary table tmp1 (id int auto_increment primary key, v int, key(v)) on commit preserve rows")
+
+	// The rows are kept after the transactions commit.
+	tk.MustExec("insert into tmp1 (v) values (1), (2)")
+	tk.MustExec("begin")
+	tk.MustExec("insert into tmp1 (v) values (3)")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustQuery("select * from tmp1 where id = 2").Check(testkit.Rows("2 2"))
+	tk.MustQuery("select * from tmp1 where id in (1, 3)").Sort().Check(testkit.Rows("1 1", "3 3"))
+	tk.MustQuery("select v from tmp1 use index(v) where v > 1 order by v").Check(testkit.Rows("2", "3"))
+
+	// The changes of the rolled back transactions are discarded.
+	tk.MustExec("begin")
+	tk.MustExec("update tmp1 set v = v + 10 where id = 1")
+	tk.MustExec("delete from tmp1 where id = 2")
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("1 11", "3 3"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("1 1", "2 2", "3 3"))
+
+	// The auto IDs are not reused by the following transactions.
+	tk.MustExec("delete from tmp1 where id = 2")
+	tk.MustExec("insert into tmp1 (v) values (4)")
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("1 1", "3 3", "4 4"))
+
+	// The rows are private to the session.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustQuery("select * from tmp1").Check(testkit.Rows())
+	tk2.MustExec("insert into tmp1 (v) values (100)")
+	tk2.MustQuery("select * from tmp1").Check(testkit.Rows("1 100"))
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("1 1", "3 3", "4 4"))
+
+	// Truncating the table only removes the rows of the current session.
+	tk.MustExec("truncate table tmp1")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows())
+	tk2.MustQuery("select * from tmp1").Check(testkit.Rows("1 100"))
+	tk.MustExec("insert into tmp1 (v) values (5)")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows("1 5"))
+
+	// The DDLs changing the layout of the rows are not supported.
+	tk.MustGetErrMsg("alter table tmp1 add column c int",
+		"[ddl:8200]TiDB doesn't support ALTER TABLE for global temporary table with ON COMMIT PRESERVE ROWS")
+	tk.MustGetErrMsg("create index idx on tmp1(v)",
+		"[ddl:8200]TiDB doesn't support CREATE INDEX for global temporary table with ON COMMIT PRESERVE ROWS")
+
+	// The rows kept by the committed transactions count towards the size limit.
+	tk.MustExec("create global temporary table tmp2 (id int auto_increment primary key, v varchar(1024)) on commit preserve rows")
+	tk.MustExec("set @@tidb_tmp_table_max_size = 1048576")
+	values := strings.TrimSuffix(strings.Repeat("(repeat('a', 1024)),", 64), ",")
+	for i := 0; ; i++ {
+		require.Less(t, i, 32)
+		if err := tk.ExecToErr("insert into tmp2 (v) values " + values); err != nil {
+			require.True(t, terror.ErrorEqual(err, table.ErrTempTableFull), "%v", err)
+			tk.MustQuery("select count(*) from tmp2").Check(testkit.Rows(strconv.Itoa(i * 64)))
+			break
+		}
+	}
+}
+
+func TestGlobalTemporaryTableSpill(t *testing.T) {
+	tempDir := t.TempDir()
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TempStoragePath = tempDir
+	})
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_mem_oom_action = 'LOG'")
+	defer tk.MustExec("set global tidb_mem_oom_action = default")
+	tk.MustExec("use test")
+	tk.MustExec("create global temporary table tmp1 (id int primary key, v varchar(16), key(v)) on commit preserve rows")
+	tk.MustExec("insert into tmp1 values (1, 'a'), (2, 'b')")
+
+	sessVars := tk.Session().GetSessionVars()
+	memTracker := sessVars.MemTracker.SearchTrackerWithoutLock(memory.LabelForTemporaryTableData)
+	diskTracker := sessVars.DiskTracker.SearchTrackerWithoutLock(memory.LabelForTemporaryTableData)
+	require.Positive(t, memTracker.BytesConsumed())
+	require.Zero(t, diskTracker.BytesConsumed())
+
+	// The data is marked when the memory quota is exceeded, and is spilled when the next statement starts.
+	spillAfter := func(sql string) {
+		tk.MustExec("set @@tidb_mem_quota_query = 1")
+		tk.MustExec(sql)
+		tk.MustExec("set @@tidb_mem_quota_query = default")
+		require.Zero(t, memTracker.BytesConsumed())
+		require.Positive(t, diskTracker.BytesConsumed())
+	}
+	spillAfter("insert into tmp1 values (3, 'c')")
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("1 a", "2 b", "3 c"))
+	tk.MustQuery("select * from tmp1 order by id desc").Check(testkit.Rows("3 c", "2 b", "1 a"))
+	tk.MustQuery("select * from tmp1 where id = 2").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select v from tmp1 use index(v) where v > 'a' order by v").Check(testkit.Rows("b", "c"))
+
+	// The rows in memory override the ones spilled to disk.
+	tk.MustExec("delete from tmp1 where id = 1")
+	tk.MustExec("insert into tmp1 values (4, 'd')")
+	require.Positive(t, memTracker.BytesConsumed())
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("2 b", "3 c", "4 d"))
+	tk.MustQuery("select * from tmp1 where id in (1, 2)").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select v from tmp1 use index(v) order by v desc").Check(testkit.Rows("d", "c", "b"))
+
+	spillAfter("update tmp1 set v = 'bb' where id = 2")
+	tk.MustQuery("select * from tmp1 order by id").Check(testkit.Rows("2 bb", "3 c", "4 d"))
+	tk.MustQuery("select * from tmp1 where id in (1, 2)").Check(testkit.Rows("2 bb"))
+	tk.MustQuery("select v from tmp1 use index(v) order by v desc").Check(testkit.Rows("d", "c", "bb"))
+
+	tk.MustExec("truncate table tmp1")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows())
+	tk.MustExec("insert into tmp1 values (1, 'a')")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows("1 a"))
+
+	// The data on disk is removed when the session is closed.
+	tk.Session().Close()
+	dirs, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Empty(t, dirs)
+}
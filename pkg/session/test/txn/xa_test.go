@@ -0,0 +1,237 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/errno"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/sessiontxn/xa"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/tikv"
+)
+
+// loseHeldBranches simulates the crash of the instance, the held transactions of the prepared branches are lost
+// while their locks are left in the storage.
+func loseHeldBranches(t *testing.T, gtrids ...string) {
+	require.NoError(t, failpoint.Enable("tikvclient/onRollback", `return("skipRollbackPessimisticLock")`))
+	defer func() {
+		require.NoError(t, failpoint.Disable("tikvclient/onRollback"))
+	}()
+	for _, gtrid := range gtrids {
+		xid := variable.XID{FormatID: 1, GTRID: gtrid}
+		txn, ok := xa.Acquire(xid)
+		require.True(t, ok)
+		require.NotNil(t, txn)
+		require.NoError(t, txn.Rollback())
+		xa.Release(xid)
+	}
+}
+
+func TestXAStateTransition(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int)")
+
+	tk.MustGetErrCode("xa end 'x'", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("xa prepare 'x'", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("xa start ''", mysql.ErrXaerInval)
+	tk.MustGetErrCode("xa start 'x' join", mysql.ErrXaerInval)
+
+	tk.MustExec("begin")
+	tk.MustGetErrCode("xa start 'x'", mysql.ErrXaerOutside)
+	tk.MustExec("rollback")
+
+	tk.MustExec("xa start 'x'")
+	tk.MustGetErrCode("xa start 'y'", mysql.ErrXaerRmfail)
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustGetErrCode("commit", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("begin", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("create table t2 (id int)", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("xa prepare 'x'", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("xa end 'y'", mysql.ErrXaerNota)
+	tk.MustExec("xa end 'x'")
+	tk.MustGetErrCode("select * from t", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("xa commit 'x'", mysql.ErrXaerRmfail)
+	tk.MustExec("xa commit 'x' one phase")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+
+	tk.MustExec("xa start 'x', 'b', 2")
+	tk.MustExec("update t set v = 2")
+	tk.MustExec("savepoint s")
+	tk.MustExec("update t set v = 3")
+	tk.MustExec("rollback to savepoint s")
+	tk.MustGetErrCode("xa rollback 'x', 'b', 2", mysql.ErrXaerRmfail)
+	tk.MustExec("xa end 'x', 'b', 2")
+	tk.MustExec("xa rollback 'x', 'b', 2")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+
+	tk.MustGetErrCode("xa commit 'x'", mysql.ErrXaerNota)
+	tk.MustGetErrCode("xa rollback 'x'", mysql.ErrXaerNota)
+}
+
+func TestXAPrepareAndCommit(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int, key(v))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	tk.MustExec("xa start 'g1', 'b1'")
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustExec("update t set v = 10 where id = 1")
+	tk.MustExec("delete from t where id = 2")
+	tk.MustExec("xa end 'g1', 'b1'")
+	tk.MustExec("xa prepare 'g1', 'b1'")
+	// The session is detached from the prepared branch.
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustExec("xa start 'g2'")
+	tk.MustExec("xa end 'g2'")
+	tk.MustExec("xa prepare 'g2'")
+	tk.MustGetErrCode("xa start 'g2'", mysql.ErrXaerDupid)
+	tk.MustQuery("xa recover").Check(testkit.Rows("1 2 2 g1b1", "1 2 0 g2"))
+	tk.MustQuery("xa recover convert xid").Check(testkit.Rows("1 2 2 0x67316231", "1 2 0 0x6732"))
+
+	// The prepared branch survives the disconnection and keeps its locks.
+	tk.Session().Close()
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@innodb_lock_wait_timeout = 1")
+	tk2.MustExec("begin")
+	tk2.MustGetErrCode("select * from t where id = 1 for update", mysql.ErrLockWaitTimeout)
+	tk2.MustExec("rollback")
+	tk2.MustGetErrCode("xa commit 'g1', 'b1' one phase", mysql.ErrXaerRmfail)
+	tk2.MustExec("xa commit 'g1', 'b1'")
+	tk2.MustExec("xa rollback 'g2'")
+	tk2.MustQuery("select * from t order by id").Check(testkit.Rows("1 10", "3 3"))
+	tk2.MustQuery("select * from t use index(v) order by v").Check(testkit.Rows("3 3", "1 10"))
+	tk2.MustQuery("xa recover").Check(testkit.Rows())
+	tk2.MustExec("admin check table t")
+}
+
+func TestXAReplayPreparedBranch(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v varchar(10), unique key(v))")
+
+	tk.MustExec("xa start 'g1'")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b')")
+	tk.MustExec("xa end 'g1'")
+	tk.MustExec("xa prepare 'g1'")
+	tk.MustExec("xa start 'g2'")
+	tk.MustExec("insert into t values (3, 'c')")
+	tk.MustExec("xa end 'g2'")
+	tk.MustExec("xa prepare 'g2'")
+	loseHeldBranches(t, "g1", "g2")
+
+	tk.MustQuery("xa recover").Check(testkit.Rows("1 2 0 g1", "1 2 0 g2"))
+	tk.MustExec("xa commit 'g1'")
+	tk.MustExec("xa rollback 'g2'")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 a", "2 b"))
+	tk.MustExec("admin check table t")
+
+	tk.MustExec("xa start 'g3'")
+	tk.MustExec("insert into t values (3, 'c')")
+	tk.MustExec("xa end 'g3'")
+	tk.MustExec("xa prepare 'g3'")
+	loseHeldBranches(t, "g3")
+	// The schema change after the branch is prepared makes it fail to commit.
+	tk.MustExec("alter table t add column c int")
+	tk.MustGetErrCode("xa commit 'g3'", errno.ErrInfoSchemaChanged)
+	tk.MustExec("xa rollback 'g3'")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 a <nil>", "2 b <nil>"))
+}
+
+func TestXAFinishBranchOfAnotherInstance(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int)")
+
+	tk.MustExec("xa start 'g1'")
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustExec("xa end 'g1'")
+	tk.MustExec("xa prepare 'g1'")
+	// Simulate that the branch is prepared by another instance, and the owning session is gone.
+	tk.Session().Close()
+	loseHeldBranches(t, "g1")
+	tk = testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("update mysql.tidb_xa_branches set owner = 'another'")
+
+	// The branch can't be finished while the instance holding its transaction is alive.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/pkg/domain/infosync/mockGetAllServerInfo",
+		"return(`{\"another\":{\"id\":\"another\"}}`)"))
+	tk.MustGetErrCode("xa commit 'g1'", mysql.ErrXaerRmfail)
+	tk.MustGetErrCode("xa rollback 'g1'", mysql.ErrXaerRmfail)
+	tk.MustQuery("xa recover").Check(testkit.Rows("1 2 0 g1"))
+	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/pkg/domain/infosync/mockGetAllServerInfo"))
+
+	// The branch is replayed after the instance is gone.
+	tk.MustExec("xa commit 'g1'")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+	tk.MustQuery("xa recover").Check(testkit.Rows())
+	tk.MustExec("admin check table t")
+}
+
+func TestXAKeepLocksOfLostBranch(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int, key(v))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+
+	tk.MustExec("xa start 'g1'")
+	tk.MustExec("update t set v = 10 where id = 1")
+	tk.MustExec("xa end 'g1'")
+	tk.MustExec("xa prepare 'g1'")
+	tk.MustExec("xa start 'g2'")
+	tk.MustExec("update t set v = 20 where id = 2")
+	tk.MustExec("xa end 'g2'")
+	tk.MustExec("xa prepare 'g2'")
+	loseHeldBranches(t, "g1", "g2")
+
+	// The locks of the lost branches don't expire, so they still block the other transactions.
+	rows := tk.MustQuery("select start_ts from mysql.tidb_xa_branches where gtrid = 'g1'").Rows()
+	startTS, err := strconv.ParseUint(rows[0][0].(string), 10, 64)
+	require.NoError(t, err)
+	status, err := store.(tikv.Storage).GetLockResolver().GetTxnStatus(startTS, 0, xa.PrimaryKey(variable.XID{FormatID: 1, GTRID: "g1"}))
+	require.NoError(t, err)
+	require.False(t, status.IsCommitted())
+	require.Greater(t, status.TTL(), uint64(time.Hour.Milliseconds()))
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set innodb_lock_wait_timeout = 1")
+	tk2.MustExec("begin pessimistic")
+	tk2.MustExec("select * from t where id = 3 for update")
+	tk2.MustGetErrCode("update t set v = 0 where id = 1", mysql.ErrLockWaitTimeout)
+	tk2.MustGetErrCode("update t set v = 0 where id = 2", mysql.ErrLockWaitTimeout)
+	tk2.MustExec("rollback")
+
+	// The branches are committed or rolled back with the locks, and the locks are released then.
+	tk.MustExec("xa commit 'g1'")
+	tk.MustExec("xa rollback 'g2'")
+	tk2.MustExec("update t set v = v + 1")
+	tk2.MustQuery("select * from t order by id").Check(testkit.Rows("1 11", "2 3", "3 4"))
+	tk2.MustExec("admin check table t")
+}
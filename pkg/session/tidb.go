@@ -34,11 +34,13 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	session_metrics "github.com/pingcap/tidb/pkg/session/metrics"
 	"github.com/pingcap/tidb/pkg/session/types"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
+	tidbtypes "github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/dbterror"
@@ -374,10 +376,13 @@ func ResultSetToStringSlice(ctx context.Context, s types.Session, rs sqlexec.Rec
 		row := rows[i]
 		iRow := make([]string, row.Len())
 		for j := 0; j < row.Len(); j++ {
+			ft := &rs.Fields()[j].Column.FieldType
 			if row.IsNull(j) {
 				iRow[j] = "<nil>"
+			} else if ft.GetType() == mysql.TypeTiDBVectorFloat32 {
+				iRow[j] = tidbtypes.FormatVectorFloat32(row.GetBytes(j))
 			} else {
-				d := row.GetDatum(j, &rs.Fields()[j].Column.FieldType)
+				d := row.GetDatum(j, ft)
 				iRow[j], err = d.ToString()
 				if err != nil {
 					return nil, err
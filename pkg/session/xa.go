@@ -0,0 +1,79 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/domain/infosync"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessiontxn/xa"
+)
+
+// prepareXATxn is called instead of committing the transaction when XA PREPARE finishes. It persists the mutations
+// of the branch, so that the branch can be committed or rolled back by any session even after this instance exits,
+// and then detaches the transaction from the session. The detached transaction is held by this instance until XA
+// COMMIT or XA ROLLBACK. The pessimistic locks of the branch are kept even after this instance exits, see
+// xa.KeepLocks. The branch can only be finished on this instance while it's alive, see xa.OwnerIsAlive.
+func (s *session) prepareXATxn(ctx context.Context) (err error) {
+	sessVars := s.sessionVars
+	xid := sessVars.XATxn.XID
+	sessVars.XATxn = nil
+	defer func() {
+		if err != nil {
+			terror.Log(s.txn.Rollback())
+		}
+	}()
+
+	txnCtx := sessVars.TxnCtx
+	if len(txnCtx.TemporaryTables) > 0 || len(txnCtx.CachedTables) > 0 || s.txn.IsPipelined() || sessVars.BinlogClient != nil {
+		return errors.New("XA transaction doesn't support temporary tables, cached tables, pipelined DML or binlog")
+	}
+	tableIDs := make([]int64, 0, len(txnCtx.TableDeltaMap))
+	for id := range txnCtx.TableDeltaMap {
+		tableIDs = append(tableIDs, id)
+	}
+	mutations, err := xa.EncodeMutations(s.txn.Transaction)
+	if err != nil {
+		return err
+	}
+	serverInfo, err := infosync.GetServerInfo()
+	if err != nil {
+		return err
+	}
+	branch := &xa.Branch{
+		XID:           xid,
+		StartTS:       s.txn.StartTS(),
+		SchemaVersion: s.GetInfoSchema().SchemaMetaVersion(),
+		TableIDs:      tableIDs,
+		Mutations:     mutations,
+		Owner:         serverInfo.ID,
+	}
+	if err = xa.KeepLocks(ctx, s.store, xid, branch.StartTS); err != nil {
+		return err
+	}
+	if err = xa.SaveBranch(ctx, s.GetRestrictedSQLExecutor(), branch); err != nil {
+		return err
+	}
+	// The metadata lock is released once the branch is detached, so the schema must always be checked when the
+	// branch is committed.
+	s.txn.SetOption(kv.SchemaChecker, domain.NewSchemaChecker(domain.GetDomain(s), branch.SchemaVersion, tableIDs, true))
+	s.txn.SetOption(kv.InfoSchema, txnCtx.InfoSchema)
+	xa.Hold(xid, s.txn.Transaction)
+	return nil
+}
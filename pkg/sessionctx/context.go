@@ -64,6 +64,22 @@ type PlanCache interface {
 	Close()
 }
 
+// InstancePlanCache is an interface for the plan cache shared by all sessions of the instance.
+type InstancePlanCache interface {
+	// Get returns a copy of the cached plan which can be used by the session exclusively.
+	Get(sctx Context, key kvcache.Key, opts *utilpc.PlanCacheMatchOpts) (value kvcache.Value, ok bool)
+	// Put puts the plan into the cache, it returns false if the plan can't be shared between sessions.
+	Put(sctx Context, key kvcache.Key, value kvcache.Value, opts *utilpc.PlanCacheMatchOpts) bool
+	// DeleteAll deletes all plans in the cache.
+	DeleteAll()
+	// Size returns the number of plans in the cache.
+	Size() int
+	// MemUsage returns the memory usage of the cache.
+	MemUsage() int64
+	// All returns the information of all plans in the cache.
+	All() []*utilpc.InstancePlanCacheItem
+}
+
 // Context is an interface for transaction and executive args environment.
 type Context interface {
 	SessionStatesHandler
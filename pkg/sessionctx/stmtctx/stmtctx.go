@@ -391,6 +391,10 @@ type StatementContext struct {
 	usedStatsInfo atomic.Pointer[UsedStatsInfo]
 	// IsSyncStatsFailed indicates whether any failure happened during sync stats
 	IsSyncStatsFailed bool
+	// AppliedCardinalityFeedback records the cardinality corrections applied in the estimation of the statement.
+	// It's a map of the string of statistics.FeedbackKey -> ratio, and it's used to learn the corrections from the
+	// actual row counts of the statement.
+	AppliedCardinalityFeedback map[string]float64
 	// UseDynamicPruneMode indicates whether use UseDynamicPruneMode in query stmt
 	UseDynamicPruneMode bool
 	// ColRefFromPlan mark the column ref used by assignment in update statement.
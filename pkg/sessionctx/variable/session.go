@@ -53,6 +53,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/disk"
 	"github.com/pingcap/tidb/pkg/util/execdetails"
 	"github.com/pingcap/tidb/pkg/util/kvcache"
+	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/mathutil"
 	"github.com/pingcap/tidb/pkg/util/memory"
 	"github.com/pingcap/tidb/pkg/util/replayer"
@@ -67,6 +68,7 @@ import (
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/twmb/murmur3"
 	atomic2 "go.uber.org/atomic"
+	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 )
 
@@ -532,7 +534,8 @@ func (r *RewritePhaseInfo) Reset() {
 	r.PreprocessSubQueries = 0
 }
 
-// TemporaryTableData is a interface to maintain temporary data in session
+// TemporaryTableData is a interface to maintain temporary data in session. The data is kept in memory, and is spilled
+// to disk when the memory quota of the session is exceeded if tidb_enable_tmp_storage_on_oom is on.
 type TemporaryTableData interface {
 	kv.Retriever
 	// Staging create a new staging buffer inside the MemBuffer.
@@ -550,20 +553,106 @@ type TemporaryTableData interface {
 	DeleteTableKey(tblID int64, k kv.Key) error
 	// SetTableKey sets the entry for k from table
 	SetTableKey(tblID int64, k kv.Key, val []byte) error
+	// ActionSpill returns the action to take when the memory quota of the session is exceeded. The action only marks
+	// the data to be spilled, because it may be read by the running statement.
+	ActionSpill() memory.ActionOnExceed
+	// Spill moves the data to disk if it's marked by the action. It should be called when the data is not being read
+	// and no staging buffer is active.
+	Spill() error
+	// Close removes the data spilled to disk.
+	Close() error
+}
+
+// TemporaryTableDiskData keeps the data of temporary tables spilled to disk. The read methods merge the data in the
+// memory buffer over the data on disk, an entry with an empty value in the memory buffer means the key is deleted.
+type TemporaryTableDiskData interface {
+	// Get gets the value for key k from the memory buffer or disk.
+	Get(ctx context.Context, memBuffer kv.Retriever, k kv.Key) ([]byte, error)
+	// Iter creates an Iterator over the memory buffer and disk.
+	Iter(memBuffer kv.Retriever, k kv.Key, upperBound kv.Key) (kv.Iterator, error)
+	// IterReverse creates a reversed Iterator over the memory buffer and disk.
+	IterReverse(memBuffer kv.Retriever, k kv.Key, lowerBound kv.Key) (kv.Iterator, error)
+	// Spill writes the data in the memory buffer to disk, and returns an empty memory buffer to replace it.
+	Spill(memBuffer kv.MemBuffer) (kv.MemBuffer, error)
+	// Close removes the data on disk.
+	Close() error
 }
 
 // temporaryTableData is used for store temporary table data in session
 type temporaryTableData struct {
 	kv.MemBuffer
 	tblSize map[int64]int64
+	// stagingTblSize keeps the table sizes before each staging buffer is created, they're restored when the changes in
+	// the staging buffer are discarded.
+	stagingTblSize []stagingTblSize
+	memTracker     *memory.Tracker
+	disk           TemporaryTableDiskData
+	// spillPending is set by the action returned by ActionSpill, the data is spilled by the next Spill.
+	spillPending atomic.Bool
 }
 
-// NewTemporaryTableData creates a new TemporaryTableData
-func NewTemporaryTableData(memBuffer kv.MemBuffer) TemporaryTableData {
+type stagingTblSize struct {
+	handle  kv.StagingHandle
+	tblSize map[int64]int64
+}
+
+// NewTemporaryTableData creates a new TemporaryTableData, the memory used by it is consumed by memTracker. The data
+// is spilled to disk.
+func NewTemporaryTableData(memBuffer kv.MemBuffer, memTracker *memory.Tracker, disk TemporaryTableDiskData) TemporaryTableData {
 	return &temporaryTableData{
-		MemBuffer: memBuffer,
-		tblSize:   make(map[int64]int64),
+		MemBuffer:  memBuffer,
+		tblSize:    make(map[int64]int64),
+		memTracker: memTracker,
+		disk:       disk,
+	}
+}
+
+// Get implements the kv.Retriever interface.
+func (d *temporaryTableData) Get(ctx context.Context, k kv.Key) ([]byte, error) {
+	return d.disk.Get(ctx, d.MemBuffer, k)
+}
+
+// Iter implements the kv.Retriever interface.
+func (d *temporaryTableData) Iter(k kv.Key, upperBound kv.Key) (kv.Iterator, error) {
+	return d.disk.Iter(d.MemBuffer, k, upperBound)
+}
+
+// IterReverse implements the kv.Retriever interface.
+func (d *temporaryTableData) IterReverse(k kv.Key, lowerBound kv.Key) (kv.Iterator, error) {
+	return d.disk.IterReverse(d.MemBuffer, k, lowerBound)
+}
+
+// Staging implements the TemporaryTableData interface.
+func (d *temporaryTableData) Staging() kv.StagingHandle {
+	h := d.MemBuffer.Staging()
+	d.stagingTblSize = append(d.stagingTblSize, stagingTblSize{handle: h, tblSize: maps.Clone(d.tblSize)})
+	return h
+}
+
+// Release implements the TemporaryTableData interface.
+func (d *temporaryTableData) Release(h kv.StagingHandle) {
+	d.MemBuffer.Release(h)
+	d.popStaging(h)
+}
+
+// Cleanup implements the TemporaryTableData interface.
+func (d *temporaryTableData) Cleanup(h kv.StagingHandle) {
+	d.MemBuffer.Cleanup(h)
+	if staging, ok := d.popStaging(h); ok {
+		d.tblSize = staging.tblSize
 	}
+	d.memTracker.ReplaceBytesUsed(int64(d.MemBuffer.Size()))
+}
+
+func (d *temporaryTableData) popStaging(h kv.StagingHandle) (stagingTblSize, bool) {
+	for i := len(d.stagingTblSize) - 1; i >= 0; i-- {
+		if d.stagingTblSize[i].handle == h {
+			staging := d.stagingTblSize[i]
+			d.stagingTblSize = d.stagingTblSize[:i]
+			return staging, true
+		}
+	}
+	return stagingTblSize{}, false
 }
 
 // GetTableSize get the size of a table
@@ -593,6 +682,58 @@ func (d *temporaryTableData) SetTableKey(tblID int64, k kv.Key, val []byte) erro
 func (d *temporaryTableData) updateTblSize(tblID int64, beforeSize int) {
 	delta := int64(d.MemBuffer.Size() - beforeSize)
 	d.tblSize[tblID] = d.GetTableSize(tblID) + delta
+	d.memTracker.Consume(delta)
+}
+
+// ActionSpill implements the TemporaryTableData interface.
+func (d *temporaryTableData) ActionSpill() memory.ActionOnExceed {
+	return &temporaryTableSpillAction{d: d}
+}
+
+// Spill implements the TemporaryTableData interface.
+func (d *temporaryTableData) Spill() error {
+	if len(d.stagingTblSize) > 0 || !d.spillPending.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	memBuffer, err := d.disk.Spill(d.MemBuffer)
+	if err != nil {
+		return err
+	}
+	// The table sizes are kept, tidb_tmp_table_max_size limits the size of the data both in memory and on disk.
+	d.MemBuffer = memBuffer
+	d.memTracker.ReplaceBytesUsed(int64(memBuffer.Size()))
+	return nil
+}
+
+// Close implements the TemporaryTableData interface.
+func (d *temporaryTableData) Close() error {
+	return d.disk.Close()
+}
+
+// temporaryTableSpillAction implements memory.ActionOnExceed for the temporary table data in session.
+type temporaryTableSpillAction struct {
+	memory.BaseOOMAction
+	d *temporaryTableData
+}
+
+// Action marks the data to be spilled if it's not empty, or calls the fallback action.
+func (a *temporaryTableSpillAction) Action(t *memory.Tracker) {
+	if a.d.memTracker.BytesConsumed() > 0 && a.d.spillPending.CompareAndSwap(false, true) {
+		logutil.BgLogger().Info("memory exceeds quota, spill the temporary table data in session to disk.",
+			zap.Int64("consumed", t.BytesConsumed()), zap.Int64("quota", t.GetBytesLimit()),
+			zap.Int64("tempTableData", a.d.memTracker.BytesConsumed()))
+		a.SetFinished()
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (*temporaryTableSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
 }
 
 const (
@@ -712,6 +853,10 @@ type SessionVars struct {
 	// TxnCtxMu is used to protect TxnCtx.
 	TxnCtxMu sync.Mutex
 
+	// XATxn is the XA transaction branch associated with the session. It's nil if the session is not in an XA
+	// transaction.
+	XATxn *XATxnContext
+
 	// TxnManager is used to manage txn context in session
 	TxnManager any
 
@@ -1264,6 +1409,15 @@ type SessionVars struct {
 	// see https://dev.mysql.com/doc/refman/8.0/en/server-system-variables.html#sysvar_cte_max_recursion_depth
 	CTEMaxRecursionDepth int
 
+	// MaxSpRecursionDepth is the maximum number of times a stored procedure can be called recursively, 0 disables
+	// the recursion.
+	// see https://dev.mysql.com/doc/refman/8.0/en/server-system-variables.html#sysvar_max_sp_recursion_depth
+	MaxSpRecursionDepth int
+
+	// ProcedureCallDepth records how many invocations of each stored procedure are running in the session, which
+	// is keyed by the qualified name of the procedure.
+	ProcedureCallDepth map[string]int
+
 	// The temporary table size threshold, which is different from MySQL. See https://github.com/pingcap/tidb/issues/28691.
 	TMPTableSize int64
 
@@ -1283,6 +1437,10 @@ type SessionVars struct {
 	// TemporaryTableData stores committed kv values for temporary table for current session.
 	TemporaryTableData TemporaryTableData
 
+	// preservedTempTables keeps the global temporary tables with `ON COMMIT PRESERVE ROWS` used in the session. Their
+	// rows live across transactions, so do the auto IDs allocated for them.
+	preservedTempTables map[int64]tableutil.TempTable
+
 	// MPPStoreFailTTL indicates the duration that protect TiDB from sending task to a new recovered TiFlash.
 	MPPStoreFailTTL string
 
@@ -1525,6 +1683,13 @@ type SessionVars struct {
 	// 0 > value <= 1 applies that percentage as the estimate when rows are found. For example 0.1 = 10%.
 	OptOrderingIdxSelRatio float64
 
+	// VectorSearchNProbe is the number of the nearest lists of a vector index scanned by a vector search.
+	// Vector indexes are not used if it's 0.
+	VectorSearchNProbe int
+
+	// EnableMaterializedViewRewrite indicates whether the optimizer rewrites the queries to read the materialized views.
+	EnableMaterializedViewRewrite bool
+
 	// EnableMPPSharedCTEExecution indicates whether we enable the shared CTE execution strategy on MPP side.
 	EnableMPPSharedCTEExecution bool
 
@@ -2019,6 +2184,7 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		EnableIndexMergeJoin:          DefTiDBEnableIndexMergeJoin,
 		AllowFallbackToTiKV:           make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:          DefCTEMaxRecursionDepth,
+		MaxSpRecursionDepth:           DefMaxSpRecursionDepth,
 		TMPTableSize:                  DefTiDBTmpTableMaxSize,
 		MPPStoreFailTTL:               DefTiDBMPPStoreFailTTL,
 		Rng:                           mathutil.NewWithTime(),
@@ -2038,6 +2204,7 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		mppExchangeCompressionMode:    DefaultExchangeCompressionMode,
 		mppVersion:                    kv.MppVersionUnspecified,
 		EnableLateMaterialization:     DefTiDBOptEnableLateMaterialization,
+		VectorSearchNProbe:            DefTiDBVectorSearchNProbe,
 		TiFlashComputeDispatchPolicy:  tiflashcompute.DispatchPolicyConsistentHash,
 		ResourceGroupName:             resourcegroup.DefaultResourceGroupName,
 		DefaultCollationForUTF8MB4:    mysql.DefaultCollationName,
@@ -2646,7 +2813,7 @@ func (s *SessionVars) GetTemporaryTable(tblInfo *model.TableInfo) tableutil.Temp
 		tempTables := s.TxnCtx.TemporaryTables
 		tempTable, ok := tempTables[tblInfo.ID]
 		if !ok {
-			tempTable = tableutil.TempTableFromMeta(tblInfo)
+			tempTable = s.newTemporaryTable(tblInfo)
 			tempTables[tblInfo.ID] = tempTable
 		}
 		return tempTable
@@ -2655,6 +2822,37 @@ func (s *SessionVars) GetTemporaryTable(tblInfo *model.TableInfo) tableutil.Temp
 	return nil
 }
 
+func (s *SessionVars) newTemporaryTable(tblInfo *model.TableInfo) tableutil.TempTable {
+	if tblInfo.TempTableType != model.TempTableGlobal || !tblInfo.TempTablePreserveRows {
+		return tableutil.TempTableFromMeta(tblInfo)
+	}
+
+	if s.preservedTempTables == nil {
+		s.preservedTempTables = make(map[int64]tableutil.TempTable)
+	}
+	tempTable, ok := s.preservedTempTables[tblInfo.ID]
+	if !ok {
+		tempTable = tableutil.TempTableFromMeta(tblInfo)
+		s.preservedTempTables[tblInfo.ID] = tempTable
+	}
+	// The table is shared by the transactions of the session, only reset the states of the current transaction.
+	tempTable.SetModified(false)
+	tempTable.SetSize(0)
+	return tempTable
+}
+
+// ResetPreservedTemporaryTable resets the states of a global temporary table with `ON COMMIT PRESERVE ROWS` kept in
+// the session, including the auto ID allocator. It's called when the table is truncated.
+func (s *SessionVars) ResetPreservedTemporaryTable(tblID int64) {
+	delete(s.preservedTempTables, tblID)
+}
+
+// HasPreservedTemporaryTables returns whether the session has used global temporary tables with
+// `ON COMMIT PRESERVE ROWS`, whose rows may be kept in the session.
+func (s *SessionVars) HasPreservedTemporaryTables() bool {
+	return len(s.preservedTempTables) > 0
+}
+
 // EncodeSessionStates saves session states into SessionStates.
 func (s *SessionVars) EncodeSessionStates(_ context.Context, sessionStates *sessionstates.SessionStates) (err error) {
 	// Encode user-defined variables.
@@ -3564,6 +3762,54 @@ func (s *SessionVars) GetSeekFactor(tbl *model.TableInfo) float64 {
 	return s.seekFactor
 }
 
+// AppendOptimizerVars appends the values of the variables which affect the optimizer to b, such as the tidb_opt_*
+// variables. The plans can be shared between the sessions with the same values. The fields are read directly, which
+// is much cheaper than formatting the system variables.
+func (s *SessionVars) AppendOptimizerVars(b []byte) []byte {
+	var flags uint64
+	for i, on := range []bool{
+		s.AllowProjectionPushDown, s.AllowDeriveTopN, s.AllowAggPushDown, s.AllowDistinctAggPushDown,
+		s.EnableSkewDistinctAgg, s.Enable3StageDistinctAgg, s.Enable3StageMultiDistinctAgg, s.ExplainNonEvaledSubQuery,
+		s.AllowWriteRowID, s.EnableOuterJoinReorder, s.OptimizerEnableNAAJ, s.MPPOuterJoinFixedBuildSide,
+		s.allowInSubqToJoinAndAgg, s.preferRangeScan, s.EnableCorrelationAdjustment,
+		s.OptimizerEnableNewOnlyFullGroupByCheck, s.enableForceInlineCTE, s.enableIndexMerge, s.DisableHashJoin,
+		s.EnableIndexMergeJoin, s.EnableAdvancedJoinHint, s.OptimizerUseInvisibleIndexes, s.OptPrefixIndexSingleScan,
+		s.EnableLateMaterialization, s.EnableMaterializedViewRewrite, s.EnableMPPSharedCTEExecution,
+		s.EnableFuzzyBinding,
+	} {
+		if on {
+			flags |= 1 << i
+		}
+	}
+	b = binary.BigEndian.AppendUint64(b, flags)
+	for _, v := range []int64{
+		int64(s.AllowCartesianBCJ), int64(s.OptimizerSelectivityLevel), s.LimitPushDownThreshold,
+		int64(s.TiDBOptJoinReorderThreshold), int64(s.CostModelVersion), s.RangeMaxSize, int64(s.CorrelationExpFactor),
+	} {
+		b = binary.BigEndian.AppendUint64(b, uint64(v))
+	}
+	for _, v := range []float64{
+		s.CorrelationThreshold, s.cpuFactor, s.CopTiFlashConcurrencyFactor, s.copCPUFactor, s.networkFactor,
+		s.scanFactor, s.descScanFactor, s.seekFactor, s.memoryFactor, s.diskFactor, s.concurrencyFactor,
+		s.DefaultStrMatchSelectivity, s.OptOrderingIdxSelThresh, s.OptOrderingIdxSelRatio,
+	} {
+		b = binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	b = append(b, s.OptObjective...)
+	b = append(b, 0)
+	fixes := make([]uint64, 0, len(s.OptimizerFixControl))
+	for fix := range s.OptimizerFixControl {
+		fixes = append(fixes, fix)
+	}
+	slices.Sort(fixes)
+	for _, fix := range fixes {
+		b = binary.BigEndian.AppendUint64(b, fix)
+		b = append(b, s.OptimizerFixControl[fix]...)
+		b = append(b, 0)
+	}
+	return b
+}
+
 // EnableEvalTopNEstimationForStrMatch means if we need to evaluate expression with TopN to improve estimation.
 // Currently, it's only for string matching functions (like and regexp).
 func (s *SessionVars) EnableEvalTopNEstimationForStrMatch() bool {
@@ -3745,6 +3991,15 @@ const (
 	OptObjectiveDeterminate = "determinate"
 )
 
+const (
+	// InstancePlanCacheEvictLRU is a possible value and the default value for TiDBInstancePlanCacheEvictionPolicy.
+	// The least recently used plans are evicted first.
+	InstancePlanCacheEvictLRU string = "LRU"
+	// InstancePlanCacheEvictLFU is a possible value for TiDBInstancePlanCacheEvictionPolicy.
+	// The least frequently hit plans are evicted first.
+	InstancePlanCacheEvictLFU = "LFU"
+)
+
 // GetOptObjective return the session variable "tidb_opt_objective".
 // Please see comments of SessionVars.OptObjective for details.
 func (s *SessionVars) GetOptObjective() string {
@@ -17,6 +17,7 @@ package variable_test
 import (
 	"context"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -559,3 +560,61 @@ func TestSetStatus(t *testing.T) {
 	require.False(t, sv.InTxn())
 	require.Equal(t, mysql.ServerStatusAutocommit|mysql.ServerStatusCursorExists, sv.Status())
 }
+
+func TestAppendOptimizerVars(t *testing.T) {
+	names := []string{
+		variable.TiDBEnableIndexMerge,
+		variable.TiDBEnableIndexMergeJoin,
+		variable.TiDBCostModelVersion,
+		variable.TiDBDefaultStrMatchSelectivity,
+	}
+	for name := range variable.GetSysVars() {
+		if strings.HasPrefix(name, "tidb_opt") {
+			names = append(names, name)
+		}
+	}
+	// Every variable which affects the optimizer must change the key of the instance plan cache.
+	for _, name := range names {
+		sv := variable.GetSysVar(name)
+		if !sv.HasSessionScope() {
+			continue
+		}
+		var val string
+		switch sv.Type {
+		case variable.TypeBool:
+			val = variable.BoolToOnOff(!variable.TiDBOptOn(sv.Value))
+		case variable.TypeInt, variable.TypeUnsigned:
+			def, err := strconv.ParseInt(sv.Value, 10, 64)
+			require.NoError(t, err)
+			next := def + 1
+			if uint64(next) > sv.MaxValue {
+				next = def - 1
+			}
+			val = strconv.FormatInt(next, 10)
+		case variable.TypeFloat:
+			def, err := strconv.ParseFloat(sv.Value, 64)
+			require.NoError(t, err)
+			next := def + 0.5
+			if next > float64(sv.MaxValue) {
+				next = (def + float64(sv.MinValue)) / 2
+			}
+			val = strconv.FormatFloat(next, 'f', -1, 64)
+		case variable.TypeEnum:
+			for _, v := range sv.PossibleValues {
+				if !strings.EqualFold(v, sv.Value) {
+					val = v
+					break
+				}
+			}
+		case variable.TypeStr:
+			val = "44:ON"
+		default:
+			require.Failf(t, "unexpected type", "%s", name)
+		}
+		vars := variable.NewSessionVars(nil)
+		require.NoError(t, vars.SetSystemVar(name, sv.Value), name)
+		before := vars.AppendOptimizerVars(nil)
+		require.NoError(t, vars.SetSystemVar(name, val), name)
+		require.NotEqual(t, before, vars.AppendOptimizerVars(nil), name)
+	}
+}
@@ -222,6 +222,15 @@ const (
 	// TiDBEvolvePlanTaskEndTime is the end time of evolution task.
 	TiDBEvolvePlanTaskEndTime = "tidb_evolve_plan_task_end_time"
 
+	// TiDBEnablePlanRegressionGuard indicates whether to record the plan baselines from the statement summary,
+	// and fall back to the accepted plan by a binding when a plan regression is detected.
+	TiDBEnablePlanRegressionGuard = "tidb_enable_plan_regression_guard"
+	// TiDBPlanRegressionRatio is the ratio of the average latency of a new plan to the accepted plan,
+	// beyond which the new plan is regarded as a regression.
+	TiDBPlanRegressionRatio = "tidb_plan_regression_ratio"
+	// TiDBPlanRegressionMinExecCount is the min execution count of a plan before it's compared with the accepted plan.
+	TiDBPlanRegressionMinExecCount = "tidb_plan_regression_min_exec_count"
+
 	// TiDBSlowLogThreshold is used to set the slow log threshold in the server.
 	TiDBSlowLogThreshold = "tidb_slow_log_threshold"
 
@@ -837,6 +846,12 @@ const (
 	TiDBPlanCacheInvalidationOnFreshStats = "tidb_plan_cache_invalidation_on_fresh_stats"
 	// TiDBSessionPlanCacheSize controls the size of session plan cache.
 	TiDBSessionPlanCacheSize = "tidb_session_plan_cache_size"
+	// TiDBEnableInstancePlanCache indicates whether to enable the instance plan cache shared by all sessions.
+	TiDBEnableInstancePlanCache = "tidb_enable_instance_plan_cache"
+	// TiDBInstancePlanCacheMaxMemSize indicates the memory budget of the instance plan cache.
+	TiDBInstancePlanCacheMaxMemSize = "tidb_instance_plan_cache_max_mem_size"
+	// TiDBInstancePlanCacheEvictionPolicy indicates how to evict plans when the instance plan cache exceeds its memory budget.
+	TiDBInstancePlanCacheEvictionPolicy = "tidb_instance_plan_cache_eviction_policy"
 
 	// TiDBConstraintCheckInPlacePessimistic controls whether to skip certain kinds of pessimistic locks.
 	TiDBConstraintCheckInPlacePessimistic = "tidb_constraint_check_in_place_pessimistic"
@@ -912,6 +927,14 @@ const (
 	// via the ordering index.
 	TiDBOptOrderingIdxSelRatio = "tidb_opt_ordering_index_selectivity_ratio"
 
+	// TiDBVectorSearchNProbe is the number of the nearest lists of a vector index scanned by a vector search.
+	// Vector indexes are not used if it's 0.
+	TiDBVectorSearchNProbe = "tidb_vector_search_nprobe"
+
+	// TiDBOptEnableMaterializedViewRewrite indicates whether the optimizer rewrites the queries to read the
+	// materialized views. The rows of a materialized view are as of its last refresh, so it's disabled by default.
+	TiDBOptEnableMaterializedViewRewrite = "tidb_opt_enable_materialized_view_rewrite"
+
 	// TiDBOptEnableMPPSharedCTEExecution indicates whether the optimizer try to build shared CTE scan during MPP execution.
 	TiDBOptEnableMPPSharedCTEExecution = "tidb_opt_enable_mpp_shared_cte_execution"
 	// TiDBOptFixControl makes the user able to control some details of the optimizer behavior.
@@ -981,6 +1004,9 @@ const (
 	// It is used to invalidate the collected predicate columns after turning off TiDBEnableColumnTracking, which avoids physical deletion.
 	// It doesn't have cache in memory, and we directly get/set the variable value from/to mysql.tidb.
 	TiDBDisableColumnTrackingTime = "tidb_disable_column_tracking_time"
+	// TiDBEnableCardinalityFeedback indicates whether to learn cardinality corrections from the actual row counts of
+	// the executions and apply them in the estimation.
+	TiDBEnableCardinalityFeedback = "tidb_enable_cardinality_feedback"
 	// TiDBStatsLoadPseudoTimeout indicates whether to fallback to pseudo stats after load timeout.
 	TiDBStatsLoadPseudoTimeout = "tidb_stats_load_pseudo_timeout"
 	// TiDBMemQuotaBindingCache indicates the memory quota for the bind cache.
@@ -1290,6 +1316,9 @@ const (
 	DefTiDBEvolvePlanTaskMaxTime                   = 600 // 600s
 	DefTiDBEvolvePlanTaskStartTime                 = "00:00 +0000"
 	DefTiDBEvolvePlanTaskEndTime                   = "23:59 +0000"
+	DefTiDBEnablePlanRegressionGuard               = false
+	DefTiDBPlanRegressionRatio                     = 2.0
+	DefTiDBPlanRegressionMinExecCount              = 10
 	DefInnodbLockWaitTimeout                       = 50 // 50s
 	DefTiDBStoreLimit                              = 0
 	DefTiDBMetricSchemaStep                        = 60 // 60s
@@ -1316,6 +1345,7 @@ const (
 	DefTiDBEnableIndexMergeJoin                    = false
 	DefTiDBTrackAggregateMemoryUsage               = true
 	DefCTEMaxRecursionDepth                        = 1000
+	DefMaxSpRecursionDepth                         = 0
 	DefTiDBTmpTableMaxSize                         = 64 << 20 // 64MB.
 	DefTiDBEnableLocalTxn                          = false
 	DefTiDBTSOClientBatchMaxWaitTime               = 0.0 // 0ms
@@ -1340,6 +1370,7 @@ const (
 	DefTiDBTableCacheLease                         = 3 // 3s
 	DefTiDBPersistAnalyzeOptions                   = true
 	DefTiDBEnableColumnTracking                    = false
+	DefTiDBEnableCardinalityFeedback               = false
 	DefTiDBStatsLoadSyncWait                       = 100
 	DefTiDBStatsLoadPseudoTimeout                  = true
 	DefSysdateIsNow                                = false
@@ -1399,6 +1430,9 @@ const (
 	DefTiDBEnableNonPreparedPlanCacheForDML        = false
 	DefTiDBNonPreparedPlanCacheSize                = 100
 	DefTiDBPlanCacheMaxPlanSize                    = 2 * size.MB
+	DefTiDBEnableInstancePlanCache                 = false
+	DefTiDBInstancePlanCacheMaxMemSize             = 100 * size.MB
+	DefTiDBInstancePlanCacheEvictionPolicy         = InstancePlanCacheEvictLRU
 	// MaxDDLReorgBatchSize is exported for testing.
 	MaxDDLReorgBatchSize                  int32  = 10240
 	MinDDLReorgBatchSize                  int32  = 32
@@ -1439,6 +1473,7 @@ const (
 	DefTiDBTTLRunningTasks                            = -1
 	DefPasswordReuseHistory                           = 0
 	DefPasswordReuseTime                              = 0
+	DefEventScheduler                                 = true
 	DefTiDBStoreBatchSize                             = 4
 	DefTiDBHistoricalStatsDuration                    = 7 * 24 * time.Hour
 	DefTiDBEnableHistoricalStatsForCapture            = false
@@ -1456,6 +1491,8 @@ const (
 	DefTiDBOptEnableLateMaterialization               = true
 	DefTiDBOptOrderingIdxSelThresh                    = 0.0
 	DefTiDBOptOrderingIdxSelRatio                     = -1
+	DefTiDBVectorSearchNProbe                         = 8
+	DefTiDBOptEnableMaterializedViewRewrite           = false
 	DefTiDBOptEnableMPPSharedCTEExecution             = false
 	DefTiDBPlanCacheInvalidationOnFreshStats          = true
 	DefTiDBEnableRowLevelChecksum                     = false
@@ -1523,6 +1560,7 @@ var (
 	PersistAnalyzeOptions                = atomic.NewBool(DefTiDBPersistAnalyzeOptions)
 	TableCacheLease                      = atomic.NewInt64(DefTiDBTableCacheLease)
 	EnableColumnTracking                 = atomic.NewBool(DefTiDBEnableColumnTracking)
+	EnableCardinalityFeedback            = atomic.NewBool(DefTiDBEnableCardinalityFeedback)
 	StatsLoadSyncWait                    = atomic.NewInt64(DefTiDBStatsLoadSyncWait)
 	StatsLoadPseudoTimeout               = atomic.NewBool(DefTiDBStatsLoadPseudoTimeout)
 	MemQuotaBindingCache                 = atomic.NewInt64(DefTiDBMemQuotaBindingCache)
@@ -1532,6 +1570,9 @@ var (
 	MaxAutoAnalyzeTime                   = atomic.NewInt64(DefTiDBMaxAutoAnalyzeTime)
 	// variables for plan cache
 	PreparedPlanCacheMemoryGuardRatio = atomic.NewFloat64(DefTiDBPrepPlanCacheMemoryGuardRatio)
+	EnableInstancePlanCache           = atomic.NewBool(DefTiDBEnableInstancePlanCache)
+	InstancePlanCacheMaxMemSize       = atomic.NewUint64(DefTiDBInstancePlanCacheMaxMemSize)
+	InstancePlanCacheEvictionPolicy   = atomic.NewString(DefTiDBInstancePlanCacheEvictionPolicy)
 	EnableDistTask                    = atomic.NewBool(DefTiDBEnableDistTask)
 	EnableFastCreateTable             = atomic.NewBool(DefTiDBEnableFastCreateTable)
 	DDLForce2Queue                    = atomic.NewBool(false)
@@ -1577,6 +1618,7 @@ var (
 	TTLDeleteWorkerCount            = atomic.NewInt32(DefTiDBTTLDeleteWorkerCount)
 	PasswordHistory                 = atomic.NewInt64(DefPasswordReuseHistory)
 	PasswordReuseInterval           = atomic.NewInt64(DefPasswordReuseTime)
+	EnableEventScheduler            = atomic.NewBool(DefEventScheduler)
 	IsSandBoxModeEnabled            = atomic.NewBool(false)
 	MaxPreparedStmtCountValue       = atomic.NewInt64(DefMaxPreparedStmtCount)
 	HistoricalStatsDuration         = atomic.NewDuration(DefTiDBHistoricalStatsDuration)
@@ -0,0 +1,62 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import "fmt"
+
+// XID is the identifier of an XA transaction branch.
+type XID struct {
+	FormatID int64
+	GTRID    string
+	BQUAL    string
+}
+
+// String implements fmt.Stringer interface.
+func (x XID) String() string {
+	return fmt.Sprintf("%x,%x,%d", x.GTRID, x.BQUAL, x.FormatID)
+}
+
+// XAState is the state of an XA transaction branch.
+type XAState int
+
+// XA transaction branch states.
+const (
+	// XAStateActive means the branch is started by XA START and accepts statements.
+	XAStateActive XAState = iota
+	// XAStateIdle means the branch is ended by XA END and waits for XA PREPARE or XA COMMIT ONE PHASE.
+	XAStateIdle
+	// XAStatePrepared means the branch is being prepared by XA PREPARE. The prepared branch is detached from the
+	// session once the statement finishes.
+	XAStatePrepared
+)
+
+// String implements fmt.Stringer interface. The names are the same as the ones in the errors of MySQL.
+func (s XAState) String() string {
+	switch s {
+	case XAStateActive:
+		return "ACTIVE"
+	case XAStateIdle:
+		return "IDLE"
+	case XAStatePrepared:
+		return "PREPARED"
+	}
+	return "NON-EXISTING"
+}
+
+// XATxnContext is the XA transaction branch associated with the session.
+type XATxnContext struct {
+	XID   XID
+	State XAState
+}
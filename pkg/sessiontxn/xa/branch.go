@@ -0,0 +1,218 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xa
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain/infosync"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// maxChunkSize is the max size of the mutations stored in one row of the branch table.
+const maxChunkSize = 4 * 1024 * 1024
+
+const (
+	flagPut byte = iota
+	flagDelete
+)
+
+// Branch is a prepared XA transaction branch.
+type Branch struct {
+	XID variable.XID
+	// StartTS is the start ts of the transaction of the branch. The branch is recovered with it, see RecoverBranch.
+	StartTS uint64
+	// SchemaVersion and TableIDs are used to check the schema when the branch is committed.
+	SchemaVersion int64
+	TableIDs      []int64
+	// Mutations is the encoded mutations of the branch, see EncodeMutations.
+	Mutations []byte
+	// Owner is the ID of the TiDB instance which prepares the branch and holds its transaction.
+	Owner string
+}
+
+// SchemaMetaVersion implements tikv.SchemaVer interface, so that the branch can be set as the kv.InfoSchema option of
+// the transaction replaying it.
+func (b *Branch) SchemaMetaVersion() int64 {
+	return b.SchemaVersion
+}
+
+// EncodeMutations encodes the mutations in the memory buffer of the transaction.
+func EncodeMutations(txn kv.Transaction) ([]byte, error) {
+	iter, err := txn.GetMemBuffer().Iter(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	var buf []byte
+	for iter.Valid() {
+		if len(iter.Value()) == 0 {
+			buf = append(buf, flagDelete)
+			buf = codec.EncodeCompactBytes(buf, iter.Key())
+		} else {
+			buf = append(buf, flagPut)
+			buf = codec.EncodeCompactBytes(buf, iter.Key())
+			buf = codec.EncodeCompactBytes(buf, iter.Value())
+		}
+		if err := iter.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// ApplyMutations writes the mutations encoded by EncodeMutations into the transaction.
+func ApplyMutations(txn kv.Transaction, data []byte) error {
+	var key, val []byte
+	var err error
+	for len(data) > 0 {
+		flag := data[0]
+		data, key, err = codec.DecodeCompactBytes(data[1:])
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch flag {
+		case flagPut:
+			data, val, err = codec.DecodeCompactBytes(data)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = txn.Set(key, val)
+		case flagDelete:
+			err = txn.Delete(key)
+		default:
+			err = errors.Errorf("invalid mutation flag %d", flag)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func internalCtx(ctx context.Context) context.Context {
+	return kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+}
+
+// SaveBranch persists the prepared branch. The mutations are split into several rows when they are too large.
+func SaveBranch(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, b *Branch) error {
+	tableIDs, err := json.Marshal(b.TableIDs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var sql strings.Builder
+	sql.WriteString("INSERT INTO mysql.tidb_xa_branches (gtrid, bqual, format_id, seq, start_ts, schema_version, table_ids, mutations, owner) VALUES ")
+	args := make([]any, 0, 9)
+	mutations := b.Mutations
+	for seq := 0; seq == 0 || len(mutations) > 0; seq++ {
+		chunk := mutations[:min(len(mutations), maxChunkSize)]
+		mutations = mutations[len(chunk):]
+		if chunk == nil {
+			chunk = []byte{}
+		}
+		if seq > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString("(%?, %?, %?, %?, %?, %?, %?, %?, %?)")
+		args = append(args, []byte(b.XID.GTRID), []byte(b.XID.BQUAL), b.XID.FormatID, seq, b.StartTS, b.SchemaVersion, string(tableIDs), chunk, b.Owner)
+	}
+	_, _, err = exec.ExecRestrictedSQL(internalCtx(ctx), nil, sql.String(), args...)
+	if kv.ErrKeyExists.Equal(err) {
+		return exeerrors.ErrXaerDupid
+	}
+	return err
+}
+
+// LoadBranch loads the persisted branch. It returns nil if the branch doesn't exist.
+func LoadBranch(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, xid variable.XID) (*Branch, error) {
+	rows, _, err := exec.ExecRestrictedSQL(internalCtx(ctx), nil,
+		"SELECT start_ts, schema_version, table_ids, mutations, owner FROM mysql.tidb_xa_branches WHERE gtrid = %? AND bqual = %? AND format_id = %? ORDER BY seq",
+		[]byte(xid.GTRID), []byte(xid.BQUAL), xid.FormatID)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	b := &Branch{
+		XID:           xid,
+		StartTS:       rows[0].GetUint64(0),
+		SchemaVersion: rows[0].GetInt64(1),
+		Owner:         rows[0].GetString(4),
+	}
+	if err := json.Unmarshal([]byte(rows[0].GetJSON(2).String()), &b.TableIDs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, row := range rows {
+		b.Mutations = append(b.Mutations, row.GetBytes(3)...)
+	}
+	return b, nil
+}
+
+// OwnerIsAlive checks whether the instance which prepares the branch is still alive. The transaction of the branch is
+// held by that instance with its pessimistic locks, so the branch can't be finished by replaying the mutations on
+// other instances, otherwise the locks will block the replay, and the branch may be committed twice.
+func OwnerIsAlive(ctx context.Context, b *Branch) (bool, error) {
+	if b.Owner == "" {
+		return false, nil
+	}
+	servers, err := infosync.GetAllServerInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := servers[b.Owner]
+	return ok, nil
+}
+
+// BranchExists checks whether the branch is persisted.
+func BranchExists(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, xid variable.XID) (bool, error) {
+	rows, _, err := exec.ExecRestrictedSQL(internalCtx(ctx), nil,
+		"SELECT 1 FROM mysql.tidb_xa_branches WHERE gtrid = %? AND bqual = %? AND format_id = %? AND seq = 0",
+		[]byte(xid.GTRID), []byte(xid.BQUAL), xid.FormatID)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// DeleteBranch deletes the persisted branch.
+func DeleteBranch(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, xid variable.XID) error {
+	_, _, err := exec.ExecRestrictedSQL(internalCtx(ctx), nil,
+		"DELETE FROM mysql.tidb_xa_branches WHERE gtrid = %? AND bqual = %? AND format_id = %?",
+		[]byte(xid.GTRID), []byte(xid.BQUAL), xid.FormatID)
+	return err
+}
+
+// ListBranches lists the XIDs of all the persisted branches.
+func ListBranches(ctx context.Context, exec sqlexec.RestrictedSQLExecutor) ([]variable.XID, error) {
+	rows, _, err := exec.ExecRestrictedSQL(internalCtx(ctx), nil,
+		"SELECT format_id, gtrid, bqual FROM mysql.tidb_xa_branches WHERE seq = 0 ORDER BY create_time")
+	if err != nil {
+		return nil, err
+	}
+	xids := make([]variable.XID, 0, len(rows))
+	for _, row := range rows {
+		xids = append(xids, variable.XID{
+			FormatID: row.GetInt64(0),
+			GTRID:    string(row.GetBytes(1)),
+			BQUAL:    string(row.GetBytes(2)),
+		})
+	}
+	return xids, nil
+}
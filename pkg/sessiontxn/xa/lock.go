@@ -0,0 +1,156 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xa
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	tikverr "github.com/tikv/client-go/v2/error"
+	tikvstore "github.com/tikv/client-go/v2/kv"
+	"github.com/tikv/client-go/v2/oracle"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
+)
+
+const (
+	// preparedLockTTL is the TTL of the primary lock of a prepared branch in milliseconds, it's about 49 days. The
+	// lock doesn't expire after the instance holding the transaction is gone, so that the other transactions can't
+	// write the keys locked by the branch before it's committed or rolled back.
+	preparedLockTTL = math.MaxUint32
+
+	keepLocksMaxBackoff = 20000
+	keepLocksTimeout    = time.Minute
+)
+
+var primaryKeyPrefix = []byte("mXABranch")
+
+// PrimaryKey returns the primary key of the transaction of the branch. It's locked first when the branch starts, so
+// the status of the branch is always decided by the lock on it.
+func PrimaryKey(xid variable.XID) kv.Key {
+	key := append([]byte{}, primaryKeyPrefix...)
+	key = codec.EncodeInt(key, xid.FormatID)
+	key = codec.EncodeBytes(key, []byte(xid.GTRID))
+	return codec.EncodeBytes(key, []byte(xid.BQUAL))
+}
+
+// KeepLocks extends the TTL of the primary lock of the prepared branch, so that the pessimistic locks of the branch
+// are kept even if the instance holding its transaction is gone, see RecoverBranch.
+func KeepLocks(ctx context.Context, store kv.Storage, xid variable.XID, startTS uint64) error {
+	s, ok := store.(tikv.Storage)
+	if !ok {
+		return errors.New("XA transaction is only supported by TiKV")
+	}
+	primary := PrimaryKey(xid)
+	bo := tikv.NewBackoffer(ctx, keepLocksMaxBackoff)
+	for {
+		loc, err := s.GetRegionCache().LocateKey(bo, primary)
+		if err != nil {
+			return err
+		}
+		req := tikvrpc.NewRequest(tikvrpc.CmdTxnHeartBeat, &kvrpcpb.TxnHeartBeatRequest{
+			PrimaryLock:   primary,
+			StartVersion:  startTS,
+			AdviseLockTtl: preparedLockTTL,
+		})
+		resp, err := s.SendReq(bo, req, loc.Region, keepLocksTimeout)
+		if err != nil {
+			return err
+		}
+		regionErr, err := resp.GetRegionError()
+		if err != nil {
+			return err
+		}
+		if regionErr != nil {
+			if err = bo.Backoff(tikv.BoRegionMiss(), errors.New(regionErr.String())); err != nil {
+				return err
+			}
+			continue
+		}
+		if resp.Resp == nil {
+			return errors.Trace(tikverr.ErrBodyMissing)
+		}
+		if keyErr := resp.Resp.(*kvrpcpb.TxnHeartBeatResponse).GetError(); keyErr != nil {
+			return errors.Errorf("keep the locks of the XA branch failed: %s", keyErr)
+		}
+		return nil
+	}
+}
+
+// RecoverBranch recovers the transaction of a prepared branch which is lost with the instance holding it. The
+// recovered transaction has the start ts of the branch and locks the keys of the branch again. The locks are still
+// held by the branch, see KeepLocks, so it never meets write conflicts. It can be committed to commit the branch, or
+// rolled back to release the locks.
+func RecoverBranch(ctx context.Context, store kv.Storage, b *Branch) (kv.Transaction, error) {
+	txn, err := store.Begin(tikv.WithStartTS(b.StartTS))
+	if err != nil {
+		return nil, err
+	}
+	txn.SetOption(kv.Pessimistic, true)
+	if err = ApplyMutations(txn, b.Mutations); err != nil {
+		terror.Log(txn.Rollback())
+		return nil, err
+	}
+	var killed uint32
+	if err = lockBranch(ctx, store, txn, b.XID, &killed); err != nil {
+		// Rolling back the transaction releases the locks of the branch, so just stop the heartbeat of it.
+		atomic.StoreUint32(&killed, 1)
+		return nil, err
+	}
+	return txn, nil
+}
+
+func lockBranch(ctx context.Context, store kv.Storage, txn kv.Transaction, xid variable.XID, killed *uint32) error {
+	// The keys which aren't locked by the branch, like the keys of the non-unique indexes, are locked with the
+	// current ts. They can only be written by the transactions locking the rows first, so there are no write
+	// conflicts either.
+	forUpdateTS, err := store.GetOracle().GetTimestamp(ctx, &oracle.Option{TxnScope: kv.GlobalTxnScope})
+	if err != nil {
+		return err
+	}
+	newLockCtx := func() *kv.LockCtx {
+		lockCtx := tikvstore.NewLockCtx(forUpdateTS, tikvstore.LockAlwaysWait, time.Now())
+		lockCtx.Killed = killed
+		return lockCtx
+	}
+	// The primary key must be locked first, so that it's still the primary key of the transaction.
+	if err = txn.LockKeys(ctx, newLockCtx(), PrimaryKey(xid)); err != nil {
+		return err
+	}
+	iter, err := txn.GetMemBuffer().Iter(nil, nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	var keys []kv.Key
+	for iter.Valid() {
+		keys = append(keys, iter.Key().Clone())
+		if err = iter.Next(); err != nil {
+			return err
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return txn.LockKeys(ctx, newLockCtx(), keys...)
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xa
+
+import (
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+)
+
+// registry keeps the transactions of the branches prepared in this instance. A held transaction still owns its
+// pessimistic locks, so it can be committed or rolled back directly without replaying the persisted mutations.
+var registry = struct {
+	sync.Mutex
+	branches map[variable.XID]*heldBranch
+}{branches: make(map[variable.XID]*heldBranch)}
+
+type heldBranch struct {
+	txn kv.Transaction
+	// busy means the branch is being committed or rolled back.
+	busy bool
+}
+
+// Hold keeps the transaction of the prepared branch.
+func Hold(xid variable.XID, txn kv.Transaction) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.branches[xid] = &heldBranch{txn: txn}
+}
+
+// Acquire marks the branch as being committed or rolled back and returns its transaction. The returned transaction
+// is nil if it isn't held by this instance. It returns false if the branch is being committed or rolled back by
+// another session. Release must be called after the branch is finished if Acquire returns true.
+func Acquire(xid variable.XID) (kv.Transaction, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+	b, ok := registry.branches[xid]
+	if !ok {
+		registry.branches[xid] = &heldBranch{busy: true}
+		return nil, true
+	}
+	if b.busy {
+		return nil, false
+	}
+	b.busy = true
+	return b.txn, true
+}
+
+// Release removes the branch from the registry.
+func Release(xid variable.XID) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.branches, xid)
+}
+
+// IsHeld checks whether the branch is held by this instance.
+func IsHeld(xid variable.XID) bool {
+	registry.Lock()
+	defer registry.Unlock()
+	_, ok := registry.branches[xid]
+	return ok
+}
@@ -22,14 +22,19 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/collate"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"go.uber.org/zap"
 )
 
-// BuildExtendedStats build extended stats for column groups if needed based on the column samples.
-func BuildExtendedStats(sctx sessionctx.Context,
-	tableID int64, cols []*model.ColumnInfo, collectors []*SampleCollector) (*ExtendedStatsColl, error) {
+// LoadExtendedStatsItems loads the extended stats registered on the table. The returned items only have
+// the types and the column IDs filled.
+func LoadExtendedStatsItems(sctx sessionctx.Context, tableID int64) (map[string]*ExtendedStatsItem, error) {
 	const sql = "SELECT name, type, column_ids FROM mysql.stats_extended WHERE table_id = %? and status in (%?, %?)"
 
 	sqlExec := sctx.GetRestrictedSQLExecutor()
@@ -37,10 +42,7 @@ func BuildExtendedStats(sctx sessionctx.Context,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	if len(rows) == 0 {
-		return nil, nil
-	}
-	statsColl := NewExtendedStatsColl()
+	items := make(map[string]*ExtendedStatsItem, len(rows))
 	for _, row := range rows {
 		name := row.GetString(0)
 		item := &ExtendedStatsItem{Tp: uint8(row.GetInt64(1))}
@@ -50,6 +52,21 @@ func BuildExtendedStats(sctx sessionctx.Context,
 			logutil.BgLogger().Error("invalid column_ids in mysql.stats_extended, skip collecting extended stats for this row", zap.String("column_ids", colIDs), zap.Error(err))
 			continue
 		}
+		items[name] = item
+	}
+	return items, nil
+}
+
+// BuildExtendedStats build extended stats for column groups if needed based on the column samples.
+// Only correlation can be built from the column samples of analyze version 1.
+func BuildExtendedStats(sctx sessionctx.Context,
+	tableID int64, cols []*model.ColumnInfo, collectors []*SampleCollector) (*ExtendedStatsColl, error) {
+	items, err := LoadExtendedStatsItems(sctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	statsColl := NewExtendedStatsColl()
+	for name, item := range items {
 		item = fillExtendedStatsItemVals(sctx, item, cols, collectors)
 		if item != nil {
 			statsColl.Stats[name] = item
@@ -61,6 +78,121 @@ func BuildExtendedStats(sctx sessionctx.Context,
 	return statsColl, nil
 }
 
+// BuildExtendedStatsFromRowSamples builds extended stats based on the row samples of analyze version 2.
+// The NDVs of cardinality stats come from groupFMSketches, which are maintained on all the rows and keyed
+// by the stats names. The dependency degrees are computed from the row samples.
+func BuildExtendedStatsFromRowSamples(sctx sessionctx.Context, items map[string]*ExtendedStatsItem,
+	cols []*model.ColumnInfo, collectors []*SampleCollector, rowCollector RowSampleCollector,
+	groupFMSketches map[string]*FMSketch) (*ExtendedStatsColl, error) {
+	statsColl := NewExtendedStatsColl()
+	for name, item := range items {
+		switch item.Tp {
+		case ast.StatsTypeCardinality:
+			sketch, ok := groupFMSketches[name]
+			if !ok {
+				continue
+			}
+			item.ScalarVals = float64(min(sketch.NDV(), rowCollector.Base().Count))
+			item.FMSketch = sketch.Copy()
+		case ast.StatsTypeDependency:
+			offsets, ok := colOffsetsOfExtendedStats(item, cols)
+			if !ok || len(offsets) != 2 {
+				continue
+			}
+			degree, err := dependencyDegree(sctx.GetSessionVars().StmtCtx, rowCollector.Base().Samples, cols, offsets[0], offsets[1])
+			if err != nil {
+				return nil, err
+			}
+			item.ScalarVals = degree
+		case ast.StatsTypeCorrelation:
+			item = fillExtStatsCorrVals(sctx, item, cols, collectors)
+		default:
+			item = nil
+		}
+		if item != nil {
+			statsColl.Stats[name] = item
+		}
+	}
+	if len(statsColl.Stats) == 0 {
+		return nil, nil
+	}
+	return statsColl, nil
+}
+
+// colOffsetsOfExtendedStats returns the offsets of the columns of the extended stats in cols.
+func colOffsetsOfExtendedStats(item *ExtendedStatsItem, cols []*model.ColumnInfo) ([]int, bool) {
+	offsets := make([]int, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		found := false
+		for i, col := range cols {
+			if col.ID == id {
+				offsets = append(offsets, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return offsets, true
+}
+
+// dependencyDegree computes the degree to which column x determines column y, i.e, the fraction of the
+// sample rows whose x value is always paired with the same y value. It's 1 if x functionally determines y.
+func dependencyDegree(sc *stmtctx.StatementContext, samples WeightedRowSampleHeap, cols []*model.ColumnInfo, x, y int) (float64, error) {
+	if len(samples) == 0 {
+		return 0, nil
+	}
+	type group struct {
+		y          string
+		count      int
+		consistent bool
+	}
+	groups := make(map[string]*group)
+	var buf []byte
+	var err error
+	for _, sample := range samples {
+		buf, err = encodeSampleValueForGroup(sc, buf[:0], sample.Columns[x], &cols[x].FieldType)
+		if err != nil {
+			return 0, err
+		}
+		xKey := string(buf)
+		buf, err = encodeSampleValueForGroup(sc, buf[:0], sample.Columns[y], &cols[y].FieldType)
+		if err != nil {
+			return 0, err
+		}
+		g, ok := groups[xKey]
+		if !ok {
+			groups[xKey] = &group{y: string(buf), count: 1, consistent: true}
+			continue
+		}
+		g.count++
+		if g.consistent && g.y != string(buf) {
+			g.consistent = false
+		}
+	}
+	supporting := 0
+	for _, g := range groups {
+		if g.consistent {
+			supporting += g.count
+		}
+	}
+	return float64(supporting) / float64(len(samples)), nil
+}
+
+// encodeSampleValueForGroup encodes the value so that the values equal under the collation of the column
+// have the same encoded bytes.
+func encodeSampleValueForGroup(sc *stmtctx.StatementContext, b []byte, val types.Datum, ft *types.FieldType) ([]byte, error) {
+	if !val.IsNull() && ft.EvalType() == types.ETString && ft.GetType() != mysql.TypeEnum && ft.GetType() != mysql.TypeSet {
+		var keyVal types.Datum
+		keyVal.SetBytes(collate.GetCollator(ft.GetCollate()).Key(val.GetString()))
+		val = keyVal
+	}
+	b, err := codec.EncodeKey(sc.TimeZone(), b, val)
+	return b, sc.HandleError(err)
+}
+
 func fillExtendedStatsItemVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
 	switch item.Tp {
 	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
@@ -72,16 +204,8 @@ func fillExtendedStatsItemVals(sctx sessionctx.Context, item *ExtendedStatsItem,
 }
 
 func fillExtStatsCorrVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
-	colOffsets := make([]int, 0, 2)
-	for _, id := range item.ColIDs {
-		for i, col := range cols {
-			if col.ID == id {
-				colOffsets = append(colOffsets, i)
-				break
-			}
-		}
-	}
-	if len(colOffsets) != 2 {
+	colOffsets, ok := colOffsetsOfExtendedStats(item, cols)
+	if !ok || len(colOffsets) != 2 {
 		return nil
 	}
 	// samplesX and samplesY are in order of handle, i.e, their SampleItem.Ordinals are in order.
@@ -0,0 +1,318 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// The shapes of the predicates in the cardinality feedback. The shape of several predicates on the same column
+// is the sorted distinct shapes joined by commas, e.g, "eq,like".
+const (
+	// FeedbackShapeEq is the shape of `col = const`.
+	FeedbackShapeEq = "eq"
+	// FeedbackShapeNe is the shape of `col != const`.
+	FeedbackShapeNe = "ne"
+	// FeedbackShapeIn is the shape of `col in (const, ...)`.
+	FeedbackShapeIn = "in"
+	// FeedbackShapeRange is the shape of `col > const`, `col <= const` and so on.
+	FeedbackShapeRange = "range"
+	// FeedbackShapeLike is the shape of the string matching functions like `col like const`.
+	FeedbackShapeLike = "like"
+	// FeedbackShapeNull is the shape of `col is null`.
+	FeedbackShapeNull = "null"
+	// FeedbackShapeOr is the shape of the DNF conditions.
+	FeedbackShapeOr = "or"
+	// FeedbackShapeOther is the shape of the other predicates.
+	FeedbackShapeOther = "other"
+	// FeedbackShapeJoin is the shape of the equal join conditions.
+	FeedbackShapeJoin = "join"
+)
+
+// FeedbackSignificantRatio is the minimal gap between the estimated and actual row counts which is worth a
+// cardinality correction.
+const FeedbackSignificantRatio = 2.0
+
+// The corrections are bounded, so a few extreme executions won't make the estimation absurd.
+const (
+	minFeedbackRatio = 1e-4
+	maxFeedbackRatio = 1e4
+)
+
+// MinFeedbackObservations is the number of the consistent executions needed before a correction is applied, so the
+// estimation isn't changed by a single execution with an unusual parameter.
+const MinFeedbackObservations = 3
+
+// maxFeedbackWeight bounds the weight of the observations in the past, so a correction still follows the changes of
+// the data.
+const maxFeedbackWeight = 16
+
+// FeedbackKey identifies a cardinality correction. For the predicates on a column, it's made of the table, the
+// column and the shape of the predicates. For the equal join conditions, the Ref fields are the join key on the
+// other side, and the key with the smaller table and column ID goes first.
+type FeedbackKey struct {
+	Shape       string
+	TableID     int64
+	ColumnID    int64
+	RefTableID  int64
+	RefColumnID int64
+}
+
+// NewJoinFeedbackKey creates the key of the correction for the equal join condition on two columns.
+func NewJoinFeedbackKey(tableID, columnID, otherTableID, otherColumnID int64) FeedbackKey {
+	if otherTableID < tableID || (otherTableID == tableID && otherColumnID < columnID) {
+		tableID, columnID, otherTableID, otherColumnID = otherTableID, otherColumnID, tableID, columnID
+	}
+	return FeedbackKey{
+		Shape:       FeedbackShapeJoin,
+		TableID:     tableID,
+		ColumnID:    columnID,
+		RefTableID:  otherTableID,
+		RefColumnID: otherColumnID,
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (k FeedbackKey) String() string {
+	if k.Shape == FeedbackShapeJoin {
+		return fmt.Sprintf("%d.%d:%s:%d.%d", k.TableID, k.ColumnID, k.Shape, k.RefTableID, k.RefColumnID)
+	}
+	return fmt.Sprintf("%d.%d:%s", k.TableID, k.ColumnID, k.Shape)
+}
+
+// IsSignificantFeedback checks whether the ratio between the actual and estimated row counts is a significant gap.
+func IsSignificantFeedback(ratio float64) bool {
+	return ratio >= FeedbackSignificantRatio || ratio <= 1/FeedbackSignificantRatio
+}
+
+// Correction is a cardinality correction learned from the actual row counts of the executions. The estimated
+// selectivity of the predicates is multiplied by the Ratio.
+type Correction struct {
+	UpdateTime time.Time
+	Ratio      float64
+	// Count is the number of the consistent executions which have updated the correction.
+	Count int64
+	// StatsVersion is the LastAnalyzeVersion of the table stats when the correction is learned. The correction
+	// decays when newer stats arrive.
+	StatsVersion uint64
+}
+
+// CardinalityFeedback stores the cardinality corrections of this instance. It's updated by the executions and
+// synchronized with mysql.stats_cardinality_feedback by the stats handle.
+var CardinalityFeedback = newFeedbackCache()
+
+type feedbackCache struct {
+	items map[FeedbackKey]*Correction
+	// tables counts the corrections of each table, so the estimation on the tables without corrections is cheap.
+	tables map[int64]int
+	// dirty is the corrections changed since the last flush, and a nil one means it's removed.
+	dirty map[FeedbackKey]*Correction
+	mu    sync.RWMutex
+}
+
+func newFeedbackCache() *feedbackCache {
+	return &feedbackCache{
+		items:  make(map[FeedbackKey]*Correction),
+		tables: make(map[int64]int),
+		dirty:  make(map[FeedbackKey]*Correction),
+	}
+}
+
+// HasTable checks whether there is any correction on the table.
+func (c *feedbackCache) HasTable(tableID int64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tables[tableID] > 0
+}
+
+// Get returns the correction of the key.
+func (c *feedbackCache) Get(key FeedbackKey) (Correction, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.items[key]
+	if !ok {
+		return Correction{}, false
+	}
+	return *item, true
+}
+
+// IsApplicable checks whether the correction has been observed by enough consistent executions to be applied.
+func (c Correction) IsApplicable() bool {
+	return c.Count >= MinFeedbackObservations
+}
+
+// Observe updates the correction of the key by the ratio between the actual row count and the estimated one
+// without any correction. The ratio is smoothed by the geometric mean of the observations weighted by their count.
+// The correction is removed if the gap is no longer significant, or it's in the opposite direction of the
+// correction, which means the executions with different parameters disagree on the correction.
+func (c *feedbackCache) Observe(key FeedbackKey, ratio float64, statsVersion uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !IsSignificantFeedback(ratio) {
+		c.remove(key)
+		return
+	}
+	item := &Correction{
+		Ratio:        min(max(ratio, minFeedbackRatio), maxFeedbackRatio),
+		Count:        1,
+		StatsVersion: statsVersion,
+		UpdateTime:   time.Now(),
+	}
+	if old, ok := c.items[key]; ok {
+		if (old.Ratio > 1) != (item.Ratio > 1) {
+			c.remove(key)
+			return
+		}
+		weight := float64(min(old.Count, maxFeedbackWeight))
+		item.Ratio = old.Ratio * math.Pow(item.Ratio/old.Ratio, 1/(weight+1))
+		item.Count += old.Count
+	}
+	c.put(key, item)
+	c.dirty[key] = item
+}
+
+// TableIDs returns the tables which have corrections.
+func (c *feedbackCache) TableIDs() []int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ids := make([]int64, 0, len(c.tables))
+	for id := range c.tables {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Decay weakens the corrections of the table learned before the stats of statsVersion, since the new stats may
+// have fixed the estimation. Each new version of stats halves the logarithm of the ratio.
+func (c *feedbackCache) Decay(tableID int64, statsVersion uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tables[tableID] == 0 {
+		return
+	}
+	for key, item := range c.items {
+		if key.TableID != tableID || item.StatsVersion >= statsVersion {
+			continue
+		}
+		newItem := *item
+		newItem.Ratio = math.Sqrt(item.Ratio)
+		newItem.StatsVersion = statsVersion
+		newItem.UpdateTime = time.Now()
+		if !IsSignificantFeedback(newItem.Ratio) {
+			c.remove(key)
+			continue
+		}
+		c.put(key, &newItem)
+		c.dirty[key] = &newItem
+	}
+}
+
+// Reset removes the corrections involving the tables, or all the corrections if no table is specified. The removed
+// corrections are not flushed, the caller should remove them from the storage.
+func (c *feedbackCache) Reset(tableIDs ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(tableIDs) == 0 {
+		c.items = make(map[FeedbackKey]*Correction)
+		c.tables = make(map[int64]int)
+		c.dirty = make(map[FeedbackKey]*Correction)
+		return
+	}
+	ids := make(map[int64]struct{}, len(tableIDs))
+	for _, id := range tableIDs {
+		ids[id] = struct{}{}
+	}
+	onTables := func(key FeedbackKey) bool {
+		_, ok := ids[key.TableID]
+		if !ok && key.Shape == FeedbackShapeJoin {
+			_, ok = ids[key.RefTableID]
+		}
+		return ok
+	}
+	for key := range c.items {
+		if onTables(key) {
+			c.drop(key)
+		}
+	}
+	for key := range c.dirty {
+		if onTables(key) {
+			delete(c.dirty, key)
+		}
+	}
+}
+
+// TakeDirty returns the corrections changed since the last call, and a nil one means it's removed.
+// The caller should give them back by MergeDirty if they fail to be flushed.
+func (c *feedbackCache) TakeDirty() map[FeedbackKey]*Correction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dirty := c.dirty
+	c.dirty = make(map[FeedbackKey]*Correction)
+	return dirty
+}
+
+// MergeDirty gives back the corrections which fail to be flushed.
+func (c *feedbackCache) MergeDirty(dirty map[FeedbackKey]*Correction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, item := range dirty {
+		if _, ok := c.dirty[key]; !ok {
+			c.dirty[key] = item
+		}
+	}
+}
+
+// Load replaces the corrections with the ones loaded from the storage, except the ones not flushed yet.
+func (c *feedbackCache) Load(items map[FeedbackKey]*Correction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.items {
+		if _, ok := c.dirty[key]; !ok {
+			c.drop(key)
+		}
+	}
+	for key, item := range items {
+		if _, ok := c.dirty[key]; !ok {
+			c.put(key, item)
+		}
+	}
+}
+
+func (c *feedbackCache) put(key FeedbackKey, item *Correction) {
+	if _, ok := c.items[key]; !ok {
+		c.tables[key.TableID]++
+	}
+	c.items[key] = item
+}
+
+func (c *feedbackCache) remove(key FeedbackKey) {
+	if c.drop(key) {
+		c.dirty[key] = nil
+	}
+}
+
+func (c *feedbackCache) drop(key FeedbackKey) bool {
+	if _, ok := c.items[key]; !ok {
+		return false
+	}
+	delete(c.items, key)
+	if c.tables[key.TableID]--; c.tables[key.TableID] <= 0 {
+		delete(c.tables, key.TableID)
+	}
+	return true
+}
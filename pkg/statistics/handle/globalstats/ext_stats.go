@@ -0,0 +1,219 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package globalstats
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+)
+
+// MergePartitionExtendedStats merges the partition-level extended stats to the global-level ones.
+// partitionColIDs are the IDs of the columns used by the partition expression, and globalStats is
+// the merged global-level column stats, whose NDVs are used to bound the merged NDVs.
+// Correlation stats are not merged because the order correlation only holds inside a partition.
+func MergePartitionExtendedStats(sctx sessionctx.Context, globalTableInfo *model.TableInfo,
+	partitionColIDs []int64, globalStats *GlobalStats) (*statistics.ExtendedStatsColl, error) {
+	pi := globalTableInfo.GetPartitionInfo()
+	if pi == nil {
+		return nil, nil
+	}
+	items, err := statistics.LoadExtendedStatsItems(sctx, globalTableInfo.ID)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	partitionIDs := make([]int64, 0, len(pi.Definitions))
+	var inIDs strings.Builder
+	for i, def := range pi.Definitions {
+		partitionIDs = append(partitionIDs, def.ID)
+		if i > 0 {
+			inIDs.WriteString(",")
+		}
+		inIDs.WriteString(strconv.FormatInt(def.ID, 10))
+	}
+
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	exec := sctx.GetRestrictedSQLExecutor()
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, "select table_id, count from mysql.stats_meta where table_id in ("+inIDs.String()+")")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	partitionCounts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		partitionCounts[row.GetInt64(0)] = row.GetInt64(1)
+	}
+	rows, _, err = exec.ExecRestrictedSQL(ctx, nil, "select table_id, name, stats, fm_sketch from mysql.stats_extended where table_id in ("+inIDs.String()+") and status = %?",
+		statistics.ExtendedStatsAnalyzed)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// stats name -> partition ID -> value
+	partitionVals := make(map[string]map[int64]float64, len(items))
+	// stats name -> partition ID -> FM sketch of the column group
+	partitionSketches := make(map[string]map[int64]*statistics.FMSketch)
+	defer func() {
+		for _, sketches := range partitionSketches {
+			for _, sketch := range sketches {
+				sketch.DestroyAndPutToPool()
+			}
+		}
+	}()
+	for _, row := range rows {
+		if row.IsNull(2) {
+			continue
+		}
+		val, err := strconv.ParseFloat(row.GetString(2), 64)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		partitionID, name := row.GetInt64(0), row.GetString(1)
+		if partitionVals[name] == nil {
+			partitionVals[name] = make(map[int64]float64, len(partitionIDs))
+		}
+		partitionVals[name][partitionID] = val
+		if row.IsNull(3) {
+			continue
+		}
+		sketch, err := statistics.DecodeFMSketch(row.GetBytes(3))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if partitionSketches[name] == nil {
+			partitionSketches[name] = make(map[int64]*statistics.FMSketch, len(partitionIDs))
+		}
+		partitionSketches[name][partitionID] = sketch
+	}
+
+	colNDVs := make(map[int64]int64)
+	for _, hg := range globalStats.Hg {
+		if hg != nil {
+			colNDVs[hg.ID] = hg.NDV
+		}
+	}
+	statsColl := statistics.NewExtendedStatsColl()
+	for name, item := range items {
+		vals := partitionVals[name]
+		// Keep the former global-level stats if some partitions haven't been analyzed with the extended stats.
+		if len(vals) < len(partitionIDs) {
+			continue
+		}
+		var ok bool
+		switch item.Tp {
+		case ast.StatsTypeCardinality:
+			// The partitions analyzed before the FM sketches are kept only have the NDVs, which can only be
+			// merged by estimation.
+			if sketches := partitionSketches[name]; len(sketches) == len(partitionIDs) {
+				item.ScalarVals = mergeGroupFMSketches(sketches, globalStats.Count)
+			} else {
+				item.ScalarVals = mergeGroupNDV(item.ColIDs, partitionColIDs, vals, colNDVs, globalStats.Count)
+			}
+			ok = true
+		case ast.StatsTypeDependency:
+			item.ScalarVals = mergeDependencyDegree(vals, partitionCounts)
+			ok = true
+		}
+		if ok {
+			statsColl.Stats[name] = item
+		}
+	}
+	if len(statsColl.Stats) == 0 {
+		return nil, nil
+	}
+	return statsColl, nil
+}
+
+// mergeGroupFMSketches merges the partition-level FM sketches of a column group, and returns the global NDV.
+func mergeGroupFMSketches(sketches map[int64]*statistics.FMSketch, count int64) float64 {
+	var merged *statistics.FMSketch
+	for _, sketch := range sketches {
+		if merged == nil {
+			merged = sketch.Copy()
+			continue
+		}
+		merged.MergeFMSketch(sketch)
+	}
+	defer merged.DestroyAndPutToPool()
+	ndv := merged.NDV()
+	if count > 0 {
+		ndv = min(ndv, count)
+	}
+	return float64(ndv)
+}
+
+// mergeGroupNDV merges the partition-level NDVs of a column group.
+// If the column group contains all the partition columns, the same group value can't appear in different
+// partitions, so the global NDV is the sum. Otherwise, the global NDV is between the max partition-level NDV
+// (or the max column NDV) and the sum, and we take the geometric mean of the bounds.
+func mergeGroupNDV(groupColIDs, partitionColIDs []int64, partitionNDVs map[int64]float64, colNDVs map[int64]int64, count int64) float64 {
+	var sum, maxNDV float64
+	for _, ndv := range partitionNDVs {
+		sum += ndv
+		maxNDV = math.Max(maxNDV, ndv)
+	}
+	disjoint := len(partitionColIDs) > 0
+	for _, id := range partitionColIDs {
+		found := false
+		for _, colID := range groupColIDs {
+			if colID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			disjoint = false
+			break
+		}
+	}
+	if disjoint {
+		return sum
+	}
+	lower := maxNDV
+	for _, id := range groupColIDs {
+		lower = math.Max(lower, float64(colNDVs[id]))
+	}
+	upper := sum
+	if count > 0 {
+		upper = math.Min(upper, float64(count))
+	}
+	if upper <= lower {
+		return lower
+	}
+	return math.Sqrt(lower * upper)
+}
+
+// mergeDependencyDegree merges the partition-level dependency degrees weighted by the row counts.
+func mergeDependencyDegree(partitionDegrees map[int64]float64, partitionCounts map[int64]int64) float64 {
+	var sum, total float64
+	for id, degree := range partitionDegrees {
+		count := float64(partitionCounts[id])
+		sum += degree * count
+		total += count
+	}
+	if total == 0 {
+		for _, degree := range partitionDegrees {
+			sum += degree
+		}
+		return sum / float64(len(partitionDegrees))
+	}
+	return sum / total
+}
@@ -641,6 +641,96 @@ func TestCorrelationStatsCompute(t *testing.T) {
 	))
 }
 
+func TestCardinalityAndDependencyStatsCompute(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@session.tidb_analyze_version=2")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d, %d)", i%10, i%10*2, i%7))
+	}
+	err := tk.ExecToErr("alter table t add stats_extended s1 cardinality(a)")
+	require.Equal(t, "Only support Cardinality statistics type on at least 2 columns", err.Error())
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,b)")
+	tk.MustExec("alter table t add stats_extended s2 cardinality(a,c)")
+	tk.MustExec("alter table t add stats_extended s3 dependency(a,b)")
+	tk.MustExec("alter table t add stats_extended s4 dependency(c,a)")
+	tk.MustQuery("select name, type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"s1 0 [1,2] <nil> 0",
+		"s2 0 [1,3] <nil> 0",
+		"s3 1 [1,2] <nil> 0",
+		"s4 1 [3,1] <nil> 0",
+	))
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select name, type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"s1 0 [1,2] 10.000000 1",
+		"s2 0 [1,3] 70.000000 1",
+		"s3 1 [1,2] 1.000000 1",
+		"s4 1 [3,1] 0.000000 1",
+	))
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	require.NoError(t, dom.StatsHandle().Update(is))
+	statsTbl := dom.StatsHandle().GetTableStats(tbl.Meta())
+	require.NotNil(t, statsTbl.ExtendedStats)
+	require.Len(t, statsTbl.ExtendedStats.Stats, 4)
+	require.Equal(t, float64(70), statsTbl.ExtendedStats.Stats["s2"].ScalarVals)
+	require.Equal(t, []int64{3, 1}, statsTbl.ExtendedStats.Stats["s4"].ColIDs)
+}
+
+func TestPartitionExtendedStatsMerge(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@session.tidb_analyze_version=2")
+	tk.MustExec("set @@tidb_partition_prune_mode='dynamic'")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int) partition by hash(a) partitions 2")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d, %d)", i%10, i%10*2, i%7))
+	}
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,c)")
+	tk.MustExec("alter table t add stats_extended s2 cardinality(b,c)")
+	tk.MustExec("alter table t add stats_extended s3 dependency(a,b)")
+	tk.MustExec("analyze table t")
+
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+	for _, def := range tblInfo.Partition.Definitions {
+		tk.MustQuery(fmt.Sprintf("select name, stats, status, fm_sketch is not null from mysql.stats_extended where table_id = %d", def.ID)).Sort().Check(testkit.Rows(
+			"s1 35.000000 1 1",
+			"s2 35.000000 1 1",
+			"s3 1.000000 1 0",
+		))
+	}
+	// The group NDVs are merged by the FM sketches of the partitions, so the group (b,c) gets the exact NDV
+	// although the partition column isn't in the group.
+	tk.MustQuery(fmt.Sprintf("select name, stats, status from mysql.stats_extended where table_id = %d", tblInfo.ID)).Sort().Check(testkit.Rows(
+		"s1 70.000000 1",
+		"s2 70.000000 1",
+		"s3 1.000000 1",
+	))
+
+	// If a partition was analyzed without the FM sketches, the group NDVs are merged by estimation. The
+	// partition column `a` is in the group (a,c), so the group NDVs of partitions are summed up. The group
+	// (b,c) may overlap between partitions, so its NDV is estimated between the max partition NDV and the sum.
+	tk.MustExec("update mysql.stats_extended set fm_sketch = null")
+	tk.MustExec("analyze table t partition p1")
+	tk.MustQuery(fmt.Sprintf("select name, stats, status from mysql.stats_extended where table_id = %d", tblInfo.ID)).Sort().Check(testkit.Rows(
+		"s1 70.000000 1",
+		"s2 49.497475 1",
+		"s3 1.000000 1",
+	))
+
+	tk.MustExec("alter table t drop stats_extended s1")
+	tk.MustQuery("select count(*) from mysql.stats_extended where name = 's1' and status <> 2").Check(testkit.Rows("0"))
+}
+
 func TestSyncStatsExtendedRemoval(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	tk := testkit.NewTestKit(t, store)
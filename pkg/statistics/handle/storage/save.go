@@ -20,7 +20,6 @@ import (
 	"strings"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/statistics"
@@ -300,7 +299,7 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 	if extStats == nil || len(extStats.Stats) == 0 {
 		return
 	}
-	var bytes []byte
+	var bytes, fmSketch []byte
 	var statsStr string
 	for name, item := range extStats.Stats {
 		bytes, err = json.Marshal(item.ColIDs)
@@ -308,13 +307,12 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 			return 0, err
 		}
 		strColIDs := string(bytes)
-		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
-			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
-			statsStr = item.StringVals
+		statsStr = fmt.Sprintf("%f", item.ScalarVals)
+		fmSketch, err = statistics.EncodeFMSketch(item.FMSketch)
+		if err != nil {
+			return 0, err
 		}
-		if _, err = util.Exec(sctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, statistics.ExtendedStatsAnalyzed); err != nil {
+		if _, err = util.Exec(sctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, statistics.ExtendedStatsAnalyzed, fmSketch); err != nil {
 			return 0, err
 		}
 	}
@@ -85,6 +85,14 @@ type StatsUsage interface {
 
 	// DumpColStatsUsageToKV sweeps the whole list, updates the column stats usage map and dumps it to KV.
 	DumpColStatsUsageToKV() error
+
+	// DumpCardinalityFeedbackToKV decays the cardinality corrections of the tables which have newer stats, dumps the
+	// changed corrections to KV and loads the corrections learned by all the instances.
+	DumpCardinalityFeedbackToKV() error
+
+	// ResetCardinalityFeedback removes the cardinality corrections involving the tables, or all the corrections if no
+	// table is specified.
+	ResetCardinalityFeedback(tableIDs []int64) error
 }
 
 // IndexUsage is an interface to define the function of collecting index usage stats.
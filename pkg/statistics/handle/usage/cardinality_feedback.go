@@ -0,0 +1,154 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	utilstats "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+)
+
+// DumpCardinalityFeedbackToKV decays the cardinality corrections of the tables which have newer stats, dumps the
+// changed corrections to KV and loads the corrections learned by all the instances.
+func (u *statsUsageImpl) DumpCardinalityFeedbackToKV() error {
+	if !variable.EnableCardinalityFeedback.Load() {
+		return nil
+	}
+	for _, tableID := range statistics.CardinalityFeedback.TableIDs() {
+		if tbl, ok := u.statsHandle.Get(tableID); ok && !tbl.Pseudo {
+			statistics.CardinalityFeedback.Decay(tableID, tbl.LastAnalyzeVersion)
+		}
+	}
+	dirty := statistics.CardinalityFeedback.TakeDirty()
+	if len(dirty) > 0 {
+		err := utilstats.CallWithSCtx(u.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+			return SaveCardinalityFeedback(sctx, dirty)
+		}, utilstats.FlagWrapTxn)
+		if err != nil {
+			statistics.CardinalityFeedback.MergeDirty(dirty)
+			return errors.Trace(err)
+		}
+	}
+	var items map[statistics.FeedbackKey]*statistics.Correction
+	err := utilstats.CallWithSCtx(u.statsHandle.SPool(), func(sctx sessionctx.Context) (err error) {
+		items, err = LoadCardinalityFeedback(sctx)
+		return err
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statistics.CardinalityFeedback.Load(items)
+	return nil
+}
+
+// ResetCardinalityFeedback removes the cardinality corrections involving the tables, or all the corrections if no
+// table is specified.
+func (u *statsUsageImpl) ResetCardinalityFeedback(tableIDs []int64) error {
+	err := utilstats.CallWithSCtx(u.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		if len(tableIDs) == 0 {
+			_, _, err := utilstats.ExecRows(sctx, "DELETE FROM mysql.stats_cardinality_feedback")
+			return err
+		}
+		for _, tableID := range tableIDs {
+			_, _, err := utilstats.ExecRows(sctx, "DELETE FROM mysql.stats_cardinality_feedback WHERE table_id = %? OR ref_table_id = %?", tableID, tableID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, utilstats.FlagWrapTxn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	statistics.CardinalityFeedback.Reset(tableIDs...)
+	return nil
+}
+
+// SaveCardinalityFeedback saves the changed cardinality corrections to mysql.stats_cardinality_feedback, and a nil
+// correction means it's removed.
+func SaveCardinalityFeedback(sctx sessionctx.Context, dirty map[statistics.FeedbackKey]*statistics.Correction) error {
+	keys := make([]statistics.FeedbackKey, 0, len(dirty))
+	for key, item := range dirty {
+		if item == nil {
+			_, _, err := utilstats.ExecRows(sctx, "DELETE FROM mysql.stats_cardinality_feedback WHERE table_id = %? AND column_id = %? AND shape = %? AND ref_table_id = %? AND ref_column_id = %?",
+				key.TableID, key.ColumnID, key.Shape, key.RefTableID, key.RefColumnID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		keys = append(keys, key)
+	}
+	// Use batch insert to reduce cost.
+	for i := 0; i < len(keys); i += batchInsertSize {
+		end := min(i+batchInsertSize, len(keys))
+		sql := new(strings.Builder)
+		sqlescape.MustFormatSQL(sql, "INSERT INTO mysql.stats_cardinality_feedback (table_id, column_id, shape, ref_table_id, ref_column_id, ratio, count, stats_version, update_time) VALUES ")
+		for j := i; j < end; j++ {
+			key, item := keys[j], dirty[keys[j]]
+			// The time is converted to the time zone of the session which executes the insert statement, as what
+			// DumpColStatsUsageToKV does.
+			sqlescape.MustFormatSQL(sql, "(%?, %?, %?, %?, %?, %?, %?, %?, CONVERT_TZ(%?, '+00:00', @@TIME_ZONE))",
+				key.TableID, key.ColumnID, key.Shape, key.RefTableID, key.RefColumnID, item.Ratio, item.Count, item.StatsVersion,
+				item.UpdateTime.UTC().Format(types.TimeFSPFormat))
+			if j < end-1 {
+				sqlescape.MustFormatSQL(sql, ",")
+			}
+		}
+		sqlescape.MustFormatSQL(sql, " ON DUPLICATE KEY UPDATE ratio = VALUES(ratio), count = VALUES(count), stats_version = VALUES(stats_version), update_time = VALUES(update_time)")
+		if _, _, err := utilstats.ExecRows(sctx, sql.String()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// LoadCardinalityFeedback loads all the cardinality corrections from mysql.stats_cardinality_feedback.
+func LoadCardinalityFeedback(sctx sessionctx.Context) (map[statistics.FeedbackKey]*statistics.Correction, error) {
+	rows, _, err := utilstats.ExecRows(sctx, "SELECT table_id, column_id, shape, ref_table_id, ref_column_id, ratio, count, stats_version, CONVERT_TZ(update_time, @@TIME_ZONE, '+00:00') FROM mysql.stats_cardinality_feedback")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	items := make(map[statistics.FeedbackKey]*statistics.Correction, len(rows))
+	for _, row := range rows {
+		key := statistics.FeedbackKey{
+			TableID:     row.GetInt64(0),
+			ColumnID:    row.GetInt64(1),
+			Shape:       row.GetString(2),
+			RefTableID:  row.GetInt64(3),
+			RefColumnID: row.GetInt64(4),
+		}
+		item := &statistics.Correction{
+			Ratio:        row.GetFloat64(5),
+			Count:        row.GetInt64(6),
+			StatsVersion: row.GetUint64(7),
+		}
+		if !row.IsNull(8) {
+			item.UpdateTime, err = row.GetTime(8).GoTime(time.UTC)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		items[key] = item
+	}
+	return items, nil
+}
@@ -188,9 +188,16 @@ func ColAndIdxExistenceMapIsEqual(m1, m2 *ColAndIdxExistenceMap) bool {
 // ExtendedStatsItem is the cached item of a mysql.stats_extended record.
 type ExtendedStatsItem struct {
 	StringVals string
-	ColIDs     []int64
+	// ColIDs are the IDs of the columns. For dependency stats, they are in the order of
+	// the determining column and the dependent column.
+	ColIDs []int64
+	// ScalarVals is the order correlation of the columns for correlation stats, the NDV of
+	// the column group for cardinality stats, and the dependency degree for dependency stats.
 	ScalarVals float64
-	Tp         uint8
+	// FMSketch is the FM sketch of the column group for cardinality stats. It's only set when the stats are
+	// built by analyze, and it's not cached. The sketches of the partitions are merged to the global-level NDV.
+	FMSketch *FMSketch
+	Tp       uint8
 }
 
 // ExtendedStatsColl is a collection of cached items for mysql.stats_extended records.
@@ -248,6 +255,9 @@ type HistColl struct {
 	// For normal index, the column id is enough, as we already have in Idx2ColUniqueIDs. But currently, mv index needs more
 	// information to match the filter against the mv index columns, and we need this map to provide this information.
 	MVIdx2Columns map[int64][]*expression.Column
+	// ExtStats is the extended stats of the table. It's used to adjust the selectivity of the conditions on
+	// the correlated columns.
+	ExtStats *ExtendedStatsColl
 }
 
 // TableMemoryUsage records tbl memory usage
@@ -529,7 +529,36 @@ func (w *GCWorker) calcGlobalMinStartTS(ctx context.Context) (uint64, error) {
 			globalMinStartTS = minStartTS
 		}
 	}
-	return globalMinStartTS, nil
+
+	// The transactions of the prepared XA branches are detached from the sessions, and they may be recovered by
+	// other instances with the start ts, so they're kept until the branches are committed or rolled back.
+	xaMinStartTS, err := w.loadXABranchesMinStartTS(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return min(globalMinStartTS, xaMinStartTS), nil
+}
+
+func (w *GCWorker) loadXABranchesMinStartTS(ctx context.Context) (uint64, error) {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnGC)
+	se := createSession(w.store)
+	defer se.Close()
+	rs, err := se.ExecuteInternal(ctx, `SELECT HIGH_PRIORITY MIN(start_ts) FROM mysql.tidb_xa_branches`)
+	if rs != nil {
+		defer terror.Call(rs.Close)
+	}
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	req := rs.NewChunk(nil)
+	err = rs.Next(ctx, req)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if req.NumRows() == 0 || req.GetRow(0).IsNull(0) {
+		return math.MaxUint64, nil
+	}
+	return req.GetRow(0).GetUint64(0), nil
 }
 
 // calcNewSafePoint uses the current global transaction min start timestamp to calculate the new safe point.
@@ -1525,7 +1554,7 @@ func (w *GCWorker) doGCPlacementRules(se sessiontypes.Session, safePoint uint64,
 		physicalTableIDs = append(physicalTableIDs, historyJob.TableID)
 	case model.ActionDropSchema, model.ActionDropTablePartition, model.ActionTruncateTablePartition,
 		model.ActionReorganizePartition, model.ActionRemovePartitioning,
-		model.ActionAlterTablePartitioning:
+		model.ActionAlterTablePartitioning, model.ActionAlterPrimaryKey:
 		if err = historyJob.DecodeArgs(&physicalTableIDs); err != nil {
 			return
 		}
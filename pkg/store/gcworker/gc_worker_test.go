@@ -335,6 +335,16 @@ func TestMinStartTS(t *testing.T) {
 	require.NoError(t, err)
 	sp = s.gcWorker.calcSafePointByMinStartTS(ctx, now-oracle.ComposeTS(10000, 0))
 	require.Equal(t, now-oracle.ComposeTS(20000, 0)-1, sp)
+
+	// The prepared XA branches block the safe point too.
+	se := createSession(s.store)
+	defer se.Close()
+	_, err = se.ExecuteInternal(kv.WithInternalSourceType(ctx, kv.InternalTxnGC),
+		"INSERT INTO mysql.tidb_xa_branches (gtrid, bqual, format_id, seq, start_ts, schema_version, table_ids, mutations) VALUES ('g1', '', 1, 0, %?, 0, '[]', '')",
+		now-oracle.ComposeTS(30000, 0))
+	require.NoError(t, err)
+	sp = s.gcWorker.calcSafePointByMinStartTS(ctx, now-oracle.ComposeTS(10000, 0))
+	require.Equal(t, now-oracle.ComposeTS(30000, 0)-1, sp)
 }
 
 func TestPrepareGC(t *testing.T) {
@@ -30,8 +30,10 @@ import (
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
 	"github.com/pingcap/tidb/pkg/util/rowcodec"
 	"github.com/pingcap/tidb/pkg/util/tracing"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
 )
 
 // index is the data structure for index data in the KV store.
@@ -83,6 +85,9 @@ func (c *index) GenIndexKey(ec errctx.Context, loc *time.Location, indexedValues
 	idxTblID := c.phyTblID
 	if c.idxInfo.Global {
 		idxTblID = c.tblInfo.ID
+		if _, ok := h.(kv.PartitionHandle); !ok && h != nil && c.phyTblID != c.tblInfo.ID {
+			h = kv.NewPartitionHandle(c.phyTblID, h)
+		}
 	}
 	key, distinct, err = tablecodec.GenIndexKey(loc, c.tblInfo, c.idxInfo, idxTblID, indexedValues, h, buf)
 	err = ec.HandleError(err)
@@ -105,7 +110,15 @@ func (c *index) GenIndexValue(ec errctx.Context, loc *time.Location, distinct bo
 // 2. (i1, [m1,m2], i2, ...) ==> [(i1, m1, i2, ...), (i1, m2, i2, ...)]
 // 3. (i1, null, i2, ...) ==> [(i1, null, i2, ...)]
 // 4. (i1, [], i2, ...) ==> nothing.
+// For FULLTEXT index, every distinct token of the text produces a value.
+// For vector index, the vector produces the list of its nearest centroid.
 func (c *index) getIndexedValue(indexedValues []types.Datum) [][]types.Datum {
+	if c.idxInfo.FullTextInfo != nil {
+		return c.getFullTextIndexedValue(indexedValues)
+	}
+	if c.idxInfo.VectorInfo != nil {
+		return c.getVectorIndexedValue(indexedValues)
+	}
 	if !c.idxInfo.MVIndex {
 		return [][]types.Datum{indexedValues}
 	}
@@ -152,6 +165,45 @@ out:
 	return vals
 }
 
+func (c *index) getFullTextIndexedValue(indexedValues []types.Datum) [][]types.Datum {
+	// The NULL and empty texts have no tokens, so they are not in the index.
+	text := indexedValues[0]
+	if text.IsNull() || (text.Kind() != types.KindString && text.Kind() != types.KindBytes) {
+		return nil
+	}
+	collation := c.tblInfo.Columns[c.idxInfo.Columns[0].Offset].GetCollate()
+	tokens := fulltext.NewTokenizer(c.idxInfo.FullTextInfo).DistinctTokens(text.GetString())
+	vals := make([][]types.Datum, 0, len(tokens))
+	for _, token := range tokens {
+		vals = append(vals, []types.Datum{types.NewCollationStringDatum(token, collation)})
+	}
+	return vals
+}
+
+func (c *index) getVectorIndexedValue(indexedValues []types.Datum) [][]types.Datum {
+	val, err := vectorindex.IndexedDatum(c.idxInfo.VectorInfo, indexedValues[0])
+	if err != nil {
+		// The values of the vector column are always valid, so the invalid value is indexed as NULL.
+		val = types.Datum{}
+	}
+	return [][]types.Datum{{val}}
+}
+
+// getVectorDeletedValue returns the lists to delete the vector from, the entry may be in the untrained list or the
+// list of its nearest centroid.
+func (c *index) getVectorDeletedValue(indexedValues []types.Datum) [][]types.Datum {
+	vals, err := vectorindex.DeletedDatums(c.idxInfo.VectorInfo, indexedValues[0])
+	if err != nil {
+		// The invalid value is indexed as NULL, see getVectorIndexedValue.
+		return [][]types.Datum{{types.Datum{}}}
+	}
+	deleted := make([][]types.Datum, 0, len(vals))
+	for _, val := range vals {
+		deleted = append(deleted, []types.Datum{val})
+	}
+	return deleted
+}
+
 // Create creates a new entry in the kvIndex data.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
@@ -398,7 +450,12 @@ func needPresumeKeyNotExistsFlag(ctx context.Context, txn kv.Transaction, key, t
 
 // Delete removes the entry for handle h and indexedValues from KV index.
 func (c *index) Delete(ctx table.MutateContext, txn kv.Transaction, indexedValue []types.Datum, h kv.Handle) error {
-	indexedValues := c.getIndexedValue(indexedValue)
+	var indexedValues [][]types.Datum
+	if c.idxInfo.VectorInfo != nil {
+		indexedValues = c.getVectorDeletedValue(indexedValue)
+	} else {
+		indexedValues = c.getIndexedValue(indexedValue)
+	}
 	sc := ctx.GetSessionVars().StmtCtx
 	for _, value := range indexedValues {
 		key, distinct, err := c.GenIndexKey(sc.ErrCtx(), sc.TimeZone(), value, h, nil)
@@ -465,7 +522,8 @@ func (c *index) Delete(ctx table.MutateContext, txn kv.Transaction, indexedValue
 				}
 			}
 		}
-		if c.idxInfo.State == model.StatePublic {
+		// A vector may be deleted from several candidate lists, and only one of them exists.
+		if c.idxInfo.State == model.StatePublic && (c.idxInfo.VectorInfo == nil || len(indexedValues) == 1) {
 			// If the index is in public state, delete this index means it must exists.
 			err = txn.SetAssertion(key, kv.SetAssertExist)
 		}
@@ -479,7 +537,7 @@ func (c *index) Delete(ctx table.MutateContext, txn kv.Transaction, indexedValue
 func (c *index) GenIndexKVIter(ec errctx.Context, loc *time.Location, indexedValue []types.Datum,
 	h kv.Handle, handleRestoreData []types.Datum) table.IndexKVGenerator {
 	var mvIndexValues [][]types.Datum
-	if c.Meta().MVIndex {
+	if c.Meta().MVIndex || c.Meta().FullTextInfo != nil || c.Meta().VectorInfo != nil {
 		mvIndexValues = c.getIndexedValue(indexedValue)
 		return table.NewMultiValueIndexKVGenerator(c, ec, loc, h, handleRestoreData, mvIndexValues)
 	}
@@ -15,6 +15,7 @@
 package tables
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 
@@ -30,8 +31,10 @@ import (
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/collate"
 	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/fulltext"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/rowcodec"
+	"github.com/pingcap/tidb/pkg/util/vectorindex"
 	"go.uber.org/zap"
 )
 
@@ -359,6 +362,12 @@ func compareIndexData(
 	sc *stmtctx.StatementContext, cols []*table.Column, indexData, input []types.Datum, indexInfo *model.IndexInfo,
 	tableInfo *model.TableInfo,
 ) error {
+	if indexInfo.FullTextInfo != nil {
+		return compareFullTextIndexData(cols, indexData, input, indexInfo, tableInfo)
+	}
+	if indexInfo.VectorInfo != nil {
+		return compareVectorIndexData(cols, indexData, input, indexInfo, tableInfo)
+	}
 	for i := range indexData {
 		decodedMutationDatum := indexData[i]
 		expectedDatum := input[indexInfo.Columns[i].Offset]
@@ -391,6 +400,49 @@ func compareIndexData(
 	return nil
 }
 
+// compareFullTextIndexData checks whether the decoded token is a token of the input text.
+func compareFullTextIndexData(
+	cols []*table.Column, indexData, input []types.Datum, indexInfo *model.IndexInfo, tableInfo *model.TableInfo,
+) error {
+	col := cols[indexInfo.Columns[0].Offset].ColumnInfo
+	expectedDatum := input[indexInfo.Columns[0].Offset]
+	token := indexData[0].GetString()
+	if !expectedDatum.IsNull() {
+		collator := collate.GetCollator(col.GetCollate())
+		for _, expected := range fulltext.NewTokenizer(indexInfo.FullTextInfo).Tokenize(expectedDatum.GetString()) {
+			if collator.Compare(expected, token) == 0 {
+				return nil
+			}
+		}
+	}
+	err := ErrInconsistentIndexedValue.GenWithStackByArgs(
+		tableInfo.Name.O, indexInfo.Name.O, col.Name.O, indexData[0].String(), expectedDatum.String(),
+	)
+	logutil.BgLogger().Error("inconsistent indexed value in index insertion", zap.Error(err))
+	return err
+}
+
+// compareVectorIndexData checks whether the decoded list is a list the input vector may be indexed in.
+func compareVectorIndexData(
+	cols []*table.Column, indexData, input []types.Datum, indexInfo *model.IndexInfo, tableInfo *model.TableInfo,
+) error {
+	col := cols[indexInfo.Columns[0].Offset].ColumnInfo
+	expectedDatum := input[indexInfo.Columns[0].Offset]
+	expectedLists, err := vectorindex.DeletedDatums(indexInfo.VectorInfo, expectedDatum)
+	if err == nil {
+		for _, expectedList := range expectedLists {
+			if expectedList.IsNull() == indexData[0].IsNull() && bytes.Equal(expectedList.GetBytes(), indexData[0].GetBytes()) {
+				return nil
+			}
+		}
+	}
+	err = ErrInconsistentIndexedValue.GenWithStackByArgs(
+		tableInfo.Name.O, indexInfo.Name.O, col.Name.O, indexData[0].String(), expectedDatum.String(),
+	)
+	logutil.BgLogger().Error("inconsistent indexed value in index insertion", zap.Error(err))
+	return err
+}
+
 // CompareIndexAndVal compare index valued and row value.
 func CompareIndexAndVal(sctx *stmtctx.StatementContext, rowVal types.Datum, idxVal types.Datum, collator collate.Collator, cmpMVIndex bool) (int, error) {
 	var cmpRes int
@@ -0,0 +1,108 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+)
+
+// Signs of the row images in the change logs.
+const (
+	mviewLogSignDelete int64 = -1
+	mviewLogSignInsert int64 = 1
+)
+
+// writeMViewLogs records a row image in the change logs of the table. The log rows are keyed by the start ts of the
+// transaction, the handle and the sign, so a row changed several times in a transaction only leaves the image before
+// the transaction and the image after it: removing a row added by the same transaction cancels the addition.
+func (t *TableCommon) writeMViewLogs(sctx table.MutateContext, txn kv.Transaction, h kv.Handle, r []types.Datum, sign int64) error {
+	if len(t.meta.MViewLogs) == 0 {
+		return nil
+	}
+	sc := sctx.GetSessionVars().StmtCtx
+	var handle types.Datum
+	if h.IsInt() {
+		handle = types.NewIntDatum(h.IntValue())
+	} else {
+		handle = types.NewBytesDatum(h.Encoded())
+	}
+	memBuffer := txn.GetMemBuffer()
+	for _, log := range t.meta.MViewLogs {
+		if sign == mviewLogSignDelete {
+			insertKey, err := mviewLogKey(sc.TimeZone(), log.LogTableID, txn.StartTS(), handle, mviewLogSignInsert)
+			if err != nil {
+				return err
+			}
+			if v, err := memBuffer.Get(context.Background(), insertKey); err == nil && len(v) > 0 {
+				if err = memBuffer.Delete(insertKey); err != nil {
+					return err
+				}
+				continue
+			} else if err != nil && !kv.IsErrNotFound(err) {
+				return err
+			}
+		}
+
+		key, err := mviewLogKey(sc.TimeZone(), log.LogTableID, txn.StartTS(), handle, sign)
+		if err != nil {
+			return err
+		}
+		row := make([]types.Datum, 0, model.MViewLogReservedCols+len(log.Columns))
+		row = append(row, types.NewUintDatum(txn.StartTS()), handle, types.NewIntDatum(sign))
+		for _, colID := range log.Columns {
+			var d types.Datum
+			for _, col := range t.Columns {
+				if col.ID == colID {
+					d = r[col.Offset]
+					break
+				}
+			}
+			row = append(row, d)
+		}
+		colIDs := make([]int64, len(row))
+		for i := range colIDs {
+			colIDs[i] = int64(i + 1)
+		}
+		value, err := tablecodec.EncodeRow(sc.TimeZone(), row, colIDs, nil, nil, &sctx.GetSessionVars().RowEncoder)
+		if err = sc.HandleError(err); err != nil {
+			return err
+		}
+		if err = memBuffer.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mviewLogKey returns the row key of a change log row, the log table is clustered by (ts, handle, sign).
+func mviewLogKey(loc *time.Location, logTableID int64, ts uint64, handle types.Datum, sign int64) (kv.Key, error) {
+	encoded, err := codec.EncodeKey(loc, nil, types.NewUintDatum(ts), handle, types.NewIntDatum(sign))
+	if err != nil {
+		return nil, err
+	}
+	h, err := kv.NewCommonHandle(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return tablecodec.EncodeRowKeyWithHandle(logTableID, h), nil
+}
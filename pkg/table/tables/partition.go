@@ -91,10 +91,13 @@ func (t *partitionedTable) GetPartitionedTable() table.PartitionedTable {
 // partitionedTable is a table, it contains many Partitions.
 type partitionedTable struct {
 	TableCommon
-	partitionExpr   *PartitionExpr
-	partitions      map[int64]*partition
-	evalBufferTypes []*types.FieldType
-	evalBufferPool  sync.Pool
+	partitionExpr *PartitionExpr
+	// subPartitionExpr locates the subpartition within a partition,
+	// only set if the table is subpartitioned.
+	subPartitionExpr *PartitionExpr
+	partitions       map[int64]*partition
+	evalBufferTypes  []*types.FieldType
+	evalBufferPool   sync.Pool
 
 	// Only used during Reorganize partition
 	// reorganizePartitions is the currently used partitions that are reorganized
@@ -102,6 +105,11 @@ type partitionedTable struct {
 	// doubleWritePartitions are the partitions not visible, but we should double write to
 	doubleWritePartitions map[int64]any
 	reorgPartitionExpr    *PartitionExpr
+
+	// Only used during Exchange partition with global indexes.
+	// exchangingPartition is the non-partitioned table to be exchanged,
+	// seen as a partition, for backfilling the new global indexes.
+	exchangingPartition *partition
 }
 
 // TODO: Check which data structures that can be shared between all partitions and which
@@ -112,11 +120,17 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Part
 		return nil, table.ErrUnknownPartition
 	}
 	ret := &partitionedTable{TableCommon: *tbl}
-	partitionExpr, err := newPartitionExpr(tblInfo, pi.Type, pi.Expr, pi.Columns, pi.Definitions)
+	partitionExpr, err := newPartitionExpr(tblInfo, pi.Type, pi.Expr, pi.Columns, pi.TopLevelDefinitions(pi.Definitions))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	ret.partitionExpr = partitionExpr
+	if pi.Sub != nil {
+		ret.subPartitionExpr, err = newPartitionExpr(tblInfo, pi.Sub.Type, pi.Sub.Expr, pi.Sub.Columns, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	initEvalBufferType(ret)
 	ret.evalBufferPool = sync.Pool{
 		New: func() any {
@@ -200,9 +214,54 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Part
 			}
 		}
 	}
+	if len(pi.DDLChangedIndex) > 0 {
+		// The new global indexes must only get entries from the new set of partitions,
+		// and the replaced global indexes only from the old set of partitions.
+		for _, def := range pi.AddingDefinitions {
+			if p, ok := partitions[def.ID]; ok {
+				p.filterIndices(func(idx table.Index) bool {
+					isNew, ok := pi.DDLChangedIndex[idx.Meta().ID]
+					return !ok || isNew
+				})
+			}
+		}
+		for _, def := range pi.DroppingDefinitions {
+			if p, ok := partitions[def.ID]; ok {
+				p.filterIndices(func(idx table.Index) bool {
+					return !pi.DDLChangedIndex[idx.Meta().ID]
+				})
+			}
+		}
+		if ei := tblInfo.ExchangePartitionInfo; ei != nil && ei.RejectWrites {
+			if p, ok := partitions[ei.ExchangePartitionDefID]; ok {
+				p.filterIndices(func(idx table.Index) bool {
+					return !pi.DDLChangedIndex[idx.Meta().ID]
+				})
+			}
+			p, err := initPartition(ret, model.PartitionDefinition{ID: ei.ExchangePartitionTableID})
+			if err != nil {
+				return nil, err
+			}
+			p.filterIndices(func(idx table.Index) bool {
+				return pi.DDLChangedIndex[idx.Meta().ID]
+			})
+			ret.exchangingPartition = p
+		}
+	}
 	return ret, nil
 }
 
+// filterIndices keeps only the indices of the partition that satisfy keep.
+func (p *partition) filterIndices(keep func(idx table.Index) bool) {
+	indices := make([]table.Index, 0, len(p.indices))
+	for _, idx := range p.indices {
+		if keep(idx) {
+			indices = append(indices, idx)
+		}
+	}
+	p.indices = indices
+}
+
 func setIndexesState(t *partitionedTable, state model.SchemaState) []*model.IndexInfo {
 	orig := t.meta.Indices
 	t.meta.Indices = make([]*model.IndexInfo, 0, len(orig))
@@ -242,7 +301,7 @@ func newPartitionExpr(tblInfo *model.TableInfo, tp model.PartitionType, expr str
 	// a partitioned table cannot rely on session context/sql modes, so use a default one!
 	ctx := mock.NewContext()
 	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
-	columns, names, err := expression.ColumnInfos2ColumnsAndNames(ctx, dbName, tblInfo.Name, tblInfo.Cols(), tblInfo)
+	columns, names, err := expression.ColumnInfos2ColumnsAndNames(ctx, dbName, tblInfo.Name, partitionExprCols(tblInfo), tblInfo)
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +321,18 @@ func newPartitionExpr(tblInfo *model.TableInfo, tp model.PartitionType, expr str
 	panic("cannot reach here")
 }
 
+// partitionExprCols returns the columns the partition expressions may refer to. Besides the public columns, the
+// changing columns are included, which are used to locate the new partitions when the partitioning column is modified.
+func partitionExprCols(tblInfo *model.TableInfo) []*model.ColumnInfo {
+	cols := tblInfo.Cols()
+	for _, col := range tblInfo.Columns {
+		if col.State != model.StatePublic && col.ChangeStateInfo != nil {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
 // PartitionExpr is the partition definition expressions.
 type PartitionExpr struct {
 	// UpperBounds: (x < y1); (x < y2); (x < y3), used by locatePartition.
@@ -314,13 +385,15 @@ func (kp *ForKeyPruning) LocateKeyPartition(numParts uint64, r []types.Datum) (i
 
 func initEvalBufferType(t *partitionedTable) {
 	hasExtraHandle := false
-	numCols := len(t.Cols())
+	// The partition expressions may refer to the changing columns, see partitionExprCols.
+	cols := partitionExprCols(t.Meta())
+	numCols := len(cols)
 	if !t.Meta().PKIsHandle {
 		hasExtraHandle = true
 		numCols++
 	}
 	t.evalBufferTypes = make([]*types.FieldType, numCols)
-	for i, col := range t.Cols() {
+	for i, col := range cols {
 		t.evalBufferTypes[i] = &col.FieldType
 	}
 
@@ -1219,13 +1292,27 @@ func (t *partitionedTable) PartitionExpr() *PartitionExpr {
 	return t.partitionExpr
 }
 
+// SubPartitionExpr returns the subpartition expression, nil if the table is
+// not subpartitioned.
+func (t *partitionedTable) SubPartitionExpr() *PartitionExpr {
+	return t.subPartitionExpr
+}
+
 func (t *partitionedTable) GetPartitionColumnIDs() []int64 {
-	// PARTITION BY {LIST|RANGE} COLUMNS uses columns directly without expressions
 	pi := t.Meta().Partition
-	if len(pi.Columns) > 0 {
-		colIDs := make([]int64, 0, len(pi.Columns))
-		for _, name := range pi.Columns {
-			col := table.FindColLowerCase(t.Cols(), name.L)
+	colIDs := t.getPartitionColumnIDs(pi.Columns, t.partitionExpr)
+	if pi.Sub != nil {
+		colIDs = append(colIDs, t.getPartitionColumnIDs(pi.Sub.Columns, t.subPartitionExpr)...)
+	}
+	return colIDs
+}
+
+func (t *partitionedTable) getPartitionColumnIDs(partCols []model.CIStr, partitionExpr *PartitionExpr) []int64 {
+	// PARTITION BY {LIST|RANGE} COLUMNS uses columns directly without expressions
+	if len(partCols) > 0 {
+		colIDs := make([]int64, 0, len(partCols))
+		for _, name := range partCols {
+			col := table.FindColLowerCase(t.WritableCols(), name.L)
 			if col == nil {
 				// For safety, should not happen
 				continue
@@ -1234,11 +1321,11 @@ func (t *partitionedTable) GetPartitionColumnIDs() []int64 {
 		}
 		return colIDs
 	}
-	if t.partitionExpr == nil {
+	if partitionExpr == nil {
 		return nil
 	}
 
-	partitionCols := expression.ExtractColumns(t.partitionExpr.Expr)
+	partitionCols := expression.ExtractColumns(partitionExpr.Expr)
 	colIDs := make([]int64, 0, len(partitionCols))
 	for _, col := range partitionCols {
 		colIDs = append(colIDs, col.ID)
@@ -1248,7 +1335,7 @@ func (t *partitionedTable) GetPartitionColumnIDs() []int64 {
 
 func (t *partitionedTable) GetPartitionColumnNames() []model.CIStr {
 	pi := t.Meta().Partition
-	if len(pi.Columns) > 0 {
+	if len(pi.Columns) > 0 && pi.Sub == nil {
 		return pi.Columns
 	}
 	colIDs := t.GetPartitionColumnIDs()
@@ -1314,6 +1401,14 @@ func (t *partitionedTable) locatePartitionIdx(ctx expression.BuildContext, r []t
 	if err != nil {
 		return -1, errors.Trace(err)
 	}
+	if sub := pi.Sub; sub != nil {
+		// The subpartitions of a partition are stored consecutively.
+		subIdx, err := t.locatePartitionCommon(ctx, sub.Type, t.subPartitionExpr, sub.Num, len(sub.Columns) > 0, r)
+		if err != nil {
+			return -1, errors.Trace(err)
+		}
+		idx = idx*int(sub.Num) + subIdx
+	}
 	return idx, nil
 }
 
@@ -1326,8 +1421,20 @@ func (t *partitionedTable) locatePartition(ctx expression.BuildContext, r []type
 	return pi.Definitions[idx].ID, nil
 }
 
-func (t *partitionedTable) locateReorgPartition(ctx expression.BuildContext, r []types.Datum) (int64, error) {
+func (t *partitionedTable) locateReorgPartition(ctx table.MutateContext, r []types.Datum) (int64, error) {
 	pi := t.Meta().GetPartitionInfo()
+	// The new partitions may be located by the changing column when the partitioning column is modified,
+	// fill its value if the row doesn't contain it.
+	for _, col := range t.DeletableCols() {
+		if col.ChangeStateInfo == nil || col.Offset < len(r) {
+			continue
+		}
+		v, err := table.CastColumnValue(ctx.GetSessionVars(), r[col.DependencyColumnOffset], col.ColumnInfo, false, false)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		r = append(r[:len(r):len(r)], v)
+	}
 	columnsSet := len(pi.DDLColumns) > 0
 	// Note that for KEY/HASH partitioning, since we do not support LINEAR,
 	// all partitions will be reorganized,
@@ -1337,7 +1444,7 @@ func (t *partitionedTable) locateReorgPartition(ctx expression.BuildContext, r [
 	if pi.DDLState == model.StateDeleteReorganization {
 		num = len(pi.DroppingDefinitions)
 	}
-	idx, err := t.locatePartitionCommon(ctx, pi.DDLType, t.reorgPartitionExpr, uint64(num), columnsSet, r)
+	idx, err := t.locatePartitionCommon(ctx.GetExprCtx(), pi.DDLType, t.reorgPartitionExpr, uint64(num), columnsSet, r)
 	if err != nil {
 		return 0, errors.Trace(err)
 	}
@@ -1501,6 +1608,9 @@ func (t *partitionedTable) GetPartition(pid int64) table.PhysicalTable {
 	// Because A nil of type *partition is a kind of `table.PhysicalTable`
 	part, ok := t.partitions[pid]
 	if !ok {
+		if t.exchangingPartition != nil && t.exchangingPartition.physicalTableID == pid {
+			return t.exchangingPartition
+		}
 		// Should never happen!
 		return nil
 	}
@@ -1535,7 +1645,7 @@ func GetReorganizedPartitionedTable(t table.Table) (table.PartitionedTable, erro
 		return nil, err
 	}
 	var tc TableCommon
-	initTableCommon(&tc, tblInfo, tblInfo.ID, t.Cols(), t.Allocators(nil), constraints)
+	initTableCommon(&tc, tblInfo, tblInfo.ID, t.WritableCols(), t.Allocators(nil), constraints)
 
 	// and rebuild the partitioning structure
 	return newPartitionedTable(&tc, tblInfo)
@@ -1637,7 +1747,7 @@ func partitionedTableAddRecord(ctx table.MutateContext, t *partitionedTable, r [
 	}
 	if _, ok := t.reorganizePartitions[pid]; ok {
 		// Double write to the ongoing reorganized partition
-		pid, err = t.locateReorgPartition(ctx.GetExprCtx(), r)
+		pid, err = t.locateReorgPartition(ctx, r)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -1697,7 +1807,7 @@ func (t *partitionedTable) RemoveRecord(ctx table.MutateContext, h kv.Handle, r
 	}
 
 	if _, ok := t.reorganizePartitions[pid]; ok {
-		pid, err = t.locateReorgPartition(ectx, r)
+		pid, err = t.locateReorgPartition(ctx, r)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1783,14 +1893,14 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx table.MutateContext,
 		}
 		newTo, newFrom := int64(0), int64(0)
 		if _, ok := t.reorganizePartitions[to]; ok {
-			newTo, err = t.locateReorgPartition(ectx, newData)
+			newTo, err = t.locateReorgPartition(ctx, newData)
 			// There might be valid cases when errors should be accepted?
 			if err != nil {
 				return errors.Trace(err)
 			}
 		}
 		if _, ok := t.reorganizePartitions[from]; ok {
-			newFrom, err = t.locateReorgPartition(ectx, currData)
+			newFrom, err = t.locateReorgPartition(ctx, currData)
 			// There might be valid cases when errors should be accepted?
 			if err != nil {
 				return errors.Trace(err)
@@ -1826,11 +1936,11 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx table.MutateContext,
 	if _, ok := t.reorganizePartitions[to]; ok {
 		// Even if to == from, in the reorganized partitions they may differ
 		// like in case of a split
-		newTo, err := t.locateReorgPartition(ectx, newData)
+		newTo, err := t.locateReorgPartition(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		newFrom, err := t.locateReorgPartition(ectx, currData)
+		newFrom, err := t.locateReorgPartition(ctx, currData)
 		if err != nil {
 			return errors.Trace(err)
 		}
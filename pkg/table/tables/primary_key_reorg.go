@@ -0,0 +1,166 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/autoid"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+)
+
+// initPrimaryKeyReorgTable initializes the table the writes are applied to when the primary key is being changed.
+func initPrimaryKeyReorgTable(t *TableCommon) error {
+	pi := t.meta.PrimaryKeyReorgInfo
+	if pi == nil || pi.Table == nil {
+		return nil
+	}
+	var allocs autoid.Allocators
+	if t.allocs.PrimaryKeyReorg != nil {
+		allocs = *t.allocs.PrimaryKeyReorg
+	}
+	tbl, err := TableFromMeta(allocs, pi.Table)
+	if err != nil {
+		return err
+	}
+	reorgTbl, ok := tbl.(*TableCommon)
+	if !ok {
+		return errors.Errorf("unexpected table type %T when changing the primary key of table %s", tbl, t.meta.Name)
+	}
+	t.pkReorgTable = reorgTbl
+	return nil
+}
+
+// GetPrimaryKeyReorgTable returns the table the writes are applied to when the primary key is being changed.
+// It returns nil if the primary key of the table is not being changed.
+func GetPrimaryKeyReorgTable(t table.Table) table.PhysicalTable {
+	tc, ok := t.(*TableCommon)
+	if !ok || tc.pkReorgTable == nil {
+		return nil
+	}
+	return tc.pkReorgTable
+}
+
+// BuildClusteredHandle builds the handle of a row from the values of the clustered primary key.
+// It returns nil if the table doesn't have a clustered primary key.
+func BuildClusteredHandle(loc *time.Location, tblInfo *model.TableInfo, r []types.Datum) (kv.Handle, error) {
+	if tblInfo.PKIsHandle {
+		return kv.IntHandle(r[tblInfo.GetPkColInfo().Offset].GetInt64()), nil
+	}
+	if !tblInfo.IsCommonHandle {
+		return nil, nil
+	}
+	pkIdx := FindPrimaryIndex(tblInfo)
+	pkDts := make([]types.Datum, 0, len(pkIdx.Columns))
+	for _, idxCol := range pkIdx.Columns {
+		pkDts = append(pkDts, r[idxCol.Offset])
+	}
+	tablecodec.TruncateIndexValues(tblInfo, pkIdx, pkDts)
+	handleBytes, err := codec.EncodeKey(loc, nil, pkDts...)
+	if err != nil {
+		return nil, err
+	}
+	return kv.NewCommonHandle(handleBytes)
+}
+
+// FindPrimaryKeyReorgIndex returns the unique index used to locate the rows of the table the primary key is
+// changed into or from, when the rows can't be located by the handle directly. It's the temporary unique index
+// in the table with the old primary key, or the non-clustered primary key in the table with the new one.
+func FindPrimaryKeyReorgIndex(t table.Table, tempIndexID int64) table.Index {
+	var pk table.Index
+	for _, idx := range t.Indices() {
+		if idx.Meta().ID == tempIndexID {
+			return idx
+		}
+		if idx.Meta().Primary {
+			pk = idx
+		}
+	}
+	return pk
+}
+
+// isPrimaryKeyReorgWritable checks whether the rows should be added to the table the writes are applied to.
+// The rows are only removed from it in the delete-only state.
+func (t *TableCommon) isPrimaryKeyReorgWritable() bool {
+	return t.pkReorgTable != nil && t.pkReorgTable.meta.State != model.StateDeleteOnly &&
+		t.pkReorgTable.meta.State != model.StateDeleteReorganization
+}
+
+// primaryKeyReorgHandle locates the row in the table the writes are applied to. It returns nil if the row is not
+// copied into it yet, the key used to locate the row is deleted then, so that the copying transaction conflicts.
+func (t *TableCommon) primaryKeyReorgHandle(sctx table.MutateContext, txn kv.Transaction, h kv.Handle, r []types.Datum) (kv.Handle, error) {
+	rt := t.pkReorgTable
+	sc := sctx.GetSessionVars().StmtCtx
+	if rt.meta.PKIsHandle || rt.meta.IsCommonHandle {
+		rh, err := BuildClusteredHandle(sc.TimeZone(), rt.meta, r)
+		return rh, sc.HandleError(err)
+	}
+	if !t.meta.PKIsHandle && !t.meta.IsCommonHandle {
+		// Both tables use _tidb_rowid as the handle, the rows are copied with the same handle.
+		return h, nil
+	}
+	idx := FindPrimaryKeyReorgIndex(rt, t.meta.PrimaryKeyReorgInfo.TempIndexID)
+	vals, err := idx.FetchValues(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	key, _, err := idx.GenIndexKey(sc.ErrCtx(), sc.TimeZone(), vals, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	val, err := txn.Get(context.Background(), key)
+	if err != nil {
+		if kv.IsErrNotFound(err) {
+			return nil, txn.Delete(key)
+		}
+		return nil, err
+	}
+	return tablecodec.DecodeHandleInUniqueIndexValue(val, false)
+}
+
+// addPrimaryKeyReorgRecord applies the added row to the table the writes are applied to.
+func (t *TableCommon) addPrimaryKeyReorgRecord(sctx table.MutateContext, h kv.Handle, r []types.Datum) error {
+	if !t.isPrimaryKeyReorgWritable() {
+		return nil
+	}
+	rt := t.pkReorgTable
+	row := make([]types.Datum, 0, len(rt.Columns)+1)
+	row = append(row, r[:len(rt.Columns)]...)
+	if !t.meta.PKIsHandle && !t.meta.IsCommonHandle && !rt.meta.PKIsHandle && !rt.meta.IsCommonHandle {
+		// Keep the _tidb_rowid, the extra value is used as the handle.
+		row = append(row, types.NewIntDatum(h.IntValue()))
+	}
+	_, err := rt.AddRecord(sctx, row)
+	return err
+}
+
+// removePrimaryKeyReorgRecord applies the removed row to the table the writes are applied to.
+func (t *TableCommon) removePrimaryKeyReorgRecord(sctx table.MutateContext, txn kv.Transaction, h kv.Handle, r []types.Datum) error {
+	if t.pkReorgTable == nil {
+		return nil
+	}
+	rh, err := t.primaryKeyReorgHandle(sctx, txn, h, r)
+	if err != nil || rh == nil {
+		return err
+	}
+	return t.pkReorgTable.RemoveRecord(sctx, rh, r[:len(t.pkReorgTable.Columns)])
+}
@@ -48,8 +48,10 @@ import (
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
 	"github.com/pingcap/tidb/pkg/util/generatedexpr"
 	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/mock"
 	"github.com/pingcap/tidb/pkg/util/rowcodec"
 	"github.com/pingcap/tidb/pkg/util/stringutil"
 	"github.com/pingcap/tidb/pkg/util/tableutil"
@@ -78,6 +80,10 @@ type TableCommon struct {
 	dependencyColumnOffsets         []int
 	Constraints                     []*table.Constraint
 	writableConstraints             []*table.Constraint
+	// nonPublicGenExprs is the expressions of the stored generated columns which are being added.
+	nonPublicGenExprs map[int64]expression.Expression
+	// pkReorgTable is the table the writes are applied to when the primary key is being changed.
+	pkReorgTable *TableCommon
 
 	// recordPrefix and indexPrefix are generated using physicalTableID.
 	recordPrefix kv.Key
@@ -172,10 +178,16 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 	}
 	var t TableCommon
 	initTableCommon(&t, tblInfo, tblInfo.ID, columns, allocs, constraints)
+	if t.nonPublicGenExprs, err = buildNonPublicGenExprs(tblInfo, columns); err != nil {
+		return nil, err
+	}
 	if tblInfo.GetPartitionInfo() == nil {
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
 		}
+		if err := initPrimaryKeyReorgTable(&t); err != nil {
+			return nil, err
+		}
 		if tblInfo.TableCacheStatusType != model.TableCacheStatusDisable {
 			return newCachedTable(&t)
 		}
@@ -196,6 +208,26 @@ func buildGeneratedExpr(tblInfo *model.TableInfo, genExpr string) (ast.ExprNode,
 	return expr, nil
 }
 
+// buildNonPublicGenExprs builds the expressions of the stored generated columns which are being added, their values
+// are calculated when writing the rows.
+func buildNonPublicGenExprs(tblInfo *model.TableInfo, cols []*table.Column) (map[int64]expression.Expression, error) {
+	var exprs map[int64]expression.Expression
+	for _, col := range cols {
+		if col.State == model.StatePublic || col.ChangeStateInfo != nil || !col.IsGenerated() || !col.GeneratedStored {
+			continue
+		}
+		expr, err := expression.ParseSimpleExpr(mock.NewContext(), col.GeneratedExprString, expression.WithTableInfo("", tblInfo))
+		if err != nil {
+			return nil, err
+		}
+		if exprs == nil {
+			exprs = make(map[int64]expression.Expression)
+		}
+		exprs[col.ID] = expr
+	}
+	return exprs, nil
+}
+
 // initTableCommon initializes a TableCommon struct.
 func initTableCommon(t *TableCommon, tblInfo *model.TableInfo, physicalTableID int64, cols []*table.Column, allocs autoid.Allocators, constraints []*table.Constraint) {
 	t.tableID = tblInfo.ID
@@ -411,6 +443,10 @@ func (t *TableCommon) RecordKey(h kv.Handle) kv.Key {
 // shouldAssert checks if the partition should be in consistent
 // state and can have assertion.
 func (t *TableCommon) shouldAssert(level variable.AssertionLevel) bool {
+	if t.meta.State != model.StatePublic {
+		// The table the writes are applied to when changing the primary key, the rows may not be copied yet.
+		return false
+	}
 	p := t.Meta().Partition
 	if p != nil {
 		// This disables asserting during Reorganize Partition.
@@ -433,10 +469,26 @@ func (t *TableCommon) shouldAssert(level variable.AssertionLevel) bool {
 	return true
 }
 
+// checkExchangeRejectWrites returns an error if the table, or the partition,
+// is being exchanged while its global indexes are rebuilt.
+func (t *TableCommon) checkExchangeRejectWrites() error {
+	ei := t.meta.ExchangePartitionInfo
+	if ei == nil || !ei.RejectWrites {
+		return nil
+	}
+	if t.meta.Partition == nil || t.physicalTableID == ei.ExchangePartitionDefID {
+		return dbterror.ErrInvalidDDLState.GenWithStack("table %s is being exchanged with global indexes, writes are rejected", t.meta.Name.O)
+	}
+	return nil
+}
+
 // UpdateRecord implements table.Table UpdateRecord interface.
 // `touched` means which columns are really modified, used for secondary indices.
 // Length of `oldData` and `newData` equals to length of `t.WritableCols()`.
 func (t *TableCommon) UpdateRecord(ctx context.Context, sctx table.MutateContext, h kv.Handle, oldData, newData []types.Datum, touched []bool) error {
+	if err := t.checkExchangeRejectWrites(); err != nil {
+		return err
+	}
 	txn, err := sctx.Txn(true)
 	if err != nil {
 		return err
@@ -516,8 +568,19 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx table.MutateContext
 				newData[col.Offset] = value
 				touched[col.Offset] = touched[col.DependencyColumnOffset]
 				checksumData = t.appendInChangeColForChecksum(sctx, h, checksumData, col.ToInfo(), &newData[col.DependencyColumnOffset], &value)
-			} else if needChecksum {
-				checksumData = t.appendNonPublicColForChecksum(sctx, h, checksumData, col.ToInfo(), &value)
+			} else {
+				v, ok, err := t.nonPublicColValue(sctx, col, newData, &oldData[col.Offset])
+				if err != nil {
+					return err
+				}
+				if ok {
+					value = v
+					newData[col.Offset] = value
+					touched[col.Offset] = true
+				}
+				if needChecksum {
+					checksumData = t.appendNonPublicColForChecksum(sctx, h, checksumData, col.ToInfo(), &value)
+				}
 			}
 		} else {
 			value = newData[col.Offset]
@@ -593,6 +656,18 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx table.MutateContext
 		return err
 	}
 
+	if err = t.writeMViewLogs(sctx, txn, h, oldData, mviewLogSignDelete); err != nil {
+		return err
+	}
+	if err = t.writeMViewLogs(sctx, txn, h, newData, mviewLogSignInsert); err != nil {
+		return err
+	}
+	if err = t.removePrimaryKeyReorgRecord(sctx, txn, h, oldData); err != nil {
+		return err
+	}
+	if err = t.addPrimaryKeyReorgRecord(sctx, h, newData); err != nil {
+		return err
+	}
 	if err = injectMutationError(t, txn, sh); err != nil {
 		return err
 	}
@@ -819,6 +894,9 @@ func checkTempTableSize(ctx table.MutateContext, tmpTable tableutil.TempTable, t
 
 // AddRecord implements table.Table AddRecord interface.
 func (t *TableCommon) AddRecord(sctx table.MutateContext, r []types.Datum, opts ...table.AddRecordOption) (recordID kv.Handle, err error) {
+	if err := t.checkExchangeRejectWrites(); err != nil {
+		return nil, err
+	}
 	txn, err := sctx.Txn(true)
 	if err != nil {
 		return nil, err
@@ -970,13 +1048,29 @@ func (t *TableCommon) AddRecord(sctx table.MutateContext, r []types.Datum, opts
 			if opt.IsUpdate {
 				// If `AddRecord` is called by an update, the default value should be handled the update.
 				value = r[col.Offset]
+				v, ok, err := t.nonPublicColValue(sctx, col, r, &value)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					value = v
+					r[col.Offset] = value
+				}
 			} else {
 				// If `AddRecord` is called by an insert and the col is in write only or write reorganization state, we must
-				// add it with its default value.
-				value, err = table.GetColOriginDefaultValue(sctx.GetExprCtx(), col.ToInfo())
+				// add it with its default value. But the auto-increment and stored generated column being added must
+				// have its real value, since the rows written here are not backfilled.
+				var ok bool
+				value, ok, err = t.nonPublicColValue(sctx, col, r, nil)
 				if err != nil {
 					return nil, err
 				}
+				if !ok {
+					value, err = table.GetColOriginDefaultValue(sctx.GetExprCtx(), col.ToInfo())
+					if err != nil {
+						return nil, err
+					}
+				}
 				// add value to `r` for dirty db in transaction.
 				// Otherwise when update will panic cause by get value of column in write only state from dirty db.
 				if col.Offset < len(r) {
@@ -1085,6 +1179,9 @@ func (t *TableCommon) AddRecord(sctx table.MutateContext, r []types.Datum, opts
 		return h, err
 	}
 
+	if err = t.writeMViewLogs(sctx, txn, recordID, r, mviewLogSignInsert); err != nil {
+		return nil, err
+	}
 	if err = injectMutationError(t, txn, sh); err != nil {
 		return nil, err
 	}
@@ -1106,6 +1203,11 @@ func (t *TableCommon) AddRecord(sctx table.MutateContext, r []types.Datum, opts
 		}
 	}
 
+	// It's done after the row and its binlog are written, since the write buffers are reused by the nested AddRecord.
+	if err = t.addPrimaryKeyReorgRecord(sctx, recordID, r); err != nil {
+		return nil, err
+	}
+
 	if sessVars.TxnCtx == nil {
 		return recordID, nil
 	}
@@ -1312,6 +1414,9 @@ func GetChangingColVal(ctx sessionctx.Context, cols []*table.Column, col *table.
 
 // RemoveRecord implements table.Table RemoveRecord interface.
 func (t *TableCommon) RemoveRecord(ctx table.MutateContext, h kv.Handle, r []types.Datum) error {
+	if err := t.checkExchangeRejectWrites(); err != nil {
+		return err
+	}
 	txn, err := ctx.Txn(true)
 	if err != nil {
 		return err
@@ -1357,6 +1462,12 @@ func (t *TableCommon) RemoveRecord(ctx table.MutateContext, h kv.Handle, r []typ
 
 	sessVars := ctx.GetSessionVars()
 	sc := sessVars.StmtCtx
+	if err = t.writeMViewLogs(ctx, txn, h, r, mviewLogSignDelete); err != nil {
+		return err
+	}
+	if err = t.removePrimaryKeyReorgRecord(ctx, txn, h, r); err != nil {
+		return err
+	}
 	if err = injectMutationError(t, txn, sh); err != nil {
 		return err
 	}
@@ -1664,6 +1775,57 @@ func tryDecodeColumnFromCommonHandle(col *table.Column, handle kv.Handle, pkIds
 	return types.Datum{}, nil
 }
 
+// nonPublicColValue calculates the value of the non-public auto-increment or stored generated column which is being
+// added. The filled old value of the auto-increment column is kept. It returns false if the column isn't this kind.
+func (t *TableCommon) nonPublicColValue(sctx table.MutateContext, col *table.Column, row []types.Datum, oldVal *types.Datum) (types.Datum, bool, error) {
+	if col.ChangeStateInfo != nil {
+		return types.Datum{}, false, nil
+	}
+	if expr, ok := t.nonPublicGenExprs[col.ID]; ok {
+		val, err := expr.Eval(sctx.GetExprCtx().GetEvalCtx(), chunk.MutRowFromDatums(row).ToRow())
+		if err != nil {
+			return types.Datum{}, false, err
+		}
+		val, err = table.CastColumnValue(sctx.GetSessionVars(), val, col.ColumnInfo, false, false)
+		return val, err == nil, err
+	}
+	if !mysql.HasAutoIncrementFlag(col.GetFlag()) {
+		return types.Datum{}, false, nil
+	}
+	if oldVal != nil && !isUnfilledAutoIncValue(*oldVal) {
+		return *oldVal, true, nil
+	}
+	alloc := t.Allocators(sctx).Get(autoid.AutoIncrementType)
+	if alloc == nil {
+		return types.Datum{}, false, autoid.ErrAutoincReadFailed
+	}
+	sessVars := sctx.GetSessionVars()
+	increment, offset := int64(sessVars.AutoIncrementIncrement), int64(sessVars.AutoIncrementOffset)
+	_, id, err := alloc.Alloc(context.Background(), 1, increment, offset)
+	if err != nil {
+		return types.Datum{}, false, err
+	}
+	var val types.Datum
+	val.SetAutoID(id, col.GetFlag())
+	val, err = table.CastColumnValue(sessVars, val, col.ColumnInfo, false, false)
+	return val, err == nil, err
+}
+
+// isUnfilledAutoIncValue checks whether the value of the auto-increment column is not allocated yet.
+func isUnfilledAutoIncValue(val types.Datum) bool {
+	switch val.Kind() {
+	case types.KindNull:
+		return true
+	case types.KindInt64:
+		return val.GetInt64() == 0
+	case types.KindUint64:
+		return val.GetUint64() == 0
+	case types.KindFloat32, types.KindFloat64:
+		return val.GetFloat64() == 0
+	}
+	return false
+}
+
 // GetColDefaultValue gets a column default value.
 // The defaultVals is used to avoid calculating the default value multiple times.
 func GetColDefaultValue(ctx sessionctx.Context, col *table.Column, defaultVals []types.Datum) (
@@ -30,6 +30,8 @@ import (
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/table/tables"
 	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/pingcap/tidb/pkg/util/memory"
 	"github.com/tikv/client-go/v2/tikv"
 )
 
@@ -38,6 +40,7 @@ type TemporaryTableDDL interface {
 	CreateLocalTemporaryTable(db *model.DBInfo, info *model.TableInfo) error
 	DropLocalTemporaryTable(schema model.CIStr, tblName model.CIStr) error
 	TruncateLocalTemporaryTable(schema model.CIStr, tblName model.CIStr) error
+	TruncateGlobalTemporaryTableData(tblInfo *model.TableInfo) error
 }
 
 // temporaryTableDDL implements temptable.TemporaryTableDDL
@@ -46,7 +49,7 @@ type temporaryTableDDL struct {
 }
 
 func (d *temporaryTableDDL) CreateLocalTemporaryTable(db *model.DBInfo, info *model.TableInfo) error {
-	if _, err := ensureSessionData(d.sctx); err != nil {
+	if _, err := EnsureSessionData(d.sctx); err != nil {
 		return err
 	}
 	info.DBID = db.ID
@@ -90,6 +93,13 @@ func (d *temporaryTableDDL) TruncateLocalTemporaryTable(schema model.CIStr, tblN
 	return d.clearTemporaryTableRecords(oldTblInfo.ID)
 }
 
+// TruncateGlobalTemporaryTableData removes the rows of a global temporary table with `ON COMMIT PRESERVE ROWS` kept
+// in the session. The rows in other sessions are not affected, so it doesn't change the table ID like a normal truncate.
+func (d *temporaryTableDDL) TruncateGlobalTemporaryTableData(tblInfo *model.TableInfo) error {
+	d.sctx.GetSessionVars().ResetPreservedTemporaryTable(tblInfo.ID)
+	return d.clearTemporaryTableRecords(tblInfo.ID)
+}
+
 func (d *temporaryTableDDL) clearTemporaryTableRecords(tblID int64) error {
 	sessionData := getSessionData(d.sctx)
 	if sessionData == nil {
@@ -103,23 +113,29 @@ func (d *temporaryTableDDL) clearTemporaryTableRecords(tblID int64) error {
 		return err
 	}
 
+	// The keys are collected before being deleted, because deleting a key spilled to disk adds it to the memory buffer
+	// under iteration.
+	var keys []kv.Key
 	for iter.Valid() {
 		key := iter.Key()
 		if !bytes.HasPrefix(key, tblPrefix) {
 			break
 		}
+		keys = append(keys, key.Clone())
 
-		err = sessionData.DeleteTableKey(tblID, key)
+		err = iter.Next()
 		if err != nil {
+			iter.Close()
 			return err
 		}
+	}
+	iter.Close()
 
-		err = iter.Next()
-		if err != nil {
+	for _, key := range keys {
+		if err = sessionData.DeleteTableKey(tblID, key); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
@@ -142,7 +158,8 @@ func getSessionData(sctx sessionctx.Context) variable.TemporaryTableData {
 	return sctx.GetSessionVars().TemporaryTableData
 }
 
-func ensureSessionData(sctx sessionctx.Context) (variable.TemporaryTableData, error) {
+// EnsureSessionData returns the committed data of temporary tables in the session, it's created if not exists.
+func EnsureSessionData(sctx sessionctx.Context) (variable.TemporaryTableData, error) {
 	sessVars := sctx.GetSessionVars()
 	if sessVars.TemporaryTableData == nil {
 		// Create this txn just for getting a MemBuffer. It's a little tricky
@@ -151,7 +168,12 @@ func ensureSessionData(sctx sessionctx.Context) (variable.TemporaryTableData, er
 			return nil, err
 		}
 
-		sessVars.TemporaryTableData = variable.NewTemporaryTableData(bufferTxn.GetMemBuffer())
+		memTracker := memory.NewTracker(memory.LabelForTemporaryTableData, -1)
+		memTracker.AttachTo(sessVars.MemTracker)
+		diskTracker := disk.NewTracker(memory.LabelForTemporaryTableData, -1)
+		diskTracker.AttachTo(sessVars.DiskTracker)
+		sessVars.TemporaryTableData = variable.NewTemporaryTableData(bufferTxn.GetMemBuffer(), memTracker,
+			newDiskData(sctx.GetStore(), diskTracker))
 	}
 
 	return sessVars.TemporaryTableData, nil
@@ -36,6 +36,9 @@ func createTestSuite(t *testing.T) (sessionctx.Context, *temporaryTableDDL) {
 
 	sctx := mock.NewContext()
 	sctx.Store = store
+	// The session trackers of the mock context are attached to themselves, detach them to track the temporary data.
+	sctx.GetSessionVars().MemTracker.Detach()
+	sctx.GetSessionVars().DiskTracker.Detach()
 	ddl := GetTemporaryTableDDL(sctx).(*temporaryTableDDL)
 	t.Cleanup(func() {
 		require.NoError(t, store.Close())
@@ -0,0 +1,234 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temptable
+
+import (
+	"context"
+	"os"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/config"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/store/driver/txn"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/tikv/client-go/v2/tikv"
+)
+
+const diskDataDirPattern = "temporary-table-data"
+
+var _ variable.TemporaryTableDiskData = &diskData{}
+
+// diskData implements variable.TemporaryTableDiskData. The data is stored in a pebble DB under the temporary storage
+// path, the DB is opened when the data is spilled for the first time.
+type diskData struct {
+	store       kv.Storage
+	diskTracker *disk.Tracker
+
+	dir string
+	db  *pebble.DB
+}
+
+func newDiskData(store kv.Storage, diskTracker *disk.Tracker) *diskData {
+	return &diskData{
+		store:       store,
+		diskTracker: diskTracker,
+	}
+}
+
+// Get implements the variable.TemporaryTableDiskData interface.
+func (d *diskData) Get(ctx context.Context, memBuffer kv.Retriever, k kv.Key) ([]byte, error) {
+	val, err := memBuffer.Get(ctx, k)
+	if d.db == nil || !kv.IsErrNotFound(err) {
+		return val, err
+	}
+
+	val, closer, err := d.db.Get(k)
+	if err == pebble.ErrNotFound {
+		return nil, kv.ErrNotExist
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer closer.Close()
+	return slices.Clone(val), nil
+}
+
+// Iter implements the variable.TemporaryTableDiskData interface.
+func (d *diskData) Iter(memBuffer kv.Retriever, k kv.Key, upperBound kv.Key) (kv.Iterator, error) {
+	memIter, err := memBuffer.Iter(k, upperBound)
+	if err != nil || d.db == nil {
+		return memIter, err
+	}
+
+	iter := d.db.NewIter(&pebble.IterOptions{LowerBound: k, UpperBound: upperBound})
+	iter.First()
+	return newUnionIter(memIter, newDiskIter(iter, false), false)
+}
+
+// IterReverse implements the variable.TemporaryTableDiskData interface.
+func (d *diskData) IterReverse(memBuffer kv.Retriever, k kv.Key, lowerBound kv.Key) (kv.Iterator, error) {
+	memIter, err := memBuffer.IterReverse(k, lowerBound)
+	if err != nil || d.db == nil {
+		return memIter, err
+	}
+
+	iter := d.db.NewIter(&pebble.IterOptions{LowerBound: lowerBound, UpperBound: k})
+	iter.Last()
+	return newUnionIter(memIter, newDiskIter(iter, true), true)
+}
+
+func newUnionIter(memIter, diskIter kv.Iterator, reverse bool) (kv.Iterator, error) {
+	iter, err := txn.NewUnionIter(memIter, diskIter, reverse)
+	if err != nil {
+		memIter.Close()
+		diskIter.Close()
+		return nil, err
+	}
+	return iter, nil
+}
+
+// Spill implements the variable.TemporaryTableDiskData interface.
+func (d *diskData) Spill(memBuffer kv.MemBuffer) (kv.MemBuffer, error) {
+	if d.db == nil {
+		if err := d.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create this txn just for getting a MemBuffer, the same as EnsureSessionData.
+	bufferTxn, err := d.store.Begin(tikv.WithStartTS(0))
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := memBuffer.Iter(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	batch := d.db.NewBatch()
+	defer batch.Close()
+	var size int64
+	for iter.Valid() {
+		key, value := iter.Key(), iter.Value()
+		if len(value) == 0 {
+			err = batch.Delete(key, nil)
+		} else {
+			err = batch.Set(key, value, nil)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		size += int64(len(key) + len(value))
+
+		if err = iter.Next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = batch.Commit(pebble.NoSync); err != nil {
+		return nil, errors.Trace(err)
+	}
+	d.diskTracker.Consume(size)
+	return bufferTxn.GetMemBuffer(), nil
+}
+
+func (d *diskData) open() (err error) {
+	tempDir := config.GetGlobalConfig().TempStoragePath
+	if err = os.MkdirAll(tempDir, 0750); err != nil {
+		return errors.Trace(err)
+	}
+	if d.dir, err = os.MkdirTemp(tempDir, diskDataDirPattern); err != nil {
+		return errors.Trace(err)
+	}
+	// The data lives only in the session, so there's no need to write WAL.
+	if d.db, err = pebble.Open(d.dir, &pebble.Options{DisableWAL: true}); err != nil {
+		_ = os.RemoveAll(d.dir)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Close implements the variable.TemporaryTableDiskData interface.
+func (d *diskData) Close() error {
+	if d.db == nil {
+		return nil
+	}
+
+	err := d.db.Close()
+	if rmErr := os.RemoveAll(d.dir); err == nil {
+		err = rmErr
+	}
+	d.db = nil
+	d.diskTracker.ReplaceBytesUsed(0)
+	return errors.Trace(err)
+}
+
+// diskIter implements kv.Iterator for the data on disk.
+type diskIter struct {
+	iter    *pebble.Iterator
+	reverse bool
+	key     kv.Key
+	value   []byte
+}
+
+func newDiskIter(iter *pebble.Iterator, reverse bool) *diskIter {
+	i := &diskIter{iter: iter, reverse: reverse}
+	i.update()
+	return i
+}
+
+// update copies the current entry, the slices returned by pebble are only valid until the next move.
+func (i *diskIter) update() {
+	if i.iter.Valid() {
+		i.key = slices.Clone(i.iter.Key())
+		i.value = slices.Clone(i.iter.Value())
+	}
+}
+
+// Valid implements the kv.Iterator interface.
+func (i *diskIter) Valid() bool {
+	return i.iter.Valid()
+}
+
+// Key implements the kv.Iterator interface.
+func (i *diskIter) Key() kv.Key {
+	return i.key
+}
+
+// Value implements the kv.Iterator interface.
+func (i *diskIter) Value() []byte {
+	return i.value
+}
+
+// Next implements the kv.Iterator interface.
+func (i *diskIter) Next() error {
+	if i.reverse {
+		i.iter.Prev()
+	} else {
+		i.iter.Next()
+	}
+	i.update()
+	return errors.Trace(i.iter.Error())
+}
+
+// Close implements the kv.Iterator interface.
+func (i *diskIter) Close() {
+	_ = i.iter.Close()
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temptable
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/store/mockstore"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/tikv"
+)
+
+func TestDiskData(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+
+	newMemBuffer := func() kv.MemBuffer {
+		bufferTxn, err := store.Begin(tikv.WithStartTS(0))
+		require.NoError(t, err)
+		return bufferTxn.GetMemBuffer()
+	}
+	iterEntries := func(iter kv.Iterator, err error) (entries []string) {
+		require.NoError(t, err)
+		defer iter.Close()
+		for ; iter.Valid(); require.NoError(t, iter.Next()) {
+			if len(iter.Value()) > 0 {
+				entries = append(entries, string(iter.Key())+"="+string(iter.Value()))
+			}
+		}
+		return entries
+	}
+
+	diskTracker := disk.NewTracker(memory.LabelForTemporaryTableData, -1)
+	d := newDiskData(store, diskTracker)
+	memBuffer := newMemBuffer()
+	require.NoError(t, memBuffer.Set(kv.Key("a"), []byte("1")))
+	require.NoError(t, memBuffer.Set(kv.Key("b"), []byte("2")))
+	require.NoError(t, memBuffer.Set(kv.Key("c"), []byte("3")))
+
+	memBuffer, err = d.Spill(memBuffer)
+	require.NoError(t, err)
+	require.Zero(t, memBuffer.Len())
+	require.Positive(t, diskTracker.BytesConsumed())
+	require.Empty(t, iterEntries(memBuffer.Iter(nil, nil)))
+	require.Equal(t, []string{"a=1", "b=2", "c=3"}, iterEntries(d.Iter(memBuffer, nil, nil)))
+
+	// The entries in memory override the ones on disk.
+	require.NoError(t, memBuffer.Set(kv.Key("b"), []byte("22")))
+	require.NoError(t, memBuffer.Delete(kv.Key("c")))
+	require.NoError(t, memBuffer.Set(kv.Key("d"), []byte("4")))
+	check := func() {
+		val, err := d.Get(context.Background(), memBuffer, kv.Key("a"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("1"), val)
+		val, err = d.Get(context.Background(), memBuffer, kv.Key("b"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("22"), val)
+		val, err = d.Get(context.Background(), memBuffer, kv.Key("c"))
+		require.True(t, len(val) == 0 || kv.IsErrNotFound(err))
+		_, err = d.Get(context.Background(), memBuffer, kv.Key("e"))
+		require.True(t, kv.IsErrNotFound(err))
+
+		require.Equal(t, []string{"a=1", "b=22", "d=4"}, iterEntries(d.Iter(memBuffer, nil, nil)))
+		require.Equal(t, []string{"b=22"}, iterEntries(d.Iter(memBuffer, kv.Key("b"), kv.Key("d"))))
+		require.Equal(t, []string{"d=4", "b=22", "a=1"}, iterEntries(d.IterReverse(memBuffer, nil, nil)))
+		require.Equal(t, []string{"b=22"}, iterEntries(d.IterReverse(memBuffer, kv.Key("d"), kv.Key("b"))))
+	}
+	check()
+	memBuffer, err = d.Spill(memBuffer)
+	require.NoError(t, err)
+	check()
+
+	dir := d.dir
+	require.NoError(t, d.Close())
+	require.Zero(t, diskTracker.BytesConsumed())
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -66,7 +67,8 @@ const (
 
 	// CommonHandleFlag is the flag used to decode the common handle in an unique index value.
 	CommonHandleFlag byte = 127
-	// PartitionIDFlag is the flag used to decode the partition ID in global index value.
+	// PartitionIDFlag is the flag used to decode the partition ID in global index value, and in the key of
+	// non-unique global index.
 	PartitionIDFlag byte = 126
 	// IndexVersionFlag is the flag used to decode the index's version info.
 	IndexVersionFlag byte = 125
@@ -186,6 +188,15 @@ func DecodeIndexKey(key kv.Key) (tableID int64, indexID int64, indexValues []str
 func DecodeValuesBytesToStrings(b []byte) ([]string, error) {
 	var datumValues []string
 	for len(b) > 0 {
+		if pid, remain := cutPartitionIDInIndexKey(b); pid != nil {
+			_, partitionID, err := codec.DecodeInt(pid)
+			if err != nil {
+				return nil, err
+			}
+			datumValues = append(datumValues, strconv.FormatInt(partitionID, 10))
+			b = remain
+			continue
+		}
 		remain, d, e := codec.DecodeOne(b)
 		if e != nil {
 			return nil, e
@@ -647,7 +658,8 @@ func Unflatten(datum types.Datum, ft *types.FieldType, loc *time.Location) (type
 		datum.SetFloat32(float32(datum.GetFloat64()))
 		return datum, nil
 	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
-		mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob:
+		mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob, mysql.TypeGeometry,
+		mysql.TypeTiDBVectorFloat32:
 		datum.SetString(datum.GetString(), ft.GetCollate())
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeYear, mysql.TypeInt24,
 		mysql.TypeLong, mysql.TypeLonglong, mysql.TypeDouble:
@@ -956,7 +968,16 @@ func DecodeIndexHandle(key, value []byte, colsLen int) (kv.Handle, error) {
 		return nil, errors.Trace(err)
 	}
 	if len(b) > 0 {
-		return decodeHandleInIndexKey(b)
+		pid, hb := cutPartitionIDInIndexKey(b)
+		h, err := decodeHandleInIndexKey(hb)
+		if err != nil || pid == nil {
+			return h, err
+		}
+		_, partitionID, err := codec.DecodeInt(pid)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return kv.NewPartitionHandle(partitionID, h), nil
 	} else if len(value) >= 8 {
 		return decodeHandleInIndexValue(value)
 	}
@@ -964,7 +985,16 @@ func DecodeIndexHandle(key, value []byte, colsLen int) (kv.Handle, error) {
 	return nil, errors.Errorf("no handle in index key: %v, value: %v", key, value)
 }
 
+// cutPartitionIDInIndexKey cuts the partition ID encoded before the handle in the key of a non-unique global index.
+func cutPartitionIDInIndexKey(keySuffix []byte) (pid []byte, handle []byte) {
+	if len(keySuffix) > 9 && keySuffix[0] == PartitionIDFlag {
+		return keySuffix[1:9], keySuffix[9:]
+	}
+	return nil, keySuffix
+}
+
 func decodeHandleInIndexKey(keySuffix []byte) (kv.Handle, error) {
+	_, keySuffix = cutPartitionIDInIndexKey(keySuffix)
 	remain, d, err := codec.DecodeOne(keySuffix)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1159,6 +1189,14 @@ func GenIndexKey(loc *time.Location, tblInfo *model.TableInfo, idxInfo *model.In
 		return nil, false, err
 	}
 	if !distinct && h != nil {
+		if ph, ok := h.(kv.PartitionHandle); ok {
+			// The handle is only unique in its partition, so the partition ID is encoded before it.
+			if idxInfo.Global && idxInfo.GlobalIndexVersion >= model.GlobalIndexVersionV1 {
+				key = append(key, PartitionIDFlag)
+				key = codec.EncodeInt(key, ph.PartitionID)
+			}
+			h = ph.Handle
+		}
 		if h.IsInt() {
 			// We choose the efficient path here instead of calling `codec.EncodeKey`
 			// because the int handle must be an int64, and it must be comparable.
@@ -1797,6 +1835,7 @@ func decodeIndexKvForClusteredIndexVersion1(key, value []byte, colsLen int, hdSt
 		handle, err = kv.NewCommonHandle(segs.CommonHandle)
 	} else {
 		// In non-unique index, decode handle in keySuffix.
+		_, keySuffix = cutPartitionIDInIndexKey(keySuffix)
 		handle, err = kv.NewCommonHandle(keySuffix)
 	}
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/terror"
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
@@ -626,6 +627,34 @@ func TestTempIndexKey(t *testing.T) {
 	require.Equal(t, iid, iid2)
 }
 
+func TestGlobalIndexKeyWithPartitionID(t *testing.T) {
+	tblInfo := &model.TableInfo{ID: 1, Columns: []*model.ColumnInfo{{ID: 1, Offset: 0, FieldType: *types.NewFieldType(mysql.TypeLonglong)}}}
+	idxInfo := &model.IndexInfo{ID: 2, Global: true, GlobalIndexVersion: model.GlobalIndexVersionV1, Columns: []*model.IndexColumn{{Offset: 0, Length: types.UnspecifiedLength}}}
+	values := []types.Datum{types.NewIntDatum(100)}
+	key1, distinct, err := GenIndexKey(time.UTC, tblInfo, idxInfo, tblInfo.ID, values, kv.NewPartitionHandle(3, kv.IntHandle(7)), nil)
+	require.NoError(t, err)
+	require.False(t, distinct)
+	// The same handle in another partition has a different key.
+	key2, _, err := GenIndexKey(time.UTC, tblInfo, idxInfo, tblInfo.ID, values, kv.NewPartitionHandle(4, kv.IntHandle(7)), nil)
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key2)
+
+	h, err := DecodeIndexHandle(key1, []byte{0}, 1)
+	require.NoError(t, err)
+	require.Equal(t, kv.NewPartitionHandle(3, kv.IntHandle(7)), h)
+	_, _, indexValues, err := DecodeIndexKey(key1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"100", "3", "7"}, indexValues)
+
+	// The legacy global index doesn't encode the partition ID.
+	idxInfo.GlobalIndexVersion = model.GlobalIndexVersionLegacy
+	key3, _, err := GenIndexKey(time.UTC, tblInfo, idxInfo, tblInfo.ID, values, kv.NewPartitionHandle(3, kv.IntHandle(7)), nil)
+	require.NoError(t, err)
+	h, err = DecodeIndexHandle(key3, []byte{0}, 1)
+	require.NoError(t, err)
+	require.Equal(t, kv.IntHandle(7), h)
+}
+
 func TestTempIndexValueCodec(t *testing.T) {
 	// Test encode temp index value.
 	encodedValue, err := codec.EncodeValue(stmtctx.NewStmtCtxWithTimeZone(time.UTC).TimeZone(), nil, types.NewIntDatum(1))
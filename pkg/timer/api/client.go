@@ -76,6 +76,13 @@ func WithSetEnable(enable bool) UpdateTimerOption {
 	}
 }
 
+// WithSetData indicates to set the timer's data.
+func WithSetData(data []byte) UpdateTimerOption {
+	return func(update *TimerUpdate) {
+		update.Data.Set(data)
+	}
+}
+
 // WithSetTimeZone sets the timezone of the timer
 func WithSetTimeZone(name string) UpdateTimerOption {
 	return func(update *TimerUpdate) {
@@ -239,6 +246,24 @@ func (c *defaultTimerClient) ManualTriggerEvent(ctx context.Context, timerID str
 }
 
 func (c *defaultTimerClient) CloseTimerEvent(ctx context.Context, timerID string, eventID string, opts ...UpdateTimerOption) error {
+	update, err := NewCloseEventUpdate(eventID, opts...)
+	if err != nil {
+		return err
+	}
+
+	if !update.Watermark.Present() {
+		timer, err := c.GetTimerByID(ctx, timerID)
+		if err != nil {
+			return err
+		}
+		update.Watermark.Set(timer.EventStart)
+	}
+	return c.store.Update(ctx, timerID, update)
+}
+
+// NewCloseEventUpdate creates the update to close the triggering event of a timer. Only the watermark and the summary
+// data can be set by opts, and the watermark is not set if it's absent in opts.
+func NewCloseEventUpdate(eventID string, opts ...UpdateTimerOption) (*TimerUpdate, error) {
 	update := &TimerUpdate{}
 	for _, opt := range opts {
 		opt(update)
@@ -246,12 +271,7 @@ func (c *defaultTimerClient) CloseTimerEvent(ctx context.Context, timerID string
 
 	fields := update.FieldsSet(unsafe.Pointer(&update.Watermark), unsafe.Pointer(&update.SummaryData))
 	if len(fields) > 0 {
-		return errors.Errorf("The field(s) [%s] are not allowed to update when close event", strings.Join(fields, ", "))
-	}
-
-	timer, err := c.GetTimerByID(ctx, timerID)
-	if err != nil {
-		return err
+		return nil, errors.Errorf("The field(s) [%s] are not allowed to update when close event", strings.Join(fields, ", "))
 	}
 
 	var zeroTime time.Time
@@ -260,11 +280,8 @@ func (c *defaultTimerClient) CloseTimerEvent(ctx context.Context, timerID string
 	update.EventID.Set("")
 	update.EventData.Set(nil)
 	update.EventStart.Set(zeroTime)
-	if !update.Watermark.Present() {
-		update.Watermark.Set(timer.EventStart)
-	}
 	update.EventExtra.Set(EventExtra{})
-	return c.store.Update(ctx, timerID, update)
+	return update, nil
 }
 
 func (c *defaultTimerClient) DeleteTimer(ctx context.Context, timerID string) (bool, error) {
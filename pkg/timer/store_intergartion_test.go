@@ -164,6 +164,12 @@ func runTimerStoreInsertAndGet(ctx context.Context, t *testing.T, store *api.Tim
 	require.NoError(t, err)
 	require.Equal(t, recordTpl, *record)
 
+	// key exists
+	dup := recordTpl.Clone()
+	dup.ID, dup.Version, dup.CreateTime = "", 0, time.Time{}
+	_, err = store.Create(ctx, dup)
+	require.True(t, errors.ErrorEqual(err, api.ErrTimerExists))
+
 	// key not exist
 	_, err = store.GetByKey(ctx, "n1", "noexist")
 	require.True(t, errors.ErrorEqual(err, api.ErrTimerNotExist))
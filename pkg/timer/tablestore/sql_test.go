@@ -499,6 +499,14 @@ func TestBuildUpdateCriteria(t *testing.T) {
 				"VERSION = VERSION + 1",
 			args: []any{"", "", "", []byte(nil), []byte(nil), json.RawMessage(`{"event":null,"manual":null,"tags":null}`)},
 		},
+		{
+			update: &api.TimerUpdate{
+				Data:   api.NewOptionalVal([]byte("data2")),
+				Enable: api.NewOptionalVal(true),
+			},
+			criteria: "ENABLE = %?, TIMER_DATA = %?, VERSION = VERSION + 1",
+			args:     []any{true, []byte("data2")},
+		},
 		{
 			update: &api.TimerUpdate{
 				CheckEventID: api.NewOptionalVal("ee"),
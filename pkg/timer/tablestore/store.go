@@ -103,6 +103,9 @@ func (s *tableTimerStoreCore) Create(ctx context.Context, record *api.TimerRecor
 	exec := sctx.GetSQLExecutor()
 	_, err = executeSQL(ctx, exec, sql, args...)
 	if err != nil {
+		if kv.ErrKeyExists.Equal(err) {
+			return "", errors.Trace(api.ErrTimerExists)
+		}
 		return "", err
 	}
 
@@ -251,51 +254,58 @@ func (s *tableTimerStoreCore) Update(ctx context.Context, timerID string, update
 
 	exec := sctx.GetSQLExecutor()
 	err = runInTxn(ctx, exec, func() error {
-		/* #nosec G202: SQL string concatenation */
-		getCheckColsSQL := fmt.Sprintf(
-			"SELECT EVENT_ID, VERSION, SCHED_POLICY_TYPE, SCHED_POLICY_EXPR FROM %s WHERE ID=%%?",
-			indentString(s.dbName, s.tblName),
-		)
+		return updateTimer(ctx, exec, s.dbName, s.tblName, timerID, update)
+	})
 
-		rows, err := executeSQL(ctx, exec, getCheckColsSQL, timerID)
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
 
-		if len(rows) == 0 {
-			return api.ErrTimerNotExist
-		}
+	s.notifier.Notify(api.WatchTimerEventUpdate, timerID)
+	return nil
+}
 
-		err = checkUpdateConstraints(
-			update,
-			rows[0].GetString(0),
-			rows[0].GetUint64(1),
-			api.SchedPolicyType(rows[0].GetString(2)),
-			rows[0].GetString(3),
-		)
+// UpdateTimerInTxn updates a timer in the current transaction of exec, so the update is committed or rolled back
+// together with the other statements in the transaction. The watchers of the timers are not notified of the update.
+func UpdateTimerInTxn(ctx context.Context, exec sqlexec.SQLExecutor, dbName, tblName, timerID string, update *api.TimerUpdate) error {
+	return updateTimer(ctx, exec, dbName, tblName, timerID, update)
+}
 
-		if err != nil {
-			return err
-		}
+func updateTimer(ctx context.Context, exec sqlexec.SQLExecutor, dbName, tblName, timerID string, update *api.TimerUpdate) error {
+	/* #nosec G202: SQL string concatenation */
+	getCheckColsSQL := fmt.Sprintf(
+		"SELECT EVENT_ID, VERSION, SCHED_POLICY_TYPE, SCHED_POLICY_EXPR FROM %s WHERE ID=%%? FOR UPDATE",
+		indentString(dbName, tblName),
+	)
 
-		updateSQL, args, err := buildUpdateTimerSQL(s.dbName, s.tblName, timerID, update)
-		if err != nil {
-			return err
-		}
+	rows, err := executeSQL(ctx, exec, getCheckColsSQL, timerID)
+	if err != nil {
+		return err
+	}
 
-		if _, err = executeSQL(ctx, exec, updateSQL, args...); err != nil {
-			return err
-		}
+	if len(rows) == 0 {
+		return api.ErrTimerNotExist
+	}
 
-		return nil
-	})
+	err = checkUpdateConstraints(
+		update,
+		rows[0].GetString(0),
+		rows[0].GetUint64(1),
+		api.SchedPolicyType(rows[0].GetString(2)),
+		rows[0].GetString(3),
+	)
 
 	if err != nil {
 		return err
 	}
 
-	s.notifier.Notify(api.WatchTimerEventUpdate, timerID)
-	return nil
+	updateSQL, args, err := buildUpdateTimerSQL(dbName, tblName, timerID, update)
+	if err != nil {
+		return err
+	}
+
+	_, err = executeSQL(ctx, exec, updateSQL, args...)
+	return err
 }
 
 func (s *tableTimerStoreCore) Delete(ctx context.Context, timerID string) (bool, error) {
@@ -107,6 +107,10 @@ type PhysicalTable struct {
 	KeyColumnTypes []*types.FieldType
 	// TimeColum is the time column used for TTL
 	TimeColumn *model.ColumnInfo
+	// TimeExpr is the time expression used for TTL instead of `TimeColumn` if it's not empty
+	TimeExpr string
+	// EpochUnit is the unit of the integer time column or expression. It's empty if the time is not an integer.
+	EpochUnit string
 }
 
 // NewBasePhysicalTable create a new PhysicalTable with specific timeColunm.
@@ -169,12 +173,21 @@ func NewPhysicalTable(schema model.CIStr, tbl *model.TableInfo, partition model.
 		return nil, errors.Errorf("table '%s.%s' is not a ttl table", schema, tbl.Name)
 	}
 
-	timeColumn := tbl.FindPublicColumnByName(ttlInfo.ColumnName.L)
-	if timeColumn == nil {
-		return nil, errors.Errorf("time column '%s' is not public in ttl table '%s.%s'", ttlInfo.ColumnName, schema, tbl.Name)
+	var timeColumn *model.ColumnInfo
+	if ttlInfo.Expr == "" {
+		timeColumn = tbl.FindPublicColumnByName(ttlInfo.ColumnName.L)
+		if timeColumn == nil {
+			return nil, errors.Errorf("time column '%s' is not public in ttl table '%s.%s'", ttlInfo.ColumnName, schema, tbl.Name)
+		}
 	}
 
-	return NewBasePhysicalTable(schema, tbl, partition, timeColumn)
+	t, err := NewBasePhysicalTable(schema, tbl, partition, timeColumn)
+	if err != nil {
+		return nil, err
+	}
+	t.TimeExpr = ttlInfo.Expr
+	t.EpochUnit = ttlInfo.EpochUnit
+	return t, nil
 }
 
 // ValidateKeyPrefix validates a key prefix
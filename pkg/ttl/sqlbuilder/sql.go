@@ -111,11 +111,21 @@ func (b *SQLBuilder) Build() (string, error) {
 
 // WriteSelect writes a select statement to select key columns without any condition
 func (b *SQLBuilder) WriteSelect() error {
+	return b.WriteSelectWithExtraColumns(nil)
+}
+
+// WriteSelectWithExtraColumns writes a select statement to select key columns followed by the extra columns without
+// any condition
+func (b *SQLBuilder) WriteSelectWithExtraColumns(extraCols []*model.ColumnInfo) error {
 	if b.state != writeBegin {
 		return errors.Errorf("invalid state: %v", b.state)
 	}
 	b.restoreCtx.WritePlain("SELECT LOW_PRIORITY SQL_NO_CACHE ")
 	b.writeColNames(b.tbl.KeyColumns, false)
+	if len(extraCols) > 0 {
+		b.restoreCtx.WritePlain(", ")
+		b.writeColNames(extraCols, false)
+	}
 	b.restoreCtx.WritePlain(" FROM ")
 	if err := b.writeTblName(); err != nil {
 		return err
@@ -179,15 +189,44 @@ func (b *SQLBuilder) WriteExpireCondition(expire time.Time) error {
 		return errors.Errorf("invalid state: %v", b.state)
 	}
 
-	b.writeColNames([]*model.ColumnInfo{b.tbl.TimeColumn}, false)
+	if b.tbl.TimeExpr != "" {
+		// the expression has been restored with quoted names when it's defined, so it can be written directly.
+		b.restoreCtx.WritePlain("(")
+		b.restoreCtx.WritePlain(b.tbl.TimeExpr)
+		b.restoreCtx.WritePlain(")")
+	} else {
+		b.writeColNames([]*model.ColumnInfo{b.tbl.TimeColumn}, false)
+	}
 	b.restoreCtx.WritePlain(" < ")
-	b.restoreCtx.WritePlain("FROM_UNIXTIME(")
-	b.restoreCtx.WritePlain(strconv.FormatInt(expire.Unix(), 10))
-	b.restoreCtx.WritePlain(")")
+	if b.tbl.EpochUnit != "" {
+		// compare the integer time with an integer constant directly to make it possible to use a range scan.
+		epoch, err := toEpoch(expire, b.tbl.EpochUnit)
+		if err != nil {
+			return err
+		}
+		b.restoreCtx.WritePlain(strconv.FormatInt(epoch, 10))
+	} else {
+		b.restoreCtx.WritePlain("FROM_UNIXTIME(")
+		b.restoreCtx.WritePlain(strconv.FormatInt(expire.Unix(), 10))
+		b.restoreCtx.WritePlain(")")
+	}
 	b.hasWriteExpireCond = true
 	return nil
 }
 
+func toEpoch(t time.Time, unit string) (int64, error) {
+	switch unit {
+	case "SECOND":
+		return t.Unix(), nil
+	case "MILLISECOND":
+		return t.UnixMilli(), nil
+	case "MICROSECOND":
+		return t.UnixMicro(), nil
+	default:
+		return 0, errors.Errorf("invalid epoch unit '%s'", unit)
+	}
+}
+
 // WriteInCondition writes an IN condition
 func (b *SQLBuilder) WriteInCondition(cols []*model.ColumnInfo, dps ...[]types.Datum) error {
 	switch b.state {
@@ -313,6 +352,7 @@ type ScanQueryGenerator struct {
 	limit         int
 	firstBuild    bool
 	exhausted     bool
+	extraCols     []*model.ColumnInfo
 }
 
 // NewScanQueryGenerator creates a new ScanQueryGenerator
@@ -373,6 +413,12 @@ func (g *ScanQueryGenerator) NextSQL(continueFromResult [][]types.Datum, nextLim
 	return g.buildSQL()
 }
 
+// SetExtraColumns sets the columns to select after the key columns. The results passed to `NextSQL` should still
+// start with the key columns.
+func (g *ScanQueryGenerator) SetExtraColumns(cols []*model.ColumnInfo) {
+	g.extraCols = cols
+}
+
 // IsExhausted returns whether the generator is exhausted
 func (g *ScanQueryGenerator) IsExhausted() bool {
 	return g.exhausted
@@ -409,7 +455,7 @@ func (g *ScanQueryGenerator) buildSQL() (string, error) {
 	}
 
 	b := NewSQLBuilder(g.tbl)
-	if err := b.WriteSelect(); err != nil {
+	if err := b.WriteSelectWithExtraColumns(g.extraCols); err != nil {
 		return "", err
 	}
 	if len(g.stack) > 0 {
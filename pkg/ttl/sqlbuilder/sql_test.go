@@ -453,6 +453,11 @@ func TestSQLBuilder(t *testing.T) {
 	must(b.WriteCommonCondition(t1.KeyColumns, ">", d("a1")))
 	mustBuild(b, "SELECT LOW_PRIORITY SQL_NO_CACHE `id` FROM `test`.`t1` WHERE `id` > 'a1'")
 
+	b = sqlbuilder.NewSQLBuilder(t2)
+	must(b.WriteSelectWithExtraColumns([]*model.ColumnInfo{t2.TimeColumn, {Name: model.NewCIStr("v")}}))
+	must(b.WriteCommonCondition(t2.KeyColumns[:1], ">", d("a1")))
+	mustBuild(b, "SELECT LOW_PRIORITY SQL_NO_CACHE `a`, `b`, `time`, `v` FROM `test2`.`t2` WHERE `a` > 'a1'")
+
 	b = sqlbuilder.NewSQLBuilder(t1)
 	must(b.WriteSelect())
 	must(b.WriteCommonCondition(t1.KeyColumns, ">", d("a1")))
@@ -891,6 +896,29 @@ func TestBuildDeleteSQL(t *testing.T) {
 		},
 	}
 
+	t3 := &cache.PhysicalTable{
+		Schema: model.NewCIStr("test"),
+		TableInfo: &model.TableInfo{
+			Name: model.NewCIStr("t3"),
+		},
+		KeyColumns: t1.KeyColumns,
+		TimeColumn: &model.ColumnInfo{
+			Name:      model.NewCIStr("created_at"),
+			FieldType: *types.NewFieldType(mysql.TypeLonglong),
+		},
+		EpochUnit: "MILLISECOND",
+	}
+
+	t4 := &cache.PhysicalTable{
+		Schema: model.NewCIStr("test"),
+		TableInfo: &model.TableInfo{
+			Name: model.NewCIStr("t4"),
+		},
+		KeyColumns: t1.KeyColumns,
+		TimeExpr:   "`created_at`/1000",
+		EpochUnit:  "SECOND",
+	}
+
 	cases := []struct {
 		tbl    *cache.PhysicalTable
 		expire time.Time
@@ -921,6 +949,18 @@ func TestBuildDeleteSQL(t *testing.T) {
 			rows:   [][]types.Datum{d(1, "a"), d(2, "b")},
 			sql:    "DELETE LOW_PRIORITY FROM `test2`.`t2` WHERE (`a`, `b`) IN ((1, 'a'), (2, 'b')) AND `time` < FROM_UNIXTIME(0) LIMIT 2",
 		},
+		{
+			tbl:    t3,
+			expire: time.UnixMilli(1700000000123).In(time.UTC),
+			rows:   [][]types.Datum{d(1), d(2)},
+			sql:    "DELETE LOW_PRIORITY FROM `test`.`t3` WHERE `id` IN (1, 2) AND `created_at` < 1700000000123 LIMIT 2",
+		},
+		{
+			tbl:    t4,
+			expire: time.UnixMilli(1700000000123).In(time.UTC),
+			rows:   [][]types.Datum{d(1)},
+			sql:    "DELETE LOW_PRIORITY FROM `test`.`t4` WHERE `id` IN (1) AND (`created_at`/1000) < 1700000000 LIMIT 1",
+		},
 	}
 
 	for _, c := range cases {
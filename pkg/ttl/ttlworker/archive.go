@@ -0,0 +1,310 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttlworker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/ttl/cache"
+	"github.com/pingcap/tidb/pkg/ttl/session"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// archiveWindowFormat is the format of the directory name of the archive files, which is the expire time of the job.
+const archiveWindowFormat = "20060102T150405Z"
+
+const insertArchiveFileTemplate = `INSERT INTO
+    mysql.tidb_ttl_archive_files (
+        job_id,
+        scan_id,
+        table_id,
+        parent_table_id,
+        table_schema,
+        table_name,
+        partition_name,
+        ttl_expire,
+        archive,
+        file_path,
+        columns,
+        row_count,
+        create_time
+    )
+VALUES
+    (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)`
+
+func insertArchiveFileSQL(task *cache.TTLTask, tbl *cache.PhysicalTable, filePath string, cols []*model.ColumnInfo,
+	rowCount int, now time.Time) (string, []any) {
+	var partitionName any
+	if tbl.Partition.O != "" {
+		partitionName = tbl.Partition.O
+	}
+
+	colNames := make([]string, 0, len(cols))
+	for _, col := range cols {
+		colNames = append(colNames, col.Name.O)
+	}
+
+	return insertArchiveFileTemplate, []any{
+		task.JobID,
+		task.ScanID,
+		tbl.ID,
+		tbl.TableInfo.ID,
+		tbl.Schema.O,
+		tbl.Name.O,
+		partitionName,
+		task.ExpireTime.Format(timeFormat),
+		ast.RedactURL(tbl.TTLInfo.Archive),
+		filePath,
+		strings.Join(colNames, ","),
+		rowCount,
+		now.Format(timeFormat),
+	}
+}
+
+// ttlArchiver exports the expired rows to the external storage set by `TTL_ARCHIVE` before they are deleted.
+// Every batch of the expired rows is written to a CSV or parquet file, according to `TTL_ARCHIVE_FORMAT`, under the directory `<schema>/<table>[/<partition>]/<expire>`,
+// and the file is recorded in `mysql.tidb_ttl_archive_files`, so that it can be imported again by `IMPORT INTO`.
+type ttlArchiver struct {
+	store storage.ExternalStorage
+	tbl   *cache.PhysicalTable
+	task  *cache.TTLTask
+	// cols are the columns exported to the files. Generated columns are skipped because they can't be imported.
+	cols []*model.ColumnInfo
+	// execID is unique for every execution of the scan task, so the files written by a retried task will not overwrite
+	// the previous ones, whose rows may have been deleted.
+	execID  string
+	seq     int
+	parquet bool
+}
+
+func newTTLArchiver(ctx context.Context, tbl *cache.PhysicalTable, task *cache.TTLTask) (*ttlArchiver, error) {
+	backend, err := storage.ParseBackend(tbl.TTLInfo.Archive, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	store, err := storage.NewWithDefaultOpt(ctx, backend)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cols := make([]*model.ColumnInfo, 0, len(tbl.Columns))
+	for _, col := range tbl.Columns {
+		if col.State == model.StatePublic && !col.IsGenerated() {
+			cols = append(cols, col)
+		}
+	}
+
+	return &ttlArchiver{
+		store:  store,
+		tbl:    tbl,
+		task:   task,
+		cols:   cols,
+		execID:  uuid.NewString(),
+		parquet: strings.EqualFold(tbl.TTLInfo.ArchiveFormat, "PARQUET"),
+	}, nil
+}
+
+// dir returns the directory of the archive files, which is partitioned by the table and the expire time of the job.
+func (a *ttlArchiver) dir() string {
+	elems := []string{a.tbl.Schema.O, a.tbl.Name.O}
+	if a.tbl.Partition.O != "" {
+		elems = append(elems, a.tbl.Partition.O)
+	}
+	elems = append(elems, a.task.ExpireTime.UTC().Format(archiveWindowFormat))
+	return path.Join(elems...)
+}
+
+// getDatumRows returns the values of the archived columns, which are selected after the key columns.
+func (a *ttlArchiver) getDatumRows(rows []chunk.Row) [][]types.Datum {
+	offset := len(a.tbl.KeyColumns)
+	datums := make([][]types.Datum, len(rows))
+	for i, row := range rows {
+		datums[i] = make([]types.Datum, len(a.cols))
+		for j, col := range a.cols {
+			datums[i][j] = row.GetDatum(offset+j, &col.FieldType)
+		}
+	}
+	return datums
+}
+
+// archive writes the rows to a new file and records it. It must succeed before the rows are deleted.
+func (a *ttlArchiver) archive(ctx context.Context, se session.Session, rows [][]types.Datum) error {
+	var buf bytes.Buffer
+	ext := "csv"
+	if a.parquet {
+		ext = "parquet"
+		if err := a.writeParquet(&buf, rows); err != nil {
+			return err
+		}
+	} else {
+		for _, row := range rows {
+			if err := writeArchiveRow(&buf, row); err != nil {
+				return err
+			}
+		}
+	}
+
+	filePath := path.Join(a.dir(), fmt.Sprintf("%s_%d_%s_%d.%s", a.task.JobID, a.task.ScanID, a.execID, a.seq, ext))
+	a.seq++
+	if err := a.store.WriteFile(ctx, filePath, buf.Bytes()); err != nil {
+		return errors.Trace(err)
+	}
+
+	sql, args := insertArchiveFileSQL(a.task, a.tbl, filePath, a.cols, len(rows), se.Now())
+	_, err := se.ExecuteSQL(ctx, sql, args...)
+	return err
+}
+
+// parquetSchema returns the metadata of the parquet columns, which are named after the archived columns, so the
+// files can be imported by `IMPORT INTO` with the column names.
+func (a *ttlArchiver) parquetSchema() []string {
+	md := make([]string, 0, len(a.cols))
+	used := make(map[string]struct{}, len(a.cols))
+	for i, col := range a.cols {
+		// ',' and '=' are the separators of the metadata.
+		name := strings.NewReplacer(",", "_", "=", "_").Replace(col.Name.O)
+		// the parquet writer identifies the columns by the variable names converted from the column names, which may
+		// be duplicated after the conversion.
+		for {
+			key := common.StringToVariableName(strings.ToLower(name))
+			if _, ok := used[key]; !ok {
+				used[key] = struct{}{}
+				break
+			}
+			name = fmt.Sprintf("%s_%d", name, i)
+		}
+		md = append(md, fmt.Sprintf("name=%s, type=%s", name, archiveParquetType(&col.FieldType)))
+	}
+	return md
+}
+
+// writeParquet writes the rows as a parquet file. The types which can't be represented by parquet exactly, such as
+// decimal and datetime, are written as their string formats, the same as `SELECT ... INTO OUTFILE`.
+func (a *ttlArchiver) writeParquet(buf *bytes.Buffer, rows [][]types.Datum) error {
+	pw, err := writer.NewCSVWriterFromWriter(a.parquetSchema(), buf, 1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, row := range rows {
+		values := make([]any, len(row))
+		for i, d := range row {
+			if d.IsNull() {
+				continue
+			}
+			switch a.cols[i].GetType() {
+			case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeYear, mysql.TypeLonglong:
+				if d.Kind() == types.KindUint64 {
+					values[i] = int64(d.GetUint64())
+				} else {
+					values[i] = d.GetInt64()
+				}
+			case mysql.TypeBit:
+				bit, err := d.GetBinaryLiteral().ToInt(types.StrictContext)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				values[i] = int64(bit)
+			case mysql.TypeFloat:
+				values[i] = d.GetFloat32()
+			case mysql.TypeDouble:
+				values[i] = d.GetFloat64()
+			default:
+				str, err := d.ToString()
+				if err != nil {
+					return errors.Trace(err)
+				}
+				values[i] = str
+			}
+		}
+		if err := pw.Write(values); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(pw.WriteStop())
+}
+
+// archiveParquetType returns the parquet type of the column.
+func archiveParquetType(tp *types.FieldType) string {
+	switch tp.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeYear:
+		return "INT64"
+	case mysql.TypeLonglong:
+		if mysql.HasUnsignedFlag(tp.GetFlag()) {
+			return "UINT_64"
+		}
+		return "INT64"
+	case mysql.TypeBit:
+		return "UINT_64"
+	case mysql.TypeFloat:
+		return "FLOAT"
+	case mysql.TypeDouble:
+		return "DOUBLE"
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if types.IsBinaryStr(tp) {
+			return "BYTE_ARRAY"
+		}
+		return "UTF8"
+	default:
+		return "UTF8"
+	}
+}
+
+func (a *ttlArchiver) close() {
+	a.store.Close()
+}
+
+// writeArchiveRow writes a row in the default CSV format of `IMPORT INTO`, in which the fields are enclosed by '"' and
+// escaped by '\', and NULL is written as `\N`.
+func writeArchiveRow(buf *bytes.Buffer, row []types.Datum) error {
+	for i, d := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if d.IsNull() {
+			buf.WriteString(`\N`)
+			continue
+		}
+		s, err := d.ToString()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('"')
+		for j := 0; j < len(s); j++ {
+			if s[j] == '"' || s[j] == '\\' {
+				buf.WriteByte('\\')
+			}
+			buf.WriteByte(s[j])
+		}
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('\n')
+	return nil
+}
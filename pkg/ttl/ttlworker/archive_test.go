@@ -0,0 +1,188 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttlworker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/ttl/cache"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteArchiveRow(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeArchiveRow(&buf, []types.Datum{
+		types.NewIntDatum(1),
+		types.NewStringDatum(`a"b\c`),
+		{},
+		types.NewStringDatum("line1\nline2"),
+	}))
+	require.Equal(t, "\"1\",\"a\\\"b\\\\c\",\\N,\"line1\nline2\"\n", buf.String())
+}
+
+func TestTTLArchiver(t *testing.T) {
+	dir := t.TempDir()
+	tbl := newMockTTLTbl(t, "t1")
+	tbl.Columns = append(tbl.Columns,
+		&model.ColumnInfo{
+			ID:        2,
+			Name:      model.NewCIStr("v"),
+			Offset:    1,
+			FieldType: *types.NewFieldType(mysql.TypeString),
+			State:     model.StatePublic,
+		},
+		&model.ColumnInfo{
+			ID:                  3,
+			Name:                model.NewCIStr("g"),
+			Offset:              2,
+			FieldType:           *types.NewFieldType(mysql.TypeLonglong),
+			State:               model.StatePublic,
+			GeneratedExprString: "1",
+		},
+	)
+	tbl.TTLInfo.Archive = "file://" + dir
+	task := &cache.TTLTask{
+		JobID:      "job1",
+		ScanID:     2,
+		ExpireTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	archiver, err := newTTLArchiver(context.Background(), tbl, task)
+	require.NoError(t, err)
+	defer archiver.close()
+	require.Equal(t, []string{"time", "v"}, []string{archiver.cols[0].Name.L, archiver.cols[1].Name.L})
+	require.Len(t, archiver.cols, 2)
+	require.Equal(t, "test/t1/20240102T030405Z", archiver.dir())
+
+	// the key column `_tidb_rowid` is selected before the archived columns
+	require.Len(t, tbl.KeyColumns, 1)
+	tm := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := newMockRows(t, types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeDatetime), types.NewFieldType(mysql.TypeString)).
+		Append(1, tm, "a").
+		Append(2, tm, `b"`).
+		Rows()
+
+	var executedSQL string
+	var executedArgs []any
+	s := newMockSession(t)
+	s.executeSQL = func(ctx context.Context, sql string, args ...any) ([]chunk.Row, error) {
+		executedSQL = sql
+		executedArgs = args
+		return nil, nil
+	}
+
+	datums := archiver.getDatumRows(rows)
+	require.Len(t, datums, 2)
+	require.NoError(t, archiver.archive(context.Background(), s, datums))
+	require.True(t, strings.HasPrefix(executedSQL, "INSERT INTO\n    mysql.tidb_ttl_archive_files"))
+
+	filePath := executedArgs[9].(string)
+	require.True(t, strings.HasPrefix(filePath, "test/t1/20240102T030405Z/job1_2_"))
+	require.Equal(t, "time,v", executedArgs[10])
+	require.Equal(t, 2, executedArgs[11])
+
+	content, err := os.ReadFile(filepath.Join(dir, filePath))
+	require.NoError(t, err)
+	require.Equal(t, "\"2023-01-01 00:00:00\",\"a\"\n\"2023-01-01 00:00:00\",\"b\\\"\"\n", string(content))
+}
+
+func TestTTLArchiverParquet(t *testing.T) {
+	dir := t.TempDir()
+	tbl := newMockTTLTbl(t, "t1")
+	tbl.Columns = append(tbl.Columns,
+		&model.ColumnInfo{
+			ID:        2,
+			Name:      model.NewCIStr("v"),
+			Offset:    1,
+			FieldType: *types.NewFieldType(mysql.TypeString),
+			State:     model.StatePublic,
+		},
+		&model.ColumnInfo{
+			ID:        3,
+			Name:      model.NewCIStr("i"),
+			Offset:    2,
+			FieldType: *types.NewFieldType(mysql.TypeLonglong),
+			State:     model.StatePublic,
+		},
+	)
+	tbl.TTLInfo.Archive = "file://" + dir
+	tbl.TTLInfo.ArchiveFormat = "PARQUET"
+	task := &cache.TTLTask{
+		JobID:      "job1",
+		ScanID:     2,
+		ExpireTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	archiver, err := newTTLArchiver(context.Background(), tbl, task)
+	require.NoError(t, err)
+	defer archiver.close()
+
+	tm := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := newMockRows(t, types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeDatetime),
+		types.NewFieldType(mysql.TypeString), types.NewFieldType(mysql.TypeLonglong)).
+		Append(1, tm, "a", 10).
+		Append(2, tm, nil, nil).
+		Rows()
+
+	var executedArgs []any
+	s := newMockSession(t)
+	s.executeSQL = func(ctx context.Context, sql string, args ...any) ([]chunk.Row, error) {
+		executedArgs = args
+		return nil, nil
+	}
+	require.NoError(t, archiver.archive(context.Background(), s, archiver.getDatumRows(rows)))
+
+	filePath := executedArgs[9].(string)
+	require.True(t, strings.HasSuffix(filePath, ".parquet"))
+	require.Equal(t, 2, executedArgs[11])
+
+	ctx := context.Background()
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	reader, err := store.Open(ctx, filePath, nil)
+	require.NoError(t, err)
+	parser, err := mydump.NewParquetParser(ctx, store, reader, filePath)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, parser.Close())
+	}()
+	require.Equal(t, []string{"time", "v", "i"}, parser.Columns())
+
+	require.NoError(t, parser.ReadRow())
+	row := parser.LastRow().Row
+	require.Equal(t, "2023-01-01 00:00:00", row[0].GetString())
+	require.Equal(t, "a", row[1].GetString())
+	require.Equal(t, int64(10), row[2].GetInt64())
+
+	require.NoError(t, parser.ReadRow())
+	row = parser.LastRow().Row
+	require.Equal(t, "2023-01-01 00:00:00", row[0].GetString())
+	require.True(t, row[1].IsNull())
+	require.True(t, row[2].IsNull())
+	require.ErrorIs(t, parser.ReadRow(), io.EOF)
+}
@@ -146,6 +146,16 @@ func (t *ttlScanTask) doScan(ctx context.Context, delCh chan<- *ttlDeleteTask, s
 		return t.result(err)
 	}
 
+	var archiver *ttlArchiver
+	if t.tbl.TTLInfo != nil && t.tbl.TTLInfo.Archive != "" {
+		// the whole expired rows are selected to be archived before they are deleted.
+		if archiver, err = newTTLArchiver(ctx, t.tbl, t.TTLTask); err != nil {
+			return t.result(err)
+		}
+		defer archiver.close()
+		generator.SetExtraColumns(archiver.cols)
+	}
+
 	retrySQL := ""
 	retryTimes := 0
 	var lastResult [][]types.Datum
@@ -212,6 +222,18 @@ func (t *ttlScanTask) doScan(ctx context.Context, delCh chan<- *ttlDeleteTask, s
 			continue
 		}
 
+		if archiver != nil {
+			// the rows are not deleted if they fail to be archived, and the task will fail so that they can be handled
+			// again in the next job.
+			if err = archiver.archive(ctx, rawSess, archiver.getDatumRows(rows)); err != nil {
+				logutil.BgLogger().Error("archive expired rows for ttl scan task failed",
+					zap.String("table", t.tbl.Schema.O+"."+t.tbl.Name.O),
+					zap.Error(err),
+				)
+				return t.result(err)
+			}
+		}
+
 		delTask := &ttlDeleteTask{
 			tbl:        t.tbl,
 			expire:     t.ExpireTime,
@@ -255,10 +255,26 @@ func validateTTLWork(ctx context.Context, s session.Session, tbl *cache.Physical
 		return errors.New("table TTL disabled")
 	}
 
-	if newTTLTbl.TimeColumn.Name.L != tbl.TimeColumn.Name.L {
+	if newTTLTbl.TimeExpr != tbl.TimeExpr {
+		return errors.New("time expression changed")
+	}
+
+	if tbl.TimeExpr == "" && newTTLTbl.TimeColumn.Name.L != tbl.TimeColumn.Name.L {
 		return errors.New("time column name changed")
 	}
 
+	if newTTLTbl.EpochUnit != tbl.EpochUnit {
+		return errors.New("time epoch unit changed")
+	}
+
+	if newTblInfo.TTLInfo.Archive != tbl.TTLInfo.Archive {
+		return errors.New("archive changed")
+	}
+
+	if newTblInfo.TTLInfo.ArchiveFormat != tbl.TTLInfo.ArchiveFormat {
+		return errors.New("archive format changed")
+	}
+
 	if newTblInfo.TTLInfo.IntervalExprStr != tbl.TTLInfo.IntervalExprStr ||
 		newTblInfo.TTLInfo.IntervalTimeUnit != tbl.TTLInfo.IntervalTimeUnit {
 		newExpireTime, err := newTTLTbl.EvalExpireTime(ctx, s, s.Now())
@@ -92,6 +92,10 @@ func newMockRows(t *testing.T, fieldTypes ...*types.FieldType) *mockRows {
 func (r *mockRows) Append(row ...any) *mockRows {
 	require.Equal(r.t, len(r.fieldTypes), len(row))
 	for i, ft := range r.fieldTypes {
+		if row[i] == nil {
+			r.AppendNull(i)
+			continue
+		}
 		tp := ft.GetType()
 		switch tp {
 		case mysql.TypeTimestamp, mysql.TypeDate, mysql.TypeDatetime:
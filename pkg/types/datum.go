@@ -937,6 +937,10 @@ func (d *Datum) ConvertTo(ctx Context, target *FieldType) (Datum, error) {
 		return d.convertToMysqlSet(ctx, target)
 	case mysql.TypeJSON:
 		return d.convertToMysqlJSON(target)
+	case mysql.TypeGeometry:
+		return d.convertToMysqlGeometry(target)
+	case mysql.TypeTiDBVectorFloat32:
+		return d.convertToVectorFloat32(target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -1688,6 +1692,38 @@ func (d *Datum) convertToMysqlSet(ctx Context, target *FieldType) (Datum, error)
 	return ret, err
 }
 
+func (d *Datum) convertToMysqlGeometry(target *FieldType) (ret Datum, err error) {
+	if d.k != KindString && d.k != KindBytes {
+		return ret, ErrCantCreateGeometryObject
+	}
+	// Only the values in the storage format, e.g. the results of ST_GeomFromText, can be stored.
+	g, err := DecodeGeometry(d.GetBytes())
+	if err != nil || !target.GetGeometryType().Accepts(g.Type) {
+		return ret, ErrCantCreateGeometryObject
+	}
+	ret.SetBytes(d.GetBytes())
+	return ret, nil
+}
+
+func (d *Datum) convertToVectorFloat32(target *FieldType) (ret Datum, err error) {
+	if d.k != KindString && d.k != KindBytes {
+		str, err := d.ToString()
+		if err != nil {
+			return ret, errors.Trace(err)
+		}
+		return ret, ErrInvalidVectorValue.GenWithStackByArgs(str)
+	}
+	v, err := ConvertToVectorFloat32(d.GetBytes())
+	if err != nil {
+		return ret, err
+	}
+	if target.GetFlen() != UnspecifiedLength && len(v) != target.GetFlen() {
+		return ret, ErrVectorDimensionMismatch.GenWithStackByArgs(len(v), target.GetFlen())
+	}
+	ret.SetBytes(EncodeVectorFloat32(v))
+	return ret, nil
+}
+
 func (d *Datum) convertToMysqlJSON(_ *FieldType) (ret Datum, err error) {
 	switch d.k {
 	case KindString, KindBytes:
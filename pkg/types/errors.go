@@ -94,4 +94,22 @@ var (
 	ErrPartitionColumnStatsMissing = dbterror.ClassTypes.NewStd(mysql.ErrPartitionColumnStatsMissing)
 	// ErrIncorrectDatetimeValue is returned when the input value is in wrong format for datetime.
 	ErrIncorrectDatetimeValue = dbterror.ClassTypes.NewStd(mysql.ErrIncorrectDatetimeValue)
+	// ErrCantCreateGeometryObject is returned when the value can't be stored in a spatial column.
+	ErrCantCreateGeometryObject = dbterror.ClassTypes.NewStd(mysql.ErrCantCreateGeometryObject)
+	// ErrGISInvalidData is returned when the geometry passed to a spatial function is invalid.
+	ErrGISInvalidData = dbterror.ClassTypes.NewStd(mysql.ErrGISInvalidData)
+	// ErrGISDifferentSRIDs is returned when the geometries passed to a spatial function have different SRIDs.
+	ErrGISDifferentSRIDs = dbterror.ClassTypes.NewStd(mysql.ErrGISDifferentSRIDs)
+	// ErrGISUnsupportedArgument is returned when a spatial function doesn't support the types of the geometries.
+	ErrGISUnsupportedArgument = dbterror.ClassTypes.NewStd(mysql.ErrGISUnsupportedArgument)
+	// ErrNonpositiveRadius is returned when the sphere radius passed to a spatial function is not positive.
+	ErrNonpositiveRadius = dbterror.ClassTypes.NewStd(mysql.ErrNonpositiveRadius)
+	// ErrLongitudeOutOfRange is returned when the longitude of a geographic point is out of range.
+	ErrLongitudeOutOfRange = dbterror.ClassTypes.NewStd(mysql.ErrLongitudeOutOfRange)
+	// ErrLatitudeOutOfRange is returned when the latitude of a geographic point is out of range.
+	ErrLatitudeOutOfRange = dbterror.ClassTypes.NewStd(mysql.ErrLatitudeOutOfRange)
+	// ErrInvalidVectorValue is returned when the value can't be converted to a vector.
+	ErrInvalidVectorValue = dbterror.ClassTypes.NewStd(mysql.ErrInvalidVectorValue)
+	// ErrVectorDimensionMismatch is returned when the dimensions of vectors don't match.
+	ErrVectorDimensionMismatch = dbterror.ClassTypes.NewStd(mysql.ErrVectorDimensionMismatch)
 )
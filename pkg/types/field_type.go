@@ -373,6 +373,17 @@ func DefaultCharsetForType(tp byte) (defaultCharset string, defaultCollation str
 // The result field type of the case expression is the merged type of the two when clause.
 // See https://github.com/mysql/mysql-server/blob/8.0/sql/field.cc#L1042
 func MergeFieldType(a byte, b byte) byte {
+	// VECTOR isn't a MySQL type, so it's not in the merge rules. It's merged as a binary string with other types.
+	if a == mysql.TypeTiDBVectorFloat32 || b == mysql.TypeTiDBVectorFloat32 {
+		if a == b || a == mysql.TypeNull || b == mysql.TypeNull {
+			return mysql.TypeTiDBVectorFloat32
+		}
+		if a == mysql.TypeTiDBVectorFloat32 {
+			a = mysql.TypeLongBlob
+		} else {
+			b = mysql.TypeLongBlob
+		}
+	}
 	ia := getFieldTypeIndex(a)
 	ib := getFieldTypeIndex(b)
 	return fieldTypeMergeRules[ia][ib]
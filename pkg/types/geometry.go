@@ -0,0 +1,320 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/pingcap/errors"
+	ast "github.com/pingcap/tidb/pkg/parser/types"
+)
+
+// GeometryType is the type of a spatial value.
+type GeometryType = ast.GeometryType
+
+const (
+	// GeometryTypeGeometry represents any type of spatial values.
+	GeometryTypeGeometry = ast.GeometryTypeGeometry
+	// GeometryTypePoint represents POINT.
+	GeometryTypePoint = ast.GeometryTypePoint
+	// GeometryTypeLineString represents LINESTRING.
+	GeometryTypeLineString = ast.GeometryTypeLineString
+	// GeometryTypePolygon represents POLYGON.
+	GeometryTypePolygon = ast.GeometryTypePolygon
+	// GeometryTypeMultiPoint represents MULTIPOINT.
+	GeometryTypeMultiPoint = ast.GeometryTypeMultiPoint
+	// GeometryTypeMultiLineString represents MULTILINESTRING.
+	GeometryTypeMultiLineString = ast.GeometryTypeMultiLineString
+	// GeometryTypeMultiPolygon represents MULTIPOLYGON.
+	GeometryTypeMultiPolygon = ast.GeometryTypeMultiPolygon
+	// GeometryTypeGeometryCollection represents GEOMETRYCOLLECTION.
+	GeometryTypeGeometryCollection = ast.GeometryTypeGeometryCollection
+)
+
+const (
+	geometrySRIDLen = 4
+	wkbHeaderLen    = 5
+	wkbPointLen     = 16
+	// maxGeometryDepth is the max nesting depth of geometry collections.
+	maxGeometryDepth = 64
+
+	// DefaultSphereRadius is the default radius of the sphere used by ST_Distance_Sphere, which is the mean radius of
+	// the Earth in meters.
+	DefaultSphereRadius = 6370986.0
+)
+
+var errInvalidGeometry = errors.New("invalid geometry")
+
+// GeoPoint is a point in a two-dimensional space. For geographic computations, X is the longitude and Y is the
+// latitude.
+type GeoPoint struct {
+	X float64
+	Y float64
+}
+
+// Geometry is a spatial value.
+//
+// The value is stored in the same format as MySQL: a 4-byte little-endian SRID followed by the WKB representation of
+// the geometry. All the computations are done in a Cartesian plane regardless of the SRID, except
+// ST_Distance_Sphere, which always treats X as the longitude and Y as the latitude.
+type Geometry struct {
+	SRID uint32
+	Type GeometryType
+	// Points are the points of a POINT or a LINESTRING.
+	Points []GeoPoint
+	// Rings are the rings of a POLYGON. The first ring is the exterior ring and the others are holes.
+	Rings [][]GeoPoint
+	// Geoms are the members of a MULTIPOINT, MULTILINESTRING, MULTIPOLYGON or GEOMETRYCOLLECTION.
+	Geoms []Geometry
+}
+
+// NewPointGeometry creates a POINT.
+func NewPointGeometry(x, y float64, srid uint32) Geometry {
+	return Geometry{SRID: srid, Type: GeometryTypePoint, Points: []GeoPoint{{X: x, Y: y}}}
+}
+
+// DecodeGeometry decodes a geometry from the storage format.
+func DecodeGeometry(data []byte) (Geometry, error) {
+	if len(data) < geometrySRIDLen+wkbHeaderLen {
+		return Geometry{}, errInvalidGeometry
+	}
+	return ParseGeometryWKB(data[geometrySRIDLen:], binary.LittleEndian.Uint32(data))
+}
+
+// ParseGeometryWKB parses a geometry from the WKB representation.
+func ParseGeometryWKB(wkb []byte, srid uint32) (Geometry, error) {
+	g, rest, err := parseWKB(wkb, 0)
+	if err != nil {
+		return Geometry{}, err
+	}
+	if len(rest) != 0 {
+		return Geometry{}, errInvalidGeometry
+	}
+	g.setSRID(srid)
+	return g, nil
+}
+
+func parseWKB(data []byte, depth int) (g Geometry, rest []byte, err error) {
+	if len(data) < wkbHeaderLen || depth > maxGeometryDepth {
+		return g, nil, errInvalidGeometry
+	}
+	var order binary.ByteOrder
+	switch data[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return g, nil, errInvalidGeometry
+	}
+	tp := order.Uint32(data[1:])
+	if tp < uint32(GeometryTypePoint) || tp > uint32(GeometryTypeGeometryCollection) {
+		return g, nil, errInvalidGeometry
+	}
+	g.Type = GeometryType(tp)
+	data = data[wkbHeaderLen:]
+	switch g.Type {
+	case GeometryTypePoint:
+		g.Points, data, err = parseWKBPoints(data, order, 1)
+	case GeometryTypeLineString:
+		g.Points, data, err = parseWKBPointList(data, order)
+	case GeometryTypePolygon:
+		var n uint32
+		if n, data, err = parseWKBCount(data, order); err != nil {
+			break
+		}
+		g.Rings = make([][]GeoPoint, 0, n)
+		for i := uint32(0); i < n && err == nil; i++ {
+			var ring []GeoPoint
+			ring, data, err = parseWKBPointList(data, order)
+			g.Rings = append(g.Rings, ring)
+		}
+	default:
+		var n uint32
+		if n, data, err = parseWKBCount(data, order); err != nil {
+			break
+		}
+		memberType := geometryMemberType(g.Type)
+		g.Geoms = make([]Geometry, 0, n)
+		for i := uint32(0); i < n && err == nil; i++ {
+			var member Geometry
+			member, data, err = parseWKB(data, depth+1)
+			if err == nil && memberType != GeometryTypeGeometry && member.Type != memberType {
+				err = errInvalidGeometry
+			}
+			g.Geoms = append(g.Geoms, member)
+		}
+	}
+	if err == nil {
+		err = g.validate()
+	}
+	return g, data, err
+}
+
+func parseWKBCount(data []byte, order binary.ByteOrder) (uint32, []byte, error) {
+	if len(data) < 4 {
+		return 0, nil, errInvalidGeometry
+	}
+	n := order.Uint32(data)
+	// Every member takes at least 4 bytes, so it's safe to preallocate the members if the count passes the check.
+	if uint64(n)*4 > uint64(len(data)-4) {
+		return 0, nil, errInvalidGeometry
+	}
+	return n, data[4:], nil
+}
+
+func parseWKBPointList(data []byte, order binary.ByteOrder) ([]GeoPoint, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, errInvalidGeometry
+	}
+	return parseWKBPoints(data[4:], order, order.Uint32(data))
+}
+
+func parseWKBPoints(data []byte, order binary.ByteOrder, n uint32) ([]GeoPoint, []byte, error) {
+	if uint64(n)*wkbPointLen > uint64(len(data)) {
+		return nil, nil, errInvalidGeometry
+	}
+	points := make([]GeoPoint, n)
+	for i := range points {
+		points[i].X = math.Float64frombits(order.Uint64(data))
+		points[i].Y = math.Float64frombits(order.Uint64(data[8:]))
+		data = data[wkbPointLen:]
+	}
+	return points, data, nil
+}
+
+// geometryMemberType returns the type of the members of a multi geometry, or GeometryTypeGeometry if the members
+// can be of any type.
+func geometryMemberType(tp GeometryType) GeometryType {
+	switch tp {
+	case GeometryTypeMultiPoint:
+		return GeometryTypePoint
+	case GeometryTypeMultiLineString:
+		return GeometryTypeLineString
+	case GeometryTypeMultiPolygon:
+		return GeometryTypePolygon
+	}
+	return GeometryTypeGeometry
+}
+
+func (g *Geometry) setSRID(srid uint32) {
+	g.SRID = srid
+	for i := range g.Geoms {
+		g.Geoms[i].setSRID(srid)
+	}
+}
+
+// validate checks the constraints which can't be checked by the grammar of WKT and WKB.
+func (g *Geometry) validate() error {
+	for _, p := range g.Points {
+		if math.IsNaN(p.X) || math.IsInf(p.X, 0) || math.IsNaN(p.Y) || math.IsInf(p.Y, 0) {
+			return errInvalidGeometry
+		}
+	}
+	switch g.Type {
+	case GeometryTypePoint:
+		if len(g.Points) != 1 {
+			return errInvalidGeometry
+		}
+	case GeometryTypeLineString:
+		if len(g.Points) < 2 {
+			return errInvalidGeometry
+		}
+	case GeometryTypePolygon:
+		if len(g.Rings) == 0 {
+			return errInvalidGeometry
+		}
+		for _, ring := range g.Rings {
+			if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+				return errInvalidGeometry
+			}
+			ring := Geometry{Type: GeometryTypeLineString, Points: ring}
+			if err := ring.validate(); err != nil {
+				return err
+			}
+		}
+	case GeometryTypeMultiPoint, GeometryTypeMultiLineString, GeometryTypeMultiPolygon:
+		if len(g.Geoms) == 0 {
+			return errInvalidGeometry
+		}
+	}
+	return nil
+}
+
+// Encode encodes the geometry to the storage format.
+func (g Geometry) Encode() []byte {
+	buf := make([]byte, geometrySRIDLen, geometrySRIDLen+g.wkbLen())
+	binary.LittleEndian.PutUint32(buf, g.SRID)
+	return g.appendWKB(buf)
+}
+
+// WKB returns the WKB representation of the geometry.
+func (g Geometry) WKB() []byte {
+	return g.appendWKB(make([]byte, 0, g.wkbLen()))
+}
+
+func (g Geometry) wkbLen() int {
+	l := wkbHeaderLen
+	switch g.Type {
+	case GeometryTypePoint:
+		l += wkbPointLen
+	case GeometryTypeLineString:
+		l += 4 + len(g.Points)*wkbPointLen
+	case GeometryTypePolygon:
+		l += 4
+		for _, ring := range g.Rings {
+			l += 4 + len(ring)*wkbPointLen
+		}
+	default:
+		l += 4
+		for _, member := range g.Geoms {
+			l += member.wkbLen()
+		}
+	}
+	return l
+}
+
+func (g Geometry) appendWKB(buf []byte) []byte {
+	buf = append(buf, 1)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(g.Type))
+	switch g.Type {
+	case GeometryTypePoint:
+		buf = appendWKBPoints(buf, g.Points)
+	case GeometryTypeLineString:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.Points)))
+		buf = appendWKBPoints(buf, g.Points)
+	case GeometryTypePolygon:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.Rings)))
+		for _, ring := range g.Rings {
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(ring)))
+			buf = appendWKBPoints(buf, ring)
+		}
+	default:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.Geoms)))
+		for _, member := range g.Geoms {
+			buf = member.appendWKB(buf)
+		}
+	}
+	return buf
+}
+
+func appendWKBPoints(buf []byte, points []GeoPoint) []byte {
+	for _, p := range points {
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.X))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.Y))
+	}
+	return buf
+}
@@ -0,0 +1,299 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+)
+
+// geoLocation is the location of a point relative to a geometry.
+type geoLocation int
+
+const (
+	geoExterior geoLocation = iota
+	geoBoundary
+	geoInterior
+)
+
+// Dimension returns the topological dimension of the geometry: 0 for points, 1 for curves and 2 for surfaces. For
+// collections, it's the max dimension of the members, or -1 if the collection is empty.
+func (g Geometry) Dimension() int {
+	switch g.Type {
+	case GeometryTypePoint, GeometryTypeMultiPoint:
+		return 0
+	case GeometryTypeLineString, GeometryTypeMultiLineString:
+		return 1
+	case GeometryTypePolygon, GeometryTypeMultiPolygon:
+		return 2
+	}
+	dim := -1
+	for _, member := range g.Geoms {
+		dim = max(dim, member.Dimension())
+	}
+	return dim
+}
+
+// components returns the points, linestrings and polygons which make up the geometry.
+func (g Geometry) components() []Geometry {
+	switch g.Type {
+	case GeometryTypePoint, GeometryTypeLineString, GeometryTypePolygon:
+		return []Geometry{g}
+	}
+	var components []Geometry
+	for _, member := range g.Geoms {
+		components = append(components, member.components()...)
+	}
+	return components
+}
+
+// Contains checks whether the other geometry is completely inside the geometry, that is, no points of other lie in
+// the exterior of the geometry, and at least one point of the interior of other lies in the interior of the
+// geometry. It returns false for ok if the types of the geometries are not supported.
+func (g Geometry) Contains(other Geometry) (contains bool, ok bool) {
+	if g.Type == GeometryTypeGeometryCollection {
+		return false, false
+	}
+	components := other.components()
+	if len(components) == 0 {
+		return false, true
+	}
+	for _, c := range components {
+		if !g.containsComponent(c) {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func (g Geometry) containsComponent(c Geometry) bool {
+	if c.Dimension() > g.Dimension() {
+		return false
+	}
+	interior := false
+	check := func(p GeoPoint) bool {
+		loc := g.locate(p)
+		interior = interior || loc == geoInterior
+		return loc != geoExterior
+	}
+	for _, p := range c.Points {
+		if !check(p) {
+			return false
+		}
+	}
+	for _, ring := range c.edgeLists() {
+		for i := 1; i < len(ring); i++ {
+			if !check(midPoint(ring[i-1], ring[i])) {
+				return false
+			}
+			if g.Dimension() == 2 && g.crossesBoundary(ring[i-1], ring[i]) {
+				return false
+			}
+		}
+	}
+	if c.Type != GeometryTypePolygon {
+		return interior
+	}
+	// The holes of the geometry mustn't be inside the polygon.
+	for _, polygon := range g.components() {
+		for _, hole := range polygon.Rings[1:] {
+			if c.locate(hole[0]) == geoInterior {
+				return false
+			}
+		}
+	}
+	if interior {
+		return true
+	}
+	// All the vertices of the polygon are on the boundary of the geometry, e.g. the polygon equals the geometry, so
+	// check a point in the interior of the polygon.
+	if p, found := c.interiorPoint(); found {
+		return g.locate(p) != geoExterior
+	}
+	return false
+}
+
+// edgeLists returns the point lists whose consecutive points form the edges of a linestring or a polygon.
+func (g Geometry) edgeLists() [][]GeoPoint {
+	switch g.Type {
+	case GeometryTypeLineString:
+		return [][]GeoPoint{g.Points}
+	case GeometryTypePolygon:
+		return g.Rings
+	}
+	return nil
+}
+
+// interiorPoint finds a point in the interior of a polygon.
+func (g Geometry) interiorPoint() (GeoPoint, bool) {
+	ring := g.Rings[0]
+	for i := 1; i < len(ring); i++ {
+		for j := i + 1; j < len(ring); j++ {
+			p := midPoint(ring[i], ring[j])
+			if g.locate(p) == geoInterior {
+				return p, true
+			}
+		}
+	}
+	return GeoPoint{}, false
+}
+
+// crossesBoundary checks whether the segment crosses any edge of the surfaces of the geometry.
+func (g Geometry) crossesBoundary(p1, p2 GeoPoint) bool {
+	for _, polygon := range g.components() {
+		for _, ring := range polygon.Rings {
+			for i := 1; i < len(ring); i++ {
+				if segmentsCross(p1, p2, ring[i-1], ring[i]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// locate returns the location of the point relative to the geometry.
+func (g Geometry) locate(p GeoPoint) geoLocation {
+	switch g.Type {
+	case GeometryTypePoint:
+		if g.Points[0] == p {
+			return geoInterior
+		}
+		return geoExterior
+	case GeometryTypeLineString:
+		for i := 1; i < len(g.Points); i++ {
+			if onSegment(g.Points[i-1], g.Points[i], p) {
+				last := len(g.Points) - 1
+				if g.Points[0] != g.Points[last] && (p == g.Points[0] || p == g.Points[last]) {
+					return geoBoundary
+				}
+				return geoInterior
+			}
+		}
+		return geoExterior
+	case GeometryTypePolygon:
+		loc := locateInRing(p, g.Rings[0])
+		if loc != geoInterior {
+			return loc
+		}
+		for _, hole := range g.Rings[1:] {
+			switch locateInRing(p, hole) {
+			case geoInterior:
+				return geoExterior
+			case geoBoundary:
+				return geoBoundary
+			}
+		}
+		return geoInterior
+	}
+	loc := geoExterior
+	for _, member := range g.Geoms {
+		loc = max(loc, member.locate(p))
+		if loc == geoInterior {
+			break
+		}
+	}
+	return loc
+}
+
+// locateInRing returns the location of the point relative to the area enclosed by the ring.
+func locateInRing(p GeoPoint, ring []GeoPoint) geoLocation {
+	inside := false
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if onSegment(a, b, p) {
+			return geoBoundary
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	if inside {
+		return geoInterior
+	}
+	return geoExterior
+}
+
+func midPoint(a, b GeoPoint) GeoPoint {
+	return GeoPoint{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+// orientation returns a positive value if a, b and c are in counterclockwise order, a negative value if they are in
+// clockwise order, and 0 if they are collinear.
+func orientation(a, b, c GeoPoint) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func onSegment(a, b, p GeoPoint) bool {
+	return orientation(a, b, p) == 0 &&
+		p.X >= min(a.X, b.X) && p.X <= max(a.X, b.X) && p.Y >= min(a.Y, b.Y) && p.Y <= max(a.Y, b.Y)
+}
+
+// segmentsCross checks whether the two segments intersect at a single point which is in the interior of both.
+func segmentsCross(p1, p2, q1, q2 GeoPoint) bool {
+	return orientation(p1, p2, q1)*orientation(p1, p2, q2) < 0 && orientation(q1, q2, p1)*orientation(q1, q2, p2) < 0
+}
+
+// DistanceSphere returns the minimum spherical distance between two points or multipoints on a sphere with the
+// radius. X is the longitude and Y is the latitude of the points.
+func (g Geometry) DistanceSphere(other Geometry, radius float64) (float64, error) {
+	const fn = "st_distance_sphere"
+	if !g.isPuntal() || !other.isPuntal() {
+		return 0, ErrGISUnsupportedArgument.GenWithStackByArgs(fn)
+	}
+	if radius <= 0 {
+		return 0, ErrNonpositiveRadius.GenWithStackByArgs(fn)
+	}
+	points, otherPoints := g.puntalPoints(), other.puntalPoints()
+	for _, ps := range [][]GeoPoint{points, otherPoints} {
+		for _, p := range ps {
+			if p.X <= -180 || p.X > 180 {
+				return 0, ErrLongitudeOutOfRange.GenWithStackByArgs(p.X, fn, -180.0, 180.0)
+			}
+			if p.Y < -90 || p.Y > 90 {
+				return 0, ErrLatitudeOutOfRange.GenWithStackByArgs(p.Y, fn, -90.0, 90.0)
+			}
+		}
+	}
+	distance := math.Inf(1)
+	for _, p := range points {
+		for _, q := range otherPoints {
+			distance = math.Min(distance, haversine(p, q, radius))
+		}
+	}
+	return distance, nil
+}
+
+func (g Geometry) isPuntal() bool {
+	return g.Type == GeometryTypePoint || g.Type == GeometryTypeMultiPoint
+}
+
+func (g Geometry) puntalPoints() []GeoPoint {
+	if g.Type == GeometryTypePoint {
+		return g.Points
+	}
+	points := make([]GeoPoint, 0, len(g.Geoms))
+	for _, member := range g.Geoms {
+		points = append(points, member.Points[0])
+	}
+	return points
+}
+
+func haversine(p, q GeoPoint, radius float64) float64 {
+	const toRadians = math.Pi / 180
+	lat1, lat2 := p.Y*toRadians, q.Y*toRadians
+	dLat, dLon := (q.Y-p.Y)*toRadians, (q.X-p.X)*toRadians
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * radius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,224 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeometryWKT(t *testing.T) {
+	tests := []struct {
+		wkt      string
+		expected string
+		tp       GeometryType
+	}{
+		{"POINT(1 2)", "POINT(1 2)", GeometryTypePoint},
+		{" point ( -1.5  2e3 ) ", "POINT(-1.5 2000)", GeometryTypePoint},
+		{"LINESTRING(0 0,1 1,2 0)", "LINESTRING(0 0,1 1,2 0)", GeometryTypeLineString},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))", "POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))", GeometryTypePolygon},
+		{"MULTIPOINT(0 0,1 1)", "MULTIPOINT((0 0),(1 1))", GeometryTypeMultiPoint},
+		{"MULTIPOINT((0 0),(1 1))", "MULTIPOINT((0 0),(1 1))", GeometryTypeMultiPoint},
+		{"MULTILINESTRING((0 0,1 1),(2 2,3 3))", "MULTILINESTRING((0 0,1 1),(2 2,3 3))", GeometryTypeMultiLineString},
+		{"MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((2 2,3 2,3 3,2 2)))", "MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((2 2,3 2,3 3,2 2)))", GeometryTypeMultiPolygon},
+		{"GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))", "GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))", GeometryTypeGeometryCollection},
+		{"GEOMCOLLECTION()", "GEOMETRYCOLLECTION EMPTY", GeometryTypeGeometryCollection},
+		{"GEOMETRYCOLLECTION EMPTY", "GEOMETRYCOLLECTION EMPTY", GeometryTypeGeometryCollection},
+	}
+	for _, tt := range tests {
+		g, err := ParseGeometryWKT(tt.wkt, 4326)
+		require.NoError(t, err, tt.wkt)
+		require.Equal(t, tt.tp, g.Type, tt.wkt)
+		require.Equal(t, tt.expected, g.WKT(), tt.wkt)
+
+		// The storage format and WKB can be decoded to the same geometry.
+		decoded, err := DecodeGeometry(g.Encode())
+		require.NoError(t, err, tt.wkt)
+		require.Equal(t, uint32(4326), decoded.SRID)
+		require.Equal(t, tt.expected, decoded.WKT())
+		decoded, err = ParseGeometryWKB(g.WKB(), 0)
+		require.NoError(t, err, tt.wkt)
+		require.Equal(t, tt.expected, decoded.WKT())
+	}
+
+	invalid := []string{
+		"",
+		"POINT",
+		"POINT()",
+		"POINT(1)",
+		"POINT(1 2 3)",
+		"POINT(1 2",
+		"POINT(1 2) x",
+		"POINT(a b)",
+		"CIRCLE(1 2)",
+		"LINESTRING(0 0)",
+		"POLYGON((0 0,1 0,1 1))",
+		"POLYGON((0 0,1 0,1 1,0 1))",
+		"MULTIPOINT()",
+		"GEOMETRYCOLLECTION(POINT(1 1),)",
+	}
+	for _, wkt := range invalid {
+		_, err := ParseGeometryWKT(wkt, 0)
+		require.Error(t, err, wkt)
+	}
+}
+
+func TestGeometryWKB(t *testing.T) {
+	// POINT(1 2) in big-endian WKB.
+	wkb, err := hex.DecodeString("00000000013FF00000000000004000000000000000")
+	require.NoError(t, err)
+	g, err := ParseGeometryWKB(wkb, 0)
+	require.NoError(t, err)
+	require.Equal(t, "POINT(1 2)", g.WKT())
+	// The WKB is always encoded in little-endian.
+	require.Equal(t, "0101000000000000000000f03f0000000000000040", hex.EncodeToString(g.WKB()))
+	require.Equal(t, "e6100000"+hex.EncodeToString(g.WKB()), hex.EncodeToString(NewPointGeometry(1, 2, 4326).Encode()))
+
+	for _, data := range []string{
+		"",
+		"0101000000",
+		"0201000000000000000000F03F0000000000000040",
+		"0109000000000000000000F03F0000000000000040",
+		"0101000000000000000000F03F000000000000004000",
+		// A MULTIPOINT whose member is a LINESTRING.
+		"010400000001000000010200000002000000000000000000000000000000000000000000000000000000000000000000F03F",
+		// A LINESTRING with a huge number of points.
+		"0102000000FFFFFFFF",
+	} {
+		wkb, err := hex.DecodeString(data)
+		require.NoError(t, err)
+		_, err = ParseGeometryWKB(wkb, 0)
+		require.Error(t, err, data)
+	}
+}
+
+func TestGeometryGeoJSON(t *testing.T) {
+	tests := []struct {
+		wkt      string
+		digits   int
+		expected string
+	}{
+		{"POINT(1.2345 2)", 20, `{"coordinates": [1.2345, 2], "type": "Point"}`},
+		{"POINT(1.2345 2)", 2, `{"coordinates": [1.23, 2], "type": "Point"}`},
+		{"POLYGON((0 0,1 0,1 1,0 0))", 20, `{"coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]], "type": "Polygon"}`},
+		{"MULTIPOINT(0 0,1 1)", 20, `{"coordinates": [[0, 0], [1, 1]], "type": "MultiPoint"}`},
+		{"GEOMETRYCOLLECTION(POINT(1 1))", 20, `{"geometries": [{"coordinates": [1, 1], "type": "Point"}], "type": "GeometryCollection"}`},
+	}
+	for _, tt := range tests {
+		g, err := ParseGeometryWKT(tt.wkt, 0)
+		require.NoError(t, err)
+		j, err := g.GeoJSON(tt.digits)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, j.String(), tt.wkt)
+	}
+}
+
+func TestGeometryContains(t *testing.T) {
+	tests := []struct {
+		g1       string
+		g2       string
+		expected bool
+	}{
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "POINT(1 1)", true},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "POINT(0 1)", false},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "POINT(5 1)", false},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 2,1 1))", "POINT(1.5 1.5)", false},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 2,1 1))", "POINT(3 3)", true},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "LINESTRING(0 0,4 4)", true},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "LINESTRING(0 0,4 0)", false},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "LINESTRING(1 1,5 5)", false},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "POLYGON((0 0,4 0,4 4,0 4,0 0))", true},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "POLYGON((1 1,2 1,2 2,1 1))", true},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 2,1 1))", "POLYGON((0.5 0.5,3 0.5,3 3,0.5 3,0.5 0.5))", false},
+		// A concave polygon doesn't contain the segment across the notch.
+		{"POLYGON((0 0,4 0,4 4,2 1,0 4,0 0))", "LINESTRING(1 3,3 3)", false},
+		{"MULTIPOLYGON(((0 0,1 0,1 1,0 1,0 0)),((2 2,3 2,3 3,2 3,2 2)))", "MULTIPOINT(0.5 0.5,2.5 2.5)", true},
+		{"MULTIPOLYGON(((0 0,1 0,1 1,0 1,0 0)),((2 2,3 2,3 3,2 3,2 2)))", "POINT(1.5 1.5)", false},
+		{"LINESTRING(0 0,2 2)", "POINT(1 1)", true},
+		{"LINESTRING(0 0,2 2)", "POINT(0 0)", false},
+		{"LINESTRING(0 0,2 2,4 0)", "LINESTRING(1 1,2 2,3 1)", true},
+		{"POINT(1 1)", "POINT(1 1)", true},
+		{"POINT(1 1)", "LINESTRING(1 1,2 2)", false},
+		{"MULTIPOINT(1 1,2 2)", "POINT(2 2)", true},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(1 1,2 2))", true},
+		{"POLYGON((0 0,4 0,4 4,0 4,0 0))", "GEOMETRYCOLLECTION EMPTY", false},
+	}
+	for _, tt := range tests {
+		g1, err := ParseGeometryWKT(tt.g1, 0)
+		require.NoError(t, err)
+		g2, err := ParseGeometryWKT(tt.g2, 0)
+		require.NoError(t, err)
+		contains, ok := g1.Contains(g2)
+		require.True(t, ok)
+		require.Equal(t, tt.expected, contains, "%s contains %s", tt.g1, tt.g2)
+	}
+
+	collection, err := ParseGeometryWKT("GEOMETRYCOLLECTION(POINT(1 1))", 0)
+	require.NoError(t, err)
+	_, ok := collection.Contains(NewPointGeometry(1, 1, 0))
+	require.False(t, ok)
+}
+
+func TestGeometryDistanceSphere(t *testing.T) {
+	p1, p2 := NewPointGeometry(0, 0, 0), NewPointGeometry(0, 1, 0)
+	distance, err := p1.DistanceSphere(p2, DefaultSphereRadius)
+	require.NoError(t, err)
+	require.InDelta(t, 111194.6, distance, 0.1)
+	distance, err = p1.DistanceSphere(p2, 1)
+	require.NoError(t, err)
+	require.InDelta(t, 0.0174533, distance, 1e-7)
+
+	mp, err := ParseGeometryWKT("MULTIPOINT(10 10,0 2)", 0)
+	require.NoError(t, err)
+	distance, err = p1.DistanceSphere(mp, 1)
+	require.NoError(t, err)
+	require.InDelta(t, 0.0349066, distance, 1e-7)
+
+	_, err = p1.DistanceSphere(p2, 0)
+	require.True(t, ErrNonpositiveRadius.Equal(err))
+	_, err = p1.DistanceSphere(NewPointGeometry(-180, 0, 0), 1)
+	require.True(t, ErrLongitudeOutOfRange.Equal(err))
+	require.EqualError(t, err, "[types:3616]Longitude -180.000000 is out of range in function st_distance_sphere. It must be within (-180.000000, 180.000000].")
+	_, err = p1.DistanceSphere(NewPointGeometry(0, 91, 0), 1)
+	require.True(t, ErrLatitudeOutOfRange.Equal(err))
+	line, err := ParseGeometryWKT("LINESTRING(0 0,1 1)", 0)
+	require.NoError(t, err)
+	_, err = p1.DistanceSphere(line, 1)
+	require.True(t, ErrGISUnsupportedArgument.Equal(err))
+}
+
+func TestConvertToGeometry(t *testing.T) {
+	ctx := DefaultStmtNoWarningContext
+	point := NewPointGeometry(1, 2, 0).Encode()
+
+	ft := NewFieldType(mysql.TypeGeometry)
+	d := NewBytesDatum(point)
+	d, err := d.ConvertTo(ctx, ft)
+	require.NoError(t, err)
+	require.Equal(t, point, d.GetBytes())
+
+	ft.SetGeometryType(GeometryTypePolygon)
+	_, err = d.ConvertTo(ctx, ft)
+	require.True(t, ErrCantCreateGeometryObject.Equal(err))
+	d = NewStringDatum("POINT(1 2)")
+	_, err = d.ConvertTo(ctx, ft)
+	require.True(t, ErrCantCreateGeometryObject.Equal(err))
+	d = NewIntDatum(1)
+	_, err = d.ConvertTo(ctx, ft)
+	require.True(t, ErrCantCreateGeometryObject.Equal(err))
+}
@@ -0,0 +1,378 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+var wktTypeNames = map[string]GeometryType{
+	"POINT":              GeometryTypePoint,
+	"LINESTRING":         GeometryTypeLineString,
+	"POLYGON":            GeometryTypePolygon,
+	"MULTIPOINT":         GeometryTypeMultiPoint,
+	"MULTILINESTRING":    GeometryTypeMultiLineString,
+	"MULTIPOLYGON":       GeometryTypeMultiPolygon,
+	"GEOMETRYCOLLECTION": GeometryTypeGeometryCollection,
+	"GEOMCOLLECTION":     GeometryTypeGeometryCollection,
+}
+
+// ParseGeometryWKT parses a geometry from the WKT representation.
+func ParseGeometryWKT(wkt string, srid uint32) (Geometry, error) {
+	p := wktParser{s: wkt}
+	g, err := p.parseGeometry(0)
+	if err != nil {
+		return Geometry{}, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return Geometry{}, errInvalidGeometry
+	}
+	g.setSRID(srid)
+	return g, nil
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) skipSpaces() {
+	for p.pos < len(p.s) && isWKTSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func isWKTSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// consume skips the spaces and consumes c if it's the next character.
+func (p *wktParser) consume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) expect(c byte) error {
+	if !p.consume(c) {
+		return errInvalidGeometry
+	}
+	return nil
+}
+
+func (p *wktParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] >= 'a' && p.s[p.pos] <= 'z' || p.s[p.pos] >= 'A' && p.s[p.pos] <= 'Z') {
+		p.pos++
+	}
+	return strings.ToUpper(p.s[start:p.pos])
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && !isWKTSpace(p.s[p.pos]) && p.s[p.pos] != ',' && p.s[p.pos] != ')' && p.s[p.pos] != '(' {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, errInvalidGeometry
+	}
+	return f, nil
+}
+
+func (p *wktParser) point() (pt GeoPoint, err error) {
+	if pt.X, err = p.number(); err != nil {
+		return pt, err
+	}
+	pt.Y, err = p.number()
+	return pt, err
+}
+
+// pointList parses a parenthesized list of points.
+func (p *wktParser) pointList() ([]GeoPoint, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var points []GeoPoint
+	for {
+		pt, err := p.point()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, pt)
+		if !p.consume(',') {
+			break
+		}
+	}
+	return points, p.expect(')')
+}
+
+// list parses a parenthesized list whose elements are parsed by f.
+func (p *wktParser) list(f func() error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := f(); err != nil {
+			return err
+		}
+		if !p.consume(',') {
+			break
+		}
+	}
+	return p.expect(')')
+}
+
+func (p *wktParser) parseGeometry(depth int) (g Geometry, err error) {
+	if depth > maxGeometryDepth {
+		return g, errInvalidGeometry
+	}
+	tp, ok := wktTypeNames[p.word()]
+	if !ok {
+		return g, errInvalidGeometry
+	}
+	g.Type = tp
+	switch tp {
+	case GeometryTypePoint:
+		err = p.list(func() error {
+			if len(g.Points) > 0 {
+				return errInvalidGeometry
+			}
+			pt, err := p.point()
+			g.Points = append(g.Points, pt)
+			return err
+		})
+	case GeometryTypeLineString:
+		g.Points, err = p.pointList()
+	case GeometryTypePolygon:
+		g.Rings, err = p.rings()
+	case GeometryTypeMultiPoint:
+		err = p.list(func() error {
+			// Both MULTIPOINT(0 0, 1 1) and MULTIPOINT((0 0), (1 1)) are allowed.
+			parenthesized := p.consume('(')
+			pt, err := p.point()
+			if err != nil {
+				return err
+			}
+			if parenthesized {
+				if err := p.expect(')'); err != nil {
+					return err
+				}
+			}
+			g.Geoms = append(g.Geoms, Geometry{Type: GeometryTypePoint, Points: []GeoPoint{pt}})
+			return nil
+		})
+	case GeometryTypeMultiLineString:
+		err = p.list(func() error {
+			points, err := p.pointList()
+			g.Geoms = append(g.Geoms, Geometry{Type: GeometryTypeLineString, Points: points})
+			return err
+		})
+	case GeometryTypeMultiPolygon:
+		err = p.list(func() error {
+			rings, err := p.rings()
+			g.Geoms = append(g.Geoms, Geometry{Type: GeometryTypePolygon, Rings: rings})
+			return err
+		})
+	case GeometryTypeGeometryCollection:
+		if p.word() == "EMPTY" {
+			break
+		}
+		if err = p.expect('('); err != nil || p.consume(')') {
+			break
+		}
+		for {
+			var member Geometry
+			if member, err = p.parseGeometry(depth + 1); err != nil {
+				return g, err
+			}
+			g.Geoms = append(g.Geoms, member)
+			if !p.consume(',') {
+				break
+			}
+		}
+		err = p.expect(')')
+	}
+	if err != nil {
+		return g, err
+	}
+	for i := range g.Geoms {
+		if err := g.Geoms[i].validate(); err != nil {
+			return g, err
+		}
+	}
+	return g, g.validate()
+}
+
+func (p *wktParser) rings() ([][]GeoPoint, error) {
+	var rings [][]GeoPoint
+	err := p.list(func() error {
+		ring, err := p.pointList()
+		rings = append(rings, ring)
+		return err
+	})
+	return rings, err
+}
+
+// WKT returns the WKT representation of the geometry.
+func (g Geometry) WKT() string {
+	var sb strings.Builder
+	g.writeWKT(&sb)
+	return sb.String()
+}
+
+func (g Geometry) writeWKT(sb *strings.Builder) {
+	switch g.Type {
+	case GeometryTypeGeometryCollection:
+		sb.WriteString("GEOMETRYCOLLECTION")
+		if len(g.Geoms) == 0 {
+			sb.WriteString(" EMPTY")
+			return
+		}
+	default:
+		sb.WriteString(strings.ToUpper(g.Type.String()))
+	}
+	g.writeWKTBody(sb)
+}
+
+func (g Geometry) writeWKTBody(sb *strings.Builder) {
+	sb.WriteByte('(')
+	switch g.Type {
+	case GeometryTypePoint:
+		writeWKTPoints(sb, g.Points)
+	case GeometryTypeLineString:
+		writeWKTPoints(sb, g.Points)
+	case GeometryTypePolygon:
+		for i, ring := range g.Rings {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteByte('(')
+			writeWKTPoints(sb, ring)
+			sb.WriteByte(')')
+		}
+	case GeometryTypeGeometryCollection:
+		for i, member := range g.Geoms {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			member.writeWKT(sb)
+		}
+	default:
+		for i, member := range g.Geoms {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			if member.Type == GeometryTypePoint {
+				sb.WriteByte('(')
+				writeWKTPoints(sb, member.Points)
+				sb.WriteByte(')')
+			} else {
+				member.writeWKTBody(sb)
+			}
+		}
+	}
+	sb.WriteByte(')')
+}
+
+func writeWKTPoints(sb *strings.Builder, points []GeoPoint) {
+	for i, pt := range points {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(formatWKTFloat(pt.X))
+		sb.WriteByte(' ')
+		sb.WriteString(formatWKTFloat(pt.Y))
+	}
+}
+
+// formatWKTFloat formats the coordinate like MySQL, which doesn't use the scientific notation unless the number is
+// very large or very small.
+func formatWKTFloat(f float64) string {
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-15 || abs >= 1e15) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+var geoJSONTypeNames = [...]string{
+	GeometryTypePoint:              "Point",
+	GeometryTypeLineString:         "LineString",
+	GeometryTypePolygon:            "Polygon",
+	GeometryTypeMultiPoint:         "MultiPoint",
+	GeometryTypeMultiLineString:    "MultiLineString",
+	GeometryTypeMultiPolygon:       "MultiPolygon",
+	GeometryTypeGeometryCollection: "GeometryCollection",
+}
+
+// GeoJSON returns the GeoJSON representation of the geometry. The coordinates are rounded to maxDecimalDigits
+// decimal places.
+func (g Geometry) GeoJSON(maxDecimalDigits int) (BinaryJSON, error) {
+	return CreateBinaryJSONWithCheck(g.geoJSONObject(maxDecimalDigits))
+}
+
+func (g Geometry) geoJSONObject(maxDecimalDigits int) map[string]any {
+	obj := map[string]any{"type": geoJSONTypeNames[g.Type]}
+	if g.Type == GeometryTypeGeometryCollection {
+		members := make([]any, 0, len(g.Geoms))
+		for _, member := range g.Geoms {
+			members = append(members, member.geoJSONObject(maxDecimalDigits))
+		}
+		obj["geometries"] = members
+		return obj
+	}
+	obj["coordinates"] = g.geoJSONCoordinates(maxDecimalDigits)
+	return obj
+}
+
+func (g Geometry) geoJSONCoordinates(maxDecimalDigits int) []any {
+	switch g.Type {
+	case GeometryTypePoint:
+		return geoJSONPoint(g.Points[0], maxDecimalDigits)
+	case GeometryTypeLineString:
+		return geoJSONPoints(g.Points, maxDecimalDigits)
+	case GeometryTypePolygon:
+		rings := make([]any, 0, len(g.Rings))
+		for _, ring := range g.Rings {
+			rings = append(rings, geoJSONPoints(ring, maxDecimalDigits))
+		}
+		return rings
+	}
+	members := make([]any, 0, len(g.Geoms))
+	for _, member := range g.Geoms {
+		members = append(members, member.geoJSONCoordinates(maxDecimalDigits))
+	}
+	return members
+}
+
+func geoJSONPoints(points []GeoPoint, maxDecimalDigits int) []any {
+	coordinates := make([]any, 0, len(points))
+	for _, pt := range points {
+		coordinates = append(coordinates, geoJSONPoint(pt, maxDecimalDigits))
+	}
+	return coordinates
+}
+
+func geoJSONPoint(pt GeoPoint, maxDecimalDigits int) []any {
+	return []any{Round(pt.X, maxDecimalDigits), Round(pt.Y, maxDecimalDigits)}
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	// MaxVectorDimension is the max dimension of a vector.
+	MaxVectorDimension = 16383
+
+	vectorDimensionLen = 4
+	vectorElementLen   = 4
+)
+
+// VectorFloat32 is a vector of float32 values, which is the value of VECTOR columns.
+// A vector is stored as its dimension (uint32) followed by its elements (float32), all in little endian.
+type VectorFloat32 []float32
+
+// EncodeVectorFloat32 encodes a vector to the storage format.
+func EncodeVectorFloat32(v VectorFloat32) []byte {
+	buf := make([]byte, 0, vectorDimensionLen+vectorElementLen*len(v))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+	for _, x := range v {
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(x))
+	}
+	return buf
+}
+
+// DecodeVectorFloat32 decodes a vector in the storage format.
+func DecodeVectorFloat32(b []byte) (VectorFloat32, error) {
+	if len(b) < vectorDimensionLen {
+		return nil, errors.Trace(ErrInvalidVectorValue.GenWithStackByArgs(string(b)))
+	}
+	dim := binary.LittleEndian.Uint32(b)
+	if dim > MaxVectorDimension || len(b) != vectorDimensionLen+vectorElementLen*int(dim) {
+		return nil, errors.Trace(ErrInvalidVectorValue.GenWithStackByArgs(string(b)))
+	}
+	v := make(VectorFloat32, dim)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[vectorDimensionLen+vectorElementLen*i:]))
+		if !isValidVectorElement(v[i]) {
+			return nil, errors.Trace(ErrInvalidVectorValue.GenWithStackByArgs(string(b)))
+		}
+	}
+	return v, nil
+}
+
+// ParseVectorFloat32 parses the text form of a vector, e.g. `[1, 2.5, -3]`.
+func ParseVectorFloat32(s string) (VectorFloat32, error) {
+	trimmed := strings.TrimSpace(s)
+	if len(trimmed) < 2 || trimmed[0] != '[' || trimmed[len(trimmed)-1] != ']' {
+		return nil, errors.Trace(ErrInvalidVectorValue.GenWithStackByArgs(s))
+	}
+	trimmed = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+	if len(trimmed) == 0 {
+		return VectorFloat32{}, nil
+	}
+	elems := strings.Split(trimmed, ",")
+	if len(elems) > MaxVectorDimension {
+		return nil, errors.Trace(ErrInvalidVectorValue.GenWithStackByArgs(s))
+	}
+	v := make(VectorFloat32, 0, len(elems))
+	for _, elem := range elems {
+		x, err := strconv.ParseFloat(strings.TrimSpace(elem), 32)
+		if err != nil || !isValidVectorElement(float32(x)) {
+			return nil, errors.Trace(ErrInvalidVectorValue.GenWithStackByArgs(s))
+		}
+		v = append(v, float32(x))
+	}
+	return v, nil
+}
+
+// ConvertToVectorFloat32 converts a value in the storage format or in the text form to a vector.
+// The storage format can't be mistaken for the text form because its dimension has zero bytes.
+func ConvertToVectorFloat32(b []byte) (VectorFloat32, error) {
+	if v, err := DecodeVectorFloat32(b); err == nil {
+		return v, nil
+	}
+	return ParseVectorFloat32(string(b))
+}
+
+func isValidVectorElement(x float32) bool {
+	return !math.IsNaN(float64(x)) && !math.IsInf(float64(x), 0)
+}
+
+// String returns the text form of the vector.
+func (v VectorFloat32) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// FormatVectorFloat32 returns the text form of a vector in the storage format. Invalid values are returned as is.
+func FormatVectorFloat32(b []byte) string {
+	v, err := DecodeVectorFloat32(b)
+	if err != nil {
+		return string(b)
+	}
+	return v.String()
+}
+
+func (v VectorFloat32) checkDimension(other VectorFloat32) error {
+	if len(v) != len(other) {
+		return errors.Trace(ErrVectorDimensionMismatch.GenWithStackByArgs(len(v), len(other)))
+	}
+	return nil
+}
+
+// L2Norm returns the Euclidean norm of the vector.
+func (v VectorFloat32) L2Norm() float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// InnerProduct returns the inner product of two vectors.
+func (v VectorFloat32) InnerProduct(other VectorFloat32) (float64, error) {
+	if err := v.checkDimension(other); err != nil {
+		return 0, err
+	}
+	var sum float64
+	for i := range v {
+		sum += float64(v[i]) * float64(other[i])
+	}
+	return sum, nil
+}
+
+// L2Distance returns the Euclidean distance between two vectors.
+func (v VectorFloat32) L2Distance(other VectorFloat32) (float64, error) {
+	if err := v.checkDimension(other); err != nil {
+		return 0, err
+	}
+	var sum float64
+	for i := range v {
+		diff := float64(v[i]) - float64(other[i])
+		sum += diff * diff
+	}
+	return math.Sqrt(sum), nil
+}
+
+// CosineDistance returns the cosine distance between two vectors, which is 1 - cosine similarity.
+// The distance is NaN if either vector is a zero vector, so isNull is true in that case.
+func (v VectorFloat32) CosineDistance(other VectorFloat32) (distance float64, isNull bool, err error) {
+	if err := v.checkDimension(other); err != nil {
+		return 0, false, err
+	}
+	var dot, norm1, norm2 float64
+	for i := range v {
+		dot += float64(v[i]) * float64(other[i])
+		norm1 += float64(v[i]) * float64(v[i])
+		norm2 += float64(other[i]) * float64(other[i])
+	}
+	if norm1 == 0 || norm2 == 0 {
+		return 0, true, nil
+	}
+	similarity := dot / math.Sqrt(norm1*norm2)
+	// Clamp the similarity in case of floating point errors.
+	similarity = math.Max(-1, math.Min(1, similarity))
+	return 1 - similarity, false, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVectorFloat32(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"[]", "[]"},
+		{"[1,2,3]", "[1,2,3]"},
+		{" [ 1.5 , -2e3, 0.1 ] ", "[1.5,-2000,0.1]"},
+	}
+	for _, tt := range tests {
+		v, err := ParseVectorFloat32(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.output, v.String())
+
+		decoded, err := DecodeVectorFloat32(EncodeVectorFloat32(v))
+		require.NoError(t, err)
+		require.Equal(t, v, decoded)
+		converted, err := ConvertToVectorFloat32(EncodeVectorFloat32(v))
+		require.NoError(t, err)
+		require.Equal(t, v, converted)
+		converted, err = ConvertToVectorFloat32([]byte(tt.input))
+		require.NoError(t, err)
+		require.Equal(t, v, converted)
+	}
+
+	for _, input := range []string{"", "[", "1,2", "[1,,2]", "[1,a]", "[1e100]", "[NaN]", "[1,2]x"} {
+		_, err := ParseVectorFloat32(input)
+		require.True(t, ErrInvalidVectorValue.Equal(err), input)
+	}
+	_, err := DecodeVectorFloat32([]byte{1, 0, 0, 0, 0})
+	require.True(t, ErrInvalidVectorValue.Equal(err))
+}
+
+func TestVectorFloat32Distance(t *testing.T) {
+	a := VectorFloat32{1, 2, 3}
+	b := VectorFloat32{4, 5, 6}
+
+	d, err := a.L2Distance(b)
+	require.NoError(t, err)
+	require.InDelta(t, math.Sqrt(27), d, 1e-9)
+	d, err = a.InnerProduct(b)
+	require.NoError(t, err)
+	require.InDelta(t, 32, d, 1e-9)
+	d, isNull, err := a.CosineDistance(b)
+	require.NoError(t, err)
+	require.False(t, isNull)
+	require.InDelta(t, 1-32/math.Sqrt(14*77), d, 1e-9)
+	d, _, err = a.CosineDistance(VectorFloat32{2, 4, 6})
+	require.NoError(t, err)
+	require.InDelta(t, 0, d, 1e-9)
+	_, isNull, err = a.CosineDistance(VectorFloat32{0, 0, 0})
+	require.NoError(t, err)
+	require.True(t, isNull)
+	require.InDelta(t, math.Sqrt(14), a.L2Norm(), 1e-9)
+
+	_, err = a.L2Distance(VectorFloat32{1, 2})
+	require.True(t, ErrVectorDimensionMismatch.Equal(err))
+}
+
+func TestConvertToVectorFloat32(t *testing.T) {
+	ft := NewFieldType(mysql.TypeTiDBVectorFloat32)
+	ft.SetFlen(3)
+	d := NewStringDatum("[1,2,3]")
+	d, err := d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.NoError(t, err)
+	require.Equal(t, "[1,2,3]", FormatVectorFloat32(d.GetBytes()))
+
+	d = NewStringDatum("[1,2]")
+	_, err = d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.True(t, ErrVectorDimensionMismatch.Equal(err))
+	d = NewIntDatum(1)
+	_, err = d.ConvertTo(DefaultStmtNoWarningContext, ft)
+	require.True(t, ErrInvalidVectorValue.Equal(err))
+
+	require.Equal(t, mysql.TypeTiDBVectorFloat32, MergeFieldType(mysql.TypeTiDBVectorFloat32, mysql.TypeNull))
+	require.Equal(t, mysql.TypeLongBlob, MergeFieldType(mysql.TypeVarchar, mysql.TypeTiDBVectorFloat32))
+}
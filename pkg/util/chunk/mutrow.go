@@ -93,7 +93,8 @@ func zeroValForType(tp *types.FieldType) any {
 		return int64(0)
 	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar:
 		return ""
-	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeGeometry,
+		mysql.TypeTiDBVectorFloat32:
 		return []byte{}
 	case mysql.TypeDuration:
 		return types.ZeroDuration
@@ -311,6 +311,8 @@ var (
 	ErrUnsupportedExpressionIndex = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "creating expression index containing unsafe functions without allow-expression-index in config"), nil))
 	// ErrPartitionExchangePartTable is returned when exchange table partition with another table is partitioned.
 	ErrPartitionExchangePartTable = ClassDDL.NewStd(mysql.ErrPartitionExchangePartTable)
+	// ErrPartitionInsteadOfSubpartition is returned when exchange a partition of a subpartitioned table.
+	ErrPartitionInsteadOfSubpartition = ClassDDL.NewStd(mysql.ErrPartitionInsteadOfSubpartition)
 	// ErrPartitionExchangeTempTable is returned when exchange table partition with a temporary table
 	ErrPartitionExchangeTempTable = ClassDDL.NewStd(mysql.ErrPartitionExchangeTempTable)
 	// ErrTablesDifferentMetadata is returned when exchanges tables is not compatible.
@@ -365,15 +367,23 @@ var (
 	ErrOptOnTemporaryTable = ClassDDL.NewStd(mysql.ErrOptOnTemporaryTable)
 	// ErrOptOnCacheTable returns when exec unsupported opt at cache mode
 	ErrOptOnCacheTable = ClassDDL.NewStd(mysql.ErrOptOnCacheTable)
-	// ErrUnsupportedOnCommitPreserve returns when exec unsupported opt on commit preserve
-	ErrUnsupportedOnCommitPreserve = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("TiDB doesn't support ON COMMIT PRESERVE ROWS for now", nil))
 	// ErrUnsupportedClusteredSecondaryKey returns when exec unsupported clustered secondary key
 	ErrUnsupportedClusteredSecondaryKey = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("CLUSTERED/NONCLUSTERED keyword is only supported for primary key", nil))
+	// ErrGlobalIndexOnNonPartitionedTable returns when GLOBAL is specified for an index of a non-partitioned table.
+	ErrGlobalIndexOnNonPartitionedTable = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("GLOBAL index is only supported on partitioned tables", nil))
+	// ErrUnsupportedGlobalIndex returns when the index can not be a global index.
+	ErrUnsupportedGlobalIndex = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "GLOBAL %s"), nil))
 
 	// ErrUnsupportedLocalTempTableDDL returns when ddl operation unsupported for local temporary table
 	ErrUnsupportedLocalTempTableDDL = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("TiDB doesn't support %s for local temporary table", nil))
+	// ErrUnsupportedPreserveRowsTempTableDDL returns when ddl operation unsupported for global temporary table with ON COMMIT PRESERVE ROWS
+	ErrUnsupportedPreserveRowsTempTableDDL = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("TiDB doesn't support %s for global temporary table with ON COMMIT PRESERVE ROWS", nil))
 	// ErrInvalidAttributesSpec is returned when meeting invalid attributes.
 	ErrInvalidAttributesSpec = ClassDDL.NewStd(mysql.ErrInvalidAttributesSpec)
+	// ErrBadFtColumn returns when the column can't be indexed by FULLTEXT index.
+	ErrBadFtColumn = ClassDDL.NewStd(mysql.ErrBadFtColumn)
+	// ErrFulltextFunctionalIndex returns when creating FULLTEXT index on expressions.
+	ErrFulltextFunctionalIndex = ClassDDL.NewStd(mysql.ErrFulltextFunctionalIndex)
 	// ErrFunctionalIndexOnJSONOrGeometryFunction returns when creating expression index and the type of the expression is JSON.
 	ErrFunctionalIndexOnJSONOrGeometryFunction = ClassDDL.NewStd(mysql.ErrFunctionalIndexOnJSONOrGeometryFunction)
 	// ErrDependentByFunctionalIndex returns when the dropped column depends by expression index.
@@ -442,7 +452,7 @@ var (
 	ErrUnsupportedColumnInTTLConfig = ClassDDL.NewStd(mysql.ErrUnsupportedColumnInTTLConfig)
 	// ErrTTLColumnCannotDrop returns when a column is dropped while referenced by TTL config
 	ErrTTLColumnCannotDrop = ClassDDL.NewStd(mysql.ErrTTLColumnCannotDrop)
-	// ErrSetTTLOptionForNonTTLTable returns when the `TTL_ENABLE` or `TTL_JOB_INTERVAL` option is set on a non-TTL table
+	// ErrSetTTLOptionForNonTTLTable returns when the `TTL_ENABLE`, `TTL_JOB_INTERVAL` or `TTL_ARCHIVE` option is set on a non-TTL table
 	ErrSetTTLOptionForNonTTLTable = ClassDDL.NewStd(mysql.ErrSetTTLOptionForNonTTLTable)
 	// ErrTempTableNotAllowedWithTTL returns when setting TTL config for a temp table
 	ErrTempTableNotAllowedWithTTL = ClassDDL.NewStd(mysql.ErrTempTableNotAllowedWithTTL)
@@ -491,6 +501,39 @@ var (
 	)
 	// ErrCheckConstraintDupName is for duplicate check constraint names
 	ErrCheckConstraintDupName = ClassDDL.NewStd(mysql.ErrCheckConstraintDupName)
+	// ErrTrgAlreadyExists returns when the trigger already exists.
+	ErrTrgAlreadyExists = ClassDDL.NewStd(mysql.ErrTrgAlreadyExists)
+	// ErrTrgDoesNotExist returns when the trigger doesn't exist.
+	ErrTrgDoesNotExist = ClassDDL.NewStd(mysql.ErrTrgDoesNotExist)
+	// ErrTrgOnViewOrTempTable returns when creating a trigger on a view or a temporary table.
+	ErrTrgOnViewOrTempTable = ClassDDL.NewStd(mysql.ErrTrgOnViewOrTempTable)
+	// ErrTrgInWrongSchema returns when the trigger and the table are in different schemas.
+	ErrTrgInWrongSchema = ClassDDL.NewStd(mysql.ErrTrgInWrongSchema)
+	// ErrNoTriggersOnSystemSchema returns when creating a trigger on a system table.
+	ErrNoTriggersOnSystemSchema = ClassDDL.NewStd(mysql.ErrNoTriggersOnSystemSchema)
+	// ErrReferencedTrgDoesNotExist returns when the trigger in the FOLLOWS or PRECEDES clause doesn't exist.
+	ErrReferencedTrgDoesNotExist = ClassDDL.NewStd(mysql.ErrReferencedTrgDoesNotExist)
+	// ErrEventAlreadyExists returns when the event already exists.
+	ErrEventAlreadyExists = ClassDDL.NewStd(mysql.ErrEventAlreadyExists)
+	// ErrEventDoesNotExist returns when the event doesn't exist.
+	ErrEventDoesNotExist = ClassDDL.NewStd(mysql.ErrEventDoesNotExist)
+	// ErrEventIntervalNotPositiveOrTooBig returns when the interval of an event is invalid.
+	ErrEventIntervalNotPositiveOrTooBig = ClassDDL.NewStd(mysql.ErrEventIntervalNotPositiveOrTooBig)
+	// ErrEventEndsBeforeStarts returns when the ENDS of an event is before its STARTS.
+	ErrEventEndsBeforeStarts = ClassDDL.NewStd(mysql.ErrEventEndsBeforeStarts)
+	// ErrEventExecTimeInThePast is the note when an event is disabled because its execution time is in the past.
+	ErrEventExecTimeInThePast = ClassDDL.NewStd(mysql.ErrEventExecTimeInThePast)
+	// ErrEventCannotCreateInThePast is the note when an event is dropped right after it's created because its
+	// execution time is in the past.
+	ErrEventCannotCreateInThePast = ClassDDL.NewStd(mysql.ErrEventCannotCreateInThePast)
+	// ErrEventCannotAlterInThePast returns when altering an event whose execution time is in the past.
+	ErrEventCannotAlterInThePast = ClassDDL.NewStd(mysql.ErrEventCannotAlterInThePast)
+	// ErrEventSameName returns when an event is renamed to its own name.
+	ErrEventSameName = ClassDDL.NewStd(mysql.ErrEventSameName)
+	// ErrMViewFastRefreshUnsupported returns when a materialized view can't be refreshed incrementally.
+	ErrMViewFastRefreshUnsupported = ClassDDL.NewStd(mysql.ErrMViewFastRefreshUnsupported)
+	// ErrMViewDependency returns when dropping or changing a table or column used by a materialized view.
+	ErrMViewDependency = ClassDDL.NewStd(mysql.ErrMViewDependency)
 	// ErrUnsupportedDistTask is for `tidb_enable_dist_task enabled` but `tidb_ddl_enable_fast_reorg` disabled.
 	ErrUnsupportedDistTask = ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation,
 		parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw,
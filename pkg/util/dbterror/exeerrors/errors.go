@@ -96,8 +96,38 @@ var (
 	ErrInvalidOptionVal               = dbterror.ClassExecutor.NewStd(mysql.ErrInvalidOptionVal)
 	ErrDuplicateOption                = dbterror.ClassExecutor.NewStd(mysql.ErrDuplicateOption)
 	ErrLoadDataUnsupportedOption      = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataUnsupportedOption)
+	ErrFileExists                     = dbterror.ClassExecutor.NewStd(mysql.ErrFileExists)
 	ErrLoadDataJobNotFound            = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataJobNotFound)
 	ErrLoadDataInvalidOperation       = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataInvalidOperation)
 	ErrLoadDataLocalUnsupportedOption = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataLocalUnsupportedOption)
 	ErrLoadDataPreCheckFailed         = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataPreCheckFailed)
+	ErrXaerNota                       = dbterror.ClassExecutor.NewStd(mysql.ErrXaerNota)
+	ErrXaerInval                      = dbterror.ClassExecutor.NewStd(mysql.ErrXaerInval)
+	ErrXaerRmfail                     = dbterror.ClassExecutor.NewStd(mysql.ErrXaerRmfail)
+	ErrXaerOutside                    = dbterror.ClassExecutor.NewStd(mysql.ErrXaerOutside)
+	ErrXaerDupid                      = dbterror.ClassExecutor.NewStd(mysql.ErrXaerDupid)
+	ErrSpAlreadyExists                = dbterror.ClassExecutor.NewStd(mysql.ErrSpAlreadyExists)
+	ErrSpDoesNotExist                 = dbterror.ClassExecutor.NewStd(mysql.ErrSpDoesNotExist)
+	ErrSpLilabelMismatch              = dbterror.ClassExecutor.NewStd(mysql.ErrSpLilabelMismatch)
+	ErrSpLabelMismatch                = dbterror.ClassExecutor.NewStd(mysql.ErrSpLabelMismatch)
+	ErrSpWrongNoOfArgs                = dbterror.ClassExecutor.NewStd(mysql.ErrSpWrongNoOfArgs)
+	ErrSpCursorMismatch               = dbterror.ClassExecutor.NewStd(mysql.ErrSpCursorMismatch)
+	ErrSpCursorAlreadyOpen            = dbterror.ClassExecutor.NewStd(mysql.ErrSpCursorAlreadyOpen)
+	ErrSpCursorNotOpen                = dbterror.ClassExecutor.NewStd(mysql.ErrSpCursorNotOpen)
+	ErrSpUndeclaredVar                = dbterror.ClassExecutor.NewStd(mysql.ErrSpUndeclaredVar)
+	ErrSpWrongNoOfFetchArgs           = dbterror.ClassExecutor.NewStd(mysql.ErrSpWrongNoOfFetchArgs)
+	ErrSpFetchNoData                  = dbterror.ClassExecutor.NewStd(mysql.ErrSpFetchNoData)
+	ErrSpDupParam                     = dbterror.ClassExecutor.NewStd(mysql.ErrSpDupParam)
+	ErrSpDupVar                       = dbterror.ClassExecutor.NewStd(mysql.ErrSpDupVar)
+	ErrSpDupCurs                      = dbterror.ClassExecutor.NewStd(mysql.ErrSpDupCurs)
+	ErrSpCaseNotFound                 = dbterror.ClassExecutor.NewStd(mysql.ErrSpCaseNotFound)
+	ErrSpNotVarArg                    = dbterror.ClassExecutor.NewStd(mysql.ErrSpNotVarArg)
+	ErrSpRecursionLimit               = dbterror.ClassExecutor.NewStd(mysql.ErrSpRecursionLimit)
+	ErrProcaccessDenied               = dbterror.ClassExecutor.NewStd(mysql.ErrProcaccessDenied)
+	ErrTrgCantChangeRow               = dbterror.ClassExecutor.NewStd(mysql.ErrTrgCantChangeRow)
+	ErrTrgNoSuchRowInTrg              = dbterror.ClassExecutor.NewStd(mysql.ErrTrgNoSuchRowInTrg)
+	ErrCantUpdateUsedTableInSfOrTrg   = dbterror.ClassExecutor.NewStd(mysql.ErrCantUpdateUsedTableInSfOrTrg)
+	ErrStmtNotAllowedInSfOrTrg        = dbterror.ClassExecutor.NewStd(mysql.ErrStmtNotAllowedInSfOrTrg)
+	ErrCommitNotAllowedInSfOrTrg      = dbterror.ClassExecutor.NewStd(mysql.ErrCommitNotAllowedInSfOrTrg)
+	ErrSpNoRetset                     = dbterror.ClassExecutor.NewStd(mysql.ErrSpNoRetset)
 )
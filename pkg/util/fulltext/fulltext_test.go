@@ -0,0 +1,101 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	standardTokenizer = NewTokenizer(&model.FullTextIndexInfo{ParserType: model.FullTextParserTypeStandard})
+	ngramTokenizer    = NewTokenizer(&model.FullTextIndexInfo{ParserType: model.FullTextParserTypeNgram})
+)
+
+func TestTokenize(t *testing.T) {
+	require.Equal(t, []string{"tidb", "distributed", "sql", "database", "tidb_server", "2024"},
+		standardTokenizer.Tokenize("TiDB is a distributed SQL database, tidb_server... 2024!"))
+	require.Equal(t, []string{"tidb", "tidb"}, standardTokenizer.Tokenize("TiDB tidb"))
+	require.Equal(t, []string{"tidb"}, standardTokenizer.DistinctTokens("TiDB tidb"))
+	require.Empty(t, standardTokenizer.Tokenize("a an it"))
+	require.Equal(t, []string{"数据库系统"}, standardTokenizer.Tokenize("数据库系统"))
+
+	require.Equal(t, []string{"数据", "据库", "系统"}, ngramTokenizer.Tokenize("数据库，系统"))
+	require.Equal(t, []string{"ti", "id", "db"}, ngramTokenizer.Tokenize("TiDB a"))
+	trigram := NewTokenizer(&model.FullTextIndexInfo{ParserType: model.FullTextParserTypeNgram, NgramSize: 3})
+	require.Equal(t, []string{"分布式", "布式数", "式数据", "数据库"}, trigram.Tokenize("分布式数据库"))
+}
+
+func TestNaturalLanguageQuery(t *testing.T) {
+	q := ParseQuery("distributed database", false, standardTokenizer)
+	doc := standardTokenizer.Tokenize("TiDB is a distributed database")
+	require.Equal(t, 2.0, q.Score(doc))
+	require.Equal(t, 0.0, q.Score(standardTokenizer.Tokenize("MySQL")))
+	// More occurrences lead to higher relevance.
+	require.Greater(t, q.Score(standardTokenizer.Tokenize("database database")), q.Score(standardTokenizer.Tokenize("database")))
+
+	tokens, intersection := q.IndexAccess()
+	require.False(t, intersection)
+	require.Equal(t, []AccessToken{{Token: "distributed"}, {Token: "database"}}, tokens)
+
+	tokens, _ = ParseQuery("the", false, standardTokenizer).IndexAccess()
+	require.Empty(t, tokens)
+}
+
+func TestBooleanQuery(t *testing.T) {
+	doc := standardTokenizer.Tokenize("TiDB is a distributed SQL database")
+	tests := []struct {
+		query        string
+		matched      bool
+		tokens       []AccessToken
+		intersection bool
+	}{
+		{"tidb mysql", true, []AccessToken{{Token: "tidb"}, {Token: "mysql"}}, false},
+		{"+tidb +mysql", false, []AccessToken{{Token: "tidb"}, {Token: "mysql"}}, true},
+		{"+tidb -mysql", true, []AccessToken{{Token: "tidb"}}, true},
+		{"+tidb -sql", false, []AccessToken{{Token: "tidb"}}, true},
+		{"-mysql", false, nil, false},
+		{"data*", true, []AccessToken{{Token: "data", Prefix: true}}, false},
+		{"+da*", true, []AccessToken{{Token: "da", Prefix: true}}, false},
+		{"+dat* +tidb", true, []AccessToken{{Token: "tidb"}}, true},
+		{`"distributed sql"`, true, []AccessToken{{Token: "distributed"}}, false},
+		{`+"sql distributed"`, false, []AccessToken{{Token: "sql"}, {Token: "distributed"}}, true},
+		{`+"distributed sql" ~(mysql)`, true, []AccessToken{{Token: "distributed"}, {Token: "sql"}}, true},
+		{`"distributed sq*"`, true, []AccessToken{{Token: "distributed"}}, false},
+		{`distributed-sql`, true, []AccessToken{{Token: "distributed"}}, false},
+	}
+	for _, tt := range tests {
+		q := ParseQuery(tt.query, true, standardTokenizer)
+		require.Equal(t, tt.matched, q.Score(doc) > 0, tt.query)
+		tokens, intersection := q.IndexAccess()
+		require.Equal(t, tt.tokens, tokens, tt.query)
+		require.Equal(t, tt.intersection, intersection, tt.query)
+	}
+
+	doc = ngramTokenizer.Tokenize("分布式数据库")
+	q := ParseQuery("+数据库", true, ngramTokenizer)
+	require.Greater(t, q.Score(doc), 0.0)
+	tokens, intersection := q.IndexAccess()
+	require.True(t, intersection)
+	require.Equal(t, []AccessToken{{Token: "数据"}, {Token: "据库"}}, tokens)
+	q = ParseQuery("+库数", true, ngramTokenizer)
+	require.Equal(t, 0.0, q.Score(doc))
+	q = ParseQuery("数*", true, ngramTokenizer)
+	require.Greater(t, q.Score(doc), 0.0)
+	tokens, _ = q.IndexAccess()
+	require.Equal(t, []AccessToken{{Token: "数", Prefix: true}}, tokens)
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+)
+
+type termOp int
+
+const (
+	termOptional termOp = iota
+	termRequired
+	termExcluded
+)
+
+// term is a word, a word prefix or a phrase in a search query.
+type term struct {
+	// tokens are the consecutive tokens which make up the term.
+	tokens []string
+	// prefix indicates whether the last token is a prefix.
+	prefix bool
+	op     termOp
+}
+
+// Query is a parsed search query of MATCH ... AGAINST.
+type Query struct {
+	terms []term
+}
+
+// ParseQuery parses the search string of MATCH ... AGAINST with the tokenizer of the FULLTEXT index.
+//
+// In natural language mode, every token of the search string is an optional term. In boolean mode, the following
+// operators are supported:
+//   - `+word`: the word must be present.
+//   - `-word`: the word must not be present.
+//   - `word*`: matches the words which begin with word.
+//   - `"some words"`: matches the phrase.
+//
+// The other boolean operators are ignored.
+func ParseQuery(query string, booleanMode bool, tokenizer Tokenizer) *Query {
+	q := &Query{}
+	if !booleanMode {
+		for _, token := range tokenizer.DistinctTokens(query) {
+			q.terms = append(q.terms, term{tokens: []string{token}})
+		}
+		return q
+	}
+	op := termOptional
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '+':
+			op = termRequired
+			i++
+			continue
+		case c == '-':
+			op = termExcluded
+			i++
+			continue
+		case c == '"':
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			q.addTerm(tokenizer.Tokenize(query[i+1:i+1+end]), false, op)
+			i += end + 2
+		case isBooleanDelimiter(c):
+			i++
+		default:
+			end := i
+			for end < len(query) && !isBooleanDelimiter(query[end]) && query[end] != '"' {
+				end++
+			}
+			q.addWordTerm(query[i:end], op, tokenizer)
+			i = end
+		}
+		op = termOptional
+	}
+	return q
+}
+
+func isBooleanDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '(', ')', '<', '>', '~':
+		return true
+	}
+	return false
+}
+
+func (q *Query) addWordTerm(word string, op termOp, tokenizer Tokenizer) {
+	prefix := strings.HasSuffix(word, "*")
+	word = strings.TrimRight(word, "*")
+	if !prefix {
+		q.addTerm(tokenizer.Tokenize(word), false, op)
+		return
+	}
+	var tokens []string
+	forEachWord(word, func(w []rune) {
+		tokens = append(tokens, string(w))
+	})
+	if tokenizer.parser == model.FullTextParserTypeNgram && len(tokens) > 0 &&
+		len([]rune(tokens[len(tokens)-1])) >= tokenizer.ngramSize {
+		// Like MySQL, a prefix term which isn't shorter than the ngram size is searched as a phrase.
+		q.addTerm(tokenizer.Tokenize(word), false, op)
+		return
+	}
+	// The prefix isn't filtered by the length and the stopwords.
+	last := len(tokens) - 1
+	if last < 0 {
+		return
+	}
+	phrase := append(tokenizer.Tokenize(strings.Join(tokens[:last], " ")), tokens[last])
+	q.addTerm(phrase, true, op)
+}
+
+func (q *Query) addTerm(tokens []string, prefix bool, op termOp) {
+	if len(tokens) == 0 {
+		return
+	}
+	q.terms = append(q.terms, term{tokens: tokens, prefix: prefix, op: op})
+}
+
+// Score returns the relevance of the document for the query, where doc is the tokens of the document. It returns 0
+// if the document doesn't match the query.
+//
+// The relevance is the sum of the logarithmic frequencies of the matched terms in the document. Unlike MySQL, it
+// doesn't take the frequencies of the terms in the whole table into account.
+func (q *Query) Score(doc []string) float64 {
+	var score float64
+	for _, t := range q.terms {
+		count := t.count(doc)
+		switch t.op {
+		case termExcluded:
+			if count > 0 {
+				return 0
+			}
+			continue
+		case termRequired:
+			if count == 0 {
+				return 0
+			}
+		}
+		if count > 0 {
+			score += 1 + math.Log(float64(count))
+		}
+	}
+	return score
+}
+
+// count returns the number of occurrences of the term in the document.
+func (t *term) count(doc []string) int {
+	count := 0
+	last := len(t.tokens) - 1
+	for i := 0; i+last < len(doc); i++ {
+		matched := true
+		for j, token := range t.tokens {
+			if j == last && t.prefix {
+				matched = strings.HasPrefix(doc[i+j], token)
+			} else {
+				matched = doc[i+j] == token
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			count++
+		}
+	}
+	return count
+}
+
+// AccessToken is a token, or a token prefix, to look up in a FULLTEXT index.
+type AccessToken struct {
+	Token  string
+	Prefix bool
+}
+
+// IndexAccess returns the tokens to look up in the FULLTEXT index to find the documents matching the query. If
+// intersection is true, the matched documents contain all the tokens, otherwise they contain at least one of them.
+// No tokens are returned if the index can't be used, e.g. the query only has excluded terms.
+func (q *Query) IndexAccess() (tokens []AccessToken, intersection bool) {
+	seen := make(map[AccessToken]struct{})
+	add := func(token AccessToken) {
+		if _, ok := seen[token]; !ok {
+			seen[token] = struct{}{}
+			tokens = append(tokens, token)
+		}
+	}
+	var prefixToken *AccessToken
+	for _, t := range q.terms {
+		if t.op != termRequired {
+			continue
+		}
+		last := len(t.tokens) - 1
+		for i, token := range t.tokens {
+			if i == last && t.prefix {
+				prefixToken = &AccessToken{Token: token, Prefix: true}
+				continue
+			}
+			add(AccessToken{Token: token})
+		}
+	}
+	if len(tokens) > 0 {
+		// Every token is looked up by a point range, so a document is found at most once for each token, which is
+		// required by the intersection.
+		return tokens, true
+	}
+	if prefixToken != nil {
+		return []AccessToken{*prefixToken}, false
+	}
+	for _, t := range q.terms {
+		if t.op == termExcluded {
+			continue
+		}
+		add(AccessToken{Token: t.tokens[0], Prefix: t.prefix && len(t.tokens) == 1})
+	}
+	return tokens, false
+}
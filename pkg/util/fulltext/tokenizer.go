@@ -0,0 +1,116 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fulltext implements the tokenizers and the search queries of FULLTEXT indexes.
+package fulltext
+
+import (
+	"unicode"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+)
+
+const (
+	// minTokenSize and maxTokenSize are the bounds of the number of characters of the tokens produced by the standard
+	// parser, which are the same as the default values of innodb_ft_min_token_size and innodb_ft_max_token_size.
+	minTokenSize = 3
+	maxTokenSize = 84
+)
+
+// stopwords are the words ignored by the standard parser, which are the same as the default stopwords of InnoDB.
+var stopwords = map[string]struct{}{
+	"a": {}, "about": {}, "an": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "com": {}, "de": {}, "en": {},
+	"for": {}, "from": {}, "how": {}, "i": {}, "in": {}, "is": {}, "it": {}, "la": {}, "of": {}, "on": {}, "or": {},
+	"that": {}, "the": {}, "this": {}, "to": {}, "was": {}, "what": {}, "when": {}, "where": {}, "who": {}, "will": {},
+	"with": {}, "und": {}, "www": {},
+}
+
+// Tokenizer splits texts into tokens according to the parser of a FULLTEXT index. The tokens are lower-cased, so
+// the search is always case-insensitive.
+type Tokenizer struct {
+	parser    model.FullTextParserType
+	ngramSize int
+}
+
+// NewTokenizer creates a Tokenizer for the FULLTEXT index.
+func NewTokenizer(info *model.FullTextIndexInfo) Tokenizer {
+	t := Tokenizer{parser: info.ParserType, ngramSize: info.NgramSize}
+	if t.parser == model.FullTextParserTypeNgram && t.ngramSize <= 0 {
+		t.ngramSize = model.DefaultNgramSize
+	}
+	return t
+}
+
+// Tokenize returns the tokens of the text in order, which may contain duplicated tokens.
+func (t Tokenizer) Tokenize(text string) []string {
+	var tokens []string
+	forEachWord(text, func(word []rune) {
+		tokens = t.appendWordTokens(tokens, word)
+	})
+	return tokens
+}
+
+// DistinctTokens returns the distinct tokens of the text, which are the entries of the text in a FULLTEXT index.
+func (t Tokenizer) DistinctTokens(text string) []string {
+	tokens := t.Tokenize(text)
+	distinct := tokens[:0]
+	seen := make(map[string]struct{}, len(tokens))
+	for _, token := range tokens {
+		if _, ok := seen[token]; !ok {
+			seen[token] = struct{}{}
+			distinct = append(distinct, token)
+		}
+	}
+	return distinct
+}
+
+func (t Tokenizer) appendWordTokens(tokens []string, word []rune) []string {
+	if t.parser == model.FullTextParserTypeNgram {
+		for i := 0; i+t.ngramSize <= len(word); i++ {
+			tokens = append(tokens, string(word[i:i+t.ngramSize]))
+		}
+		return tokens
+	}
+	if len(word) < minTokenSize || len(word) > maxTokenSize {
+		return tokens
+	}
+	token := string(word)
+	if _, ok := stopwords[token]; ok {
+		return tokens
+	}
+	return append(tokens, token)
+}
+
+// forEachWord calls f with the lower-cased words of the text. Letters, digits and underscores make up words, and all
+// the other characters are delimiters. The word passed to f is only valid during the call.
+func forEachWord(text string, f func(word []rune)) {
+	word := make([]rune, 0, 16)
+	for _, r := range text {
+		if isWordChar(r) {
+			word = append(word, unicode.ToLower(r))
+			continue
+		}
+		if len(word) > 0 {
+			f(word)
+			word = word[:0]
+		}
+	}
+	if len(word) > 0 {
+		f(word)
+	}
+}
+
+func isWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -860,6 +860,12 @@ const (
 	LabelForChunkDataInDiskByChunks int = -30
 	// LabelForSortPartition represents the label of the sort partition
 	LabelForSortPartition = -31
+	// LabelForTemporaryTableData represents the label of the committed data of temporary tables in the session
+	LabelForTemporaryTableData int = -32
+	// LabelForTriggerRows represents the label of the rows recorded for the AFTER triggers
+	LabelForTriggerRows int = -33
+	// LabelForStoredRoutine represents the label of the rows of the cursors and the result set of a stored routine
+	LabelForStoredRoutine int = -34
 )
 
 // MetricsTypes is used to get label for metrics
@@ -15,6 +15,8 @@
 package util
 
 import (
+	"time"
+
 	"github.com/pingcap/tidb/pkg/types"
 )
 
@@ -34,3 +36,19 @@ type PlanCacheMatchOpts struct {
 	// Below are some variables that can affect the plan
 	ForeignKeyChecks bool
 }
+
+// InstancePlanCacheItem is the information of a plan in the instance plan cache.
+type InstancePlanCacheItem struct {
+	SQLDigest  string
+	SQLText    string
+	SchemaName string
+	PlanDigest string
+	ParamTypes string
+	MemSize    int64
+	// Hits is the number of times this plan is hit.
+	Hits int64
+	// HitRate is the hit rate of the statement, which is the number of hits divided by the number of lookups.
+	HitRate        float64
+	CreateTime     time.Time
+	LastAccessTime time.Time
+}
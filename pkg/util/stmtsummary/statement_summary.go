@@ -389,6 +389,11 @@ type BindableStmt struct {
 	Charset   string
 	Collation string
 	Users     map[string]struct{} // which users have processed this stmt
+
+	// The following fields are the runtime stats of the plan in the current summary window.
+	PlanDigest string
+	ExecCount  int64
+	SumLatency time.Duration
 }
 
 // GetMoreThanCntBindableStmt gets users' select/update/delete SQLs that occurred more than the specified count.
@@ -417,6 +422,10 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 							Charset:   ssElement.charset,
 							Collation: ssElement.collation,
 							Users:     make(map[string]struct{}),
+
+							PlanDigest: ssbd.planDigest,
+							ExecCount:  ssElement.execCount,
+							SumLatency: ssElement.sumLatency,
 						}
 						maps.Copy(stmt.Users, ssElement.authUsers)
 						// If it is SQL command prepare / execute, the ssElement.sampleSQL is `execute ...`, we should get the original select query.
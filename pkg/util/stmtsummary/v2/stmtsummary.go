@@ -360,6 +360,10 @@ func (s *StmtSummary) GetMoreThanCntBindableStmt(cnt int64) []*stmtsummary.Binda
 						Charset:   record.Charset,
 						Collation: record.Collation,
 						Users:     make(map[string]struct{}),
+
+						PlanDigest: record.PlanDigest,
+						ExecCount:  record.ExecCount,
+						SumLatency: record.SumLatency,
 					}
 					maps.Copy(stmt.Users, record.AuthUsers)
 
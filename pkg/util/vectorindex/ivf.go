@@ -0,0 +1,244 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vectorindex implements the IVF (inverted file) vector indexes. The indexed vectors are clustered into lists
+// by k-means, and every row is indexed by the list of its nearest centroid. A search only scans the lists whose
+// centroids are the nearest to the searched vector, so the results are approximate.
+package vectorindex
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+const (
+	// MaxTrainingRows is the max number of rows sampled to train the centroids.
+	MaxTrainingRows = 8192
+	// MinTrainingRows is the min number of rows to train the centroids. An index with fewer rows is left untrained,
+	// and all its rows are in the untrained list.
+	MinTrainingRows = 256
+	// UntrainedList is the list of the rows indexed before the centroids are trained, until they are reassigned to
+	// the lists of the centroids. It's always scanned by a search, and the lists of the centroids follow it.
+	UntrainedList = 0
+	// maxLists is the max number of lists of an index.
+	maxLists = 256
+	// maxCentroidElements limits the size of the centroids kept in the table meta.
+	maxCentroidElements = 32768
+	// trainingIterations is the number of iterations of k-means.
+	trainingIterations = 16
+	// trainingSeed makes the training deterministic.
+	trainingSeed = 1
+)
+
+// NumLists returns the number of lists of an index trained with the rows, which is about the square root of the
+// number of rows.
+func NumLists(rows, dimension int) int {
+	lists := int(math.Sqrt(float64(rows)))
+	lists = min(lists, maxLists, maxCentroidElements/max(dimension, 1))
+	return max(lists, 1)
+}
+
+// normalize returns the unit vector of v for the cosine metric, or v itself for the L2 metric.
+func normalize(metric model.DistanceMetric, v []float32) []float32 {
+	if metric != model.DistanceMetricCosine {
+		return v
+	}
+	norm := types.VectorFloat32(v).L2Norm()
+	if norm == 0 {
+		return v
+	}
+	normalized := make([]float32, len(v))
+	for i, x := range v {
+		normalized[i] = float32(float64(x) / norm)
+	}
+	return normalized
+}
+
+// squaredL2 returns the squared Euclidean distance, which orders the centroids in the same way as the distance.
+func squaredL2(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		diff := float64(a[i]) - float64(b[i])
+		sum += diff * diff
+	}
+	return sum
+}
+
+// nearest returns the index of the nearest centroid of v.
+func nearest(centroids [][]float32, v []float32) int {
+	best, bestDist := 0, math.Inf(1)
+	for i, c := range centroids {
+		if d := squaredL2(c, v); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// Train trains the centroids of the lists with the sampled vectors by k-means. The vectors are normalized for the
+// cosine metric, so the L2 distance between the normalized vectors orders them in the same way as the cosine distance.
+func Train(metric model.DistanceMetric, dimension int, samples []types.VectorFloat32) [][]float32 {
+	points := make([][]float32, 0, len(samples))
+	for _, v := range samples {
+		if len(v) == dimension {
+			points = append(points, normalize(metric, v))
+		}
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	k := min(NumLists(len(points), dimension), len(points))
+	rng := rand.New(rand.NewSource(trainingSeed)) // #nosec G404
+
+	// Initialize the centroids by k-means++.
+	centroids := make([][]float32, 0, k)
+	centroids = append(centroids, slices.Clone(points[rng.Intn(len(points))]))
+	dists := make([]float64, len(points))
+	for len(centroids) < k {
+		var sum float64
+		for i, p := range points {
+			dists[i] = squaredL2(p, centroids[nearest(centroids, p)])
+			sum += dists[i]
+		}
+		if sum == 0 {
+			// All the points are the same as the centroids.
+			break
+		}
+		target := rng.Float64() * sum
+		chosen := len(points) - 1
+		for i, d := range dists {
+			if target < d {
+				chosen = i
+				break
+			}
+			target -= d
+		}
+		centroids = append(centroids, slices.Clone(points[chosen]))
+	}
+
+	// Refine the centroids by Lloyd's algorithm.
+	assignments := make([]int, len(points))
+	sums := make([][]float64, len(centroids))
+	counts := make([]int, len(centroids))
+	for iter := 0; iter < trainingIterations; iter++ {
+		changed := iter == 0
+		for i, p := range points {
+			if c := nearest(centroids, p); c != assignments[i] {
+				assignments[i] = c
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		for c := range centroids {
+			sums[c] = make([]float64, dimension)
+			counts[c] = 0
+		}
+		for i, p := range points {
+			c := assignments[i]
+			counts[c]++
+			for j, x := range p {
+				sums[c][j] += float64(x)
+			}
+		}
+		for c := range centroids {
+			// An empty list keeps its centroid.
+			if counts[c] == 0 {
+				continue
+			}
+			for j := range centroids[c] {
+				centroids[c][j] = float32(sums[c][j] / float64(counts[c]))
+			}
+			centroids[c] = normalize(metric, centroids[c])
+		}
+	}
+	return centroids
+}
+
+// Assign returns the list of the vector.
+func Assign(info *model.VectorIndexInfo, v types.VectorFloat32) int {
+	if len(info.Centroids) == 0 || info.PendingCentroids || len(v) != info.Dimension {
+		return UntrainedList
+	}
+	return nearest(info.Centroids, normalize(info.DistanceMetric, v)) + 1
+}
+
+// Probe returns the untrained list and the nprobe lists whose centroids are the nearest to the searched vector, in
+// ascending order.
+func Probe(info *model.VectorIndexInfo, v types.VectorFloat32, nprobe int) []int {
+	if len(info.Centroids) == 0 || len(v) != info.Dimension {
+		return []int{UntrainedList}
+	}
+	v = normalize(info.DistanceMetric, v)
+	lists := make([]int, len(info.Centroids))
+	dists := make([]float64, len(info.Centroids))
+	for i, c := range info.Centroids {
+		lists[i] = i + 1
+		dists[i] = squaredL2(c, v)
+	}
+	sort.SliceStable(lists, func(i, j int) bool {
+		return dists[lists[i]-1] < dists[lists[j]-1]
+	})
+	lists = append(lists[:min(max(nprobe, 1), len(lists))], UntrainedList)
+	slices.Sort(lists)
+	return lists
+}
+
+// NumListsOf returns the number of the lists of the centroids of the index.
+func NumListsOf(info *model.VectorIndexInfo) int {
+	return max(len(info.Centroids), 1)
+}
+
+// ListDatum returns the value of the index entries in the list. The list ID is encoded as bytes, which is the same
+// kind as the values of the indexed vector column.
+func ListDatum(list int) types.Datum {
+	return types.NewBytesDatum(binary.BigEndian.AppendUint32(nil, uint32(list)))
+}
+
+// IndexedDatum returns the value of the index entry of the vector, which is in the storage format. The NULL vectors
+// are indexed as NULL.
+func IndexedDatum(info *model.VectorIndexInfo, vector types.Datum) (types.Datum, error) {
+	if vector.IsNull() {
+		return vector, nil
+	}
+	v, err := types.DecodeVectorFloat32(vector.GetBytes())
+	if err != nil {
+		return types.Datum{}, err
+	}
+	return ListDatum(Assign(info, v)), nil
+}
+
+// DeletedDatums returns the values of the index entries to delete for the vector. Once the centroids are trained, the
+// entry may be in the untrained list or the list of the vector, depending on when the row was indexed.
+func DeletedDatums(info *model.VectorIndexInfo, vector types.Datum) ([]types.Datum, error) {
+	if vector.IsNull() {
+		return []types.Datum{vector}, nil
+	}
+	v, err := types.DecodeVectorFloat32(vector.GetBytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Centroids) == 0 || len(v) != info.Dimension {
+		return []types.Datum{ListDatum(UntrainedList)}, nil
+	}
+	list := nearest(info.Centroids, normalize(info.DistanceMetric, v)) + 1
+	return []types.Datum{ListDatum(UntrainedList), ListDatum(list)}, nil
+}
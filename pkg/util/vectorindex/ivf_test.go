@@ -0,0 +1,93 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectorindex
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumLists(t *testing.T) {
+	require.Equal(t, 1, NumLists(0, 3))
+	require.Equal(t, 1, NumLists(3, 3))
+	require.Equal(t, 10, NumLists(100, 3))
+	require.Equal(t, 256, NumLists(1000000, 3))
+	require.Equal(t, 32, NumLists(1000000, 1024))
+	require.Equal(t, 2, NumLists(1000000, 16383))
+}
+
+func TestTrainAndProbe(t *testing.T) {
+	// Two clusters around (0, 0) and (10, 10).
+	var samples []types.VectorFloat32
+	for i := 0; i < 50; i++ {
+		d := float32(i%5) * 0.1
+		samples = append(samples, types.VectorFloat32{d, -d}, types.VectorFloat32{10 + d, 10 - d})
+	}
+	info := &model.VectorIndexInfo{Dimension: 2, DistanceMetric: model.DistanceMetricL2}
+	info.Centroids = Train(info.DistanceMetric, info.Dimension, samples)
+	require.Len(t, info.Centroids, NumLists(len(samples), 2))
+	require.Equal(t, info.Centroids, Train(info.DistanceMetric, info.Dimension, samples))
+
+	near := Assign(info, types.VectorFloat32{0.1, 0.1})
+	far := Assign(info, types.VectorFloat32{9.9, 9.9})
+	require.NotEqual(t, near, far)
+	require.NotEqual(t, UntrainedList, near)
+	// The untrained list is always probed.
+	require.Equal(t, []int{UntrainedList, near}, Probe(info, types.VectorFloat32{0, 0}, 1))
+	require.Len(t, Probe(info, types.VectorFloat32{0, 0}, 3), 4)
+	require.Len(t, Probe(info, types.VectorFloat32{0, 0}, 1000), len(info.Centroids)+1)
+
+	d, err := IndexedDatum(info, types.NewBytesDatum(types.EncodeVectorFloat32(types.VectorFloat32{9.9, 9.9})))
+	require.NoError(t, err)
+	require.Equal(t, ListDatum(far), d)
+	d, err = IndexedDatum(info, types.Datum{})
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+
+	// The rows are still indexed in the untrained list while the centroids are pending, but the entries are deleted
+	// from both lists.
+	vector := types.NewBytesDatum(types.EncodeVectorFloat32(types.VectorFloat32{9.9, 9.9}))
+	info.PendingCentroids = true
+	d, err = IndexedDatum(info, vector)
+	require.NoError(t, err)
+	require.Equal(t, ListDatum(UntrainedList), d)
+	ds, err := DeletedDatums(info, vector)
+	require.NoError(t, err)
+	require.Equal(t, []types.Datum{ListDatum(UntrainedList), ListDatum(far)}, ds)
+	ds, err = DeletedDatums(&model.VectorIndexInfo{Dimension: 2, DistanceMetric: model.DistanceMetricL2}, vector)
+	require.NoError(t, err)
+	require.Equal(t, []types.Datum{ListDatum(UntrainedList)}, ds)
+}
+
+func TestTrainCosine(t *testing.T) {
+	// The vectors in the same direction are in the same list regardless of their lengths.
+	var samples []types.VectorFloat32
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, types.VectorFloat32{float32(i), 0}, types.VectorFloat32{0, float32(i)})
+	}
+	info := &model.VectorIndexInfo{Dimension: 2, DistanceMetric: model.DistanceMetricCosine}
+	info.Centroids = Train(info.DistanceMetric, info.Dimension, samples)
+	require.Equal(t, Assign(info, types.VectorFloat32{1, 0}), Assign(info, types.VectorFloat32{100, 1}))
+	require.NotEqual(t, Assign(info, types.VectorFloat32{1, 0}), Assign(info, types.VectorFloat32{0, 100}))
+
+	// Nothing is trained without samples, and all the vectors are in one list.
+	empty := &model.VectorIndexInfo{Dimension: 2, DistanceMetric: model.DistanceMetricCosine}
+	require.Nil(t, Train(empty.DistanceMetric, empty.Dimension, nil))
+	require.Equal(t, 0, Assign(empty, types.VectorFloat32{1, 2}))
+	require.Equal(t, []int{0}, Probe(empty, types.VectorFloat32{1, 2}, 8))
+}